@@ -0,0 +1,74 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/upbound/upjet/pkg/pipeline"
+	"github.com/upbound/upjet/pkg/scaffold"
+)
+
+func main() {
+	var (
+		app = kingpin.New(filepath.Base(os.Args[0]), "Upjet tools for the Crossplane providers generated from Terraform providers.").DefaultEnvars()
+
+		initCmd   = app.Command("init", "Bootstrap the repository of a new provider.")
+		rootDir   = initCmd.Arg("root-dir", "Root directory of the provider repository").Default(".").String()
+		name      = initCmd.Flag("name", `Provider name, which is also the prefix of the Terraform resources. For example, this is "github" for the GitHub Terraform provider.`).Short('n').Required().String()
+		source    = initCmd.Flag("source", `Terraform provider source in the Terraform registry, e.g. "integrations/github"`).Short('s').Required().String()
+		version   = initCmd.Flag("version", "Terraform provider version").Short('v').Required().String()
+		module    = initCmd.Flag("module", `Go module path of the provider. Defaults to "github.com/upbound/provider-<name>".`).Short('m').String()
+		repo      = initCmd.Flag("repo", `Terraform provider Git repository. Defaults to "https://github.com/<namespace>/terraform-provider-<type>".`).String()
+		docsPath  = initCmd.Flag("docs-path", "Path of the resource documentation in the Terraform provider repository").Default("website/docs/r").String()
+		overwrite = initCmd.Flag("overwrite", "Overwrite the existing files").Default("false").Bool()
+
+		breakingCmd = app.Command("breaking-changes", "Detect the breaking changes of the schemas in the OpenAPI document of a provider compared to the one of its previous release.")
+		previous    = breakingCmd.Flag("previous", "OpenAPI document of the previous release").Required().ExistingFile()
+		current     = breakingCmd.Flag("current", "Newly generated OpenAPI document").Required().ExistingFile()
+		warnOnly    = breakingCmd.Flag("warn-only", "Only report the breaking changes without failing").Default("false").Bool()
+
+		kustomizeCmd = app.Command("kustomize", "Generate the kustomize base and the development overlay installing a provider without the Crossplane package manager.")
+		crdDir       = kustomizeCmd.Flag("crd-dir", "Directory of the CRDs generated by controller-gen").Default("package/crds").ExistingDir()
+		outputDir    = kustomizeCmd.Flag("output-dir", "Directory of the kustomize base and overlay").Default("cluster/kustomize").String()
+		shortName    = kustomizeCmd.Flag("name", `Short name of the provider, e.g. "aws"`).Short('n').Required().String()
+		rootGroup    = kustomizeCmd.Flag("root-group", `Root API group of the provider, e.g. "aws.upbound.io"`).Required().String()
+		image        = kustomizeCmd.Flag("image", "Image of the provider controller").Required().String()
+		namespace    = kustomizeCmd.Flag("namespace", "Namespace of the provider controller").Default("upbound-system").String()
+	)
+	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	case initCmd.FullCommand():
+		paths, err := scaffold.Init(*rootDir, scaffold.Options{
+			Name:       *name,
+			ModulePath: *module,
+			Source:     *source,
+			Version:    *version,
+			Repo:       *repo,
+			DocsPath:   *docsPath,
+			Overwrite:  *overwrite,
+		})
+		kingpin.FatalIfError(err, "Failed to bootstrap the provider repository in %s", *rootDir)
+		for _, p := range paths {
+			fmt.Println(p)
+		}
+	case breakingCmd.FullCommand():
+		changes, err := pipeline.DetectBreakingChanges(*previous, *current)
+		kingpin.FatalIfError(err, "Failed to detect the breaking changes")
+		for _, c := range changes {
+			fmt.Println(c)
+		}
+		if len(changes) > 0 && !*warnOnly {
+			kingpin.Fatalf("Found %d breaking changes", len(changes))
+		}
+	case kustomizeCmd.FullCommand():
+		kg := pipeline.NewKustomizeGenerator(*outputDir, *shortName, *rootGroup, *image)
+		kg.Namespace = *namespace
+		kingpin.FatalIfError(kg.Generate(*crdDir), "Failed to generate the kustomize base in %s", *outputDir)
+	}
+}
@@ -5,7 +5,10 @@ Copyright 2021 Upbound Inc.
 package config
 
 import (
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
@@ -54,6 +57,69 @@ var (
 // ResourceOption allows setting optional fields of a Resource object.
 type ResourceOption func(*Resource)
 
+// ResourceDefaults are the defaults of a layer of the resource
+// configurations, i.e. provider-wide or per group. The unset fields keep the
+// defaults of the enclosing layer.
+type ResourceDefaults struct {
+	// UseAsync, if set, overrides the default Resource.UseAsync.
+	UseAsync *bool
+
+	// PollInterval, if set, overrides the default Resource.PollInterval.
+	PollInterval time.Duration
+
+	// SchemaDefaults, if set, overrides the default Resource.SchemaDefaults.
+	SchemaDefaults *bool
+
+	// MaxBlockNestingDepth, if set, overrides the default
+	// Resource.MaxBlockNestingDepth.
+	MaxBlockNestingDepth int
+
+	// OmittedFields are the top-level Terraform fields, e.g. "tags_all",
+	// removed from the schemas of the resources.
+	OmittedFields []string
+
+	// MapNormalizations are the normalizations of the map fields, e.g.
+	// "tags", set for the resources having those fields. They override the
+	// normalizations of the same fields set by the enclosing layer.
+	MapNormalizations map[string]MapNormalization
+
+	// Options are applied to the resources after the other defaults.
+	Options []ResourceOption
+}
+
+// apply applies the defaults to the given resource.
+func (d ResourceDefaults) apply(r *Resource) {
+	if d.UseAsync != nil {
+		r.UseAsync = *d.UseAsync
+	}
+	if d.PollInterval != 0 {
+		r.PollInterval = d.PollInterval
+	}
+	if d.SchemaDefaults != nil {
+		r.SchemaDefaults = *d.SchemaDefaults
+	}
+	if d.MaxBlockNestingDepth != 0 {
+		r.MaxBlockNestingDepth = d.MaxBlockNestingDepth
+	}
+	if r.TerraformResource != nil {
+		for _, f := range d.OmittedFields {
+			delete(r.TerraformResource.Schema, f)
+		}
+		for p, n := range d.MapNormalizations {
+			if sch := GetSchema(r.TerraformResource, reIndexSegment.ReplaceAllString(p, "")); sch == nil || sch.Type != schema.TypeMap {
+				continue
+			}
+			if r.MapNormalizations == nil {
+				r.MapNormalizations = make(map[string]MapNormalization, len(d.MapNormalizations))
+			}
+			r.MapNormalizations[p] = n
+		}
+	}
+	for _, o := range d.Options {
+		o(r)
+	}
+}
+
 // DefaultResource keeps an initial default configuration for all resources of a
 // provider.
 func DefaultResource(name string, terraformSchema *schema.Resource, terraformRegistry *registry.Resource, opts ...ResourceOption) *Resource {
@@ -133,6 +199,19 @@ func MarkAsRequired(sch *schema.Resource, fieldpaths ...string) {
 	}
 }
 
+// MarkAsSensitive marks the schema of the given fieldpath as sensitive. It's
+// useful for the fields that carry secret information but are not marked as
+// sensitive in the Terraform schema. Like the other sensitive fields, the
+// parameters are generated as secret key selectors and the observations are
+// published as connection details instead of being stored in the status.
+func MarkAsSensitive(sch *schema.Resource, fieldpaths ...string) {
+	for _, fieldpath := range fieldpaths {
+		if s := GetSchema(sch, fieldpath); s != nil {
+			s.Sensitive = true
+		}
+	}
+}
+
 // GetSchema returns the schema of the field whose fieldpath is given.
 // Returns nil if Schema is not found at the specified path.
 func GetSchema(sch *schema.Resource, fieldpath string) *schema.Schema {
@@ -174,3 +253,31 @@ func ManipulateEveryField(r *schema.Resource, op func(sch *schema.Schema)) {
 		}
 	}
 }
+
+// SchemaFingerprint returns a string identifying the shape of the given
+// block schema, i.e. the names, the types and the optional, required,
+// computed and sensitive properties of its fields, and their item limits,
+// recursively. The blocks with the same fingerprint generate identical
+// types.
+func SchemaFingerprint(r *schema.Resource) string {
+	keys := make([]string, 0, len(r.Schema))
+	for k := range r.Schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	sb.WriteString("{")
+	for _, k := range keys {
+		s := r.Schema[k]
+		sb.WriteString(fmt.Sprintf("%s:%s:%t:%t:%t:%t:%d:%d", k, s.Type, s.Optional, s.Required, s.Computed, s.Sensitive, s.MinItems, s.MaxItems))
+		switch e := s.Elem.(type) {
+		case *schema.Resource:
+			sb.WriteString(SchemaFingerprint(e))
+		case *schema.Schema:
+			sb.WriteString(":" + e.Type.String())
+		}
+		sb.WriteString(";")
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
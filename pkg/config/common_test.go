@@ -114,6 +114,7 @@ func TestDefaultResource(t *testing.T) {
 		cmpopts.IgnoreFields(Sensitive{}, "fieldPaths", "AdditionalConnectionDetailsFn"),
 		cmpopts.IgnoreFields(LateInitializer{}, "ignoredCanonicalFieldPaths"),
 		cmpopts.IgnoreFields(ExternalName{}, "SetIdentifierArgumentFn", "GetExternalNameFn", "GetIDFn"),
+		cmp.AllowUnexported(Resource{}),
 	}
 
 	for name, tc := range cases {
@@ -406,6 +407,84 @@ func TestMarkAsRequired(t *testing.T) {
 	}
 }
 
+func TestMarkAsSensitive(t *testing.T) {
+	type args struct {
+		sch    *schema.Resource
+		fields []string
+	}
+	type want struct {
+		sch *schema.Resource
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"DoesNotExist": {
+			args: args{
+				fields: []string{"topB"},
+				sch: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"topA": {Type: schema.TypeString},
+					},
+				},
+			},
+			want: want{
+				sch: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"topA": {Type: schema.TypeString},
+					},
+				},
+			},
+		},
+		"TopLevelAndNestedFields": {
+			args: args{
+				fields: []string{"topA", "topB.leafA"},
+				sch: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"topA": {Type: schema.TypeString, Optional: true},
+						"topB": {
+							Type: schema.TypeList,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"leafA": {Type: schema.TypeString, Computed: true},
+									"leafB": {Type: schema.TypeString, Computed: true},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				sch: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"topA": {Type: schema.TypeString, Optional: true, Sensitive: true},
+						"topB": {
+							Type: schema.TypeList,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"leafA": {Type: schema.TypeString, Computed: true, Sensitive: true},
+									"leafB": {Type: schema.TypeString, Computed: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			MarkAsSensitive(tc.args.sch, tc.args.fields...)
+			if diff := cmp.Diff(tc.want.sch, tc.args.sch); diff != "" {
+				t.Errorf("\n%s\nMarkAsSensitive(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestGetSchema(t *testing.T) {
 	type args struct {
 		sch       *schema.Resource
@@ -0,0 +1,170 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+// Package conversion contains the API version converters that can be
+// registered for the managed resources served in multiple API versions, and
+// the conversions between the Crossplane and Terraform representations of
+// the managed resources.
+package conversion
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// AllVersions denotes that a Conversion is applicable for all versions
+	// of an API with which the Conversion is registered. It can be used for
+	// both the conversion source or target API versions.
+	AllVersions = "*"
+)
+
+// Conversion is the interface for the API version converters. The
+// Conversions registered for a resource are applied in chain while
+// converting between its API versions, so the converters can be modular.
+type Conversion interface {
+	// Applicable returns true if this Conversion is applicable while
+	// converting the API version of the src object to the API version of
+	// the dst object.
+	Applicable(src, dst runtime.Object) bool
+}
+
+// PavedConversion is a Conversion between the paved representations of the
+// conversion source and target objects.
+type PavedConversion interface {
+	Conversion
+	// ConvertPaved converts from the specified src paved object to the
+	// target paved object. It returns true if the conversion has been
+	// performed.
+	ConvertPaved(src, target *fieldpath.Paved) (bool, error)
+}
+
+// ManagedConversion is a Conversion between the conversion source and
+// target managed resources.
+type ManagedConversion interface {
+	Conversion
+	// ConvertManaged converts from the specified src managed resource to the
+	// target managed resource. It returns true if the conversion has been
+	// performed.
+	ConvertManaged(src, target resource.Managed) (bool, error)
+}
+
+type baseConversion struct {
+	sourceVersion string
+	targetVersion string
+}
+
+func (c *baseConversion) Applicable(src, dst runtime.Object) bool {
+	return (c.sourceVersion == AllVersions || c.sourceVersion == src.GetObjectKind().GroupVersionKind().Version) &&
+		(c.targetVersion == AllVersions || c.targetVersion == dst.GetObjectKind().GroupVersionKind().Version)
+}
+
+type fieldCopy struct {
+	baseConversion
+	sourceField string
+	targetField string
+}
+
+func (f *fieldCopy) ConvertPaved(src, target *fieldpath.Paved) (bool, error) {
+	v, err := src.GetValue(f.sourceField)
+	if fieldpath.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get the field %q from the conversion source object", f.sourceField)
+	}
+	return true, errors.Wrapf(target.SetValue(f.targetField, v), "failed to set the field %q of the conversion target object", f.targetField)
+}
+
+// NewFieldRenameConversion returns a new Conversion that copies the value of
+// the sourceField in the sourceVersion to the targetField in the
+// targetVersion, e.g. for a field renamed between the versions. The fields
+// are specified as the field paths of the objects, e.g.
+// "spec.forProvider.instanceType".
+func NewFieldRenameConversion(sourceVersion, sourceField, targetVersion, targetField string) Conversion {
+	return &fieldCopy{
+		baseConversion: baseConversion{
+			sourceVersion: sourceVersion,
+			targetVersion: targetVersion,
+		},
+		sourceField: sourceField,
+		targetField: targetField,
+	}
+}
+
+type customConverter struct {
+	baseConversion
+	converter func(src, target resource.Managed) error
+}
+
+func (c *customConverter) ConvertManaged(src, target resource.Managed) (bool, error) {
+	if err := c.converter(src, target); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// NewCustomConverter returns a new Conversion that converts the whole
+// objects in the sourceVersion to the targetVersion using the specified
+// converter function.
+func NewCustomConverter(sourceVersion, targetVersion string, converter func(src, target resource.Managed) error) Conversion {
+	return &customConverter{
+		baseConversion: baseConversion{
+			sourceVersion: sourceVersion,
+			targetVersion: targetVersion,
+		},
+		converter: converter,
+	}
+}
+
+// ListConversionMode denotes the direction of a singleton list conversion
+// between API versions.
+type ListConversionMode int
+
+const (
+	// ToEmbeddedObject converts the singleton lists of the conversion source
+	// into the embedded objects of the conversion target.
+	ToEmbeddedObject ListConversionMode = iota
+	// ToSingletonList converts the embedded objects of the conversion source
+	// into the singleton lists of the conversion target.
+	ToSingletonList
+)
+
+type singletonListAPIConversion struct {
+	baseConversion
+	conversion *singletonListConversion
+	mode       ListConversionMode
+}
+
+func (s *singletonListAPIConversion) ConvertPaved(_, target *fieldpath.Paved) (bool, error) {
+	m := FromTerraform
+	if s.mode == ToSingletonList {
+		m = ToTerraform
+	}
+	params, err := s.conversion.Convert(target.UnstructuredContent(), m)
+	if err != nil {
+		return false, err
+	}
+	target.SetUnstructuredContent(params)
+	return true, nil
+}
+
+// NewSingletonListAPIConversion returns a new Conversion that converts the
+// singleton lists at the given field paths, e.g.
+// "spec.forProvider.rule[*].filter", in the sourceVersion into the embedded
+// objects in the targetVersion, or vice versa, depending on the given mode.
+// The paths are specified with the wildcards of all the enclosing lists,
+// including the embedded ones.
+func NewSingletonListAPIConversion(sourceVersion, targetVersion string, paths []string, mode ListConversionMode) Conversion {
+	return &singletonListAPIConversion{
+		baseConversion: baseConversion{
+			sourceVersion: sourceVersion,
+			targetVersion: targetVersion,
+		},
+		conversion: &singletonListConversion{paths: paths},
+		mode:       mode,
+	}
+}
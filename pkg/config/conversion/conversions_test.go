@@ -0,0 +1,100 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package conversion
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newObject(version string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(schema.GroupVersionKind{Group: "ec2.aws.upbound.io", Version: version, Kind: "VPC"})
+	return u
+}
+
+func TestApplicable(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		conversion Conversion
+		src, dst   string
+		want       bool
+	}{
+		"Versions": {
+			reason:     "A conversion should be applicable for its source and target versions.",
+			conversion: NewFieldRenameConversion("v1beta1", "spec.forProvider.a", "v1beta2", "spec.forProvider.b"),
+			src:        "v1beta1",
+			dst:        "v1beta2",
+			want:       true,
+		},
+		"ReverseVersions": {
+			reason:     "A conversion should not be applicable in the reverse direction.",
+			conversion: NewFieldRenameConversion("v1beta1", "spec.forProvider.a", "v1beta2", "spec.forProvider.b"),
+			src:        "v1beta2",
+			dst:        "v1beta1",
+		},
+		"AllVersions": {
+			reason:     "A conversion registered for all versions should be applicable for any versions.",
+			conversion: NewFieldRenameConversion(AllVersions, "spec.forProvider.a", AllVersions, "spec.forProvider.b"),
+			src:        "v1beta2",
+			dst:        "v1beta1",
+			want:       true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.conversion.Applicable(newObject(tc.src), newObject(tc.dst))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nApplicable(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSingletonListAPIConversion(t *testing.T) {
+	list := map[string]any{"spec": map[string]any{"forProvider": map[string]any{
+		"rule": []any{map[string]any{"filter": []any{map[string]any{"prefix": "a"}}}},
+	}}}
+	embedded := map[string]any{"spec": map[string]any{"forProvider": map[string]any{
+		"rule": map[string]any{"filter": map[string]any{"prefix": "a"}},
+	}}}
+	paths := []string{"spec.forProvider.rule", "spec.forProvider.rule[*].filter"}
+	cases := map[string]struct {
+		reason string
+		mode   ListConversionMode
+		src    map[string]any
+		want   map[string]any
+	}{
+		"ToEmbeddedObject": {
+			reason: "The singleton lists should be converted into embedded objects.",
+			mode:   ToEmbeddedObject,
+			src:    list,
+			want:   embedded,
+		},
+		"ToSingletonList": {
+			reason: "The embedded objects should be converted into singleton lists.",
+			mode:   ToSingletonList,
+			src:    embedded,
+			want:   list,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewSingletonListAPIConversion("v1beta1", "v1beta2", paths, tc.mode)
+			target := fieldpath.Pave(runtime.DeepCopyJSON(tc.src))
+			if _, err := c.(PavedConversion).ConvertPaved(fieldpath.Pave(tc.src), target); err != nil {
+				t.Fatalf("\n%s\nConvertPaved(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, target.UnstructuredContent()); diff != "" {
+				t.Errorf("\n%s\nConvertPaved(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package conversion
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/pkg/errors"
+)
+
+// Mode denotes the direction of a TerraformConversion.
+type Mode int
+
+const (
+	// ToTerraform converts the Crossplane representation of a managed
+	// resource's parameters or observation into the Terraform
+	// representation.
+	ToTerraform Mode = iota
+	// FromTerraform converts the Terraform representation of a managed
+	// resource's parameters or state into the Crossplane representation.
+	FromTerraform
+)
+
+// TerraformConversion is a bidirectional conversion between the Crossplane
+// and the Terraform representations of the parameters, observations and
+// states of a managed resource. Both representations are keyed with the
+// Terraform field names. The TerraformConversions registered for a resource
+// are applied in chain, in reverse order while converting from Terraform.
+type TerraformConversion interface {
+	// Convert converts the given representation in the given direction.
+	Convert(params map[string]any, mode Mode) (map[string]any, error)
+}
+
+// TerraformConversionFn is a function implementing TerraformConversion.
+type TerraformConversionFn func(params map[string]any, mode Mode) (map[string]any, error)
+
+// Convert calls the TerraformConversionFn.
+func (fn TerraformConversionFn) Convert(params map[string]any, mode Mode) (map[string]any, error) {
+	return fn(params, mode)
+}
+
+type singletonListConversion struct {
+	paths []string
+}
+
+// NewSingletonListConversion returns a TerraformConversion that represents
+// the lists at the given paths, which have at most one element, as objects
+// in the Crossplane representation. The paths are in the Terraform
+// representation, e.g. "rule" or "rule[*].filter".
+func NewSingletonListConversion(paths ...string) TerraformConversion {
+	return &singletonListConversion{paths: paths}
+}
+
+func (s *singletonListConversion) Convert(params map[string]any, mode Mode) (map[string]any, error) {
+	paths := append([]string(nil), s.paths...)
+	// the outer lists are converted first into Terraform and last from
+	// Terraform so that the wildcards of the inner paths keep matching.
+	sort.SliceStable(paths, func(i, j int) bool {
+		di, dj := strings.Count(paths[i], "."), strings.Count(paths[j], ".")
+		if mode == ToTerraform {
+			return di < dj
+		}
+		return di > dj
+	})
+	pv := fieldpath.Pave(params)
+	for _, p := range paths {
+		expanded, err := pv.ExpandWildcards(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot expand the path %q", p)
+		}
+		for _, e := range expanded {
+			if err := convertSingleton(pv, e, mode); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return pv.UnstructuredContent(), nil
+}
+
+func convertSingleton(pv *fieldpath.Paved, path string, mode Mode) error {
+	v, err := pv.GetValue(path)
+	if err != nil {
+		return errors.Wrapf(err, "cannot get the value at the path %q", path)
+	}
+	switch mode {
+	case ToTerraform:
+		if o, ok := v.(map[string]any); ok {
+			return errors.Wrapf(pv.SetValue(path, []any{o}), "cannot set the list at the path %q", path)
+		}
+	case FromTerraform:
+		l, ok := v.([]any)
+		if !ok {
+			return nil
+		}
+		if len(l) == 0 {
+			return errors.Wrapf(pv.DeleteField(path), "cannot delete the empty list at the path %q", path)
+		}
+		return errors.Wrapf(pv.SetValue(path, l[0]), "cannot set the object at the path %q", path)
+	}
+	return nil
+}
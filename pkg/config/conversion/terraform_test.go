@@ -0,0 +1,103 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package conversion
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSingletonListConversion(t *testing.T) {
+	type args struct {
+		paths  []string
+		params map[string]any
+		mode   Mode
+	}
+	type want struct {
+		params map[string]any
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"FromTerraform": {
+			reason: "The singleton lists should be converted into objects, inner ones included.",
+			args: args{
+				paths: []string{"rule", "rule[*].filter"},
+				params: map[string]any{
+					"rule": []any{map[string]any{
+						"filter": []any{map[string]any{"prefix": "a"}},
+					}},
+					"name": "test",
+				},
+				mode: FromTerraform,
+			},
+			want: want{
+				params: map[string]any{
+					"rule": map[string]any{
+						"filter": map[string]any{"prefix": "a"},
+					},
+					"name": "test",
+				},
+			},
+		},
+		"FromTerraformEmptyList": {
+			reason: "The empty singleton lists should be removed.",
+			args: args{
+				paths:  []string{"rule"},
+				params: map[string]any{"rule": []any{}},
+				mode:   FromTerraform,
+			},
+			want: want{
+				params: map[string]any{},
+			},
+		},
+		"ToTerraform": {
+			reason: "The objects should be converted into singleton lists, inner ones included.",
+			args: args{
+				paths: []string{"rule[*].filter", "rule"},
+				params: map[string]any{
+					"rule": map[string]any{
+						"filter": map[string]any{"prefix": "a"},
+					},
+				},
+				mode: ToTerraform,
+			},
+			want: want{
+				params: map[string]any{
+					"rule": []any{map[string]any{
+						"filter": []any{map[string]any{"prefix": "a"}},
+					}},
+				},
+			},
+		},
+		"MissingPath": {
+			reason: "The params should be left as is if the paths do not exist.",
+			args: args{
+				paths:  []string{"rule", "rule[*].filter"},
+				params: map[string]any{"name": "test"},
+				mode:   ToTerraform,
+			},
+			want: want{
+				params: map[string]any{"name": "test"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewSingletonListConversion(tc.args.paths...).Convert(tc.args.params, tc.args.mode)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nConvert(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.params, got); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want params, +got params:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -47,6 +47,7 @@ var (
 		GetExternalNameFn:       IDAsExternalName,
 		GetIDFn:                 ExternalNameAsID,
 		DisableNameInitializer:  true,
+		AdoptOnExternalName:     true,
 	}
 
 	parameterPattern = regexp.MustCompile(`{{\s*\.parameters\.([^\s}]+)\s*}}`)
@@ -85,6 +86,11 @@ func ParameterAsIdentifier(param string) ExternalName {
 //
 //	such as the AWS account ID for the AWS provider.
 //
+// context: The values resolved from the ProviderConfig or the environment by
+//
+//	the setup function of the provider, such as the project for the GCP
+//	provider. Same as setup.context.
+//
 // external_name: The value of external name annotation of the custom resource.
 //
 //	It is required to use this as part of the template.
@@ -97,6 +103,7 @@ func ParameterAsIdentifier(param string) ExternalName {
 // Example usages:
 // TemplatedStringAsIdentifier("index_name", "/subscriptions/{{ .setup.configuration.subscription }}/{{ .external_name }}")
 // TemplatedStringAsIdentifier("index_name", "/resource/{{ .external_name }}/static")
+// TemplatedStringAsIdentifier("name", "projects/{{ .context.project }}/topics/{{ .external_name }}")
 // TemplatedStringAsIdentifier("index_name", "{{ .parameters.cluster_id }}:{{ .parameters.node_id }}:{{ .external_name }}")
 // TemplatedStringAsIdentifier("", "arn:aws:network-firewall:{{ .setup.configuration.region }}:{{ .setup.client_metadata.account_id }}:{{ .parameters.type | ToLower }}-rulegroup/{{ .external_name }}")
 func TemplatedStringAsIdentifier(nameFieldPath, tmpl string) ExternalName {
@@ -141,6 +148,7 @@ func TemplatedStringAsIdentifier(nameFieldPath, tmpl string) ExternalName {
 				"external_name": externalName,
 				"parameters":    parameters,
 				"setup":         setup,
+				"context":       setup["context"],
 			}
 			b := bytes.Buffer{}
 			if err := t.Execute(&b, o); err != nil {
@@ -159,6 +167,57 @@ func TemplatedStringAsIdentifier(nameFieldPath, tmpl string) ExternalName {
 	}
 }
 
+// SetupContextValue returns the string value with the given key in the
+// context of the setup map supplied to the GetIDFn, i.e. a value resolved
+// from the ProviderConfig or the environment, and whether it is found.
+func SetupContextValue(setup map[string]any, key string) (string, bool) {
+	c, _ := setup["context"].(map[string]any)
+	v, ok := c[key].(string)
+	return v, ok
+}
+
+// ExternalNameTests configures the generation of the round-trip tests of the
+// external name configurations.
+type ExternalNameTests struct {
+	// ProviderFn is the expression in the provider's "config" package that
+	// returns the *Provider whose external name configurations are tested,
+	// e.g. "GetProvider()". Defaults to "GetProvider()".
+	ProviderFn string
+
+	// TerraformProviderConfig is the Terraform provider configuration
+	// passed to the GetIDFns in the tests, e.g. with the "region" or the
+	// setup "context" values used by the ID templates.
+	TerraformProviderConfig map[string]any
+}
+
+// ExternalNameRoundTrip returns an error if the given Terraform ID of a
+// resource, e.g. from its import statement, isn't constructed back by the
+// GetIDFn of the given external name configuration from the external name
+// its GetExternalNameFn extracts. The given parameters are passed to both
+// of the functions, in the Terraform state with the ID to the latter.
+func ExternalNameRoundTrip(ctx context.Context, e ExternalName, id string, parameters, terraformProviderConfig map[string]any) error {
+	if e.GetExternalNameFn == nil || e.GetIDFn == nil {
+		return errors.New("external name configuration does not have GetExternalNameFn or GetIDFn")
+	}
+	tfstate := make(map[string]any, len(parameters)+1)
+	for k, v := range parameters {
+		tfstate[k] = v
+	}
+	tfstate["id"] = id
+	en, err := e.GetExternalNameFn(tfstate)
+	if err != nil {
+		return errors.Wrapf(err, "cannot get external name from id %q", id)
+	}
+	got, err := e.GetIDFn(ctx, en, parameters, terraformProviderConfig)
+	if err != nil {
+		return errors.Wrapf(err, "cannot get id from external name %q", en)
+	}
+	if got != id {
+		return errors.Errorf("id %q is constructed from external name %q instead of %q", got, en, id)
+	}
+	return nil
+}
+
 // GetExternalNameFromTemplated takes a Terraform ID and the template it's produced
 // from and reverse it to get the external name. For example, you can supply
 // "/subscription/{{ .paramters.some }}/{{ .external_name }}" with
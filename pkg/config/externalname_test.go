@@ -223,6 +223,21 @@ func TestTemplatedGetIDFn(t *testing.T) {
 				id: "olala/paramval:myname/configval",
 			},
 		},
+		"Context": {
+			reason: "Should work when the context values resolved by the setup are used.",
+			args: args{
+				tmpl:         "projects/{{ .context.project }}/topics/{{ .external_name }}",
+				externalName: "mytopic",
+				setup: map[string]any{
+					"context": map[string]any{
+						"project": "myproject",
+					},
+				},
+			},
+			want: want{
+				id: "projects/myproject/topics/mytopic",
+			},
+		},
 		"TemplateFunctionToLower": {
 			reason: "Should work with a call of ToLower.",
 			args: args{
@@ -366,3 +381,109 @@ func TestTemplatedGetExternalNameFn(t *testing.T) {
 		})
 	}
 }
+
+func TestSetupContextValue(t *testing.T) {
+	type want struct {
+		value string
+		ok    bool
+	}
+	cases := map[string]struct {
+		reason string
+		setup  map[string]any
+		key    string
+		want   want
+	}{
+		"Found": {
+			reason: "The value in the setup context should be returned.",
+			setup: map[string]any{
+				"context": map[string]any{
+					"account_id": "123456789012",
+				},
+			},
+			key: "account_id",
+			want: want{
+				value: "123456789012",
+				ok:    true,
+			},
+		},
+		"NotFound": {
+			reason: "A missing value should not be found.",
+			setup: map[string]any{
+				"context": map[string]any{},
+			},
+			key: "account_id",
+		},
+		"NoContext": {
+			reason: "A value should not be found if there is no setup context.",
+			setup:  map[string]any{},
+			key:    "account_id",
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			v, ok := SetupContextValue(tc.setup, tc.key)
+			if diff := cmp.Diff(tc.want, want{value: v, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nSetupContextValue(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExternalNameRoundTrip(t *testing.T) {
+	type args struct {
+		e                       ExternalName
+		id                      string
+		parameters              map[string]any
+		terraformProviderConfig map[string]any
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want error
+	}{
+		"RoundTrip": {
+			reason: "An ID constructed back from its external name should be accepted.",
+			args: args{
+				e:  TemplatedStringAsIdentifier("name", "projects/{{ .setup.configuration.project }}/topics/{{ .external_name }}"),
+				id: "projects/myproject/topics/mytopic",
+				terraformProviderConfig: map[string]any{
+					"configuration": map[string]any{
+						"project": "myproject",
+					},
+				},
+			},
+		},
+		"RoundTripWithParameters": {
+			reason: "The parameters should be available to the GetIDFn.",
+			args: args{
+				e:          TemplatedStringAsIdentifier("", "{{ .parameters.cluster_id }}:{{ .external_name }}"),
+				id:         "cluster1:node1",
+				parameters: map[string]any{"cluster_id": "cluster1"},
+			},
+		},
+		"Mismatch": {
+			reason: "An ID that is constructed differently from its external name should be rejected.",
+			args: args{
+				e:  TemplatedStringAsIdentifier("", "{{ .parameters.cluster_id }}:{{ .external_name }}"),
+				id: "cluster1:node1",
+			},
+			want: errors.Errorf("id %q is constructed from external name %q instead of %q", "<no value>:node1", "node1", "cluster1:node1"),
+		},
+		"MissingFunctions": {
+			reason: "An external name configuration without the functions should be rejected.",
+			args: args{
+				e:  ExternalName{},
+				id: "id",
+			},
+			want: errors.New("external name configuration does not have GetExternalNameFn or GetIDFn"),
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			err := ExternalNameRoundTrip(context.TODO(), tc.args.e, tc.args.id, tc.args.parameters, tc.args.terraformProviderConfig)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExternalNameRoundTrip(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
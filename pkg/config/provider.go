@@ -6,13 +6,22 @@ package config
 
 import (
 	"fmt"
+	"io/fs"
 	"regexp"
+	"sort"
+	"strings"
 
 	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
+	apiversion "k8s.io/apimachinery/pkg/version"
 
 	"github.com/upbound/upjet/pkg/registry"
 	conversiontfjson "github.com/upbound/upjet/pkg/types/conversion/tfjson"
+	conversiontfprotov6 "github.com/upbound/upjet/pkg/types/conversion/tfprotov6"
+	tjname "github.com/upbound/upjet/pkg/types/name"
 )
 
 // ResourceConfiguratorFn is a function that implements the ResourceConfigurator
@@ -51,6 +60,19 @@ type BasePackages struct {
 	ControllerMap map[string]string
 }
 
+// SkipReason is the reason a Terraform resource is not generated.
+type SkipReason string
+
+const (
+	// SkipReasonNoSchema is the reason of the resources without schemas.
+	SkipReasonNoSchema SkipReason = "NoSchema"
+	// SkipReasonSkipList is the reason of the resources in the SkipList.
+	SkipReasonSkipList SkipReason = "SkipList"
+	// SkipReasonNotIncluded is the reason of the resources not in the
+	// IncludeList.
+	SkipReasonNotIncluded SkipReason = "NotIncluded"
+)
+
 // Provider holds configuration for a provider to be generated with Upjet.
 type Provider struct {
 	// TerraformResourcePrefix is the prefix used in all resources of this
@@ -70,6 +92,12 @@ type Provider struct {
 	// categories, see: https://kubernetes.io/docs/tasks/extend-kubernetes/custom-resources/custom-resource-definitions/#categories
 	ShortName string
 
+	// TerraformProviderSource is the source address of the Terraform provider
+	// in the Terraform registry, e.g. "hashicorp/aws". If set, the
+	// generated types are linked to the documentation of their Terraform
+	// resources in the registry. See Resource.DocsURL for details.
+	TerraformProviderSource string
+
 	// ModulePath is the go module path for the Crossplane provider repo, e.g.
 	// "github.com/upbound/provider-aws"
 	ModulePath string
@@ -87,6 +115,16 @@ type Provider struct {
 	// applied to all resources before any user-provided options are applied.
 	DefaultResourceOptions []ResourceOption
 
+	// ResourceDefaults are the provider-wide defaults of the resource
+	// configurations. They are applied after the DefaultResourceOptions.
+	ResourceDefaults ResourceDefaults
+
+	// GroupDefaults maps the short groups to the defaults of the
+	// configurations of the resources in those groups, which override the
+	// provider-wide ResourceDefaults. They are applied after the
+	// GroupKindRules, and the resource configurators override them.
+	GroupDefaults map[string]ResourceDefaults
+
 	// SkipList is a list of regex for the Terraform resources to be skipped.
 	// For example, to skip generation of "aws_shield_protection_group", one
 	// can add "aws_shield_protection_group$". To skip whole aws waf group, one
@@ -97,15 +135,215 @@ type Provider struct {
 	// provider subpackage main program. If this is set, the generated provider
 	// is broken up into subpackage families partitioned across the API groups.
 	// A monolithic provider is also generated to
-	// ensure backwards-compatibility.
+	// ensure backwards-compatibility. The template is executed with the
+	// Group, the ModulePath, the ShortName and the license Header of the
+	// provider.
 	MainTemplate string
 
+	// FamilyBuildTag is the build tag, e.g. "family", that the generated
+	// controller setup files of the API groups are constrained with, so
+	// that the family providers can be built from the monolithic codebase.
+	// If set, the setup function of each API group is generated in its own
+	// file, which registers it to the monolithic Setup function and is only
+	// included in the builds without the tag or with the tag suffixed with
+	// its group, e.g. "-tags family,family_s3" for a provider-aws-s3 with
+	// the controllers of the s3 and the config groups.
+	FamilyBuildTag string
+
+	// GenerateSubProviderMains enables the generation of the main programs
+	// of the sub-providers, i.e. "cmd/provider/<group>/zz_main.go", with the
+	// built-in template if the MainTemplate is not set. The main program of
+	// a sub-provider only sets up the controllers of its API group and the
+	// ProviderConfig controllers, and it expects the layout of the provider
+	// template, i.e. the "GetProvider" function in the "config" package and
+	// the "TerraformSetupBuilder" function in the "internal/clients" package.
+	GenerateSubProviderMains bool
+
+	// TemplateOverrides are the templates shadowing the built-in templates
+	// of the generated files with the same names in the
+	// "pkg/pipeline/templates" package, e.g. "crd_types.go.tmpl" of the CRD
+	// types or "controller.go.tmpl" of the controllers, in an embed.FS or an
+	// os.DirFS of a directory. The overriding templates receive the same
+	// values as the built-in ones they're best derived from.
+	TemplateOverrides fs.FS
+
+	// ExampleSyncWaveAnnotationKey is the annotation key used to annotate
+	// the generated example manifests with their sync-waves, which are
+	// derived from the cross-resource reference graph, e.g.
+	// "argocd.argoproj.io/sync-wave". If not set, the example manifests are
+	// not annotated.
+	ExampleSyncWaveAnnotationKey string
+
+	// InferReferences enables the inference of cross-resource references
+	// from the field names like "vpc_id" and the scraped documentation. The
+	// inferred references are not configured but written to the
+	// "config/zz_inferred_references.go" file for the maintainers to review.
+	InferReferences bool
+
+	// PinTypeNames enables the persisted mapping of the Terraform paths of
+	// the blocks to the names of the Go types generated for them, which is
+	// read from and written to the "type_names.json" file of each API
+	// version package. The mapped names are kept as the resources change so
+	// that the resolution of the type name collisions doesn't rename the
+	// existing types, and the names that change nevertheless are reported.
+	PinTypeNames bool
+
+	// GenerateAPIDocs enables the generation of the Markdown API reference
+	// of the managed resources under "docs/api", with the fields of their
+	// specs and statuses, the links to their example manifests and how to
+	// import the existing resources.
+	GenerateAPIDocs bool
+
+	// APIDocsFrontmatter enables the YAML frontmatter of the generated API
+	// reference pages, which is read by the static site generators of the
+	// docs sites, e.g. Hugo or Docusaurus. The frontmatter of a resource page
+	// has its title, i.e. the Kind, its API group and version, its
+	// description scraped from the Terraform registry and the path of its
+	// example manifest relative to the page, if any.
+	APIDocsFrontmatter bool
+
+	// ExternalNameTests configures the generation of the round-trip tests of
+	// the external name configurations in the "config" package. If set, the
+	// resources whose external name configurations have GetIDFns or
+	// GetExternalNameFns other than the defaults are tested with the IDs in
+	// their scraped import statements, so that the misconfigured external
+	// names are caught before they are released.
+	ExternalNameTests *ExternalNameTests
+
+	// ProviderConfigAuth enables the generation of the ProviderConfig and
+	// ProviderConfigUsage types in the "apis/v1beta1" package, the
+	// ProviderConfig controller in the "internal/controller/providerconfig"
+	// package and the ConfigureCredentials function in the
+	// "internal/clients" package, which extracts the credentials of the
+	// ProviderConfig of a managed resource into the Terraform provider
+	// configuration in its terraform.SetupFn, from the supported credential
+	// sources. See ProviderConfigAuth for details.
+	ProviderConfigAuth *ProviderConfigAuth
+
+	// GenerateTypedClients enables the generation of the typed clients,
+	// listers and informers of the managed resources in the
+	// "pkg/client/<group>/<version>" packages, which are built on the
+	// controller-runtime clients and caches, for the operators built on top
+	// of the provider.
+	GenerateTypedClients bool
+
+	// GenerateApplyConfigurations enables the generation of the typed
+	// apply configurations of the managed resources in the
+	// "pkg/applyconfiguration/<group>/<version>" packages, which are the
+	// builders of the server-side apply patches that only contain the
+	// fields set, like the ones generated for the Kubernetes APIs.
+	GenerateApplyConfigurations bool
+
+	// GenerateTerraformedTests enables the generation of the golden tests of
+	// the Terraform conversions of the managed resources with examples in
+	// their API version packages. The tests decode the generated example
+	// manifests, convert them to the Terraform parameters and observations
+	// and back, and compare them with the golden files in the "testdata"
+	// directories of the packages, which are written by running the tests
+	// with the "-update" flag.
+	GenerateTerraformedTests bool
+
+	// LicenseHeader configures the license header of the generated Go files,
+	// which is the "hack/boilerplate.go.txt" file of the provider if not set.
+	// The header is rendered into the "hack/zz_boilerplate.go.txt" file,
+	// which can also be passed to the other code generators of the provider,
+	// e.g. controller-gen, to have the same header in all the generated
+	// files.
+	LicenseHeader *LicenseHeader
+
+	// ControllerTests configures the generation of the envtest-based
+	// integration tests of the controllers of the managed resources. If set,
+	// a test is generated in the controller package of each resource with
+	// an example, which creates the resource of its example manifest in a
+	// local API server with the generated CRDs, reconciles it against a fake
+	// Terraform CLI until it's ready, and deletes it.
+	ControllerTests *ControllerTests
+
+	// SharedBlockSchemas maps the names of the blocks shared by the
+	// resources, e.g. "Endpoint", to their schemas. The types of the shared
+	// blocks, e.g. "EndpointParameters", "EndpointInitParameters" and
+	// "EndpointObservation", are generated once in the SharedTypesPackage
+	// and referenced by the resources configured to use them with
+	// Resource.SharedBlocks, instead of being generated for each resource.
+	// The shared blocks cannot have sensitive fields.
+	SharedBlockSchemas map[string]*schema.Resource
+
+	// SharedTypesPackage is the path of the package, relative to the root
+	// of the provider repo, e.g. "apis/common/v1", that the types of the
+	// SharedBlockSchemas are generated in. If set, the identical blocks of
+	// the resources, which can be shared, are reported during the
+	// generation.
+	SharedTypesPackage string
+
+	// MaxTypeNameLength is the maximum length of the names of the Go types
+	// generated for the blocks, including their "InitParameters" suffixes.
+	// The longer names, e.g. of the deeply nested blocks, are truncated and
+	// suffixed with a hash of the original names to keep them unique and
+	// deterministic. The truncated names are reported during the generation
+	// and they can be persisted with PinTypeNames. Zero disables the
+	// truncation.
+	MaxTypeNameLength int
+
+	// StrictIgnoredFields fails the code generation if any of the
+	// LateInitializer.IgnoredFields entries of the resources doesn't match
+	// a field in their schemas, instead of reporting them.
+	StrictIgnoredFields bool
+
+	// ExternalProviders are the other provider modules whose managed
+	// resources can be referenced by the resources of this Provider.
+	ExternalProviders []ExternalProvider
+
+	// GroupKindRules is an ordered list of rules overriding the ShortGroup
+	// and the Kind of the resources whose Terraform names match them. Only
+	// the first matching rule is applied to a resource. The rules are
+	// applied before the resource configurators, so that the overrides made
+	// by the configurators take precedence.
+	GroupKindRules []GroupKindRule
+
+	// ResourceAliases are the additional Kinds the Terraform resources are
+	// registered under. See ResourceAlias for details.
+	ResourceAliases []ResourceAlias
+
+	// FrameworkResourceSchemas are the schemas of the resources served via
+	// the Terraform plugin protocol version 6, e.g. by a provider built with
+	// the Terraform plugin framework, keyed by the Terraform resource names.
+	// They take precedence over the schemas of the same resources in the
+	// provider schema passed to NewProvider, so that the framework-only
+	// resources can be generated from the schemas reported by the provider
+	// server.
+	FrameworkResourceSchemas map[string]*tfprotov6.Schema
+
+	// MovedResources are the previous Kinds of the resources whose Kinds or
+	// groups have been renamed. See MovedResource for details.
+	MovedResources []MovedResource
+
+	// VersionPromotions are the promotions of the resources to new API
+	// versions, which are applied in order. See VersionPromotion for
+	// details.
+	VersionPromotions []VersionPromotion
+
+	// TerraformExecutionMode is how the controllers run the Terraform
+	// operations on the external resources. Defaults to
+	// TerraformExecutionModeCLI.
+	TerraformExecutionMode TerraformExecutionMode
+
+	// TerraformPluginServerFn returns a new gRPC plugin server of the
+	// Terraform provider linked into the provider binary, e.g.
+	// schema.NewGRPCProviderServer(aws.Provider()) for a provider built with
+	// the Terraform plugin SDK. It's required by
+	// TerraformExecutionModeInProcess.
+	TerraformPluginServerFn func() tfprotov5.ProviderServer
+
 	// skippedResourceNames is a list of Terraform resource names
 	// available in the Terraform provider schema, but
 	// not in the include list or in the skip list, meaning that
 	// the corresponding managed resources are not generated.
 	skippedResourceNames []string
 
+	// skipReasons are the reasons of the skipped Terraform resources keyed
+	// by their names.
+	skipReasons map[string]SkipReason
+
 	// IncludeList is a list of regex for the Terraform resources to be
 	// included. For example, to include "aws_shield_protection_group" into
 	// the generated resources, one can add "aws_shield_protection_group$".
@@ -113,6 +351,12 @@ type Provider struct {
 	// Defaults to []string{".+"} which would include all resources.
 	IncludeList []string
 
+	// DataSourceIncludeList is a list of regex for the Terraform data sources
+	// to be generated as read-only managed resources, e.g. "aws_ami$". See
+	// Resource.DataSource for details. No data sources are generated by
+	// default.
+	DataSourceIncludeList []string
+
 	// Resources is a map holding resource configurations where key is Terraform
 	// resource name.
 	Resources map[string]*Resource
@@ -126,6 +370,240 @@ type Provider struct {
 	resourceConfigurators map[string]ResourceConfiguratorChain
 }
 
+// TerraformExecutionMode is the way the Terraform operations are run.
+type TerraformExecutionMode string
+
+const (
+	// TerraformExecutionModeCLI runs the Terraform operations by forking
+	// the Terraform CLI in the workspaces of the managed resources.
+	TerraformExecutionModeCLI TerraformExecutionMode = "CLI"
+
+	// TerraformExecutionModeInProcess runs the Terraform operations in the
+	// process of the controllers by calling the plugin server returned by
+	// the TerraformPluginServerFn of the Provider directly, without the
+	// Terraform CLI and the provider plugin processes. The workspaces keep
+	// their main.tf.json and terraform.tfstate files, so the rest of the
+	// controllers work the same in both modes. Only the Terraform plugin
+	// protocol version 5 is supported, so the operations on the
+	// FrameworkResourceSchemas resources fail in this mode.
+	TerraformExecutionModeInProcess TerraformExecutionMode = "InProcess"
+)
+
+// ExternalProvider is another provider module, e.g. a provider family
+// sibling, whose managed resources can be referenced by the resources of a
+// Provider.
+type ExternalProvider struct {
+	// ModulePath is the Go module path of the external provider, e.g.
+	// "github.com/upbound/provider-aws".
+	ModulePath string
+
+	// Version is the version of the external provider module required, e.g.
+	// "v0.40.0". If set, the requirement is added to the go.mod file of the
+	// generated provider during code generation.
+	Version string
+
+	// Types maps the names of the Terraform resources of the external
+	// provider to the types of the corresponding CRDs in the form of
+	// <package-path-relative-to-module>.<type-name>, e.g.
+	// "aws_vpc": "apis/ec2/v1beta1.VPC". References with these Terraform
+	// names resolve to the types of the external provider.
+	Types map[string]string
+}
+
+// TypePath returns the type of the CRD of the given Terraform resource in the
+// form of <package-path>.<type-name>, and whether the resource belongs to this
+// ExternalProvider.
+func (ep ExternalProvider) TypePath(tfName string) (string, bool) {
+	t, ok := ep.Types[tfName]
+	if !ok {
+		return "", false
+	}
+	return ep.ModulePath + "/" + t, true
+}
+
+// GroupKindRule overrides the ShortGroup and the Kind of the resources whose
+// Terraform names match its Pattern. The ShortGroup and Kind templates are
+// expanded with the submatches of the Pattern as in regexp.Regexp.Expand,
+// e.g. for the Pattern `^aws_ec2_(.+)$`, the Kind template "$1" yields
+// "TransitGateway" for "aws_ec2_transit_gateway". The expanded Kind is
+// converted into camel case if it is in snake case, so the literal parts of
+// a Kind template should also be in snake case, e.g. "default_$1".
+type GroupKindRule struct {
+	// Pattern is the regular expression matched against the Terraform
+	// resource names.
+	Pattern string
+
+	// ShortGroup is the template of the short group. The default short group
+	// is kept if empty.
+	ShortGroup string
+
+	// Kind is the template of the kind. The default kind is kept if empty.
+	Kind string
+}
+
+// apply overrides the ShortGroup and the Kind of the given resource if its
+// name matches the compiled Pattern, and reports whether it matches.
+func (gkr GroupKindRule) apply(re *regexp.Regexp, r *Resource) bool {
+	m := re.FindStringSubmatchIndex(r.Name)
+	if m == nil {
+		return false
+	}
+	if gkr.ShortGroup != "" {
+		r.ShortGroup = string(re.ExpandString(nil, gkr.ShortGroup, r.Name, m))
+	}
+	if gkr.Kind != "" {
+		r.Kind = tjname.NewFromSnake(string(re.ExpandString(nil, gkr.Kind, r.Name, m))).Camel
+	}
+	return true
+}
+
+// ResourceAlias registers a Terraform resource under an additional Kind,
+// e.g. "aws_s3_bucket" as "LoggingBucket" in addition to "Bucket", so that
+// purpose-built APIs can be exposed with their own CRDs, controllers and
+// examples. The configuration of an alias is keyed with AliasKey in the
+// Resources of the Provider and it is configured first with the
+// configurators of its Terraform resource and then with the ones added for
+// its key, which can bake in parameter defaults via ParameterDefaults or
+// remove fields from the top-level schema of the alias, which is a copy of
+// the Terraform resource's.
+type ResourceAlias struct {
+	// Name is the name of the Terraform resource, e.g. "aws_s3_bucket".
+	Name string
+	// Kind is the Kind of the alias, e.g. "LoggingBucket". It must be unique
+	// in the API group of the alias.
+	Kind string
+}
+
+// AliasKey returns the key of the configuration of the alias of the given
+// Terraform resource with the given Kind in the Resources of a Provider.
+func AliasKey(name, kind string) string {
+	return name + "/" + kind
+}
+
+// DataSourceKey returns the key of the configuration of the given Terraform
+// data source in the Resources of a Provider.
+func DataSourceKey(name string) string {
+	return "data." + name
+}
+
+// MovedResource is a previous Kind of a resource, e.g. "Bucket" in the
+// "s3" group before it's renamed to "BucketV2" or moved to another group.
+// The CRD of the previous Kind keeps being generated, so that the existing
+// managed resources can still be read, but instead of being reconciled,
+// they're moved to the current Kind: a managed resource of the current Kind
+// is created with the same name, labels, annotations, including the
+// external name, and spec, and the managed resource of the previous Kind is
+// deleted with its external resource orphaned. Thus, the external resources
+// are adopted by the current Kind without being re-imported.
+type MovedResource struct {
+	// Name is the key of the configuration of the current Kind in the
+	// Resources of the Provider, i.e. the Terraform resource name or the
+	// AliasKey of an alias.
+	Name string
+	// ShortGroup is the previous short group of the resource. It defaults
+	// to the current short group.
+	ShortGroup string
+	// Version is the previous API version of the resource. It defaults to
+	// the current version.
+	Version string
+	// Kind is the previous Kind of the resource. It defaults to the current
+	// Kind.
+	Kind string
+}
+
+// VersionPromotion promotes the resources from an API version to a new one,
+// e.g. from "v1beta1" to "v1beta2", which automates the mechanical part of
+// the API promotion of many Kinds: the new version becomes the storage
+// version of the promoted resources, which are still served in the previous
+// version, and the conversion webhooks converting the managed resources
+// between the versions are generated.
+type VersionPromotion struct {
+	// From is the API version the resources are promoted from.
+	From string
+	// To is the new API version the resources are promoted to, which must
+	// be newer than From.
+	To string
+	// Resources are the regular expressions of the names of the Terraform
+	// resources promoted, as in IncludeList. All the resources in the From
+	// version are promoted if empty.
+	Resources []string
+	// EmbedSingletonLists embeds the singleton lists as objects in the new
+	// version, unless the singleton list embedding is already configured
+	// for a resource. See SingletonListEmbedding for details.
+	EmbedSingletonLists bool
+	// Configure configures the promoted resources after their promotion,
+	// e.g. to add the Conversions of the fields whose semantics change in
+	// the new version.
+	Configure func(r *Resource)
+}
+
+// apply promotes the given resource if it's selected by the promotion and
+// returns true if it's promoted.
+func (vp VersionPromotion) apply(r *Resource) (bool, error) {
+	if r.Version != vp.From || (len(vp.Resources) > 0 && !matches(r.Name, vp.Resources)) {
+		return false, nil
+	}
+	if apiversion.CompareKubeAwareVersionStrings(vp.To, vp.From) <= 0 {
+		return false, errors.Errorf("the version %q to promote to must be newer than the version %q", vp.To, vp.From)
+	}
+	served := make([]string, 0, len(r.ServedVersions)+1)
+	served = append(served, vp.From)
+	for _, v := range r.ServedVersions {
+		if v != vp.From && v != vp.To {
+			served = append(served, v)
+		}
+	}
+	r.Version = vp.To
+	r.ServedVersions = served
+	if vp.EmbedSingletonLists && !r.SingletonListEmbedding.Enabled {
+		r.SingletonListEmbedding = SingletonListEmbedding{Enabled: true, Since: vp.To}
+	}
+	if vp.Configure != nil {
+		vp.Configure(r)
+	}
+	return true, nil
+}
+
+// MovedKey returns the key of the configuration of the given previous Kind
+// of the given Terraform resource in the Resources of a Provider.
+func MovedKey(name, shortGroup, kind string) string {
+	return name + "/" + shortGroup + "/" + kind
+}
+
+// LicenseHeader is the template of the license header of the generated Go
+// files.
+type LicenseHeader struct {
+	// Template is the text/template of the header, which must only consist of
+	// Go comments, e.g. the "// SPDX-License-Identifier: Apache-2.0" and
+	// "// Copyright {{ .Year }} Example Corp." lines. It's executed with the
+	// Year of the generation, the ShortName and the ModulePath of the
+	// provider, and the Timestamp of the generation if it's enabled.
+	Template string
+
+	// Timestamp enables the Timestamp of the generation in the RFC 3339
+	// format in the template. It's empty if not enabled, so that the headers
+	// don't change in every generation.
+	Timestamp bool
+}
+
+// ControllerTests configures the generation of the integration tests of the
+// controllers of the managed resources.
+type ControllerTests struct {
+	// ProviderFn is the expression in the provider's "config" package that
+	// returns the *Provider whose controllers are tested, e.g.
+	// "GetProvider()". Defaults to "GetProvider()".
+	ProviderFn string
+
+	// CRDsPath is the path of the directory of the generated CRDs relative
+	// to the root directory of the provider. Defaults to "package/crds".
+	CRDsPath string
+
+	// BuildTag is the build tag of the tests, which are only built and run
+	// with the tag as they require the binaries of the Kubernetes control
+	// plane. Defaults to "envtest".
+	BuildTag string
+}
+
 // ReferenceInjector injects cross-resource references across the resources
 // of this Provider.
 type ReferenceInjector interface {
@@ -163,6 +641,21 @@ func WithSkipList(l []string) ProviderOption {
 	}
 }
 
+// WithTerraformProviderSource configures TerraformProviderSource for this
+// Provider.
+func WithTerraformProviderSource(s string) ProviderOption {
+	return func(p *Provider) {
+		p.TerraformProviderSource = s
+	}
+}
+
+// WithDataSourceIncludeList configures DataSourceIncludeList for this Provider.
+func WithDataSourceIncludeList(l []string) ProviderOption {
+	return func(p *Provider) {
+		p.DataSourceIncludeList = l
+	}
+}
+
 // WithBasePackages configures BasePackages for this Provider.
 func WithBasePackages(b BasePackages) ProviderOption {
 	return func(p *Provider) {
@@ -178,6 +671,25 @@ func WithDefaultResourceOptions(opts ...ResourceOption) ProviderOption {
 	}
 }
 
+// WithResourceDefaults configures the provider-wide ResourceDefaults for this
+// Provider.
+func WithResourceDefaults(d ResourceDefaults) ProviderOption {
+	return func(p *Provider) {
+		p.ResourceDefaults = d
+	}
+}
+
+// WithGroupDefaults configures the ResourceDefaults of the resources in the
+// given short group for this Provider.
+func WithGroupDefaults(shortGroup string, d ResourceDefaults) ProviderOption {
+	return func(p *Provider) {
+		if p.GroupDefaults == nil {
+			p.GroupDefaults = map[string]ResourceDefaults{}
+		}
+		p.GroupDefaults[shortGroup] = d
+	}
+}
+
 // WithReferenceInjectors configures an ordered list of `ReferenceInjector`s
 // for this Provider. The configured reference resolvers are executed in order
 // to inject cross-resource references across this Provider's resources.
@@ -200,6 +712,191 @@ func WithMainTemplate(template string) ProviderOption {
 	}
 }
 
+// WithFamilyBuildTag configures FamilyBuildTag for this Provider.
+func WithFamilyBuildTag(tag string) ProviderOption {
+	return func(p *Provider) {
+		p.FamilyBuildTag = tag
+	}
+}
+
+// WithSubProviderMains enables GenerateSubProviderMains for this Provider.
+func WithSubProviderMains() ProviderOption {
+	return func(p *Provider) {
+		p.GenerateSubProviderMains = true
+	}
+}
+
+// WithTemplateOverrides configures TemplateOverrides for this Provider.
+func WithTemplateOverrides(fsys fs.FS) ProviderOption {
+	return func(p *Provider) {
+		p.TemplateOverrides = fsys
+	}
+}
+
+// WithExampleSyncWaveAnnotationKey configures ExampleSyncWaveAnnotationKey
+// for this Provider.
+func WithExampleSyncWaveAnnotationKey(key string) ProviderOption {
+	return func(p *Provider) {
+		p.ExampleSyncWaveAnnotationKey = key
+	}
+}
+
+// WithReferenceInference enables InferReferences for this Provider.
+func WithReferenceInference() ProviderOption {
+	return func(p *Provider) {
+		p.InferReferences = true
+	}
+}
+
+// WithMaxTypeNameLength configures MaxTypeNameLength for this Provider.
+func WithMaxTypeNameLength(l int) ProviderOption {
+	return func(p *Provider) {
+		p.MaxTypeNameLength = l
+	}
+}
+
+// WithSharedBlocks configures the SharedBlockSchemas and the
+// SharedTypesPackage for this Provider.
+func WithSharedBlocks(pkg string, blocks map[string]*schema.Resource) ProviderOption {
+	return func(p *Provider) {
+		p.SharedTypesPackage = pkg
+		p.SharedBlockSchemas = blocks
+	}
+}
+
+// WithAPIDocs enables GenerateAPIDocs for this Provider.
+func WithAPIDocs() ProviderOption {
+	return func(p *Provider) {
+		p.GenerateAPIDocs = true
+	}
+}
+
+// WithAPIDocsFrontmatter enables GenerateAPIDocs and APIDocsFrontmatter for
+// this Provider.
+func WithAPIDocsFrontmatter() ProviderOption {
+	return func(p *Provider) {
+		p.GenerateAPIDocs = true
+		p.APIDocsFrontmatter = true
+	}
+}
+
+// WithExternalNameTests configures ExternalNameTests for this Provider.
+func WithExternalNameTests(t ExternalNameTests) ProviderOption {
+	return func(p *Provider) {
+		p.ExternalNameTests = &t
+	}
+}
+
+// WithProviderConfigAuth configures ProviderConfigAuth for this Provider.
+func WithProviderConfigAuth(a ProviderConfigAuth) ProviderOption {
+	return func(p *Provider) {
+		p.ProviderConfigAuth = &a
+	}
+}
+
+// WithTypedClients enables GenerateTypedClients for this Provider.
+func WithTypedClients() ProviderOption {
+	return func(p *Provider) {
+		p.GenerateTypedClients = true
+	}
+}
+
+// WithApplyConfigurations enables GenerateApplyConfigurations for this
+// Provider.
+func WithApplyConfigurations() ProviderOption {
+	return func(p *Provider) {
+		p.GenerateApplyConfigurations = true
+	}
+}
+
+// WithTerraformedTests enables GenerateTerraformedTests for this Provider.
+func WithTerraformedTests() ProviderOption {
+	return func(p *Provider) {
+		p.GenerateTerraformedTests = true
+	}
+}
+
+// WithLicenseHeader configures LicenseHeader for this Provider.
+func WithLicenseHeader(h LicenseHeader) ProviderOption {
+	return func(p *Provider) {
+		p.LicenseHeader = &h
+	}
+}
+
+// WithControllerTests configures ControllerTests for this Provider.
+func WithControllerTests(t ControllerTests) ProviderOption {
+	return func(p *Provider) {
+		p.ControllerTests = &t
+	}
+}
+
+// WithStrictIgnoredFields enables StrictIgnoredFields for this Provider.
+func WithStrictIgnoredFields() ProviderOption {
+	return func(p *Provider) {
+		p.StrictIgnoredFields = true
+	}
+}
+
+// WithTypeNamePinning enables PinTypeNames for this Provider.
+func WithTypeNamePinning() ProviderOption {
+	return func(p *Provider) {
+		p.PinTypeNames = true
+	}
+}
+
+// WithExternalProviders configures ExternalProviders for this Provider.
+func WithExternalProviders(eps ...ExternalProvider) ProviderOption {
+	return func(p *Provider) {
+		p.ExternalProviders = eps
+	}
+}
+
+// WithGroupKindRules configures GroupKindRules for this Provider.
+func WithGroupKindRules(rules ...GroupKindRule) ProviderOption {
+	return func(p *Provider) {
+		p.GroupKindRules = rules
+	}
+}
+
+// WithResourceAliases configures ResourceAliases for this Provider.
+func WithResourceAliases(aliases ...ResourceAlias) ProviderOption {
+	return func(p *Provider) {
+		p.ResourceAliases = aliases
+	}
+}
+
+// WithFrameworkResourceSchemas configures FrameworkResourceSchemas for this
+// Provider.
+func WithFrameworkResourceSchemas(schemas map[string]*tfprotov6.Schema) ProviderOption {
+	return func(p *Provider) {
+		p.FrameworkResourceSchemas = schemas
+	}
+}
+
+// WithVersionPromotions configures VersionPromotions for this Provider.
+func WithVersionPromotions(promotions ...VersionPromotion) ProviderOption {
+	return func(p *Provider) {
+		p.VersionPromotions = promotions
+	}
+}
+
+// WithMovedResources configures MovedResources for this Provider.
+func WithMovedResources(moved ...MovedResource) ProviderOption {
+	return func(p *Provider) {
+		p.MovedResources = moved
+	}
+}
+
+// WithInProcessExecution configures the Provider to run the Terraform
+// operations in process with the plugin servers returned by the given
+// function. See TerraformExecutionModeInProcess for details.
+func WithInProcessExecution(fn func() tfprotov5.ProviderServer) ProviderOption {
+	return func(p *Provider) {
+		p.TerraformExecutionMode = TerraformExecutionModeInProcess
+		p.TerraformPluginServerFn = fn
+	}
+}
+
 // NewProvider builds and returns a new Provider from provider
 // tfjson schema, that is generated using Terraform CLI with:
 // `terraform providers schema --json`
@@ -211,9 +908,10 @@ func NewProvider(schema []byte, prefix string, modulePath string, metadata []byt
 	if len(ps.Schemas) != 1 {
 		panic(fmt.Sprintf("there should exactly be 1 provider schema but there are %d", len(ps.Schemas)))
 	}
-	var rs map[string]*tfjson.Schema
+	var rs, ds map[string]*tfjson.Schema
 	for _, v := range ps.Schemas {
 		rs = v.ResourceSchemas
+		ds = v.DataSourceSchemas
 		break
 	}
 
@@ -241,17 +939,69 @@ func NewProvider(schema []byte, prefix string, modulePath string, metadata []byt
 		o(p)
 	}
 
+	frameworkResources, err := conversiontfprotov6.GetV2ResourceMap(p.FrameworkResourceSchemas)
+	if err != nil {
+		panic(errors.Wrap(err, "cannot convert the framework resource schemas"))
+	}
+	for name, r := range frameworkResources {
+		resourceMap[name] = r
+	}
+
+	gkPatterns := make([]*regexp.Regexp, len(p.GroupKindRules))
+	for i, rule := range p.GroupKindRules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			panic(errors.Wrapf(err, "cannot compile the pattern of the group kind rule at index %d", i))
+		}
+		gkPatterns[i] = re
+	}
 	p.skippedResourceNames = make([]string, 0, len(resourceMap))
+	p.skipReasons = make(map[string]SkipReason)
 	for name, terraformResource := range resourceMap {
-		if len(terraformResource.Schema) == 0 {
+		var reason SkipReason
+		switch {
+		case len(terraformResource.Schema) == 0:
 			// There are resources with no schema, that we will address later.
 			fmt.Printf("Skipping resource %s because it has no schema\n", name)
+			reason = SkipReasonNoSchema
+		case matches(name, p.SkipList):
+			reason = SkipReasonSkipList
+		case !matches(name, p.IncludeList):
+			reason = SkipReasonNotIncluded
 		}
-		if len(terraformResource.Schema) == 0 || matches(name, p.SkipList) || !matches(name, p.IncludeList) {
+		if reason != "" {
 			p.skippedResourceNames = append(p.skippedResourceNames, name)
+			p.skipReasons[name] = reason
+			continue
+		}
+		p.Resources[name] = p.newResource(name, terraformResource, providerMetadata.Resources[name], gkPatterns)
+	}
+	for _, a := range p.ResourceAliases {
+		terraformResource, ok := resourceMap[a.Name]
+		if !ok {
+			panic(errors.Errorf("cannot find the Terraform resource %s of the alias %s", a.Name, a.Kind))
+		}
+		// the alias gets its own copies of the top-level schema and the
+		// metadata so that its fields and examples can be configured
+		// independently of the Terraform resource.
+		var rm *registry.Resource
+		if m := providerMetadata.Resources[a.Name]; m != nil {
+			c := *m
+			rm = &c
+		}
+		r := p.newResource(a.Name, shallowCopy(terraformResource), rm, gkPatterns)
+		r.Kind = a.Kind
+		r.aliasKind = a.Kind
+		p.Resources[AliasKey(a.Name, a.Kind)] = r
+	}
+	for name, terraformResource := range conversiontfjson.GetV2ResourceMap(ds) {
+		if len(terraformResource.Schema) == 0 || !matches(name, p.DataSourceIncludeList) {
 			continue
 		}
-		p.Resources[name] = DefaultResource(name, terraformResource, providerMetadata.Resources[name], p.DefaultResourceOptions...)
+		r := p.newResource(name, terraformResource, nil, gkPatterns)
+		r.asDataSource()
+		r.DocsURL = p.docsURL("data-sources", name)
+		p.Resources[DataSourceKey(name)] = r
 	}
 	for i, refInjector := range p.refInjectors {
 		if err := refInjector.InjectReferences(p.Resources); err != nil {
@@ -261,7 +1011,101 @@ func NewProvider(schema []byte, prefix string, modulePath string, metadata []byt
 	return p
 }
 
-// AddResourceConfigurator adds resource specific configurators.
+// newResource returns the default configuration of the given Terraform
+// resource with the provider-wide and the group defaults and the group kind
+// rules applied.
+func (p *Provider) newResource(name string, terraformResource *schema.Resource, terraformRegistry *registry.Resource, gkPatterns []*regexp.Regexp) *Resource {
+	r := DefaultResource(name, terraformResource, terraformRegistry, p.DefaultResourceOptions...)
+	r.DocsURL = p.docsURL("resources", name)
+	p.ResourceDefaults.apply(r)
+	for i, rule := range p.GroupKindRules {
+		if rule.apply(gkPatterns[i], r) {
+			break
+		}
+	}
+	if d, ok := p.GroupDefaults[r.ShortGroup]; ok {
+		d.apply(r)
+	}
+	return r
+}
+
+// SharedBlockCandidates returns the groups of the identical blocks of the
+// resources, which can be generated as shared blocks, in the form of
+// "resource:path", e.g. "aws_lb:subnet_mapping". Only the blocks found in
+// multiple resources are returned, excluding the ones with sensitive
+// fields and the ones already shared. The groups are sorted.
+func (p *Provider) SharedBlockCandidates() [][]string {
+	shared := map[string]struct{}{}
+	for _, s := range p.SharedBlockSchemas {
+		shared[SchemaFingerprint(s)] = struct{}{}
+	}
+	blocks := map[string][]string{}
+	resources := map[string]map[string]struct{}{}
+	for _, r := range p.Resources {
+		if r.TerraformResource == nil || r.DataSource || r.MovedTo != nil {
+			continue
+		}
+		name := r.Name
+		collectBlocks(r.TerraformResource, "", func(path string, er *schema.Resource) {
+			fp := SchemaFingerprint(er)
+			if _, ok := shared[fp]; ok || hasSensitiveField(er) {
+				return
+			}
+			blocks[fp] = append(blocks[fp], name+":"+path)
+			if resources[fp] == nil {
+				resources[fp] = map[string]struct{}{}
+			}
+			resources[fp][name] = struct{}{}
+		})
+	}
+	var groups [][]string
+	for fp, l := range blocks {
+		if len(resources[fp]) < 2 {
+			continue
+		}
+		sort.Strings(l)
+		groups = append(groups, l)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i][0] < groups[j][0]
+	})
+	return groups
+}
+
+func collectBlocks(res *schema.Resource, prefix string, fn func(path string, er *schema.Resource)) {
+	for k, s := range res.Schema {
+		er, ok := s.Elem.(*schema.Resource)
+		if !ok {
+			continue
+		}
+		fn(prefix+k, er)
+		collectBlocks(er, prefix+k+".", fn)
+	}
+}
+
+// docsURL returns the URL of the documentation of the given Terraform
+// resource or data source in the Terraform registry, or an empty string if
+// the TerraformProviderSource is not configured.
+func (p *Provider) docsURL(kind, name string) string {
+	if p.TerraformProviderSource == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://registry.terraform.io/providers/%s/latest/docs/%s/%s", p.TerraformProviderSource, kind, strings.TrimPrefix(name, p.TerraformResourcePrefix))
+}
+
+// shallowCopy returns a copy of the given Terraform resource with a copy of
+// its top-level schema map.
+func shallowCopy(tr *schema.Resource) *schema.Resource {
+	c := *tr
+	c.Schema = make(map[string]*schema.Schema, len(tr.Schema))
+	for k, v := range tr.Schema {
+		c.Schema[k] = v
+	}
+	return &c
+}
+
+// AddResourceConfigurator adds resource specific configurators. The
+// configurators of an alias are added with its AliasKey.
 func (p *Provider) AddResourceConfigurator(resource string, c ResourceConfiguratorFn) { //nolint:interfacer
 	// Note(turkenh): nolint reasoning - easier to provide a function without
 	// converting to an explicit type supporting the ResourceConfigurator
@@ -280,10 +1124,86 @@ func (p *Provider) SetResourceConfigurator(resource string, c ResourceConfigurat
 func (p *Provider) ConfigureResources() {
 	for name, c := range p.resourceConfigurators {
 		// if not skipped & included & configured via the default configurator
-		if r, ok := p.Resources[name]; ok {
+		if r, ok := p.Resources[name]; ok && r.aliasKind == "" {
 			c.Configure(r)
 		}
 	}
+	for name, r := range p.Resources {
+		if r.aliasKind == "" {
+			continue
+		}
+		// the configurators of an alias are applied after the ones of its
+		// Terraform resource, which must not override the alias Kind.
+		p.resourceConfigurators[r.Name].Configure(r)
+		r.Kind = r.aliasKind
+		p.resourceConfigurators[name].Configure(r)
+	}
+	for _, vp := range p.VersionPromotions {
+		for name, r := range p.Resources {
+			if _, err := vp.apply(r); err != nil {
+				panic(errors.Wrapf(err, "cannot promote the resource %s", name))
+			}
+		}
+	}
+	for _, m := range p.MovedResources {
+		r, err := p.movedResource(m)
+		if err != nil {
+			panic(errors.Wrapf(err, "cannot configure the moved resource %s", m.Name))
+		}
+		p.Resources[r.Key()] = r
+	}
+	for name, r := range p.Resources {
+		if err := r.ApplySchemaElementOptions(); err != nil {
+			panic(errors.Wrapf(err, "cannot apply the schema element options of resource %s", name))
+		}
+	}
+}
+
+// movedResource returns the configuration of the given previous Kind, which
+// is a copy of the configuration of the current Kind served only in the
+// previous version.
+func (p *Provider) movedResource(m MovedResource) (*Resource, error) {
+	to, ok := p.Resources[m.Name]
+	if !ok {
+		return nil, errors.New("cannot find the configuration of the current Kind")
+	}
+	r := *to
+	r.TerraformResource = shallowCopy(to.TerraformResource)
+	r.ServedVersions = nil
+	r.Conversions = nil
+	r.aliasKind = ""
+	r.MovedTo = to
+	if m.ShortGroup != "" {
+		r.ShortGroup = m.ShortGroup
+	}
+	if m.Version != "" {
+		r.Version = m.Version
+	}
+	if m.Kind != "" {
+		r.Kind = m.Kind
+	}
+	if r.ShortGroup == to.ShortGroup && r.Kind == to.Kind {
+		return nil, errors.New("either the short group or the Kind of a moved resource must differ from the current ones")
+	}
+	if _, ok := p.Resources[r.Key()]; ok {
+		return nil, errors.Errorf("previous Kind %s of group %s is already moved", r.Kind, r.ShortGroup)
+	}
+	return &r, nil
+}
+
+// GetResource returns the configuration of the resource with the given
+// Terraform name and Kind, i.e. the configuration of the alias of the
+// Terraform resource or of the Terraform data source with that Kind if there
+// is one, and the configuration of the Terraform resource otherwise.
+func (p *Provider) GetResource(name, kind string) (*Resource, bool) {
+	if r, ok := p.Resources[AliasKey(name, kind)]; ok {
+		return r, true
+	}
+	if r, ok := p.Resources[DataSourceKey(name)]; ok && r.Kind == kind {
+		return r, true
+	}
+	r, ok := p.Resources[name]
+	return r, ok
 }
 
 // GetSkippedResourceNames returns a list of Terraform resource names
@@ -294,6 +1214,12 @@ func (p *Provider) GetSkippedResourceNames() []string {
 	return p.skippedResourceNames
 }
 
+// GetSkipReason returns the reason the Terraform resource with the given
+// name is skipped, which is empty if it's not skipped.
+func (p *Provider) GetSkipReason(name string) SkipReason {
+	return p.skipReasons[name]
+}
+
 func matches(name string, regexList []string) bool {
 	for _, r := range regexList {
 		ok, err := regexp.MatchString(r, name)
@@ -0,0 +1,625 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package config
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestGroupKindRuleApply(t *testing.T) {
+	type want struct {
+		matched    bool
+		shortGroup string
+		kind       string
+	}
+	cases := map[string]struct {
+		reason string
+		rule   GroupKindRule
+		name   string
+		want   want
+	}{
+		"ExpandGroupAndKind": {
+			reason: "The group and the kind templates should be expanded with the submatches.",
+			rule: GroupKindRule{
+				Pattern:    `^aws_(ec2)_(.+)$`,
+				ShortGroup: "${1}",
+				Kind:       "${2}",
+			},
+			name: "aws_ec2_transit_gateway",
+			want: want{
+				matched:    true,
+				shortGroup: "ec2",
+				kind:       "TransitGateway",
+			},
+		},
+		"KeepDefaultGroup": {
+			reason: "The default group should be kept if the group template is empty.",
+			rule: GroupKindRule{
+				Pattern: `^aws_default_(.+)$`,
+				Kind:    "default_${1}",
+			},
+			name: "aws_default_vpc",
+			want: want{
+				matched:    true,
+				shortGroup: "default",
+				kind:       "DefaultVPC",
+			},
+		},
+		"NoMatch": {
+			reason: "A resource whose name does not match the pattern should not be modified.",
+			rule: GroupKindRule{
+				Pattern:    `^aws_s3_(.+)$`,
+				ShortGroup: "s3",
+			},
+			name: "aws_ec2_transit_gateway",
+			want: want{
+				shortGroup: "ec2",
+				kind:       "TransitGateway",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := DefaultResource(tc.name, nil, nil)
+			matched := tc.rule.apply(regexp.MustCompile(tc.rule.Pattern), r)
+			got := want{matched: matched, shortGroup: r.ShortGroup, kind: r.Kind}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\napply(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResourceDefaultsApply(t *testing.T) {
+	syncMode, schemaDefaults := false, true
+	type want struct {
+		useAsync       bool
+		pollInterval   time.Duration
+		schemaDefaults bool
+		fields         []string
+		kind           string
+		tagsKeys       []string
+	}
+	cases := map[string]struct {
+		reason   string
+		defaults []ResourceDefaults
+		want     want
+	}{
+		"NoDefaults": {
+			reason: "The resource should be left as is if no defaults are set.",
+			want: want{
+				useAsync: true,
+				fields:   []string{"name", "tags", "tags_all"},
+				kind:     "Resource",
+			},
+		},
+		"Layered": {
+			reason: "The unset fields of a layer should keep the defaults of the enclosing layer.",
+			defaults: []ResourceDefaults{
+				{
+					UseAsync:       &syncMode,
+					PollInterval:   time.Minute,
+					SchemaDefaults: &schemaDefaults,
+					OmittedFields:  []string{"tags_all"},
+				},
+				{
+					PollInterval: 10 * time.Minute,
+					Options: []ResourceOption{
+						func(r *Resource) {
+							r.Kind = "Custom"
+						},
+					},
+				},
+			},
+			want: want{
+				useAsync:       false,
+				pollInterval:   10 * time.Minute,
+				schemaDefaults: true,
+				fields:         []string{"name", "tags"},
+				kind:           "Custom",
+			},
+		},
+		"MapNormalizations": {
+			reason: "The map normalizations should be set for the existing map fields and override those of the enclosing layer.",
+			defaults: []ResourceDefaults{
+				{
+					MapNormalizations: map[string]MapNormalization{
+						"tags":   {IgnoredKeys: []string{"aws:*"}},
+						"labels": {LowercaseKeys: true},
+						"name":   {LowercaseKeys: true},
+					},
+				},
+				{
+					MapNormalizations: map[string]MapNormalization{
+						"tags": {IgnoredKeys: []string{"azure:*"}},
+					},
+				},
+			},
+			want: want{
+				useAsync: true,
+				fields:   []string{"name", "tags", "tags_all"},
+				kind:     "Resource",
+				tagsKeys: []string{"azure:*"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := DefaultResource("aws_test_resource", &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name":     {Type: schema.TypeString},
+					"tags":     {Type: schema.TypeMap},
+					"tags_all": {Type: schema.TypeMap},
+				},
+			}, nil)
+			for _, d := range tc.defaults {
+				d.apply(r)
+			}
+			fields := make([]string, 0, len(r.TerraformResource.Schema))
+			for f := range r.TerraformResource.Schema {
+				fields = append(fields, f)
+			}
+			sort.Strings(fields)
+			got := want{useAsync: r.UseAsync, pollInterval: r.PollInterval, schemaDefaults: r.SchemaDefaults, fields: fields, kind: r.Kind}
+			if len(r.MapNormalizations) > 0 {
+				if len(r.MapNormalizations) != 1 {
+					t.Fatalf("\n%s\napply(...): unexpected map normalizations: %v", tc.reason, r.MapNormalizations)
+				}
+				got.tagsKeys = r.MapNormalizations["tags"].IgnoredKeys
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\napply(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConfigureResourceAliases(t *testing.T) {
+	tr := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"bucket": {Type: schema.TypeString, Optional: true},
+			"acl":    {Type: schema.TypeString, Optional: true},
+		},
+	}
+	bucket := DefaultResource("aws_s3_bucket", tr, nil)
+	alias := DefaultResource("aws_s3_bucket", shallowCopy(tr), nil)
+	alias.Kind = "LoggingBucket"
+	alias.aliasKind = "LoggingBucket"
+	p := &Provider{
+		Resources: map[string]*Resource{
+			"aws_s3_bucket": bucket,
+			AliasKey("aws_s3_bucket", "LoggingBucket"): alias,
+		},
+		resourceConfigurators: map[string]ResourceConfiguratorChain{},
+	}
+	p.AddResourceConfigurator("aws_s3_bucket", func(r *Resource) {
+		r.Kind = "Bucket"
+		r.UseAsync = false
+	})
+	p.AddResourceConfigurator(AliasKey("aws_s3_bucket", "LoggingBucket"), func(r *Resource) {
+		delete(r.TerraformResource.Schema, "acl")
+		r.ParameterDefaults = map[string]any{"acl": "log-delivery-write"}
+	})
+	p.ConfigureResources()
+
+	type want struct {
+		kind     string
+		key      string
+		useAsync bool
+		fields   []string
+		defaults map[string]any
+	}
+	cases := map[string]struct {
+		reason string
+		kind   string
+		want   want
+	}{
+		"TerraformResource": {
+			reason: "The Terraform resource should only be configured with its own configurators.",
+			kind:   "Bucket",
+			want: want{
+				kind:   "Bucket",
+				key:    "aws_s3_bucket",
+				fields: []string{"acl", "bucket"},
+			},
+		},
+		"Alias": {
+			reason: "The alias should be configured with the configurators of its Terraform resource and then with its own, keeping its Kind.",
+			kind:   "LoggingBucket",
+			want: want{
+				kind:     "LoggingBucket",
+				key:      "aws_s3_bucket/LoggingBucket",
+				fields:   []string{"bucket"},
+				defaults: map[string]any{"acl": "log-delivery-write"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, ok := p.GetResource("aws_s3_bucket", tc.kind)
+			if !ok {
+				t.Fatalf("GetResource(...): resource not found")
+			}
+			fields := make([]string, 0, len(r.TerraformResource.Schema))
+			for f := range r.TerraformResource.Schema {
+				fields = append(fields, f)
+			}
+			sort.Strings(fields)
+			got := want{kind: r.Kind, key: r.Key(), useAsync: r.UseAsync, fields: fields, defaults: r.ParameterDefaults}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nConfigureResources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConfigureDataSources(t *testing.T) {
+	tr := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":   {Type: schema.TypeString, Optional: true},
+			"vpc_id": {Type: schema.TypeString, Computed: true},
+		},
+	}
+	ds := DefaultResource("aws_vpc", tr, nil)
+	ds.asDataSource()
+	p := &Provider{
+		Resources: map[string]*Resource{
+			"aws_vpc":                DefaultResource("aws_vpc", tr, nil),
+			DataSourceKey("aws_vpc"): ds,
+		},
+		resourceConfigurators: map[string]ResourceConfiguratorChain{},
+	}
+	p.AddResourceConfigurator("aws_vpc", func(r *Resource) {
+		r.ShortGroup = "ec2"
+	})
+	p.AddResourceConfigurator(DataSourceKey("aws_vpc"), func(r *Resource) {
+		r.ShortGroup = "ec2data"
+	})
+	p.ConfigureResources()
+
+	type want struct {
+		group    string
+		key      string
+		useAsync bool
+		policies ManagementPolicies
+	}
+	cases := map[string]struct {
+		reason string
+		kind   string
+		want   want
+	}{
+		"TerraformResource": {
+			reason: "The Terraform resource should not be configured with the configurators of the data source.",
+			kind:   "VPC",
+			want: want{
+				group:    "ec2",
+				key:      "aws_vpc",
+				useAsync: true,
+			},
+		},
+		"DataSource": {
+			reason: "The data source should be configured with its own configurators and it should only be observed.",
+			kind:   "DataVPC",
+			want: want{
+				group: "ec2data",
+				key:   "data.aws_vpc",
+				policies: ManagementPolicies{
+					Supported: []xpv1.ManagementPolicies{{xpv1.ManagementActionObserve}},
+					Default:   xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, ok := p.GetResource("aws_vpc", tc.kind)
+			if !ok {
+				t.Fatalf("GetResource(...): resource not found")
+			}
+			got := want{group: r.ShortGroup, key: r.Key(), useAsync: r.UseAsync, policies: r.ManagementPolicies}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nConfigureResources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMovedResource(t *testing.T) {
+	bucket := DefaultResource("aws_s3_bucket", &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"bucket": {Type: schema.TypeString, Optional: true},
+		},
+	}, nil)
+	bucket.ShortGroup = "storage"
+	bucket.Version = "v1beta2"
+	bucket.ServedVersions = []string{"v1beta1"}
+	type want struct {
+		key        string
+		shortGroup string
+		version    string
+		kind       string
+		err        string
+	}
+	cases := map[string]struct {
+		reason string
+		moved  MovedResource
+		want   want
+	}{
+		"Moved": {
+			reason: "The previous Kind should be a copy of the current one in the previous group and version, served in a single version.",
+			moved:  MovedResource{Name: "aws_s3_bucket", ShortGroup: "s3", Version: "v1beta1"},
+			want: want{
+				key:        "aws_s3_bucket/s3/Bucket",
+				shortGroup: "s3",
+				version:    "v1beta1",
+				kind:       "Bucket",
+			},
+		},
+		"NotFound": {
+			reason: "An error should be returned if the current Kind is not configured.",
+			moved:  MovedResource{Name: "aws_s3_object", Kind: "Object"},
+			want: want{
+				err: "cannot find the configuration of the current Kind",
+			},
+		},
+		"NotMoved": {
+			reason: "An error should be returned if neither the short group nor the Kind is changed.",
+			moved:  MovedResource{Name: "aws_s3_bucket", Version: "v1alpha1"},
+			want: want{
+				err: "either the short group or the Kind of a moved resource must differ from the current ones",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &Provider{Resources: map[string]*Resource{"aws_s3_bucket": bucket}}
+			r, err := p.movedResource(tc.moved)
+			got := want{}
+			if err != nil {
+				got.err = err.Error()
+			} else {
+				got = want{key: r.Key(), shortGroup: r.ShortGroup, version: r.Version, kind: r.Kind}
+				if r.MovedTo != bucket || len(r.ServedVersions) != 0 {
+					t.Errorf("\n%s\nmovedResource(...): want the current Kind in MovedTo and no served versions", tc.reason)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nmovedResource(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestVersionPromotionApply(t *testing.T) {
+	type args struct {
+		promotion VersionPromotion
+		name      string
+		version   string
+		served    []string
+		embedding SingletonListEmbedding
+	}
+	type want struct {
+		promoted  bool
+		version   string
+		served    []string
+		embedding SingletonListEmbedding
+		err       string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Promoted": {
+			reason: "A resource in the version promoted from should be stored in the new version and served in the previous one.",
+			args: args{
+				promotion: VersionPromotion{From: "v1beta1", To: "v1beta2"},
+				name:      "aws_s3_bucket",
+				version:   "v1beta1",
+			},
+			want: want{
+				promoted: true,
+				version:  "v1beta2",
+				served:   []string{"v1beta1"},
+			},
+		},
+		"AlreadyServed": {
+			reason: "The previously served versions of a promoted resource should still be served.",
+			args: args{
+				promotion: VersionPromotion{From: "v1beta1", To: "v1"},
+				name:      "aws_s3_bucket",
+				version:   "v1beta1",
+				served:    []string{"v1alpha1"},
+			},
+			want: want{
+				promoted: true,
+				version:  "v1",
+				served:   []string{"v1beta1", "v1alpha1"},
+			},
+		},
+		"NotSelected": {
+			reason: "A resource not matching the patterns of the promotion should not be promoted.",
+			args: args{
+				promotion: VersionPromotion{From: "v1beta1", To: "v1beta2", Resources: []string{"^aws_ec2_"}},
+				name:      "aws_s3_bucket",
+				version:   "v1beta1",
+			},
+			want: want{
+				version: "v1beta1",
+			},
+		},
+		"OtherVersion": {
+			reason: "A resource in another version should not be promoted.",
+			args: args{
+				promotion: VersionPromotion{From: "v1beta1", To: "v1beta2"},
+				name:      "aws_s3_bucket",
+				version:   "v1alpha1",
+			},
+			want: want{
+				version: "v1alpha1",
+			},
+		},
+		"EmbedSingletonLists": {
+			reason: "The singleton lists should be embedded since the new version if configured.",
+			args: args{
+				promotion: VersionPromotion{From: "v1beta1", To: "v1beta2", EmbedSingletonLists: true},
+				name:      "aws_s3_bucket",
+				version:   "v1beta1",
+			},
+			want: want{
+				promoted:  true,
+				version:   "v1beta2",
+				served:    []string{"v1beta1"},
+				embedding: SingletonListEmbedding{Enabled: true, Since: "v1beta2"},
+			},
+		},
+		"SingletonListEmbeddingConfigured": {
+			reason: "The configured singleton list embedding of a resource should be kept.",
+			args: args{
+				promotion: VersionPromotion{From: "v1beta1", To: "v1beta2", EmbedSingletonLists: true},
+				name:      "aws_s3_bucket",
+				version:   "v1beta1",
+				embedding: SingletonListEmbedding{Enabled: true},
+			},
+			want: want{
+				promoted:  true,
+				version:   "v1beta2",
+				served:    []string{"v1beta1"},
+				embedding: SingletonListEmbedding{Enabled: true},
+			},
+		},
+		"NotNewer": {
+			reason: "An error should be returned if the version promoted to is not newer than the version promoted from.",
+			args: args{
+				promotion: VersionPromotion{From: "v1beta2", To: "v1beta1"},
+				name:      "aws_s3_bucket",
+				version:   "v1beta2",
+			},
+			want: want{
+				version: "v1beta2",
+				err:     `the version "v1beta1" to promote to must be newer than the version "v1beta2"`,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := DefaultResource(tc.args.name, &schema.Resource{}, nil)
+			r.Version = tc.args.version
+			r.ServedVersions = tc.args.served
+			r.SingletonListEmbedding = tc.args.embedding
+			configured := false
+			tc.args.promotion.Configure = func(_ *Resource) {
+				configured = true
+			}
+			promoted, err := tc.args.promotion.apply(r)
+			got := want{promoted: promoted, version: r.Version, served: r.ServedVersions, embedding: r.SingletonListEmbedding}
+			if err != nil {
+				got.err = err.Error()
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\napply(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.promoted, configured); diff != "" {
+				t.Errorf("\n%s\napply(...): -want configured, +got configured:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDocsURL(t *testing.T) {
+	type args struct {
+		source string
+		kind   string
+		name   string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"NoSource": {
+			reason: "No documentation URL should be derived if the Terraform provider source is not configured.",
+			args: args{
+				kind: "resources",
+				name: "aws_vpc",
+			},
+		},
+		"Resource": {
+			reason: "The documentation URL of a resource should not contain the resource prefix.",
+			args: args{
+				source: "hashicorp/aws",
+				kind:   "resources",
+				name:   "aws_vpc",
+			},
+			want: "https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/vpc",
+		},
+		"DataSource": {
+			reason: "The documentation URL of a data source should point to the data sources section.",
+			args: args{
+				source: "hashicorp/aws",
+				kind:   "data-sources",
+				name:   "aws_vpc",
+			},
+			want: "https://registry.terraform.io/providers/hashicorp/aws/latest/docs/data-sources/vpc",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &Provider{TerraformResourcePrefix: "aws_", TerraformProviderSource: tc.args.source}
+			if diff := cmp.Diff(tc.want, p.docsURL(tc.args.kind, tc.args.name)); diff != "" {
+				t.Errorf("\n%s\ndocsURL(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSharedBlockCandidates(t *testing.T) {
+	endpoint := func() *schema.Schema {
+		return &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"address": {Type: schema.TypeString, Required: true},
+				},
+			},
+		}
+	}
+	cases := map[string]struct {
+		reason string
+		shared map[string]*schema.Resource
+		want   [][]string
+	}{
+		"Candidates": {
+			reason: "The identical blocks of multiple resources should be returned.",
+			want:   [][]string{{"aws_a:endpoint", "aws_b:target"}},
+		},
+		"AlreadyShared": {
+			reason: "The blocks identical to a shared block should not be returned.",
+			shared: map[string]*schema.Resource{"Endpoint": endpoint().Elem.(*schema.Resource)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &Provider{
+				SharedBlockSchemas: tc.shared,
+				Resources: map[string]*Resource{
+					"aws_a": {Name: "aws_a", TerraformResource: &schema.Resource{Schema: map[string]*schema.Schema{"endpoint": endpoint()}}},
+					"aws_b": {Name: "aws_b", TerraformResource: &schema.Resource{Schema: map[string]*schema.Schema{"target": endpoint()}}},
+				},
+			}
+			if diff := cmp.Diff(tc.want, p.SharedBlockCandidates()); diff != "" {
+				t.Errorf("\n%s\nSharedBlockCandidates(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package config
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// CredentialsSourceStaticKeys is the source of the credentials whose
+// ProviderConfigAuth.StaticKeys are read individually from the keys of the
+// Secret referenced by the ProviderConfig.
+const CredentialsSourceStaticKeys xpv1.CredentialsSource = "StaticKeys"
+
+// ProviderConfigAuth is the declarative specification of the credentials
+// supported by the ProviderConfig of a provider, from which the
+// ProviderConfig and ProviderConfigUsage types, the ProviderConfig
+// controller and the extraction of the credentials into the Terraform
+// provider configuration are generated.
+type ProviderConfigAuth struct {
+	// Sources are the sources of the credentials supported by the
+	// ProviderConfig, which are the Secret, Environment, Filesystem,
+	// InjectedIdentity, e.g. IRSA or workload identity, and StaticKeys
+	// sources. Defaults to the Secret source.
+	Sources []xpv1.CredentialsSource
+
+	// CredentialKeys are the keys of the JSON document of the credentials
+	// read from the Secret, Environment and Filesystem sources, which are
+	// set as the same named arguments of the Terraform provider
+	// configuration, e.g. "token". All the keys of the document are set if
+	// empty.
+	CredentialKeys []string
+
+	// StaticKeys are the keys of the Secret of the StaticKeys source, which
+	// are set as the same named arguments of the Terraform provider
+	// configuration, e.g. "access_key" and "secret_key". They're required if
+	// the StaticKeys source is supported.
+	StaticKeys []string
+
+	// InjectedIdentityEnv maps the arguments of the Terraform provider
+	// configuration to the environment variables of the provider's pod they
+	// are set from with the InjectedIdentity source, e.g. "role_arn":
+	// "AWS_ROLE_ARN". No arguments are set if empty, e.g. if the Terraform
+	// provider reads the injected identity from the environment itself.
+	InjectedIdentityEnv map[string]string
+
+	// AssumeRoleChain enables the chain of the roles assumed with the
+	// credentials, which is configured in the ProviderConfig. See
+	// AssumeRoleChain for details.
+	AssumeRoleChain *AssumeRoleChain
+}
+
+// AssumeRoleChain configures how the chain of the roles assumed with the
+// credentials of a ProviderConfig is set in the Terraform provider
+// configuration.
+type AssumeRoleChain struct {
+	// Argument is the block of the Terraform provider configuration the
+	// roles are set in, in the order they are assumed. Defaults to
+	// "assume_role".
+	Argument string
+
+	// RoleArgument is the argument of the block for the role to assume.
+	// Defaults to "role_arn".
+	RoleArgument string
+
+	// ExternalIDArgument is the argument of the block for the external ID
+	// of the role. Defaults to "external_id".
+	ExternalIDArgument string
+
+	// SessionNameArgument is the argument of the block for the name of the
+	// session of the role. Defaults to "session_name".
+	SessionNameArgument string
+}
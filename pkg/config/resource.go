@@ -7,12 +7,17 @@ package config
 import (
 	"context"
 	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/apimachinery/pkg/util/sets"
+	apiversion "k8s.io/apimachinery/pkg/version"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -21,9 +26,15 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
 
+	"github.com/upbound/upjet/pkg/config/conversion"
 	"github.com/upbound/upjet/pkg/registry"
+	tjname "github.com/upbound/upjet/pkg/types/name"
 )
 
+// reIndexSegment matches the list index segments, e.g. "[*]" or "[0]", in
+// a field path.
+var reIndexSegment = regexp.MustCompile(`\[(\*|\d+)\]`)
+
 // SetIdentifierArgumentsFn sets the name of the resource in Terraform attributes map,
 // i.e. Main HCL file.
 type SetIdentifierArgumentsFn func(base map[string]any, externalName string)
@@ -62,6 +73,107 @@ var NopAdditionalConnectionDetails AdditionalConnectionDetailsFn = func(_ map[st
 	return nil, nil
 }
 
+// ReadinessCheckFn reports whether the external resource with the given
+// observed Terraform attributes is ready to be used.
+type ReadinessCheckFn func(attr map[string]any) (bool, error)
+
+// ReadyWhenAttribute returns a ReadinessCheckFn reporting the external
+// resource as ready when the attribute at the given path, e.g. "status" or
+// "endpoint[0].state", has one of the given values.
+func ReadyWhenAttribute(path string, values ...string) ReadinessCheckFn {
+	return func(attr map[string]any) (bool, error) {
+		v, err := fieldpath.Pave(attr).GetValue(path)
+		if fieldpath.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, errors.Wrapf(err, "cannot get the value of the attribute %q", path)
+		}
+		for _, val := range values {
+			if fmt.Sprint(v) == val {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// FieldDiff is a difference between the desired and the observed values of a
+// field of a resource.
+type FieldDiff struct {
+	// Path is the Terraform path of the field including the indices of the
+	// list elements, e.g. "rule[0].filter".
+	Path string
+	// Old is the observed value of the field.
+	Old string
+	// New is the desired value of the field.
+	New string
+}
+
+// DiffSuppressFn reports whether the difference between the observed (old)
+// and the desired (new) values of a field is spurious, e.g. caused by a
+// server-side normalization, and should not trigger an update.
+type DiffSuppressFn func(oldValue, newValue string) bool
+
+// DiffFilterFn returns the significant ones of the given field differences
+// which have not been suppressed by the DiffSuppressFns.
+type DiffFilterFn func(diffs []FieldDiff) []FieldDiff
+
+// MapNormalization configures the normalization of the values of a map
+// field, e.g. "tags", which is applied to both the desired and the observed
+// values before they are compared, and to the observed value before it is
+// late-initialized, so that the insignificant differences do not cause
+// perpetual updates.
+type MapNormalization struct {
+	// LowercaseKeys converts the keys of the map into lower case.
+	LowercaseKeys bool
+
+	// IgnoredKeys are the keys managed by the provider or the external
+	// system, which are dropped from the map. A key ending with "*" matches
+	// the keys with the given prefix, e.g. "aws:*".
+	IgnoredKeys []string
+
+	// DefaultEntries are added to the map if it does not have their keys,
+	// e.g. the default tags added by the provider.
+	DefaultEntries map[string]string
+}
+
+// Normalize returns the normalized copy of the given map value.
+func (n MapNormalization) Normalize(m map[string]any) map[string]any {
+	norm := make(map[string]any, len(m)+len(n.DefaultEntries))
+	for k, v := range m {
+		if n.ignored(k) {
+			continue
+		}
+		norm[n.key(k)] = v
+	}
+	for k, v := range n.DefaultEntries {
+		if _, ok := norm[n.key(k)]; !ok {
+			norm[n.key(k)] = v
+		}
+	}
+	return norm
+}
+
+func (n MapNormalization) key(k string) string {
+	if n.LowercaseKeys {
+		return strings.ToLower(k)
+	}
+	return k
+}
+
+func (n MapNormalization) ignored(k string) bool {
+	for _, ik := range n.IgnoredKeys {
+		if p, ok := strings.CutSuffix(ik, "*"); ok && strings.HasPrefix(n.key(k), n.key(p)) {
+			return true
+		}
+		if n.key(k) == n.key(ik) {
+			return true
+		}
+	}
+	return false
+}
+
 // ExternalName contains all information that is necessary for naming operations,
 // such as removal of those fields from spec schema and calling Configure function
 // to fill attributes with information given in external name.
@@ -90,6 +202,9 @@ type ExternalName struct {
 	// The function here should use information from supplied arguments to
 	// construct this ID, i.e. "mygroup1" from external name, subscription ID
 	// from terraformProviderConfig, and others from parameters map if needed.
+	// The values resolved from the ProviderConfig or the environment are
+	// available in the "context" of terraformProviderConfig, see
+	// SetupContextValue.
 	GetIDFn GetIDFn
 
 	// OmittedFields are the ones you'd like to be removed from the schema since
@@ -107,6 +222,14 @@ type ExternalName struct {
 	// and not let you name it.
 	DisableNameInitializer bool
 
+	// AdoptOnExternalName allows adopting existing external resources whose
+	// identifiers are assigned by the provider. If the external-name
+	// annotation of a managed resource is set before it is created, the
+	// external resource with that identifier is imported instead of a new
+	// one being created, and the reconciliation fails if there is no such
+	// external resource. It is enabled by IdentifierFromProvider.
+	AdoptOnExternalName bool
+
 	// IdentifierFields are the fields that are used to construct external
 	// resource identifier. We need to know these fields no matter what the
 	// management policy is including the Observe Only, different from other
@@ -127,7 +250,8 @@ type Reference struct {
 	// TerraformName is the name of the Terraform resource
 	// which will be referenced. The supplied resource name is
 	// converted to a type name of the corresponding CRD using
-	// the configured TerraformTypeMapper.
+	// the configured TerraformTypeMapper. The resource may also belong
+	// to one of the ExternalProviders of the Provider.
 	TerraformName string
 	// Extractor is the function to be used to extract value from the
 	// referenced type. Defaults to getting external name.
@@ -145,15 +269,37 @@ type Reference struct {
 
 // Sensitive represents configurations to handle sensitive information
 type Sensitive struct {
-	// AdditionalConnectionDetailsFn is the path for function adding additional
-	// connection details keys
+	// AdditionalConnectionDetailsFn is the function adding additional
+	// connection details keys computed from the Terraform state attributes,
+	// e.g. a kubeconfig or a JDBC URL composed of several attributes. The
+	// returned keys are merged with the sensitive attributes of the resource
+	// and must not collide with them. Optional.
 	AdditionalConnectionDetailsFn AdditionalConnectionDetailsFn
 
+	// WriteOnlyFields are the names of the top-level Terraform arguments,
+	// e.g. "master_password", whose values are sent to Terraform but never
+	// round-trip into the Terraform state, the connection details or the
+	// status of the managed resource. They are generated as secret key
+	// references like the sensitive arguments. Since their values are not
+	// kept in the state, changes to them after creation are ignored.
+	WriteOnlyFields []string
+
 	// fieldPaths keeps the mapping of sensitive fields in Terraform schema with
 	// terraform field path as key and xp field path as value.
 	fieldPaths map[string]string
 }
 
+// IsWriteOnly returns whether the given top-level Terraform argument is
+// configured as write-only.
+func (s *Sensitive) IsWriteOnly(tfName string) bool {
+	for _, f := range s.WriteOnlyFields {
+		if f == tfName {
+			return true
+		}
+	}
+	return false
+}
+
 // LateInitializer represents configurations that control
 // late-initialization behaviour
 type LateInitializer struct {
@@ -162,6 +308,15 @@ type LateInitializer struct {
 	// Terraform field paths concatenated with dots. For example, if we want to
 	// ignore "ebs" block in "aws_launch_template", we should add
 	// "block_device_mappings.ebs".
+	// Each path segment may be a glob pattern as accepted by path.Match, and
+	// list indices such as "[*]" or "[0]" are ignored, so that
+	// "rule[*].filter" is equivalent to "rule.filter". A "**" segment matches
+	// zero or more segments, e.g. "rule.**" ignores the "rule" block and
+	// everything under it and "**.filter" ignores every "filter" field
+	// regardless of its depth. The entries that don't match any field in
+	// the schema, e.g. the stale ones of the removed fields, are reported
+	// during the code generation, which fails instead if
+	// Provider.StrictIgnoredFields is set.
 	IgnoredFields []string
 
 	// ignoredCanonicalFieldPaths are the Canonical field paths to be skipped
@@ -170,6 +325,47 @@ type LateInitializer struct {
 	ignoredCanonicalFieldPaths []string
 }
 
+// MatchIgnoredFields returns the IgnoredFields entries that match the given
+// Terraform field path, e.g. "rule.filter".
+func (l *LateInitializer) MatchIgnoredFields(tfPath string) []string {
+	var matched []string
+	for _, p := range l.IgnoredFields {
+		if matchFieldPath(p, tfPath) {
+			matched = append(matched, p)
+		}
+	}
+	return matched
+}
+
+// matchFieldPath reports whether the given dot-separated Terraform field path
+// matches the supplied IgnoredFields pattern.
+func matchFieldPath(pattern, fieldPath string) bool {
+	return matchSegments(strings.Split(reIndexSegment.ReplaceAllString(pattern, ""), "."), strings.Split(fieldPath, "."))
+}
+
+func matchSegments(pattern, fieldPath []string) bool {
+	if len(pattern) == 0 {
+		return len(fieldPath) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(fieldPath); i++ {
+			if matchSegments(pattern[1:], fieldPath[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(fieldPath) == 0 {
+		return false
+	}
+	// a malformed pattern does not match anything and is reported as an
+	// unmatched entry during code generation.
+	if ok, err := path.Match(pattern[0], fieldPath[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], fieldPath[1:])
+}
+
 // GetIgnoredCanonicalFields returns the ignoredCanonicalFields
 func (l *LateInitializer) GetIgnoredCanonicalFields() []string {
 	return l.ignoredCanonicalFieldPaths
@@ -180,6 +376,11 @@ func (l *LateInitializer) AddIgnoredCanonicalFields(cf string) {
 	if l.ignoredCanonicalFieldPaths == nil {
 		l.ignoredCanonicalFieldPaths = make([]string, 0)
 	}
+	for _, f := range l.ignoredCanonicalFieldPaths {
+		if f == cf {
+			return
+		}
+	}
 	l.ignoredCanonicalFieldPaths = append(l.ignoredCanonicalFieldPaths, cf)
 }
 
@@ -206,9 +407,315 @@ type OperationTimeouts struct {
 	Delete time.Duration
 }
 
+// DriftPolicy controls what the reconciler does when the external resource
+// is observed to have drifted from the desired state while the desired state
+// has not changed since the resource was last observed to be in sync.
+type DriftPolicy string
+
+const (
+	// DriftPolicyAutoCorrect reapplies the desired state to correct the
+	// drift. This is the default.
+	DriftPolicyAutoCorrect DriftPolicy = "AutoCorrect"
+	// DriftPolicyReportOnly only reports the drift with a condition without
+	// reapplying the desired state.
+	DriftPolicyReportOnly DriftPolicy = "ReportOnly"
+	// DriftPolicyBlock reports the drift with a condition and does not
+	// reapply the desired state until the remediation is approved by
+	// annotating the managed resource.
+	DriftPolicyBlock DriftPolicy = "Block"
+)
+
+// Scope is the scope of the CRD of a resource.
+type Scope string
+
+const (
+	// ScopeCluster generates a cluster-scoped CRD. This is the default.
+	ScopeCluster Scope = "Cluster"
+	// ScopeNamespaced generates a namespaced CRD. The references, the
+	// secret references and the connection secrets of the namespaced
+	// managed resources are confined to their namespaces.
+	ScopeNamespaced Scope = "Namespaced"
+)
+
+// TypeOverride is the Go type generated for a Terraform field instead of the
+// type inferred from its schema.
+type TypeOverride string
+
+const (
+	// TypeOverrideString generates a string field for a Terraform field of a
+	// primitive type, e.g., for an integer that overflows int64.
+	TypeOverrideString TypeOverride = "string"
+	// TypeOverrideStringMap generates a map of strings for a loosely-typed
+	// Terraform map field.
+	TypeOverrideStringMap TypeOverride = "map[string]string"
+	// TypeOverrideJSON generates an embedded JSON value, whose unknown
+	// fields are preserved, for a loosely-typed Terraform string field
+	// holding JSON, e.g., a policy document. The JSON value is encoded into
+	// and decoded from the Terraform string.
+	TypeOverrideJSON TypeOverride = "json"
+	// TypeOverrideIntOrString generates an intstr.IntOrString field for a
+	// Terraform integer or string field accepting both numbers and
+	// strings, e.g., a port that may also be given as an expression. The
+	// numbers must fit into int32.
+	TypeOverrideIntOrString TypeOverride = "intOrString"
+)
+
+// SchemaElementOption overrides the properties of the Terraform schema of a
+// field that are missing or wrong in the upstream schema. The overrides are
+// applied to the schema before the types are generated, and the list
+// constraints are also generated as CRD validations.
+type SchemaElementOption struct {
+	// MaxItems, if positive, is the maximum number of the elements of a list
+	// or set field, e.g. 1 for a block that can only be specified once.
+	MaxItems int
+
+	// MinItems, if positive, is the minimum number of the elements of a list
+	// or set field.
+	MinItems int
+
+	// ElemType, if set, is the type of the elements of a list, set or map
+	// field of primitive elements, e.g. schema.TypeInt for a field whose
+	// element type is missing in the schema and is hence generated as a
+	// string.
+	ElemType schema.ValueType
+}
+
+// FieldValidator configures the admission-time validation of a spec field,
+// which is generated as a CRD validation so that an invalid spec is rejected
+// by the API server instead of failing during the Terraform apply. A
+// FieldValidator configured for a field replaces the validations inferred
+// from its Terraform schema, e.g. from its validation.StringInSlice
+// validator.
+type FieldValidator struct {
+	// Pattern, if set, is the regular expression the value of a string field
+	// must match, e.g. "^[a-z0-9-]+$".
+	Pattern string
+
+	// Minimum, if set, is the inclusive lower bound of the value of a
+	// numeric field.
+	Minimum *int
+
+	// Maximum, if set, is the inclusive upper bound of the value of a
+	// numeric field.
+	Maximum *int
+
+	// Enum, if set, are the allowed values of a string or an integer field,
+	// e.g. the instance types supported by the provider.
+	Enum []string
+
+	// ExclusiveWith are the top-level Terraform arguments that cannot be set
+	// together with the field, which must also be a top-level argument.
+	ExclusiveWith []string
+}
+
+// CompositionHints are the metadata of a resource for the composition and
+// XRD tooling, which are emitted into the provider manifest generated by the
+// code generation pipeline.
+type CompositionHints struct {
+	// ClaimKind is the suggested Kind of the claims of the composite
+	// resources composing the managed resource, e.g. "Bucket".
+	ClaimKind string
+
+	// ClaimPlural is the suggested plural name of the claims, e.g.
+	// "buckets".
+	ClaimPlural string
+
+	// ConnectionSecretKeys are the suggested keys of the connection secret
+	// of the managed resource to be propagated to the composite resources,
+	// e.g. "endpoint".
+	ConnectionSecretKeys []string
+
+	// Fields are the Terraform paths of the fields relevant to the
+	// compositions, e.g. "region" or "rule.filter", which are emitted as
+	// the paths of their fields in the managed resource.
+	Fields []string
+}
+
+// FieldDeprecation configures the deprecation of a field. A deprecated field
+// is documented as deprecated, the admission webhook warns when it is set,
+// and it is no longer generated starting with the API version it is
+// removed in.
+type FieldDeprecation struct {
+	// Message is appended to the deprecation notice of the field, e.g.
+	// "Use instanceTypes instead."
+	Message string
+
+	// RemovedIn is the API version starting with which the field is no
+	// longer generated, e.g. "v1beta2". The field is generated in all
+	// versions if empty.
+	RemovedIn string
+
+	// Fallback is the conversion applied while converting the managed
+	// resources between the API versions with and without the field, e.g.
+	// to carry its value to a replacing field. Optional.
+	Fallback conversion.Conversion
+}
+
+// FieldMaturity is the maturity level of a field.
+type FieldMaturity string
+
+const (
+	// FieldMaturityAlpha is the maturity level of an experimental field,
+	// which may change or be removed without notice.
+	FieldMaturityAlpha FieldMaturity = "Alpha"
+	// FieldMaturityBeta is the maturity level of a well-tested field whose
+	// details may still change.
+	FieldMaturityBeta FieldMaturity = "Beta"
+	// FieldMaturityStable is the maturity level of a field which follows
+	// the compatibility guarantees of its API version.
+	FieldMaturityStable FieldMaturity = "Stable"
+)
+
+// Notice returns the deprecation notice of the field.
+func (d FieldDeprecation) Notice() string {
+	n := "This field is deprecated"
+	if d.RemovedIn != "" {
+		n += " and will be removed in " + d.RemovedIn
+	}
+	n += "."
+	if d.Message != "" {
+		n += " " + d.Message
+	}
+	return n
+}
+
+// SingletonListEmbedding configures the generation of the Terraform
+// configuration blocks with at most one element (MaxItems: 1) as embedded
+// objects in the CRD instead of lists with a single element. The embedded
+// objects are converted to and from the lists at the Terraform boundary.
+// The blocks with sensitive fields are not embedded.
+type SingletonListEmbedding struct {
+	// Enabled enables the embedding of the singleton lists.
+	Enabled bool
+
+	// Since is the API version starting with which the singleton lists are
+	// embedded, e.g. "v1beta2", so that the existing API versions of a CRD
+	// keep their lists. The managed resources are converted between the API
+	// versions with and without the embedded objects. The singleton lists
+	// are embedded in all versions if empty.
+	Since string
+}
+
+// ObservationPruning configures the observation fields pruned from the
+// status of the managed resources, which keeps the CRDs of the resources
+// with very large Terraform schemas under the size limits. The pruned fields
+// are neither generated in the status types nor set from the Terraform
+// state. The "id" field and the fields displayed in the printer columns
+// cannot be pruned. Please note that the pruned fields cannot be used to
+// extract the values of the references to the resource.
+type ObservationPruning struct {
+	// ExcludedFields are the Terraform field paths, e.g. "rule.filter", of
+	// the observation fields pruned together with their nested fields. List
+	// indices such as "[*]" are ignored.
+	ExcludedFields []string
+
+	// IncludedFields, if set, are the Terraform field paths of the only
+	// observation fields kept together with their nested fields and their
+	// ancestors. The ExcludedFields are pruned from them.
+	IncludedFields []string
+}
+
+// Pruned reports whether the observation field with the given Terraform
+// field path, e.g. "rule.filter", is pruned.
+func (p ObservationPruning) Pruned(tfPath string) bool {
+	for _, e := range p.ExcludedFields {
+		if e = reIndexSegment.ReplaceAllString(e, ""); tfPath == e || strings.HasPrefix(tfPath, e+".") {
+			return true
+		}
+	}
+	if len(p.IncludedFields) == 0 {
+		return false
+	}
+	for _, i := range p.IncludedFields {
+		i = reIndexSegment.ReplaceAllString(i, "")
+		if tfPath == i || strings.HasPrefix(tfPath, i+".") || strings.HasPrefix(i, tfPath+".") {
+			return false
+		}
+	}
+	return true
+}
+
+// ManagementPolicies configures the management policies of the managed
+// resources of a resource, e.g. of a read-only cloud resource which can
+// only be observed. They take effect when the management policies feature
+// is enabled.
+type ManagementPolicies struct {
+	// Supported are the supported combinations of the management actions.
+	// The managed resources with other management policies are not
+	// reconciled. The combinations supported by the managed reconciler by
+	// default are supported if empty.
+	Supported []xpv1.ManagementPolicies
+
+	// Default are the management policies set to the managed resources
+	// with the default management policies of the CRD, i.e. ["*"], instead
+	// of managing their full lifecycle. Optional.
+	Default xpv1.ManagementPolicies
+}
+
+// PrinterColumn is an additional printer column of the CRD of a resource
+// displayed by "kubectl get".
+type PrinterColumn struct {
+	// Name of the column, e.g. "REGION".
+	Name string
+	// Type of the column, which is one of "integer", "number", "string",
+	// "boolean" or "date".
+	Type string
+	// JSONPath is the path of the field displayed in the column, e.g.
+	// ".spec.forProvider.region".
+	JSONPath string
+}
+
+// Condition is a custom status condition of the managed resources of a
+// resource in addition to the Ready and Synced conditions.
+type Condition struct {
+	// Type of the condition, e.g. "LastApplyFailed", which must be a valid
+	// Go identifier.
+	Type string
+	// Reasons of the condition, e.g. "ApplyFailure", each of which must be
+	// a valid Go identifier.
+	Reasons []string
+}
+
 // NewInitializerFn returns the Initializer with a client.
 type NewInitializerFn func(client client.Client) managed.Initializer
 
+// OperationHookFn is run before or after an operation on the external
+// resource of the given managed resource. providerConfig is the Terraform
+// provider configuration built from the ProviderConfig of the managed
+// resource. An error returned by a hook fails the operation.
+type OperationHookFn func(ctx context.Context, mg xpresource.Managed, providerConfig map[string]any) error
+
+// NewOperationHookFn returns the OperationHookFn with a client.
+type NewOperationHookFn func(client client.Client) OperationHookFn
+
+// AdmissionValidationFn validates the given managed resource at admission
+// time with its Terraform parameters and the Terraform provider
+// configuration built from its ProviderConfig, e.g. to check an argument
+// against the region of the provider. The returned warnings are displayed
+// to the user and an error rejects the request.
+type AdmissionValidationFn func(ctx context.Context, mg xpresource.Managed, parameters, providerConfig map[string]any) ([]string, error)
+
+// OperationHooks are the hooks run by the controller around the operations
+// on the external resources, e.g. to set defaults from the environment,
+// acquire quotas or record audit data. The hooks of an operation are run in
+// the given order.
+type OperationHooks struct {
+	// PreCreate hooks are run before the external resource is created.
+	PreCreate []NewOperationHookFn
+	// PreUpdate hooks are run before the external resource is updated.
+	PreUpdate []NewOperationHookFn
+	// PreDelete hooks are run before the external resource is deleted.
+	PreDelete []NewOperationHookFn
+	// PostObserve hooks are run after the external resource is observed
+	// successfully.
+	PostObserve []NewOperationHookFn
+}
+
+// Empty reports whether no hooks are configured.
+func (h OperationHooks) Empty() bool {
+	return len(h.PreCreate) == 0 && len(h.PreUpdate) == 0 && len(h.PreDelete) == 0 && len(h.PostObserve) == 0
+}
+
 // TagInitializer returns a tagger to use default tag initializer.
 var TagInitializer NewInitializerFn = func(client client.Client) managed.Initializer {
 	return NewTagger(client, "tags")
@@ -283,9 +790,32 @@ type Resource struct {
 	// be `ec2.aws.crossplane.io`
 	ShortGroup string
 
-	// Version is the version CRD will have.
+	// Version is the version CRD will have. If the CRD is served in multiple
+	// versions, this is its storage version, which acts as the hub for the
+	// conversions between the versions.
 	Version string
 
+	// ServedVersions are the API versions in which the CRD is served in
+	// addition to Version, e.g. "v1beta1" when Version is "v1beta2". The
+	// types of these versions are generated from the same Terraform schema
+	// and are converted to and from the storage version by the conversion
+	// webhook. Controllers and examples are only generated for the storage
+	// version.
+	ServedVersions []string
+
+	// Conversions are the converters applied in order while converting the
+	// managed resources between the served API versions, e.g. to carry the
+	// value of a field renamed in a new version. The PavedConversions are
+	// applied before the ManagedConversions.
+	Conversions []conversion.Conversion
+
+	// TerraformConversions are the conversions applied in order between the
+	// Crossplane and the Terraform representations of the parameters,
+	// observations and states of the managed resources, e.g. to flatten the
+	// singleton lists or to convert units. They are applied in reverse order
+	// while converting from the Terraform representation.
+	TerraformConversions []conversion.TerraformConversion
+
 	// Kind is the kind of the CRD.
 	Kind string
 
@@ -296,9 +826,63 @@ type Resource struct {
 
 	InitializerFns []NewInitializerFn
 
-	// OperationTimeouts allows configuring resource operation timeouts.
+	// OperationHooks are the hooks run by the controller around the
+	// operations on the external resources.
+	OperationHooks OperationHooks
+
+	// AdmissionValidations are the validations of the managed resources run
+	// by their validating webhooks on create and update, if the webhooks are
+	// started, for the rules that cannot be expressed in CEL, e.g. the ones
+	// depending on the Terraform provider configuration.
+	AdmissionValidations []AdmissionValidationFn
+
+	// ManagementPolicies configures the supported and the default management
+	// policies of the managed resources of this kind.
+	ManagementPolicies ManagementPolicies
+
+	// PollInterval is the poll interval of the controller of the managed
+	// resources of this kind. The poll interval of the provider is used if
+	// zero.
+	PollInterval time.Duration
+
+	// DriftPolicy is the default drift remediation policy of the managed
+	// resources of this kind. It can be overridden per managed resource with
+	// the "upjet.upbound.io/drift-policy" annotation. Defaults to
+	// DriftPolicyAutoCorrect.
+	DriftPolicy DriftPolicy
+
+	// OperationTimeouts allows configuring resource operation timeouts. The
+	// reconciliation timeout of the controller and the timeouts of the
+	// asynchronous Terraform operations are extended accordingly, so that
+	// long-running operations are not cancelled before they time out.
 	OperationTimeouts OperationTimeouts
 
+	// ReadinessCheckFn, if set, is consulted with the observed Terraform
+	// attributes to decide whether the managed resource is ready, instead of
+	// considering it ready as soon as the external resource exists. It is
+	// useful for the resources provisioned asynchronously by the cloud
+	// provider, e.g. ReadyWhenAttribute("status", "ACTIVE").
+	ReadinessCheckFn ReadinessCheckFn
+
+	// DiffSuppressFns maps the Terraform field paths, e.g. "rule.filter" or
+	// "tags", to the functions suppressing the spurious differences between
+	// the desired and the observed values of the corresponding fields and
+	// their nested fields. They are consulted when the Terraform plan of a
+	// managed resource reports changes, and the resource is considered up to
+	// date if all the differences found are suppressed.
+	DiffSuppressFns map[string]DiffSuppressFn
+
+	// DiffFilterFn, if set, is called with the differences which have not
+	// been suppressed by the DiffSuppressFns and returns the significant
+	// ones. The resource is considered up to date if there are none.
+	DiffFilterFn DiffFilterFn
+
+	// MapNormalizations maps the Terraform paths of the map fields, e.g.
+	// "tags" or "rule[*].labels", to their normalizations applied while
+	// comparing the desired and the observed values and late-initializing
+	// the observed values.
+	MapNormalizations map[string]MapNormalization
+
 	// ExternalName allows you to specify a custom ExternalName.
 	ExternalName ExternalName
 
@@ -311,6 +895,102 @@ type Resource struct {
 	// LateInitializer configuration to control late-initialization behaviour
 	LateInitializer LateInitializer
 
+	// FieldRenames maps the Terraform field paths, e.g. "rule.filter", to the
+	// lower camel case names the corresponding fields should have in the CRD,
+	// e.g. "filterRule". The Terraform names of the renamed fields are kept
+	// in their tf tags so that the conversion between the CRD and the
+	// Terraform representations is not affected.
+	FieldRenames map[string]string
+
+	// OverrideFieldNames maps the Terraform paths of the blocks, e.g.
+	// "rule.filter", to the names of the Go types generated for them
+	// without the "Parameters" and "Observation" suffixes, e.g.
+	// "BucketRuleFilter". The type names of the blocks are otherwise
+	// derived from their field names and, if those collide with the types
+	// already generated in the same package, prefixed with the names of
+	// their parents, which may change as resources are added. The
+	// auto-resolved collisions are reported during the code generation so
+	// that they can be pinned here.
+	OverrideFieldNames map[string]string
+
+	// SharedBlocks maps the Terraform paths of the blocks, e.g.
+	// "rule.endpoint", to the names of the Provider.SharedBlockSchemas whose
+	// shared types are used for the blocks instead of generating new ones.
+	// The schemas of the blocks must be identical to the shared ones. Please
+	// note that the references, the renames and the other field
+	// configurations of the resource are not applied to the fields of the
+	// shared blocks.
+	SharedBlocks map[string]string
+
+	// DeprecatedFields maps the Terraform field paths, e.g. "rule.filter",
+	// to the deprecation configurations of the corresponding fields.
+	DeprecatedFields map[string]FieldDeprecation
+
+	// FieldMaturities maps the Terraform field paths, e.g. "rule.filter",
+	// to the maturity levels of the corresponding fields. The descriptions
+	// of the fields are prefixed with their maturity levels, e.g.
+	// "(Alpha) ...".
+	FieldMaturities map[string]FieldMaturity
+
+	// AlphaFieldVersions are the API versions in which the alpha fields are
+	// generated, e.g. an experimental "v1alpha2" version served along with
+	// the stable ones, so that the experimental upstream attributes can be
+	// shipped without changing the stable versions. The alpha fields are
+	// generated in all versions if empty.
+	AlphaFieldVersions []string
+
+	// DisableForceNewImmutability disables the validation rules generated
+	// for the top-level parameters marked as ForceNew in the Terraform
+	// schema, which reject the changes to those parameters instead of
+	// letting them silently replace the external resource.
+	DisableForceNewImmutability bool
+
+	// AllowReplacementWithAnnotation replaces the validation rules generated
+	// for the top-level ForceNew parameters with an admission webhook, which
+	// rejects the changes to all the ForceNew parameters, including the
+	// nested ones, unless the managed resource is annotated to allow the
+	// replacement of its external resource with the
+	// "upjet.upbound.io/allow-replacement" annotation set to "true".
+	AllowReplacementWithAnnotation bool
+
+	// TypeOverrides maps the Terraform field paths, e.g. "rule.max_size", to
+	// the Go types to be generated for the corresponding fields instead of
+	// the types inferred from their schemas. The numbers and booleans in the
+	// Terraform state of a field overridden with a string type are converted
+	// into strings while setting the observation or late-initializing the
+	// parameters.
+	TypeOverrides map[string]TypeOverride
+
+	// SchemaElementOptions maps the Terraform field paths, e.g.
+	// "rule.filter", to the overrides of the properties of the schemas of
+	// the corresponding fields.
+	SchemaElementOptions map[string]SchemaElementOption
+
+	// FieldValidators maps the Terraform paths of the spec fields, e.g.
+	// "name" or "rule.priority", to their admission-time validations.
+	FieldValidators map[string]FieldValidator
+
+	// ListMapKeys maps the Terraform paths of the list and set blocks of the
+	// spec, e.g. "rule", to the Terraform names of the required fields
+	// identifying their items, e.g. "name", which are generated as the list
+	// map keys of the blocks so that the server-side apply merges their
+	// items by the keys instead of replacing the blocks. The set blocks
+	// having a required "name" or "key" field are keyed by it if not
+	// configured.
+	ListMapKeys map[string][]string
+
+	// CompositionHints are emitted into the provider manifest for the
+	// composition and XRD tooling.
+	CompositionHints CompositionHints
+
+	// ProviderConfigOverrides are the arguments of the Terraform provider
+	// configuration, e.g. "region", that a managed resource can override
+	// via the generated spec.providerOverrides field to target a different
+	// configuration than its ProviderConfig, e.g. to manage the replica of a
+	// bucket in another region. The resource is then configured with an
+	// aliased provider block with the overridden arguments.
+	ProviderConfigOverrides []string
+
 	// MetaResource is the metadata associated with the resource scraped from
 	// the Terraform registry.
 	MetaResource *registry.Resource
@@ -319,4 +999,358 @@ type Resource struct {
 	// the plural name of the generated CRD. Overriding this sets both the
 	// path and the plural name for the generated CRD.
 	Path string
+
+	// Scope is the scope of the generated CRD. Defaults to ScopeCluster.
+	Scope Scope
+
+	// Categories are the CRD categories of the resource in addition to the
+	// default categories, i.e. "crossplane", "managed" and the short name of
+	// the provider.
+	Categories []string
+
+	// ShortNames are the short names of the CRD of the resource, e.g. "vpc".
+	ShortNames []string
+
+	// PrinterColumns are the printer columns of the CRD of the resource
+	// displayed by "kubectl get" in addition to the default columns. If
+	// not configured, the columns of the top-level state and location
+	// attributes of the resource, e.g. "status" and "region", are derived
+	// from its schema.
+	PrinterColumns []PrinterColumn
+
+	// DisableAutoPrinterColumns disables the printer columns derived from
+	// the schema of the resource if no PrinterColumns are configured.
+	DisableAutoPrinterColumns bool
+
+	// MaxBlockNestingDepth is the maximum nesting depth of the Terraform
+	// configuration blocks that are generated as typed fields. Blocks nested
+	// deeper than this are collapsed into runtime.RawExtension fields, which
+	// keeps the generated CRD schema within practical depth limits. The
+	// values of the collapsed blocks are converted to and from Terraform as
+	// is, i.e. with the Terraform field names, and their kinds and item
+	// limits are validated when the Terraform configuration of a managed
+	// resource is produced, as the CRD schemas of the collapsed fields have
+	// no types to validate with CEL rules. The top-level blocks of a
+	// resource have a depth of 1. The Terraform paths of the collapsed
+	// blocks are reported during code generation. Please note that
+	// sensitive fields and references under a collapsed block are not
+	// processed. Zero, the default, means no limit.
+	MaxBlockNestingDepth int
+
+	// SingletonListEmbedding configures the generation of the singleton
+	// list blocks as embedded objects.
+	SingletonListEmbedding SingletonListEmbedding
+
+	// DisableConstraintRules disables the CEL rules generated for the
+	// ExactlyOneOf, AtLeastOneOf, ConflictsWith and RequiredWith constraints
+	// among the arguments declared in the Terraform schema of the resource.
+	DisableConstraintRules bool
+
+	// CollapseObservationBlocks collapses the computed-only blocks, which
+	// are only observed, into runtime.RawExtension fields of the status
+	// instead of generating typed structs and their deepcopy functions for
+	// them. This reduces the size of the generated code of the resources
+	// with huge observation schemas. The values of the collapsed blocks are
+	// decoded from Terraform as is, i.e. with the Terraform field names, and
+	// they can be read with resource.GetCollapsedValue. The blocks with
+	// sensitive fields are not collapsed. The Terraform paths of the
+	// collapsed blocks are reported during code generation.
+	CollapseObservationBlocks bool
+
+	// ObservationPruning configures the observation fields pruned from the
+	// status of the managed resources.
+	ObservationPruning ObservationPruning
+
+	// Conditions are the custom status conditions of the managed resources,
+	// whose typed constants, constructors and setters are generated in the
+	// API version packages of the resource, e.g. the "VPCLastApplyFailed"
+	// condition type and the "SetLastApplyFailedCondition" method of VPC,
+	// so that they're not built from raw strings.
+	Conditions []Condition
+
+	// ParameterDefaults maps the top-level Terraform arguments to the values
+	// sent to Terraform when they are not set in the spec of a managed
+	// resource. Together with removing the arguments from the schema, they
+	// allow baking fixed values into the Kinds of the resource aliases.
+	ParameterDefaults map[string]any
+
+	// HiddenParameters maps the top-level Terraform arguments that are not
+	// user-facing, e.g. a feature flag required by the Terraform resource, to
+	// the values injected into the Terraform configuration by the controller.
+	// The hidden parameters are removed from the spec of the managed resource
+	// while they're still reported in its status.
+	HiddenParameters map[string]any
+
+	// SchemaDefaults enables generating the static defaults of the spec
+	// fields in the Terraform schema as the defaults of the CRD fields, so
+	// that the effective values are visible in the OpenAPI schema and set at
+	// admission instead of being late-initialized. The defaults of the
+	// sensitive and deprecated fields are not generated.
+	SchemaDefaults bool
+
+	// MovedTo is the configuration of the current Kind if this is the
+	// configuration of a MovedResource. It's set by the Provider.
+	MovedTo *Resource
+
+	// DocsURL is the URL of the documentation of the Terraform resource in
+	// the Terraform registry, e.g.
+	// "https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/s3_bucket".
+	// It's linked from the comments of the generated Kind and of the types
+	// of its blocks, whose sections are anchored with their names. It's
+	// set by the Provider if its TerraformProviderSource is configured.
+	DocsURL string
+
+	// DataSource is set by the Provider if this is the configuration of a
+	// Terraform data source, whose managed resources are read-only: the
+	// arguments of the data source are their parameters and its attributes
+	// are observed, e.g. to look up an existing network. Their Kinds are
+	// prefixed with "Data", e.g. "DataAMI", and they can only be observed.
+	DataSource bool
+
+	// aliasKind is the Kind of the alias if this is the configuration of a
+	// ResourceAlias.
+	aliasKind string
+}
+
+// Namespaced returns whether the CRD of the resource is namespaced.
+func (r *Resource) Namespaced() bool {
+	return r.Scope == ScopeNamespaced
+}
+
+// ApplySchemaElementOptions applies the SchemaElementOptions to the
+// Terraform schema of the resource. It returns an error if a configured
+// field does not exist or an option is not applicable to its type.
+func (r *Resource) ApplySchemaElementOptions() error { //nolint:gocyclo
+	paths := make([]string, 0, len(r.SchemaElementOptions))
+	for p := range r.SchemaElementOptions {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		o := r.SchemaElementOptions[p]
+		sch := GetSchema(r.TerraformResource, p)
+		if sch == nil {
+			return errors.Errorf("cannot find the schema element of the field %q", p)
+		}
+		isList := sch.Type == schema.TypeList || sch.Type == schema.TypeSet
+		if (o.MaxItems > 0 || o.MinItems > 0) && !isList {
+			return errors.Errorf("cannot set the item limits of the field %q of type %s", p, sch.Type)
+		}
+		if o.MaxItems > 0 {
+			sch.MaxItems = o.MaxItems
+		}
+		if o.MinItems > 0 {
+			sch.MinItems = o.MinItems
+		}
+		if o.ElemType == schema.TypeInvalid {
+			continue
+		}
+		if _, ok := sch.Elem.(*schema.Resource); ok || (!isList && sch.Type != schema.TypeMap) {
+			return errors.Errorf("cannot set the element type of the field %q, which is not a collection of primitive elements", p)
+		}
+		switch o.ElemType {
+		case schema.TypeBool, schema.TypeInt, schema.TypeFloat, schema.TypeString:
+			sch.Elem = &schema.Schema{Type: o.ElemType}
+		case schema.TypeInvalid, schema.TypeList, schema.TypeMap, schema.TypeSet:
+			return errors.Errorf("element type %s of the field %q is not a primitive type", o.ElemType, p)
+		}
+	}
+	return nil
+}
+
+// Key returns the key of the resource configuration in the Resources of the
+// Provider, i.e. the AliasKey for the aliases, the MovedKey for the moved
+// resources, the DataSourceKey for the data sources and the Terraform
+// resource name otherwise.
+func (r *Resource) Key() string {
+	if r.MovedTo != nil {
+		return MovedKey(r.Name, r.ShortGroup, r.Kind)
+	}
+	if r.aliasKind != "" {
+		return AliasKey(r.Name, r.aliasKind)
+	}
+	if r.DataSource {
+		return DataSourceKey(r.Name)
+	}
+	return r.Name
+}
+
+// asDataSource configures the resource as the read-only managed resource of
+// a Terraform data source, which can only be observed and has no external
+// name of its own.
+func (r *Resource) asDataSource() {
+	r.DataSource = true
+	r.Kind = "Data" + r.Kind
+	r.ExternalName = IdentifierFromProvider
+	r.UseAsync = false
+	r.ManagementPolicies = ManagementPolicies{
+		Supported: []xpv1.ManagementPolicies{{xpv1.ManagementActionObserve}},
+		Default:   xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+	}
+}
+
+// EmbeddedSingletonLists returns the Terraform paths, e.g.
+// "rule[*].filter", of the singleton list blocks that are embedded as
+// objects in the given API version of the resource. The paths are sorted.
+// The blocks collapsed into runtime.RawExtension fields and the blocks with
+// sensitive fields, whose connection details mappings require lists, are
+// excluded.
+func (r *Resource) EmbeddedSingletonLists(version string) []string {
+	e := r.SingletonListEmbedding
+	if !e.Enabled || r.TerraformResource == nil ||
+		(e.Since != "" && apiversion.CompareKubeAwareVersionStrings(version, e.Since) < 0) {
+		return nil
+	}
+	return r.singletonLists(r.TerraformResource, "", 1)
+}
+
+func (r *Resource) singletonLists(res *schema.Resource, prefix string, depth int) []string {
+	if r.MaxBlockNestingDepth > 0 && depth > r.MaxBlockNestingDepth {
+		return nil
+	}
+	keys := make([]string, 0, len(res.Schema))
+	for k := range res.Schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var paths []string
+	for _, k := range keys {
+		sch := res.Schema[k]
+		er, ok := sch.Elem.(*schema.Resource)
+		if !ok || (sch.Type != schema.TypeList && sch.Type != schema.TypeSet) {
+			continue
+		}
+		// the fields of a sensitive block are generated as sensitive.
+		if sch.Sensitive || r.CollapsedObservationBlock(sch) {
+			continue
+		}
+		p := prefix + k
+		if sch.MaxItems == 1 && !hasSensitiveField(er) {
+			paths = append(paths, p)
+		}
+		paths = append(paths, r.singletonLists(er, p+"[*].", depth+1)...)
+	}
+	return paths
+}
+
+// CollapsedBlocks returns the schemas of the blocks that are collapsed into
+// runtime.RawExtension fields as they're nested deeper than
+// MaxBlockNestingDepth, keyed by their Terraform paths, e.g.
+// "rule[*].filter".
+func (r *Resource) CollapsedBlocks() map[string]*schema.Schema {
+	if r.MaxBlockNestingDepth <= 0 || r.TerraformResource == nil {
+		return nil
+	}
+	blocks := map[string]*schema.Schema{}
+	r.collapsedBlocks(r.TerraformResource, "", 1, blocks)
+	return blocks
+}
+
+func (r *Resource) collapsedBlocks(res *schema.Resource, prefix string, depth int, blocks map[string]*schema.Schema) {
+	for k, sch := range res.Schema {
+		er, ok := sch.Elem.(*schema.Resource)
+		if !ok {
+			continue
+		}
+		if depth > r.MaxBlockNestingDepth {
+			blocks[prefix+k] = sch
+			continue
+		}
+		r.collapsedBlocks(er, prefix+k+"[*].", depth+1, blocks)
+	}
+}
+
+// SetFields returns the Terraform paths, e.g. "rule.tags", of the set fields
+// of the resource, whose elements are unordered. The paths are sorted.
+func (r *Resource) SetFields() []string {
+	if r.TerraformResource == nil {
+		return nil
+	}
+	return setFields(r.TerraformResource, "")
+}
+
+func setFields(res *schema.Resource, prefix string) []string {
+	keys := make([]string, 0, len(res.Schema))
+	for k := range res.Schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var paths []string
+	for _, k := range keys {
+		sch := res.Schema[k]
+		if sch.Type == schema.TypeSet {
+			paths = append(paths, prefix+k)
+		}
+		if er, ok := sch.Elem.(*schema.Resource); ok {
+			paths = append(paths, setFields(er, prefix+k+".")...)
+		}
+	}
+	return paths
+}
+
+// SingletonListConversions returns the conversions of the managed
+// resources between the API versions of the resource with and without the
+// embedded singleton lists.
+func (r *Resource) SingletonListConversions() []conversion.Conversion {
+	versions := append([]string{r.Version}, r.ServedVersions...)
+	var conversions []conversion.Conversion
+	for _, src := range versions {
+		if len(r.EmbeddedSingletonLists(src)) > 0 {
+			continue
+		}
+		for _, dst := range versions {
+			tfPaths := r.EmbeddedSingletonLists(dst)
+			if len(tfPaths) == 0 {
+				continue
+			}
+			paths := make([]string, 0, 2*len(tfPaths))
+			for _, p := range tfPaths {
+				cp := r.crdFieldPath(p)
+				paths = append(paths, "spec.forProvider."+cp, "status.atProvider."+cp)
+			}
+			conversions = append(conversions,
+				conversion.NewSingletonListAPIConversion(src, dst, paths, conversion.ToEmbeddedObject),
+				conversion.NewSingletonListAPIConversion(dst, src, paths, conversion.ToSingletonList))
+		}
+	}
+	return conversions
+}
+
+// crdFieldPath returns the path of the field with the given Terraform path,
+// e.g. "rule[*].filter", relative to the forProvider and atProvider objects,
+// e.g. "rule[*].filterRule".
+func (r *Resource) crdFieldPath(tfPath string) string {
+	segments := strings.Split(tfPath, ".")
+	names := make([]string, 0, len(segments))
+	crd := make([]string, len(segments))
+	for i, seg := range segments {
+		n := strings.TrimSuffix(seg, "[*]")
+		names = append(names, n)
+		cn := tjname.NewFromSnake(n).LowerCamelComputed
+		if rn, ok := r.FieldRenames[strings.Join(names, ".")]; ok {
+			cn = tjname.NewFromCamel(rn).LowerCamelComputed
+		}
+		crd[i] = cn + strings.TrimPrefix(seg, n)
+	}
+	return strings.Join(crd, ".")
+}
+
+// CollapsedObservationBlock reports whether the block with the given schema
+// is collapsed into a runtime.RawExtension field of the status as configured
+// by CollapseObservationBlocks.
+func (r *Resource) CollapsedObservationBlock(sch *schema.Schema) bool {
+	er, ok := sch.Elem.(*schema.Resource)
+	return ok && r.CollapseObservationBlocks && sch.Computed && !sch.Optional && !sch.Sensitive && !hasSensitiveField(er)
+}
+
+func hasSensitiveField(res *schema.Resource) bool {
+	for _, sch := range res.Schema {
+		if sch.Sensitive {
+			return true
+		}
+		if er, ok := sch.Elem.(*schema.Resource); ok && hasSensitiveField(er) {
+			return true
+		}
+	}
+	return false
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -109,3 +110,393 @@ func TestSetExternalTagsWithPaved(t *testing.T) {
 		})
 	}
 }
+
+func TestLateInitializerMatchIgnoredFields(t *testing.T) {
+	type args struct {
+		ignoredFields []string
+		tfPath        string
+	}
+	type want struct {
+		matched []string
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"ExactPath": {
+			args: args{
+				ignoredFields: []string{"block_device_mappings.ebs"},
+				tfPath:        "block_device_mappings.ebs",
+			},
+			want: want{
+				matched: []string{"block_device_mappings.ebs"},
+			},
+		},
+		"IndexWildcard": {
+			args: args{
+				ignoredFields: []string{"rule[*].filter", "rule[0].filter"},
+				tfPath:        "rule.filter",
+			},
+			want: want{
+				matched: []string{"rule[*].filter", "rule[0].filter"},
+			},
+		},
+		"SegmentGlob": {
+			args: args{
+				ignoredFields: []string{"rule.filter_*", "rule.*"},
+				tfPath:        "rule.filter_prefix",
+			},
+			want: want{
+				matched: []string{"rule.filter_*", "rule.*"},
+			},
+		},
+		"Prefix": {
+			args: args{
+				ignoredFields: []string{"rule.**"},
+				tfPath:        "rule",
+			},
+			want: want{
+				matched: []string{"rule.**"},
+			},
+		},
+		"AnyDepth": {
+			args: args{
+				ignoredFields: []string{"**.filter"},
+				tfPath:        "rule.nested.filter",
+			},
+			want: want{
+				matched: []string{"**.filter"},
+			},
+		},
+		"NoMatch": {
+			args: args{
+				ignoredFields: []string{"rule.*", "rule[*].filter", "[malformed"},
+				tfPath:        "rule.nested.filter",
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			l := &LateInitializer{IgnoredFields: tc.ignoredFields}
+			got := l.MatchIgnoredFields(tc.tfPath)
+			if diff := cmp.Diff(tc.want.matched, got); diff != "" {
+				t.Errorf("MatchIgnoredFields(...): -want matched, +got matched: %s", diff)
+			}
+		})
+	}
+}
+
+func TestEmbeddedSingletonLists(t *testing.T) {
+	block := func(maxItems int, s map[string]*schema.Schema) *schema.Schema {
+		return &schema.Schema{Type: schema.TypeList, Optional: true, MaxItems: maxItems, Elem: &schema.Resource{Schema: s}}
+	}
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Required: true},
+			"rule": block(1, map[string]*schema.Schema{
+				"filter": block(1, map[string]*schema.Schema{
+					"prefix": {Type: schema.TypeString, Optional: true},
+				}),
+				"tags": {Type: schema.TypeList, Optional: true, MaxItems: 1, Elem: &schema.Schema{Type: schema.TypeString}},
+			}),
+			"target": block(0, map[string]*schema.Schema{
+				"options": block(1, map[string]*schema.Schema{
+					"size": {Type: schema.TypeInt, Optional: true},
+				}),
+			}),
+			"credentials": block(1, map[string]*schema.Schema{
+				"password": {Type: schema.TypeString, Optional: true, Sensitive: true},
+			}),
+		},
+	}
+	type args struct {
+		embedding SingletonListEmbedding
+		maxDepth  int
+		version   string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want []string
+	}{
+		"Disabled": {
+			reason: "No singleton lists should be embedded if the embedding is not enabled.",
+			args: args{
+				version: "v1beta1",
+			},
+		},
+		"Enabled": {
+			reason: "The singleton list blocks without sensitive fields should be embedded at all depths.",
+			args: args{
+				embedding: SingletonListEmbedding{Enabled: true},
+				version:   "v1beta1",
+			},
+			want: []string{"rule", "rule[*].filter", "target[*].options"},
+		},
+		"BeforeSince": {
+			reason: "No singleton lists should be embedded in the API versions before the configured one.",
+			args: args{
+				embedding: SingletonListEmbedding{Enabled: true, Since: "v1beta2"},
+				version:   "v1beta1",
+			},
+		},
+		"Since": {
+			reason: "The singleton lists should be embedded starting with the configured API version.",
+			args: args{
+				embedding: SingletonListEmbedding{Enabled: true, Since: "v1beta2"},
+				version:   "v1",
+			},
+			want: []string{"rule", "rule[*].filter", "target[*].options"},
+		},
+		"Collapsed": {
+			reason: "The blocks collapsed into runtime.RawExtension fields should not be embedded.",
+			args: args{
+				embedding: SingletonListEmbedding{Enabled: true},
+				maxDepth:  1,
+				version:   "v1beta1",
+			},
+			want: []string{"rule"},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			r := &Resource{
+				TerraformResource:      res,
+				SingletonListEmbedding: tc.embedding,
+				MaxBlockNestingDepth:   tc.maxDepth,
+			}
+			got := r.EmbeddedSingletonLists(tc.version)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nEmbeddedSingletonLists(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCRDFieldPath(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		renames map[string]string
+		tfPath  string
+		want    string
+	}{
+		"Nested": {
+			reason: "The Terraform field names should be converted into lower camel case keeping the wildcards.",
+			tfPath: "lifecycle_rule[*].noncurrent_version",
+			want:   "lifecycleRule[*].noncurrentVersion",
+		},
+		"Renamed": {
+			reason:  "The renamed fields should have their configured names.",
+			renames: map[string]string{"lifecycle_rule.noncurrent_version": "oldVersion"},
+			tfPath:  "lifecycle_rule[*].noncurrent_version",
+			want:    "lifecycleRule[*].oldVersion",
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			r := &Resource{FieldRenames: tc.renames}
+			got := r.crdFieldPath(tc.tfPath)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ncrdFieldPath(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMapNormalizationNormalize(t *testing.T) {
+	cases := map[string]struct {
+		reason        string
+		normalization MapNormalization
+		m             map[string]any
+		want          map[string]any
+	}{
+		"NoNormalization": {
+			reason: "The map should be copied as is if no normalization is configured.",
+			m:      map[string]any{"Env": "prod"},
+			want:   map[string]any{"Env": "prod"},
+		},
+		"LowercaseKeys": {
+			reason:        "The keys should be converted into lower case.",
+			normalization: MapNormalization{LowercaseKeys: true},
+			m:             map[string]any{"Env": "Prod"},
+			want:          map[string]any{"env": "Prod"},
+		},
+		"IgnoredKeys": {
+			reason:        "The ignored keys and the keys with the ignored prefixes should be dropped.",
+			normalization: MapNormalization{IgnoredKeys: []string{"owner", "aws:*"}},
+			m:             map[string]any{"env": "prod", "owner": "a", "aws:stack": "s"},
+			want:          map[string]any{"env": "prod"},
+		},
+		"DefaultEntries": {
+			reason:        "The default entries should be added unless the map has their keys.",
+			normalization: MapNormalization{LowercaseKeys: true, DefaultEntries: map[string]string{"Env": "dev", "team": "a"}},
+			m:             map[string]any{"ENV": "prod"},
+			want:          map[string]any{"env": "prod", "team": "a"},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			got := tc.normalization.Normalize(tc.m)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nNormalize(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObservationPruningPruned(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		pruning ObservationPruning
+		tfPath  string
+		want    bool
+	}{
+		"NoPruning": {
+			reason: "No field should be pruned if no pruning is configured.",
+			tfPath: "rule.filter",
+		},
+		"Excluded": {
+			reason:  "An excluded field should be pruned.",
+			pruning: ObservationPruning{ExcludedFields: []string{"rule[*].filter"}},
+			tfPath:  "rule.filter",
+			want:    true,
+		},
+		"ExcludedSubtree": {
+			reason:  "The nested fields of an excluded field should be pruned.",
+			pruning: ObservationPruning{ExcludedFields: []string{"rule"}},
+			tfPath:  "rule.filter.prefix",
+			want:    true,
+		},
+		"ExcludedSiblingPrefix": {
+			reason:  "A field whose name starts with the name of an excluded field should not be pruned.",
+			pruning: ObservationPruning{ExcludedFields: []string{"rule"}},
+			tfPath:  "rules",
+		},
+		"IncludedAncestor": {
+			reason:  "The ancestors of an included field should be kept.",
+			pruning: ObservationPruning{IncludedFields: []string{"rule.filter"}},
+			tfPath:  "rule",
+		},
+		"IncludedSubtree": {
+			reason:  "The nested fields of an included field should be kept.",
+			pruning: ObservationPruning{IncludedFields: []string{"rule"}},
+			tfPath:  "rule.filter",
+		},
+		"NotIncluded": {
+			reason:  "A field that is not included should be pruned.",
+			pruning: ObservationPruning{IncludedFields: []string{"rule.filter"}},
+			tfPath:  "rule.status",
+			want:    true,
+		},
+		"IncludedAndExcluded": {
+			reason:  "An excluded field should be pruned even if it is included.",
+			pruning: ObservationPruning{IncludedFields: []string{"rule"}, ExcludedFields: []string{"rule.filter"}},
+			tfPath:  "rule.filter",
+			want:    true,
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.pruning.Pruned(tc.tfPath)); diff != "" {
+				t.Errorf("\n%s\nPruned(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplySchemaElementOptions(t *testing.T) {
+	newResource := func() *schema.Resource {
+		return &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name":  {Type: schema.TypeString, Optional: true},
+				"ports": {Type: schema.TypeList, Optional: true},
+				"rule": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"filter": {Type: schema.TypeSet, Optional: true},
+						},
+					},
+				},
+			},
+		}
+	}
+	type want struct {
+		maxItems int
+		minItems int
+		elem     any
+		err      string
+	}
+	cases := map[string]struct {
+		reason  string
+		path    string
+		options SchemaElementOption
+		want    want
+	}{
+		"ItemLimits": {
+			reason:  "The item limits of a nested list should be set.",
+			path:    "rule.filter",
+			options: SchemaElementOption{MinItems: 1, MaxItems: 1},
+			want: want{
+				maxItems: 1,
+				minItems: 1,
+			},
+		},
+		"ElemType": {
+			reason:  "The element type of a list of primitive elements should be set.",
+			path:    "ports",
+			options: SchemaElementOption{ElemType: schema.TypeInt},
+			want: want{
+				elem: &schema.Schema{Type: schema.TypeInt},
+			},
+		},
+		"NotFound": {
+			reason:  "An error should be returned if the field does not exist.",
+			path:    "rule.action",
+			options: SchemaElementOption{MaxItems: 1},
+			want: want{
+				err: `cannot find the schema element of the field "rule.action"`,
+			},
+		},
+		"NotList": {
+			reason:  "An error should be returned if the item limits are set for a field which is not a list.",
+			path:    "name",
+			options: SchemaElementOption{MaxItems: 1},
+			want: want{
+				err: `cannot set the item limits of the field "name" of type TypeString`,
+			},
+		},
+		"Block": {
+			reason:  "An error should be returned if the element type of a block is set.",
+			path:    "rule",
+			options: SchemaElementOption{ElemType: schema.TypeString},
+			want: want{
+				err: `cannot set the element type of the field "rule", which is not a collection of primitive elements`,
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			r := &Resource{
+				TerraformResource:    newResource(),
+				SchemaElementOptions: map[string]SchemaElementOption{tc.path: tc.options},
+			}
+			err := ""
+			if e := r.ApplySchemaElementOptions(); e != nil {
+				err = e.Error()
+			}
+			if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Fatalf("\n%s\nApplySchemaElementOptions(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != "" {
+				return
+			}
+			sch := GetSchema(r.TerraformResource, tc.path)
+			got := want{maxItems: sch.MaxItems, minItems: sch.MinItems, elem: sch.Elem}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nApplySchemaElementOptions(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/resource"
+	"github.com/upbound/upjet/pkg/terraform"
+)
+
+const (
+	errFmtAdmissionValidation = "admission validation at index %d failed"
+	warnFmtSkippedValidations = "the admission validations are skipped as the Terraform provider configuration cannot be built: %s"
+)
+
+// AdmissionValidator is an admission validator running the
+// AdmissionValidations of a resource configuration with the Terraform
+// parameters of the managed resources and the Terraform provider
+// configurations built from their ProviderConfigs.
+type AdmissionValidator struct {
+	kube    client.Client
+	setupFn terraform.SetupFn
+	config  *config.Resource
+}
+
+// NewAdmissionValidator returns a new AdmissionValidator for the managed
+// resources of the given resource configuration, whose Terraform provider
+// configurations are built with the given client and setup function.
+func NewAdmissionValidator(kube client.Client, setupFn terraform.SetupFn, cfg *config.Resource) *AdmissionValidator {
+	return &AdmissionValidator{
+		kube:    kube,
+		setupFn: setupFn,
+		config:  cfg,
+	}
+}
+
+// ValidateCreate runs the admission validations for the created object.
+func (v *AdmissionValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, obj)
+}
+
+// ValidateUpdate runs the admission validations for the updated object.
+func (v *AdmissionValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, newObj)
+}
+
+// ValidateDelete does nothing.
+func (v *AdmissionValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate runs the admission validations in order and returns their
+// warnings together with the first error returned. The validations are
+// skipped with a warning if the Terraform provider configuration cannot be
+// built, e.g. as the ProviderConfig is not created yet, so that the managed
+// resources can be created together with their ProviderConfigs.
+func (v *AdmissionValidator) validate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	tr, ok := obj.(resource.Terraformed)
+	if !ok {
+		return nil, errors.New(errUnexpectedObject)
+	}
+	params, err := tr.GetParameters()
+	if err != nil {
+		return nil, errors.Wrap(err, errGetParameters)
+	}
+	ts, err := v.setupFn(ctx, v.kube, tr)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf(warnFmtSkippedValidations, err.Error())}, nil
+	}
+	var warnings admission.Warnings
+	for i, fn := range v.config.AdmissionValidations {
+		w, err := fn(ctx, tr, params, ts.Configuration)
+		warnings = append(warnings, w...)
+		if err != nil {
+			return warnings, errors.Wrapf(err, errFmtAdmissionValidation, i)
+		}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/resource/fake"
+	"github.com/upbound/upjet/pkg/terraform"
+)
+
+func TestAdmissionValidator(t *testing.T) {
+	errBoom := errors.New("boom")
+	setupFn := func(_ context.Context, _ client.Client, _ xpresource.Managed) (terraform.Setup, error) {
+		return terraform.Setup{Configuration: map[string]any{"region": "us-east-1"}}, nil
+	}
+	regionRequired := func(_ context.Context, _ xpresource.Managed, parameters, providerConfig map[string]any) ([]string, error) {
+		if parameters["region"] == nil && providerConfig["region"] == nil {
+			return nil, errors.New("region is required")
+		}
+		return nil, nil
+	}
+	type args struct {
+		setupFn     terraform.SetupFn
+		validations []config.AdmissionValidationFn
+		params      map[string]any
+	}
+	type want struct {
+		warnings admission.Warnings
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoValidations": {
+			reason: "No warnings or errors should be returned if there are no validations.",
+			args: args{
+				setupFn: setupFn,
+			},
+		},
+		"ProviderConfiguration": {
+			reason: "The validations should be run with the Terraform provider configuration.",
+			args: args{
+				setupFn:     setupFn,
+				validations: []config.AdmissionValidationFn{regionRequired},
+			},
+		},
+		"Warnings": {
+			reason: "The warnings of all the validations should be returned.",
+			args: args{
+				setupFn: setupFn,
+				validations: []config.AdmissionValidationFn{
+					func(_ context.Context, _ xpresource.Managed, _, _ map[string]any) ([]string, error) {
+						return []string{"a"}, nil
+					},
+					func(_ context.Context, _ xpresource.Managed, _, _ map[string]any) ([]string, error) {
+						return []string{"b"}, nil
+					},
+				},
+			},
+			want: want{
+				warnings: admission.Warnings{"a", "b"},
+			},
+		},
+		"ValidationError": {
+			reason: "The first error returned from the validations should be returned and the subsequent validations should not be run.",
+			args: args{
+				setupFn: setupFn,
+				validations: []config.AdmissionValidationFn{
+					func(_ context.Context, _ xpresource.Managed, _, _ map[string]any) ([]string, error) {
+						return []string{"a"}, errBoom
+					},
+					func(_ context.Context, _ xpresource.Managed, _, _ map[string]any) ([]string, error) {
+						return []string{"b"}, nil
+					},
+				},
+				params: map[string]any{"name": "test"},
+			},
+			want: want{
+				warnings: admission.Warnings{"a"},
+				err:      errors.Wrapf(errBoom, errFmtAdmissionValidation, 0),
+			},
+		},
+		"SetupFailed": {
+			reason: "The validations should be skipped with a warning if the Terraform provider configuration cannot be built.",
+			args: args{
+				setupFn: func(_ context.Context, _ client.Client, _ xpresource.Managed) (terraform.Setup, error) {
+					return terraform.Setup{}, errBoom
+				},
+				validations: []config.AdmissionValidationFn{regionRequired},
+			},
+			want: want{
+				warnings: admission.Warnings{fmt.Sprintf(warnFmtSkippedValidations, errBoom.Error())},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := &config.Resource{AdmissionValidations: tc.args.validations}
+			obj := &fake.Terraformed{Parameterizable: fake.Parameterizable{Parameters: tc.args.params}}
+			warnings, err := NewAdmissionValidator(nil, tc.args.setupFn, cfg).ValidateCreate(context.TODO(), obj)
+			if diff := cmp.Diff(tc.want.warnings, warnings); diff != "" {
+				t.Errorf("\n%s\nValidateCreate(...): -want warnings, +got warnings:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateCreate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/resource"
+	"github.com/upbound/upjet/pkg/terraform"
+)
+
+const (
+	errFmtAdoptNonExistent = "cannot adopt the external resource with the identifier %q as it does not exist"
+)
+
+// adoptionPending reports whether the external resource of the given managed
+// resource should be adopted, i.e. its provider-assigned identifier has been
+// set as the external name before the managed resource has been created or
+// observed.
+func (e *external) adoptionPending(tr resource.Terraformed) bool {
+	if !e.config.ExternalName.AdoptOnExternalName || meta.GetExternalName(tr) == "" {
+		return false
+	}
+	if !meta.GetExternalCreatePending(tr).IsZero() || !meta.GetExternalCreateSucceeded(tr).IsZero() {
+		return false
+	}
+	// the private attributes are recorded once the external resource has
+	// been observed, after which it is refreshed as usual.
+	_, observed := tr.GetAnnotations()[resource.AnnotationKeyPrivateRawAttribute]
+	return !observed
+}
+
+// adopt imports the external resource with the external name of the given
+// managed resource. It returns an error if there is no such external
+// resource so that a new one is not created in its place.
+func (e *external) adopt(ctx context.Context, tr resource.Terraformed) (terraform.RefreshResult, error) {
+	res, err := e.workspace.Import(ctx, tr)
+	if err != nil {
+		return terraform.RefreshResult{}, errors.Wrap(err, errImport)
+	}
+	if !res.ASyncInProgress && !res.Exists {
+		return terraform.RefreshResult{}, errors.Errorf(errFmtAdoptNonExistent, meta.GetExternalName(tr))
+	}
+	return terraform.RefreshResult(res), nil
+}
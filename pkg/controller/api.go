@@ -39,12 +39,20 @@ const (
 // APISecretClient is a client for getting k8s secrets
 type APISecretClient struct {
 	kube client.Client
+	// namespace, if set, is the namespace the secrets are read from
+	// regardless of the namespaces of the references, which confines the
+	// secret references of a namespaced managed resource to its namespace.
+	namespace string
 }
 
 // GetSecretData gets and returns data for the referenced secret
 func (a *APISecretClient) GetSecretData(ctx context.Context, ref *xpv1.SecretReference) (map[string][]byte, error) {
 	secret := &v1.Secret{}
-	if err := a.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+	ns := ref.Namespace
+	if a.namespace != "" {
+		ns = a.namespace
+	}
+	if err := a.kube.Get(ctx, types.NamespacedName{Namespace: ns, Name: ref.Name}, secret); err != nil {
 		return nil, err
 	}
 	return secret.Data, nil
@@ -77,9 +85,8 @@ type APICallbacks struct {
 }
 
 // Apply makes sure the error is saved in async operation condition.
-func (ac *APICallbacks) Apply(name string) terraform.CallbackFn {
+func (ac *APICallbacks) Apply(nn types.NamespacedName) terraform.CallbackFn {
 	return func(err error, ctx context.Context) error {
-		nn := types.NamespacedName{Name: name}
 		tr := ac.newTerraformed()
 		if kErr := ac.kube.Get(ctx, nn, tr); kErr != nil {
 			return errors.Wrap(kErr, errGet)
@@ -91,9 +98,8 @@ func (ac *APICallbacks) Apply(name string) terraform.CallbackFn {
 }
 
 // Destroy makes sure the error is saved in async operation condition.
-func (ac *APICallbacks) Destroy(name string) terraform.CallbackFn {
+func (ac *APICallbacks) Destroy(nn types.NamespacedName) terraform.CallbackFn {
 	return func(err error, ctx context.Context) error {
-		nn := types.NamespacedName{Name: name}
 		tr := ac.newTerraformed()
 		if kErr := ac.kube.Get(ctx, nn, tr); kErr != nil {
 			return errors.Wrap(kErr, errGet)
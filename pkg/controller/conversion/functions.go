@@ -0,0 +1,148 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+// Package conversion contains the functions used by the generated conversion
+// methods of the managed resources served in multiple API versions.
+package conversion
+
+import (
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/config/conversion"
+	"github.com/upbound/upjet/pkg/resource/json"
+)
+
+const (
+	errMarshalSrc   = "cannot marshal the conversion source object"
+	errUnmarshalDst = "cannot unmarshal the conversion destination object"
+	errToPaved      = "cannot pave the conversion %s object"
+	errFromPaved    = "cannot convert the conversion destination object from its paved representation"
+	errConvertPaved = "cannot apply the paved conversion"
+	errNotManaged   = "the conversion source and destination objects must be managed resources"
+	errConvert      = "cannot apply the managed conversion"
+)
+
+var registry *config.Provider
+
+// RegisterConversions registers the API version conversions of the
+// resources of the given provider, which are applied by RoundTrip. It must
+// be called before the conversion webhooks are started.
+func RegisterConversions(pc *config.Provider) {
+	registry = pc
+}
+
+// terraformResourceTyper is implemented by the Terraformed resources.
+type terraformResourceTyper interface {
+	GetTerraformResourceType() string
+}
+
+func getConversions(src runtime.Object, kind string) []conversion.Conversion {
+	tr, ok := src.(terraformResourceTyper)
+	if registry == nil || !ok {
+		return nil
+	}
+	r, ok := registry.GetResource(tr.GetTerraformResourceType(), kind)
+	if !ok {
+		return nil
+	}
+	// the conversions of the embedded singleton lists are applied before
+	// the resource's own conversions, and the fallback conversions of the
+	// deprecated fields after them
+	conversions := append(r.SingletonListConversions(), r.Conversions...)
+	paths := make([]string, 0, len(r.DeprecatedFields))
+	for p := range r.DeprecatedFields {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if f := r.DeprecatedFields[p].Fallback; f != nil {
+			conversions = append(conversions, f)
+		}
+	}
+	return conversions
+}
+
+// RoundTrip converts the src object into the dst object through their JSON
+// representations and sets the GroupVersionKind of dst to the given one. The
+// fields of src that do not exist in dst are dropped. The registered
+// conversions applicable for the API versions of src and dst are applied
+// in the process: the paved conversions on the JSON representation before
+// it is decoded into dst, so that they can also convert the fields whose
+// types differ between the API versions, and then the managed conversions.
+func RoundTrip(dst, src runtime.Object, gvk schema.GroupVersionKind) error { //nolint:gocyclo
+	dst.GetObjectKind().SetGroupVersionKind(gvk)
+	var paved []conversion.PavedConversion
+	var managed []conversion.ManagedConversion
+	for _, c := range getConversions(src, gvk.Kind) {
+		if !c.Applicable(src, dst) {
+			continue
+		}
+		switch cv := c.(type) {
+		case conversion.PavedConversion:
+			paved = append(paved, cv)
+		case conversion.ManagedConversion:
+			managed = append(managed, cv)
+		}
+	}
+	srcPaved, err := toPaved(src)
+	if err != nil {
+		return errors.Wrap(err, errMarshalSrc)
+	}
+	if len(paved) > 0 {
+		// the target of the paved conversions is a copy of src as dst is
+		// decoded from it
+		dstPaved, err := toPaved(src)
+		if err != nil {
+			return errors.Wrapf(err, errToPaved, "destination")
+		}
+		for _, c := range paved {
+			if _, err := c.ConvertPaved(srcPaved, dstPaved); err != nil {
+				return errors.Wrap(err, errConvertPaved)
+			}
+		}
+		srcPaved = dstPaved
+	}
+	buff, err := json.JSParser.Marshal(srcPaved.UnstructuredContent())
+	if err != nil {
+		return errors.Wrap(err, errFromPaved)
+	}
+	if err := json.JSParser.Unmarshal(buff, dst); err != nil {
+		return errors.Wrap(err, errUnmarshalDst)
+	}
+	dst.GetObjectKind().SetGroupVersionKind(gvk)
+	if len(managed) == 0 {
+		return nil
+	}
+	srcManaged, srcOK := src.(xpresource.Managed)
+	dstManaged, dstOK := dst.(xpresource.Managed)
+	if !srcOK || !dstOK {
+		return errors.New(errNotManaged)
+	}
+	for _, c := range managed {
+		if _, err := c.ConvertManaged(srcManaged, dstManaged); err != nil {
+			return errors.Wrap(err, errConvert)
+		}
+	}
+	return nil
+}
+
+// toPaved returns the paved JSON representation of the given object.
+func toPaved(o runtime.Object) (*fieldpath.Paved, error) {
+	buff, err := json.JSParser.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]any{}
+	if err := json.JSParser.Unmarshal(buff, &m); err != nil {
+		return nil, err
+	}
+	return fieldpath.Pave(m), nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package conversion
+
+import (
+	"testing"
+
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/config/conversion"
+	"github.com/upbound/upjet/pkg/resource/fake"
+)
+
+var errBoom = errors.New("boom")
+
+func TestRoundTrip(t *testing.T) {
+	type args struct {
+		src *unstructured.Unstructured
+		gvk schema.GroupVersionKind
+	}
+	type want struct {
+		dst *unstructured.Unstructured
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "The source object should be copied into the destination object with the given GroupVersionKind.",
+			args: args{
+				src: &unstructured.Unstructured{
+					Object: map[string]any{
+						"apiVersion": "ec2.aws.upbound.io/v1beta1",
+						"kind":       "VPC",
+						"spec": map[string]any{
+							"forProvider": map[string]any{
+								"region": "us-west-1",
+							},
+						},
+					},
+				},
+				gvk: schema.GroupVersionKind{Group: "ec2.aws.upbound.io", Version: "v1beta2", Kind: "VPC"},
+			},
+			want: want{
+				dst: &unstructured.Unstructured{
+					Object: map[string]any{
+						"apiVersion": "ec2.aws.upbound.io/v1beta2",
+						"kind":       "VPC",
+						"spec": map[string]any{
+							"forProvider": map[string]any{
+								"region": "us-west-1",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dst := &unstructured.Unstructured{}
+			err := RoundTrip(dst, tc.args.src, tc.args.gvk)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nRoundTrip(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.dst, dst); diff != "" {
+				t.Errorf("\n%s\nRoundTrip(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRoundTripConversions(t *testing.T) {
+	type want struct {
+		params      map[string]any
+		annotations map[string]string
+		err         error
+	}
+	cases := map[string]struct {
+		reason           string
+		conversions      []conversion.Conversion
+		deprecatedFields map[string]config.FieldDeprecation
+		want             want
+	}{
+		"NoConversions": {
+			reason: "The source object should be copied into the destination object if there are no registered conversions.",
+			want: want{
+				params: map[string]any{"a": "x"},
+			},
+		},
+		"FieldRename": {
+			reason: "A renamed field should be copied into the destination object.",
+			conversions: []conversion.Conversion{
+				conversion.NewFieldRenameConversion(conversion.AllVersions, "Parameters.a", conversion.AllVersions, "Parameters.b"),
+			},
+			want: want{
+				params: map[string]any{"a": "x", "b": "x"},
+			},
+		},
+		"CustomConverter": {
+			reason: "A custom converter should be applied to the destination object.",
+			conversions: []conversion.Conversion{
+				conversion.NewCustomConverter(conversion.AllVersions, conversion.AllVersions, func(_, target xpresource.Managed) error {
+					target.SetAnnotations(map[string]string{"converted": "true"})
+					return nil
+				}),
+			},
+			want: want{
+				params:      map[string]any{"a": "x"},
+				annotations: map[string]string{"converted": "true"},
+			},
+		},
+		"DeprecatedFieldFallback": {
+			reason: "The fallback conversions of the deprecated fields should be applied along with the resource's conversions.",
+			conversions: []conversion.Conversion{
+				conversion.NewFieldRenameConversion(conversion.AllVersions, "Parameters.a", conversion.AllVersions, "Parameters.b"),
+			},
+			deprecatedFields: map[string]config.FieldDeprecation{
+				"a": {
+					Fallback: conversion.NewFieldRenameConversion(conversion.AllVersions, "Parameters.a", conversion.AllVersions, "Parameters.c"),
+				},
+				"d": {},
+			},
+			want: want{
+				params: map[string]any{"a": "x", "b": "x", "c": "x"},
+			},
+		},
+		"CustomConverterError": {
+			reason: "An error returned by a custom converter should be reported.",
+			conversions: []conversion.Conversion{
+				conversion.NewCustomConverter(conversion.AllVersions, conversion.AllVersions, func(_, _ xpresource.Managed) error {
+					return errBoom
+				}),
+			},
+			want: want{
+				params: map[string]any{"a": "x"},
+				err:    errors.Wrap(errBoom, errConvert),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := config.DefaultResource("test_resource", nil, nil)
+			r.Conversions = tc.conversions
+			r.DeprecatedFields = tc.deprecatedFields
+			RegisterConversions(&config.Provider{Resources: map[string]*config.Resource{"test_resource": r}})
+			defer RegisterConversions(nil)
+			src := &fake.Terraformed{
+				MetadataProvider: fake.MetadataProvider{Type: "test_resource"},
+				Parameterizable:  fake.Parameterizable{Parameters: map[string]any{"a": "x"}},
+			}
+			dst := &fake.Terraformed{}
+			err := RoundTrip(dst, src, schema.GroupVersionKind{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nRoundTrip(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.params, dst.Parameters); diff != "" {
+				t.Errorf("\n%s\nRoundTrip(...): -want parameters, +got parameters:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.annotations, dst.GetAnnotations()); diff != "" {
+				t.Errorf("\n%s\nRoundTrip(...): -want annotations, +got annotations:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/types/name"
+)
+
+const (
+	errPaveObject = "cannot pave the object"
+)
+
+// DeprecationValidator is an admission validator warning about the
+// deprecated fields set in the managed resources.
+type DeprecationValidator struct {
+	config *config.Resource
+}
+
+// NewDeprecationValidator returns a new DeprecationValidator for the managed
+// resources of the given resource configuration.
+func NewDeprecationValidator(cfg *config.Resource) *DeprecationValidator {
+	return &DeprecationValidator{
+		config: cfg,
+	}
+}
+
+// ValidateCreate warns about the deprecated fields set in the created object.
+func (v *DeprecationValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.warnings(obj)
+}
+
+// ValidateUpdate warns about the deprecated fields set in the updated object.
+func (v *DeprecationValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return v.warnings(newObj)
+}
+
+// ValidateDelete does nothing.
+func (v *DeprecationValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *DeprecationValidator) warnings(obj runtime.Object) (admission.Warnings, error) {
+	if len(v.config.DeprecatedFields) == 0 {
+		return nil, nil
+	}
+	pv, err := fieldpath.PaveObject(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, errPaveObject)
+	}
+	tfPaths := make([]string, 0, len(v.config.DeprecatedFields))
+	for p := range v.config.DeprecatedFields {
+		tfPaths = append(tfPaths, p)
+	}
+	sort.Strings(tfPaths)
+	var warnings admission.Warnings
+	for _, p := range tfPaths {
+		crdPath, ok := parameterPath(v.config, p)
+		if !ok {
+			continue
+		}
+		paths, err := pv.ExpandWildcards(crdPath)
+		if err != nil {
+			continue
+		}
+		for _, cp := range paths {
+			if _, err := pv.GetValue(cp); err == nil {
+				warnings = append(warnings, fmt.Sprintf("%s: %s", cp, v.config.DeprecatedFields[p].Notice()))
+			}
+		}
+	}
+	return warnings, nil
+}
+
+// parameterPath returns the path of the parameter field with the given
+// Terraform field path in the managed resources of the given resource
+// configuration, or false if there is no such parameter field.
+func parameterPath(cfg *config.Resource, tfPath string) (string, bool) {
+	if cfg.TerraformResource == nil {
+		return "", false
+	}
+	res := cfg.TerraformResource
+	embedded := map[string]struct{}{}
+	for _, p := range cfg.EmbeddedSingletonLists(cfg.Version) {
+		embedded[p] = struct{}{}
+	}
+	segments := strings.Split(tfPath, ".")
+	crdPath := "spec.forProvider"
+	// wildcardPath is the Terraform path of the current segment with the
+	// wildcards of the list blocks, e.g. "rule[*].filter".
+	wildcardPath := ""
+	for i, seg := range segments {
+		if res == nil {
+			return "", false
+		}
+		sch, ok := res.Schema[seg]
+		if !ok || (!sch.Optional && !sch.Required) {
+			return "", false
+		}
+		n := name.NewFromSnake(seg).LowerCamelComputed
+		if rn, ok := cfg.FieldRenames[strings.Join(segments[:i+1], ".")]; ok {
+			n = name.NewFromCamel(rn).LowerCamelComputed
+		}
+		crdPath += "." + n
+		wildcardPath += seg
+		res = nil
+		if er, ok := sch.Elem.(*schema.Resource); ok {
+			res = er
+			if sch.Type == schema.TypeList || sch.Type == schema.TypeSet {
+				if _, ok := embedded[wildcardPath]; !ok {
+					crdPath += "[*]"
+				}
+				wildcardPath += "[*]"
+			}
+			wildcardPath += "."
+		}
+	}
+	return crdPath, true
+}
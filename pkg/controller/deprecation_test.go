@@ -0,0 +1,141 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+func TestDeprecationValidator(t *testing.T) {
+	type args struct {
+		deprecatedFields map[string]config.FieldDeprecation
+		fieldRenames     map[string]string
+		forProvider      map[string]any
+	}
+	type want struct {
+		warnings admission.Warnings
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoDeprecatedFields": {
+			reason: "No warnings should be returned if there are no deprecated fields.",
+			args: args{
+				forProvider: map[string]any{"instanceType": "small"},
+			},
+		},
+		"DeprecatedFieldNotSet": {
+			reason: "No warnings should be returned if the deprecated fields are not set.",
+			args: args{
+				deprecatedFields: map[string]config.FieldDeprecation{
+					"instance_type": {},
+				},
+				forProvider: map[string]any{"name": "test"},
+			},
+		},
+		"DeprecatedFieldsSet": {
+			reason: "A warning should be returned for each deprecated field set, including the nested ones.",
+			args: args{
+				deprecatedFields: map[string]config.FieldDeprecation{
+					"instance_type": {
+						Message:   "Use instanceTypes instead.",
+						RemovedIn: "v1beta2",
+					},
+					"rule.filter": {},
+				},
+				forProvider: map[string]any{
+					"instanceType": "small",
+					"rule": []any{
+						map[string]any{"filter": "a"},
+						map[string]any{"priority": 1},
+						map[string]any{"filter": "b"},
+					},
+				},
+			},
+			want: want{
+				warnings: admission.Warnings{
+					"spec.forProvider.instanceType: This field is deprecated and will be removed in v1beta2. Use instanceTypes instead.",
+					"spec.forProvider.rule[0].filter: This field is deprecated.",
+					"spec.forProvider.rule[2].filter: This field is deprecated.",
+				},
+			},
+		},
+		"RenamedDeprecatedField": {
+			reason: "The warning for a renamed deprecated field should refer to its renamed path.",
+			args: args{
+				deprecatedFields: map[string]config.FieldDeprecation{
+					"instance_type": {},
+				},
+				fieldRenames: map[string]string{
+					"instance_type": "size",
+				},
+				forProvider: map[string]any{"size": "small"},
+			},
+			want: want{
+				warnings: admission.Warnings{
+					"spec.forProvider.size: This field is deprecated.",
+				},
+			},
+		},
+		"ObservationOnlyField": {
+			reason: "No warnings should be returned for the deprecated fields which are not parameters.",
+			args: args{
+				deprecatedFields: map[string]config.FieldDeprecation{
+					"arn": {},
+				},
+				forProvider: map[string]any{"arn": "test"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":          {Type: schema.TypeString, Required: true},
+						"instance_type": {Type: schema.TypeString, Optional: true},
+						"arn":           {Type: schema.TypeString, Computed: true},
+						"rule": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"filter":   {Type: schema.TypeString, Optional: true},
+									"priority": {Type: schema.TypeInt, Optional: true},
+								},
+							},
+						},
+					},
+				},
+				DeprecatedFields: tc.args.deprecatedFields,
+				FieldRenames:     tc.args.fieldRenames,
+			}
+			obj := &unstructured.Unstructured{Object: map[string]any{
+				"spec": map[string]any{
+					"forProvider": tc.args.forProvider,
+				},
+			}}
+			warnings, err := NewDeprecationValidator(cfg).ValidateCreate(context.TODO(), obj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateCreate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.warnings, warnings); diff != "" {
+				t.Errorf("\n%s\nValidateCreate(...): -want warnings, +got warnings:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
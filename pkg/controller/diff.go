@@ -0,0 +1,263 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+var reIndex = regexp.MustCompile(`\[\d+\]`)
+
+// diffsSuppressed reports whether all the differences between the given
+// desired parameters and the observed Terraform state are suppressed by the
+// map normalizations, the diff suppression functions or the diff filter of
+// the given resource configuration. It reports false if no differences are
+// found, in which case the changes in the Terraform plan cannot be explained
+// by the parameters. The arguments set in the state but not in the desired
+// parameters are differences too, as Terraform plans to remove them, unless
+// the schema of the resource says they're not configured by the parameters.
+// Please note that the elements of the set fields are compared regardless of
+// their order, but a reordered set never suppresses the changes on its own as
+// Terraform does not plan changes for it.
+func diffsSuppressed(cfg *config.Resource, params, tfstate map[string]any) (bool, error) {
+	sets := map[string]struct{}{}
+	for _, p := range cfg.SetFields() {
+		sets[p] = struct{}{}
+	}
+	var diffs []config.FieldDiff
+	collectDiffs(cfg, params, tfstate, "", sets, &diffs)
+	if len(diffs) == 0 {
+		return false, nil
+	}
+	if len(cfg.MapNormalizations) > 0 {
+		// the whole parameters and state are compared again after the
+		// normalization, so that the normalized map fields only suppress
+		// the changes if they're the only remaining differences.
+		var err error
+		if params, err = normalizeMaps(cfg, params); err != nil {
+			return false, err
+		}
+		if tfstate, err = normalizeMaps(cfg, tfstate); err != nil {
+			return false, err
+		}
+		diffs = nil
+		collectDiffs(cfg, params, tfstate, "", sets, &diffs)
+	}
+	remaining := make([]config.FieldDiff, 0, len(diffs))
+	for _, d := range diffs {
+		if fn := diffSuppressFn(cfg, d.Path); fn == nil || !fn(d.Old, d.New) {
+			remaining = append(remaining, d)
+		}
+	}
+	if cfg.DiffFilterFn != nil && len(remaining) > 0 {
+		remaining = cfg.DiffFilterFn(remaining)
+	}
+	return len(remaining) == 0, nil
+}
+
+// diffSuppressFn returns the diff suppression function configured for the
+// field with the given path or for one of its parents.
+func diffSuppressFn(cfg *config.Resource, path string) config.DiffSuppressFn {
+	p := reIndex.ReplaceAllString(path, "")
+	for {
+		if fn, ok := cfg.DiffSuppressFns[p]; ok {
+			return fn
+		}
+		i := strings.LastIndex(p, ".")
+		if i == -1 {
+			return nil
+		}
+		p = p[:i]
+	}
+}
+
+// collectDiffs collects the differences between the desired value and the
+// observed value, i.e. the fields set in the desired value whose observed
+// values differ and the arguments removed from the observed value. The
+// elements of the set fields with the given Terraform paths are compared
+// regardless of their order.
+func collectDiffs(cfg *config.Resource, desired, observed any, path string, sets map[string]struct{}, diffs *[]config.FieldDiff) {
+	switch d := desired.(type) {
+	case nil:
+		return
+	case map[string]any:
+		o, _ := observed.(map[string]any)
+		for _, k := range sortedKeys(d) {
+			collectDiffs(cfg, d[k], o[k], joinPath(path, k), sets, diffs)
+		}
+		for _, k := range sortedKeys(o) {
+			if _, ok := d[k]; ok {
+				continue
+			}
+			if p := joinPath(path, k); removed(cfg, p, o[k]) {
+				*diffs = append(*diffs, config.FieldDiff{Path: p, Old: fmt.Sprint(o[k])})
+			}
+		}
+	case []any:
+		o, _ := observed.([]any)
+		if _, ok := sets[reIndex.ReplaceAllString(path, "")]; ok {
+			o = alignSet(d, o)
+		}
+		for i, v := range d {
+			var ov any
+			if i < len(o) {
+				ov = o[i]
+			}
+			collectDiffs(cfg, v, ov, fmt.Sprintf("%s[%d]", path, i), sets, diffs)
+		}
+		// the list or the set is configured as a whole, so its additional
+		// observed elements are removed.
+		for i := len(d); i < len(o); i++ {
+			if !isZero(o[i]) {
+				*diffs = append(*diffs, config.FieldDiff{Path: fmt.Sprintf("%s[%d]", path, i), Old: fmt.Sprint(o[i])})
+			}
+		}
+	default:
+		n := fmt.Sprint(d)
+		var old string
+		if observed != nil {
+			old = fmt.Sprint(observed)
+		}
+		if old != n {
+			*diffs = append(*diffs, config.FieldDiff{Path: path, Old: old, New: n})
+		}
+	}
+}
+
+// removed reports whether the argument with the given Terraform path, which is
+// observed with the given value but not set in the desired parameters, is
+// planned to be removed. The arguments of a resource without a schema are
+// considered removed, so that the changes in the plan are not suppressed.
+func removed(cfg *config.Resource, path string, observed any) bool {
+	if isZero(observed) {
+		return false
+	}
+	if cfg.TerraformResource == nil {
+		return true
+	}
+	sch, mapKey := schemaAt(cfg.TerraformResource, reIndex.ReplaceAllString(path, ""))
+	switch {
+	// the entries of a configured map are replaced as a whole.
+	case mapKey:
+		return true
+	// the attributes that are not in the schema, e.g. the id, or that are
+	// computed are not configured by the parameters.
+	case sch == nil || sch.Computed:
+		return false
+	// the sensitive arguments are configured from the connection secrets
+	// and the changes of the write-only ones are ignored.
+	case sch.Sensitive || cfg.Sensitive.IsWriteOnly(path):
+		return false
+	case sch.Default != nil:
+		return fmt.Sprint(sch.Default) != fmt.Sprint(observed)
+	}
+	return true
+}
+
+// schemaAt returns the schema of the field with the given Terraform path
+// without indices, and whether the path is of an entry of a map field, in
+// which case the schema of the map field is returned.
+func schemaAt(res *schema.Resource, path string) (*schema.Schema, bool) {
+	parts := strings.Split(path, ".")
+	for i, p := range parts {
+		sch, ok := res.Schema[p]
+		if !ok {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return sch, false
+		}
+		if sch.Type == schema.TypeMap {
+			return sch, i == len(parts)-2
+		}
+		er, ok := sch.Elem.(*schema.Resource)
+		if !ok {
+			return nil, false
+		}
+		res = er
+	}
+	return nil, false
+}
+
+// isZero reports whether the given observed value is the zero value of its
+// type, which Terraform does not distinguish from an unset argument.
+func isZero(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case bool:
+		return !t
+	case float64:
+		return t == 0
+	case []any:
+		return len(t) == 0
+	case map[string]any:
+		return len(t) == 0
+	}
+	return false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// alignSet returns the elements of the given observed set reordered so that
+// the elements equal to the desired ones are at the same indices. The other
+// observed elements fill the remaining indices in their order, and the ones
+// left over follow the desired ones.
+func alignSet(desired, observed []any) []any {
+	aligned := make([]any, len(desired))
+	matched := make([]bool, len(desired))
+	used := make([]bool, len(observed))
+	for i, d := range desired {
+		for j, o := range observed {
+			if !used[j] && reflect.DeepEqual(d, o) {
+				aligned[i], matched[i], used[j] = o, true, true
+				break
+			}
+		}
+	}
+	j := 0
+	for i := range desired {
+		if matched[i] {
+			continue
+		}
+		for j < len(observed) && used[j] {
+			j++
+		}
+		if j == len(observed) {
+			break
+		}
+		aligned[i], used[j] = observed[j], true
+	}
+	for j, o := range observed {
+		if !used[j] {
+			aligned = append(aligned, o)
+		}
+	}
+	return aligned
+}
@@ -0,0 +1,327 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+func TestDiffsSuppressed(t *testing.T) {
+	caseInsensitive := func(oldValue, newValue string) bool {
+		return strings.EqualFold(oldValue, newValue)
+	}
+	type args struct {
+		cfg     *config.Resource
+		params  map[string]any
+		tfstate map[string]any
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want bool
+	}{
+		"NoDiffs": {
+			reason: "The changes in the plan should not be suppressed if no differences are found.",
+			args: args{
+				cfg: &config.Resource{
+					DiffSuppressFns: map[string]config.DiffSuppressFn{"name": caseInsensitive},
+				},
+				params:  map[string]any{"name": "test"},
+				tfstate: map[string]any{"name": "test", "id": "test"},
+			},
+		},
+		"Suppressed": {
+			reason: "The changes in the plan should be suppressed if all differences are suppressed.",
+			args: args{
+				cfg: &config.Resource{
+					DiffSuppressFns: map[string]config.DiffSuppressFn{"name": caseInsensitive},
+				},
+				params:  map[string]any{"name": "Test", "size": float64(3)},
+				tfstate: map[string]any{"name": "test", "size": float64(3)},
+			},
+			want: true,
+		},
+		"NotSuppressed": {
+			reason: "The changes in the plan should not be suppressed if a difference is not suppressed.",
+			args: args{
+				cfg: &config.Resource{
+					DiffSuppressFns: map[string]config.DiffSuppressFn{"name": caseInsensitive},
+				},
+				params:  map[string]any{"name": "Test", "size": float64(3)},
+				tfstate: map[string]any{"name": "test", "size": float64(2)},
+			},
+		},
+		"NestedSuppressed": {
+			reason: "The differences in the nested fields of lists and maps should be suppressed by the functions of their parents.",
+			args: args{
+				cfg: &config.Resource{
+					DiffSuppressFns: map[string]config.DiffSuppressFn{
+						"rule.filter": caseInsensitive,
+						"tags":        caseInsensitive,
+					},
+				},
+				params: map[string]any{
+					"rule": []any{map[string]any{"filter": "A"}, map[string]any{"filter": "B"}},
+					"tags": map[string]any{"env": "Prod"},
+				},
+				tfstate: map[string]any{
+					"rule": []any{map[string]any{"filter": "a"}, map[string]any{"filter": "b"}},
+					"tags": map[string]any{"env": "prod"},
+				},
+			},
+			want: true,
+		},
+		"ArgumentRemoved": {
+			reason: "The changes in the plan should not be suppressed if an argument set in the state is removed from the parameters.",
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name":        {Type: schema.TypeString, Optional: true},
+							"description": {Type: schema.TypeString, Optional: true},
+						},
+					},
+					DiffSuppressFns: map[string]config.DiffSuppressFn{"name": caseInsensitive},
+				},
+				params:  map[string]any{"name": "Test"},
+				tfstate: map[string]any{"name": "test", "description": "test"},
+			},
+		},
+		"NotConfiguredArguments": {
+			reason: "The attributes in the state that are not configured by the parameters should not prevent the suppression.",
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name":     {Type: schema.TypeString, Optional: true},
+							"arn":      {Type: schema.TypeString, Computed: true},
+							"zone":     {Type: schema.TypeString, Optional: true, Computed: true},
+							"password": {Type: schema.TypeString, Optional: true, Sensitive: true},
+							"tier":     {Type: schema.TypeString, Optional: true, Default: "basic"},
+							"enabled":  {Type: schema.TypeBool, Optional: true},
+						},
+					},
+					DiffSuppressFns: map[string]config.DiffSuppressFn{"name": caseInsensitive},
+				},
+				params:  map[string]any{"name": "Test"},
+				tfstate: map[string]any{"id": "test", "name": "test", "arn": "arn:test", "zone": "a", "password": "secret", "tier": "basic", "enabled": false},
+			},
+			want: true,
+		},
+		"MapEntryRemoved": {
+			reason: "The changes in the plan should not be suppressed if an entry of a map is removed from the parameters.",
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {Type: schema.TypeString, Optional: true},
+							"tags": {Type: schema.TypeMap, Optional: true, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						},
+					},
+					DiffSuppressFns: map[string]config.DiffSuppressFn{"name": caseInsensitive},
+				},
+				params:  map[string]any{"name": "Test", "tags": map[string]any{"env": "prod"}},
+				tfstate: map[string]any{"name": "test", "tags": map[string]any{"env": "prod", "team": "a"}},
+			},
+		},
+		"BlockRemoved": {
+			reason: "The changes in the plan should not be suppressed if an element of a block list is removed from the parameters.",
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"rule": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"filter": {Type: schema.TypeString, Optional: true},
+								},
+							}},
+						},
+					},
+					DiffSuppressFns: map[string]config.DiffSuppressFn{"rule.filter": caseInsensitive},
+				},
+				params:  map[string]any{"rule": []any{map[string]any{"filter": "A"}}},
+				tfstate: map[string]any{"rule": []any{map[string]any{"filter": "a"}, map[string]any{"filter": "b"}}},
+			},
+		},
+		"NoSchema": {
+			reason: "The changes in the plan should not be suppressed if the state has arguments not in the parameters and there's no schema to tell whether they're configured.",
+			args: args{
+				cfg: &config.Resource{
+					DiffSuppressFns: map[string]config.DiffSuppressFn{"name": caseInsensitive},
+				},
+				params:  map[string]any{"name": "Test"},
+				tfstate: map[string]any{"name": "test", "description": "test"},
+			},
+		},
+		"Filtered": {
+			reason: "The changes in the plan should be suppressed if the diff filter drops all the remaining differences.",
+			args: args{
+				cfg: &config.Resource{
+					DiffFilterFn: func(diffs []config.FieldDiff) []config.FieldDiff {
+						var remaining []config.FieldDiff
+						for _, d := range diffs {
+							if d.Path != "rule[1].filter" {
+								remaining = append(remaining, d)
+							}
+						}
+						return remaining
+					},
+				},
+				params:  map[string]any{"rule": []any{map[string]any{"filter": "a"}, map[string]any{"filter": "b"}}},
+				tfstate: map[string]any{"rule": []any{map[string]any{"filter": "a"}}},
+			},
+			want: true,
+		},
+		"SetReordered": {
+			reason: "The changes in the plan should not be suppressed if a set is only reordered, as Terraform does not plan changes for the order of the set elements.",
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"zones": {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						},
+					},
+				},
+				params:  map[string]any{"zones": []any{"a", "b", "c"}},
+				tfstate: map[string]any{"zones": []any{"c", "a", "b"}},
+			},
+		},
+		"SetReorderedSuppressed": {
+			reason: "The elements of the sets should be compared regardless of their order, so that the other differences can be suppressed.",
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name":  {Type: schema.TypeString, Optional: true},
+							"zones": {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						},
+					},
+					DiffSuppressFns: map[string]config.DiffSuppressFn{"name": caseInsensitive},
+				},
+				params:  map[string]any{"name": "Test", "zones": []any{"a", "b", "c"}},
+				tfstate: map[string]any{"name": "test", "zones": []any{"c", "a", "b"}},
+			},
+			want: true,
+		},
+		"SetGrown": {
+			reason: "The sets with additional observed elements should not be suppressed.",
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"zones": {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						},
+					},
+				},
+				params:  map[string]any{"zones": []any{"a", "b"}},
+				tfstate: map[string]any{"zones": []any{"b", "c", "a"}},
+			},
+		},
+		"SetElementChanged": {
+			reason: "The changed elements of the sets should not be suppressed.",
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"zones": {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						},
+					},
+				},
+				params:  map[string]any{"zones": []any{"a", "b"}},
+				tfstate: map[string]any{"zones": []any{"b", "d"}},
+			},
+		},
+		"ListReordered": {
+			reason: "The elements of the lists should be compared in order.",
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"zones": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						},
+					},
+				},
+				params:  map[string]any{"zones": []any{"a", "b"}},
+				tfstate: map[string]any{"zones": []any{"b", "a"}},
+			},
+		},
+		"Normalized": {
+			reason: "The changes in the plan should be suppressed if the map fields only differ before normalization.",
+			args: args{
+				cfg: &config.Resource{
+					MapNormalizations: map[string]config.MapNormalization{
+						"tags": {
+							LowercaseKeys:  true,
+							IgnoredKeys:    []string{"aws:*"},
+							DefaultEntries: map[string]string{"managed-by": "crossplane"},
+						},
+					},
+				},
+				params:  map[string]any{"name": "test", "tags": map[string]any{"Env": "prod"}},
+				tfstate: map[string]any{"name": "test", "tags": map[string]any{"env": "prod", "aws:stack": "s", "managed-by": "crossplane"}},
+			},
+			want: true,
+		},
+		"NormalizedWithRemovedArgument": {
+			reason: "The changes in the plan should not be suppressed if the map fields only differ before normalization but an argument is removed from the parameters.",
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"description": {Type: schema.TypeString, Optional: true},
+							"tags":        {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						},
+					},
+					MapNormalizations: map[string]config.MapNormalization{
+						"tags": {LowercaseKeys: true},
+					},
+				},
+				params:  map[string]any{"tags": map[string]any{"Env": "prod"}},
+				tfstate: map[string]any{"description": "test", "tags": map[string]any{"env": "prod"}},
+			},
+		},
+		"NormalizedMapsDiffer": {
+			reason: "The changes in the plan should not be suppressed if the normalized map fields differ.",
+			args: args{
+				cfg: &config.Resource{
+					MapNormalizations: map[string]config.MapNormalization{
+						"tags": {IgnoredKeys: []string{"aws:*"}},
+					},
+				},
+				params:  map[string]any{"tags": map[string]any{"env": "prod"}},
+				tfstate: map[string]any{"tags": map[string]any{"env": "prod", "team": "a"}},
+			},
+		},
+		"NormalizedNoDiffs": {
+			reason: "The changes in the plan should not be suppressed if the map fields are equal without normalization.",
+			args: args{
+				cfg: &config.Resource{
+					MapNormalizations: map[string]config.MapNormalization{
+						"tags": {LowercaseKeys: true},
+					},
+				},
+				params:  map[string]any{"tags": map[string]any{"env": "prod"}},
+				tfstate: map[string]any{"tags": map[string]any{"env": "prod"}},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := diffsSuppressed(tc.args.cfg, tc.args.params, tc.args.tfstate)
+			if err != nil {
+				t.Fatalf("\n%s\ndiffsSuppressed(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ndiffsSuppressed(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
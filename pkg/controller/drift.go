@@ -0,0 +1,82 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/resource"
+)
+
+const (
+	errFmtUnknownDriftPolicy = "unknown drift policy %q"
+)
+
+// driftPolicy returns the drift remediation policy of the given managed
+// resource, which is the configured policy unless overridden with an
+// annotation.
+func driftPolicy(mg xpresource.Managed, cfg *config.Resource) (config.DriftPolicy, error) {
+	p := cfg.DriftPolicy
+	if a, ok := mg.GetAnnotations()[resource.AnnotationKeyDriftPolicy]; ok {
+		p = config.DriftPolicy(a)
+	}
+	switch p {
+	case "":
+		return config.DriftPolicyAutoCorrect, nil
+	case config.DriftPolicyAutoCorrect, config.DriftPolicyReportOnly, config.DriftPolicyBlock:
+		return p, nil
+	default:
+		return "", errors.Errorf(errFmtUnknownDriftPolicy, p)
+	}
+}
+
+// applyDriftPolicy applies the drift remediation policy of the given managed
+// resource using the result of the plan, and returns whether the resource
+// should be reported as up-to-date to the managed reconciler. A difference
+// between the desired and the observed state is considered a drift only if
+// the generation of the managed resource has not changed since the resource
+// was last observed to be in sync, otherwise, it's a desired state change
+// which is always applied.
+func (e *external) applyDriftPolicy(ctx context.Context, mg xpresource.Managed, upToDate bool) (bool, error) {
+	policy, err := driftPolicy(mg, e.config)
+	if err != nil {
+		return false, err
+	}
+	if policy == config.DriftPolicyAutoCorrect {
+		return upToDate, nil
+	}
+	gen := strconv.FormatInt(mg.GetGeneration(), 10)
+	a := mg.GetAnnotations()
+	if upToDate {
+		if a[resource.AnnotationKeyInSyncGeneration] != gen || a[resource.AnnotationKeyApproveDriftRemediation] != "" {
+			meta.AddAnnotations(mg, map[string]string{resource.AnnotationKeyInSyncGeneration: gen})
+			meta.RemoveAnnotations(mg, resource.AnnotationKeyApproveDriftRemediation)
+			if err := e.kube.Update(ctx, mg); err != nil {
+				return false, errors.Wrap(err, errUpdateAnnotations)
+			}
+		}
+		mg.SetConditions(resource.InSyncCondition())
+		return true, nil
+	}
+	if a[resource.AnnotationKeyInSyncGeneration] != gen {
+		return false, nil
+	}
+	switch {
+	case policy == config.DriftPolicyReportOnly:
+		mg.SetConditions(resource.DriftReportedCondition())
+		return true, nil
+	case a[resource.AnnotationKeyApproveDriftRemediation] == "true":
+		return false, nil
+	default:
+		mg.SetConditions(resource.DriftBlockedCondition())
+		return true, nil
+	}
+}
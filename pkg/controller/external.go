@@ -10,9 +10,11 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -37,6 +39,11 @@ const (
 	errStatusUpdate      = "cannot update status of custom resource"
 	errScheduleProvider  = "cannot schedule native Terraform provider process"
 	errUpdateAnnotations = "cannot update managed resource annotations"
+	errApplyDriftPolicy  = "cannot apply drift policy"
+	errGetParameters     = "cannot get parameters"
+	errCheckReadiness    = "cannot check readiness"
+	errCompareDiffs      = "cannot compare the parameters with the Terraform state"
+	errNormalizeMaps     = "cannot normalize the map fields of the Terraform state"
 )
 
 // Option allows you to configure Connector.
@@ -81,6 +88,7 @@ type Connector struct {
 	getTerraformSetup terraform.SetupFn
 	config            *config.Resource
 	callback          CallbackProvider
+	hooks             operationHooks
 	logger            logging.Logger
 }
 
@@ -97,7 +105,7 @@ func (c *Connector) Connect(ctx context.Context, mg xpresource.Managed) (managed
 		return nil, errors.Wrap(err, errGetTerraformSetup)
 	}
 
-	ws, err := c.store.Workspace(ctx, &APISecretClient{kube: c.kube}, tr, ts, c.config)
+	ws, err := c.store.Workspace(ctx, &APISecretClient{kube: c.kube, namespace: mg.GetNamespace()}, tr, ts, c.config)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetWorkspace)
 	}
@@ -105,6 +113,8 @@ func (c *Connector) Connect(ctx context.Context, mg xpresource.Managed) (managed
 		workspace:         ws,
 		config:            c.config,
 		callback:          c.callback,
+		hooks:             c.hooks,
+		providerConfig:    ts.Configuration,
 		providerScheduler: ts.Scheduler,
 		providerHandle:    ws.ProviderHandle,
 		kube:              c.kube,
@@ -116,6 +126,8 @@ type external struct {
 	workspace         Workspace
 	config            *config.Resource
 	callback          CallbackProvider
+	hooks             operationHooks
+	providerConfig    map[string]any
 	providerScheduler terraform.ProviderScheduler
 	providerHandle    terraform.ProviderHandle
 	kube              client.Client
@@ -145,7 +157,15 @@ func (e *external) stopProvider() {
 	}
 }
 
-func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) { //nolint:gocyclo
+func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	obs, err := e.observe(ctx, mg)
+	if err != nil {
+		return obs, err
+	}
+	return obs, errors.Wrap(e.runHooks(ctx, e.hooks.postObserve, mg), errPostObserveHook)
+}
+
+func (e *external) observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) { //nolint:gocyclo
 	// We skip the gocyclo check because most of the operations are straight-forward
 	// and serial.
 	// TODO(muvaf): Look for ways to reduce the cyclomatic complexity without
@@ -159,6 +179,12 @@ func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.
 		return managed.ExternalObservation{}, errors.New(errUnexpectedObject)
 	}
 
+	// The data sources are read-only, so they're only observed regardless
+	// of the management policies.
+	if e.config.DataSource {
+		return e.observeDataSource(ctx, tr)
+	}
+
 	policySet := sets.New[xpv1.ManagementAction](tr.GetManagementPolicies()...)
 
 	// Note(turkenh): We don't need to check if the management policies are
@@ -174,9 +200,18 @@ func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.
 		return e.Import(ctx, tr)
 	}
 
-	res, err := e.workspace.Refresh(ctx)
-	if err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(err, errRefresh)
+	var res terraform.RefreshResult
+	var err error
+	if e.adoptionPending(tr) {
+		res, err = e.adopt(ctx, tr)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+	} else {
+		res, err = e.workspace.Refresh(ctx)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errRefresh)
+		}
 	}
 
 	switch {
@@ -237,12 +272,22 @@ func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.
 
 	var lateInitedParams bool
 	if policyHasLateInit {
-		lateInitedParams, err = tr.LateInitialize(res.State.GetAttributes())
+		attrs := res.State.GetAttributes()
+		if len(e.config.MapNormalizations) > 0 {
+			if attrs, err = normalizedAttributes(e.config, tfstate); err != nil {
+				return managed.ExternalObservation{}, errors.Wrap(err, errNormalizeMaps)
+			}
+		}
+		lateInitedParams, err = tr.LateInitialize(attrs)
 		if err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, "cannot late initialize parameters")
 		}
 	}
-	markedAvailable := tr.GetCondition(xpv1.TypeReady).Equal(xpv1.Available())
+	ready, err := readyCondition(e.config, tfstate)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errCheckReadiness)
+	}
+	markedAvailable := tr.GetCondition(xpv1.TypeReady).Equal(ready)
 
 	// In the following switch block, before running a relatively costly
 	// Terraform apply and that may fail before critical annotations are
@@ -267,8 +312,10 @@ func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.
 		}, nil
 	// we prioritize status updates over late-init'ed spec updates
 	case !markedAvailable:
-		addTTR(tr)
-		tr.SetConditions(xpv1.Available())
+		if ready.Reason == xpv1.ReasonAvailable {
+			addTTR(tr)
+		}
+		tr.SetConditions(ready)
 		return managed.ExternalObservation{
 			ResourceExists:    true,
 			ResourceUpToDate:  true,
@@ -290,28 +337,67 @@ func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.
 			return managed.ExternalObservation{}, errors.Wrap(err, errPlan)
 		}
 
-		resource.SetUpToDateCondition(mg, plan.UpToDate)
+		upToDate := plan.UpToDate
+		if !upToDate && (len(e.config.DiffSuppressFns) > 0 || e.config.DiffFilterFn != nil || len(e.config.MapNormalizations) > 0) {
+			params, err := e.configuredParameters(tr)
+			if err != nil {
+				return managed.ExternalObservation{}, errors.Wrap(err, errGetParameters)
+			}
+			if upToDate, err = diffsSuppressed(e.config, params, tfstate); err != nil {
+				return managed.ExternalObservation{}, errors.Wrap(err, errCompareDiffs)
+			}
+		}
+		resource.SetUpToDateCondition(mg, upToDate)
+		upToDate, err = e.applyDriftPolicy(ctx, mg, upToDate)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errApplyDriftPolicy)
+		}
 
 		return managed.ExternalObservation{
 			ResourceExists:    true,
-			ResourceUpToDate:  plan.UpToDate,
+			ResourceUpToDate:  upToDate,
 			ConnectionDetails: conn,
 		}, nil
 	}
 }
 
+// configuredParameters returns the parameters of the given resource as they're
+// configured in the Terraform configuration of its workspace, i.e. with the
+// parameter defaults, the hidden parameters and the identifier arguments.
+func (e *external) configuredParameters(tr resource.Terraformed) (map[string]any, error) {
+	params, err := tr.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range e.config.ParameterDefaults {
+		if _, ok := params[k]; !ok {
+			params[k] = v
+		}
+	}
+	for k, v := range e.config.HiddenParameters {
+		params[k] = v
+	}
+	if e.config.ExternalName.SetIdentifierArgumentFn != nil {
+		e.config.ExternalName.SetIdentifierArgumentFn(params, meta.GetExternalName(tr))
+	}
+	return params, nil
+}
+
 func addTTR(mg xpresource.Managed) {
 	gvk := mg.GetObjectKind().GroupVersionKind()
 	metrics.TTRMeasurements.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind).Observe(time.Since(mg.GetCreationTimestamp().Time).Seconds())
 }
 
 func (e *external) Create(ctx context.Context, mg xpresource.Managed) (managed.ExternalCreation, error) {
+	if err := e.runHooks(ctx, e.hooks.preCreate, mg); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errPreCreateHook)
+	}
 	if err := e.scheduleProvider(); err != nil {
 		return managed.ExternalCreation{}, errors.Wrapf(err, "cannot schedule a native provider during create: %s", mg.GetUID())
 	}
 	defer e.stopProvider()
 	if e.config.UseAsync {
-		return managed.ExternalCreation{}, errors.Wrap(e.workspace.ApplyAsync(e.callback.Apply(mg.GetName())), errStartAsyncApply)
+		return managed.ExternalCreation{}, errors.Wrap(e.workspace.ApplyAsync(e.callback.Apply(types.NamespacedName{Namespace: mg.GetNamespace(), Name: mg.GetName()})), errStartAsyncApply)
 	}
 	tr, ok := mg.(resource.Terraformed)
 	if !ok {
@@ -337,12 +423,15 @@ func (e *external) Create(ctx context.Context, mg xpresource.Managed) (managed.E
 }
 
 func (e *external) Update(ctx context.Context, mg xpresource.Managed) (managed.ExternalUpdate, error) {
+	if err := e.runHooks(ctx, e.hooks.preUpdate, mg); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errPreUpdateHook)
+	}
 	if err := e.scheduleProvider(); err != nil {
 		return managed.ExternalUpdate{}, errors.Wrapf(err, "cannot schedule a native provider during update: %s", mg.GetUID())
 	}
 	defer e.stopProvider()
 	if e.config.UseAsync {
-		return managed.ExternalUpdate{}, errors.Wrap(e.workspace.ApplyAsync(e.callback.Apply(mg.GetName())), errStartAsyncApply)
+		return managed.ExternalUpdate{}, errors.Wrap(e.workspace.ApplyAsync(e.callback.Apply(types.NamespacedName{Namespace: mg.GetNamespace(), Name: mg.GetName()})), errStartAsyncApply)
 	}
 	tr, ok := mg.(resource.Terraformed)
 	if !ok {
@@ -360,12 +449,15 @@ func (e *external) Update(ctx context.Context, mg xpresource.Managed) (managed.E
 }
 
 func (e *external) Delete(ctx context.Context, mg xpresource.Managed) error {
+	if err := e.runHooks(ctx, e.hooks.preDelete, mg); err != nil {
+		return errors.Wrap(err, errPreDeleteHook)
+	}
 	if err := e.scheduleProvider(); err != nil {
 		return errors.Wrapf(err, "cannot schedule a native provider during delete: %s", mg.GetUID())
 	}
 	defer e.stopProvider()
 	if e.config.UseAsync {
-		return errors.Wrap(e.workspace.DestroyAsync(e.callback.Destroy(mg.GetName())), errStartAsyncDestroy)
+		return errors.Wrap(e.workspace.DestroyAsync(e.callback.Destroy(types.NamespacedName{Namespace: mg.GetNamespace(), Name: mg.GetName()})), errStartAsyncDestroy)
 	}
 	return errors.Wrap(e.workspace.Destroy(ctx), errDestroy)
 }
@@ -375,6 +467,22 @@ func (e *external) Import(ctx context.Context, tr resource.Terraformed) (managed
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errImport)
 	}
+	return e.observeOnly(tr, terraform.RefreshResult(res))
+}
+
+// observeDataSource reads the Terraform data source of the given read-only
+// managed resource, which has no state to be imported.
+func (e *external) observeDataSource(ctx context.Context, tr resource.Terraformed) (managed.ExternalObservation, error) {
+	res, err := e.workspace.Refresh(ctx)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errRefresh)
+	}
+	return e.observeOnly(tr, res)
+}
+
+// observeOnly sets the observation of the given managed resource, which is
+// only observed, from the given result of reading its external resource.
+func (e *external) observeOnly(tr resource.Terraformed, res terraform.RefreshResult) (managed.ExternalObservation, error) {
 	// We normally don't expect apply/destroy to be in progress when the
 	// management policy is set to "ObserveOnly". However, this could happen
 	// if the policy is changed to "ObserveOnly" while an async operation is
@@ -411,7 +519,11 @@ func (e *external) Import(ctx context.Context, tr resource.Terraformed) (managed
 		return managed.ExternalObservation{}, errors.Wrap(err, "cannot get connection details")
 	}
 
-	tr.SetConditions(xpv1.Available())
+	ready, err := readyCondition(e.config, tfstate)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errCheckReadiness)
+	}
+	tr.SetConditions(ready)
 	return managed.ExternalObservation{
 		ResourceExists:    true,
 		ResourceUpToDate:  true,
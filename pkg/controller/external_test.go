@@ -18,6 +18,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/upbound/upjet/pkg/config"
@@ -97,16 +98,16 @@ func (s StoreFns) Workspace(ctx context.Context, c resource.SecretClient, tr res
 }
 
 type CallbackFns struct {
-	ApplyFn   func(string) terraform.CallbackFn
-	DestroyFn func(string) terraform.CallbackFn
+	ApplyFn   func(types.NamespacedName) terraform.CallbackFn
+	DestroyFn func(types.NamespacedName) terraform.CallbackFn
 }
 
-func (c CallbackFns) Apply(name string) terraform.CallbackFn {
-	return c.ApplyFn(name)
+func (c CallbackFns) Apply(nn types.NamespacedName) terraform.CallbackFn {
+	return c.ApplyFn(nn)
 }
 
-func (c CallbackFns) Destroy(name string) terraform.CallbackFn {
-	return c.DestroyFn(name)
+func (c CallbackFns) Destroy(nn types.NamespacedName) terraform.CallbackFn {
+	return c.DestroyFn(nn)
 }
 
 func TestConnect(t *testing.T) {
@@ -190,6 +191,7 @@ func TestObserve(t *testing.T) {
 		w      Workspace
 		obj    xpresource.Managed
 		client client.Client
+		cfg    *config.Resource
 	}
 	type want struct {
 		obs       managed.ExternalObservation
@@ -481,6 +483,55 @@ func TestObserve(t *testing.T) {
 				condition: available(),
 			},
 		},
+		"DataSourceSuccess": {
+			reason: "We should read a data source with a refresh instead of an import",
+			args: args{
+				obj: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						Manageable: xpfake.Manageable{
+							Policy: xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+						},
+					},
+				},
+				cfg: dataSourceConfig(),
+				w: WorkspaceFns{
+					RefreshFn: func(_ context.Context) (terraform.RefreshResult, error) {
+						return terraform.RefreshResult{
+							Exists: true,
+							State:  exampleState,
+						}, nil
+					},
+				},
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				condition: available(),
+			},
+		},
+		"DataSourceRefreshFailed": {
+			reason: "We should return the error if the data source cannot be read",
+			args: args{
+				obj: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						Manageable: xpfake.Manageable{
+							Policy: xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+						},
+					},
+				},
+				cfg: dataSourceConfig(),
+				w: WorkspaceFns{
+					RefreshFn: func(_ context.Context) (terraform.RefreshResult, error) {
+						return terraform.RefreshResult{}, errBoom
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errRefresh),
+			},
+		},
 		"TransitionToReadyManagementPolicyDefault": {
 			reason: "We should mark the resource as ready if the refresh succeeds and there is no ongoing operation",
 			args: args{
@@ -583,10 +634,235 @@ func TestObserve(t *testing.T) {
 				err: errors.Wrap(errBoom, errUpdateAnnotations),
 			},
 		},
+		"DriftReported": {
+			reason: "A drift should only be reported if the drift policy is ReportOnly",
+			args: args{
+				obj: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: driftAnnotations(config.DriftPolicyReportOnly),
+						},
+						ConditionedStatus: xpv1.ConditionedStatus{
+							Conditions: []xpv1.Condition{xpv1.Available()},
+						},
+						Manageable: xpfake.Manageable{
+							Policy: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+						},
+					},
+				},
+				w: WorkspaceFns{
+					RefreshFn: func(_ context.Context) (terraform.RefreshResult, error) {
+						return terraform.RefreshResult{
+							Exists: true,
+							State:  exampleState,
+						}, nil
+					},
+					PlanFn: func(_ context.Context) (terraform.PlanResult, error) {
+						return terraform.PlanResult{UpToDate: false}, nil
+					},
+				},
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				condition: condition(resource.DriftReportedCondition()),
+			},
+		},
+		"DriftBlocked": {
+			reason: "A drift should not be remediated without approval if the drift policy is Block",
+			args: args{
+				obj: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: driftAnnotations(config.DriftPolicyBlock),
+						},
+						ConditionedStatus: xpv1.ConditionedStatus{
+							Conditions: []xpv1.Condition{xpv1.Available()},
+						},
+						Manageable: xpfake.Manageable{
+							Policy: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+						},
+					},
+				},
+				w: WorkspaceFns{
+					RefreshFn: func(_ context.Context) (terraform.RefreshResult, error) {
+						return terraform.RefreshResult{
+							Exists: true,
+							State:  exampleState,
+						}, nil
+					},
+					PlanFn: func(_ context.Context) (terraform.PlanResult, error) {
+						return terraform.PlanResult{UpToDate: false}, nil
+					},
+				},
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				condition: condition(resource.DriftBlockedCondition()),
+			},
+		},
+		"DriftRemediationApproved": {
+			reason: "An approved drift should be remediated if the drift policy is Block",
+			args: args{
+				obj: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: func() map[string]string {
+								a := driftAnnotations(config.DriftPolicyBlock)
+								a[resource.AnnotationKeyApproveDriftRemediation] = "true"
+								return a
+							}(),
+						},
+						ConditionedStatus: xpv1.ConditionedStatus{
+							Conditions: []xpv1.Condition{xpv1.Available()},
+						},
+						Manageable: xpfake.Manageable{
+							Policy: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+						},
+					},
+				},
+				w: WorkspaceFns{
+					RefreshFn: func(_ context.Context) (terraform.RefreshResult, error) {
+						return terraform.RefreshResult{
+							Exists: true,
+							State:  exampleState,
+						}, nil
+					},
+					PlanFn: func(_ context.Context) (terraform.PlanResult, error) {
+						return terraform.PlanResult{UpToDate: false}, nil
+					},
+				},
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+			},
+		},
+		"UnknownDriftPolicy": {
+			reason: "An unknown drift policy should be reported as an error",
+			args: args{
+				obj: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: driftAnnotations("Unknown"),
+						},
+						ConditionedStatus: xpv1.ConditionedStatus{
+							Conditions: []xpv1.Condition{xpv1.Available()},
+						},
+						Manageable: xpfake.Manageable{
+							Policy: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+						},
+					},
+				},
+				w: WorkspaceFns{
+					RefreshFn: func(_ context.Context) (terraform.RefreshResult, error) {
+						return terraform.RefreshResult{
+							Exists: true,
+							State:  exampleState,
+						}, nil
+					},
+					PlanFn: func(_ context.Context) (terraform.PlanResult, error) {
+						return terraform.PlanResult{UpToDate: false}, nil
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtUnknownDriftPolicy, "Unknown"), errApplyDriftPolicy),
+			},
+		},
+		"AdoptNonExistent": {
+			reason: "It should return error instead of creating a new external resource if the one to be adopted does not exist",
+			args: args{
+				cfg: adoptionConfig(),
+				obj: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{xpmeta.AnnotationKeyExternalName: "some-id"},
+						},
+						Manageable: xpfake.Manageable{
+							Policy: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+						},
+					},
+				},
+				w: WorkspaceFns{
+					ImportFn: func(_ context.Context, _ resource.Terraformed) (terraform.ImportResult, error) {
+						return terraform.ImportResult{Exists: false}, nil
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtAdoptNonExistent, "some-id"),
+			},
+		},
+		"Adopted": {
+			reason: "The external resource with the external name should be imported if it is to be adopted",
+			args: args{
+				cfg: adoptionConfig(),
+				obj: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{xpmeta.AnnotationKeyExternalName: "some-id"},
+						},
+						Manageable: xpfake.Manageable{
+							Policy: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+						},
+					},
+				},
+				w: WorkspaceFns{
+					ImportFn: func(_ context.Context, _ resource.Terraformed) (terraform.ImportResult, error) {
+						return terraform.ImportResult{Exists: true, State: exampleState}, nil
+					},
+				},
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+				},
+			},
+		},
+		"AdoptionCompleted": {
+			reason: "The external resource should be refreshed once it has been observed",
+			args: args{
+				cfg: adoptionConfig(),
+				obj: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: exampleCriticalAnnotations,
+						},
+						Manageable: xpfake.Manageable{
+							Policy: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+						},
+					},
+				},
+				w: WorkspaceFns{
+					RefreshFn: func(_ context.Context) (terraform.RefreshResult, error) {
+						return terraform.RefreshResult{Exists: false}, nil
+					},
+				},
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists: false,
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := &external{workspace: tc.w, config: config.DefaultResource("upjet_resource", nil, nil), kube: tc.args.client}
+			cfg := tc.args.cfg
+			if cfg == nil {
+				cfg = config.DefaultResource("upjet_resource", nil, nil)
+			}
+			e := &external{workspace: tc.w, config: cfg, kube: tc.args.client}
 			observation, err := e.Observe(context.TODO(), tc.args.obj)
 			if diff := cmp.Diff(tc.want.obs, observation); diff != "" {
 				t.Errorf("\n%s\nObserve(...): -want observation, +got observation:\n%s", tc.reason, diff)
@@ -608,12 +884,41 @@ func available() *xpv1.Condition {
 	return &c
 }
 
+func condition(c xpv1.Condition) *xpv1.Condition {
+	return &c
+}
+
+func driftAnnotations(p config.DriftPolicy) map[string]string {
+	a := map[string]string{
+		resource.AnnotationKeyDriftPolicy:      string(p),
+		resource.AnnotationKeyInSyncGeneration: "0",
+	}
+	for k, v := range exampleCriticalAnnotations {
+		a[k] = v
+	}
+	return a
+}
+
+func adoptionConfig() *config.Resource {
+	cfg := config.DefaultResource("upjet_resource", nil, nil)
+	cfg.ExternalName = config.IdentifierFromProvider
+	return cfg
+}
+
+func dataSourceConfig() *config.Resource {
+	cfg := config.DefaultResource("upjet_resource", nil, nil)
+	cfg.DataSource = true
+	cfg.ExternalName = config.IdentifierFromProvider
+	return cfg
+}
+
 func TestCreate(t *testing.T) {
 	type args struct {
-		w   Workspace
-		c   CallbackProvider
-		cfg *config.Resource
-		obj xpresource.Managed
+		w     Workspace
+		c     CallbackProvider
+		cfg   *config.Resource
+		obj   xpresource.Managed
+		hooks operationHooks
 	}
 	type want struct {
 		err error
@@ -639,7 +944,7 @@ func TestCreate(t *testing.T) {
 					UseAsync: true,
 				},
 				c: CallbackFns{
-					ApplyFn: func(s string) terraform.CallbackFn {
+					ApplyFn: func(_ types.NamespacedName) terraform.CallbackFn {
 						return nil
 					},
 				},
@@ -654,6 +959,23 @@ func TestCreate(t *testing.T) {
 				err: errors.Wrap(errBoom, errStartAsyncApply),
 			},
 		},
+		"PreCreateHookFailed": {
+			reason: "It should return error if a pre-create hook fails",
+			args: args{
+				cfg: &config.Resource{},
+				obj: &fake.Terraformed{},
+				hooks: operationHooks{
+					preCreate: []config.OperationHookFn{
+						func(_ context.Context, _ xpresource.Managed, _ map[string]any) error {
+							return errBoom
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errPreCreateHook),
+			},
+		},
 		"SyncApplyFailed": {
 			reason: "It should return error if it cannot apply in sync mode",
 			args: args{
@@ -672,7 +994,7 @@ func TestCreate(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := &external{workspace: tc.w, callback: tc.c, config: tc.cfg}
+			e := &external{workspace: tc.w, callback: tc.c, config: tc.cfg, hooks: tc.args.hooks}
 			_, err := e.Create(context.TODO(), tc.args.obj)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nCreate(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -712,7 +1034,7 @@ func TestUpdate(t *testing.T) {
 					UseAsync: true,
 				},
 				c: CallbackFns{
-					ApplyFn: func(s string) terraform.CallbackFn {
+					ApplyFn: func(_ types.NamespacedName) terraform.CallbackFn {
 						return nil
 					},
 				},
@@ -776,7 +1098,7 @@ func TestDelete(t *testing.T) {
 					UseAsync: true,
 				},
 				c: CallbackFns{
-					DestroyFn: func(_ string) terraform.CallbackFn {
+					DestroyFn: func(_ types.NamespacedName) terraform.CallbackFn {
 						return nil
 					},
 				},
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+const (
+	errPreCreateHook   = "cannot run pre-create hook"
+	errPreUpdateHook   = "cannot run pre-update hook"
+	errPreDeleteHook   = "cannot run pre-delete hook"
+	errPostObserveHook = "cannot run post-observe hook"
+)
+
+// operationHooks are the hooks of config.OperationHooks bound to a client.
+type operationHooks struct {
+	preCreate   []config.OperationHookFn
+	preUpdate   []config.OperationHookFn
+	preDelete   []config.OperationHookFn
+	postObserve []config.OperationHookFn
+}
+
+func newHooks(kube client.Client, fns []config.NewOperationHookFn) []config.OperationHookFn {
+	hooks := make([]config.OperationHookFn, 0, len(fns))
+	for _, fn := range fns {
+		hooks = append(hooks, fn(kube))
+	}
+	return hooks
+}
+
+// WithOperationHooks configures the hooks run before and after the operations
+// on the external resources.
+func WithOperationHooks(h config.OperationHooks) Option {
+	return func(c *Connector) {
+		c.hooks = operationHooks{
+			preCreate:   newHooks(c.kube, h.PreCreate),
+			preUpdate:   newHooks(c.kube, h.PreUpdate),
+			preDelete:   newHooks(c.kube, h.PreDelete),
+			postObserve: newHooks(c.kube, h.PostObserve),
+		}
+	}
+}
+
+// runHooks runs the given hooks in order and stops at the first error.
+func (e *external) runHooks(ctx context.Context, hooks []config.OperationHookFn, mg xpresource.Managed) error {
+	for _, h := range hooks {
+		if err := h(ctx, mg, e.providerConfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/resource/fake"
+)
+
+func TestOperationHooks(t *testing.T) {
+	type want struct {
+		calls []string
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		hooks  []string
+		want   want
+	}{
+		"NoHooks": {
+			reason: "Nothing should be run if no hooks are configured.",
+		},
+		"HooksRun": {
+			reason: "The hooks should be run in order with the provider configuration.",
+			hooks:  []string{"first", "second"},
+			want: want{
+				calls: []string{"first:test-region", "second:test-region"},
+			},
+		},
+		"HookFailed": {
+			reason: "The hooks after a failed hook should not be run.",
+			hooks:  []string{"first", "fail", "second"},
+			want: want{
+				calls: []string{"first:test-region", "fail:test-region"},
+				err:   errBoom,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var calls []string
+			fns := make([]config.NewOperationHookFn, 0, len(tc.hooks))
+			for _, h := range tc.hooks {
+				h := h
+				fns = append(fns, func(_ client.Client) config.OperationHookFn {
+					return func(_ context.Context, _ xpresource.Managed, providerConfig map[string]any) error {
+						calls = append(calls, h+":"+providerConfig["region"].(string))
+						if h == "fail" {
+							return errBoom
+						}
+						return nil
+					}
+				})
+			}
+			c := NewConnector(nil, nil, nil, &config.Resource{}, WithOperationHooks(config.OperationHooks{PreUpdate: fns}))
+			e := &external{hooks: c.hooks, providerConfig: map[string]any{"region": "test-region"}}
+			err := e.runHooks(context.TODO(), e.hooks.preUpdate, &fake.Terraformed{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrunHooks(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.calls, calls); diff != "" {
+				t.Errorf("\n%s\nrunHooks(...): -want calls, +got calls:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -7,6 +7,8 @@ package controller
 import (
 	"context"
 
+	"k8s.io/apimachinery/pkg/types"
+
 	"github.com/upbound/upjet/pkg/config"
 	"github.com/upbound/upjet/pkg/resource"
 	"github.com/upbound/upjet/pkg/terraform"
@@ -40,6 +42,6 @@ type Store interface {
 // CallbackProvider provides functions that can be called with the result of
 // async operations.
 type CallbackProvider interface {
-	Apply(name string) terraform.CallbackFn
-	Destroy(name string) terraform.CallbackFn
+	Apply(nn types.NamespacedName) terraform.CallbackFn
+	Destroy(nn types.NamespacedName) terraform.CallbackFn
 }
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/upbound/upjet/pkg/metrics"
+)
+
+// NewInstrumentedReconciler returns a reconcile.Reconciler recording the
+// results of the reconciliations of the given reconciler in the
+// per-kind metrics of the managed resources of the given GroupVersionKind.
+func NewInstrumentedReconciler(r reconcile.Reconciler, gvk schema.GroupVersionKind) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		res, err := r.Reconcile(ctx, req)
+		result := metrics.ResultSuccess
+		if err != nil {
+			result = metrics.ResultError
+		}
+		metrics.Reconciles.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind, result).Inc()
+		return res, err
+	})
+}
+
+// NewInstrumentedConnecter returns a managed.ExternalConnecter whose
+// external clients record the execution times and the failures of their
+// operations in the per-kind metrics of the managed resources of the given
+// GroupVersionKind.
+func NewInstrumentedConnecter(c managed.ExternalConnecter, gvk schema.GroupVersionKind) managed.ExternalConnecter {
+	return managed.ExternalConnectorFn(func(ctx context.Context, mg xpresource.Managed) (managed.ExternalClient, error) {
+		ec, err := c.Connect(ctx, mg)
+		if err != nil {
+			return nil, err
+		}
+		return &instrumentedExternal{ExternalClient: ec, gvk: gvk}, nil
+	})
+}
+
+// instrumentedExternal is a managed.ExternalClient recording the execution
+// times and the failures of the operations of the wrapped client.
+type instrumentedExternal struct {
+	managed.ExternalClient
+	gvk schema.GroupVersionKind
+}
+
+// observe records the execution time of an operation that started at the
+// given time and its failure if err is not nil.
+func (e *instrumentedExternal) observe(operation string, start time.Time, err error) {
+	metrics.OperationTime.WithLabelValues(e.gvk.Group, e.gvk.Version, e.gvk.Kind, operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.OperationFailures.WithLabelValues(e.gvk.Group, e.gvk.Version, e.gvk.Kind, operation).Inc()
+	}
+}
+
+func (e *instrumentedExternal) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	start := time.Now()
+	obs, err := e.ExternalClient.Observe(ctx, mg)
+	e.observe(metrics.OperationObserve, start, err)
+	return obs, err
+}
+
+func (e *instrumentedExternal) Create(ctx context.Context, mg xpresource.Managed) (managed.ExternalCreation, error) {
+	start := time.Now()
+	c, err := e.ExternalClient.Create(ctx, mg)
+	e.observe(metrics.OperationCreate, start, err)
+	return c, err
+}
+
+func (e *instrumentedExternal) Update(ctx context.Context, mg xpresource.Managed) (managed.ExternalUpdate, error) {
+	start := time.Now()
+	u, err := e.ExternalClient.Update(ctx, mg)
+	e.observe(metrics.OperationUpdate, start, err)
+	return u, err
+}
+
+func (e *instrumentedExternal) Delete(ctx context.Context, mg xpresource.Managed) error {
+	start := time.Now()
+	err := e.ExternalClient.Delete(ctx, mg)
+	e.observe(metrics.OperationDelete, start, err)
+	return err
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/upbound/upjet/pkg/metrics"
+	"github.com/upbound/upjet/pkg/resource/fake"
+)
+
+func TestInstrumentedReconciler(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   map[string]float64
+	}{
+		"Success": {
+			reason: "A successful reconciliation should be counted with the success result.",
+			want:   map[string]float64{metrics.ResultSuccess: 1, metrics.ResultError: 0},
+		},
+		"Error": {
+			reason: "A failed reconciliation should be counted with the error result.",
+			err:    errors.New("boom"),
+			want:   map[string]float64{metrics.ResultSuccess: 0, metrics.ResultError: 1},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gvk := schema.GroupVersionKind{Group: "reconciles.upbound.io", Version: "v1beta1", Kind: name}
+			metrics.RegisterKind(gvk)
+			r := NewInstrumentedReconciler(reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{}, tc.err
+			}), gvk)
+			_, _ = r.Reconcile(context.TODO(), reconcile.Request{})
+			got := map[string]float64{}
+			for result := range tc.want {
+				got[result] = testutil.ToFloat64(metrics.Reconciles.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind, result))
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want reconciles, +got reconciles:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInstrumentedConnecter(t *testing.T) {
+	errBoom := errors.New("boom")
+	gvk := schema.GroupVersionKind{Group: "operations.upbound.io", Version: "v1beta1", Kind: "Thing"}
+	metrics.RegisterKind(gvk)
+	c := NewInstrumentedConnecter(managed.ExternalConnectorFn(func(context.Context, xpresource.Managed) (managed.ExternalClient, error) {
+		return managed.ExternalClientFns{
+			ObserveFn: func(context.Context, xpresource.Managed) (managed.ExternalObservation, error) {
+				return managed.ExternalObservation{}, nil
+			},
+			CreateFn: func(context.Context, xpresource.Managed) (managed.ExternalCreation, error) {
+				return managed.ExternalCreation{}, errBoom
+			},
+			UpdateFn: func(context.Context, xpresource.Managed) (managed.ExternalUpdate, error) {
+				return managed.ExternalUpdate{}, nil
+			},
+			DeleteFn: func(context.Context, xpresource.Managed) error {
+				return errBoom
+			},
+		}, nil
+	}), gvk)
+	mg := &fake.Terraformed{}
+	ec, err := c.Connect(context.TODO(), mg)
+	if err != nil {
+		t.Fatalf("Connect(...): %v", err)
+	}
+	_, _ = ec.Observe(context.TODO(), mg)
+	_, _ = ec.Create(context.TODO(), mg)
+	_, _ = ec.Update(context.TODO(), mg)
+	_ = ec.Delete(context.TODO(), mg)
+
+	want := map[string]float64{
+		metrics.OperationObserve: 0,
+		metrics.OperationCreate:  1,
+		metrics.OperationUpdate:  0,
+		metrics.OperationDelete:  1,
+	}
+	got := map[string]float64{}
+	for op := range want {
+		got[op] = testutil.ToFloat64(metrics.OperationFailures.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind, op))
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nThe failed operations should be counted.\n-want failures, +got failures:\n%s", diff)
+	}
+}
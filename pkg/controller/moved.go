@@ -0,0 +1,102 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	errGetMoved        = "cannot get the managed resource of the previous Kind"
+	errGetMoveTarget   = "cannot get the managed resource of the current Kind"
+	errCreateMoved     = "cannot create the managed resource of the current Kind"
+	errDeleteMoved     = "cannot delete the managed resource of the current Kind"
+	errSetOrphanPolicy = "cannot set the deletion policy of the managed resource of the previous Kind"
+	errReleaseMoved    = "cannot release the managed resource of the previous Kind"
+	errDeletePrevious  = "cannot delete the managed resource of the previous Kind"
+)
+
+type mover struct {
+	kube     client.Client
+	from, to schema.GroupVersionKind
+}
+
+// NewMover returns a reconcile.Reconciler that moves the managed resources
+// of the given previous Kind to the given current Kind. A managed resource
+// of the current Kind is created with the same name, labels, annotations
+// and spec, so that it adopts the external resource via its external name.
+// Then, the managed resource of the previous Kind is deleted with its
+// external resource orphaned. If the managed resource of the previous Kind
+// is being deleted, the managed resource of the current Kind is deleted,
+// too, so that the deletion policy is honored by the current Kind.
+func NewMover(kube client.Client, from, to schema.GroupVersionKind) reconcile.Reconciler {
+	return &mover{
+		kube: kube,
+		from: from,
+		to:   to,
+	}
+}
+
+func (m *mover) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	from := &unstructured.Unstructured{}
+	from.SetGroupVersionKind(m.from)
+	if err := m.kube.Get(ctx, req.NamespacedName, from); err != nil {
+		return reconcile.Result{}, errors.Wrap(xpresource.IgnoreNotFound(err), errGetMoved)
+	}
+	to := &unstructured.Unstructured{}
+	to.SetGroupVersionKind(m.to)
+	err := m.kube.Get(ctx, req.NamespacedName, to)
+	switch {
+	case xpresource.IgnoreNotFound(err) != nil:
+		return reconcile.Result{}, errors.Wrap(err, errGetMoveTarget)
+	case err != nil:
+		to = moved(from, m.to)
+		if err := m.kube.Create(ctx, to); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errCreateMoved)
+		}
+	}
+	if meta.WasDeleted(from) && !meta.WasDeleted(to) {
+		if err := m.kube.Delete(ctx, to); xpresource.IgnoreNotFound(err) != nil {
+			return reconcile.Result{}, errors.Wrap(err, errDeleteMoved)
+		}
+	}
+	if err := unstructured.SetNestedField(from.Object, string(xpv1.DeletionOrphan), "spec", "deletionPolicy"); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errSetOrphanPolicy)
+	}
+	meta.RemoveFinalizer(from, managed.FinalizerName)
+	if err := m.kube.Update(ctx, from); err != nil {
+		return reconcile.Result{}, errors.Wrap(xpresource.IgnoreNotFound(err), errReleaseMoved)
+	}
+	if meta.WasDeleted(from) {
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{}, errors.Wrap(xpresource.IgnoreNotFound(m.kube.Delete(ctx, from)), errDeletePrevious)
+}
+
+// moved returns a managed resource of the given Kind with the name, labels,
+// annotations and spec of the given managed resource.
+func moved(from *unstructured.Unstructured, gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	to := &unstructured.Unstructured{Object: map[string]any{}}
+	to.SetGroupVersionKind(gvk)
+	to.SetName(from.GetName())
+	to.SetNamespace(from.GetNamespace())
+	to.SetLabels(from.GetLabels())
+	to.SetAnnotations(from.GetAnnotations())
+	if spec, ok := from.Object["spec"]; ok {
+		to.Object["spec"] = runtime.DeepCopyJSONValue(spec)
+	}
+	return to
+}
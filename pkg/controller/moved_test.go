@@ -0,0 +1,132 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestMover(t *testing.T) {
+	errBoom := errors.New("boom")
+	from := schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"}
+	to := schema.GroupVersionKind{Group: "storage.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"}
+	now := metav1.Now()
+	previous := func(deleted bool) func(obj client.Object) error {
+		return func(obj client.Object) error {
+			u := obj.(*unstructured.Unstructured)
+			if u.GroupVersionKind() != from {
+				return kerrors.NewNotFound(schema.GroupResource{}, "bucket")
+			}
+			u.SetName("bucket")
+			u.SetAnnotations(map[string]string{"crossplane.io/external-name": "my-bucket"})
+			u.SetFinalizers([]string{managed.FinalizerName})
+			if deleted {
+				u.SetDeletionTimestamp(&now)
+			}
+			u.Object["spec"] = map[string]any{"deletionPolicy": "Delete", "forProvider": map[string]any{"region": "us-east-1"}}
+			return nil
+		}
+	}
+	type calls struct {
+		created, deleted []string
+		updated          map[string]any
+	}
+	type want struct {
+		calls calls
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		get    test.ObjectFn
+		err    error
+		want   want
+	}{
+		"NotFound": {
+			reason: "Nothing should be done if the managed resource of the previous Kind does not exist.",
+			get: func(_ client.Object) error {
+				return kerrors.NewNotFound(schema.GroupResource{}, "bucket")
+			},
+		},
+		"GetError": {
+			reason: "An error should be returned if the managed resource of the previous Kind cannot be fetched.",
+			get: func(_ client.Object) error {
+				return errBoom
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetMoved),
+			},
+		},
+		"Moved": {
+			reason: "The managed resource of the current Kind should be created and the previous one should be deleted with its external resource orphaned.",
+			get:    previous(false),
+			want: want{
+				calls: calls{
+					created: []string{to.Kind + "." + to.Group},
+					deleted: []string{from.Kind + "." + from.Group},
+					updated: map[string]any{"deletionPolicy": "Orphan", "forProvider": map[string]any{"region": "us-east-1"}},
+				},
+			},
+		},
+		"Deleted": {
+			reason: "The managed resource of the current Kind should be deleted if the previous one is being deleted.",
+			get:    previous(true),
+			want: want{
+				calls: calls{
+					created: []string{to.Kind + "." + to.Group},
+					deleted: []string{to.Kind + "." + to.Group},
+					updated: map[string]any{"deletionPolicy": "Orphan", "forProvider": map[string]any{"region": "us-east-1"}},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := calls{}
+			kube := &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, tc.get),
+				MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+					u := obj.(*unstructured.Unstructured)
+					if u.GetAnnotations()["crossplane.io/external-name"] != "my-bucket" || u.Object["spec"] == nil {
+						t.Errorf("\n%s\nReconcile(...): the managed resource of the current Kind is created without the external name or the spec", tc.reason)
+					}
+					got.created = append(got.created, u.GroupVersionKind().GroupKind().String())
+					return nil
+				},
+				MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					u := obj.(*unstructured.Unstructured)
+					if len(u.GetFinalizers()) != 0 {
+						t.Errorf("\n%s\nReconcile(...): the finalizer of the managed resource of the previous Kind is not removed", tc.reason)
+					}
+					got.updated = u.Object["spec"].(map[string]any)
+					return nil
+				},
+				MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+					got.deleted = append(got.deleted, obj.GetObjectKind().GroupVersionKind().GroupKind().String())
+					return nil
+				},
+			}
+			_, err := NewMover(kube, from, to).Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "bucket"}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.calls, got, cmp.AllowUnexported(calls{})); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want calls, +got calls:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
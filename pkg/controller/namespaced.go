@@ -0,0 +1,89 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errResolveReferences        = "cannot resolve references"
+	errUpdateManaged            = "cannot update the managed resource"
+	errUpdateConnectionSecretNS = "cannot update the namespace of the connection secret reference"
+)
+
+// namespacedReader is a client.Reader confined to a namespace. The
+// namespaces of the requests are ignored for the cluster-scoped objects.
+type namespacedReader struct {
+	client.Reader
+	namespace string
+}
+
+func (r namespacedReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	key.Namespace = r.namespace
+	return r.Reader.Get(ctx, key, obj, opts...)
+}
+
+func (r namespacedReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return r.Reader.List(ctx, list, append(opts, client.InNamespace(r.namespace))...)
+}
+
+type namespacedReferenceResolver struct {
+	kube client.Client
+}
+
+// NewNamespacedReferenceResolver returns a managed.ReferenceResolver that
+// resolves the references of a namespaced managed resource only to the
+// managed resources in its namespace. The references to the cluster-scoped
+// managed resources are resolved as usual.
+func NewNamespacedReferenceResolver(kube client.Client) managed.ReferenceResolver {
+	return &namespacedReferenceResolver{kube: kube}
+}
+
+func (r *namespacedReferenceResolver) ResolveReferences(ctx context.Context, mg xpresource.Managed) error {
+	rr, ok := mg.(interface {
+		ResolveReferences(context.Context, client.Reader) error
+	})
+	if !ok {
+		return nil
+	}
+	existing := mg.DeepCopyObject()
+	if err := rr.ResolveReferences(ctx, namespacedReader{Reader: r.kube, namespace: mg.GetNamespace()}); err != nil {
+		return errors.Wrap(err, errResolveReferences)
+	}
+	if cmp.Equal(existing, mg) {
+		return nil
+	}
+	return errors.Wrap(r.kube.Update(ctx, mg), errUpdateManaged)
+}
+
+type connectionSecretNamespacer struct {
+	kube client.Client
+}
+
+// NewConnectionSecretNamespacer returns a managed.Initializer that sets the
+// namespace of the connection secret reference of a namespaced managed
+// resource to its namespace, so that the connection details are only
+// published into the namespace of the managed resource.
+func NewConnectionSecretNamespacer(kube client.Client) managed.Initializer {
+	return &connectionSecretNamespacer{kube: kube}
+}
+
+func (n *connectionSecretNamespacer) Initialize(ctx context.Context, mg xpresource.Managed) error {
+	ref := mg.GetWriteConnectionSecretToReference()
+	if mg.GetNamespace() == "" || ref == nil || ref.Namespace == mg.GetNamespace() {
+		return nil
+	}
+	ref = ref.DeepCopy()
+	ref.Namespace = mg.GetNamespace()
+	mg.SetWriteConnectionSecretToReference(ref)
+	return errors.Wrap(n.kube.Update(ctx, mg), errUpdateConnectionSecretNS)
+}
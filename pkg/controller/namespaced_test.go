@@ -0,0 +1,111 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestConnectionSecretNamespacer(t *testing.T) {
+	errBoom := errors.New("boom")
+	newManaged := func(namespace string, ref *xpv1.SecretReference) *fake.Managed {
+		mg := &fake.Managed{}
+		mg.SetNamespace(namespace)
+		mg.SetWriteConnectionSecretToReference(ref)
+		return mg
+	}
+	type want struct {
+		ref *xpv1.SecretReference
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		mg     *fake.Managed
+		want   want
+	}{
+		"ClusterScoped": {
+			reason: "The connection secret reference of a cluster-scoped managed resource should not be changed.",
+			mg:     newManaged("", &xpv1.SecretReference{Name: "conn", Namespace: "team-b"}),
+			want: want{
+				ref: &xpv1.SecretReference{Name: "conn", Namespace: "team-b"},
+			},
+		},
+		"SameNamespace": {
+			reason: "The connection secret reference in the namespace of the managed resource should not be changed.",
+			mg:     newManaged("team-a", &xpv1.SecretReference{Name: "conn", Namespace: "team-a"}),
+			want: want{
+				ref: &xpv1.SecretReference{Name: "conn", Namespace: "team-a"},
+			},
+		},
+		"OtherNamespace": {
+			reason: "The connection secret reference should be confined to the namespace of the managed resource.",
+			kube:   &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			mg:     newManaged("team-a", &xpv1.SecretReference{Name: "conn", Namespace: "team-b"}),
+			want: want{
+				ref: &xpv1.SecretReference{Name: "conn", Namespace: "team-a"},
+			},
+		},
+		"UpdateError": {
+			reason: "An error should be returned if the managed resource cannot be updated.",
+			kube:   &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+			mg:     newManaged("team-a", &xpv1.SecretReference{Name: "conn"}),
+			want: want{
+				ref: &xpv1.SecretReference{Name: "conn", Namespace: "team-a"},
+				err: errors.Wrap(errBoom, errUpdateConnectionSecretNS),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewConnectionSecretNamespacer(tc.kube).Initialize(context.TODO(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nInitialize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ref, tc.mg.GetWriteConnectionSecretToReference()); diff != "" {
+				t.Errorf("\n%s\nInitialize(...): -want reference, +got reference:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNamespacedReader(t *testing.T) {
+	var getNamespace string
+	var listOpts client.ListOptions
+	r := namespacedReader{
+		Reader: &test.MockClient{
+			MockGet: func(_ context.Context, key client.ObjectKey, _ client.Object) error {
+				getNamespace = key.Namespace
+				return nil
+			},
+			MockList: func(_ context.Context, _ client.ObjectList, opts ...client.ListOption) error {
+				listOpts.ApplyOptions(opts)
+				return nil
+			},
+		},
+		namespace: "team-a",
+	}
+	if err := r.Get(context.TODO(), client.ObjectKey{Namespace: "team-b", Name: "vpc"}, &fake.Managed{}); err != nil {
+		t.Fatalf("Get(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("team-a", getNamespace); diff != "" {
+		t.Errorf("Get(...): -want namespace, +got namespace:\n%s", diff)
+	}
+	if err := r.List(context.TODO(), &unstructured.UnstructuredList{}, client.MatchingLabels{"app": "test"}); err != nil {
+		t.Fatalf("List(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("team-a", listOpts.Namespace); diff != "" {
+		t.Errorf("List(...): -want namespace, +got namespace:\n%s", diff)
+	}
+}
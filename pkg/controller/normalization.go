@@ -0,0 +1,85 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/resource/json"
+)
+
+const (
+	errFmtExpandMapPath = "cannot expand the map field path %q"
+	errCopyParams       = "cannot copy the parameters to normalize"
+)
+
+// normalizeMaps returns a copy of the given parameters or Terraform state in
+// which the map fields are normalized with the map normalizations of the
+// given resource configuration.
+func normalizeMaps(cfg *config.Resource, params map[string]any) (map[string]any, error) {
+	buff, err := json.JSParser.Marshal(params)
+	if err != nil {
+		return nil, errors.Wrap(err, errCopyParams)
+	}
+	normalized := map[string]any{}
+	if err := json.JSParser.Unmarshal(buff, &normalized); err != nil {
+		return nil, errors.Wrap(err, errCopyParams)
+	}
+	pv := fieldpath.Pave(normalized)
+	for _, p := range mapNormalizationPaths(cfg) {
+		expanded, err := pv.ExpandWildcards(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtExpandMapPath, p)
+		}
+		for _, e := range expanded {
+			m, ok := mapValue(pv, e)
+			if !ok {
+				continue
+			}
+			if err := pv.SetValue(e, cfg.MapNormalizations[p].Normalize(m)); err != nil {
+				return nil, errors.Wrapf(err, "cannot set the normalized map field %q", e)
+			}
+		}
+	}
+	return normalized, nil
+}
+
+// normalizedAttributes returns the JSON representation of the given
+// Terraform state with the map fields normalized, which is late-initialized
+// so that the insignificant map entries are not copied into the parameters.
+func normalizedAttributes(cfg *config.Resource, tfstate map[string]any) ([]byte, error) {
+	normalized, err := normalizeMaps(cfg, tfstate)
+	if err != nil {
+		return nil, err
+	}
+	return json.JSParser.Marshal(normalized)
+}
+
+// mapValue returns the map value at the given path, or an empty map and
+// false if there is no map at the path.
+func mapValue(pv *fieldpath.Paved, path string) (map[string]any, bool) {
+	v, err := pv.GetValue(path)
+	if err != nil {
+		return map[string]any{}, false
+	}
+	m, ok := v.(map[string]any)
+	if !ok || m == nil {
+		return map[string]any{}, false
+	}
+	return m, true
+}
+
+func mapNormalizationPaths(cfg *config.Resource) []string {
+	paths := make([]string, 0, len(cfg.MapNormalizations))
+	for p := range cfg.MapNormalizations {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -6,6 +6,7 @@ package controller
 
 import (
 	"crypto/tls"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -15,6 +16,10 @@ import (
 	"github.com/upbound/upjet/pkg/terraform"
 )
 
+const (
+	defaultReconcileTimeout = 3 * time.Minute
+)
+
 // Options contains incriminating options for a given Upjet controller instance.
 type Options struct {
 	controller.Options
@@ -39,6 +44,12 @@ type Options struct {
 
 	// ESSOptions for External Secret Stores.
 	ESSOptions *ESSOptions
+
+	// StartWebhooks enables the conversion webhooks of the managed resources
+	// served in multiple API versions.
+	// The webhook server of the manager can be configured with
+	// NewWebhookServer if WebhookTLSAvailable.
+	StartWebhooks bool
 }
 
 // ESSOptions for External Secret Stores.
@@ -46,3 +57,26 @@ type ESSOptions struct {
 	TLSConfig     *tls.Config
 	TLSSecretName *string
 }
+
+// ReconcileTimeout returns the reconciliation timeout of the managed
+// resources of the given resource configuration. A reconciliation refreshes
+// the resource and, unless the resource is configured to be reconciled
+// asynchronously, runs the longest of the create, update and delete
+// operations, each bounded by its configured Terraform timeout. The returned
+// timeout is never shorter than the default reconciliation timeout.
+func ReconcileTimeout(cfg *config.Resource) time.Duration {
+	t := cfg.OperationTimeouts.Read
+	if !cfg.UseAsync {
+		var op time.Duration
+		for _, o := range []time.Duration{cfg.OperationTimeouts.Create, cfg.OperationTimeouts.Update, cfg.OperationTimeouts.Delete} {
+			if o > op {
+				op = o
+			}
+		}
+		t += op
+	}
+	if t > defaultReconcileTimeout {
+		return t
+	}
+	return defaultReconcileTimeout
+}
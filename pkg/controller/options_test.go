@@ -0,0 +1,68 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+func TestReconcileTimeout(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cfg    *config.Resource
+		want   time.Duration
+	}{
+		"NoTimeouts": {
+			reason: "The default timeout should be returned if no timeouts are configured.",
+			cfg:    &config.Resource{},
+			want:   defaultReconcileTimeout,
+		},
+		"ShortTimeouts": {
+			reason: "The default timeout should be returned if the configured timeouts are shorter.",
+			cfg: &config.Resource{
+				OperationTimeouts: config.OperationTimeouts{
+					Read:   time.Second,
+					Create: time.Minute,
+				},
+			},
+			want: defaultReconcileTimeout,
+		},
+		"Sync": {
+			reason: "The refresh and the longest operation should fit in the timeout of a synchronous resource.",
+			cfg: &config.Resource{
+				OperationTimeouts: config.OperationTimeouts{
+					Read:   5 * time.Minute,
+					Create: 30 * time.Minute,
+					Update: 20 * time.Minute,
+					Delete: 40 * time.Minute,
+				},
+			},
+			want: 45 * time.Minute,
+		},
+		"Async": {
+			reason: "Only the refresh should fit in the timeout of an asynchronous resource.",
+			cfg: &config.Resource{
+				UseAsync: true,
+				OperationTimeouts: config.OperationTimeouts{
+					Read:   5 * time.Minute,
+					Create: 30 * time.Minute,
+				},
+			},
+			want: 5 * time.Minute,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ReconcileTimeout(tc.cfg)); diff != "" {
+				t.Errorf("\n%s\nReconcileTimeout(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
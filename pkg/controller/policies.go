@@ -0,0 +1,84 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+const (
+	errGetManaged         = "cannot get the managed resource"
+	errSetDefaultPolicies = "cannot set the default management policies of the managed resource"
+)
+
+// SupportedManagementPolicies returns the management policies supported by
+// the given resource configuration, including its default management
+// policies, in the form accepted by the managed reconciler. It returns nil
+// if the configuration does not restrict the supported management policies.
+func SupportedManagementPolicies(cfg *config.Resource) []sets.Set[xpv1.ManagementAction] {
+	if len(cfg.ManagementPolicies.Supported) == 0 {
+		return nil
+	}
+	supported := make([]sets.Set[xpv1.ManagementAction], 0, len(cfg.ManagementPolicies.Supported)+1)
+	hasDefault := len(cfg.ManagementPolicies.Default) == 0
+	d := sets.New[xpv1.ManagementAction](cfg.ManagementPolicies.Default...)
+	for _, p := range cfg.ManagementPolicies.Supported {
+		s := sets.New[xpv1.ManagementAction](p...)
+		hasDefault = hasDefault || s.Equal(d)
+		supported = append(supported, s)
+	}
+	if !hasDefault {
+		supported = append(supported, d)
+	}
+	return supported
+}
+
+type managementPoliciesDefaulter struct {
+	kube       client.Client
+	newManaged func() xpresource.Managed
+	policies   xpv1.ManagementPolicies
+	reconciler reconcile.Reconciler
+}
+
+// NewManagementPoliciesDefaulter returns a reconcile.Reconciler that sets
+// the given default management policies to the managed resources with the
+// default management policies of the CRD, i.e. ["*"], before they are
+// reconciled by the given reconciler. The managed resources being deleted
+// are left as is.
+func NewManagementPoliciesDefaulter(kube client.Client, newManaged func() xpresource.Managed, policies xpv1.ManagementPolicies, r reconcile.Reconciler) reconcile.Reconciler {
+	return &managementPoliciesDefaulter{
+		kube:       kube,
+		newManaged: newManaged,
+		policies:   policies,
+		reconciler: r,
+	}
+}
+
+func (d *managementPoliciesDefaulter) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	mg := d.newManaged()
+	if err := d.kube.Get(ctx, req.NamespacedName, mg); err != nil {
+		if xpresource.IgnoreNotFound(err) == nil {
+			return d.reconciler.Reconcile(ctx, req)
+		}
+		return reconcile.Result{}, errors.Wrap(err, errGetManaged)
+	}
+	p := mg.GetManagementPolicies()
+	if len(d.policies) == 0 || mg.GetDeletionTimestamp() != nil ||
+		!sets.New[xpv1.ManagementAction](p...).Equal(sets.New[xpv1.ManagementAction](xpv1.ManagementActionAll)) {
+		return d.reconciler.Reconcile(ctx, req)
+	}
+	mg.SetManagementPolicies(d.policies)
+	// the update triggers the reconciliation with the default policies
+	return reconcile.Result{}, errors.Wrap(d.kube.Update(ctx, mg), errSetDefaultPolicies)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+func TestSupportedManagementPolicies(t *testing.T) {
+	observe := xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+	observeDelete := xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionDelete}
+	cases := map[string]struct {
+		reason   string
+		policies config.ManagementPolicies
+		want     []sets.Set[xpv1.ManagementAction]
+	}{
+		"NotRestricted": {
+			reason:   "No supported policies should be returned if they are not restricted.",
+			policies: config.ManagementPolicies{Default: observe},
+		},
+		"Supported": {
+			reason:   "The supported policies should be returned.",
+			policies: config.ManagementPolicies{Supported: []xpv1.ManagementPolicies{observe, observeDelete}, Default: observe},
+			want:     []sets.Set[xpv1.ManagementAction]{sets.New(observe...), sets.New(observeDelete...)},
+		},
+		"DefaultNotListed": {
+			reason:   "The default policies should be supported even if they are not listed.",
+			policies: config.ManagementPolicies{Supported: []xpv1.ManagementPolicies{observeDelete}, Default: observe},
+			want:     []sets.Set[xpv1.ManagementAction]{sets.New(observeDelete...), sets.New(observe...)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := SupportedManagementPolicies(&config.Resource{ManagementPolicies: tc.policies})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSupportedManagementPolicies(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestManagementPoliciesDefaulter(t *testing.T) {
+	errBoom := errors.New("boom")
+	observe := xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+	type want struct {
+		policies   xpv1.ManagementPolicies
+		reconciled bool
+		err        error
+	}
+	cases := map[string]struct {
+		reason   string
+		policies xpv1.ManagementPolicies
+		getErr   error
+		want     want
+	}{
+		"DefaultPolicies": {
+			reason:   "The default management policies should be set to a managed resource with the CRD default policies.",
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+			want: want{
+				policies: observe,
+			},
+		},
+		"CustomPolicies": {
+			reason:   "A managed resource with custom management policies should be reconciled as is.",
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionDelete},
+			want: want{
+				reconciled: true,
+			},
+		},
+		"NotFound": {
+			reason: "A managed resource that cannot be found should be passed to the reconciler.",
+			getErr: kerrors.NewNotFound(schema.GroupResource{}, ""),
+			want: want{
+				reconciled: true,
+			},
+		},
+		"GetFailed": {
+			reason: "The error getting the managed resource should be returned.",
+			getErr: errBoom,
+			want: want{
+				err: errors.Wrap(errBoom, errGetManaged),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var updated xpv1.ManagementPolicies
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					if tc.getErr != nil {
+						return tc.getErr
+					}
+					obj.(*fake.Managed).SetManagementPolicies(tc.policies)
+					return nil
+				},
+				MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					updated = obj.(*fake.Managed).GetManagementPolicies()
+					return nil
+				},
+			}
+			reconciled := false
+			r := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				reconciled = true
+				return reconcile.Result{}, nil
+			})
+			d := NewManagementPoliciesDefaulter(kube, func() xpresource.Managed { return &fake.Managed{} }, observe, r)
+			_, err := d.Reconcile(context.TODO(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nReconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.policies, updated); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want updated policies, +got updated policies:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reconciled, reconciled); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want reconciled, +got reconciled:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
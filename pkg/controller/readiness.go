@@ -0,0 +1,32 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+const (
+	msgNotReady = "the external resource exists but is not ready yet"
+)
+
+// readyCondition returns the Ready condition of the managed resource whose
+// external resource has the given observed Terraform state. The resource is
+// ready as soon as it exists unless a readiness check is configured.
+func readyCondition(cfg *config.Resource, tfstate map[string]any) (xpv1.Condition, error) {
+	if cfg.ReadinessCheckFn == nil {
+		return xpv1.Available(), nil
+	}
+	ready, err := cfg.ReadinessCheckFn(tfstate)
+	if err != nil {
+		return xpv1.Condition{}, err
+	}
+	if !ready {
+		return xpv1.Unavailable().WithMessage(msgNotReady), nil
+	}
+	return xpv1.Available(), nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+func TestReadyCondition(t *testing.T) {
+	type args struct {
+		fn      config.ReadinessCheckFn
+		tfstate map[string]any
+	}
+	type want struct {
+		condition xpv1.Condition
+		err       error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoReadinessCheck": {
+			reason: "The resource should be ready as soon as it exists if no readiness check is configured.",
+			args: args{
+				tfstate: map[string]any{"status": "CREATING"},
+			},
+			want: want{
+				condition: xpv1.Available(),
+			},
+		},
+		"Ready": {
+			reason: "The resource should be ready if the attribute has one of the expected values.",
+			args: args{
+				fn:      config.ReadyWhenAttribute("endpoint[0].status", "ACTIVE", "AVAILABLE"),
+				tfstate: map[string]any{"endpoint": []any{map[string]any{"status": "AVAILABLE"}}},
+			},
+			want: want{
+				condition: xpv1.Available(),
+			},
+		},
+		"NotReady": {
+			reason: "The resource should not be ready if the attribute does not have one of the expected values.",
+			args: args{
+				fn:      config.ReadyWhenAttribute("status", "ACTIVE"),
+				tfstate: map[string]any{"status": "CREATING"},
+			},
+			want: want{
+				condition: xpv1.Unavailable().WithMessage(msgNotReady),
+			},
+		},
+		"AttributeNotFound": {
+			reason: "The resource should not be ready if the attribute is not observed yet.",
+			args: args{
+				fn:      config.ReadyWhenAttribute("status", "ACTIVE"),
+				tfstate: map[string]any{},
+			},
+			want: want{
+				condition: xpv1.Unavailable().WithMessage(msgNotReady),
+			},
+		},
+		"CheckFailed": {
+			reason: "The error returned by the readiness check should be reported.",
+			args: args{
+				fn: func(_ map[string]any) (bool, error) {
+					return false, errBoom
+				},
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := readyCondition(&config.Resource{ReadinessCheckFn: tc.args.fn}, tc.args.tfstate)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nreadyCondition(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.condition, got, cmpopts.IgnoreTypes(metav1.Time{})); diff != "" {
+				t.Errorf("\n%s\nreadyCondition(...): -want condition, +got condition:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
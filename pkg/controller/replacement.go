@@ -0,0 +1,262 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/resource"
+)
+
+const (
+	errGetObjectMeta   = "cannot get the object metadata"
+	errFmtReplacement  = "changing %s replaces the external resource, set the %s annotation to \"true\" to allow it"
+	annotationValueYes = "true"
+)
+
+// ReplacementValidator is an admission validator rejecting the changes to
+// the ForceNew parameters of the managed resources, which replace their
+// external resources, unless the replacement is allowed with the
+// resource.AnnotationKeyAllowReplacement annotation.
+type ReplacementValidator struct {
+	config *config.Resource
+}
+
+// NewReplacementValidator returns a new ReplacementValidator for the managed
+// resources of the given resource configuration.
+func NewReplacementValidator(cfg *config.Resource) *ReplacementValidator {
+	return &ReplacementValidator{
+		config: cfg,
+	}
+}
+
+// ValidateCreate does nothing.
+func (v *ReplacementValidator) ValidateCreate(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateUpdate rejects the changes to the ForceNew parameters unless the
+// replacement is allowed in the updated object. The changed and the removed
+// ForceNew parameters are rejected, while the unset ones can be set, e.g.
+// by the late-initialization of the controller, unless they're in the
+// elements added to the block lists. The elements of the set blocks are
+// compared regardless of their order, while the ones of the list blocks are
+// compared by their indices like Terraform does.
+func (v *ReplacementValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	m, err := meta.Accessor(newObj)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetObjectMeta)
+	}
+	if m.GetAnnotations()[resource.AnnotationKeyAllowReplacement] == annotationValueYes {
+		return nil, nil
+	}
+	oldPv, err := fieldpath.PaveObject(oldObj)
+	if err != nil {
+		return nil, errors.Wrap(err, errPaveObject)
+	}
+	newPv, err := fieldpath.PaveObject(newObj)
+	if err != nil {
+		return nil, errors.Wrap(err, errPaveObject)
+	}
+	var crdPaths []string
+	for _, p := range forceNewParameters(v.config.TerraformResource, "") {
+		if crdPath, ok := parameterPath(v.config, p); ok {
+			crdPaths = append(crdPaths, crdPath)
+		}
+	}
+	for _, sp := range setBlockPaths(v.config) {
+		sortSetElements(oldPv, sp, crdPaths)
+		sortSetElements(newPv, sp, crdPaths)
+	}
+	var changed []string
+	for _, crdPath := range crdPaths {
+		if valuesChanged(oldPv, values(oldPv, crdPath), values(newPv, crdPath)) {
+			changed = append(changed, crdPath)
+		}
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	return nil, errors.Errorf(errFmtReplacement, strings.Join(changed, ", "), resource.AnnotationKeyAllowReplacement)
+}
+
+// ValidateDelete does nothing.
+func (v *ReplacementValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// forceNewParameters returns the sorted Terraform field paths, e.g.
+// "rule.filter", of the ForceNew parameters of the given resource.
+func forceNewParameters(res *schema.Resource, prefix string) []string {
+	if res == nil {
+		return nil
+	}
+	var paths []string
+	for k, sch := range res.Schema {
+		if !sch.Optional && !sch.Required {
+			continue
+		}
+		if sch.ForceNew {
+			paths = append(paths, prefix+k)
+		}
+		if er, ok := sch.Elem.(*schema.Resource); ok {
+			paths = append(paths, forceNewParameters(er, prefix+k+".")...)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// values returns the values at the expanded paths of the given path with
+// wildcards in the given object.
+func values(pv *fieldpath.Paved, path string) map[string]any {
+	paths, err := pv.ExpandWildcards(path)
+	if err != nil {
+		return nil
+	}
+	result := make(map[string]any, len(paths))
+	for _, p := range paths {
+		if v, err := pv.GetValue(p); err == nil {
+			result[p] = v
+		}
+	}
+	return result
+}
+
+// valuesChanged reports whether any of the given old values are changed or
+// removed in the given new values, or whether any of the new values are in
+// the elements added to the lists of the given old object.
+func valuesChanged(oldPv *fieldpath.Paved, oldValues, newValues map[string]any) bool {
+	for p, ov := range oldValues {
+		nv, ok := newValues[p]
+		if (!ok && ov != nil) || (ok && !cmp.Equal(ov, nv)) {
+			return true
+		}
+	}
+	for p, nv := range newValues {
+		if _, ok := oldValues[p]; !ok && nv != nil && addedToList(oldPv, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// addedToList reports whether the value at the given path, which is not set
+// in the given old object, is in an element added to a list of it, rather
+// than in an object of it, e.g. the parameters or an element of a list.
+func addedToList(oldPv *fieldpath.Paved, path string) bool {
+	segments, err := fieldpath.Parse(path)
+	if err != nil {
+		return false
+	}
+	for i := len(segments) - 1; i > 0; i-- {
+		v, err := oldPv.GetValue(segments[:i].String())
+		if err != nil || v == nil {
+			continue
+		}
+		_, ok := v.([]any)
+		return ok
+	}
+	return false
+}
+
+// setBlockPaths returns the CRD field paths, e.g. "spec.forProvider.rule", of
+// the set blocks in the parameters of the given resource, the nested ones
+// first.
+func setBlockPaths(cfg *config.Resource) []string {
+	var paths []string
+	for _, p := range setBlocks(cfg.TerraformResource, "") {
+		crdPath, ok := parameterPath(cfg, p)
+		// the embedded singleton sets are objects in the CRD.
+		if !ok || !strings.HasSuffix(crdPath, "[*]") {
+			continue
+		}
+		paths = append(paths, strings.TrimSuffix(crdPath, "[*]"))
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		return strings.Count(paths[i], "[*]") > strings.Count(paths[j], "[*]")
+	})
+	return paths
+}
+
+func setBlocks(res *schema.Resource, prefix string) []string {
+	if res == nil {
+		return nil
+	}
+	var paths []string
+	for k, sch := range res.Schema {
+		er, ok := sch.Elem.(*schema.Resource)
+		if !ok || (!sch.Optional && !sch.Required) {
+			continue
+		}
+		if sch.Type == schema.TypeSet {
+			paths = append(paths, prefix+k)
+		}
+		paths = append(paths, setBlocks(er, prefix+k+".")...)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// sortSetElements sorts the elements of the set blocks at the given CRD field
+// path by the values of their ForceNew parameters at the given CRD field
+// paths, so that they're compared regardless of their order.
+func sortSetElements(pv *fieldpath.Paved, path string, forceNewPaths []string) {
+	prefix := path + "[*]."
+	var rels []string
+	for _, p := range forceNewPaths {
+		if strings.HasPrefix(p, prefix) {
+			rels = append(rels, strings.TrimPrefix(p, prefix))
+		}
+	}
+	expanded, err := pv.ExpandWildcards(path)
+	if err != nil || len(rels) == 0 {
+		return
+	}
+	for _, e := range expanded {
+		v, err := pv.GetValue(e)
+		if err != nil {
+			continue
+		}
+		l, ok := v.([]any)
+		if !ok {
+			continue
+		}
+		keys := make(map[int]string, len(l))
+		for i, el := range l {
+			m, _ := el.(map[string]any)
+			ep := fieldpath.Pave(m)
+			k := ""
+			for _, r := range rels {
+				k += fmt.Sprint(values(ep, r)) + "\n"
+			}
+			keys[i] = k
+		}
+		idx := make([]int, len(l))
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.SliceStable(idx, func(i, j int) bool {
+			return keys[idx[i]] < keys[idx[j]]
+		})
+		sorted := make([]any, len(l))
+		for i, j := range idx {
+			sorted[i] = l[j]
+		}
+		_ = pv.SetValue(e, sorted)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/resource"
+)
+
+func TestReplacementValidator(t *testing.T) {
+	type args struct {
+		annotations map[string]string
+		oldParams   map[string]any
+		newParams   map[string]any
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want error
+	}{
+		"NoForceNewChange": {
+			reason: "No error should be returned if the ForceNew parameters are not changed.",
+			args: args{
+				oldParams: map[string]any{"name": "test", "description": "a"},
+				newParams: map[string]any{"name": "test", "description": "b"},
+			},
+		},
+		"ForceNewChange": {
+			reason: "An error should be returned if the ForceNew parameters are changed, including the nested ones.",
+			args: args{
+				oldParams: map[string]any{
+					"name": "test",
+					"rule": []any{map[string]any{"filter": "a"}},
+				},
+				newParams: map[string]any{
+					"name": "other",
+					"rule": []any{map[string]any{"filter": "b"}},
+				},
+			},
+			want: errors.Errorf(errFmtReplacement, "spec.forProvider.name, spec.forProvider.rule[*].filter", resource.AnnotationKeyAllowReplacement),
+		},
+		"ForceNewRemoved": {
+			reason: "An error should be returned if a ForceNew parameter is removed as Terraform replaces the resource to unset it.",
+			args: args{
+				oldParams: map[string]any{"name": "test", "zone": "us-east-1a"},
+				newParams: map[string]any{"name": "test"},
+			},
+			want: errors.Errorf(errFmtReplacement, "spec.forProvider.zone", resource.AnnotationKeyAllowReplacement),
+		},
+		"NestedForceNewRemoved": {
+			reason: "An error should be returned if an element of a block list with a ForceNew parameter is removed.",
+			args: args{
+				oldParams: map[string]any{"name": "test", "rule": []any{map[string]any{"filter": "a"}, map[string]any{"filter": "b"}}},
+				newParams: map[string]any{"name": "test", "rule": []any{map[string]any{"filter": "a"}}},
+			},
+			want: errors.Errorf(errFmtReplacement, "spec.forProvider.rule[*].filter", resource.AnnotationKeyAllowReplacement),
+		},
+		"ElementAdded": {
+			reason: "An error should be returned if an element with a ForceNew parameter is added to a block list.",
+			args: args{
+				oldParams: map[string]any{"name": "test", "rule": []any{map[string]any{"filter": "a"}}},
+				newParams: map[string]any{"name": "test", "rule": []any{map[string]any{"filter": "a"}, map[string]any{"filter": "b"}}},
+			},
+			want: errors.Errorf(errFmtReplacement, "spec.forProvider.rule[*].filter", resource.AnnotationKeyAllowReplacement),
+		},
+		"ListReordered": {
+			reason: "An error should be returned if the elements of a block list with ForceNew parameters are reordered as Terraform compares them by their indices.",
+			args: args{
+				oldParams: map[string]any{"name": "test", "rule": []any{map[string]any{"filter": "a"}, map[string]any{"filter": "b"}}},
+				newParams: map[string]any{"name": "test", "rule": []any{map[string]any{"filter": "b"}, map[string]any{"filter": "a"}}},
+			},
+			want: errors.Errorf(errFmtReplacement, "spec.forProvider.rule[*].filter", resource.AnnotationKeyAllowReplacement),
+		},
+		"SetReordered": {
+			reason: "No error should be returned if the elements of a set block with ForceNew parameters are reordered.",
+			args: args{
+				oldParams: map[string]any{"name": "test", "target": []any{map[string]any{"address": "a", "port": float64(1)}, map[string]any{"address": "b", "port": float64(2)}}},
+				newParams: map[string]any{"name": "test", "target": []any{map[string]any{"address": "b", "port": float64(3)}, map[string]any{"address": "a", "port": float64(1)}}},
+			},
+		},
+		"SetElementChanged": {
+			reason: "An error should be returned if a ForceNew parameter of an element of a set block is changed.",
+			args: args{
+				oldParams: map[string]any{"name": "test", "target": []any{map[string]any{"address": "a"}, map[string]any{"address": "b"}}},
+				newParams: map[string]any{"name": "test", "target": []any{map[string]any{"address": "b"}, map[string]any{"address": "c"}}},
+			},
+			want: errors.Errorf(errFmtReplacement, "spec.forProvider.target[*].address", resource.AnnotationKeyAllowReplacement),
+		},
+		"BlockLateInitialized": {
+			reason: "No error should be returned if an unset block list with ForceNew parameters is set, e.g. by the late-initialization.",
+			args: args{
+				oldParams: map[string]any{"name": "test"},
+				newParams: map[string]any{"name": "test", "rule": []any{map[string]any{"filter": "a"}}},
+			},
+		},
+		"ForceNewLateInitialized": {
+			reason: "No error should be returned if an unset ForceNew parameter is set, e.g. by the late-initialization.",
+			args: args{
+				oldParams: map[string]any{"name": "test"},
+				newParams: map[string]any{"name": "test", "zone": "us-east-1a"},
+			},
+		},
+		"NestedForceNewLateInitialized": {
+			reason: "No error should be returned if an unset nested ForceNew parameter is set.",
+			args: args{
+				oldParams: map[string]any{"name": "test", "rule": []any{map[string]any{}}},
+				newParams: map[string]any{"name": "test", "rule": []any{map[string]any{"filter": "a"}}},
+			},
+		},
+		"LateInitializedForceNewChange": {
+			reason: "An error should be returned if a late-initialized ForceNew parameter is changed.",
+			args: args{
+				oldParams: map[string]any{"name": "test", "zone": "us-east-1a"},
+				newParams: map[string]any{"name": "test", "zone": "us-east-1b"},
+			},
+			want: errors.Errorf(errFmtReplacement, "spec.forProvider.zone", resource.AnnotationKeyAllowReplacement),
+		},
+		"ReplacementAllowed": {
+			reason: "No error should be returned if the replacement is allowed with the annotation.",
+			args: args{
+				annotations: map[string]string{resource.AnnotationKeyAllowReplacement: "true"},
+				oldParams:   map[string]any{"name": "test"},
+				newParams:   map[string]any{"name": "other"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":        {Type: schema.TypeString, Required: true, ForceNew: true},
+						"description": {Type: schema.TypeString, Optional: true},
+						"zone":        {Type: schema.TypeString, Optional: true, Computed: true, ForceNew: true},
+						"arn":         {Type: schema.TypeString, Computed: true, ForceNew: true},
+						"rule": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"filter": {Type: schema.TypeString, Optional: true, ForceNew: true},
+								},
+							},
+						},
+						"target": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"address": {Type: schema.TypeString, Required: true, ForceNew: true},
+									"port":    {Type: schema.TypeInt, Optional: true},
+								},
+							},
+						},
+					},
+				},
+			}
+			newObj := func(params map[string]any) *unstructured.Unstructured {
+				u := &unstructured.Unstructured{Object: map[string]any{
+					"spec": map[string]any{
+						"forProvider": params,
+					},
+				}}
+				u.SetAnnotations(tc.args.annotations)
+				return u
+			}
+			_, err := NewReplacementValidator(cfg).ValidateUpdate(context.TODO(), newObj(tc.args.oldParams), newObj(tc.args.newParams))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateUpdate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ValidatorChain is an admission validator running the chained validators
+// in order, which is needed as a webhook is registered with one validator.
+// The warnings of all the validators are returned together with the first
+// error returned.
+type ValidatorChain []admission.CustomValidator
+
+// NewValidatorChain returns a new ValidatorChain of the given validators.
+func NewValidatorChain(validators ...admission.CustomValidator) ValidatorChain {
+	return validators
+}
+
+// ValidateCreate runs the chained validators for the created object.
+func (c ValidatorChain) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return c.validate(func(v admission.CustomValidator) (admission.Warnings, error) {
+		return v.ValidateCreate(ctx, obj)
+	})
+}
+
+// ValidateUpdate runs the chained validators for the updated object.
+func (c ValidatorChain) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return c.validate(func(v admission.CustomValidator) (admission.Warnings, error) {
+		return v.ValidateUpdate(ctx, oldObj, newObj)
+	})
+}
+
+// ValidateDelete runs the chained validators for the deleted object.
+func (c ValidatorChain) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return c.validate(func(v admission.CustomValidator) (admission.Warnings, error) {
+		return v.ValidateDelete(ctx, obj)
+	})
+}
+
+func (c ValidatorChain) validate(fn func(v admission.CustomValidator) (admission.Warnings, error)) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	for _, v := range c {
+		w, err := fn(v)
+		warnings = append(warnings, w...)
+		if err != nil {
+			return warnings, err
+		}
+	}
+	return warnings, nil
+}
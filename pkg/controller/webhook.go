@@ -0,0 +1,53 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const (
+	// DefaultWebhookTLSCertDir is the directory in which the Crossplane
+	// package manager mounts the TLS certificate and key of the webhook
+	// server of the provider.
+	DefaultWebhookTLSCertDir = "/tls/server"
+
+	tlsCertFile = "tls.crt"
+	tlsKeyFile  = "tls.key"
+)
+
+// WebhookTLSAvailable returns true if the TLS certificate and key of the
+// webhook server exist in the given directory, in which case the webhooks
+// can be started with Options.StartWebhooks.
+func WebhookTLSAvailable(certDir string) bool {
+	for _, f := range []string{tlsCertFile, tlsKeyFile} {
+		if _, err := os.Stat(filepath.Join(certDir, f)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// NewWebhookServer returns a webhook server serving the conversion and the
+// admission webhooks of the managed resources on the given port with the
+// TLS certificate and key in the given directory, which are reloaded when
+// they are rotated.
+func NewWebhookServer(certDir string, port int) webhook.Server {
+	return webhook.NewServer(webhook.Options{
+		Port:     port,
+		CertDir:  certDir,
+		CertName: tlsCertFile,
+		KeyName:  tlsKeyFile,
+		TLSOpts: []func(*tls.Config){
+			func(c *tls.Config) {
+				c.MinVersion = tls.VersionTLS12
+			},
+		},
+	})
+}
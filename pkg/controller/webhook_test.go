@@ -0,0 +1,47 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWebhookTLSAvailable(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		files  []string
+		want   bool
+	}{
+		"Available": {
+			reason: "The TLS should be available if both the certificate and the key exist.",
+			files:  []string{"tls.crt", "tls.key"},
+			want:   true,
+		},
+		"NoKey": {
+			reason: "The TLS should not be available if the key does not exist.",
+			files:  []string{"tls.crt"},
+		},
+		"NoFiles": {
+			reason: "The TLS should not be available if the directory is empty.",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tc.files {
+				if err := os.WriteFile(filepath.Join(dir, f), []byte("data"), 0600); err != nil {
+					t.Fatalf("cannot write %s: %v", f, err)
+				}
+			}
+			if diff := cmp.Diff(tc.want, WebhookTLSAvailable(dir)); diff != "" {
+				t.Errorf("\n%s\nWebhookTLSAvailable(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
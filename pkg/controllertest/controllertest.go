@@ -0,0 +1,302 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+// Package controllertest contains a harness for the integration tests of the
+// controllers of the managed resources, which run the controllers against a
+// local Kubernetes API server started by envtest and a fake Terraform CLI.
+package controllertest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	xpcontroller "github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/upbound/upjet/pkg/config"
+	tjcontroller "github.com/upbound/upjet/pkg/controller"
+	"github.com/upbound/upjet/pkg/terraform"
+	"github.com/upbound/upjet/pkg/terraform/fake"
+)
+
+const (
+	defaultTimeout = time.Minute
+	pollInterval   = 250 * time.Millisecond
+	// secretValue is the value of the keys of the Secrets referred by the
+	// sensitive fields of the examples.
+	secretValue = "example"
+)
+
+// SetupFn adds the controller of a managed resource to the manager, i.e. the
+// generated Setup function of the controller package of the resource.
+type SetupFn func(mgr ctrl.Manager, o tjcontroller.Options) error
+
+// Options are the options of a controller test.
+type Options struct {
+	// CRDDirectoryPaths are the paths of the directories of the CRDs
+	// installed to the API server, which include the CRD of the managed
+	// resource.
+	CRDDirectoryPaths []string
+
+	// ExampleFile is the path of the example manifest of the managed
+	// resource, whose first document is the managed resource and the rest
+	// are its dependencies, which are not created.
+	ExampleFile string
+
+	// AddToScheme adds the types of the API version of the managed resource
+	// to a scheme.
+	AddToScheme func(s *runtime.Scheme) error
+
+	// Provider is the configuration of the provider, which contains the
+	// configuration of the managed resource.
+	Provider *config.Provider
+
+	// Timeout is the timeout of each of the creation and the deletion of the
+	// managed resource. Defaults to a minute.
+	Timeout time.Duration
+}
+
+// Run starts a Kubernetes API server with envtest, which requires the
+// binaries of the control plane in the directory set by the
+// KUBEBUILDER_ASSETS environment variable, and a manager with the controller
+// added by the given SetupFn, whose Terraform workspaces run a fake
+// Terraform CLI. It then creates the managed resource of the configured
+// example without its references to the other resources, waits until it's
+// ready and synced, deletes it and waits until it's gone. The Secrets
+// referred by the sensitive fields of the example and the namespaces of the
+// resource and its Secrets are created before the managed resource.
+func Run(t testing.TB, setup SetupFn, o Options) {
+	t.Helper()
+	if o.Timeout == 0 {
+		o.Timeout = defaultTimeout
+	}
+	mg, err := readExample(o.ExampleFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := map[client.ObjectKey]map[string]string{}
+	for _, p := range []string{"forProvider", "initProvider"} {
+		if params, ok := mg.Object["spec"].(map[string]any)[p]; ok {
+			removeReferences(params, secrets)
+		}
+	}
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     o.CRDDirectoryPaths,
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		t.Fatalf("cannot start the test environment: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := env.Stop(); err != nil {
+			t.Errorf("cannot stop the test environment: %v", err)
+		}
+	})
+
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatalf("cannot add the Kubernetes types to the scheme: %v", err)
+	}
+	if err := o.AddToScheme(s); err != nil {
+		t.Fatalf("cannot add the managed resource types to the scheme: %v", err)
+	}
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{Scheme: s, MetricsBindAddress: "0"})
+	if err != nil {
+		t.Fatalf("cannot create the manager: %v", err)
+	}
+	opts := tjcontroller.Options{
+		Options:  xpcontroller.DefaultOptions(),
+		Provider: o.Provider,
+		WorkspaceStore: terraform.NewWorkspaceStore(logging.NewNopLogger(),
+			terraform.WithWorkspaceExecutor(fake.NewExecutor()),
+			terraform.WithDisableInit(true)),
+		SetupFn: func(_ context.Context, _ client.Client, _ xpresource.Managed) (terraform.Setup, error) {
+			return terraform.Setup{}, nil
+		},
+	}
+	opts.PollInterval = time.Second
+	if err := setup(mgr, opts); err != nil {
+		t.Fatalf("cannot set up the controller: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := mgr.Start(ctx); err != nil {
+			t.Errorf("cannot start the manager: %v", err)
+		}
+	}()
+	// the manager is stopped before the test environment.
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		t.Fatalf("cannot create the client: %v", err)
+	}
+	if err := createDependencies(ctx, kube, mg, secrets); err != nil {
+		t.Fatal(err)
+	}
+	if err := kube.Create(ctx, mg); err != nil {
+		t.Fatalf("cannot create the managed resource: %v", err)
+	}
+	key := client.ObjectKeyFromObject(mg)
+	var conditions []any
+	err = wait.PollUntilContextTimeout(ctx, pollInterval, o.Timeout, true, func(ctx context.Context) (bool, error) {
+		if err := kube.Get(ctx, key, mg); err != nil {
+			return false, errors.Wrap(err, "cannot get the managed resource")
+		}
+		conditions, _, _ = unstructured.NestedSlice(mg.Object, "status", "conditions")
+		return isTrue(conditions, "Ready") && isTrue(conditions, "Synced"), nil
+	})
+	if err != nil {
+		t.Fatalf("the managed resource %s is not ready and synced: %v: conditions: %v", key.Name, err, conditions)
+	}
+
+	if err := kube.Delete(ctx, mg); err != nil {
+		t.Fatalf("cannot delete the managed resource: %v", err)
+	}
+	err = wait.PollUntilContextTimeout(ctx, pollInterval, o.Timeout, true, func(ctx context.Context) (bool, error) {
+		err := kube.Get(ctx, key, mg)
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+		conditions, _, _ = unstructured.NestedSlice(mg.Object, "status", "conditions")
+		return false, errors.Wrap(err, "cannot get the managed resource")
+	})
+	if err != nil {
+		t.Fatalf("the managed resource %s is not deleted: %v: conditions: %v", key.Name, err, conditions)
+	}
+}
+
+// readExample returns the first document of the given example manifest.
+func readExample(file string) (*unstructured.Unstructured, error) {
+	b, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read the example manifest")
+	}
+	d := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(b), 4096)
+	for {
+		u := &unstructured.Unstructured{}
+		if err := d.Decode(&u.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.Errorf("no managed resource in the example manifest %s", file)
+			}
+			return nil, errors.Wrap(err, "cannot decode the example manifest")
+		}
+		// the empty documents are skipped.
+		if len(u.Object) == 0 {
+			continue
+		}
+		if _, ok := u.Object["spec"].(map[string]any); !ok {
+			return nil, errors.Errorf("the first resource in the example manifest %s has no spec", file)
+		}
+		return u, nil
+	}
+}
+
+// removeReferences removes the references to and the selectors of the other
+// resources in the given parameters, as the referred resources are not
+// created, and collects the keys of the Secrets referred by the sensitive
+// fields.
+func removeReferences(params any, secrets map[client.ObjectKey]map[string]string) {
+	switch p := params.(type) {
+	case []any:
+		for _, e := range p {
+			removeReferences(e, secrets)
+		}
+	case map[string]any:
+		for k, v := range p {
+			switch {
+			case strings.HasSuffix(k, "SecretRef"):
+				ref, ok := v.(map[string]any)
+				if !ok {
+					continue
+				}
+				name, _ := ref["name"].(string)
+				ns, _ := ref["namespace"].(string)
+				key, _ := ref["key"].(string)
+				nn := client.ObjectKey{Namespace: ns, Name: name}
+				if secrets[nn] == nil {
+					secrets[nn] = map[string]string{}
+				}
+				if key != "" {
+					secrets[nn][key] = secretValue
+				}
+			case strings.HasSuffix(k, "Ref") || strings.HasSuffix(k, "Refs") || strings.HasSuffix(k, "Selector"):
+				delete(p, k)
+			default:
+				removeReferences(v, secrets)
+			}
+		}
+	}
+}
+
+// createDependencies creates the namespaces of the given managed resource
+// and the given Secrets, and the Secrets.
+func createDependencies(ctx context.Context, kube client.Client, mg *unstructured.Unstructured, secrets map[client.ObjectKey]map[string]string) error {
+	namespaces := map[string]struct{}{}
+	if ns := mg.GetNamespace(); ns != "" {
+		namespaces[ns] = struct{}{}
+	}
+	if ns, _, _ := unstructured.NestedString(mg.Object, "spec", "writeConnectionSecretToRef", "namespace"); ns != "" {
+		namespaces[ns] = struct{}{}
+	}
+	for k := range secrets {
+		namespaces[k.Namespace] = struct{}{}
+	}
+	for ns := range namespaces {
+		if ns == "" {
+			continue
+		}
+		if err := kube.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}); err != nil && !kerrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "cannot create the namespace %s", ns)
+		}
+	}
+	for k, data := range secrets {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: k.Namespace, Name: k.Name},
+			StringData: data,
+		}
+		if err := kube.Create(ctx, s); err != nil {
+			return errors.Wrapf(err, "cannot create the secret %s", k.Name)
+		}
+	}
+	return nil
+}
+
+// isTrue returns whether the condition of the given type is true in the
+// given conditions.
+func isTrue(conditions []any, t string) bool {
+	for _, c := range conditions {
+		m, ok := c.(map[string]any)
+		if ok && m["type"] == t {
+			return m["status"] == string(corev1.ConditionTrue)
+		}
+	}
+	return false
+}
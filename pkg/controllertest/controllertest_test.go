@@ -0,0 +1,102 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package controllertest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const example = `---
+
+apiVersion: ec2.aws.upbound.io/v1beta1
+kind: Subnet
+metadata:
+  name: example
+spec:
+  forProvider:
+    cidrBlock: 10.0.1.0/24
+    vpcIdSelector:
+      matchLabels:
+        testing.upbound.io/example-name: example
+    tags:
+    - key: name
+      valueSecretRef:
+        name: example-secret
+        namespace: upbound-system
+        key: attribute.value
+    routeTableIdsRefs:
+    - name: example
+
+---
+
+apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPC
+metadata:
+  name: example
+spec:
+  forProvider:
+    cidrBlock: 10.0.0.0/16
+`
+
+func TestReadExample(t *testing.T) {
+	type want struct {
+		forProvider map[string]any
+		secrets     map[client.ObjectKey]map[string]string
+	}
+	cases := map[string]struct {
+		reason  string
+		example string
+		want    want
+	}{
+		"References": {
+			reason:  "The references and the selectors of the first resource should be removed and the Secrets of its sensitive fields should be collected.",
+			example: example,
+			want: want{
+				forProvider: map[string]any{
+					"cidrBlock": "10.0.1.0/24",
+					"tags": []any{
+						map[string]any{
+							"key": "name",
+							"valueSecretRef": map[string]any{
+								"name":      "example-secret",
+								"namespace": "upbound-system",
+								"key":       "attribute.value",
+							},
+						},
+					},
+				},
+				secrets: map[client.ObjectKey]map[string]string{
+					{Namespace: "upbound-system", Name: "example-secret"}: {"attribute.value": secretValue},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "example.yaml")
+			if err := os.WriteFile(file, []byte(tc.example), 0600); err != nil {
+				t.Fatal(err)
+			}
+			mg, err := readExample(file)
+			if err != nil {
+				t.Fatalf("\n%s\nreadExample(...): %v", tc.reason, err)
+			}
+			secrets := map[client.ObjectKey]map[string]string{}
+			forProvider := mg.Object["spec"].(map[string]any)["forProvider"]
+			removeReferences(forProvider, secrets)
+			if diff := cmp.Diff(tc.want.forProvider, forProvider); diff != "" {
+				t.Errorf("\n%s\nremoveReferences(...): -want forProvider, +got forProvider:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.secrets, secrets); diff != "" {
+				t.Errorf("\n%s\nremoveReferences(...): -want secrets, +got secrets:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
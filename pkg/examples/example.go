@@ -13,7 +13,9 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	xpmeta "github.com/crossplane/crossplane-runtime/pkg/meta"
@@ -22,6 +24,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/config/conversion"
 	"github.com/upbound/upjet/pkg/registry/reference"
 	"github.com/upbound/upjet/pkg/resource/json"
 	tjtypes "github.com/upbound/upjet/pkg/types"
@@ -41,16 +44,20 @@ const (
 
 // Generator represents a pipeline for generating example manifests.
 // Generates example manifests for Terraform resources under examples-generated.
+// Generate is safe for concurrent use.
 type Generator struct {
 	reference.Injector
-	rootDir         string
-	configResources map[string]*config.Resource
-	resources       map[string]*reference.PavedWithManifest
+	rootDir               string
+	configResources       map[string]*config.Resource
+	mu                    sync.Mutex
+	resources             map[string]*reference.PavedWithManifest
+	syncWaveAnnotationKey string
+	syncWaves             map[string]int
 }
 
 // NewGenerator returns a configured Generator
-func NewGenerator(rootDir, modulePath, shortName string, configResources map[string]*config.Resource) *Generator {
-	return &Generator{
+func NewGenerator(rootDir, modulePath, shortName string, configResources map[string]*config.Resource, opts ...GeneratorOption) *Generator {
+	eg := &Generator{
 		Injector: reference.Injector{
 			ModulePath:        modulePath,
 			ProviderShortName: shortName,
@@ -59,6 +66,18 @@ func NewGenerator(rootDir, modulePath, shortName string, configResources map[str
 		configResources: configResources,
 		resources:       make(map[string]*reference.PavedWithManifest),
 	}
+	for _, o := range opts {
+		o(eg)
+	}
+	return eg
+}
+
+// WithExternalProviders configures the Generator to resolve the types of the
+// references to the resources of the given external providers.
+func WithExternalProviders(eps []config.ExternalProvider) GeneratorOption {
+	return func(eg *Generator) {
+		eg.ExternalProviders = eps
+	}
 }
 
 // StoreExamples stores the generated example manifests under examples-generated in
@@ -78,7 +97,7 @@ func (eg *Generator) StoreExamples() error { // nolint:gocyclo
 		}
 		if r, ok := eg.configResources[reference.NewRefPartsFromResourceName(rn).Resource]; ok && r.MetaResource != nil {
 			re := r.MetaResource.Examples[0]
-			context, err := reference.PrepareLocalResolutionContext(re, reference.NewRefParts(reference.NewRefPartsFromResourceName(rn).Resource, re.Name).GetResourceName(false))
+			context, err := reference.PrepareLocalResolutionContext(re, reference.NewRefParts(r.Name, re.Name).GetResourceName(false))
 			if err != nil {
 				return errors.Wrapf(err, "cannot prepare local resolution context for resource: %s", rn)
 			}
@@ -116,7 +135,12 @@ func (eg *Generator) StoreExamples() error { // nolint:gocyclo
 func paveCRManifest(exampleParams map[string]any, r *config.Resource, eName, group, version, eGroup string) *reference.PavedWithManifest {
 	delete(exampleParams, "depends_on")
 	delete(exampleParams, "lifecycle")
-	transformFields(r, exampleParams, r.ExternalName.OmittedFields, "")
+	// the hidden parameters are not part of the spec
+	omitted := append([]string{}, r.ExternalName.OmittedFields...)
+	for n := range r.HiddenParameters {
+		omitted = append(omitted, n)
+	}
+	transformFields(r, exampleParams, omitted, "")
 	metadata := map[string]any{
 		"labels": map[string]string{
 			labelExampleName: eName,
@@ -133,6 +157,10 @@ func paveCRManifest(exampleParams map[string]any, r *config.Resource, eName, gro
 			"forProvider": exampleParams,
 		},
 	}
+	// the secrets referred by the example are in the same namespace
+	if r.Namespaced() {
+		metadata["namespace"] = defaultNamespace
+	}
 	if len(r.MetaResource.ExternalName) != 0 {
 		metadata["annotations"].(map[string]string)[xpmeta.AnnotationKeyExternalName] = r.MetaResource.ExternalName
 	}
@@ -161,6 +189,13 @@ func (eg *Generator) writeManifest(writer io.Writer, pm *reference.PavedWithMani
 	if err := pm.Paved.SetValue("metadata.name", pm.ExampleName); err != nil {
 		return errors.Wrapf(err, `cannot set "metadata.name" for resource %q:%s`, pm.Config.Name, pm.ExampleName)
 	}
+	if eg.syncWaveAnnotationKey != "" {
+		annotations, err := pm.Paved.GetValue("metadata.annotations")
+		if err != nil {
+			return errors.Wrap(err, `cannot get "metadata.annotations" from paved`)
+		}
+		annotations.(map[string]string)[eg.syncWaveAnnotationKey] = strconv.Itoa(eg.syncWave(pm.Config.Name))
+	}
 	u := pm.Paved.UnstructuredContent()
 	buff, err := yaml.Marshal(u)
 	if err != nil {
@@ -175,20 +210,36 @@ func (eg *Generator) writeManifest(writer io.Writer, pm *reference.PavedWithMani
 
 // Generate generates an example manifest for the specified Terraform resource.
 func (eg *Generator) Generate(group, version string, r *config.Resource) error {
-	rm := eg.configResources[r.Name].MetaResource
+	rm := r.MetaResource
 	if rm == nil || len(rm.Examples) == 0 {
 		return nil
 	}
 	groupPrefix := strings.ToLower(strings.Split(group, ".")[0])
 	// e.g. gvk = ec2/v1beta1/instance
 	gvk := fmt.Sprintf("%s/%s/%s", groupPrefix, version, strings.ToLower(r.Kind))
-	pm := paveCRManifest(rm.Examples[0].Paved.UnstructuredContent(), r, rm.Examples[0].Name, group, version, gvk)
-	manifestDir := filepath.Join(eg.rootDir, "examples-generated", groupPrefix)
-	pm.ManifestPath = filepath.Join(manifestDir, fmt.Sprintf("%s.yaml", strings.ToLower(r.Kind)))
-	eg.resources[fmt.Sprintf("%s.%s", r.Name, reference.Wildcard)] = pm
+	params := rm.Examples[0].Paved.UnstructuredContent()
+	if sl := r.EmbeddedSingletonLists(version); len(sl) > 0 {
+		var err error
+		if params, err = conversion.NewSingletonListConversion(sl...).Convert(params, conversion.FromTerraform); err != nil {
+			return errors.Wrapf(err, "cannot embed the singleton lists in the example manifest for resource %s", r.Name)
+		}
+	}
+	pm := paveCRManifest(params, r, rm.Examples[0].Name, group, version, gvk)
+	pm.ManifestPath = eg.ManifestPath(group, r)
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+	eg.resources[fmt.Sprintf("%s.%s", r.Key(), reference.Wildcard)] = pm
 	return nil
 }
 
+// ManifestPath returns the path of the example manifest of the given
+// resource in the given group, which is written by StoreExamples if the
+// resource has examples.
+func (eg *Generator) ManifestPath(group string, r *config.Resource) string {
+	groupPrefix := strings.ToLower(strings.Split(group, ".")[0])
+	return filepath.Join(eg.rootDir, "examples-generated", groupPrefix, fmt.Sprintf("%s.yaml", strings.ToLower(r.Kind)))
+}
+
 func getHierarchicalName(prefix, name string) string {
 	if prefix == "" {
 		return name
@@ -235,7 +286,15 @@ func transformFields(r *config.Resource, params map[string]any, omittedFields []
 		}
 	}
 
-	for n, v := range params {
+	// the parameters are renamed in place, so the renamed ones must not be
+	// visited again.
+	names := make([]string, 0, len(params))
+	for n := range params {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		v := params[n]
 		fieldPath := getHierarchicalName(namePrefix, n)
 		sch := config.GetSchema(r.TerraformResource, fieldPath)
 		if sch == nil {
@@ -245,6 +304,9 @@ func transformFields(r *config.Resource, params map[string]any, omittedFields []
 		// so we'll need to perform at least name change on it.
 		delete(params, n)
 		fn := name.NewFromSnake(n)
+		if rn, ok := r.FieldRenames[fieldPath]; ok {
+			fn = name.NewFromCamel(rn)
+		}
 		switch {
 		case sch.Sensitive:
 			secretName, secretKey := getSecretRef(v)
@@ -0,0 +1,158 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package examples
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+const (
+	// AnnotationKeyArgoCDSyncWave is the annotation key used by Argo CD to
+	// order the application of the resources.
+	AnnotationKeyArgoCDSyncWave = "argocd.argoproj.io/sync-wave"
+)
+
+// GeneratorOption configures a Generator.
+type GeneratorOption func(*Generator)
+
+// WithSyncWaveAnnotation configures the Generator to annotate the generated
+// example manifests with their sync-waves using the given annotation key.
+// Sync-waves are derived from the cross-resource reference graph, so that
+// GitOps engines apply the referenced resources before the resources
+// referencing them.
+func WithSyncWaveAnnotation(key string) GeneratorOption {
+	return func(eg *Generator) {
+		eg.syncWaveAnnotationKey = key
+	}
+}
+
+// syncWave returns the sync-wave of the specified Terraform resource, which
+// is zero for resources not referencing any other resource, and one more
+// than the largest sync-wave of the referenced resources otherwise. The
+// resources referencing each other in a cycle share the same sync-wave, so
+// that the sync-waves don't depend on the order they're computed in.
+func (eg *Generator) syncWave(tfName string) int {
+	if eg.syncWaves == nil {
+		eg.syncWaves = eg.computeSyncWaves()
+	}
+	return eg.syncWaves[tfName]
+}
+
+// computeSyncWaves returns the sync-waves of all the configured resources.
+// The strongly connected components of the reference graph, i.e. the
+// resources in the reference cycles, are collapsed with Tarjan's algorithm,
+// which yields them in a reverse topological order, so the sync-waves of
+// the components they reference are computed before theirs.
+func (eg *Generator) computeSyncWaves() map[string]int {
+	names := make([]string, 0, len(eg.configResources))
+	for n := range eg.configResources {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	edges := make(map[string][]string, len(names))
+	for _, n := range names {
+		r := eg.configResources[n]
+		refs := make([]string, 0, len(r.References))
+		for _, ref := range r.References {
+			if target := eg.referencedResource(r, ref); target != "" && target != n {
+				if _, ok := eg.configResources[target]; ok {
+					refs = append(refs, target)
+				}
+			}
+		}
+		sort.Strings(refs)
+		edges[n] = refs
+	}
+
+	waves := make(map[string]int, len(names))
+	index := make(map[string]int, len(names))
+	lowLink := make(map[string]int, len(names))
+	onStack := make(map[string]bool, len(names))
+	var stack []string
+	var connect func(n string)
+	connect = func(n string) {
+		index[n], lowLink[n] = len(index), len(index)
+		stack = append(stack, n)
+		onStack[n] = true
+		for _, m := range edges[n] {
+			if _, ok := index[m]; !ok {
+				connect(m)
+				if lowLink[m] < lowLink[n] {
+					lowLink[n] = lowLink[m]
+				}
+			} else if onStack[m] && index[m] < lowLink[n] {
+				lowLink[n] = index[m]
+			}
+		}
+		if lowLink[n] != index[n] {
+			return
+		}
+		// n is the root of a component, whose members are on the stack.
+		var component []string
+		for {
+			m := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[m] = false
+			component = append(component, m)
+			if m == n {
+				break
+			}
+		}
+		members := make(map[string]bool, len(component))
+		for _, m := range component {
+			members[m] = true
+		}
+		wave := 0
+		for _, m := range component {
+			for _, t := range edges[m] {
+				if !members[t] && waves[t]+1 > wave {
+					wave = waves[t] + 1
+				}
+			}
+		}
+		for _, m := range component {
+			waves[m] = wave
+		}
+	}
+	for _, n := range names {
+		if _, ok := index[n]; !ok {
+			connect(n)
+		}
+	}
+	return waves
+}
+
+// referencedResource returns the name of the Terraform resource targeted by
+// the given reference of the specified resource, or an empty string if it
+// cannot be found among the configured resources.
+func (eg *Generator) referencedResource(r *config.Resource, ref config.Reference) string {
+	if ref.TerraformName != "" {
+		return ref.TerraformName
+	}
+	if ref.Type == "" {
+		return ""
+	}
+	// Type is either the name of a type in the same package or
+	// <package-path>.<type-name>, with a package path of the form
+	// <module-path>/apis/<short-group>/<version>.
+	kind, group, version := ref.Type, r.ShortGroup, r.Version
+	if i := strings.LastIndex(ref.Type, "."); i != -1 {
+		kind = ref.Type[i+1:]
+		dirs := strings.Split(ref.Type[:i], "/")
+		if len(dirs) < 2 {
+			return ""
+		}
+		group, version = dirs[len(dirs)-2], dirs[len(dirs)-1]
+	}
+	for n, c := range eg.configResources {
+		if c.Kind == kind && c.Version == version && strings.EqualFold(c.ShortGroup, group) {
+			return n
+		}
+	}
+	return ""
+}
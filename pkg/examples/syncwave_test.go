@@ -0,0 +1,106 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package examples
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+func TestSyncWave(t *testing.T) {
+	type args struct {
+		resources map[string]*config.Resource
+	}
+	type want struct {
+		waves map[string]int
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"TerraformNameReferences": {
+			args: args{
+				resources: map[string]*config.Resource{
+					"aws_vpc": {Name: "aws_vpc"},
+					"aws_subnet": {Name: "aws_subnet", References: config.References{
+						"vpc_id": {TerraformName: "aws_vpc"},
+					}},
+					"aws_instance": {Name: "aws_instance", References: config.References{
+						"subnet_id": {TerraformName: "aws_subnet"},
+						"vpc_id":    {TerraformName: "aws_vpc"},
+					}},
+				},
+			},
+			want: want{
+				waves: map[string]int{"aws_vpc": 0, "aws_subnet": 1, "aws_instance": 2},
+			},
+		},
+		"TypeReferences": {
+			args: args{
+				resources: map[string]*config.Resource{
+					"aws_vpc": {Name: "aws_vpc", Kind: "VPC", ShortGroup: "ec2", Version: "v1beta1"},
+					"aws_subnet": {Name: "aws_subnet", Kind: "Subnet", ShortGroup: "ec2", Version: "v1beta1", References: config.References{
+						"vpc_id": {Type: "VPC"},
+					}},
+					"aws_db_subnet_group": {Name: "aws_db_subnet_group", Kind: "SubnetGroup", ShortGroup: "rds", Version: "v1beta1", References: config.References{
+						"subnet_ids": {Type: "github.com/upbound/provider-aws/apis/ec2/v1beta1.Subnet"},
+					}},
+				},
+			},
+			want: want{
+				waves: map[string]int{"aws_vpc": 0, "aws_subnet": 1, "aws_db_subnet_group": 2},
+			},
+		},
+		"Cycle": {
+			args: args{
+				resources: map[string]*config.Resource{
+					"a": {Name: "a", References: config.References{
+						"b_id": {TerraformName: "b"},
+						"d_id": {TerraformName: "d"},
+					}},
+					"b": {Name: "b", References: config.References{
+						"a_id": {TerraformName: "a"},
+					}},
+					"c": {Name: "c", References: config.References{
+						"b_id": {TerraformName: "b"},
+					}},
+					"d": {Name: "d"},
+				},
+			},
+			want: want{
+				waves: map[string]int{"a": 1, "b": 1, "c": 2, "d": 0},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			names := make([]string, 0, len(tc.args.resources))
+			for n := range tc.args.resources {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			reversed := make([]string, len(names))
+			for i, n := range names {
+				reversed[len(names)-1-i] = n
+			}
+			// the sync-waves should not depend on the order they're
+			// evaluated in.
+			for _, order := range [][]string{names, reversed} {
+				eg := NewGenerator("", "", "", tc.args.resources, WithSyncWaveAnnotation(AnnotationKeyArgoCDSyncWave))
+				got := make(map[string]int, len(order))
+				for _, n := range order {
+					got[n] = eg.syncWave(n)
+				}
+				if diff := cmp.Diff(tc.want.waves, got); diff != "" {
+					t.Errorf("syncWave(...) in order %v: -want waves, +got waves: %s", order, diff)
+				}
+			}
+		})
+	}
+}
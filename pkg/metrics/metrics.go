@@ -16,6 +16,7 @@ package metrics
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
@@ -25,6 +26,24 @@ const (
 	promSysResource = "resource"
 )
 
+const (
+	// ResultSuccess is the result of the successful reconciliations.
+	ResultSuccess = "success"
+	// ResultError is the result of the failed reconciliations.
+	ResultError = "error"
+)
+
+const (
+	// OperationObserve is the observe operation on the external resources.
+	OperationObserve = "observe"
+	// OperationCreate is the create operation on the external resources.
+	OperationCreate = "create"
+	// OperationUpdate is the update operation on the external resources.
+	OperationUpdate = "update"
+	// OperationDelete is the delete operation on the external resources.
+	OperationDelete = "delete"
+)
+
 var (
 	// CLITime is the Terraform CLI execution times histogram.
 	CLITime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
@@ -61,8 +80,50 @@ var (
 		Help:      "Measures in seconds the time-to-readiness (TTR) for managed resources",
 		Buckets:   []float64{10, 15, 30, 60, 120, 300, 600, 1800, 3600},
 	}, []string{"group", "version", "kind"})
+
+	// Reconciles are the number of the reconciliations of the managed
+	// resources by their results.
+	Reconciles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNSUpjet,
+		Subsystem: promSysResource,
+		Name:      "reconciles_total",
+		Help:      "The number of the reconciliations of the managed resources by their results",
+	}, []string{"group", "version", "kind", "result"})
+
+	// OperationTime is the histogram of the execution times of the
+	// operations on the external resources, which run the Terraform
+	// operations.
+	OperationTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: promNSUpjet,
+		Subsystem: promSysResource,
+		Name:      "operation_duration",
+		Help:      "Measures in seconds how long it takes an operation on an external resource to complete",
+		Buckets:   []float64{0.1, 0.5, 1, 3, 5, 10, 30, 60, 120, 300},
+	}, []string{"group", "version", "kind", "operation"})
+
+	// OperationFailures are the number of the failed operations on the
+	// external resources.
+	OperationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNSUpjet,
+		Subsystem: promSysResource,
+		Name:      "operation_failures_total",
+		Help:      "The number of the failed operations on the external resources",
+	}, []string{"group", "version", "kind", "operation"})
 )
 
 func init() {
-	metrics.Registry.MustRegister(CLITime, CLIExecutions, TFProcesses, TTRMeasurements)
+	metrics.Registry.MustRegister(CLITime, CLIExecutions, TFProcesses, TTRMeasurements, Reconciles, OperationTime, OperationFailures)
+}
+
+// RegisterKind initializes the series of the per-kind metrics of the
+// managed resources of the given GroupVersionKind, so that they are exposed
+// with zero values before the managed resources are reconciled.
+func RegisterKind(gvk schema.GroupVersionKind) {
+	for _, r := range []string{ResultSuccess, ResultError} {
+		Reconciles.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind, r)
+	}
+	for _, op := range []string{OperationObserve, OperationCreate, OperationUpdate, OperationDelete} {
+		OperationTime.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind, op)
+		OperationFailures.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind, op)
+	}
 }
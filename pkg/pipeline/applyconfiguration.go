@@ -0,0 +1,88 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/muvaf/typewriter/pkg/wrapper"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+)
+
+const applyConfigurationRoot = "pkg/applyconfiguration"
+
+// NewApplyConfigurationGenerator returns a new ApplyConfigurationGenerator.
+func NewApplyConfigurationGenerator(rootDir, modulePath, group, version string, overrides fs.FS) *ApplyConfigurationGenerator {
+	shortGroup := strings.ToLower(strings.Split(group, ".")[0])
+	return &ApplyConfigurationGenerator{
+		LocalDirectoryPath: filepath.Join(rootDir, applyConfigurationRoot, shortGroup, version),
+		LicenseHeaderPath:  licenseHeaderPath(rootDir),
+		PackagePath:        filepath.Join(modulePath, applyConfigurationRoot, shortGroup, version),
+		Group:              group,
+		Version:            version,
+		overrides:          overrides,
+	}
+}
+
+// ApplyConfigurationGenerator generates the typed apply configurations of
+// the managed resources in an API version, which build the server-side
+// apply patches of the managed resources with only the fields set.
+type ApplyConfigurationGenerator struct {
+	LocalDirectoryPath string
+	LicenseHeaderPath  string
+	PackagePath        string
+	Group              string
+	Version            string
+
+	overrides fs.FS
+}
+
+// Generate writes the apply configurations of the given resources, whose
+// types are in the given package.
+func (ag *ApplyConfigurationGenerator) Generate(cfgs []*terraformedInput, typesPkgPath string) error {
+	if len(cfgs) == 0 {
+		return nil
+	}
+	tmpl, err := templates.Resolve(ag.overrides, "applyconfiguration.go.tmpl")
+	if err != nil {
+		return err
+	}
+	file := wrapper.NewFile(ag.PackagePath, ag.Version, tmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(ag.LicenseHeaderPath),
+	)
+	resources := make([]map[string]any, len(cfgs))
+	for i, cfg := range cfgs {
+		initParametersTypeName := cfg.InitParametersTypeName
+		if initParametersTypeName == "" {
+			// the generation cache of a previous version of upjet doesn't
+			// have the names of the InitParameters types.
+			initParametersTypeName = strings.TrimSuffix(cfg.ParametersTypeName, "Parameters") + "InitParameters"
+		}
+		resources[i] = map[string]any{
+			"Kind":                   cfg.Kind,
+			"Namespaced":             cfg.Namespaced(),
+			"ParametersTypeName":     cfg.ParametersTypeName,
+			"InitParametersTypeName": initParametersTypeName,
+			"ProviderOverrides":      len(cfg.ProviderConfigOverrides) > 0,
+		}
+	}
+	vars := map[string]any{
+		"Version":          ag.Version,
+		"APIVersion":       fmt.Sprintf("%s/%s", ag.Group, ag.Version),
+		"TypePackageAlias": file.Imports.UsePackage(typesPkgPath),
+		"Resources":        resources,
+	}
+	if err := os.MkdirAll(ag.LocalDirectoryPath, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "cannot mkdir %s", ag.LocalDirectoryPath)
+	}
+	return errors.Wrap(file.Write(filepath.Join(ag.LocalDirectoryPath, "zz_applyconfiguration.go"), vars, os.ModePerm), "cannot write apply configuration file")
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+func TestApplyConfigurationGenerate(t *testing.T) {
+	type want struct {
+		contains    []string
+		notContains []string
+	}
+	cases := map[string]struct {
+		reason string
+		input  *terraformedInput
+		want   want
+	}{
+		"ClusterScoped": {
+			reason: "The apply configuration of a cluster-scoped resource should be constructed with its name and its parameters should be typed.",
+			input: &terraformedInput{
+				Resource:               &config.Resource{Kind: "Bucket"},
+				ParametersTypeName:     "BucketParameters",
+				InitParametersTypeName: "BucketInitParameters_2",
+			},
+			want: want{
+				contains: []string{
+					"package v1beta1",
+					"func Bucket(name string) *BucketApplyConfiguration {",
+					`b.WithAPIVersion("s3.aws.upbound.io/v1beta1")`,
+					"ForProvider                      *v1beta1.BucketParameters `json:\"forProvider,omitempty\"`",
+					"func (b *BucketSpecApplyConfiguration) WithInitProvider(value v1beta1.BucketInitParameters_2) *BucketSpecApplyConfiguration {",
+				},
+				notContains: []string{"WithNamespace", "WithProviderOverrides"},
+			},
+		},
+		"PreviousCache": {
+			reason: "The name of the InitParameters type should be derived from the name of the Parameters type if it's not cached.",
+			input: &terraformedInput{
+				Resource:           &config.Resource{Kind: "Bucket", ProviderConfigOverrides: []string{"region"}},
+				ParametersTypeName: "BucketParameters",
+			},
+			want: want{
+				contains: []string{
+					"InitProvider                     *v1beta1.BucketInitParameters `json:\"initProvider,omitempty\"`",
+					"func (b *BucketSpecApplyConfiguration) WithProviderOverrides(entries map[string]string) *BucketSpecApplyConfiguration {",
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(rootDir, "hack"), 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(rootDir, "hack", "boilerplate.go.txt"), []byte("/*\nCopyright 2023 Upbound Inc.\n*/"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			ag := NewApplyConfigurationGenerator(rootDir, "example.org/provider", "s3.aws.upbound.io", "v1beta1", nil)
+			if err := ag.Generate([]*terraformedInput{tc.input}, "example.org/provider/apis/s3/v1beta1"); err != nil {
+				t.Fatalf("\n%s\nGenerate(...): unexpected error: %v", tc.reason, err)
+			}
+			b, err := os.ReadFile(filepath.Join(ag.LocalDirectoryPath, "zz_applyconfiguration.go"))
+			if err != nil {
+				t.Fatalf("cannot read the apply configuration file: %v", err)
+			}
+			for _, s := range tc.want.contains {
+				if !strings.Contains(string(b), s) {
+					t.Errorf("\n%s\nGenerate(...): apply configuration file does not contain %q:\n%s", tc.reason, s, string(b))
+				}
+			}
+			for _, s := range tc.want.notContains {
+				if strings.Contains(string(b), s) {
+					t.Errorf("\n%s\nGenerate(...): apply configuration file contains %q", tc.reason, s)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+)
+
+const (
+	// generationCacheFile is the file, relative to the root directory, in
+	// which the hashes of the inputs of the generated resources are
+	// persisted for the incremental generation.
+	generationCacheFile = ".work/upjet/generation_cache.json"
+
+	upjetModulePath = "github.com/upbound/upjet"
+)
+
+// cachedResource is the entry of a resource generated in an API version in
+// the generation cache, which holds the outputs of its generation needed by
+// the other generators when its generation is skipped.
+type cachedResource struct {
+	Hash                   string            `json:"hash"`
+	ParametersTypeName     string            `json:"parametersTypeName"`
+	InitParametersTypeName string            `json:"initParametersTypeName,omitempty"`
+	CompositionFieldPaths  map[string]string `json:"compositionFieldPaths,omitempty"`
+	TypeNames              map[string]string `json:"typeNames,omitempty"`
+	SensitiveFieldPaths    map[string]string `json:"sensitiveFieldPaths,omitempty"`
+	IgnoredCanonicalFields []string          `json:"ignoredCanonicalFields,omitempty"`
+}
+
+// generationCache is the cache of the generated resources, which are not
+// generated again unless their inputs, i.e. their schemas, configurations
+// and metadata, the provider configuration, the templates or the version of
+// upjet, change. It's safe for concurrent use.
+type generationCache struct {
+	path     string
+	previous map[string]*cachedResource
+
+	mu      sync.Mutex
+	current map[string]*cachedResource
+}
+
+// newGenerationCache returns the generation cache of the given root
+// directory, which doesn't return the previously generated resources if
+// force is true.
+func newGenerationCache(rootDir string, force bool) (*generationCache, error) {
+	c := &generationCache{
+		path:     filepath.Join(rootDir, generationCacheFile),
+		previous: map[string]*cachedResource{},
+		current:  map[string]*cachedResource{},
+	}
+	if force {
+		return c, nil
+	}
+	b, err := os.ReadFile(c.path)
+	switch {
+	case os.IsNotExist(err):
+		return c, nil
+	case err != nil:
+		return nil, errors.Wrap(err, "cannot read the generation cache file")
+	}
+	// a corrupted cache is ignored as the resources are generated again.
+	if err := json.Unmarshal(b, &c.previous); err != nil {
+		c.previous = map[string]*cachedResource{}
+	}
+	return c, nil
+}
+
+// lookup returns the cached outputs of the resource with the given key if
+// its inputs have the given hash and the given files generated for it
+// exist. The entry found is kept in the cache.
+func (c *generationCache) lookup(key, hash string, files ...string) (*cachedResource, bool) {
+	if c == nil {
+		return nil, false
+	}
+	e, ok := c.previous[key]
+	if !ok || e.Hash != hash {
+		return nil, false
+	}
+	for _, f := range files {
+		if _, err := os.Stat(f); err != nil {
+			return nil, false
+		}
+	}
+	c.store(key, e)
+	return e, true
+}
+
+// preserve returns the cached outputs of the resource with the given key
+// regardless of the hash of its inputs if the given files generated for it
+// exist, which is the case for the resources not selected for a partial
+// run. The entry found is kept in the cache with its hash, so that the
+// resource is generated again by the next incremental generation if its
+// inputs have changed.
+func (c *generationCache) preserve(key string, files ...string) (*cachedResource, bool) {
+	if c == nil {
+		return nil, false
+	}
+	e, ok := c.previous[key]
+	if !ok {
+		return nil, false
+	}
+	for _, f := range files {
+		if _, err := os.Stat(f); err != nil {
+			return nil, false
+		}
+	}
+	c.store(key, e)
+	return e, true
+}
+
+// store stores the outputs of the generated resource with the given key.
+func (c *generationCache) store(key string, e *cachedResource) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current[key] = e
+}
+
+// write persists the resources stored in the cache, which replace the
+// previously cached ones.
+func (c *generationCache) write() error {
+	if c == nil {
+		return nil
+	}
+	b, err := json.MarshalIndent(c.current, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal the generation cache")
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0750); err != nil {
+		return errors.Wrap(err, "cannot create the generation cache directory")
+	}
+	return errors.Wrap(os.WriteFile(c.path, b, 0600), "cannot write the generation cache file")
+}
+
+// generationCacheKey returns the key of the given resource generated in the
+// given group and version in the generation cache.
+func generationCacheKey(group, version, name string) string {
+	return fmt.Sprintf("%s/%s/%s", group, version, name)
+}
+
+// inputHashes returns the hashes of the inputs of the given resources of
+// the given provider generated with the given templates keyed by their
+// generation cache keys. They're computed before the generation as the
+// generators modify the configurations.
+func inputHashes(pc *config.Provider, tmpls generationTemplates, resourcesGroups map[string]map[string]map[string]*config.Resource) map[string]string {
+	p := *pc
+	p.Resources = nil
+	p.TemplateOverrides = nil
+	// the overriding templates are hashed by their contents as the file
+	// systems, e.g. an os.DirFS, may not have them.
+	common := contentHash(&p, tmpls.CRDTypes, tmpls.Controller, templates.MovedControllerTemplate,
+		templates.DocsTemplate, tmpls.OverrideContents, upjetVersion())
+	hashes := map[string]string{}
+	for group, versions := range resourcesGroups {
+		for version, resources := range versions {
+			for name, r := range resources {
+				hashes[generationCacheKey(group, version, name)] = contentHash(common, r)
+			}
+		}
+	}
+	return hashes
+}
+
+// upjetVersion returns the version of the upjet module the generator is
+// built with, which is "(devel)" for a local replacement.
+func upjetVersion() string {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if bi.Main.Path == upjetModulePath {
+		return bi.Main.Version
+	}
+	for _, d := range bi.Deps {
+		if d.Path == upjetModulePath {
+			if d.Replace != nil {
+				return d.Replace.Version
+			}
+			return d.Version
+		}
+	}
+	return ""
+}
+
+// contentHash returns the hash of the contents of the given values, which
+// are walked recursively. The functions are hashed by their names, so the
+// changes of the values captured by the closures are not detected.
+func contentHash(values ...any) string {
+	hs := &hasher{h: sha256.New(), visited: map[uintptr]struct{}{}}
+	for _, v := range values {
+		hs.write(reflect.ValueOf(v))
+	}
+	return hex.EncodeToString(hs.h.Sum(nil))
+}
+
+type hasher struct {
+	h       hash.Hash
+	visited map[uintptr]struct{}
+}
+
+func (hs *hasher) write(v reflect.Value) { // nolint:gocyclo
+	if !v.IsValid() {
+		fmt.Fprint(hs.h, "nil;")
+		return
+	}
+	switch v.Kind() { // nolint:exhaustive
+	case reflect.Bool:
+		fmt.Fprintf(hs.h, "%t;", v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fmt.Fprintf(hs.h, "%d;", v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		fmt.Fprintf(hs.h, "%d;", v.Uint())
+	case reflect.Float32, reflect.Float64:
+		fmt.Fprintf(hs.h, "%g;", v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		fmt.Fprintf(hs.h, "%g;", v.Complex())
+	case reflect.String:
+		fmt.Fprintf(hs.h, "%d:%s;", v.Len(), v.String())
+	case reflect.Func:
+		if v.IsNil() {
+			fmt.Fprint(hs.h, "nil;")
+			return
+		}
+		fmt.Fprintf(hs.h, "func:%s;", runtime.FuncForPC(v.Pointer()).Name())
+	case reflect.Pointer:
+		if v.IsNil() {
+			fmt.Fprint(hs.h, "nil;")
+			return
+		}
+		// the values referred multiple times are hashed once, which also
+		// breaks the cycles.
+		if _, ok := hs.visited[v.Pointer()]; ok {
+			fmt.Fprint(hs.h, "visited;")
+			return
+		}
+		hs.visited[v.Pointer()] = struct{}{}
+		hs.write(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			fmt.Fprint(hs.h, "nil;")
+			return
+		}
+		fmt.Fprintf(hs.h, "%s:", v.Elem().Type())
+		hs.write(v.Elem())
+	case reflect.Struct:
+		fmt.Fprintf(hs.h, "%s{", v.Type())
+		for i := 0; i < v.NumField(); i++ {
+			fmt.Fprintf(hs.h, "%s:", v.Type().Field(i).Name)
+			hs.write(v.Field(i))
+		}
+		fmt.Fprint(hs.h, "}")
+	case reflect.Slice, reflect.Array:
+		fmt.Fprintf(hs.h, "[%d:", v.Len())
+		for i := 0; i < v.Len(); i++ {
+			hs.write(v.Index(i))
+		}
+		fmt.Fprint(hs.h, "]")
+	case reflect.Map:
+		// the entries are hashed in the order of the hashes of their keys.
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]reflect.Value, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k := (&hasher{h: sha256.New(), visited: map[uintptr]struct{}{}})
+			k.write(iter.Key())
+			kh := hex.EncodeToString(k.h.Sum(nil))
+			keys = append(keys, kh)
+			values[kh] = iter.Value()
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(hs.h, "{%d:", len(keys))
+		for _, k := range keys {
+			fmt.Fprintf(hs.h, "%s=", k)
+			hs.write(values[k])
+		}
+		fmt.Fprint(hs.h, "}")
+	default:
+		// the channels and the unsafe pointers are hashed by their types.
+		fmt.Fprintf(hs.h, "%s;", v.Type())
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+type node struct {
+	Name string
+	Next *node
+}
+
+func TestContentHash(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		a      any
+		b      any
+		want   bool
+	}{
+		"SameContent": {
+			reason: "The values with the same contents should have the same hash regardless of their map orders.",
+			a: &config.Resource{
+				Name:       "aws_vpc",
+				References: config.References{"a": {Type: "A"}, "b": {Type: "B"}, "c": {Type: "C"}},
+			},
+			b: &config.Resource{
+				Name:       "aws_vpc",
+				References: config.References{"c": {Type: "C"}, "b": {Type: "B"}, "a": {Type: "A"}},
+			},
+			want: true,
+		},
+		"DifferentContent": {
+			reason: "The values with different contents should have different hashes.",
+			a: &config.Resource{
+				Name:       "aws_vpc",
+				References: config.References{"a": {Type: "A"}},
+			},
+			b: &config.Resource{
+				Name:       "aws_vpc",
+				References: config.References{"a": {Type: "B"}},
+			},
+			want: false,
+		},
+		"Cycle": {
+			reason: "The values referring to themselves should be hashed.",
+			a: func() any {
+				n := &node{Name: "a"}
+				n.Next = n
+				return n
+			}(),
+			b: func() any {
+				n := &node{Name: "a"}
+				n.Next = n
+				return n
+			}(),
+			want: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := contentHash(tc.a) == contentHash(tc.b)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ncontentHash(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGenerationCacheLookup(t *testing.T) {
+	type args struct {
+		hash  string
+		files []string
+		force bool
+	}
+	type want struct {
+		entry *cachedResource
+		hit   bool
+	}
+	dir := t.TempDir()
+	file := filepath.Join(dir, "zz_generated.go")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	entry := &cachedResource{Hash: "h1", ParametersTypeName: "VPCParameters"}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Unchanged": {
+			reason: "A resource with the cached hash whose files exist should be skipped.",
+			args: args{
+				hash:  "h1",
+				files: []string{file},
+			},
+			want: want{
+				entry: entry,
+				hit:   true,
+			},
+		},
+		"Changed": {
+			reason: "A resource whose inputs changed should be generated.",
+			args: args{
+				hash:  "h2",
+				files: []string{file},
+			},
+		},
+		"MissingFile": {
+			reason: "A resource whose generated files don't exist should be generated.",
+			args: args{
+				hash:  "h1",
+				files: []string{file, filepath.Join(dir, "missing.go")},
+			},
+		},
+		"Force": {
+			reason: "All the resources should be generated if the generation is forced.",
+			args: args{
+				hash:  "h1",
+				files: []string{file},
+				force: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			c, err := newGenerationCache(rootDir, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.store("ec2/v1beta1/aws_vpc", entry)
+			if err := c.write(); err != nil {
+				t.Fatal(err)
+			}
+			c, err = newGenerationCache(rootDir, tc.args.force)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, hit := c.lookup("ec2/v1beta1/aws_vpc", tc.args.hash, tc.args.files...)
+			if diff := cmp.Diff(tc.want.entry, got, cmp.AllowUnexported(cachedResource{})); diff != "" {
+				t.Errorf("\n%s\nlookup(...): -want entry, +got entry:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.hit, hit); diff != "" {
+				t.Errorf("\n%s\nlookup(...): -want hit, +got hit:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGenerationCachePreserve(t *testing.T) {
+	type want struct {
+		entry *cachedResource
+		hit   bool
+	}
+	dir := t.TempDir()
+	file := filepath.Join(dir, "zz_generated.go")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	entry := &cachedResource{Hash: "h1", ParametersTypeName: "VPCParameters"}
+	cases := map[string]struct {
+		reason string
+		key    string
+		files  []string
+		want   want
+	}{
+		"Cached": {
+			reason: "A cached resource whose files exist should be preserved regardless of its hash.",
+			key:    "ec2/v1beta1/aws_vpc",
+			files:  []string{file},
+			want: want{
+				entry: entry,
+				hit:   true,
+			},
+		},
+		"NotCached": {
+			reason: "A resource not found in the cache should be generated.",
+			key:    "ec2/v1beta1/aws_subnet",
+			files:  []string{file},
+		},
+		"MissingFile": {
+			reason: "A resource whose generated files don't exist should be generated.",
+			key:    "ec2/v1beta1/aws_vpc",
+			files:  []string{file, filepath.Join(dir, "missing.go")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			c, err := newGenerationCache(rootDir, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.store("ec2/v1beta1/aws_vpc", entry)
+			if err := c.write(); err != nil {
+				t.Fatal(err)
+			}
+			c, err = newGenerationCache(rootDir, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, hit := c.preserve(tc.key, tc.files...)
+			if diff := cmp.Diff(tc.want.entry, got, cmp.AllowUnexported(cachedResource{})); diff != "" {
+				t.Errorf("\n%s\npreserve(...): -want entry, +got entry:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.hit, hit); diff != "" {
+				t.Errorf("\n%s\npreserve(...): -want hit, +got hit:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/muvaf/typewriter/pkg/wrapper"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+)
+
+const clientRoot = "pkg/client"
+
+// NewClientGenerator returns a new ClientGenerator.
+func NewClientGenerator(rootDir, modulePath, group, version string, overrides fs.FS) *ClientGenerator {
+	shortGroup := strings.ToLower(strings.Split(group, ".")[0])
+	return &ClientGenerator{
+		LocalDirectoryPath: filepath.Join(rootDir, clientRoot, shortGroup, version),
+		LicenseHeaderPath:  licenseHeaderPath(rootDir),
+		PackagePath:        filepath.Join(modulePath, clientRoot, shortGroup, version),
+		Group:              group,
+		Version:            version,
+		overrides:          overrides,
+	}
+}
+
+// ClientGenerator generates the typed clients, listers and informers of the
+// managed resources in an API version, so that they can be used without
+// falling back to the unstructured clients.
+type ClientGenerator struct {
+	LocalDirectoryPath string
+	LicenseHeaderPath  string
+	PackagePath        string
+	Group              string
+	Version            string
+
+	overrides fs.FS
+}
+
+// Generate writes the typed clients of the given resources, whose types are
+// in the given package.
+func (cg *ClientGenerator) Generate(cfgs []*config.Resource, typesPkgPath string) error {
+	if len(cfgs) == 0 {
+		return nil
+	}
+	tmpl, err := templates.Resolve(cg.overrides, "client.go.tmpl")
+	if err != nil {
+		return err
+	}
+	clientFile := wrapper.NewFile(cg.PackagePath, cg.Version, tmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(cg.LicenseHeaderPath),
+	)
+	resources := make([]map[string]any, len(cfgs))
+	for i, cfg := range cfgs {
+		resources[i] = map[string]any{
+			"Kind":       cfg.Kind,
+			"Namespaced": cfg.Namespaced(),
+		}
+	}
+	vars := map[string]any{
+		"Version":          cg.Version,
+		"APIVersion":       fmt.Sprintf("%s/%s", cg.Group, cg.Version),
+		"TypePackageAlias": clientFile.Imports.UsePackage(typesPkgPath),
+		"Resources":        resources,
+	}
+	if err := os.MkdirAll(cg.LocalDirectoryPath, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "cannot mkdir %s", cg.LocalDirectoryPath)
+	}
+	return errors.Wrap(clientFile.Write(filepath.Join(cg.LocalDirectoryPath, "zz_client.go"), vars, os.ModePerm), "cannot write client file")
+}
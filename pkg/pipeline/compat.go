@@ -0,0 +1,218 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// BreakingChange is a backwards-incompatible change of the schema of a Kind
+// between two OpenAPI documents of a provider.
+type BreakingChange struct {
+	// Schema is the name of the schema of the Kind in the OpenAPI documents,
+	// e.g. "io.upbound.aws.ec2.v1beta1.VPC".
+	Schema string
+	// Path is the path of the changed field in the schema, e.g.
+	// "spec.forProvider.cidrBlock". It's empty if the whole schema is
+	// removed.
+	Path string
+	// Message describes the change.
+	Message string
+}
+
+// String returns the string representation of the BreakingChange.
+func (c BreakingChange) String() string {
+	if c.Path == "" {
+		return fmt.Sprintf("%s: %s", c.Schema, c.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", c.Schema, c.Path, c.Message)
+}
+
+// DetectBreakingChanges returns the breaking changes of the schemas in the
+// OpenAPI document in the given current file compared to the ones in the
+// given previous file, e.g. the document generated by the OpenAPIGenerator
+// for the previous release of the provider, sorted by the schemas and the
+// paths. The removed schemas and fields, the changed types, the fields that
+// become required and the tightened validations of the fields outside the
+// statuses are breaking changes.
+func DetectBreakingChanges(previousFile, currentFile string) ([]BreakingChange, error) {
+	previous, err := readOpenAPISchemas(previousFile)
+	if err != nil {
+		return nil, err
+	}
+	current, err := readOpenAPISchemas(currentFile)
+	if err != nil {
+		return nil, err
+	}
+	var changes []BreakingChange
+	for name, p := range previous {
+		c, ok := current[name]
+		if !ok {
+			changes = append(changes, BreakingChange{Schema: name, Message: "schema is removed"})
+			continue
+		}
+		for _, d := range compareSchemas(p, c, nil) {
+			d.Schema = name
+			changes = append(changes, d)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Schema != changes[j].Schema {
+			return changes[i].Schema < changes[j].Schema
+		}
+		if changes[i].Path != changes[j].Path {
+			return changes[i].Path < changes[j].Path
+		}
+		return changes[i].Message < changes[j].Message
+	})
+	return changes, nil
+}
+
+func readOpenAPISchemas(file string) (map[string]map[string]any, error) {
+	b, err := os.ReadFile(file) // nolint:gosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read the OpenAPI document %s", file)
+	}
+	doc := struct {
+		Components struct {
+			Schemas map[string]map[string]any `json:"schemas"`
+		} `json:"components"`
+	}{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, errors.Wrapf(err, "cannot unmarshal the OpenAPI document %s", file)
+	}
+	return doc.Components.Schemas, nil
+}
+
+// compareSchemas returns the breaking changes of the given current schema
+// at the given path compared to the given previous schema.
+func compareSchemas(previous, current map[string]any, path []string) []BreakingChange { // nolint:gocyclo
+	var changes []BreakingChange
+	add := func(format string, args ...any) {
+		changes = append(changes, BreakingChange{Path: strings.Join(path, "."), Message: fmt.Sprintf(format, args...)})
+	}
+	child := func(name string) []string {
+		return append(path[:len(path):len(path)], name)
+	}
+	if pt, ct := previous["type"], current["type"]; pt != nil && !reflect.DeepEqual(pt, ct) {
+		add("type is changed from %v to %v", pt, ct)
+		// the rest of the schema isn't comparable.
+		return changes
+	}
+	// the validations of the observed state can be tightened, as they're
+	// not validating the inputs.
+	input := len(path) == 0 || path[0] != "status"
+
+	pp, _ := previous["properties"].(map[string]any)
+	cp, _ := current["properties"].(map[string]any)
+	for name, p := range pp {
+		c, ok := cp[name]
+		if !ok {
+			changes = append(changes, BreakingChange{Path: strings.Join(child(name), "."), Message: "field is removed"})
+			continue
+		}
+		ps, _ := p.(map[string]any)
+		cs, _ := c.(map[string]any)
+		changes = append(changes, compareSchemas(ps, cs, child(name))...)
+	}
+	// the elements of the lists and the values of the maps are compared
+	// at the paths with the "[*]" suffixes.
+	for _, k := range []string{"items", "additionalProperties"} {
+		ps, pok := previous[k].(map[string]any)
+		cs, cok := current[k].(map[string]any)
+		if pok && cok && len(path) > 0 {
+			elemPath := append(path[:len(path)-1:len(path)-1], path[len(path)-1]+"[*]")
+			changes = append(changes, compareSchemas(ps, cs, elemPath)...)
+		}
+	}
+	if !input {
+		return changes
+	}
+
+	previousRequired := stringSet(previous["required"])
+	for _, r := range stringList(current["required"]) {
+		if !previousRequired[r] {
+			changes = append(changes, BreakingChange{Path: strings.Join(child(r), "."), Message: "field is required"})
+		}
+	}
+	if ce, ok := current["enum"].([]any); ok {
+		pe, pok := previous["enum"].([]any)
+		if !pok {
+			add("enum is added")
+		}
+		for _, v := range pe {
+			if !containsValue(ce, v) {
+				add("enum value %v is removed", v)
+			}
+		}
+	}
+	for _, k := range []string{"maximum", "maxLength", "maxItems", "maxProperties"} {
+		if c, ok := current[k].(float64); ok {
+			if p, pok := previous[k].(float64); !pok || c < p {
+				add("%s is decreased to %v", k, c)
+			}
+		}
+	}
+	for _, k := range []string{"minimum", "minLength", "minItems", "minProperties"} {
+		if c, ok := current[k].(float64); ok {
+			if p, pok := previous[k].(float64); !pok || c > p {
+				add("%s is increased to %v", k, c)
+			}
+		}
+	}
+	if c, ok := current["pattern"].(string); ok && c != previous["pattern"] {
+		add("pattern is changed to %q", c)
+	}
+	if c, ok := current["nullable"].(bool); ok && !c {
+		if p, _ := previous["nullable"].(bool); p {
+			add("field is not nullable")
+		}
+	}
+	pv, _ := previous["x-kubernetes-validations"].([]any)
+	cv, _ := current["x-kubernetes-validations"].([]any)
+	for _, v := range cv {
+		if !containsValue(pv, v) {
+			if m, ok := v.(map[string]any); ok {
+				add("validation rule %q is added", m["rule"])
+			}
+		}
+	}
+	return changes
+}
+
+func stringList(v any) []string {
+	l, _ := v.([]any)
+	s := make([]string, 0, len(l))
+	for _, e := range l {
+		if str, ok := e.(string); ok {
+			s = append(s, str)
+		}
+	}
+	return s
+}
+
+func stringSet(v any) map[string]bool {
+	s := map[string]bool{}
+	for _, e := range stringList(v) {
+		s[e] = true
+	}
+	return s
+}
+
+func containsValue(l []any, v any) bool {
+	for _, e := range l {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
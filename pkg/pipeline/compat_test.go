@@ -0,0 +1,118 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDetectBreakingChanges(t *testing.T) {
+	const vpc = "io.upbound.aws.ec2.v1beta1.VPC"
+	type args struct {
+		previous string
+		current  string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []BreakingChange
+	}{
+		"Compatible": {
+			reason: "The added optional fields and the loosened validations should not be breaking changes.",
+			args: args{
+				previous: `{"type": "object", "properties": {"spec": {"type": "object", "properties": {
+					"cidrBlock": {"type": "string", "maxLength": 10},
+					"tenancy": {"type": "string", "enum": ["default"]}}}}}`,
+				current: `{"type": "object", "properties": {"spec": {"type": "object", "properties": {
+					"cidrBlock": {"type": "string", "maxLength": 20},
+					"tenancy": {"type": "string", "enum": ["default", "dedicated"]},
+					"tags": {"type": "object", "additionalProperties": {"type": "string"}}}}}}`,
+			},
+		},
+		"RemovedAndChangedFields": {
+			reason: "The removed fields and the fields whose types are changed, including the elements of the lists, should be breaking changes.",
+			args: args{
+				previous: `{"type": "object", "properties": {"spec": {"type": "object", "properties": {
+					"cidrBlock": {"type": "string"},
+					"ipv6": {"type": "boolean"},
+					"subnets": {"type": "array", "items": {"type": "string"}}}}}}`,
+				current: `{"type": "object", "properties": {"spec": {"type": "object", "properties": {
+					"cidrBlock": {"type": "array"},
+					"subnets": {"type": "array", "items": {"type": "object"}}}}}}`,
+			},
+			want: []BreakingChange{
+				{Schema: vpc, Path: "spec.cidrBlock", Message: "type is changed from string to array"},
+				{Schema: vpc, Path: "spec.ipv6", Message: "field is removed"},
+				{Schema: vpc, Path: "spec.subnets[*]", Message: "type is changed from string to object"},
+			},
+		},
+		"TightenedValidations": {
+			reason: "The required fields, the removed enum values, the tightened bounds and the added validation rules should be breaking changes.",
+			args: args{
+				previous: `{"type": "object", "properties": {"spec": {"type": "object", "properties": {
+					"cidrBlock": {"type": "string", "maxLength": 20},
+					"count": {"type": "integer"},
+					"tenancy": {"type": "string", "enum": ["default", "dedicated"]}}}}}`,
+				current: `{"type": "object", "properties": {"spec": {"type": "object", "required": ["cidrBlock"],
+					"x-kubernetes-validations": [{"rule": "has(self.cidrBlock)"}], "properties": {
+					"cidrBlock": {"type": "string", "maxLength": 10},
+					"count": {"type": "integer", "minimum": 1},
+					"tenancy": {"type": "string", "enum": ["default"]}}}}}`,
+			},
+			want: []BreakingChange{
+				{Schema: vpc, Path: "spec", Message: `validation rule "has(self.cidrBlock)" is added`},
+				{Schema: vpc, Path: "spec.cidrBlock", Message: "field is required"},
+				{Schema: vpc, Path: "spec.cidrBlock", Message: "maxLength is decreased to 10"},
+				{Schema: vpc, Path: "spec.count", Message: "minimum is increased to 1"},
+				{Schema: vpc, Path: "spec.tenancy", Message: "enum value dedicated is removed"},
+			},
+		},
+		"TightenedStatus": {
+			reason: "The tightened validations of the status should not be breaking changes.",
+			args: args{
+				previous: `{"type": "object", "properties": {"status": {"type": "object", "properties": {
+					"id": {"type": "string"}}}}}`,
+				current: `{"type": "object", "properties": {"status": {"type": "object", "required": ["id"], "properties": {
+					"id": {"type": "string", "pattern": "^vpc-"}}}}}`,
+			},
+		},
+		"RemovedSchema": {
+			reason: "The removed schemas should be breaking changes.",
+			args: args{
+				previous: `{"type": "object"}`,
+			},
+			want: []BreakingChange{
+				{Schema: vpc, Message: "schema is removed"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			files := make([]string, 2)
+			for i, s := range []string{tc.args.previous, tc.args.current} {
+				schemas := "{}"
+				if s != "" {
+					schemas = `{"` + vpc + `": ` + s + `}`
+				}
+				files[i] = filepath.Join(dir, []string{"previous.json", "current.json"}[i])
+				if err := os.WriteFile(files[i], []byte(`{"components": {"schemas": `+schemas+`}}`), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := DetectBreakingChanges(files[0], files[1])
+			if err != nil {
+				t.Fatalf("\n%s\nDetectBreakingChanges(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDetectBreakingChanges(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
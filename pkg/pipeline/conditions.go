@@ -0,0 +1,118 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/muvaf/typewriter/pkg/wrapper"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+)
+
+// reservedConditionTypes are the types of the conditions set by the managed
+// reconciler and upjet, which cannot be redeclared.
+var reservedConditionTypes = map[string]bool{
+	"Ready":              true,
+	"Synced":             true,
+	"AsyncOperation":     true,
+	"LastAsyncOperation": true,
+	"Drifted":            true,
+}
+
+// NewConditionsGenerator returns a new ConditionsGenerator.
+func NewConditionsGenerator(pkg *types.Package, rootDir, group, version string, overrides fs.FS) *ConditionsGenerator {
+	return &ConditionsGenerator{
+		LocalDirectoryPath: filepath.Join(rootDir, "apis", strings.ToLower(strings.Split(group, ".")[0]), version),
+		LicenseHeaderPath:  licenseHeaderPath(rootDir),
+		pkg:                pkg,
+		overrides:          overrides,
+	}
+}
+
+// ConditionsGenerator generates the typed constants, constructors and
+// setters of the custom status conditions of the managed resources in an
+// API version.
+type ConditionsGenerator struct {
+	LocalDirectoryPath string
+	LicenseHeaderPath  string
+
+	pkg       *types.Package
+	overrides fs.FS
+}
+
+// Generate writes the conditions file with the custom status conditions of
+// the given resources.
+func (cg *ConditionsGenerator) Generate(cfgs []*config.Resource) error {
+	var resources []map[string]any
+	for _, cfg := range cfgs {
+		if len(cfg.Conditions) == 0 {
+			continue
+		}
+		if err := validateConditions(cfg.Conditions); err != nil {
+			return errors.Wrapf(err, "invalid conditions of resource %s", cfg.Name)
+		}
+		resources = append(resources, map[string]any{
+			"Kind":       cfg.Kind,
+			"Conditions": cfg.Conditions,
+		})
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+	tmpl, err := templates.Resolve(cg.overrides, "conditions.go.tmpl")
+	if err != nil {
+		return err
+	}
+	conditionsFile := wrapper.NewFile(cg.pkg.Path(), cg.pkg.Name(), tmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(cg.LicenseHeaderPath),
+	)
+	vars := map[string]any{
+		"APIVersion": cg.pkg.Name(),
+		"Resources":  resources,
+	}
+	return errors.Wrap(
+		conditionsFile.Write(filepath.Join(cg.LocalDirectoryPath, "zz_generated_conditions.go"), vars, os.ModePerm),
+		"cannot write the conditions file",
+	)
+}
+
+// validateConditions returns an error if the types or the reasons of the
+// given conditions aren't exported Go identifiers, or if a type is reserved
+// or declared more than once.
+func validateConditions(conditions []config.Condition) error {
+	seen := make(map[string]bool, len(conditions))
+	for _, c := range conditions {
+		if !token.IsExported(c.Type) || !token.IsIdentifier(c.Type) {
+			return errors.Errorf("condition type %q is not an exported Go identifier", c.Type)
+		}
+		if reservedConditionTypes[c.Type] {
+			return errors.Errorf("condition type %q is reserved", c.Type)
+		}
+		if seen[c.Type] {
+			return errors.Errorf("condition type %q is declared more than once", c.Type)
+		}
+		seen[c.Type] = true
+		reasons := make(map[string]bool, len(c.Reasons))
+		for _, r := range c.Reasons {
+			if !token.IsExported(r) || !token.IsIdentifier(r) {
+				return errors.Errorf("reason %q of condition type %q is not an exported Go identifier", r, c.Type)
+			}
+			if reasons[r] {
+				return errors.Errorf("reason %q of condition type %q is declared more than once", r, c.Type)
+			}
+			reasons[r] = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+func TestValidateConditions(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		conditions []config.Condition
+		want       error
+	}{
+		"Valid": {
+			reason: "The conditions with exported identifiers as their types and reasons should be valid.",
+			conditions: []config.Condition{
+				{Type: "LastApplyFailed", Reasons: []string{"ApplyFailure", "Success"}},
+				{Type: "Replicated"},
+			},
+		},
+		"UnexportedType": {
+			reason:     "A condition type which is not an exported identifier should be rejected.",
+			conditions: []config.Condition{{Type: "lastApplyFailed"}},
+			want:       errors.New(`condition type "lastApplyFailed" is not an exported Go identifier`),
+		},
+		"InvalidReason": {
+			reason:     "A reason which is not an identifier should be rejected.",
+			conditions: []config.Condition{{Type: "LastApplyFailed", Reasons: []string{"Apply-Failure"}}},
+			want:       errors.New(`reason "Apply-Failure" of condition type "LastApplyFailed" is not an exported Go identifier`),
+		},
+		"ReservedType": {
+			reason:     "The types of the conditions set by the managed reconciler should be rejected.",
+			conditions: []config.Condition{{Type: "Ready"}},
+			want:       errors.New(`condition type "Ready" is reserved`),
+		},
+		"DuplicateType": {
+			reason:     "A condition type declared more than once should be rejected.",
+			conditions: []config.Condition{{Type: "Replicated"}, {Type: "Replicated"}},
+			want:       errors.New(`condition type "Replicated" is declared more than once`),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateConditions(tc.conditions)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nvalidateConditions(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ Copyright 2021 Upbound Inc.
 package pipeline
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,12 +18,15 @@ import (
 )
 
 // NewControllerGenerator returns a new ControllerGenerator.
-func NewControllerGenerator(rootDir, modulePath, group string) *ControllerGenerator {
+func NewControllerGenerator(rootDir, modulePath, group string, overrides fs.FS) *ControllerGenerator {
 	return &ControllerGenerator{
 		Group:              group,
 		ControllerGroupDir: filepath.Join(rootDir, "internal", "controller", strings.Split(group, ".")[0]),
 		ModulePath:         modulePath,
-		LicenseHeaderPath:  filepath.Join(rootDir, "hack", "boilerplate.go.txt"),
+		RootPackagePath:    filepath.Join(modulePath, "internal", "controller"),
+		LicenseHeaderPath:  licenseHeaderPath(rootDir),
+		Template:           templates.ControllerTemplate,
+		overrides:          overrides,
 	}
 }
 
@@ -31,13 +35,31 @@ type ControllerGenerator struct {
 	Group              string
 	ControllerGroupDir string
 	ModulePath         string
-	LicenseHeaderPath  string
+	// RootPackagePath is the path of the root package of the controller
+	// packages of the API groups.
+	RootPackagePath   string
+	LicenseHeaderPath string
+	// Template is the template of the controller files.
+	Template string
+
+	overrides fs.FS
+}
+
+// PackagePath returns the path of the controller package of the given
+// resource.
+func (cg *ControllerGenerator) PackagePath(cfg *config.Resource) string {
+	return filepath.Join(cg.RootPackagePath, strings.ToLower(strings.Split(cg.Group, ".")[0]), strings.ToLower(cfg.Kind))
+}
+
+// FilePath returns the path of the controller file of the given resource.
+func (cg *ControllerGenerator) FilePath(cfg *config.Resource) string {
+	return filepath.Join(cg.ControllerGroupDir, strings.ToLower(cfg.Kind), "zz_controller.go")
 }
 
 // Generate writes controller setup functions.
 func (cg *ControllerGenerator) Generate(cfg *config.Resource, typesPkgPath string, featuresPkgPath string) (pkgPath string, err error) {
-	controllerPkgPath := filepath.Join(cg.ModulePath, "internal", "controller", strings.ToLower(strings.Split(cg.Group, ".")[0]), strings.ToLower(cfg.Kind))
-	ctrlFile := wrapper.NewFile(controllerPkgPath, strings.ToLower(cfg.Kind), templates.ControllerTemplate,
+	controllerPkgPath := cg.PackagePath(cfg)
+	ctrlFile := wrapper.NewFile(controllerPkgPath, strings.ToLower(cfg.Kind), cg.Template,
 		wrapper.WithGenStatement(GenStatement),
 		wrapper.WithHeaderPath(cg.LicenseHeaderPath),
 	)
@@ -47,11 +69,22 @@ func (cg *ControllerGenerator) Generate(cfg *config.Resource, typesPkgPath strin
 		"CRD": map[string]string{
 			"Kind": cfg.Kind,
 		},
-		"DisableNameInitializer": cfg.ExternalName.DisableNameInitializer,
-		"TypePackageAlias":       ctrlFile.Imports.UsePackage(typesPkgPath),
-		"UseAsync":               cfg.UseAsync,
-		"ResourceType":           cfg.Name,
-		"Initializers":           cfg.InitializerFns,
+		"DisableNameInitializer":      cfg.ExternalName.DisableNameInitializer,
+		"TypePackageAlias":            ctrlFile.Imports.UsePackage(typesPkgPath),
+		"UseAsync":                    cfg.UseAsync,
+		"ResourceKey":                 cfg.Key(),
+		"Initializers":                cfg.InitializerFns,
+		"MultiVersion":                len(cfg.ServedVersions) > 0,
+		"Namespaced":                  cfg.Namespaced(),
+		"DeprecatedFields":            len(cfg.DeprecatedFields) > 0,
+		"AllowReplacement":            cfg.AllowReplacementWithAnnotation,
+		"AdmissionValidations":        len(cfg.AdmissionValidations) > 0,
+		"OperationTimeouts":           cfg.OperationTimeouts != config.OperationTimeouts{},
+		"OperationHooks":              !cfg.OperationHooks.Empty(),
+		"PollInterval":                cfg.PollInterval != 0,
+		"TerraformConversions":        len(cfg.TerraformConversions) > 0,
+		"SupportedManagementPolicies": len(cfg.ManagementPolicies.Supported) > 0,
+		"DefaultManagementPolicies":   len(cfg.ManagementPolicies.Default) > 0,
 	}
 
 	// If the provider has a features package, add it to the controller template.
@@ -61,9 +94,41 @@ func (cg *ControllerGenerator) Generate(cfg *config.Resource, typesPkgPath strin
 		vars["FeaturesPackageAlias"] = ctrlFile.Imports.UsePackage(featuresPkgPath)
 	}
 
-	filePath := filepath.Join(cg.ControllerGroupDir, strings.ToLower(cfg.Kind), "zz_controller.go")
 	return controllerPkgPath, errors.Wrap(
-		ctrlFile.Write(filePath, vars, os.ModePerm),
+		ctrlFile.Write(cg.FilePath(cfg), vars, os.ModePerm),
+		"cannot write controller file",
+	)
+}
+
+// GenerateMover writes the setup function of the controller moving the
+// managed resources of the given previous Kind to its current Kind in the
+// given group.
+func (cg *ControllerGenerator) GenerateMover(cfg *config.Resource, typesPkgPath, movedGroup string) (pkgPath string, err error) {
+	controllerPkgPath := cg.PackagePath(cfg)
+	tmpl, err := templates.Resolve(cg.overrides, "moved_controller.go.tmpl")
+	if err != nil {
+		return "", err
+	}
+	ctrlFile := wrapper.NewFile(controllerPkgPath, strings.ToLower(cfg.Kind), tmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(cg.LicenseHeaderPath),
+	)
+
+	vars := map[string]any{
+		"Package": strings.ToLower(cfg.Kind),
+		"CRD": map[string]string{
+			"Kind": cfg.Kind,
+		},
+		"MovedTo": map[string]string{
+			"Group":   movedGroup,
+			"Version": cfg.MovedTo.Version,
+			"Kind":    cfg.MovedTo.Kind,
+		},
+		"TypePackageAlias": ctrlFile.Imports.UsePackage(typesPkgPath),
+	}
+
+	return controllerPkgPath, errors.Wrap(
+		ctrlFile.Write(cg.FilePath(cfg), vars, os.ModePerm),
 		"cannot write controller file",
 	)
 }
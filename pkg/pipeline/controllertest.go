@@ -0,0 +1,104 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/muvaf/typewriter/pkg/wrapper"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+)
+
+const (
+	defaultControllerTestsProviderFn = "GetProvider()"
+	defaultControllerTestsCRDsPath   = "package/crds"
+	defaultControllerTestsBuildTag   = "envtest"
+)
+
+// NewControllerTestsGenerator returns a new ControllerTestsGenerator with
+// the given options, whose unset fields are defaulted.
+func NewControllerTestsGenerator(rootDir, modulePath string, t config.ControllerTests, overrides fs.FS) *ControllerTestsGenerator {
+	if t.ProviderFn == "" {
+		t.ProviderFn = defaultControllerTestsProviderFn
+	}
+	if t.CRDsPath == "" {
+		t.CRDsPath = defaultControllerTestsCRDsPath
+	}
+	if t.BuildTag == "" {
+		t.BuildTag = defaultControllerTestsBuildTag
+	}
+	return &ControllerTestsGenerator{
+		CRDsDirectoryPath: filepath.Join(rootDir, t.CRDsPath),
+		ConfigPackagePath: filepath.Join(modulePath, "config"),
+		LicenseHeaderPath: licenseHeaderPath(rootDir),
+		ProviderFn:        t.ProviderFn,
+		BuildTag:          t.BuildTag,
+		overrides:         overrides,
+	}
+}
+
+// ControllerTestsGenerator generates the envtest-based integration tests of
+// the controllers of the managed resources.
+type ControllerTestsGenerator struct {
+	CRDsDirectoryPath string
+	ConfigPackagePath string
+	LicenseHeaderPath string
+	ProviderFn        string
+	BuildTag          string
+
+	overrides fs.FS
+}
+
+// FilePath returns the path of the test file of the controller in the given
+// controller file.
+func (tg *ControllerTestsGenerator) FilePath(controllerFile string) string {
+	return filepath.Join(filepath.Dir(controllerFile), "zz_controller_test.go")
+}
+
+// Generate writes the test file of the given resource's controller in the
+// given controller file of the given package, which creates the resource of
+// the given example manifest of the given API version package.
+func (tg *ControllerTestsGenerator) Generate(cfg *config.Resource, ctrlPkgPath, controllerFile, typesPkgPath, examplePath string) error {
+	tmpl, err := templates.Resolve(tg.overrides, "controller_test.go.tmpl")
+	if err != nil {
+		return err
+	}
+	testFile := wrapper.NewFile(ctrlPkgPath, filepath.Base(ctrlPkgPath), tmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(tg.LicenseHeaderPath),
+	)
+	dir := filepath.Dir(controllerFile)
+	vars := map[string]any{
+		"Package":            filepath.Base(ctrlPkgPath),
+		"Kind":               cfg.Kind,
+		"BuildTag":           tg.BuildTag,
+		"CRDsPath":           relativePath(dir, tg.CRDsDirectoryPath),
+		"ExamplePath":        relativePath(dir, examplePath),
+		"TypePackageAlias":   testFile.Imports.UsePackage(typesPkgPath),
+		"ConfigPackageAlias": testFile.Imports.UsePackage(tg.ConfigPackagePath),
+		"ProviderFn":         tg.ProviderFn,
+	}
+	return errors.Wrapf(testFile.Write(tg.FilePath(controllerFile), vars, os.ModePerm), "cannot write the controller test file of resource %s", cfg.Name)
+}
+
+// relativePath returns the given path relative to the given directory with
+// the slash separators.
+func relativePath(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil {
+		path = rel
+	}
+	return filepath.ToSlash(path)
+}
+
+// hasControllerTest returns true if the controller of the given resource is
+// tested, i.e. if the resource has an example and it's not moved.
+func hasControllerTest(cfg *config.Resource) bool {
+	return cfg.MovedTo == nil && cfg.MetaResource != nil && len(cfg.MetaResource.Examples) > 0
+}
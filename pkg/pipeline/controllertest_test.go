@@ -0,0 +1,114 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/registry"
+)
+
+func TestControllerTestsGenerate(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		tests    config.ControllerTests
+		contains []string
+	}{
+		"Defaults": {
+			reason: "The test should be built with the default tag and use the default provider function and CRDs.",
+			contains: []string{
+				"//go:build envtest\n",
+				"package bucket",
+				`v1beta1 "example.org/provider/apis/s3/v1beta1"`,
+				`config "example.org/provider/config"`,
+				`CRDDirectoryPaths: []string{"../../../../package/crds"},`,
+				`ExampleFile:       "../../../../examples-generated/s3/bucket.yaml",`,
+				"AddToScheme:       v1beta1.AddToScheme,",
+				"Provider:          config.GetProvider(),",
+				"controllertest.Run(t, Setup, controllertest.Options{",
+			},
+		},
+		"Configured": {
+			reason: "The test should be built with the configured tag and use the configured provider function and CRDs.",
+			tests: config.ControllerTests{
+				ProviderFn: "GetProvider(context.Background(), false)",
+				CRDsPath:   "config/crds",
+				BuildTag:   "integration",
+			},
+			contains: []string{
+				"//go:build integration\n",
+				`CRDDirectoryPaths: []string{"../../../../config/crds"},`,
+				"Provider:          config.GetProvider(context.Background(), false),",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(rootDir, "hack"), 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(rootDir, "hack", "boilerplate.go.txt"), []byte("/*\nCopyright 2023 Upbound Inc.\n*/"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			controllerFile := filepath.Join(rootDir, "internal", "controller", "s3", "bucket", "zz_controller.go")
+			if err := os.MkdirAll(filepath.Dir(controllerFile), 0750); err != nil {
+				t.Fatal(err)
+			}
+			cfg := &config.Resource{Name: "aws_s3_bucket", Kind: "Bucket"}
+			tg := NewControllerTestsGenerator(rootDir, "example.org/provider", tc.tests, nil)
+			err := tg.Generate(cfg, "example.org/provider/internal/controller/s3/bucket", controllerFile,
+				"example.org/provider/apis/s3/v1beta1", filepath.Join(rootDir, "examples-generated", "s3", "bucket.yaml"))
+			if err != nil {
+				t.Fatalf("\n%s\nGenerate(...): %v", tc.reason, err)
+			}
+			b, err := os.ReadFile(filepath.Clean(tg.FilePath(controllerFile)))
+			if err != nil {
+				t.Fatalf("cannot read the generated file: %v", err)
+			}
+			for _, s := range tc.contains {
+				if !strings.Contains(string(b), s) {
+					t.Errorf("\n%s\nGenerate(...): generated file does not contain %q:\n%s", tc.reason, s, string(b))
+				}
+			}
+		})
+	}
+}
+
+func TestHasControllerTest(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cfg    *config.Resource
+		want   bool
+	}{
+		"WithExample": {
+			reason: "The controller of a resource with an example should be tested.",
+			cfg:    &config.Resource{MetaResource: &registry.Resource{Examples: []registry.ResourceExample{{Name: "example"}}}},
+			want:   true,
+		},
+		"NoExample": {
+			reason: "The controller of a resource without an example should not be tested.",
+			cfg:    &config.Resource{MetaResource: &registry.Resource{}},
+		},
+		"Moved": {
+			reason: "The controller of a moved resource should not be tested.",
+			cfg: &config.Resource{
+				MetaResource: &registry.Resource{Examples: []registry.ResourceExample{{Name: "example"}}},
+				MovedTo:      &config.Resource{},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := hasControllerTest(tc.cfg); got != tc.want {
+				t.Errorf("\n%s\nhasControllerTest(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"bytes"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/muvaf/typewriter/pkg/wrapper"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+)
+
+// NewConversionGenerator returns a new ConversionGenerator.
+func NewConversionGenerator(pkg *types.Package, rootDir, modulePath, group string, overrides fs.FS) *ConversionGenerator {
+	shortGroup := strings.ToLower(strings.Split(group, ".")[0])
+	return &ConversionGenerator{
+		LocalDirectoryPath: filepath.Join(rootDir, "apis", shortGroup, pkg.Name()),
+		LicenseHeaderPath:  licenseHeaderPath(rootDir),
+		GroupPackagePath:   filepath.Join(modulePath, "apis", shortGroup),
+		pkg:                pkg,
+		overrides:          overrides,
+	}
+}
+
+// ConversionGenerator generates the conversion methods of the CRDs served in
+// multiple versions. The storage versions are the conversion hubs and the
+// other served versions are the spokes.
+type ConversionGenerator struct {
+	LocalDirectoryPath string
+	LicenseHeaderPath  string
+	GroupPackagePath   string
+
+	pkg       *types.Package
+	overrides fs.FS
+}
+
+// GenerateHubs writes the hub markers of the given resources whose storage
+// version is the version of this generator.
+func (cg *ConversionGenerator) GenerateHubs(cfgs []*config.Resource) error {
+	if len(cfgs) == 0 {
+		return nil
+	}
+	tmpl, err := templates.Resolve(cg.overrides, "conversion_hub.go.tmpl")
+	if err != nil {
+		return err
+	}
+	hubFile := wrapper.NewFile(cg.pkg.Path(), cg.pkg.Name(), tmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(cg.LicenseHeaderPath),
+	)
+	resources := make([]map[string]any, len(cfgs))
+	for i, cfg := range cfgs {
+		resources[i] = map[string]any{
+			"CRD": map[string]string{
+				"Kind": cfg.Kind,
+			},
+		}
+	}
+	vars := map[string]any{
+		"APIVersion": cg.pkg.Name(),
+		"Resources":  resources,
+	}
+	return errors.Wrap(
+		hubFile.Write(filepath.Join(cg.LocalDirectoryPath, "zz_generated.conversion_hubs.go"), vars, os.ModePerm),
+		"cannot write the conversion hubs file",
+	)
+}
+
+// GenerateSpokes writes the conversion methods of the given resources served
+// in the version of this generator, which is not their storage version.
+func (cg *ConversionGenerator) GenerateSpokes(cfgs []*config.Resource) error {
+	if len(cfgs) == 0 {
+		return nil
+	}
+	tmpl, err := templates.Resolve(cg.overrides, "conversion_spoke.go.tmpl")
+	if err != nil {
+		return err
+	}
+	spokeFile := wrapper.NewFile(cg.pkg.Path(), cg.pkg.Name(), tmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(cg.LicenseHeaderPath),
+	)
+	resources := make([]map[string]any, len(cfgs))
+	for i, cfg := range cfgs {
+		resources[i] = map[string]any{
+			"CRD": map[string]string{
+				"Kind": cfg.Kind,
+			},
+			"HubPackageAlias": spokeFile.Imports.UsePackage(filepath.Join(cg.GroupPackagePath, cfg.Version)),
+		}
+	}
+	vars := map[string]any{
+		"APIVersion": cg.pkg.Name(),
+		"Resources":  resources,
+	}
+	return errors.Wrap(
+		spokeFile.Write(filepath.Join(cg.LocalDirectoryPath, "zz_generated.conversion_spokes.go"), vars, os.ModePerm),
+		"cannot write the conversion spokes file",
+	)
+}
+
+// conversionWebhook is the conversion of a CRD served in multiple versions,
+// whose webhook client configuration and CA bundle are injected by the
+// Crossplane package manager.
+const conversionWebhook = `  conversion:
+    strategy: Webhook
+    webhook:
+      conversionReviewVersions:
+      - v1
+`
+
+type crdVersions struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Conversion *struct{} `json:"conversion,omitempty"`
+		Versions   []struct {
+			Name string `json:"name"`
+		} `json:"versions"`
+	} `json:"spec"`
+}
+
+// SetConversionWebhooks sets the conversion strategy of the CRDs in the
+// given directory, e.g. "package/crds", that are served in multiple
+// versions to Webhook so that the conversion webhooks generated for them
+// are called by the API server. It's meant to be run after the CRDs are
+// generated by controller-gen, which doesn't set the conversion strategy,
+// and it keeps the existing conversions.
+func SetConversionWebhooks(crdDir string) error {
+	files, err := filepath.Glob(filepath.Join(crdDir, "*.yaml"))
+	if err != nil {
+		return errors.Wrap(err, "cannot list the CRD files")
+	}
+	for _, f := range files {
+		b, err := os.ReadFile(filepath.Clean(f))
+		if err != nil {
+			return errors.Wrapf(err, "cannot read the CRD file %s", f)
+		}
+		crd := &crdVersions{}
+		if err := yaml.Unmarshal(b, crd); err != nil {
+			return errors.Wrapf(err, "cannot unmarshal the CRD file %s", f)
+		}
+		if crd.Kind != "CustomResourceDefinition" || len(crd.Spec.Versions) < 2 || crd.Spec.Conversion != nil {
+			continue
+		}
+		i := bytes.Index(b, []byte("\nspec:\n"))
+		if i == -1 {
+			return errors.Errorf("cannot find the spec of the CRD in file %s", f)
+		}
+		i += len("\nspec:\n")
+		patched := append(append(append([]byte{}, b[:i]...), conversionWebhook...), b[i:]...)
+		if err := os.WriteFile(f, patched, 0600); err != nil {
+			return errors.Wrapf(err, "cannot write the CRD file %s", f)
+		}
+	}
+	return nil
+}
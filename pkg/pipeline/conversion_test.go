@@ -0,0 +1,111 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSetConversionWebhooks(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		crd    string
+		want   string
+	}{
+		"MultiVersion": {
+			reason: "The conversion strategy of a CRD served in multiple versions should be set to Webhook.",
+			crd: `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: things.example.upbound.io
+spec:
+  group: example.upbound.io
+  versions:
+  - name: v1beta1
+  - name: v1beta2
+`,
+			want: `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: things.example.upbound.io
+spec:
+  conversion:
+    strategy: Webhook
+    webhook:
+      conversionReviewVersions:
+      - v1
+  group: example.upbound.io
+  versions:
+  - name: v1beta1
+  - name: v1beta2
+`,
+		},
+		"SingleVersion": {
+			reason: "A CRD served in a single version should not be changed.",
+			crd: `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.upbound.io
+  versions:
+  - name: v1beta1
+`,
+			want: `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.upbound.io
+  versions:
+  - name: v1beta1
+`,
+		},
+		"ExistingConversion": {
+			reason: "The existing conversion of a CRD should be kept.",
+			crd: `---
+kind: CustomResourceDefinition
+spec:
+  conversion:
+    strategy: None
+  versions:
+  - name: v1beta1
+  - name: v1beta2
+`,
+			want: `---
+kind: CustomResourceDefinition
+spec:
+  conversion:
+    strategy: None
+  versions:
+  - name: v1beta1
+  - name: v1beta2
+`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			f := filepath.Join(dir, "example.upbound.io_things.yaml")
+			if err := os.WriteFile(f, []byte(tc.crd), 0600); err != nil {
+				t.Fatalf("cannot write the CRD file: %v", err)
+			}
+			if err := SetConversionWebhooks(dir); err != nil {
+				t.Fatalf("SetConversionWebhooks(...): %v", err)
+			}
+			got, err := os.ReadFile(f)
+			if err != nil {
+				t.Fatalf("cannot read the CRD file: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("\n%s\nSetConversionWebhooks(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
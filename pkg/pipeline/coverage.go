@@ -0,0 +1,126 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+const (
+	externalNameIdentifierFromProvider = "IdentifierFromProvider"
+	externalNameNameAsIdentifier       = "NameAsIdentifier"
+	externalNameParameterAsIdentifier  = "ParameterAsIdentifier"
+	externalNameTemplated              = "TemplatedStringAsIdentifier"
+	externalNameCustom                 = "Custom"
+)
+
+// templatedExternalName is used to detect the external name configurations
+// built with config.TemplatedStringAsIdentifier, whose functions are
+// closures of the same function literals.
+var templatedExternalName = config.TemplatedStringAsIdentifier("", "{{ .external_name }}")
+
+// CoverageEntry is the entry of a Terraform resource in the coverage report.
+type CoverageEntry struct {
+	TerraformResource string            `json:"terraformResource"`
+	Generated         bool              `json:"generated"`
+	DataSource        bool              `json:"dataSource,omitempty"`
+	Kind              string            `json:"kind,omitempty"`
+	Group             string            `json:"group,omitempty"`
+	Version           string            `json:"version,omitempty"`
+	ExternalName      string            `json:"externalName,omitempty"`
+	References        int               `json:"references,omitempty"`
+	SkipReason        config.SkipReason `json:"skipReason,omitempty"`
+}
+
+// CoverageReport is the machine-readable report of the coverage of the
+// Terraform resources of a provider by its managed resources.
+type CoverageReport struct {
+	Total     int             `json:"total"`
+	Generated int             `json:"generated"`
+	Skipped   int             `json:"skipped"`
+	Resources []CoverageEntry `json:"resources"`
+}
+
+// NewCoverageReport returns the coverage report of the given provider with
+// the entries of its resources, the Terraform resources being generated
+// multiple times, e.g. with aliases, having multiple entries, and its
+// skipped resources sorted by their Terraform names and Kinds.
+func NewCoverageReport(pc *config.Provider) *CoverageReport {
+	r := &CoverageReport{}
+	for _, res := range pc.Resources {
+		r.Resources = append(r.Resources, CoverageEntry{
+			TerraformResource: res.Name,
+			Generated:         true,
+			DataSource:        res.DataSource,
+			Kind:              res.Kind,
+			Group:             apiGroup(pc, res),
+			Version:           res.Version,
+			ExternalName:      externalNameStrategy(res.ExternalName),
+			References:        len(res.References),
+		})
+		r.Generated++
+	}
+	for _, name := range pc.GetSkippedResourceNames() {
+		r.Resources = append(r.Resources, CoverageEntry{
+			TerraformResource: name,
+			SkipReason:        pc.GetSkipReason(name),
+		})
+		r.Skipped++
+	}
+	r.Total = r.Generated + r.Skipped
+	sort.Slice(r.Resources, func(i, j int) bool {
+		if r.Resources[i].TerraformResource != r.Resources[j].TerraformResource {
+			return r.Resources[i].TerraformResource < r.Resources[j].TerraformResource
+		}
+		return r.Resources[i].Kind < r.Resources[j].Kind
+	})
+	return r
+}
+
+// Write writes the coverage report to the given file.
+func (r *CoverageReport) Write(path string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal coverage report")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return errors.Wrapf(err, "cannot mkdir %s", filepath.Dir(path))
+	}
+	return errors.Wrap(os.WriteFile(path, append(b, '\n'), 0600), "cannot write coverage report file")
+}
+
+// apiGroup returns the API group of the given resource of the given
+// provider.
+func apiGroup(pc *config.Provider, r *config.Resource) string {
+	if r.ShortGroup == "" {
+		return pc.RootGroup
+	}
+	return strings.ToLower(r.ShortGroup) + "." + pc.RootGroup
+}
+
+// externalNameStrategy returns the name of the built-in external name
+// configuration the given one is built with, or "Custom".
+func externalNameStrategy(e config.ExternalName) string {
+	switch {
+	case sameFunc(e.GetIDFn, templatedExternalName.GetIDFn) && sameFunc(e.GetExternalNameFn, templatedExternalName.GetExternalNameFn):
+		return externalNameTemplated
+	case hasCustomExternalName(e):
+		return externalNameCustom
+	case e.DisableNameInitializer:
+		return externalNameIdentifierFromProvider
+	case len(e.IdentifierFields) == 1:
+		return externalNameParameterAsIdentifier
+	default:
+		return externalNameNameAsIdentifier
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+func TestExternalNameStrategy(t *testing.T) {
+	custom := config.NameAsIdentifier
+	custom.GetIDFn = func(_ context.Context, en string, _ map[string]any, _ map[string]any) (string, error) {
+		return "prefix/" + en, nil
+	}
+	cases := map[string]struct {
+		reason string
+		e      config.ExternalName
+		want   string
+	}{
+		"IdentifierFromProvider": {
+			reason: "IdentifierFromProvider should be detected.",
+			e:      config.IdentifierFromProvider,
+			want:   externalNameIdentifierFromProvider,
+		},
+		"NameAsIdentifier": {
+			reason: "NameAsIdentifier should be detected.",
+			e:      config.NameAsIdentifier,
+			want:   externalNameNameAsIdentifier,
+		},
+		"ParameterAsIdentifier": {
+			reason: "ParameterAsIdentifier should be detected.",
+			e:      config.ParameterAsIdentifier("bucket"),
+			want:   externalNameParameterAsIdentifier,
+		},
+		"TemplatedStringAsIdentifier": {
+			reason: "TemplatedStringAsIdentifier should be detected regardless of its template.",
+			e:      config.TemplatedStringAsIdentifier("name", "{{ .parameters.region }}/{{ .external_name }}"),
+			want:   externalNameTemplated,
+		},
+		"Custom": {
+			reason: "An external name configuration with custom functions should be reported as custom.",
+			e:      custom,
+			want:   externalNameCustom,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, externalNameStrategy(tc.e)); diff != "" {
+				t.Errorf("\n%s\nexternalNameStrategy(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewCoverageReport(t *testing.T) {
+	pc := &config.Provider{
+		RootGroup: "aws.upbound.io",
+		Resources: map[string]*config.Resource{
+			"aws_vpc": {
+				Name:         "aws_vpc",
+				Kind:         "VPC",
+				ShortGroup:   "ec2",
+				Version:      "v1beta1",
+				ExternalName: config.IdentifierFromProvider,
+			},
+			"aws_subnet": {
+				Name:         "aws_subnet",
+				Kind:         "Subnet",
+				ShortGroup:   "ec2",
+				Version:      "v1beta1",
+				ExternalName: config.IdentifierFromProvider,
+				References:   config.References{"vpc_id": {Type: "VPC"}},
+			},
+			config.DataSourceKey("aws_vpc"): {
+				Name:         "aws_vpc",
+				Kind:         "DataVPC",
+				ShortGroup:   "ec2",
+				Version:      "v1beta1",
+				DataSource:   true,
+				ExternalName: config.IdentifierFromProvider,
+			},
+		},
+	}
+	want := &CoverageReport{
+		Total:     3,
+		Generated: 3,
+		Resources: []CoverageEntry{
+			{
+				TerraformResource: "aws_subnet",
+				Generated:         true,
+				Kind:              "Subnet",
+				Group:             "ec2.aws.upbound.io",
+				Version:           "v1beta1",
+				ExternalName:      externalNameIdentifierFromProvider,
+				References:        1,
+			},
+			{
+				TerraformResource: "aws_vpc",
+				Generated:         true,
+				DataSource:        true,
+				Kind:              "DataVPC",
+				Group:             "ec2.aws.upbound.io",
+				Version:           "v1beta1",
+				ExternalName:      externalNameIdentifierFromProvider,
+			},
+			{
+				TerraformResource: "aws_vpc",
+				Generated:         true,
+				Kind:              "VPC",
+				Group:             "ec2.aws.upbound.io",
+				Version:           "v1beta1",
+				ExternalName:      externalNameIdentifierFromProvider,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, NewCoverageReport(pc)); diff != "" {
+		t.Errorf("\nThe generated resources should be reported sorted by their Terraform names and Kinds.\nNewCoverageReport(...): -want, +got:\n%s", diff)
+	}
+}
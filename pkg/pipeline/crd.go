@@ -5,12 +5,14 @@ Copyright 2021 Upbound Inc.
 package pipeline
 
 import (
+	"encoding/json"
 	"fmt"
 	"go/types"
 	"os"
 	"path/filepath"
 	"strings"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	twtypes "github.com/muvaf/typewriter/pkg/types"
 	"github.com/muvaf/typewriter/pkg/wrapper"
@@ -27,15 +29,18 @@ const (
 	GenStatement = "// Code generated by upjet. DO NOT EDIT."
 
 	apiRoot = "apis"
+
+	typeNamesFile = "type_names.json"
 )
 
 // NewCRDGenerator returns a new CRDGenerator.
 func NewCRDGenerator(pkg *types.Package, rootDir, providerShortName, group, version string) *CRDGenerator {
 	return &CRDGenerator{
 		LocalDirectoryPath: filepath.Join(rootDir, apiRoot, strings.ToLower(strings.Split(group, ".")[0]), version),
-		LicenseHeaderPath:  filepath.Join(rootDir, "hack", "boilerplate.go.txt"),
+		LicenseHeaderPath:  licenseHeaderPath(rootDir),
 		Group:              group,
 		ProviderShortName:  providerShortName,
+		Template:           templates.CRDTypesTemplate,
 		pkg:                pkg,
 	}
 }
@@ -48,13 +53,56 @@ type CRDGenerator struct {
 	ProviderShortName  string
 	LicenseHeaderPath  string
 	Generated          *tjtypes.Generated
+	// MaxTypeNameLength is the maximum length of the generated type names
+	// of the blocks. Zero disables their truncation.
+	MaxTypeNameLength int
+	// StrictIgnoredFields fails the generation if any of the
+	// late-initialization ignored fields doesn't match a field.
+	StrictIgnoredFields bool
+	// SharedBlocks are the types of the blocks shared by the resources.
+	SharedBlocks map[string]*tjtypes.SharedBlock
+	// Template is the template of the CRD types files.
+	Template string
+
+	pkg    *types.Package
+	pinned *tjtypes.PinnedTypeNames
+}
 
-	pkg *types.Package
+// PinTypeNames reads the mapping of the type names from the type names file
+// of the API version package, if it exists, and pins the names in it for the
+// subsequently generated CRDs. It returns the mapping read.
+func (cg *CRDGenerator) PinTypeNames() (tjtypes.TypeNameMapping, error) {
+	m := tjtypes.TypeNameMapping{}
+	b, err := os.ReadFile(filepath.Join(cg.LocalDirectoryPath, typeNamesFile))
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, errors.Wrap(err, "cannot read the type names file")
+	default:
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, errors.Wrap(err, "cannot unmarshal the type names file")
+		}
+	}
+	cg.pinned = tjtypes.NewPinnedTypeNames(cg.pkg, m)
+	return m, nil
+}
+
+// WriteTypeNames writes the given mapping of the type names to the type
+// names file of the API version package.
+func (cg *CRDGenerator) WriteTypeNames(m tjtypes.TypeNameMapping) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal the type names")
+	}
+	if err := os.MkdirAll(cg.LocalDirectoryPath, os.ModePerm); err != nil {
+		return errors.Wrap(err, "cannot create the API version directory")
+	}
+	return errors.Wrap(os.WriteFile(filepath.Join(cg.LocalDirectoryPath, typeNamesFile), append(b, '\n'), 0600), "cannot write the type names file")
 }
 
 // Generate builds and writes a new CRD out of Terraform resource definition.
 func (cg *CRDGenerator) Generate(cfg *config.Resource) (string, error) {
-	file := wrapper.NewFile(cg.pkg.Path(), cg.pkg.Name(), templates.CRDTypesTemplate,
+	file := wrapper.NewFile(cg.pkg.Path(), cg.pkg.Name(), cg.Template,
 		wrapper.WithGenStatement(GenStatement),
 		wrapper.WithHeaderPath(cg.LicenseHeaderPath),
 	)
@@ -65,7 +113,7 @@ func (cg *CRDGenerator) Generate(cfg *config.Resource) (string, error) {
 		Computed: true,
 	}
 
-	gen, err := tjtypes.NewBuilder(cg.pkg).Build(cfg)
+	gen, err := tjtypes.NewBuilder(cg.pkg, tjtypes.WithPinnedTypeNames(cg.pinned), tjtypes.WithMaxTypeNameLength(cg.MaxTypeNameLength), tjtypes.WithStrictIgnoredFields(cg.StrictIgnoredFields), tjtypes.WithSharedBlocks(cg.SharedBlocks)).Build(cfg)
 	if err != nil {
 		return "", errors.Wrapf(err, "cannot build types for %s", cfg.Kind)
 	}
@@ -85,13 +133,24 @@ func (cg *CRDGenerator) Generate(cfg *config.Resource) (string, error) {
 	vars := map[string]any{
 		"Types": typesStr,
 		"CRD": map[string]string{
-			"APIVersion":      cfg.Version,
-			"Group":           cg.Group,
-			"Kind":            cfg.Kind,
-			"ForProviderType": gen.ForProviderType.Obj().Name(),
-			"AtProviderType":  gen.AtProviderType.Obj().Name(),
-			"ValidationRules": gen.ValidationRules,
-			"Path":            cfg.Path,
+			"APIVersion":        cg.pkg.Name(),
+			"Group":             cg.Group,
+			"Kind":              cfg.Kind,
+			"ForProviderType":   gen.ForProviderType.Obj().Name(),
+			"InitProviderType":  gen.InitProviderType.Obj().Name(),
+			"AtProviderType":    gen.AtProviderType.Obj().Name(),
+			"ValidationRules":   gen.ValidationRules,
+			"Path":              cfg.Path,
+			"Scope":             scope(cfg),
+			"ProviderOverrides": providerOverrides(cfg.ProviderConfigOverrides),
+			"Categories":        categories(cfg.Categories),
+			"ShortNames":        strings.Join(cfg.ShortNames, ","),
+			"PrinterColumns":    printerColumns(gen.PrinterColumns),
+			"StorageVersion":    storageVersion(cfg, cg.pkg.Name()),
+			"Deprecation":       movedDeprecation(cfg, cg.Group),
+			"DocsURL":           cfg.DocsURL,
+
+			"ManagementPolicies": managementPolicies(cfg.ManagementPolicies),
 		},
 		"Provider": map[string]string{
 			"ShortName": cg.ProviderShortName,
@@ -102,8 +161,115 @@ func (cg *CRDGenerator) Generate(cfg *config.Resource) (string, error) {
 		// remove sentences with the `terraform` keyword in them
 		vars["CRD"].(map[string]string)["Description"] = tjpkg.FilterDescription(cfg.MetaResource.Description, tjpkg.TerraformKeyword)
 	}
-	filePath := filepath.Join(cg.LocalDirectoryPath, fmt.Sprintf("zz_%s_types.go", strings.ToLower(cfg.Kind)))
-	return gen.ForProviderType.Obj().Name(), errors.Wrap(file.Write(filePath, vars, os.ModePerm), "cannot write crd file")
+	return gen.ForProviderType.Obj().Name(), errors.Wrap(file.Write(cg.FilePath(cfg), vars, os.ModePerm), "cannot write crd file")
+}
+
+// FilePath returns the path of the CRD types file of the given resource.
+func (cg *CRDGenerator) FilePath(cfg *config.Resource) string {
+	return filepath.Join(cg.LocalDirectoryPath, fmt.Sprintf("zz_%s_types.go", strings.ToLower(cfg.Kind)))
+}
+
+// scope returns the scope of the CRD of the given resource.
+func scope(cfg *config.Resource) string {
+	if cfg.Namespaced() {
+		return string(config.ScopeNamespaced)
+	}
+	return string(config.ScopeCluster)
+}
+
+// providerOverrides returns the comment of the spec field overriding the
+// given provider configuration arguments with a validation rule rejecting
+// the other arguments. It returns an empty string if no argument can be
+// overridden.
+func providerOverrides(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = "'" + a + "'"
+	}
+	return fmt.Sprintf(`// ProviderOverrides overrides the arguments of the provider configuration
+	// of the ProviderConfig for this managed resource: %s.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self.all(k, k in [%s])",message="only %s can be overridden"`,
+		strings.Join(args, ", "), strings.Join(quoted, ", "), strings.Join(args, ", "))
+}
+
+// storageVersion returns "true" if the CRD of the given resource is served in
+// multiple versions and the specified version is its storage version.
+func storageVersion(cfg *config.Resource, version string) string {
+	if len(cfg.ServedVersions) == 0 || cfg.Version != version {
+		return ""
+	}
+	return "true"
+}
+
+// movedDeprecation returns the marker deprecating the CRD of the given
+// resource in the given group, prefixed with a new line, if it's a previous
+// Kind of a moved resource.
+func movedDeprecation(cfg *config.Resource, group string) string {
+	if cfg.MovedTo == nil {
+		return ""
+	}
+	return fmt.Sprintf("\n// +kubebuilder:deprecatedversion:warning=%q", fmt.Sprintf("%s is moved to %s.%s/%s", cfg.Kind, cfg.MovedTo.Kind, movedGroup(cfg, group), cfg.MovedTo.Version))
+}
+
+// movedGroup returns the API group of the current Kind of the given moved
+// resource whose previous Kind is in the given group.
+func movedGroup(cfg *config.Resource, group string) string {
+	root := group
+	if cfg.ShortGroup != "" {
+		root = strings.TrimPrefix(group, strings.ToLower(cfg.ShortGroup)+".")
+	}
+	if cfg.MovedTo.ShortGroup == "" {
+		return root
+	}
+	return strings.ToLower(cfg.MovedTo.ShortGroup) + "." + root
+}
+
+// managementPolicies returns the comment lines documenting the given
+// supported and default management policies, each on a new line.
+func managementPolicies(mp config.ManagementPolicies) string {
+	var sb strings.Builder
+	if len(mp.Supported) > 0 {
+		supported := make([]string, len(mp.Supported))
+		for i, p := range mp.Supported {
+			supported[i] = policiesString(p)
+		}
+		sb.WriteString(fmt.Sprintf("\n// The supported management policies are %s.", strings.Join(supported, ", ")))
+	}
+	if len(mp.Default) > 0 {
+		sb.WriteString(fmt.Sprintf("\n// The default management policies are %s.", policiesString(mp.Default)))
+	}
+	return sb.String()
+}
+
+func policiesString(p xpv1.ManagementPolicies) string {
+	actions := make([]string, len(p))
+	for i, a := range p {
+		actions[i] = fmt.Sprintf("%q", a)
+	}
+	return "[" + strings.Join(actions, ", ") + "]"
+}
+
+// categories returns the given additional CRD categories to be appended to
+// the default categories in the resource marker.
+func categories(c []string) string {
+	if len(c) == 0 {
+		return ""
+	}
+	return "," + strings.Join(c, ",")
+}
+
+// printerColumns returns the printer column markers of the given additional
+// printer columns, each on a new line.
+func printerColumns(cols []config.PrinterColumn) string {
+	var sb strings.Builder
+	for _, c := range cols {
+		sb.WriteString(fmt.Sprintf("\n// +kubebuilder:printcolumn:name=%q,type=%q,JSONPath=%q", c.Name, c.Type, c.JSONPath))
+	}
+	return sb.String()
 }
 
 func deleteOmittedFields(sch map[string]*schema.Schema, omittedFields []string) {
@@ -9,7 +9,10 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/upbound/upjet/pkg/config"
 )
 
 func TestDeleteOmittedFields(t *testing.T) {
@@ -160,3 +163,62 @@ func TestDeleteOmittedFields(t *testing.T) {
 		})
 	}
 }
+
+func TestPrinterColumns(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cols   []config.PrinterColumn
+		want   string
+	}{
+		"NoColumns": {
+			reason: "Should not render any markers if there are no printer columns.",
+		},
+		"Columns": {
+			reason: "Should render a marker for each printer column on a new line.",
+			cols: []config.PrinterColumn{
+				{Name: "REGION", Type: "string", JSONPath: ".spec.forProvider.region"},
+				{Name: "STATE", Type: "string", JSONPath: ".status.atProvider.state"},
+			},
+			want: "\n// +kubebuilder:printcolumn:name=\"REGION\",type=\"string\",JSONPath=\".spec.forProvider.region\"" +
+				"\n// +kubebuilder:printcolumn:name=\"STATE\",type=\"string\",JSONPath=\".status.atProvider.state\"",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, printerColumns(tc.cols)); diff != "" {
+				t.Errorf("\n%s\nprinterColumns(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestManagementPolicies(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		policies config.ManagementPolicies
+		want     string
+	}{
+		"NoPolicies": {
+			reason: "Should not document any management policies if none are configured.",
+		},
+		"Policies": {
+			reason: "Should document the supported and the default management policies on new lines.",
+			policies: config.ManagementPolicies{
+				Supported: []xpv1.ManagementPolicies{
+					{xpv1.ManagementActionObserve},
+					{xpv1.ManagementActionObserve, xpv1.ManagementActionDelete},
+				},
+				Default: xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+			},
+			want: "\n// The supported management policies are [\"Observe\"], [\"Observe\", \"Delete\"]." +
+				"\n// The default management policies are [\"Observe\"].",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, managementPolicies(tc.policies)); diff != "" {
+				t.Errorf("\n%s\nmanagementPolicies(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
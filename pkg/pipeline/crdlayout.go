@@ -0,0 +1,151 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// CRDLayout is the layout of the CRD files in a directory.
+type CRDLayout string
+
+const (
+	// CRDLayoutPerKind is the layout of controller-gen with a file for each
+	// CRD, e.g. "ec2.aws.upbound.io_vpcs.yaml".
+	CRDLayoutPerKind CRDLayout = "PerKind"
+	// CRDLayoutPerGroup is the layout with a file for each API group, e.g.
+	// "ec2.aws.upbound.io.yaml", with the CRDs of the group sorted by their
+	// plural names.
+	CRDLayoutPerGroup CRDLayout = "PerGroup"
+	// CRDLayoutBundle is the layout with a single "crds.yaml" file with all
+	// the CRDs sorted by their groups and plural names.
+	CRDLayoutBundle CRDLayout = "Bundle"
+)
+
+const crdBundleFile = "crds.yaml"
+
+// crdDocument is a CRD read from a CRD file.
+type crdDocument struct {
+	crdNames
+	file string
+	doc  []byte
+}
+
+// LayoutCRDs rewrites the CRD files in the given directory, e.g.
+// "package/crds", in the given layout. The CRD files can be in any of the
+// layouts, so it's meant to be run after the CRDs are generated by
+// controller-gen and after SetConversionWebhooks, which only patches the
+// CRD files in the CRDLayoutPerKind layout.
+func LayoutCRDs(crdDir string, layout CRDLayout) error {
+	switch layout {
+	case CRDLayoutPerKind, CRDLayoutPerGroup, CRDLayoutBundle:
+	default:
+		return errors.Errorf("unknown CRD layout %q", layout)
+	}
+	crds, err := readCRDs(crdDir)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(crds, func(i, j int) bool {
+		if crds[i].Spec.Group != crds[j].Spec.Group {
+			return crds[i].Spec.Group < crds[j].Spec.Group
+		}
+		return crds[i].Spec.Names.Plural < crds[j].Spec.Names.Plural
+	})
+	files := map[string]*bytes.Buffer{}
+	var names []string
+	for _, crd := range crds {
+		var name string
+		switch layout {
+		case CRDLayoutPerKind:
+			name = fmt.Sprintf("%s_%s.yaml", crd.Spec.Group, crd.Spec.Names.Plural)
+		case CRDLayoutPerGroup:
+			name = fmt.Sprintf("%s.yaml", crd.Spec.Group)
+		case CRDLayoutBundle:
+			name = crdBundleFile
+		}
+		if files[name] == nil {
+			files[name] = &bytes.Buffer{}
+			names = append(names, name)
+		}
+		files[name].WriteString("---\n")
+		files[name].Write(crd.doc)
+		files[name].WriteString("\n")
+	}
+	for _, name := range names {
+		f := filepath.Join(crdDir, name)
+		if err := os.WriteFile(f, files[name].Bytes(), 0600); err != nil {
+			return errors.Wrapf(err, "cannot write the CRD file %s", f)
+		}
+	}
+	// the CRD files of the previous layout are removed.
+	for _, crd := range crds {
+		if _, ok := files[filepath.Base(crd.file)]; ok {
+			continue
+		}
+		if err := os.Remove(crd.file); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "cannot remove the CRD file %s", crd.file)
+		}
+	}
+	return nil
+}
+
+// readCRDs returns the CRDs in the files in the given directory, each of
+// which may have multiple CRDs. The files that don't have any CRDs are
+// ignored but a file with both CRDs and other documents is rejected as its
+// other documents would be lost if it's rewritten.
+func readCRDs(crdDir string) ([]crdDocument, error) {
+	files, err := filepath.Glob(filepath.Join(crdDir, "*.yaml"))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot list the CRD files")
+	}
+	var crds []crdDocument
+	for _, f := range files {
+		b, err := os.ReadFile(filepath.Clean(f))
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read the CRD file %s", f)
+		}
+		var fileCRDs []crdDocument
+		others := false
+		r := k8syaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(b)))
+		for {
+			doc, err := r.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot read the CRD file %s", f)
+			}
+			doc = bytes.TrimSpace(doc)
+			if len(doc) == 0 || bytes.Equal(doc, []byte("---")) {
+				continue
+			}
+			crd := crdDocument{file: f, doc: bytes.TrimPrefix(doc, []byte("---\n"))}
+			if err := yaml.Unmarshal(crd.doc, &crd.crdNames); err != nil {
+				return nil, errors.Wrapf(err, "cannot unmarshal the CRD file %s", f)
+			}
+			if crd.Kind != "CustomResourceDefinition" {
+				others = true
+				continue
+			}
+			fileCRDs = append(fileCRDs, crd)
+		}
+		if others && len(fileCRDs) > 0 {
+			return nil, errors.Errorf("CRD file %s has documents other than CRDs", f)
+		}
+		crds = append(crds, fileCRDs...)
+	}
+	return crds, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLayoutCRDs(t *testing.T) {
+	const (
+		vpcs = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: ec2.aws.upbound.io
+  names:
+    plural: vpcs
+`
+		subnets = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: ec2.aws.upbound.io
+  names:
+    plural: subnets
+`
+		buckets = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: s3.aws.upbound.io
+  names:
+    plural: buckets
+`
+	)
+	perKind := map[string]string{
+		"ec2.aws.upbound.io_vpcs.yaml":    "---\n" + vpcs,
+		"ec2.aws.upbound.io_subnets.yaml": "---\n" + subnets,
+		"s3.aws.upbound.io_buckets.yaml":  "---\n" + buckets,
+	}
+	cases := map[string]struct {
+		reason string
+		files  map[string]string
+		layout CRDLayout
+		want   map[string]string
+	}{
+		"PerKind": {
+			reason: "The CRD files of controller-gen should be kept as they are.",
+			files:  perKind,
+			layout: CRDLayoutPerKind,
+			want:   perKind,
+		},
+		"PerGroup": {
+			reason: "The CRDs should be aggregated in the files of their groups sorted by their plural names.",
+			files:  perKind,
+			layout: CRDLayoutPerGroup,
+			want: map[string]string{
+				"ec2.aws.upbound.io.yaml": "---\n" + subnets + "---\n" + vpcs,
+				"s3.aws.upbound.io.yaml":  "---\n" + buckets,
+			},
+		},
+		"Bundle": {
+			reason: "The CRDs should be aggregated in a single file sorted by their groups and plural names.",
+			files:  perKind,
+			layout: CRDLayoutBundle,
+			want: map[string]string{
+				"crds.yaml": "---\n" + subnets + "---\n" + vpcs + "---\n" + buckets,
+			},
+		},
+		"BundleToPerKind": {
+			reason: "The aggregated CRDs should be split into the files of controller-gen.",
+			files: map[string]string{
+				"crds.yaml": "---\n" + subnets + "---\n" + vpcs + "---\n" + buckets,
+			},
+			layout: CRDLayoutPerKind,
+			want:   perKind,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for f, c := range tc.files {
+				if err := os.WriteFile(filepath.Join(dir, f), []byte(c), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := LayoutCRDs(dir, tc.layout); err != nil {
+				t.Fatalf("\n%s\nLayoutCRDs(...): %v", tc.reason, err)
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]string{}
+			for _, e := range entries {
+				b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				got[e.Name()] = string(b)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nLayoutCRDs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
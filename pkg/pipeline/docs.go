@@ -0,0 +1,285 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	twtypes "github.com/muvaf/typewriter/pkg/types"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	tjpkg "github.com/upbound/upjet/pkg"
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+	tjtypes "github.com/upbound/upjet/pkg/types"
+)
+
+const docsRoot = "docs/api"
+
+// NewDocsGenerator returns a new DocsGenerator.
+func NewDocsGenerator(rootDir string, overrides fs.FS) *DocsGenerator {
+	return &DocsGenerator{
+		LocalDirectoryPath: filepath.Join(rootDir, docsRoot),
+		overrides:          overrides,
+	}
+}
+
+// DocsGenerator generates the Markdown API reference of the managed
+// resources. It's safe for concurrent use.
+type DocsGenerator struct {
+	LocalDirectoryPath string
+	// Frontmatter enables the YAML frontmatter of the generated pages.
+	Frontmatter bool
+
+	overrides fs.FS
+
+	mu        sync.Mutex
+	resources []docsIndexEntry
+}
+
+type docsIndexEntry struct {
+	Kind              string
+	APIVersion        string
+	TerraformResource string
+	Path              string
+}
+
+// docsFrontmatter is the YAML frontmatter of an API reference page.
+type docsFrontmatter struct {
+	Title       string `json:"title"`
+	Group       string `json:"group,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Example is the path of the example manifest relative to the page.
+	Example string `json:"example,omitempty"`
+}
+
+type docsType struct {
+	Name   string
+	Fields []docsField
+}
+
+type docsField struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// Generate writes the API reference of the given resource generated in the
+// given group and version with the given types.
+func (dg *DocsGenerator) Generate(cfg *config.Resource, group, version string, gen *tjtypes.Generated) error {
+	groupPrefix := strings.ToLower(strings.Split(group, ".")[0])
+	vars := map[string]any{
+		"GenStatement":      docsGenStatement(),
+		"Kind":              cfg.Kind,
+		"APIVersion":        fmt.Sprintf("%s/%s", group, version),
+		"Scope":             scope(cfg),
+		"TerraformResource": cfg.Name,
+		"DocsURL":           cfg.DocsURL,
+		"ForProvider":       docsTypes(gen.ForProviderType, gen.Comments),
+		"AtProvider":        docsTypes(gen.AtProviderType, gen.Comments),
+	}
+	if cfg.MetaResource != nil {
+		vars["Description"] = tjpkg.FilterDescription(cfg.MetaResource.Description, tjpkg.TerraformKeyword)
+		vars["ImportStatements"] = cfg.MetaResource.ImportStatements
+		// the example manifests are only generated for the storage versions.
+		if len(cfg.MetaResource.Examples) > 0 && cfg.Version == version && cfg.MovedTo == nil {
+			vars["ExamplePath"] = fmt.Sprintf("../../../../examples-generated/%s/%s.yaml", groupPrefix, strings.ToLower(cfg.Kind))
+		}
+	}
+	if dg.Frontmatter {
+		fm := docsFrontmatter{
+			Title:   cfg.Kind,
+			Group:   group,
+			Version: version,
+		}
+		fm.Description, _ = vars["Description"].(string)
+		fm.Description = strings.Join(strings.Fields(fm.Description), " ")
+		fm.Example, _ = vars["ExamplePath"].(string)
+		b, err := yaml.Marshal(fm)
+		if err != nil {
+			return errors.Wrap(err, "cannot marshal the docs frontmatter")
+		}
+		vars["Frontmatter"] = string(b)
+	}
+	docsTemplate, err := dg.template("docs.md.tmpl")
+	if err != nil {
+		return err
+	}
+	var buff bytes.Buffer
+	if err := docsTemplate.Execute(&buff, vars); err != nil {
+		return errors.Wrap(err, "cannot execute the docs template")
+	}
+	f := dg.FilePath(cfg, group, version)
+	if err := os.MkdirAll(filepath.Dir(f), 0750); err != nil {
+		return errors.Wrapf(err, "cannot mkdir %s", filepath.Dir(f))
+	}
+	dg.AddToIndex(cfg, group, version)
+	b := append(bytes.TrimRight(buff.Bytes(), "\n"), '\n')
+	return errors.Wrap(os.WriteFile(f, b, 0600), "cannot write the docs file")
+}
+
+// FilePath returns the path of the API reference of the given resource
+// generated in the given group and version.
+func (dg *DocsGenerator) FilePath(cfg *config.Resource, group, version string) string {
+	return filepath.Join(dg.LocalDirectoryPath, docsPath(cfg, group, version))
+}
+
+// AddToIndex adds the API reference of the given resource generated in the
+// given group and version to the index without generating it.
+func (dg *DocsGenerator) AddToIndex(cfg *config.Resource, group, version string) {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	dg.resources = append(dg.resources, docsIndexEntry{
+		Kind:              cfg.Kind,
+		APIVersion:        fmt.Sprintf("%s/%s", group, version),
+		TerraformResource: cfg.Name,
+		Path:              filepath.ToSlash(docsPath(cfg, group, version)),
+	})
+}
+
+// docsPath returns the path of the API reference of the given resource
+// generated in the given group and version relative to the docs root.
+func docsPath(cfg *config.Resource, group, version string) string {
+	return filepath.Join(strings.ToLower(strings.Split(group, ".")[0]), version, fmt.Sprintf("%s.md", strings.ToLower(cfg.Kind)))
+}
+
+// GenerateIndex writes the index of the API references generated, sorted by
+// their API versions and Kinds.
+func (dg *DocsGenerator) GenerateIndex() error {
+	sort.Slice(dg.resources, func(i, j int) bool {
+		if dg.resources[i].APIVersion != dg.resources[j].APIVersion {
+			return dg.resources[i].APIVersion < dg.resources[j].APIVersion
+		}
+		return dg.resources[i].Kind < dg.resources[j].Kind
+	})
+	vars := map[string]any{
+		"GenStatement": docsGenStatement(),
+		"Resources":    dg.resources,
+	}
+	if dg.Frontmatter {
+		b, err := yaml.Marshal(docsFrontmatter{Title: "API Reference"})
+		if err != nil {
+			return errors.Wrap(err, "cannot marshal the docs index frontmatter")
+		}
+		vars["Frontmatter"] = string(b)
+	}
+	docsIndexTemplate, err := dg.template("docs_index.md.tmpl")
+	if err != nil {
+		return err
+	}
+	var buff bytes.Buffer
+	if err := docsIndexTemplate.Execute(&buff, vars); err != nil {
+		return errors.Wrap(err, "cannot execute the docs index template")
+	}
+	if err := os.MkdirAll(dg.LocalDirectoryPath, 0750); err != nil {
+		return errors.Wrapf(err, "cannot mkdir %s", dg.LocalDirectoryPath)
+	}
+	return errors.Wrap(os.WriteFile(filepath.Join(dg.LocalDirectoryPath, "README.md"), buff.Bytes(), 0600), "cannot write the docs index file")
+}
+
+// template returns the parsed template with the given name, which is
+// resolved with the overrides.
+func (dg *DocsGenerator) template(name string) (*template.Template, error) {
+	tmpl, err := templates.Resolve(dg.overrides, name)
+	if err != nil {
+		return nil, err
+	}
+	t, err := template.New(name).Parse(tmpl)
+	return t, errors.Wrapf(err, "cannot parse the template %s", name)
+}
+
+func docsGenStatement() string {
+	return fmt.Sprintf("<!-- %s -->", strings.TrimPrefix(GenStatement, "// "))
+}
+
+// docsTypes returns the given type and the types of its package reachable
+// from it in the breadth-first order.
+func docsTypes(root *types.Named, comments twtypes.Comments) []docsType {
+	var result []docsType
+	queue := []*types.Named{root}
+	seen := map[*types.Named]struct{}{root: {}}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		dt := docsType{Name: n.Obj().Name()}
+		st, ok := n.Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			f := st.Field(i)
+			name := strings.Split(reflect.StructTag(st.Tag(i)).Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name()
+			}
+			dt.Fields = append(dt.Fields, docsField{
+				Name: name,
+				Type: docsTypeString(f.Type(), root.Obj().Pkg(), func(nt *types.Named) {
+					if _, ok := seen[nt]; !ok {
+						seen[nt] = struct{}{}
+						queue = append(queue, nt)
+					}
+				}),
+				Description: docsDescription(comments[twtypes.QualifiedFieldPath(n.Obj(), f.Name())]),
+			})
+		}
+		result = append(result, dt)
+	}
+	return result
+}
+
+// docsTypeString returns the Markdown representation of the given type,
+// linking to the sections of the named types in the given package, which
+// are passed to the given function.
+func docsTypeString(t types.Type, pkg *types.Package, fn func(*types.Named)) string {
+	switch tt := t.(type) {
+	case *types.Pointer:
+		return docsTypeString(tt.Elem(), pkg, fn)
+	case *types.Slice:
+		return "[]" + docsTypeString(tt.Elem(), pkg, fn)
+	case *types.Map:
+		return fmt.Sprintf("map[%s]%s", docsTypeString(tt.Key(), pkg, fn), docsTypeString(tt.Elem(), pkg, fn))
+	case *types.Named:
+		if tt.Obj().Pkg() == nil {
+			return tt.Obj().Name()
+		}
+		if tt.Obj().Pkg().Path() != pkg.Path() {
+			return fmt.Sprintf("%s.%s", tt.Obj().Pkg().Name(), tt.Obj().Name())
+		}
+		fn(tt)
+		return fmt.Sprintf("[%s](#%s)", tt.Obj().Name(), strings.ToLower(tt.Obj().Name()))
+	default:
+		return t.String()
+	}
+}
+
+// docsDescription returns the given field comment without the markers in a
+// single line suitable for a Markdown table cell.
+func docsDescription(comment string) string {
+	var lines []string
+	for _, l := range strings.Split(comment, "\n") {
+		l = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(l), "//"))
+		if l == "" || strings.HasPrefix(l, "+") {
+			continue
+		}
+		lines = append(lines, l)
+	}
+	return strings.ReplaceAll(strings.Join(lines, " "), "|", `\|`)
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/registry"
+	tjtypes "github.com/upbound/upjet/pkg/types"
+)
+
+func TestDocsTypes(t *testing.T) {
+	cfg := &config.Resource{
+		Kind: "Thing",
+		TerraformResource: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"name": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "The name of the thing.",
+				},
+				"rule": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"port": {
+								Type:        schema.TypeInt,
+								Optional:    true,
+								Description: "The port, e.g. 80 | 443.",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	gen, err := tjtypes.NewBuilder(types.NewPackage("example.com/apis/example/v1beta1", "v1beta1")).Build(cfg)
+	if err != nil {
+		t.Fatalf("Build(...): %v", err)
+	}
+	cases := map[string]struct {
+		reason string
+		root   *types.Named
+		want   []docsType
+	}{
+		"Parameters": {
+			reason: "The types reachable from the parameters should be documented without the markers.",
+			root:   gen.ForProviderType,
+			want: []docsType{
+				{
+					Name: "ThingParameters",
+					Fields: []docsField{
+						{Name: "name", Type: "string", Description: "The name of the thing."},
+						{Name: "rule", Type: "[][RuleParameters](#ruleparameters)"},
+					},
+				},
+				{
+					Name: "RuleParameters",
+					Fields: []docsField{
+						{Name: "port", Type: "int64", Description: `The port, e.g. 80 \| 443.`},
+					},
+				},
+			},
+		},
+		"Observation": {
+			reason: "The types reachable from the observation should be documented.",
+			root:   gen.AtProviderType,
+			want: []docsType{
+				{
+					Name: "ThingObservation",
+					Fields: []docsField{
+						{Name: "id", Type: "string"},
+						{Name: "name", Type: "string", Description: "The name of the thing."},
+						{Name: "rule", Type: "[][RuleObservation](#ruleobservation)"},
+					},
+				},
+				{
+					Name: "RuleObservation",
+					Fields: []docsField{
+						{Name: "port", Type: "int64", Description: `The port, e.g. 80 \| 443.`},
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, docsTypes(tc.root, gen.Comments)); diff != "" {
+				t.Errorf("\n%s\ndocsTypes(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDocsGenerate(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		frontmatter bool
+		overrides   fs.FS
+		prefix      string
+	}{
+		"Frontmatter": {
+			reason:      "The page should start with the frontmatter of the resource.",
+			frontmatter: true,
+			prefix: `---
+description: Manages a bucket.
+example: ../../../../examples-generated/s3/bucket.yaml
+group: s3.aws.upbound.io
+title: Bucket
+version: v1beta1
+---
+
+<!-- Code generated by upjet. DO NOT EDIT. -->
+
+# Bucket
+`,
+		},
+		"NoFrontmatter": {
+			reason: "The page should start with the generation statement if the frontmatter is disabled.",
+			prefix: "<!-- Code generated by upjet. DO NOT EDIT. -->\n\n# Bucket\n",
+		},
+		"TemplateOverrides": {
+			reason: "The page should be rendered from the overriding template.",
+			overrides: fstest.MapFS{
+				"docs.md.tmpl": {Data: []byte("# {{ .Kind }} ({{ .APIVersion }})\n")},
+			},
+			prefix: "# Bucket (s3.aws.upbound.io/v1beta1)\n",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := &config.Resource{
+				Name:    "aws_s3_bucket",
+				Kind:    "Bucket",
+				Version: "v1beta1",
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"bucket": {Type: schema.TypeString, Required: true},
+					},
+				},
+				MetaResource: &registry.Resource{
+					Description: "Manages a\n  bucket.",
+					Examples:    []registry.ResourceExample{{Name: "example"}},
+				},
+			}
+			gen, err := tjtypes.NewBuilder(types.NewPackage("example.com/apis/s3/v1beta1", "v1beta1")).Build(cfg)
+			if err != nil {
+				t.Fatalf("Build(...): %v", err)
+			}
+			dg := NewDocsGenerator(t.TempDir(), tc.overrides)
+			dg.Frontmatter = tc.frontmatter
+			if err := dg.Generate(cfg, "s3.aws.upbound.io", "v1beta1", &gen); err != nil {
+				t.Fatalf("\n%s\nGenerate(...): %v", tc.reason, err)
+			}
+			b, err := os.ReadFile(filepath.Clean(dg.FilePath(cfg, "s3.aws.upbound.io", "v1beta1")))
+			if err != nil {
+				t.Fatalf("cannot read the generated page: %v", err)
+			}
+			if !strings.HasPrefix(string(b), tc.prefix) {
+				t.Errorf("\n%s\nGenerate(...): generated page does not start with:\n%s\ngot:\n%s", tc.reason, tc.prefix, string(b))
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/muvaf/typewriter/pkg/wrapper"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+)
+
+const defaultExternalNameTestsProviderFn = "GetProvider()"
+
+// NewExternalNameTestsGenerator returns a new ExternalNameTestsGenerator.
+func NewExternalNameTestsGenerator(rootDir, modulePath string, overrides fs.FS) *ExternalNameTestsGenerator {
+	return &ExternalNameTestsGenerator{
+		LocalDirectoryPath: filepath.Join(rootDir, "config"),
+		LicenseHeaderPath:  licenseHeaderPath(rootDir),
+		ModulePath:         modulePath,
+		overrides:          overrides,
+	}
+}
+
+// ExternalNameTestsGenerator generates the round-trip tests of the external
+// name configurations of the resources.
+type ExternalNameTestsGenerator struct {
+	LocalDirectoryPath string
+	ModulePath         string
+	LicenseHeaderPath  string
+
+	overrides fs.FS
+}
+
+type externalNameTestCase struct {
+	Name       string
+	Resource   string
+	ID         string
+	Parameters map[string]string
+}
+
+// Generate writes the external name tests file with the test cases of the
+// given resources configured with the given options.
+func (eg *ExternalNameTestsGenerator) Generate(t config.ExternalNameTests, resources map[string]*config.Resource) error {
+	tmpl, err := templates.Resolve(eg.overrides, "external_name_test.go.tmpl")
+	if err != nil {
+		return err
+	}
+	testsFile := wrapper.NewFile(filepath.Join(eg.ModulePath, "config"), "config", tmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(eg.LicenseHeaderPath),
+	)
+	providerFn := t.ProviderFn
+	if providerFn == "" {
+		providerFn = defaultExternalNameTestsProviderFn
+	}
+	vars := map[string]any{
+		"Cases":                   externalNameTestCases(resources),
+		"ProviderFn":              providerFn,
+		"TerraformProviderConfig": strings.ReplaceAll(fmt.Sprintf("%#v", t.TerraformProviderConfig), "interface {}", "any"),
+	}
+	filePath := filepath.Join(eg.LocalDirectoryPath, "zz_external_name_test.go")
+	return errors.Wrap(testsFile.Write(filePath, vars, os.ModePerm), "cannot write external name tests file")
+}
+
+// externalNameTestCases returns the test cases of the given resources with
+// non-trivial external name configurations, one for each of their import
+// statements, sorted by their names. The string arguments of the examples
+// of the resources are used as the parameters.
+func externalNameTestCases(resources map[string]*config.Resource) []externalNameTestCase {
+	var cases []externalNameTestCase
+	for name, r := range resources {
+		if r.MetaResource == nil || !hasCustomExternalName(r.ExternalName) {
+			continue
+		}
+		var params map[string]string
+		for _, ex := range r.MetaResource.Examples {
+			if ex.Name != name {
+				continue
+			}
+			for k, v := range ex.Paved.UnstructuredContent() {
+				if s, ok := v.(string); ok && !strings.Contains(s, "${") {
+					if params == nil {
+						params = map[string]string{}
+					}
+					params[k] = s
+				}
+			}
+			break
+		}
+		var ids []string
+		for _, s := range r.MetaResource.ImportStatements {
+			if id, ok := importID(name, s); ok {
+				ids = append(ids, id)
+			}
+		}
+		for i, id := range ids {
+			c := externalNameTestCase{
+				Name:       name,
+				Resource:   name,
+				ID:         id,
+				Parameters: params,
+			}
+			if len(ids) > 1 {
+				c.Name = fmt.Sprintf("%s/%d", name, i)
+			}
+			cases = append(cases, c)
+		}
+	}
+	sort.Slice(cases, func(i, j int) bool {
+		return cases[i].Name < cases[j].Name
+	})
+	return cases
+}
+
+// hasCustomExternalName returns whether the given external name
+// configuration has a GetIDFn or a GetExternalNameFn other than the
+// defaults, whose round-trips are trivial.
+func hasCustomExternalName(e config.ExternalName) bool {
+	return !sameFunc(e.GetIDFn, config.ExternalNameAsID) || !sameFunc(e.GetExternalNameFn, config.IDAsExternalName)
+}
+
+func sameFunc(a, b any) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.IsNil() || vb.IsNil() {
+		return va.IsNil() == vb.IsNil()
+	}
+	return va.Pointer() == vb.Pointer()
+}
+
+// importID returns the ID in the given Terraform import statement of the
+// resource with the given name, e.g. "vpc-a01106c2" in
+// "terraform import aws_vpc.test_vpc vpc-a01106c2".
+func importID(name, statement string) (string, bool) {
+	fields := strings.Fields(strings.ReplaceAll(statement, "\\\n", " "))
+	if len(fields) < 4 || fields[0] != "terraform" || fields[1] != "import" {
+		return "", false
+	}
+	if !strings.HasPrefix(strings.Trim(fields[2], `'"`), name+".") {
+		return "", false
+	}
+	id := strings.Trim(strings.Join(fields[3:], " "), `'"`)
+	return id, id != ""
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/registry"
+)
+
+func TestExternalNameTestCases(t *testing.T) {
+	templated := config.TemplatedStringAsIdentifier("name", "{{ .parameters.region }}/{{ .external_name }}")
+	cases := map[string]struct {
+		reason    string
+		resources map[string]*config.Resource
+		want      []externalNameTestCase
+	}{
+		"DefaultExternalName": {
+			reason: "The resources with the default external name configurations should not be tested.",
+			resources: map[string]*config.Resource{
+				"aws_vpc": {
+					ExternalName: config.IdentifierFromProvider,
+					MetaResource: &registry.Resource{
+						ImportStatements: []string{"terraform import aws_vpc.test_vpc vpc-a01106c2"},
+					},
+				},
+			},
+		},
+		"CustomExternalName": {
+			reason: "The import statements of the resources with custom external name configurations should be tested with the string arguments of their examples.",
+			resources: map[string]*config.Resource{
+				"aws_topic": {
+					ExternalName: templated,
+					MetaResource: &registry.Resource{
+						Examples: []registry.ResourceExample{
+							{
+								Name: "aws_topic",
+								Paved: *fieldpath.Pave(map[string]any{
+									"region": "us-west-1",
+									"vpc_id": "${aws_vpc.example.id}",
+									"count":  2.0,
+								}),
+							},
+						},
+						ImportStatements: []string{
+							"terraform import aws_topic.example us-west-1/topic1",
+							`terraform import 'aws_topic.other' "us-west-1/topic2"`,
+							"terraform import aws_other.example other",
+						},
+					},
+				},
+			},
+			want: []externalNameTestCase{
+				{
+					Name:       "aws_topic/0",
+					Resource:   "aws_topic",
+					ID:         "us-west-1/topic1",
+					Parameters: map[string]string{"region": "us-west-1"},
+				},
+				{
+					Name:       "aws_topic/1",
+					Resource:   "aws_topic",
+					ID:         "us-west-1/topic2",
+					Parameters: map[string]string{"region": "us-west-1"},
+				},
+			},
+		},
+		"NoImportStatements": {
+			reason: "The resources without import statements should not be tested.",
+			resources: map[string]*config.Resource{
+				"aws_topic": {
+					ExternalName: templated,
+					MetaResource: &registry.Resource{},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := externalNameTestCases(tc.resources)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nexternalNameTestCases(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
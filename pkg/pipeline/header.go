@@ -0,0 +1,96 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+const (
+	licenseHeaderDir           = "hack"
+	licenseHeaderFile          = "boilerplate.go.txt"
+	generatedLicenseHeaderFile = "zz_boilerplate.go.txt"
+)
+
+// licenseHeaderPath returns the path of the license header of the generated
+// files of the provider in the given root directory, which is the header
+// rendered by WriteLicenseHeader if any, or the "hack/boilerplate.go.txt"
+// file of the provider.
+func licenseHeaderPath(rootDir string) string {
+	p := filepath.Join(rootDir, licenseHeaderDir, generatedLicenseHeaderFile)
+	if _, err := os.Stat(p); err == nil {
+		return p
+	}
+	return filepath.Join(rootDir, licenseHeaderDir, licenseHeaderFile)
+}
+
+// WriteLicenseHeader renders the license header of the given provider into
+// the "hack/zz_boilerplate.go.txt" file in the given root directory at the
+// given time, which is then used by all the generators. The rendered header
+// is removed if the provider has no license header configured, so that the
+// "hack/boilerplate.go.txt" file is used instead.
+func WriteLicenseHeader(pc *config.Provider, rootDir string, now time.Time) error {
+	p := filepath.Join(rootDir, licenseHeaderDir, generatedLicenseHeaderFile)
+	if pc.LicenseHeader == nil {
+		return errors.Wrap(removeIfExists(p), "cannot remove the rendered license header")
+	}
+	header, err := renderLicenseHeader(*pc.LicenseHeader, map[string]any{
+		"Year":       now.Year(),
+		"ShortName":  pc.ShortName,
+		"ModulePath": pc.ModulePath,
+		"Timestamp":  timestamp(pc.LicenseHeader.Timestamp, now),
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+		return errors.Wrapf(err, "cannot mkdir %s", filepath.Dir(p))
+	}
+	return errors.Wrap(os.WriteFile(p, header, 0600), "cannot write the rendered license header")
+}
+
+// renderLicenseHeader executes the template of the given license header with
+// the given variables and returns an error if the result isn't a Go comment.
+func renderLicenseHeader(h config.LicenseHeader, vars map[string]any) ([]byte, error) {
+	t, err := template.New("header").Parse(h.Template)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse the license header template")
+	}
+	buff := &bytes.Buffer{}
+	if err := t.Execute(buff, vars); err != nil {
+		return nil, errors.Wrap(err, "cannot execute the license header template")
+	}
+	header := bytes.TrimRight(buff.Bytes(), "\n")
+	// the header is followed by the package clause in the generated files.
+	src := append(append([]byte{}, header...), "\n\npackage header\n"...)
+	if _, err := parser.ParseFile(token.NewFileSet(), "", src, parser.PackageClauseOnly); err != nil {
+		return nil, errors.Wrap(err, "the license header is not a Go comment")
+	}
+	return append(header, '\n'), nil
+}
+
+func timestamp(enabled bool, now time.Time) string {
+	if !enabled {
+		return ""
+	}
+	return now.UTC().Format(time.RFC3339)
+}
+
+func removeIfExists(p string) error {
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+func TestWriteLicenseHeader(t *testing.T) {
+	now := time.Date(2023, 9, 1, 12, 30, 0, 0, time.UTC)
+	type want struct {
+		header string
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		header *config.LicenseHeader
+		want   want
+	}{
+		"NotConfigured": {
+			reason: "The boilerplate of the provider should be used if no license header is configured.",
+			want: want{
+				header: "/*\nCopyright 2021 Upbound Inc.\n*/",
+			},
+		},
+		"SPDX": {
+			reason: "The configured template should be rendered with the year and the provider.",
+			header: &config.LicenseHeader{
+				Template: "// SPDX-FileCopyrightText: {{ .Year }} Example Corp.\n// SPDX-License-Identifier: Apache-2.0\n// {{ .ShortName }} ({{ .ModulePath }})\n",
+			},
+			want: want{
+				header: "// SPDX-FileCopyrightText: 2023 Example Corp.\n// SPDX-License-Identifier: Apache-2.0\n// example (example.org/provider)\n",
+			},
+		},
+		"Timestamp": {
+			reason: "The timestamp of the generation should be rendered if it's enabled.",
+			header: &config.LicenseHeader{
+				Template:  "/*\nCopyright {{ .Year }} Example Corp.\n{{ if .Timestamp }}Generated at {{ .Timestamp }}.\n{{ end }}*/",
+				Timestamp: true,
+			},
+			want: want{
+				header: "/*\nCopyright 2023 Example Corp.\nGenerated at 2023-09-01T12:30:00Z.\n*/\n",
+			},
+		},
+		"NoTimestamp": {
+			reason: "The timestamp of the generation should not be rendered if it's not enabled.",
+			header: &config.LicenseHeader{
+				Template: "/*\nCopyright {{ .Year }} Example Corp.\n{{ if .Timestamp }}Generated at {{ .Timestamp }}.\n{{ end }}*/",
+			},
+			want: want{
+				header: "/*\nCopyright 2023 Example Corp.\n*/\n",
+			},
+		},
+		"NotComment": {
+			reason: "An error should be returned if the rendered header is not a Go comment.",
+			header: &config.LicenseHeader{
+				Template: "Copyright {{ .Year }} Example Corp.",
+			},
+			want: want{
+				err: errors.Wrap(errors.New("1:1: expected 'package', found Copyright"), "the license header is not a Go comment"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(rootDir, "hack"), 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(rootDir, "hack", "boilerplate.go.txt"), []byte("/*\nCopyright 2021 Upbound Inc.\n*/"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			// a previously rendered header should be replaced or removed.
+			if err := os.WriteFile(filepath.Join(rootDir, "hack", "zz_boilerplate.go.txt"), []byte("// stale\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			pc := &config.Provider{ShortName: "example", ModulePath: "example.org/provider", LicenseHeader: tc.header}
+			err := WriteLicenseHeader(pc, rootDir, now)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nWriteLicenseHeader(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			b, err := os.ReadFile(licenseHeaderPath(rootDir))
+			if err != nil {
+				t.Fatalf("cannot read the license header: %v", err)
+			}
+			if diff := cmp.Diff(tc.want.header, string(b)); diff != "" {
+				t.Errorf("\n%s\nWriteLicenseHeader(...): -want header, +got header:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"github.com/upbound/upjet/pkg/config"
+)
+
+// ResourceArtifacts are the files generated for a resource in an API
+// version. The paths of the files are in the staging directory of the
+// generation, whose files are committed to the root directory after the
+// hooks are called, so the files written by the hooks in the output
+// directories of the generation are committed with the generated files.
+type ResourceArtifacts struct {
+	// Resource is the configuration of the resource.
+	Resource *config.Resource
+	// Group and Version are the API group and version the resource is
+	// generated in, e.g. "ec2.aws.upbound.io" and "v1beta1".
+	Group   string
+	Version string
+	// Skipped is true if the generation of the resource is skipped by the
+	// incremental generation as its inputs haven't changed, in which case
+	// its files are kept as they are.
+	Skipped bool
+	// CRDTypes is the path of the file of the CRD types.
+	CRDTypes string
+	// Controller is the path of the file of the controller, which is only
+	// generated in the storage version of the resource.
+	Controller string
+	// Docs is the path of the API reference if it's generated.
+	Docs string
+	// Example is the path of the example manifest if the resource has
+	// examples. The example manifests are written after all the resources
+	// are generated, so they're only available in AfterAll.
+	Example string
+}
+
+// Hook is called by Run around the generation of the resources, so that
+// the providers can generate their own files, e.g. policies, docs or tests,
+// from the configurations of the resources and the generated files. The
+// hooks are called concurrently for the resources in different API groups
+// if the groups are generated concurrently, and an error returned by a hook
+// fails the generation. The configurations of the resources must not be
+// modified by the hooks.
+type Hook interface {
+	// BeforeResource is called before the given resource is generated in
+	// the given API group and version.
+	BeforeResource(r *config.Resource, group, version string) error
+	// AfterResource is called after all the resources in the API version of
+	// the given resource are generated.
+	AfterResource(a ResourceArtifacts) error
+	// AfterAll is called with the artifacts of all the resources, sorted by
+	// their API groups, versions and Terraform names, after the generation
+	// is completed.
+	AfterAll(artifacts []ResourceArtifacts) error
+}
+
+// WithHooks registers the given hooks, which are called in order.
+func WithHooks(hooks ...Hook) RunOption {
+	return func(o *runOptions) {
+		o.hooks = append(o.hooks, hooks...)
+	}
+}
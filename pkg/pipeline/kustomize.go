@@ -0,0 +1,282 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	kustomizeBaseDir    = "base"
+	kustomizeOverlayDir = "overlays/dev"
+	kustomizationFile   = "kustomization.yaml"
+	kustomizeAPIVersion = "kustomize.config.k8s.io/v1beta1"
+
+	// defaultKustomizeNamespace is the default namespace of the controller
+	// of the provider.
+	defaultKustomizeNamespace = "upbound-system"
+)
+
+var (
+	// verbsEvents are the verbs of the provider on the events it records.
+	verbsEvents = []string{"create", "update", "patch"}
+	// verbsLeases are the verbs of the provider on the leases and the
+	// config maps of the leader election.
+	verbsLeases = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+)
+
+// NewKustomizeGenerator returns a new KustomizeGenerator writing the
+// kustomize base and overlay of the provider with the given short name and
+// root group, e.g. "aws" and "aws.upbound.io", whose controller runs the
+// given image, to the given directory.
+func NewKustomizeGenerator(kustomizeDir, shortName, rootGroup, image string) *KustomizeGenerator {
+	return &KustomizeGenerator{
+		LocalDirectoryPath: kustomizeDir,
+		ShortName:          shortName,
+		RootGroup:          rootGroup,
+		Image:              image,
+		Namespace:          defaultKustomizeNamespace,
+	}
+}
+
+// KustomizeGenerator generates a kustomize base installing the provider
+// without the Crossplane package manager, e.g. to the development clusters
+// and in the CI, which has the CRDs, the ClusterRoles of the provider, its
+// namespace, ServiceAccount and Deployment. It also scaffolds a development
+// overlay of the base, which isn't overwritten once it exists.
+type KustomizeGenerator struct {
+	LocalDirectoryPath string
+	ShortName          string
+	RootGroup          string
+	Image              string
+	Namespace          string
+}
+
+type kustomization struct {
+	APIVersion string           `json:"apiVersion"`
+	Kind       string           `json:"kind"`
+	Namespace  string           `json:"namespace,omitempty"`
+	Resources  []string         `json:"resources"`
+	Images     []kustomizeImage `json:"images,omitempty"`
+}
+
+type kustomizeImage struct {
+	Name   string `json:"name"`
+	NewTag string `json:"newTag,omitempty"`
+}
+
+// Generate writes the kustomize base of the CRDs in the given directory,
+// e.g. "package/crds", and the development overlay if it doesn't exist.
+// Like the RBACGenerator, it's meant to be run after the CRDs are generated
+// by controller-gen. As kustomize doesn't load the files outside of the
+// directory of a kustomization, the CRDs are copied to the base, one file
+// per CRD regardless of the layout of the given directory.
+func (kg *KustomizeGenerator) Generate(crdDir string) error {
+	crds, err := readCRDs(crdDir)
+	if err != nil {
+		return err
+	}
+	sort.Slice(crds, func(i, j int) bool {
+		if crds[i].Spec.Group != crds[j].Spec.Group {
+			return crds[i].Spec.Group < crds[j].Spec.Group
+		}
+		return crds[i].Spec.Names.Plural < crds[j].Spec.Names.Plural
+	})
+	base := filepath.Join(kg.LocalDirectoryPath, kustomizeBaseDir)
+	// the base is regenerated from scratch so that the CRDs and the
+	// ClusterRoles of the removed resources are removed.
+	if err := os.RemoveAll(base); err != nil {
+		return errors.Wrapf(err, "cannot remove %s", base)
+	}
+	if err := os.MkdirAll(filepath.Join(base, "crds"), 0750); err != nil {
+		return errors.Wrapf(err, "cannot mkdir %s", filepath.Join(base, "crds"))
+	}
+	if err := writeObject(filepath.Join(base, "namespace.yaml"), kg.namespace()); err != nil {
+		return err
+	}
+	resources := []string{"namespace.yaml"}
+	for _, crd := range crds {
+		name := fmt.Sprintf("%s_%s.yaml", crd.Spec.Group, crd.Spec.Names.Plural)
+		f := filepath.Join(base, "crds", name)
+		if err := os.WriteFile(f, append(crd.doc, '\n'), 0600); err != nil {
+			return errors.Wrapf(err, "cannot write the CRD file %s", f)
+		}
+		resources = append(resources, "crds/"+name)
+	}
+	rg := NewRBACGenerator(filepath.Join(base, "rbac"), kg.ShortName, kg.RootGroup)
+	if err := rg.Generate(crdDir); err != nil {
+		return errors.Wrap(err, "cannot generate the ClusterRoles")
+	}
+	if err := writeObject(filepath.Join(base, "rbac", "system.yaml"), kg.systemClusterRole(rg)); err != nil {
+		return err
+	}
+	roles, err := filepath.Glob(filepath.Join(base, "rbac", "*.yaml"))
+	if err != nil {
+		return errors.Wrap(err, "cannot list the ClusterRole files")
+	}
+	sort.Strings(roles)
+	for _, r := range roles {
+		resources = append(resources, "rbac/"+filepath.Base(r))
+	}
+	for _, o := range []struct {
+		file string
+		obj  object
+	}{
+		{file: "serviceaccount.yaml", obj: kg.serviceAccount()},
+		{file: "clusterrolebinding.yaml", obj: kg.clusterRoleBinding(rg)},
+		{file: "deployment.yaml", obj: kg.deployment()},
+	} {
+		if err := writeObject(filepath.Join(base, o.file), o.obj); err != nil {
+			return err
+		}
+		resources = append(resources, o.file)
+	}
+	if err := writeKustomization(filepath.Join(base, kustomizationFile), kustomization{
+		APIVersion: kustomizeAPIVersion,
+		Kind:       "Kustomization",
+		Namespace:  kg.Namespace,
+		Resources:  resources,
+	}); err != nil {
+		return err
+	}
+	return kg.scaffoldOverlay()
+}
+
+// scaffoldOverlay writes the development overlay of the base, which pins
+// the tag of the image of the controller, if it doesn't exist.
+func (kg *KustomizeGenerator) scaffoldOverlay() error {
+	overlay := filepath.Join(kg.LocalDirectoryPath, filepath.FromSlash(kustomizeOverlayDir))
+	f := filepath.Join(overlay, kustomizationFile)
+	if _, err := os.Stat(f); err == nil || !os.IsNotExist(err) {
+		return errors.Wrapf(err, "cannot stat the overlay %s", f)
+	}
+	if err := os.MkdirAll(overlay, 0750); err != nil {
+		return errors.Wrapf(err, "cannot mkdir %s", overlay)
+	}
+	return writeKustomization(f, kustomization{
+		APIVersion: kustomizeAPIVersion,
+		Kind:       "Kustomization",
+		Resources:  []string{"../../" + kustomizeBaseDir},
+		Images:     []kustomizeImage{{Name: kg.Image, NewTag: "latest"}},
+	})
+}
+
+// name is the name of the ServiceAccount and the Deployment of the provider.
+func (kg *KustomizeGenerator) name() string {
+	return fmt.Sprintf("provider-%s", kg.ShortName)
+}
+
+func (kg *KustomizeGenerator) labels() map[string]string {
+	return map[string]string{"app.kubernetes.io/name": kg.name()}
+}
+
+func (kg *KustomizeGenerator) namespace() *corev1.Namespace {
+	return &corev1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: kg.Namespace},
+	}
+}
+
+func (kg *KustomizeGenerator) serviceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "ServiceAccount"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kg.name(),
+			Namespace: kg.Namespace,
+		},
+	}
+}
+
+// systemClusterRole returns the ClusterRole aggregated to the one of the
+// provider that allows recording the events and the leader election, which
+// the Crossplane package manager grants to the providers it installs.
+func (kg *KustomizeGenerator) systemClusterRole(rg *RBACGenerator) *rbacv1.ClusterRole {
+	cr := rg.clusterRole(fmt.Sprintf("%s:system", rg.providerRoleName()), "", nil, nil)
+	cr.Labels = map[string]string{rg.aggregationLabel(): "true"}
+	cr.Rules = []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{""},
+			Resources: []string{"events"},
+			Verbs:     verbsEvents,
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"configmaps"},
+			Verbs:     verbsLeases,
+		},
+		{
+			APIGroups: []string{"coordination.k8s.io"},
+			Resources: []string{"leases"},
+			Verbs:     verbsLeases,
+		},
+	}
+	return cr
+}
+
+func (kg *KustomizeGenerator) clusterRoleBinding(rg *RBACGenerator) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
+		ObjectMeta: metav1.ObjectMeta{Name: rg.providerRoleName()},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     rg.providerRoleName(),
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      kg.name(),
+				Namespace: kg.Namespace,
+			},
+		},
+	}
+}
+
+func (kg *KustomizeGenerator) deployment() *appsv1.Deployment {
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: appsv1.SchemeGroupVersion.String(), Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kg.name(),
+			Namespace: kg.Namespace,
+			Labels:    kg.labels(),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: kg.labels()},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: kg.labels()},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: kg.name(),
+					Containers: []corev1.Container{
+						{
+							Name:            "provider",
+							Image:           kg.Image,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func writeKustomization(path string, k kustomization) error {
+	b, err := yaml.Marshal(k)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal the kustomization")
+	}
+	return errors.Wrapf(os.WriteFile(path, b, 0600), "cannot write the kustomization file %s", path)
+}
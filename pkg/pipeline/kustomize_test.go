@@ -0,0 +1,148 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestKustomizeGenerate(t *testing.T) {
+	crds := `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: ec2.aws.upbound.io
+  names:
+    kind: VPC
+    plural: vpcs
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: aws.upbound.io
+  names:
+    kind: ProviderConfig
+    plural: providerconfigs
+`
+	kustomization := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+namespace: upbound-system
+resources:
+- namespace.yaml
+- crds/aws.upbound.io_providerconfigs.yaml
+- crds/ec2.aws.upbound.io_vpcs.yaml
+- rbac/ec2.aws.upbound.io.yaml
+- rbac/provider.yaml
+- rbac/system.yaml
+- serviceaccount.yaml
+- clusterrolebinding.yaml
+- deployment.yaml
+`
+	deployment := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app.kubernetes.io/name: provider-aws
+  name: provider-aws
+  namespace: upbound-system
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: provider-aws
+  strategy: {}
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: provider-aws
+    spec:
+      containers:
+      - image: xpkg.upbound.io/upbound/provider-aws
+        imagePullPolicy: IfNotPresent
+        name: provider
+        resources: {}
+      serviceAccountName: provider-aws
+`
+	overlay := `apiVersion: kustomize.config.k8s.io/v1beta1
+images:
+- name: xpkg.upbound.io/upbound/provider-aws
+  newTag: latest
+kind: Kustomization
+resources:
+- ../../base
+`
+	cases := map[string]struct {
+		reason  string
+		overlay string
+		want    map[string]string
+	}{
+		"Scaffold": {
+			reason: "The base should have the CRDs, the ClusterRoles and the Deployment of the provider, the stale CRDs should be removed and the overlay should be scaffolded.",
+			want: map[string]string{
+				"base/kustomization.yaml": kustomization,
+				"base/deployment.yaml":    deployment,
+				"base/namespace.yaml": `apiVersion: v1
+kind: Namespace
+metadata:
+  name: upbound-system
+`,
+				"overlays/dev/kustomization.yaml": overlay,
+			},
+		},
+		"ExistingOverlay": {
+			reason:  "The existing overlay should not be overwritten.",
+			overlay: "resources:\n- ../../base\n",
+			want: map[string]string{
+				"base/kustomization.yaml":         kustomization,
+				"overlays/dev/kustomization.yaml": "resources:\n- ../../base\n",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			crdDir, kustomizeDir := t.TempDir(), t.TempDir()
+			if err := os.WriteFile(filepath.Join(crdDir, "crds.yaml"), []byte(crds), 0600); err != nil {
+				t.Fatal(err)
+			}
+			stale := filepath.Join(kustomizeDir, "base", "crds", "s3.aws.upbound.io_buckets.yaml")
+			if err := os.MkdirAll(filepath.Dir(stale), 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(stale, []byte("kind: CustomResourceDefinition\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if tc.overlay != "" {
+				f := filepath.Join(kustomizeDir, "overlays", "dev", "kustomization.yaml")
+				if err := os.MkdirAll(filepath.Dir(f), 0750); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(f, []byte(tc.overlay), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := NewKustomizeGenerator(kustomizeDir, "aws", "aws.upbound.io", "xpkg.upbound.io/upbound/provider-aws").Generate(crdDir); err != nil {
+				t.Fatalf("\n%s\nGenerate(...): %v", tc.reason, err)
+			}
+			if _, err := os.Stat(stale); !os.IsNotExist(err) {
+				t.Errorf("\n%s\nGenerate(...): the stale CRD file is not removed", tc.reason)
+			}
+			got := map[string]string{}
+			for f := range tc.want {
+				b, err := os.ReadFile(filepath.Join(kustomizeDir, filepath.FromSlash(f)))
+				if err != nil {
+					t.Fatalf("\n%s\nGenerate(...): cannot read %s: %v", tc.reason, f, err)
+				}
+				got[f] = string(b)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGenerate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+// ManifestResource is the entry of a managed resource in the provider
+// manifest.
+type ManifestResource struct {
+	TerraformResource    string   `json:"terraformResource"`
+	Group                string   `json:"group"`
+	Version              string   `json:"version"`
+	Kind                 string   `json:"kind"`
+	ClaimKind            string   `json:"claimKind,omitempty"`
+	ClaimPlural          string   `json:"claimPlural,omitempty"`
+	ConnectionSecretKeys []string `json:"connectionSecretKeys,omitempty"`
+	CompositionFields    []string `json:"compositionFields,omitempty"`
+}
+
+// NewManifestResource returns the manifest entry of the given resource
+// generated in the given group and version. fieldPaths maps the Terraform
+// paths of the composition hint fields to their paths in the managed
+// resource.
+func NewManifestResource(r *config.Resource, group, version string, fieldPaths map[string]string) ManifestResource {
+	mr := ManifestResource{
+		TerraformResource:    r.Name,
+		Group:                group,
+		Version:              version,
+		Kind:                 r.Kind,
+		ClaimKind:            r.CompositionHints.ClaimKind,
+		ClaimPlural:          r.CompositionHints.ClaimPlural,
+		ConnectionSecretKeys: r.CompositionHints.ConnectionSecretKeys,
+	}
+	for _, p := range r.CompositionHints.Fields {
+		if fp, ok := fieldPaths[p]; ok {
+			mr.CompositionFields = append(mr.CompositionFields, fp)
+		}
+	}
+	return mr
+}
+
+// NewManifestGenerator returns a new ManifestGenerator.
+func NewManifestGenerator(rootDir string) *ManifestGenerator {
+	return &ManifestGenerator{
+		LocalDirectoryPath: filepath.Join(rootDir, "config"),
+	}
+}
+
+// ManifestGenerator generates the machine-readable provider manifest
+// consumed by the composition and XRD tooling.
+type ManifestGenerator struct {
+	LocalDirectoryPath string
+}
+
+// Generate writes the provider manifest with the given resources sorted by
+// their groups, Kinds and versions.
+func (mg *ManifestGenerator) Generate(resources []ManifestResource) error {
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Group != resources[j].Group {
+			return resources[i].Group < resources[j].Group
+		}
+		if resources[i].Kind != resources[j].Kind {
+			return resources[i].Kind < resources[j].Kind
+		}
+		return resources[i].Version < resources[j].Version
+	})
+	b, err := json.MarshalIndent(map[string]any{"resources": resources}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal provider manifest")
+	}
+	filePath := filepath.Join(mg.LocalDirectoryPath, "zz_provider_manifest.json")
+	return errors.Wrap(os.WriteFile(filePath, append(b, '\n'), 0600), "cannot write provider manifest file")
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestManifestGenerate(t *testing.T) {
+	resources := []ManifestResource{
+		{TerraformResource: "aws_vpc", Group: "ec2.aws.upbound.io", Version: "v1beta2", Kind: "VPC"},
+		{TerraformResource: "aws_s3_bucket", Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"},
+		{TerraformResource: "aws_vpc", Group: "ec2.aws.upbound.io", Version: "v1beta1", Kind: "VPC"},
+		{TerraformResource: "aws_subnet", Group: "ec2.aws.upbound.io", Version: "v1beta1", Kind: "Subnet"},
+	}
+	want := `{
+  "resources": [
+    {
+      "terraformResource": "aws_subnet",
+      "group": "ec2.aws.upbound.io",
+      "version": "v1beta1",
+      "kind": "Subnet"
+    },
+    {
+      "terraformResource": "aws_vpc",
+      "group": "ec2.aws.upbound.io",
+      "version": "v1beta1",
+      "kind": "VPC"
+    },
+    {
+      "terraformResource": "aws_vpc",
+      "group": "ec2.aws.upbound.io",
+      "version": "v1beta2",
+      "kind": "VPC"
+    },
+    {
+      "terraformResource": "aws_s3_bucket",
+      "group": "s3.aws.upbound.io",
+      "version": "v1beta1",
+      "kind": "Bucket"
+    }
+  ]
+}
+`
+	dir := t.TempDir()
+	if err := (&ManifestGenerator{LocalDirectoryPath: dir}).Generate(resources); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "zz_provider_manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, string(b)); diff != "" {
+		t.Errorf("\nThe resources should be sorted by their groups, Kinds and versions regardless of the order they're generated in.\nGenerate(...): -want, +got:\n%s", diff)
+	}
+}
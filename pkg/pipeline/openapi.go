@@ -0,0 +1,116 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const openAPIVersion = "3.0.0"
+
+// NewOpenAPIGenerator returns a new OpenAPIGenerator writing the OpenAPI
+// document of the provider with the given title and version, e.g.
+// "provider-aws" and "v0.40.0", to the given file.
+func NewOpenAPIGenerator(path, title, version string) *OpenAPIGenerator {
+	return &OpenAPIGenerator{
+		Path:    path,
+		Title:   title,
+		Version: version,
+	}
+}
+
+// OpenAPIGenerator generates a single OpenAPI v3 document with the schemas
+// of all the Kinds of the provider from its CRDs, so that the API of the
+// provider can be consumed by the tools that don't read CRDs.
+type OpenAPIGenerator struct {
+	Path    string
+	Title   string
+	Version string
+}
+
+type crdSchemas struct {
+	Spec struct {
+		Group string `json:"group"`
+		Names struct {
+			Kind string `json:"kind"`
+		} `json:"names"`
+		Versions []struct {
+			Name   string `json:"name"`
+			Served bool   `json:"served"`
+			Schema struct {
+				OpenAPIV3Schema map[string]any `json:"openAPIV3Schema"`
+			} `json:"schema"`
+		} `json:"versions"`
+	} `json:"spec"`
+}
+
+// Generate writes the OpenAPI document with the schemas of the served
+// versions of the CRDs in the given directory, e.g. "package/crds". It's
+// meant to be run after the CRDs are generated by controller-gen. The
+// schemas are named after the reversed groups, the versions and the Kinds,
+// e.g. "io.upbound.aws.ec2.v1beta1.VPC", as in the OpenAPI documents of
+// the Kubernetes API servers, and they're annotated with their GVKs.
+func (og *OpenAPIGenerator) Generate(crdDir string) error {
+	crds, err := readCRDs(crdDir)
+	if err != nil {
+		return err
+	}
+	schemas := map[string]any{}
+	for _, c := range crds {
+		crd := &crdSchemas{}
+		if err := yaml.Unmarshal(c.doc, crd); err != nil {
+			return errors.Wrapf(err, "cannot unmarshal the CRD file %s", c.file)
+		}
+		for _, v := range crd.Spec.Versions {
+			if !v.Served || v.Schema.OpenAPIV3Schema == nil {
+				continue
+			}
+			s := make(map[string]any, len(v.Schema.OpenAPIV3Schema)+1)
+			for k, p := range v.Schema.OpenAPIV3Schema {
+				s[k] = p
+			}
+			s["x-kubernetes-group-version-kind"] = []map[string]string{
+				{"group": crd.Spec.Group, "version": v.Name, "kind": crd.Spec.Names.Kind},
+			}
+			schemas[openAPISchemaName(crd.Spec.Group, v.Name, crd.Spec.Names.Kind)] = s
+		}
+	}
+	// the keys of the maps are sorted when they're marshaled, so the
+	// document is stable.
+	b, err := json.MarshalIndent(map[string]any{
+		"openapi": openAPIVersion,
+		"info": map[string]string{
+			"title":   og.Title,
+			"version": og.Version,
+		},
+		"paths": map[string]any{},
+		"components": map[string]any{
+			"schemas": schemas,
+		},
+	}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal the OpenAPI document")
+	}
+	if err := os.MkdirAll(filepath.Dir(og.Path), 0750); err != nil {
+		return errors.Wrapf(err, "cannot mkdir %s", filepath.Dir(og.Path))
+	}
+	return errors.Wrapf(os.WriteFile(og.Path, append(b, '\n'), 0600), "cannot write the OpenAPI document %s", og.Path)
+}
+
+// openAPISchemaName returns the name of the schema of the given Kind, e.g.
+// "io.upbound.aws.ec2.v1beta1.VPC" for "ec2.aws.upbound.io/v1beta1, VPC".
+func openAPISchemaName(group, version, kind string) string {
+	parts := strings.Split(group, ".")
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(append(parts, version, kind), ".")
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOpenAPIGenerate(t *testing.T) {
+	crd := `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: ec2.aws.upbound.io
+  names:
+    kind: VPC
+    plural: vpcs
+  versions:
+  - name: v1beta1
+    served: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+  - name: v1alpha1
+    served: false
+    schema:
+      openAPIV3Schema:
+        type: object
+`
+	want := `{
+  "components": {
+    "schemas": {
+      "io.upbound.aws.ec2.v1beta1.VPC": {
+        "properties": {
+          "spec": {
+            "type": "object"
+          }
+        },
+        "type": "object",
+        "x-kubernetes-group-version-kind": [
+          {
+            "group": "ec2.aws.upbound.io",
+            "kind": "VPC",
+            "version": "v1beta1"
+          }
+        ]
+      }
+    }
+  },
+  "info": {
+    "title": "provider-aws",
+    "version": "v0.40.0"
+  },
+  "openapi": "3.0.0",
+  "paths": {}
+}
+`
+	crdDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(crdDir, "ec2.aws.upbound.io_vpcs.yaml"), []byte(crd), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "openapi", "openapi.json")
+	if err := NewOpenAPIGenerator(path, "provider-aws", "v0.40.0").Generate(crdDir); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("\nThe schemas of the served versions should be named after their GVKs and annotated with them.\nGenerate(...): -want, +got:\n%s", diff)
+	}
+}
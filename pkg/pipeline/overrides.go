@@ -0,0 +1,129 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+)
+
+// generationTemplates are the templates of the generated files, which are
+// either the built-in ones or the ones overriding them.
+type generationTemplates struct {
+	CRDTypes    string
+	Terraformed string
+	Controller  string
+	Setup       string
+	// Overrides overlay the built-in templates of the other generators,
+	// which resolve them when they render their templates.
+	Overrides fs.FS
+	// OverrideContents are the contents of the overriding templates keyed
+	// by their names, which are hashed by the generation cache.
+	OverrideContents map[string]string
+}
+
+// loadTemplates returns the built-in templates shadowed by the templates
+// with the same names in the given file system, if it's not nil. The
+// overriding templates whose names don't match the ones of the built-in
+// templates are rejected as they would be silently ignored.
+func loadTemplates(overrides fs.FS) (generationTemplates, error) {
+	t := generationTemplates{
+		CRDTypes:    templates.CRDTypesTemplate,
+		Terraformed: templates.TerraformedTemplate,
+		Controller:  templates.ControllerTemplate,
+		Setup:       templates.SetupTemplate,
+	}
+	if overrides == nil {
+		return t, nil
+	}
+	builtin := map[string]*string{
+		"crd_types.go.tmpl":   &t.CRDTypes,
+		"terraformed.go.tmpl": &t.Terraformed,
+		"controller.go.tmpl":  &t.Controller,
+		"setup.go.tmpl":       &t.Setup,
+	}
+	contents := map[string]string{}
+	names := templates.Names()
+	files, err := fs.Glob(overrides, "*.tmpl")
+	if err != nil {
+		return t, errors.Wrap(err, "cannot list the overriding templates")
+	}
+	for _, f := range files {
+		if i := sort.SearchStrings(names, f); i == len(names) || names[i] != f {
+			return t, errors.Errorf("cannot override unknown template %s, known templates are: %s", f, strings.Join(names, ", "))
+		}
+		b, err := fs.ReadFile(overrides, f)
+		if err != nil {
+			return t, errors.Wrapf(err, "cannot read the overriding template %s", f)
+		}
+		contents[f] = string(b)
+		if tmpl, ok := builtin[f]; ok {
+			*tmpl = string(b)
+		}
+	}
+	t.Overrides = overrides
+	t.OverrideContents = contents
+	return t, nil
+}
+
+// overlayFS is the union of its layers, whose files shadow the ones with the
+// same names in the layers after them. The nil layers are skipped.
+type overlayFS []fs.FS
+
+// newOverlayFS returns the union of the given layers, or nil if none of them
+// is set.
+func newOverlayFS(layers ...fs.FS) fs.FS {
+	var o overlayFS
+	for _, l := range layers {
+		if l != nil {
+			o = append(o, l)
+		}
+	}
+	switch len(o) {
+	case 0:
+		return nil
+	case 1:
+		return o[0]
+	}
+	return o
+}
+
+// Open opens the file with the given name in the first layer having it.
+func (o overlayFS) Open(name string) (fs.File, error) {
+	for _, l := range o {
+		f, err := l.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return f, err
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// Glob returns the sorted names of the files matching the given pattern in
+// any of the layers.
+func (o overlayFS) Glob(pattern string) ([]string, error) {
+	seen := map[string]struct{}{}
+	var result []string
+	for _, l := range o {
+		matches, err := fs.Glob(l, pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if _, ok := seen[m]; !ok {
+				seen[m] = struct{}{}
+				result = append(result, m)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+)
+
+func TestLoadTemplates(t *testing.T) {
+	builtin := generationTemplates{
+		CRDTypes:    templates.CRDTypesTemplate,
+		Terraformed: templates.TerraformedTemplate,
+		Controller:  templates.ControllerTemplate,
+		Setup:       templates.SetupTemplate,
+	}
+	overrides := fstest.MapFS{
+		"controller.go.tmpl": {Data: []byte("controller")},
+		"setup.go.tmpl":      {Data: []byte("setup")},
+		"client.go.tmpl":     {Data: []byte("client")},
+	}
+	type want struct {
+		templates generationTemplates
+		err       error
+	}
+	cases := map[string]struct {
+		reason    string
+		overrides fs.FS
+		want      want
+	}{
+		"NoOverrides": {
+			reason: "The built-in templates should be used if there are no overrides.",
+			want: want{
+				templates: builtin,
+			},
+		},
+		"Override": {
+			reason:    "The overriding templates should shadow the built-in ones with the same names, including the ones resolved by the other generators.",
+			overrides: overrides,
+			want: want{
+				templates: generationTemplates{
+					CRDTypes:    templates.CRDTypesTemplate,
+					Terraformed: templates.TerraformedTemplate,
+					Controller:  "controller",
+					Setup:       "setup",
+					Overrides:   overrides,
+					OverrideContents: map[string]string{
+						"controller.go.tmpl": "controller",
+						"setup.go.tmpl":      "setup",
+						"client.go.tmpl":     "client",
+					},
+				},
+			},
+		},
+		"UnknownTemplate": {
+			reason: "An overriding template without a built-in template with the same name should be rejected.",
+			overrides: fstest.MapFS{
+				"controllers.go.tmpl": {Data: []byte("controller")},
+			},
+			want: want{
+				templates: builtin,
+				err:       errors.New("cannot override unknown template controllers.go.tmpl, known templates are: " + strings.Join(templates.Names(), ", ")),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := loadTemplates(tc.overrides)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nloadTemplates(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.templates, got); diff != "" {
+				t.Errorf("\n%s\nloadTemplates(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOverlayFS(t *testing.T) {
+	type want struct {
+		contents map[string]string
+		names    []string
+	}
+	cases := map[string]struct {
+		reason string
+		layers []fs.FS
+		want   want
+	}{
+		"Overlay": {
+			reason: "The files of the former layers should shadow the ones of the latter layers, and the files of all the layers should be listed.",
+			layers: []fs.FS{
+				fstest.MapFS{"controller.go.tmpl": {Data: []byte("run")}},
+				nil,
+				fstest.MapFS{
+					"controller.go.tmpl": {Data: []byte("provider")},
+					"setup.go.tmpl":      {Data: []byte("setup")},
+				},
+			},
+			want: want{
+				contents: map[string]string{
+					"controller.go.tmpl": "run",
+					"setup.go.tmpl":      "setup",
+				},
+				names: []string{"controller.go.tmpl", "setup.go.tmpl"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := newOverlayFS(tc.layers...)
+			names, err := fs.Glob(o, "*.tmpl")
+			if err != nil {
+				t.Fatalf("\n%s\nGlob(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.names, names); diff != "" {
+				t.Errorf("\n%s\nGlob(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			contents := map[string]string{}
+			for _, n := range names {
+				b, err := fs.ReadFile(o, n)
+				if err != nil {
+					t.Fatalf("\n%s\nReadFile(...): %v", tc.reason, err)
+				}
+				contents[n] = string(b)
+			}
+			if diff := cmp.Diff(tc.want.contents, contents); diff != "" {
+				t.Errorf("\n%s\nReadFile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/muvaf/typewriter/pkg/wrapper"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+)
+
+const (
+	providerConfigAPIRoot        = "apis/v1beta1"
+	providerConfigControllerRoot = "internal/controller/providerconfig"
+	credentialsRoot              = "internal/clients"
+)
+
+// NewProviderConfigGenerator returns a new ProviderConfigGenerator.
+func NewProviderConfigGenerator(rootDir, modulePath, shortName string, overrides fs.FS) *ProviderConfigGenerator {
+	return &ProviderConfigGenerator{
+		APIDirectoryPath:         filepath.Join(rootDir, providerConfigAPIRoot),
+		APIPackagePath:           filepath.Join(modulePath, providerConfigAPIRoot),
+		ControllerDirectoryPath:  filepath.Join(rootDir, providerConfigControllerRoot),
+		ControllerPackagePath:    filepath.Join(modulePath, providerConfigControllerRoot),
+		CredentialsDirectoryPath: filepath.Join(rootDir, credentialsRoot),
+		CredentialsPackagePath:   filepath.Join(modulePath, credentialsRoot),
+		LicenseHeaderPath:        licenseHeaderPath(rootDir),
+		ShortName:                shortName,
+		overrides:                overrides,
+	}
+}
+
+// ProviderConfigGenerator generates the ProviderConfig and
+// ProviderConfigUsage types, the ProviderConfig controller and the
+// extraction of the credentials of the ProviderConfigs from a declarative
+// specification of the supported credential sources.
+type ProviderConfigGenerator struct {
+	APIDirectoryPath         string
+	APIPackagePath           string
+	ControllerDirectoryPath  string
+	ControllerPackagePath    string
+	CredentialsDirectoryPath string
+	CredentialsPackagePath   string
+	LicenseHeaderPath        string
+	ShortName                string
+
+	overrides fs.FS
+}
+
+// Generate writes the types, the controller and the credentials files of
+// the ProviderConfig with the given specification of its credentials.
+func (pg *ProviderConfigGenerator) Generate(auth config.ProviderConfigAuth) error {
+	vars, err := providerConfigVars(auth)
+	if err != nil {
+		return errors.Wrap(err, "invalid ProviderConfig credentials specification")
+	}
+	vars["ShortName"] = pg.ShortName
+
+	typesTmpl, err := templates.Resolve(pg.overrides, "providerconfig_types.go.tmpl")
+	if err != nil {
+		return err
+	}
+	typesFile := wrapper.NewFile(pg.APIPackagePath, filepath.Base(pg.APIPackagePath), typesTmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(pg.LicenseHeaderPath),
+	)
+	vars["PackageName"] = filepath.Base(pg.APIPackagePath)
+	if err := pg.write(typesFile, pg.APIDirectoryPath, "zz_providerconfig_types.go", vars); err != nil {
+		return errors.Wrap(err, "cannot write the ProviderConfig types file")
+	}
+
+	controllerTmpl, err := templates.Resolve(pg.overrides, "providerconfig_controller.go.tmpl")
+	if err != nil {
+		return err
+	}
+	controllerFile := wrapper.NewFile(pg.ControllerPackagePath, filepath.Base(pg.ControllerPackagePath), controllerTmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(pg.LicenseHeaderPath),
+	)
+	vars["PackageName"] = filepath.Base(pg.ControllerPackagePath)
+	vars["APIPackageAlias"] = controllerFile.Imports.UsePackage(pg.APIPackagePath)
+	if err := pg.write(controllerFile, pg.ControllerDirectoryPath, "zz_controller.go", vars); err != nil {
+		return errors.Wrap(err, "cannot write the ProviderConfig controller file")
+	}
+
+	credentialsTmpl, err := templates.Resolve(pg.overrides, "credentials.go.tmpl")
+	if err != nil {
+		return err
+	}
+	credentialsFile := wrapper.NewFile(pg.CredentialsPackagePath, filepath.Base(pg.CredentialsPackagePath), credentialsTmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(pg.LicenseHeaderPath),
+	)
+	vars["PackageName"] = filepath.Base(pg.CredentialsPackagePath)
+	vars["APIPackageAlias"] = credentialsFile.Imports.UsePackage(pg.APIPackagePath)
+	return errors.Wrap(pg.write(credentialsFile, pg.CredentialsDirectoryPath, "zz_credentials.go", vars), "cannot write the credentials file")
+}
+
+func (pg *ProviderConfigGenerator) write(f *wrapper.File, dir, name string, vars map[string]any) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "cannot mkdir %s", dir)
+	}
+	return f.Write(filepath.Join(dir, name), vars, os.ModePerm)
+}
+
+// providerConfigVars returns the template variables of the given
+// specification of the credentials of the ProviderConfig after validating
+// it and defaulting its unset fields.
+func providerConfigVars(auth config.ProviderConfigAuth) (map[string]any, error) {
+	sources := auth.Sources
+	if len(sources) == 0 {
+		sources = []xpv1.CredentialsSource{xpv1.CredentialsSourceSecret}
+	}
+	vars := map[string]any{
+		"CredentialKeys": auth.CredentialKeys,
+	}
+	names := make([]string, len(sources))
+	for i, s := range sources {
+		switch s {
+		case xpv1.CredentialsSourceSecret, xpv1.CredentialsSourceEnvironment, xpv1.CredentialsSourceFilesystem:
+		case xpv1.CredentialsSourceInjectedIdentity:
+			vars["InjectedIdentity"] = true
+			vars["InjectedIdentityEnv"] = auth.InjectedIdentityEnv
+		case config.CredentialsSourceStaticKeys:
+			if len(auth.StaticKeys) == 0 {
+				return nil, errors.Errorf("the keys of the %s credentials source are required", s)
+			}
+			vars["StaticKeys"] = auth.StaticKeys
+			vars["StaticKeysSource"] = string(s)
+		default:
+			return nil, errors.Errorf("unsupported credentials source %q", s)
+		}
+		names[i] = string(s)
+	}
+	vars["Sources"] = strings.Join(names, ";")
+	if auth.AssumeRoleChain != nil {
+		arc := *auth.AssumeRoleChain
+		if arc.Argument == "" {
+			arc.Argument = "assume_role"
+		}
+		if arc.RoleArgument == "" {
+			arc.RoleArgument = "role_arn"
+		}
+		if arc.ExternalIDArgument == "" {
+			arc.ExternalIDArgument = "external_id"
+		}
+		if arc.SessionNameArgument == "" {
+			arc.SessionNameArgument = "session_name"
+		}
+		vars["AssumeRoleChain"] = arc
+	}
+	return vars, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+func TestProviderConfigGenerate(t *testing.T) {
+	type want struct {
+		types       []string
+		credentials []string
+		notContains []string
+		err         error
+	}
+	cases := map[string]struct {
+		reason string
+		auth   config.ProviderConfigAuth
+		want   want
+	}{
+		"Default": {
+			reason: "The Secret credentials source should be supported by default and all the keys of the credentials should be set.",
+			want: want{
+				types:       []string{"package v1beta1", "+kubebuilder:validation:Enum=Secret\n", "categories={crossplane,provider,example}"},
+				credentials: []string{"package clients", "func ConfigureCredentials(", "for k, v := range creds {"},
+				notContains: []string{"AssumeRoleChain", "xpv1.CredentialsSourceInjectedIdentity", `"StaticKeys"`},
+			},
+		},
+		"CredentialKeys": {
+			reason: "Only the configured keys of the credentials should be set.",
+			auth: config.ProviderConfigAuth{
+				Sources:        []xpv1.CredentialsSource{xpv1.CredentialsSourceSecret, xpv1.CredentialsSourceEnvironment},
+				CredentialKeys: []string{"token", "owner"},
+			},
+			want: want{
+				types:       []string{"+kubebuilder:validation:Enum=Secret;Environment\n"},
+				credentials: []string{`for _, k := range []string{"token", "owner"} {`},
+			},
+		},
+		"InjectedIdentity": {
+			reason: "The configured arguments should be set from the environment with the InjectedIdentity credentials source.",
+			auth: config.ProviderConfigAuth{
+				Sources:             []xpv1.CredentialsSource{xpv1.CredentialsSourceInjectedIdentity},
+				InjectedIdentityEnv: map[string]string{"role_arn": "AWS_ROLE_ARN"},
+			},
+			want: want{
+				types:       []string{"+kubebuilder:validation:Enum=InjectedIdentity\n"},
+				credentials: []string{"case xpv1.CredentialsSourceInjectedIdentity:", `os.Getenv("AWS_ROLE_ARN")`, `ps.Configuration["role_arn"] = v`},
+			},
+		},
+		"StaticKeys": {
+			reason: "The configured keys should be read from the Secret with the StaticKeys credentials source.",
+			auth: config.ProviderConfigAuth{
+				Sources:    []xpv1.CredentialsSource{config.CredentialsSourceStaticKeys},
+				StaticKeys: []string{"access_key", "secret_key"},
+			},
+			want: want{
+				types:       []string{"+kubebuilder:validation:Enum=StaticKeys\n"},
+				credentials: []string{`case "StaticKeys":`, `for _, k := range []string{"access_key", "secret_key"} {`},
+			},
+		},
+		"AssumeRoleChain": {
+			reason: "The chain of the roles of the ProviderConfig should be set in the configured block with the default arguments.",
+			auth: config.ProviderConfigAuth{
+				AssumeRoleChain: &config.AssumeRoleChain{Argument: "assume_roles"},
+			},
+			want: want{
+				types:       []string{"AssumeRoleChain []AssumeRoleOptions `json:\"assumeRoleChain,omitempty\"`"},
+				credentials: []string{`"role_arn": r.RoleARN`, `role["external_id"] = *r.ExternalID`, `ps.Configuration["assume_roles"] = roles`},
+			},
+		},
+		"NoStaticKeys": {
+			reason: "An error should be returned if the StaticKeys credentials source is supported without any keys.",
+			auth: config.ProviderConfigAuth{
+				Sources: []xpv1.CredentialsSource{config.CredentialsSourceStaticKeys},
+			},
+			want: want{
+				err: errors.Wrap(errors.New("the keys of the StaticKeys credentials source are required"), "invalid ProviderConfig credentials specification"),
+			},
+		},
+		"UnsupportedSource": {
+			reason: "An error should be returned if an unsupported credentials source is configured.",
+			auth: config.ProviderConfigAuth{
+				Sources: []xpv1.CredentialsSource{xpv1.CredentialsSourceNone},
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`unsupported credentials source "None"`), "invalid ProviderConfig credentials specification"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(rootDir, "hack"), 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(rootDir, "hack", "boilerplate.go.txt"), []byte("/*\nCopyright 2023 Upbound Inc.\n*/"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			pg := NewProviderConfigGenerator(rootDir, "example.org/provider", "example", nil)
+			err := pg.Generate(tc.auth)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nGenerate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			files := map[string][]string{
+				filepath.Join(pg.APIDirectoryPath, "zz_providerconfig_types.go"): tc.want.types,
+				filepath.Join(pg.ControllerDirectoryPath, "zz_controller.go"):    {"package providerconfig", `v1beta1 "example.org/provider/apis/v1beta1"`},
+				filepath.Join(pg.CredentialsDirectoryPath, "zz_credentials.go"):  tc.want.credentials,
+			}
+			var all strings.Builder
+			for f, contains := range files {
+				b, err := os.ReadFile(filepath.Clean(f))
+				if err != nil {
+					t.Fatalf("cannot read the generated file: %v", err)
+				}
+				all.Write(b)
+				for _, s := range contains {
+					if !strings.Contains(string(b), s) {
+						t.Errorf("\n%s\nGenerate(...): %s does not contain %q:\n%s", tc.reason, filepath.Base(f), s, string(b))
+					}
+				}
+			}
+			for _, s := range tc.want.notContains {
+				if strings.Contains(all.String(), s) {
+					t.Errorf("\n%s\nGenerate(...): generated files contain %q", tc.reason, s)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// verbsManaged are the verbs of the provider on the generated Kinds.
+	verbsManaged = []string{"get", "list", "watch", "update", "patch"}
+	// verbsProviderConfig are the verbs of the provider on the provider
+	// configs and their usages, which it creates and deletes.
+	verbsProviderConfig = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+	// verbsSecrets are the verbs of the provider on the connection secrets.
+	verbsSecrets = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+)
+
+// NewRBACGenerator returns a new RBACGenerator writing the ClusterRoles of
+// the provider with the given short name and root group, e.g. "aws" and
+// "aws.upbound.io", to the given directory.
+func NewRBACGenerator(rbacDir, shortName, rootGroup string) *RBACGenerator {
+	return &RBACGenerator{
+		LocalDirectoryPath: rbacDir,
+		ShortName:          shortName,
+		RootGroup:          rootGroup,
+	}
+}
+
+// RBACGenerator generates the ClusterRoles of the provider from its CRDs,
+// one for each API group aggregated to the one of the whole provider, which
+// also allows the access to the connection secrets and the provider configs
+// in the root group.
+type RBACGenerator struct {
+	LocalDirectoryPath string
+	ShortName          string
+	RootGroup          string
+}
+
+type crdNames struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Group string `json:"group"`
+		Names struct {
+			Plural string `json:"plural"`
+		} `json:"names"`
+	} `json:"spec"`
+}
+
+// Generate writes the ClusterRoles of the CRDs in the given directory, e.g.
+// "package/crds". It's meant to be run after the CRDs are generated by
+// controller-gen so that the ClusterRoles are kept in lockstep with them.
+func (rg *RBACGenerator) Generate(crdDir string) error {
+	plurals, err := crdPlurals(crdDir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(rg.LocalDirectoryPath, 0750); err != nil {
+		return errors.Wrapf(err, "cannot mkdir %s", rg.LocalDirectoryPath)
+	}
+	for group, resources := range plurals {
+		if group == rg.RootGroup {
+			continue
+		}
+		cr := rg.clusterRole(fmt.Sprintf("%s:%s", rg.providerRoleName(), group), group, resources, verbsManaged)
+		cr.Labels = map[string]string{rg.aggregationLabel(): "true"}
+		if err := writeObject(filepath.Join(rg.LocalDirectoryPath, fmt.Sprintf("%s.yaml", group)), cr); err != nil {
+			return err
+		}
+	}
+	cr := rg.clusterRole(rg.providerRoleName(), rg.RootGroup, plurals[rg.RootGroup], verbsProviderConfig)
+	cr.Rules = append(cr.Rules, rbacv1.PolicyRule{
+		APIGroups: []string{""},
+		Resources: []string{"secrets"},
+		Verbs:     verbsSecrets,
+	})
+	cr.AggregationRule = &rbacv1.AggregationRule{
+		ClusterRoleSelectors: []metav1.LabelSelector{
+			{MatchLabels: map[string]string{rg.aggregationLabel(): "true"}},
+		},
+	}
+	return writeObject(filepath.Join(rg.LocalDirectoryPath, "provider.yaml"), cr)
+}
+
+func (rg *RBACGenerator) providerRoleName() string {
+	return fmt.Sprintf("provider-%s", rg.ShortName)
+}
+
+func (rg *RBACGenerator) aggregationLabel() string {
+	return fmt.Sprintf("rbac.%s/aggregate-to-%s", rg.RootGroup, rg.providerRoleName())
+}
+
+// clusterRole returns the ClusterRole with the given name allowing the given
+// verbs on the given resources of the given group and their statuses.
+func (rg *RBACGenerator) clusterRole(name, group string, resources []string, verbs []string) *rbacv1.ClusterRole {
+	cr := &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "ClusterRole",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	if len(resources) == 0 {
+		return cr
+	}
+	res := make([]string, 0, 2*len(resources))
+	for _, r := range resources {
+		res = append(res, r, r+"/status")
+	}
+	cr.Rules = []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{group},
+			Resources: res,
+			Verbs:     verbs,
+		},
+	}
+	return cr
+}
+
+// crdPlurals returns the sorted plural names of the CRDs in the given
+// directory keyed by their groups.
+func crdPlurals(crdDir string) (map[string][]string, error) {
+	crds, err := readCRDs(crdDir)
+	if err != nil {
+		return nil, err
+	}
+	plurals := map[string][]string{}
+	for _, crd := range crds {
+		plurals[crd.Spec.Group] = append(plurals[crd.Spec.Group], crd.Spec.Names.Plural)
+	}
+	for _, p := range plurals {
+		sort.Strings(p)
+	}
+	return plurals, nil
+}
+
+// object is a Kubernetes object written to a manifest file.
+type object interface {
+	runtime.Object
+	metav1.Object
+}
+
+// writeObject writes the given object to the given manifest file without
+// its creation timestamps and its empty spec and status.
+func writeObject(path string, obj object) error {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return errors.Wrapf(err, "cannot convert the %s %s", kind, obj.GetName())
+	}
+	unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u, "spec", "template", "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u, "status")
+	if spec, ok := u["spec"].(map[string]any); ok && len(spec) == 0 {
+		delete(u, "spec")
+	}
+	b, err := yaml.Marshal(u)
+	if err != nil {
+		return errors.Wrapf(err, "cannot marshal the %s %s", kind, obj.GetName())
+	}
+	return errors.Wrapf(os.WriteFile(path, b, 0600), "cannot write the %s file %s", kind, path)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRBACGenerate(t *testing.T) {
+	crds := map[string]string{
+		"ec2.aws.upbound.io_vpcs.yaml": `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: ec2.aws.upbound.io
+  names:
+    kind: VPC
+    plural: vpcs
+`,
+		"ec2.aws.upbound.io_subnets.yaml": `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: ec2.aws.upbound.io
+  names:
+    kind: Subnet
+    plural: subnets
+`,
+		"aws.upbound.io_providerconfigs.yaml": `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: aws.upbound.io
+  names:
+    kind: ProviderConfig
+    plural: providerconfigs
+`,
+	}
+	want := map[string]string{
+		"ec2.aws.upbound.io.yaml": `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  labels:
+    rbac.aws.upbound.io/aggregate-to-provider-aws: "true"
+  name: provider-aws:ec2.aws.upbound.io
+rules:
+- apiGroups:
+  - ec2.aws.upbound.io
+  resources:
+  - subnets
+  - subnets/status
+  - vpcs
+  - vpcs/status
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - patch
+`,
+		"provider.yaml": `aggregationRule:
+  clusterRoleSelectors:
+  - matchLabels:
+      rbac.aws.upbound.io/aggregate-to-provider-aws: "true"
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: provider-aws
+rules:
+- apiGroups:
+  - aws.upbound.io
+  resources:
+  - providerconfigs
+  - providerconfigs/status
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+`,
+	}
+	crdDir, rbacDir := t.TempDir(), t.TempDir()
+	for f, c := range crds {
+		if err := os.WriteFile(filepath.Join(crdDir, f), []byte(c), 0600); err != nil {
+			t.Fatalf("cannot write the CRD file: %v", err)
+		}
+	}
+	if err := NewRBACGenerator(rbacDir, "aws", "aws.upbound.io").Generate(crdDir); err != nil {
+		t.Fatalf("Generate(...): %v", err)
+	}
+	got := map[string]string{}
+	files, err := os.ReadDir(rbacDir)
+	if err != nil {
+		t.Fatalf("cannot read the RBAC directory: %v", err)
+	}
+	for _, f := range files {
+		b, err := os.ReadFile(filepath.Join(rbacDir, f.Name()))
+		if err != nil {
+			t.Fatalf("cannot read the ClusterRole file: %v", err)
+		}
+		got[f.Name()] = string(b)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nThe ClusterRoles of the API groups should be aggregated to the one of the provider.\nGenerate(...): -want, +got:\n%s", diff)
+	}
+}
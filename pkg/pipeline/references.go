@@ -0,0 +1,56 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/muvaf/typewriter/pkg/wrapper"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+)
+
+// NewInferredReferencesGenerator returns a new
+// InferredReferencesGenerator.
+func NewInferredReferencesGenerator(rootDir, modulePath string, overrides fs.FS) *InferredReferencesGenerator {
+	return &InferredReferencesGenerator{
+		LocalDirectoryPath: filepath.Join(rootDir, "config"),
+		LicenseHeaderPath:  licenseHeaderPath(rootDir),
+		ModulePath:         modulePath,
+		overrides:          overrides,
+	}
+}
+
+// InferredReferencesGenerator generates the file containing the inferred
+// cross-resource references for the maintainers to review.
+type InferredReferencesGenerator struct {
+	LocalDirectoryPath string
+	ModulePath         string
+	LicenseHeaderPath  string
+
+	overrides fs.FS
+}
+
+// Generate writes the inferred references file with the given references
+// keyed by the Terraform resource names.
+func (ig *InferredReferencesGenerator) Generate(refs map[string]config.References) error {
+	tmpl, err := templates.Resolve(ig.overrides, "inferred_references.go.tmpl")
+	if err != nil {
+		return err
+	}
+	refsFile := wrapper.NewFile(filepath.Join(ig.ModulePath, "config"), "config", tmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(ig.LicenseHeaderPath),
+	)
+	vars := map[string]any{
+		"References": refs,
+	}
+	filePath := filepath.Join(ig.LocalDirectoryPath, "zz_inferred_references.go")
+	return errors.Wrap(refsFile.Write(filePath, vars, os.ModePerm), "cannot write inferred references file")
+}
@@ -5,9 +5,11 @@ Copyright 2021 Upbound Inc.
 package pipeline
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/muvaf/typewriter/pkg/wrapper"
 	"github.com/pkg/errors"
@@ -16,11 +18,13 @@ import (
 )
 
 // NewRegisterGenerator returns a new RegisterGenerator.
-func NewRegisterGenerator(rootDir, modulePath string) *RegisterGenerator {
+func NewRegisterGenerator(rootDir, modulePath string, overrides fs.FS) *RegisterGenerator {
 	return &RegisterGenerator{
 		LocalDirectoryPath: filepath.Join(rootDir, "apis"),
-		LicenseHeaderPath:  filepath.Join(rootDir, "hack", "boilerplate.go.txt"),
+		LicenseHeaderPath:  licenseHeaderPath(rootDir),
 		ModulePath:         modulePath,
+		PackagePath:        filepath.Join(modulePath, "apis"),
+		overrides:          overrides,
 	}
 }
 
@@ -29,12 +33,27 @@ type RegisterGenerator struct {
 	LocalDirectoryPath string
 	ModulePath         string
 	LicenseHeaderPath  string
+	// PackagePath is the path of the package of the register file.
+	PackagePath string
+	// SchemeBuilder is the existing runtime.SchemeBuilder variable which
+	// the API version packages are registered into, qualified with the path
+	// of its package, e.g. "github.com/acme/provider-foo/apis.AddToSchemes".
+	// If empty, the AddToSchemes scheme builder is declared in the register
+	// file.
+	SchemeBuilder string
+
+	overrides fs.FS
 }
 
 // Generate writes the register file with the content produced using given
 // list of version packages.
 func (rg *RegisterGenerator) Generate(versionPkgList []string) error {
-	registerFile := wrapper.NewFile(filepath.Join(rg.ModulePath, "apis"), "apis", templates.RegisterTemplate,
+	pkgName := filepath.Base(rg.PackagePath)
+	tmpl, err := templates.Resolve(rg.overrides, "register.go.tmpl")
+	if err != nil {
+		return err
+	}
+	registerFile := wrapper.NewFile(rg.PackagePath, pkgName, tmpl,
 		wrapper.WithGenStatement(GenStatement),
 		wrapper.WithHeaderPath(rg.LicenseHeaderPath),
 	)
@@ -43,8 +62,22 @@ func (rg *RegisterGenerator) Generate(versionPkgList []string) error {
 	for i, pkgPath := range versionPkgList {
 		aliases[i] = registerFile.Imports.UsePackage(pkgPath)
 	}
+	schemeBuilder := "AddToSchemes"
+	if rg.SchemeBuilder != "" {
+		i := strings.LastIndex(rg.SchemeBuilder, ".")
+		if i < 1 || i == len(rg.SchemeBuilder)-1 {
+			return errors.Errorf("invalid scheme builder %q: the scheme builder must be qualified with the path of its package", rg.SchemeBuilder)
+		}
+		schemeBuilder = rg.SchemeBuilder[i+1:]
+		if pkgPath := rg.SchemeBuilder[:i]; pkgPath != rg.PackagePath {
+			schemeBuilder = registerFile.Imports.UsePackage(pkgPath) + schemeBuilder
+		}
+	}
 	vars := map[string]any{
-		"Aliases": aliases,
+		"PackageName":   pkgName,
+		"Aliases":       aliases,
+		"SchemeBuilder": schemeBuilder,
+		"Declare":       rg.SchemeBuilder == "",
 	}
 	filePath := filepath.Join(rg.LocalDirectoryPath, "zz_register.go")
 	return errors.Wrap(registerFile.Write(filePath, vars, os.ModePerm), "cannot write register file")
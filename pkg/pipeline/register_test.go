@@ -0,0 +1,117 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestRegisterGenerate(t *testing.T) {
+	type args struct {
+		packagePath   string
+		schemeBuilder string
+	}
+	type want struct {
+		contains    []string
+		notContains []string
+		err         error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Declared": {
+			reason: "The AddToSchemes scheme builder should be declared in the register file if no scheme builder is configured.",
+			args: args{
+				packagePath: "example.org/provider/apis",
+			},
+			want: want{
+				contains: []string{
+					"package apis",
+					"AddToSchemes = append(AddToSchemes,",
+					"var AddToSchemes runtime.SchemeBuilder",
+				},
+			},
+		},
+		"SchemeBuilderInPackage": {
+			reason: "The API version packages should be registered into the configured scheme builder of the package of the register file without declaring it.",
+			args: args{
+				packagePath:   "example.org/provider/apis/upjet",
+				schemeBuilder: "example.org/provider/apis/upjet.AddToSchemes",
+			},
+			want: want{
+				contains:    []string{"package upjet", "AddToSchemes = append(AddToSchemes,"},
+				notContains: []string{"var AddToSchemes"},
+			},
+		},
+		"SchemeBuilderInOtherPackage": {
+			reason: "The API version packages should be registered into the configured scheme builder of another package by importing it.",
+			args: args{
+				packagePath:   "example.org/provider/apis/upjet",
+				schemeBuilder: "example.org/provider/apis.AddToSchemes",
+			},
+			want: want{
+				contains:    []string{`"example.org/provider/apis"`, "apis.AddToSchemes = append(apis.AddToSchemes,"},
+				notContains: []string{"var AddToSchemes"},
+			},
+		},
+		"UnqualifiedSchemeBuilder": {
+			reason: "An error should be returned if the configured scheme builder is not qualified with the path of its package.",
+			args: args{
+				packagePath:   "example.org/provider/apis",
+				schemeBuilder: "AddToSchemes",
+			},
+			want: want{
+				err: errors.Errorf("invalid scheme builder %q: the scheme builder must be qualified with the path of its package", "AddToSchemes"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(rootDir, "hack"), 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(rootDir, "hack", "boilerplate.go.txt"), []byte("/*\nCopyright 2023 Upbound Inc.\n*/"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			rg := NewRegisterGenerator(rootDir, "example.org/provider", nil)
+			rg.PackagePath = tc.args.packagePath
+			rg.SchemeBuilder = tc.args.schemeBuilder
+			if err := os.MkdirAll(rg.LocalDirectoryPath, 0750); err != nil {
+				t.Fatal(err)
+			}
+			err := rg.Generate([]string{"example.org/provider/apis/upjet/ec2/v1beta1"})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nGenerate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			b, err := os.ReadFile(filepath.Join(rg.LocalDirectoryPath, "zz_register.go"))
+			if err != nil {
+				t.Fatalf("cannot read the register file: %v", err)
+			}
+			for _, s := range tc.want.contains {
+				if !strings.Contains(string(b), s) {
+					t.Errorf("\n%s\nGenerate(...): register file does not contain %q:\n%s", tc.reason, s, string(b))
+				}
+			}
+			for _, s := range tc.want.notContains {
+				if strings.Contains(string(b), s) {
+					t.Errorf("\n%s\nGenerate(...): register file contains %q:\n%s", tc.reason, s, string(b))
+				}
+			}
+		})
+	}
+}
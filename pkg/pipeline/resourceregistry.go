@@ -0,0 +1,146 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/muvaf/typewriter/pkg/wrapper"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+)
+
+const resourceRegistryRoot = "apis/registry"
+
+// NewResourceRegistryGenerator returns a new ResourceRegistryGenerator.
+func NewResourceRegistryGenerator(rootDir, modulePath string, overrides fs.FS) *ResourceRegistryGenerator {
+	return &ResourceRegistryGenerator{
+		LocalDirectoryPath: filepath.Join(rootDir, resourceRegistryRoot),
+		LicenseHeaderPath:  licenseHeaderPath(rootDir),
+		PackagePath:        filepath.Join(modulePath, resourceRegistryRoot),
+		overrides:          overrides,
+	}
+}
+
+// ResourceRegistryGenerator generates the "apis/registry" package with the
+// identities of the managed resources, i.e. their GroupVersionKinds, the
+// names of their Terraform resources and the names of their CRDs, so that
+// they can be looked up instead of being hard-coded.
+type ResourceRegistryGenerator struct {
+	LocalDirectoryPath string
+	LicenseHeaderPath  string
+	PackagePath        string
+
+	overrides fs.FS
+}
+
+type crdIdentity struct {
+	Spec struct {
+		Group string `json:"group"`
+		Names struct {
+			Kind   string `json:"kind"`
+			Plural string `json:"plural"`
+		} `json:"names"`
+		Versions []struct {
+			Name    string `json:"name"`
+			Served  bool   `json:"served"`
+			Storage bool   `json:"storage"`
+		} `json:"versions"`
+	} `json:"spec"`
+}
+
+type registryResource struct {
+	TerraformResource string
+	DataSource        bool
+	Group             string
+	Version           string
+	Kind              string
+	Versions          []string
+	CRDName           string
+}
+
+// Generate writes the identities of the resources of the given provider
+// whose CRDs are in the given directory, e.g. "package/crds". It's meant to
+// be run after the CRDs are generated by controller-gen as the names of the
+// CRDs are derived from the plural names chosen by controller-gen.
+func (rg *ResourceRegistryGenerator) Generate(pc *config.Provider, crdDir string) error {
+	crds, err := readCRDs(crdDir)
+	if err != nil {
+		return err
+	}
+	identities := make(map[string]*crdIdentity, len(crds))
+	for _, c := range crds {
+		crd := &crdIdentity{}
+		if err := yaml.Unmarshal(c.doc, crd); err != nil {
+			return errors.Wrapf(err, "cannot unmarshal the CRD file %s", c.file)
+		}
+		identities[crd.Spec.Group+"/"+crd.Spec.Names.Kind] = crd
+	}
+	resources, groups := registryResources(pc, identities)
+	tmpl, err := templates.Resolve(rg.overrides, "resource_registry.go.tmpl")
+	if err != nil {
+		return err
+	}
+	registryFile := wrapper.NewFile(rg.PackagePath, "registry", tmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(rg.LicenseHeaderPath),
+	)
+	vars := map[string]any{
+		"Groups":    groups,
+		"Resources": resources,
+	}
+	if err := os.MkdirAll(rg.LocalDirectoryPath, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "cannot mkdir %s", rg.LocalDirectoryPath)
+	}
+	return errors.Wrap(registryFile.Write(filepath.Join(rg.LocalDirectoryPath, "zz_registry.go"), vars, os.ModePerm), "cannot write resource registry file")
+}
+
+// registryResources returns the identities of the resources of the given
+// provider with the given CRDs keyed by their groups and Kinds, sorted by
+// the names of their CRDs, and their sorted groups.
+func registryResources(pc *config.Provider, identities map[string]*crdIdentity) ([]registryResource, []string) {
+	var resources []registryResource
+	groups := map[string]struct{}{}
+	for _, r := range pc.Resources {
+		group := apiGroup(pc, r)
+		crd, ok := identities[group+"/"+r.Kind]
+		if !ok {
+			continue
+		}
+		rr := registryResource{
+			TerraformResource: r.Name,
+			DataSource:        r.DataSource,
+			Group:             group,
+			Kind:              r.Kind,
+			CRDName:           fmt.Sprintf("%s.%s", crd.Spec.Names.Plural, group),
+		}
+		for _, v := range crd.Spec.Versions {
+			if v.Storage {
+				rr.Version = v.Name
+			}
+			if v.Served {
+				rr.Versions = append(rr.Versions, v.Name)
+			}
+		}
+		resources = append(resources, rr)
+		groups[group] = struct{}{}
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].CRDName < resources[j].CRDName
+	})
+	groupList := make([]string, 0, len(groups))
+	for g := range groups {
+		groupList = append(groupList, g)
+	}
+	sort.Strings(groupList)
+	return resources, groupList
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/yaml"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+func TestRegistryResources(t *testing.T) {
+	crds := []string{`
+spec:
+  group: ec2.aws.upbound.io
+  names:
+    kind: VPC
+    plural: vpcs
+  versions:
+  - name: v1beta1
+    served: true
+  - name: v1beta2
+    served: true
+    storage: true
+`, `
+spec:
+  group: aws.upbound.io
+  names:
+    kind: ProviderConfig
+    plural: providerconfigs
+  versions:
+  - name: v1beta1
+    served: true
+    storage: true
+`, `
+spec:
+  group: ec2.aws.upbound.io
+  names:
+    kind: DataVPC
+    plural: datavpcs
+  versions:
+  - name: v1beta1
+    served: true
+    storage: true
+`}
+	identities := map[string]*crdIdentity{}
+	for _, c := range crds {
+		crd := &crdIdentity{}
+		if err := yaml.Unmarshal([]byte(c), crd); err != nil {
+			t.Fatal(err)
+		}
+		identities[crd.Spec.Group+"/"+crd.Spec.Names.Kind] = crd
+	}
+	pc := &config.Provider{
+		RootGroup: "aws.upbound.io",
+		Resources: map[string]*config.Resource{
+			"aws_vpc": {
+				Name:       "aws_vpc",
+				Kind:       "VPC",
+				ShortGroup: "ec2",
+			},
+			config.DataSourceKey("aws_vpc"): {
+				Name:       "aws_vpc",
+				Kind:       "DataVPC",
+				ShortGroup: "ec2",
+				DataSource: true,
+			},
+			"aws_subnet": {
+				Name:       "aws_subnet",
+				Kind:       "Subnet",
+				ShortGroup: "ec2",
+			},
+		},
+	}
+	type want struct {
+		resources []registryResource
+		groups    []string
+	}
+	w := want{
+		resources: []registryResource{
+			{
+				TerraformResource: "aws_vpc",
+				DataSource:        true,
+				Group:             "ec2.aws.upbound.io",
+				Version:           "v1beta1",
+				Kind:              "DataVPC",
+				Versions:          []string{"v1beta1"},
+				CRDName:           "datavpcs.ec2.aws.upbound.io",
+			},
+			{
+				TerraformResource: "aws_vpc",
+				Group:             "ec2.aws.upbound.io",
+				Version:           "v1beta2",
+				Kind:              "VPC",
+				Versions:          []string{"v1beta1", "v1beta2"},
+				CRDName:           "vpcs.ec2.aws.upbound.io",
+			},
+		},
+		groups: []string{"ec2.aws.upbound.io"},
+	}
+	resources, groups := registryResources(pc, identities)
+	reason := "The resources with CRDs should be identified with their storage versions and the CRD names of their plurals, and the CRDs of the other Kinds should be ignored."
+	if diff := cmp.Diff(w.resources, resources); diff != "" {
+		t.Errorf("\n%s\nregistryResources(...): -want resources, +got resources:\n%s", reason, diff)
+	}
+	if diff := cmp.Diff(w.groups, groups); diff != "" {
+		t.Errorf("\n%s\nregistryResources(...): -want groups, +got groups:\n%s", reason, diff)
+	}
+}
@@ -6,50 +6,242 @@ package pipeline
 
 import (
 	"fmt"
+	"io/fs"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
 	"github.com/upbound/upjet/pkg/config"
 	"github.com/upbound/upjet/pkg/examples"
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+	"github.com/upbound/upjet/pkg/registry/reference"
+	tjtypes "github.com/upbound/upjet/pkg/types"
 )
 
 type terraformedInput struct {
 	*config.Resource
-	ParametersTypeName string
+	ParametersTypeName     string
+	InitParametersTypeName string
 }
 
-// Run runs the Upjet code generation pipelines.
-func Run(pc *config.Provider, rootDir string) { // nolint:gocyclo
+// RunOption configures the code generation pipelines.
+type RunOption func(*runOptions)
+
+type runOptions struct {
+	groupConcurrency int
+	incremental      bool
+	force            bool
+	coverage         string
+	hooks            []Hook
+	// apisRoot and controllersRoot are the roots of the generated API and
+	// controller packages relative to the root directory and the module
+	// path.
+	apisRoot        string
+	controllersRoot string
+	schemeBuilder   string
+	// include and exclude are the patterns of the Terraform resources
+	// selected for a partial run.
+	include []string
+	exclude []string
+	// templateOverrides overlay the built-in templates and the ones of the
+	// provider configuration.
+	templateOverrides fs.FS
+}
+
+// WithGroupConcurrency configures the number of the API groups generated
+// concurrently, e.g. with a --group-concurrency flag of the provider's
+// generator. The groups whose configurations share a Terraform resource,
+// e.g. via the resource aliases or the moved Kinds, are generated
+// sequentially as a single batch, and the resources of a group are
+// generated sequentially as they share the type names of their version
+// packages, so the speedup is bounded by the number and the sizes of the
+// groups. The groups are generated sequentially by default, and as many
+// groups as the number of the CPUs are generated concurrently if it's not
+// positive.
+func WithGroupConcurrency(n int) RunOption {
+	return func(o *runOptions) {
+		o.groupConcurrency = n
+	}
+}
+
+// WithIncrementalGeneration enables the incremental generation, which
+// skips the generation of the resources whose inputs, i.e. their schemas,
+// configurations and metadata, the provider configuration, the templates
+// and the version of upjet, haven't changed since they were last generated.
+// The hashes of the inputs are persisted in the ".work/upjet" directory. If
+// force is true, e.g. with a --force flag of the provider's generator, all
+// the resources are generated and their hashes are persisted.
+func WithIncrementalGeneration(force bool) RunOption {
+	return func(o *runOptions) {
+		o.incremental = true
+		o.force = force
+	}
+}
+
+// WithCoverageReport enables the machine-readable report of the coverage of
+// the Terraform resources by the generated managed resources, which is
+// written to the given path relative to the root directory, e.g.
+// "config/zz_coverage.json". See CoverageReport for details.
+func WithCoverageReport(path string) RunOption {
+	return func(o *runOptions) {
+		o.coverage = path
+	}
+}
+
+// WithAPIsRoot configures the root directory of the generated API packages
+// relative to the root directory and the module path, which is "apis" by
+// default, e.g. "apis/upjet" to generate a handful of managed resources
+// inside a hand-written provider without adopting the layout of the upjet
+// based providers. The name of the package of the register file is the base
+// name of the directory.
+func WithAPIsRoot(dir string) RunOption {
+	return func(o *runOptions) {
+		o.apisRoot = dir
+	}
+}
+
+// WithControllersRoot configures the root directory of the generated
+// controller packages relative to the root directory and the module path,
+// which is "internal/controller" by default. The name of the package of the
+// setup files is the base name of the directory.
+func WithControllersRoot(dir string) RunOption {
+	return func(o *runOptions) {
+		o.controllersRoot = dir
+	}
+}
+
+// WithSchemeBuilder registers the generated API version packages into the
+// given existing runtime.SchemeBuilder variable, which is qualified with the
+// path of its package, e.g. "github.com/acme/provider-foo/apis.AddToSchemes",
+// instead of declaring the AddToSchemes scheme builder in the register file.
+// This is useful to add the generated managed resources to the scheme of a
+// hand-written provider.
+func WithSchemeBuilder(schemeBuilder string) RunOption {
+	return func(o *runOptions) {
+		o.schemeBuilder = schemeBuilder
+	}
+}
+
+// WithResourceSelection limits the resources generated in this run to the
+// ones whose Terraform resource names match any of the given include
+// patterns, or all the resources if none given, and none of the given
+// exclude patterns, e.g. with the --resources 'aws_s3_.*' flag of the
+// provider's generator, to speed up focused development and debugging. The
+// patterns are regular expressions as in config.Provider.IncludeList. The
+// files of the resources not selected are preserved and the outputs of
+// their previous generations are restored from the generation cache, which
+// is persisted by the incremental generation and the partial runs. The
+// resources not found in the generation cache are generated.
+func WithResourceSelection(include, exclude []string) RunOption {
+	return func(o *runOptions) {
+		o.include = include
+		o.exclude = exclude
+	}
+}
+
+// WithTemplateOverrides overlays the built-in templates and the
+// TemplateOverrides of the provider configuration with the templates with
+// the same names in the given file system, e.g. by the tooling driving the
+// generation programmatically. Any of the built-in templates in the
+// "pkg/pipeline/templates" package can be overridden, and the overriding
+// templates are resolved when they're rendered.
+func WithTemplateOverrides(fsys fs.FS) RunOption {
+	return func(o *runOptions) {
+		o.templateOverrides = fsys
+	}
+}
+
+// groupConcurrency returns the number of the API groups generated
+// concurrently for the given configured concurrency.
+func groupConcurrency(n int) int {
+	if n < 1 {
+		return runtime.NumCPU()
+	}
+	return n
+}
+
+// Run runs the Upjet code generation pipelines. The outputs are generated
+// in a staging copy of the output directories under ".work/upjet", whose
+// changed files are moved to the root directory only if the generation
+// succeeds. The stale files generated by upjet, e.g. the files of the
+// resources removed from the configuration, are removed.
+func Run(pc *config.Provider, rootDir string, opts ...RunOption) { // nolint:gocyclo
 	// Note(turkenh): nolint reasoning - this is the main function of the code
 	// generation pipeline. We didn't want to split it into multiple functions
 	// for better readability considering the straightforward logic here.
 
+	o := &runOptions{
+		groupConcurrency: 1,
+		apisRoot:         apiRoot,
+		controllersRoot:  filepath.Join("internal", "controller"),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	tmpls, err := loadTemplates(newOverlayFS(o.templateOverrides, pc.TemplateOverrides))
+	if err != nil {
+		panic(errors.Wrap(err, "cannot load the templates"))
+	}
+	targetDir := rootDir
+	tx, err := newTransaction(targetDir, outputPaths(pc, o))
+	if err != nil {
+		panic(errors.Wrap(err, "cannot stage the outputs"))
+	}
+	// the outputs are generated in the staging directory, which is discarded
+	// if the generation fails, so that the root directory is left intact.
+	defer tx.discard()
+	rootDir = tx.dir
+
+	if err := WriteLicenseHeader(pc, rootDir, time.Now()); err != nil {
+		panic(errors.Wrap(err, "cannot write the license header"))
+	}
+
 	// Group resources based on their Group and API Versions.
 	// An example entry in the tree would be:
 	// ec2.awsjet.crossplane.io -> v1alpha1 -> aws_vpc
 	resourcesGroups := map[string]map[string]map[string]*config.Resource{}
 	for name, resource := range pc.Resources {
-		group := pc.RootGroup
-		if resource.ShortGroup != "" {
-			group = strings.ToLower(resource.ShortGroup) + "." + pc.RootGroup
-		}
+		group := apiGroup(pc, resource)
 		if len(resourcesGroups[group]) == 0 {
 			resourcesGroups[group] = map[string]map[string]*config.Resource{}
 		}
-		if len(resourcesGroups[group][resource.Version]) == 0 {
-			resourcesGroups[group][resource.Version] = map[string]*config.Resource{}
+		for _, version := range append([]string{resource.Version}, resource.ServedVersions...) {
+			if len(resourcesGroups[group][version]) == 0 {
+				resourcesGroups[group][version] = map[string]*config.Resource{}
+			}
+			resourcesGroups[group][version][name] = resource
 		}
-		resourcesGroups[group][resource.Version][name] = resource
 	}
 
-	exampleGen := examples.NewGenerator(rootDir, pc.ModulePath, pc.ShortName, pc.Resources)
+	var exampleOpts []examples.GeneratorOption
+	if pc.ExampleSyncWaveAnnotationKey != "" {
+		exampleOpts = append(exampleOpts, examples.WithSyncWaveAnnotation(pc.ExampleSyncWaveAnnotationKey))
+	}
+	if len(pc.ExternalProviders) > 0 {
+		exampleOpts = append(exampleOpts, examples.WithExternalProviders(pc.ExternalProviders))
+	}
+	exampleGen := examples.NewGenerator(rootDir, pc.ModulePath, pc.ShortName, pc.Resources, exampleOpts...)
 	if err := exampleGen.SetReferenceTypes(pc.Resources); err != nil {
 		panic(errors.Wrap(err, "cannot set reference types for resources"))
 	}
+	if pc.InferReferences {
+		refs := reference.NewInferrer(pc.TerraformResourcePrefix).InferReferences(pc.Resources)
+		if err := NewInferredReferencesGenerator(rootDir, pc.ModulePath, tmpls.Overrides).Generate(refs); err != nil {
+			panic(errors.Wrap(err, "cannot generate inferred references file"))
+		}
+	}
+	if pc.ExternalNameTests != nil {
+		if err := NewExternalNameTestsGenerator(rootDir, pc.ModulePath, tmpls.Overrides).Generate(*pc.ExternalNameTests, pc.Resources); err != nil {
+			panic(errors.Wrap(err, "cannot generate external name tests file"))
+		}
+	}
 	// Add ProviderConfig API package to the list of API version packages.
 	apiVersionPkgList := make([]string, 0)
 	for _, p := range pc.BasePackages.APIVersion {
@@ -87,63 +279,137 @@ func Run(pc *config.Provider, rootDir string) { // nolint:gocyclo
 			controllerPkgMap[config.PackageNameMonolith] = append(controllerPkgMap[config.PackageNameMonolith], path)
 		}
 	}
-	count := 0
-	for group, versions := range resourcesGroups {
-		for version, resources := range versions {
-			var tfResources []*terraformedInput
-			versionGen := NewVersionGenerator(rootDir, pc.ModulePath, group, version)
-			crdGen := NewCRDGenerator(versionGen.Package(), rootDir, pc.ShortName, group, version)
-			tfGen := NewTerraformedGenerator(versionGen.Package(), rootDir, group, version)
-			ctrlGen := NewControllerGenerator(rootDir, pc.ModulePath, group)
-
-			for _, name := range sortedResources(resources) {
-				paramTypeName, err := crdGen.Generate(resources[name])
-				if err != nil {
-					panic(errors.Wrapf(err, "cannot generate crd for resource %s", name))
-				}
-				tfResources = append(tfResources, &terraformedInput{
-					Resource:           resources[name],
-					ParametersTypeName: paramTypeName,
-				})
-
-				featuresPkgPath := ""
-				if pc.FeaturesPackage != "" {
-					featuresPkgPath = filepath.Join(pc.ModulePath, pc.FeaturesPackage)
-				}
-				ctrlPkgPath, err := ctrlGen.Generate(resources[name], versionGen.Package().Path(), featuresPkgPath)
+	var sharedBlocks map[string]*tjtypes.SharedBlock
+	if pc.SharedTypesPackage != "" {
+		var err error
+		if sharedBlocks, err = NewSharedTypesGenerator(rootDir, pc.ModulePath, pc.SharedTypesPackage, tmpls.Overrides).Generate(pc.SharedBlockSchemas); err != nil {
+			panic(errors.Wrap(err, "cannot generate the shared types"))
+		}
+		for _, c := range pc.SharedBlockCandidates() {
+			fmt.Printf("Found identical blocks, which can be shared with SharedBlockSchemas: %s\n", strings.Join(c, ", "))
+		}
+	}
+	var docsGen *DocsGenerator
+	if pc.GenerateAPIDocs {
+		docsGen = NewDocsGenerator(rootDir, tmpls.Overrides)
+		docsGen.Frontmatter = pc.APIDocsFrontmatter
+	}
+	gens := groupGenerators{
+		examples:     exampleGen,
+		docs:         docsGen,
+		sharedBlocks: sharedBlocks,
+		templates:    tmpls,
+		hooks:        o.hooks,
+		layout: layout{
+			apisRoot:        o.apisRoot,
+			controllersRoot: o.controllersRoot,
+		},
+	}
+	if len(o.include) > 0 || len(o.exclude) > 0 {
+		var err error
+		if gens.selection, err = newResourceSelection(o.include, o.exclude); err != nil {
+			panic(errors.Wrap(err, "cannot select the resources"))
+		}
+	}
+	gens.incremental = o.incremental
+	if o.incremental || gens.selection != nil {
+		var err error
+		if gens.cache, err = newGenerationCache(rootDir, o.force); err != nil {
+			panic(errors.Wrap(err, "cannot load the generation cache"))
+		}
+		gens.hashes = inputHashes(pc, tmpls, resourcesGroups)
+	}
+	outputs := make(chan *groupOutput, len(resourcesGroups))
+	errs := make(chan error, len(resourcesGroups))
+	sem := make(chan struct{}, groupConcurrency(o.groupConcurrency))
+	var wg sync.WaitGroup
+	for _, batch := range groupBatches(resourcesGroups) {
+		wg.Add(1)
+		go func(batch []string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			for _, group := range batch {
+				out, err := generateGroup(pc, rootDir, group, resourcesGroups[group], gens)
 				if err != nil {
-					panic(errors.Wrapf(err, "cannot generate controller for resource %s", name))
-				}
-				sGroup := strings.Split(group, ".")[0]
-				controllerPkgMap[sGroup] = append(controllerPkgMap[sGroup], ctrlPkgPath)
-				controllerPkgMap[config.PackageNameMonolith] = append(controllerPkgMap[config.PackageNameMonolith], ctrlPkgPath)
-				if err := exampleGen.Generate(group, version, resources[name]); err != nil {
-					panic(errors.Wrapf(err, "cannot generate example manifest for resource %s", name))
+					errs <- errors.Wrapf(err, "cannot generate group %s", group)
+					return
 				}
-				count++
+				outputs <- out
 			}
-
-			if err := tfGen.Generate(tfResources, version); err != nil {
-				panic(errors.Wrapf(err, "cannot generate terraformed for resource %s", group))
-			}
-
-			if err := versionGen.Generate(); err != nil {
-				panic(errors.Wrap(err, "cannot generate version files"))
-			}
-			apiVersionPkgList = append(apiVersionPkgList, versionGen.Package().Path())
+		}(batch)
+	}
+	wg.Wait()
+	close(outputs)
+	close(errs)
+	if err := <-errs; err != nil {
+		panic(err)
+	}
+	count, skipped, preserved := 0, 0, 0
+	// The provider manifest is only generated if any of the resources
+	// declares composition hints.
+	var manifestResources []ManifestResource
+	hasCompositionHints := false
+	var artifacts []ResourceArtifacts
+	kept := map[string]struct{}{}
+	for out := range outputs {
+		apiVersionPkgList = append(apiVersionPkgList, out.apiVersionPkgs...)
+		for g, pkgs := range out.controllerPkgs {
+			controllerPkgMap[g] = append(controllerPkgMap[g], pkgs...)
+		}
+		manifestResources = append(manifestResources, out.manifestResources...)
+		artifacts = append(artifacts, out.artifacts...)
+		hasCompositionHints = hasCompositionHints || out.hasCompositionHints
+		count += out.count
+		skipped += out.skipped
+		for _, f := range out.kept {
+			kept[f] = struct{}{}
 		}
+		preserved += out.preserved
 	}
 
 	if err := exampleGen.StoreExamples(); err != nil {
 		panic(errors.Wrapf(err, "cannot store examples"))
 	}
 
-	if err := NewRegisterGenerator(rootDir, pc.ModulePath).Generate(apiVersionPkgList); err != nil {
+	if docsGen != nil {
+		if err := docsGen.GenerateIndex(); err != nil {
+			panic(errors.Wrap(err, "cannot generate the API reference index"))
+		}
+	}
+
+	if hasCompositionHints {
+		if err := NewManifestGenerator(rootDir).Generate(manifestResources); err != nil {
+			panic(errors.Wrap(err, "cannot generate provider manifest"))
+		}
+	}
+
+	registerGen := NewRegisterGenerator(rootDir, pc.ModulePath, tmpls.Overrides)
+	registerGen.LocalDirectoryPath = filepath.Join(rootDir, o.apisRoot)
+	registerGen.PackagePath = filepath.Join(pc.ModulePath, o.apisRoot)
+	registerGen.SchemeBuilder = o.schemeBuilder
+	if err := registerGen.Generate(apiVersionPkgList); err != nil {
 		panic(errors.Wrap(err, "cannot generate register file"))
 	}
+	if pc.ProviderConfigAuth != nil {
+		if err := NewProviderConfigGenerator(rootDir, pc.ModulePath, pc.ShortName, tmpls.Overrides).Generate(*pc.ProviderConfigAuth); err != nil {
+			panic(errors.Wrap(err, "cannot generate the ProviderConfig"))
+		}
+	}
 	// Generate the provider,
 	// i.e. the setup function and optionally the provider's main program.
-	if err := NewProviderGenerator(rootDir, pc.ModulePath).Generate(controllerPkgMap, pc.MainTemplate); err != nil {
+	providerGen := NewProviderGenerator(rootDir, pc.ModulePath)
+	providerGen.LocalDirectoryPath = filepath.Join(rootDir, o.controllersRoot)
+	providerGen.Template = tmpls.Setup
+	providerGen.FamilyBuildTag = pc.FamilyBuildTag
+	providerGen.ShortName = pc.ShortName
+	mainTemplate := pc.MainTemplate
+	if mainTemplate == "" && pc.GenerateSubProviderMains {
+		if mainTemplate, err = templates.Resolve(tmpls.Overrides, "main.go.tmpl"); err != nil {
+			panic(err)
+		}
+	}
+	if err := providerGen.Generate(controllerPkgMap, mainTemplate); err != nil {
 		panic(errors.Wrap(err, "cannot generate setup file"))
 	}
 
@@ -151,20 +417,503 @@ func Run(pc *config.Provider, rootDir string) { // nolint:gocyclo
 	// So, we set the directory of the command instead of passing in the directory
 	// as an argument to "find".
 	apisCmd := exec.Command("bash", "-c", "goimports -w $(find . -iname 'zz_*')")
-	apisCmd.Dir = filepath.Clean(filepath.Join(rootDir, "apis"))
+	apisCmd.Dir = filepath.Clean(filepath.Join(rootDir, o.apisRoot))
 	if out, err := apisCmd.CombinedOutput(); err != nil {
 		panic(errors.Wrap(err, "cannot run goimports for apis folder: "+string(out)))
 	}
 
 	internalCmd := exec.Command("bash", "-c", "goimports -w $(find . -iname 'zz_*')")
-	internalCmd.Dir = filepath.Clean(filepath.Join(rootDir, "internal"))
+	internalCmd.Dir = filepath.Clean(filepath.Join(rootDir, o.controllersRoot))
 	if out, err := internalCmd.CombinedOutput(); err != nil {
-		panic(errors.Wrap(err, "cannot run goimports for internal folder: "+string(out)))
+		panic(errors.Wrap(err, "cannot run goimports for controllers folder: "+string(out)))
+	}
+
+	if pc.GenerateTypedClients {
+		clientCmd := exec.Command("bash", "-c", "goimports -w $(find . -iname 'zz_*')")
+		clientCmd.Dir = filepath.Clean(filepath.Join(rootDir, clientRoot))
+		if out, err := clientCmd.CombinedOutput(); err != nil {
+			panic(errors.Wrap(err, "cannot run goimports for client folder: "+string(out)))
+		}
 	}
 
+	if pc.GenerateApplyConfigurations {
+		applyConfigurationCmd := exec.Command("bash", "-c", "goimports -w $(find . -iname 'zz_*')")
+		applyConfigurationCmd.Dir = filepath.Clean(filepath.Join(rootDir, applyConfigurationRoot))
+		if out, err := applyConfigurationCmd.CombinedOutput(); err != nil {
+			panic(errors.Wrap(err, "cannot run goimports for apply configuration folder: "+string(out)))
+		}
+	}
+
+	if pc.ProviderConfigAuth != nil {
+		credentialsCmd := exec.Command("bash", "-c", "goimports -w $(find . -iname 'zz_*')")
+		credentialsCmd.Dir = filepath.Clean(filepath.Join(rootDir, credentialsRoot))
+		if out, err := credentialsCmd.CombinedOutput(); err != nil {
+			panic(errors.Wrap(err, "cannot run goimports for credentials folder: "+string(out)))
+		}
+	}
+
+	if o.coverage != "" {
+		if err := NewCoverageReport(pc).Write(filepath.Join(rootDir, o.coverage)); err != nil {
+			panic(errors.Wrap(err, "cannot write the coverage report"))
+		}
+	}
+
+	if len(o.hooks) > 0 {
+		sort.Slice(artifacts, func(i, j int) bool {
+			if artifacts[i].Group != artifacts[j].Group {
+				return artifacts[i].Group < artifacts[j].Group
+			}
+			if artifacts[i].Version != artifacts[j].Version {
+				return artifacts[i].Version < artifacts[j].Version
+			}
+			return artifacts[i].Resource.Name < artifacts[j].Resource.Name
+		})
+		for _, h := range o.hooks {
+			if err := h.AfterAll(artifacts); err != nil {
+				panic(errors.Wrap(err, "cannot run the hook after the generation"))
+			}
+		}
+	}
+
+	if err := gens.cache.write(); err != nil {
+		panic(errors.Wrap(err, "cannot write the generation cache"))
+	}
+
+	if err := tx.removeStale(kept); err != nil {
+		panic(errors.Wrap(err, "cannot remove the stale files"))
+	}
+	if err := tx.commit(); err != nil {
+		panic(errors.Wrap(err, "cannot commit the outputs"))
+	}
+
+	// Require the configured versions of the external provider modules whose
+	// API types are imported by the generated reference resolvers.
+	for _, ep := range pc.ExternalProviders {
+		if ep.Version == "" {
+			continue
+		}
+		getCmd := exec.Command("go", "get", ep.ModulePath+"@"+ep.Version) // nolint:gosec
+		getCmd.Dir = filepath.Clean(targetDir)
+		if out, err := getCmd.CombinedOutput(); err != nil {
+			panic(errors.Wrapf(err, "cannot require external provider module %s: %s", ep.ModulePath, string(out)))
+		}
+	}
+
+	if skipped > 0 {
+		fmt.Printf("\nSkipped the generation of %d unchanged resources.", skipped)
+	}
+	if preserved > 0 {
+		fmt.Printf("\nPreserved %d resources not selected for this run.", preserved)
+	}
 	fmt.Printf("\nGenerated %d resources!\n", count)
 }
 
+// groupBatches returns the batches of the given API groups, which are
+// generated concurrently while the groups in a batch are generated
+// sequentially. The groups of the resources configured with the same
+// Terraform resource, i.e. its aliases and moved Kinds, are in the same
+// batch as their configurations share the nested schemas.
+func groupBatches(resourcesGroups map[string]map[string]map[string]*config.Resource) [][]string {
+	parents := make(map[string]string, len(resourcesGroups))
+	var find func(string) string
+	find = func(g string) string {
+		if parents[g] != g {
+			parents[g] = find(parents[g])
+		}
+		return parents[g]
+	}
+	groupOf := map[string]string{}
+	for g, versions := range resourcesGroups {
+		parents[g] = g
+		for _, resources := range versions {
+			for _, r := range resources {
+				if _, ok := groupOf[r.Name]; !ok {
+					groupOf[r.Name] = g
+				}
+			}
+		}
+	}
+	for g, versions := range resourcesGroups {
+		for _, resources := range versions {
+			for _, r := range resources {
+				parents[find(g)] = find(groupOf[r.Name])
+			}
+		}
+	}
+	batches := map[string][]string{}
+	for g := range resourcesGroups {
+		batches[find(g)] = append(batches[find(g)], g)
+	}
+	result := make([][]string, 0, len(batches))
+	for _, b := range batches {
+		sort.Strings(b)
+		result = append(result, b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i][0] < result[j][0]
+	})
+	return result
+}
+
+// groupGenerators are the generators shared by the API groups, which are
+// safe for concurrent use.
+type groupGenerators struct {
+	examples     *examples.Generator
+	docs         *DocsGenerator
+	sharedBlocks map[string]*tjtypes.SharedBlock
+	templates    generationTemplates
+	// cache is the generation cache, which is nil unless the incremental
+	// generation is enabled, and hashes are the hashes of the inputs of
+	// the resources keyed by their generation cache keys.
+	cache  *generationCache
+	hashes map[string]string
+	hooks  []Hook
+	layout layout
+	// incremental is true if the unchanged resources are not generated
+	// again, and selection is the selection of the resources of a partial
+	// run, which is nil if all the resources are selected.
+	incremental bool
+	selection   *resourceSelection
+}
+
+// layout is the layout of the generated API and controller packages in the
+// module of the provider.
+type layout struct {
+	apisRoot        string
+	controllersRoot string
+}
+
+// groupOutput is the output of the generation of an API group, which is
+// merged into the outputs of the other groups.
+type groupOutput struct {
+	apiVersionPkgs      []string
+	controllerPkgs      map[string][]string
+	manifestResources   []ManifestResource
+	artifacts           []ResourceArtifacts
+	hasCompositionHints bool
+	// kept are the files of the resources whose generations are skipped,
+	// which are kept as they are.
+	kept      []string
+	count     int
+	skipped   int
+	preserved int
+}
+
+// generateGroup generates the API versions of the given group with their
+// resources. The groups can be generated concurrently as each resource, and
+// thus its configuration, belongs to a single group.
+func generateGroup(pc *config.Provider, rootDir, group string, versions map[string]map[string]*config.Resource, gens groupGenerators) (*groupOutput, error) { // nolint:gocyclo
+	out := &groupOutput{controllerPkgs: map[string][]string{}}
+	// the versions are generated in order as the resources served in
+	// multiple versions accumulate the outputs of their generations, e.g.
+	// their late-initialization filters.
+	for _, version := range sortedVersions(versions) {
+		resources := versions[version]
+		var tfResources []*terraformedInput
+		var hubs, spokes, withExamples []*config.Resource
+		var artifacts []ResourceArtifacts
+		versionGen := newVersionGenerator(rootDir, pc.ModulePath, gens.layout.apisRoot, group, version, gens.templates.Overrides)
+		crdGen := NewCRDGenerator(versionGen.Package(), rootDir, pc.ShortName, group, version)
+		crdGen.LocalDirectoryPath = versionGen.DirectoryPath
+		crdGen.MaxTypeNameLength = pc.MaxTypeNameLength
+		crdGen.StrictIgnoredFields = pc.StrictIgnoredFields
+		crdGen.SharedBlocks = gens.sharedBlocks
+		crdGen.Template = gens.templates.CRDTypes
+		tfGen := NewTerraformedGenerator(versionGen.Package(), rootDir, group, version)
+		tfGen.LocalDirectoryPath = versionGen.DirectoryPath
+		tfGen.Template = gens.templates.Terraformed
+		ctrlGen := NewControllerGenerator(rootDir, pc.ModulePath, group, gens.templates.Overrides)
+		ctrlGen.ControllerGroupDir = filepath.Join(rootDir, gens.layout.controllersRoot, strings.Split(group, ".")[0])
+		ctrlGen.RootPackagePath = filepath.Join(pc.ModulePath, gens.layout.controllersRoot)
+		ctrlGen.Template = gens.templates.Controller
+		var ctrlTestsGen *ControllerTestsGenerator
+		if pc.ControllerTests != nil {
+			ctrlTestsGen = NewControllerTestsGenerator(rootDir, pc.ModulePath, *pc.ControllerTests, gens.templates.Overrides)
+		}
+		// typeNames is the mapping of the type names to be written if
+		// the type names are pinned.
+		var typeNames, pinnedTypeNames tjtypes.TypeNameMapping
+		if pc.PinTypeNames {
+			var err error
+			if pinnedTypeNames, err = crdGen.PinTypeNames(); err != nil {
+				return nil, errors.Wrapf(err, "cannot pin the type names of group %s version %s", group, version)
+			}
+			typeNames = tjtypes.TypeNameMapping{}
+		}
+
+		for _, name := range sortedResources(resources) {
+			for _, h := range gens.hooks {
+				if err := h.BeforeResource(resources[name], group, version); err != nil {
+					return nil, errors.Wrapf(err, "cannot run the hook before resource %s", name)
+				}
+			}
+			key := generationCacheKey(group, version, name)
+			files := []string{crdGen.FilePath(resources[name])}
+			if resources[name].Version == version {
+				files = append(files, ctrlGen.FilePath(resources[name]))
+				if ctrlTestsGen != nil && hasControllerTest(resources[name]) {
+					files = append(files, ctrlTestsGen.FilePath(ctrlGen.FilePath(resources[name])))
+				}
+			}
+			if gens.docs != nil {
+				files = append(files, gens.docs.FilePath(resources[name], group, version))
+			}
+			var cached *cachedResource
+			skip := false
+			switch {
+			case !gens.selection.selected(name):
+				if cached, skip = gens.cache.preserve(key, files...); skip {
+					out.preserved++
+				}
+			case gens.incremental:
+				// the pinned type names of the resource are also inputs of
+				// its generation.
+				if cached, skip = gens.cache.lookup(key, contentHash(gens.hashes[key], pinnedTypeNames[name]), files...); skip {
+					out.skipped++
+				}
+			}
+			if skip {
+				out.kept = append(out.kept, files...)
+				// the outputs of the skipped generation are restored.
+				for tfPath, xpPath := range cached.SensitiveFieldPaths {
+					resources[name].Sensitive.AddFieldPath(tfPath, xpPath)
+				}
+				for _, f := range cached.IgnoredCanonicalFields {
+					resources[name].LateInitializer.AddIgnoredCanonicalFields(f)
+				}
+				if gens.docs != nil {
+					gens.docs.AddToIndex(resources[name], group, version)
+				}
+			} else {
+				var err error
+				if cached, err = generateResource(crdGen, gens.docs, resources[name], group, version); err != nil {
+					return nil, errors.Wrapf(err, "cannot generate crd for resource %s", name)
+				}
+				cached.Hash = contentHash(gens.hashes[key], pinnedTypeNames[name])
+				gens.cache.store(key, cached)
+			}
+			a := ResourceArtifacts{
+				Resource: resources[name],
+				Group:    group,
+				Version:  version,
+				Skipped:  skip,
+				CRDTypes: crdGen.FilePath(resources[name]),
+			}
+			if resources[name].Version == version {
+				a.Controller = ctrlGen.FilePath(resources[name])
+				if m := resources[name].MetaResource; m != nil && len(m.Examples) > 0 && resources[name].MovedTo == nil {
+					a.Example = gens.examples.ManifestPath(group, resources[name])
+				}
+			}
+			if gens.docs != nil {
+				a.Docs = gens.docs.FilePath(resources[name], group, version)
+			}
+			artifacts = append(artifacts, a)
+			paramTypeName := cached.ParametersTypeName
+			if n := cached.TypeNames; typeNames != nil && len(n) > 0 {
+				typeNames[name] = n
+				if c := typeNameChanges(pinnedTypeNames[name], n); c != "" {
+					fmt.Printf("Changed the pinned type names of resource %s in version %s, which breaks the API compatibility: %s\n", name, version, c)
+				}
+			}
+			tfResources = append(tfResources, &terraformedInput{
+				Resource:               resources[name],
+				ParametersTypeName:     paramTypeName,
+				InitParametersTypeName: cached.InitParametersTypeName,
+			})
+			// Controllers and examples are only generated for the storage
+			// versions, which are the conversion hubs.
+			if resources[name].Version != version {
+				spokes = append(spokes, resources[name])
+				continue
+			}
+			if len(resources[name].ServedVersions) > 0 {
+				hubs = append(hubs, resources[name])
+			}
+
+			if resources[name].MovedTo != nil {
+				// the previous Kinds of the moved resources are only
+				// served to be moved to their current Kinds.
+				ctrlPkgPath := ctrlGen.PackagePath(resources[name])
+				if !skip {
+					if _, err := ctrlGen.GenerateMover(resources[name], versionGen.Package().Path(), movedGroup(resources[name], group)); err != nil {
+						return nil, errors.Wrapf(err, "cannot generate mover controller for resource %s", name)
+					}
+				}
+				sGroup := strings.Split(group, ".")[0]
+				out.controllerPkgs[sGroup] = append(out.controllerPkgs[sGroup], ctrlPkgPath)
+				out.controllerPkgs[config.PackageNameMonolith] = append(out.controllerPkgs[config.PackageNameMonolith], ctrlPkgPath)
+				continue
+			}
+
+			featuresPkgPath := ""
+			if pc.FeaturesPackage != "" {
+				featuresPkgPath = filepath.Join(pc.ModulePath, pc.FeaturesPackage)
+			}
+			ctrlPkgPath := ctrlGen.PackagePath(resources[name])
+			if !skip {
+				if _, err := ctrlGen.Generate(resources[name], versionGen.Package().Path(), featuresPkgPath); err != nil {
+					return nil, errors.Wrapf(err, "cannot generate controller for resource %s", name)
+				}
+				if ctrlTestsGen != nil && hasControllerTest(resources[name]) {
+					if err := ctrlTestsGen.Generate(resources[name], ctrlPkgPath, ctrlGen.FilePath(resources[name]), versionGen.Package().Path(), gens.examples.ManifestPath(group, resources[name])); err != nil {
+						return nil, errors.Wrapf(err, "cannot generate controller test for resource %s", name)
+					}
+				}
+			}
+			sGroup := strings.Split(group, ".")[0]
+			out.controllerPkgs[sGroup] = append(out.controllerPkgs[sGroup], ctrlPkgPath)
+			out.controllerPkgs[config.PackageNameMonolith] = append(out.controllerPkgs[config.PackageNameMonolith], ctrlPkgPath)
+			if err := gens.examples.Generate(group, version, resources[name]); err != nil {
+				return nil, errors.Wrapf(err, "cannot generate example manifest for resource %s", name)
+			}
+			withExamples = append(withExamples, resources[name])
+			out.manifestResources = append(out.manifestResources, NewManifestResource(resources[name], group, version, cached.CompositionFieldPaths))
+			if !reflect.DeepEqual(resources[name].CompositionHints, config.CompositionHints{}) {
+				out.hasCompositionHints = true
+			}
+			out.count++
+		}
+
+		if typeNames != nil {
+			if err := crdGen.WriteTypeNames(typeNames); err != nil {
+				return nil, errors.Wrapf(err, "cannot write the type names of group %s version %s", group, version)
+			}
+		}
+
+		if err := tfGen.Generate(tfResources, version); err != nil {
+			return nil, errors.Wrapf(err, "cannot generate terraformed for resource %s", group)
+		}
+
+		cfgs := make([]*config.Resource, 0, len(resources))
+		for _, name := range sortedResources(resources) {
+			cfgs = append(cfgs, resources[name])
+		}
+		condGen := NewConditionsGenerator(versionGen.Package(), rootDir, group, version, gens.templates.Overrides)
+		condGen.LocalDirectoryPath = versionGen.DirectoryPath
+		if err := condGen.Generate(cfgs); err != nil {
+			return nil, errors.Wrapf(err, "cannot generate conditions for group %s version %s", group, version)
+		}
+
+		convGen := NewConversionGenerator(versionGen.Package(), rootDir, pc.ModulePath, group, gens.templates.Overrides)
+		convGen.LocalDirectoryPath = versionGen.DirectoryPath
+		convGen.GroupPackagePath = filepath.Dir(versionGen.Package().Path())
+		if err := convGen.GenerateHubs(hubs); err != nil {
+			return nil, errors.Wrapf(err, "cannot generate conversion hubs for group %s", group)
+		}
+		if err := convGen.GenerateSpokes(spokes); err != nil {
+			return nil, errors.Wrapf(err, "cannot generate conversion spokes for group %s", group)
+		}
+
+		if pc.GenerateTerraformedTests {
+			testsGen := NewTerraformedTestsGenerator(versionGen.Package(), rootDir, group, version, gens.templates.Overrides)
+			testsGen.LocalDirectoryPath = versionGen.DirectoryPath
+			if err := testsGen.Generate(withExamples); err != nil {
+				return nil, errors.Wrapf(err, "cannot generate terraformed tests for group %s version %s", group, version)
+			}
+		}
+
+		if err := versionGen.Generate(); err != nil {
+			return nil, errors.Wrap(err, "cannot generate version files")
+		}
+		out.apiVersionPkgs = append(out.apiVersionPkgs, versionGen.Package().Path())
+
+		if pc.GenerateTypedClients {
+			if err := NewClientGenerator(rootDir, pc.ModulePath, group, version, gens.templates.Overrides).Generate(cfgs, versionGen.Package().Path()); err != nil {
+				return nil, errors.Wrapf(err, "cannot generate typed clients for group %s version %s", group, version)
+			}
+		}
+
+		if pc.GenerateApplyConfigurations {
+			if err := NewApplyConfigurationGenerator(rootDir, pc.ModulePath, group, version, gens.templates.Overrides).Generate(tfResources, versionGen.Package().Path()); err != nil {
+				return nil, errors.Wrapf(err, "cannot generate apply configurations for group %s version %s", group, version)
+			}
+		}
+
+		for _, a := range artifacts {
+			for _, h := range gens.hooks {
+				if err := h.AfterResource(a); err != nil {
+					return nil, errors.Wrapf(err, "cannot run the hook after resource %s", a.Resource.Name)
+				}
+			}
+		}
+		out.artifacts = append(out.artifacts, artifacts...)
+	}
+	return out, nil
+}
+
+// generateResource generates the CRD types and, if the given docs generator
+// is not nil, the API reference of the given resource in the given group and
+// version, and returns the outputs of its generation to be cached.
+func generateResource(crdGen *CRDGenerator, docsGen *DocsGenerator, r *config.Resource, group, version string) (*cachedResource, error) {
+	paramTypeName, err := crdGen.Generate(r)
+	if err != nil {
+		return nil, err
+	}
+	if docsGen != nil {
+		if err := docsGen.Generate(r, group, version, crdGen.Generated); err != nil {
+			return nil, errors.Wrap(err, "cannot generate the API reference")
+		}
+	}
+	if paths := crdGen.Generated.CollapsedPaths; len(paths) > 0 {
+		fmt.Printf("Collapsed the blocks of resource %s nested deeper than %d levels into runtime.RawExtension fields: %s\n", r.Name, r.MaxBlockNestingDepth, strings.Join(paths, ", "))
+	}
+	if paths := crdGen.Generated.CollapsedObservationPaths; len(paths) > 0 {
+		fmt.Printf("Collapsed the computed-only blocks of resource %s into runtime.RawExtension fields: %s\n", r.Name, strings.Join(paths, ", "))
+	}
+	if f := crdGen.Generated.UnmatchedIgnoredFields; len(f) > 0 {
+		fmt.Printf("The late-initialization ignored fields of resource %s do not match any field in the schema and can be removed: %s\n", r.Name, strings.Join(f, ", "))
+	}
+	if c := crdGen.Generated.ResolvedTypeNameCollisions; len(c) > 0 {
+		fmt.Printf("Resolved the type name collisions of resource %s, which can be pinned with OverrideFieldNames: %s\n", r.Name, typeNameCollisions(c))
+	}
+	if t := crdGen.Generated.TruncatedTypeNames; len(t) > 0 {
+		fmt.Printf("Truncated the type names of resource %s longer than %d characters, which can be pinned with OverrideFieldNames: %s\n", r.Name, crdGen.MaxTypeNameLength, typeNameCollisions(t))
+	}
+	return &cachedResource{
+		ParametersTypeName:     paramTypeName,
+		InitParametersTypeName: crdGen.Generated.InitProviderType.Obj().Name(),
+		CompositionFieldPaths:  crdGen.Generated.CompositionFieldPaths,
+		TypeNames:              crdGen.Generated.TypeNames,
+		SensitiveFieldPaths:    r.Sensitive.GetFieldPaths(),
+		IgnoredCanonicalFields: r.LateInitializer.GetIgnoredCanonicalFields(),
+	}, nil
+}
+
+// typeNameCollisions returns the given resolved type name collisions in
+// the form of "path: name" sorted by the Terraform paths.
+func typeNameCollisions(c map[string]string) string {
+	l := make([]string, 0, len(c))
+	for p, n := range c {
+		l = append(l, p+": "+n)
+	}
+	sort.Strings(l)
+	return strings.Join(l, ", ")
+}
+
+// typeNameChanges returns the report of the changes of the given pinned type
+// names of the blocks to the given current ones. The blocks which are added
+// or removed are not reported.
+func typeNameChanges(pinned, current map[string]string) string {
+	var l []string
+	for p, n := range current {
+		if pn, ok := pinned[p]; ok && pn != n {
+			l = append(l, fmt.Sprintf("%s: %s -> %s", p, pn, n))
+		}
+	}
+	sort.Strings(l)
+	return strings.Join(l, ", ")
+}
+
+func sortedVersions(m map[string]map[string]*config.Resource) []string {
+	result := make([]string, 0, len(m))
+	for v := range m {
+		result = append(result, v)
+	}
+	sort.Strings(result)
+	return result
+}
+
 func sortedResources(m map[string]*config.Resource) []string {
 	result := make([]string, len(m))
 	i := 0
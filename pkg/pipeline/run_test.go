@@ -0,0 +1,46 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+func TestGroupBatches(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		groups map[string]map[string]map[string]*config.Resource
+		want   [][]string
+	}{
+		"Independent": {
+			reason: "The groups of different Terraform resources should be in separate batches.",
+			groups: map[string]map[string]map[string]*config.Resource{
+				"ec2.aws.upbound.io": {"v1beta1": {"aws_vpc": {Name: "aws_vpc"}}},
+				"s3.aws.upbound.io":  {"v1beta1": {"aws_s3_bucket": {Name: "aws_s3_bucket"}}},
+			},
+			want: [][]string{{"ec2.aws.upbound.io"}, {"s3.aws.upbound.io"}},
+		},
+		"SharedTerraformResource": {
+			reason: "The groups of the resources configured with the same Terraform resource should be in the same batch.",
+			groups: map[string]map[string]map[string]*config.Resource{
+				"ec2.aws.upbound.io": {"v1beta1": {"aws_vpc": {Name: "aws_vpc"}}},
+				"vpc.aws.upbound.io": {"v1beta1": {"aws_vpc/vpc/VPC": {Name: "aws_vpc"}}},
+				"s3.aws.upbound.io":  {"v1beta1": {"aws_s3_bucket": {Name: "aws_s3_bucket"}}},
+			},
+			want: [][]string{{"ec2.aws.upbound.io", "vpc.aws.upbound.io"}, {"s3.aws.upbound.io"}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, groupBatches(tc.groups)); diff != "" {
+				t.Errorf("\n%s\ngroupBatches(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// resourceSelection is the selection of the resources generated in a
+// partial run by their Terraform resource names.
+type resourceSelection struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// newResourceSelection returns the selection of the resources matching any
+// of the given include patterns, or all the resources if none given, and
+// none of the given exclude patterns.
+func newResourceSelection(include, exclude []string) (*resourceSelection, error) {
+	s := &resourceSelection{}
+	var err error
+	if s.include, err = compilePatterns(include); err != nil {
+		return nil, err
+	}
+	if s.exclude, err = compilePatterns(exclude); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot compile the resource pattern %q", p)
+		}
+		res[i] = re
+	}
+	return res, nil
+}
+
+// selected returns true if the resource with the given Terraform resource
+// name is selected. All the resources are selected by a nil selection.
+func (s *resourceSelection) selected(name string) bool {
+	if s == nil {
+		return true
+	}
+	included := len(s.include) == 0
+	for _, re := range s.include {
+		if re.MatchString(name) {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+	for _, re := range s.exclude {
+		if re.MatchString(name) {
+			return false
+		}
+	}
+	return true
+}
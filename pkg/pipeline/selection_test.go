@@ -0,0 +1,68 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResourceSelection(t *testing.T) {
+	names := []string{"aws_s3_bucket", "aws_s3_bucket_policy", "aws_vpc"}
+	cases := map[string]struct {
+		reason  string
+		include []string
+		exclude []string
+		want    []string
+		wantErr bool
+	}{
+		"All": {
+			reason: "All the resources should be selected without any patterns.",
+			want:   names,
+		},
+		"Include": {
+			reason:  "Only the resources matching any of the include patterns should be selected.",
+			include: []string{"aws_s3_.*"},
+			want:    []string{"aws_s3_bucket", "aws_s3_bucket_policy"},
+		},
+		"Exclude": {
+			reason:  "The resources matching any of the exclude patterns should not be selected.",
+			exclude: []string{"_policy$"},
+			want:    []string{"aws_s3_bucket", "aws_vpc"},
+		},
+		"IncludeAndExclude": {
+			reason:  "The included resources matching any of the exclude patterns should not be selected.",
+			include: []string{"aws_s3_.*", "aws_vpc"},
+			exclude: []string{"_policy$"},
+			want:    []string{"aws_s3_bucket", "aws_vpc"},
+		},
+		"InvalidPattern": {
+			reason:  "An error should be returned for an invalid pattern.",
+			include: []string{"aws_s3_("},
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := newResourceSelection(tc.include, tc.exclude)
+			if diff := cmp.Diff(tc.wantErr, err != nil); diff != "" {
+				t.Fatalf("\n%s\nnewResourceSelection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			var got []string
+			for _, n := range names {
+				if s.selected(n) {
+					got = append(got, n)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nselected(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"text/template"
 
 	"github.com/muvaf/typewriter/pkg/wrapper"
@@ -24,8 +25,9 @@ func NewProviderGenerator(rootDir, modulePath string) *ProviderGenerator {
 	return &ProviderGenerator{
 		ProviderPath:       filepath.Join(rootDir, "cmd", "provider"),
 		LocalDirectoryPath: filepath.Join(rootDir, "internal", "controller"),
-		LicenseHeaderPath:  filepath.Join(rootDir, "hack", "boilerplate.go.txt"),
+		LicenseHeaderPath:  licenseHeaderPath(rootDir),
 		ModulePath:         modulePath,
+		Template:           templates.SetupTemplate,
 	}
 }
 
@@ -35,6 +37,13 @@ type ProviderGenerator struct {
 	LocalDirectoryPath string
 	LicenseHeaderPath  string
 	ModulePath         string
+	// Template is the template of the controller setup files.
+	Template string
+	// ShortName is the short name of the provider.
+	ShortName string
+	// FamilyBuildTag is the build tag the setup files of the API groups are
+	// constrained with. See config.Provider.FamilyBuildTag for details.
+	FamilyBuildTag string
 }
 
 // Generate writes the setup file and the corresponding provider main file
@@ -48,22 +57,39 @@ func (sg *ProviderGenerator) Generate(versionPkgMap map[string][]string, mainTem
 		}
 		t = tmpl
 	}
-	if t == nil {
+	if t == nil && sg.FamilyBuildTag == "" {
 		return errors.Wrap(sg.generate("", versionPkgMap[config.PackageNameMonolith]), "failed to generate the controller setup file")
 	}
+	if sg.FamilyBuildTag != "" {
+		// the monolithic setup function calls the setup functions of the
+		// API groups included in the build.
+		if err := sg.generate("", nil); err != nil {
+			return errors.Wrap(err, "failed to generate the controller setup file")
+		}
+	}
 	for g, versionPkgList := range versionPkgMap {
+		if t == nil && g == config.PackageNameMonolith {
+			continue
+		}
 		if err := sg.generate(g, versionPkgList); err != nil {
 			return errors.Wrapf(err, "failed to generate the controller setup file for group: %s", g)
 		}
-		if err := generateProviderMain(sg.ProviderPath, g, t); err != nil {
+		if t == nil {
+			continue
+		}
+		if err := sg.generateProviderMain(g, t); err != nil {
 			return errors.Wrapf(err, "failed to write main program for group: %s", g)
 		}
 	}
 	return nil
 }
 
-func generateProviderMain(providerPath, group string, t *template.Template) error {
-	f := filepath.Join(providerPath, group)
+func (sg *ProviderGenerator) generateProviderMain(group string, t *template.Template) error {
+	f := filepath.Join(sg.ProviderPath, group)
+	header, err := os.ReadFile(filepath.Clean(sg.LicenseHeaderPath))
+	if err != nil {
+		return errors.Wrap(err, "failed to read the license header")
+	}
 	if err := os.MkdirAll(f, 0750); err != nil {
 		return errors.Wrapf(err, "failed to mkdir provider main program path: %s", f)
 	}
@@ -77,7 +103,10 @@ func generateProviderMain(providerPath, group string, t *template.Template) erro
 		}
 	}()
 	if err := t.Execute(m, map[string]any{
-		"Group": group,
+		"Group":      group,
+		"ModulePath": sg.ModulePath,
+		"ShortName":  sg.ShortName,
+		"Header":     strings.TrimRight(string(header), "\n"),
 	}); err != nil {
 		return errors.Wrap(err, "failed to execute provider main program template")
 	}
@@ -85,7 +114,7 @@ func generateProviderMain(providerPath, group string, t *template.Template) erro
 }
 
 func (sg *ProviderGenerator) generate(group string, versionPkgList []string) error {
-	setupFile := wrapper.NewFile(filepath.Join(sg.ModulePath, "apis"), "apis", templates.SetupTemplate,
+	setupFile := wrapper.NewFile(filepath.Join(sg.ModulePath, "apis"), "apis", sg.Template,
 		wrapper.WithGenStatement(GenStatement),
 		wrapper.WithHeaderPath(sg.LicenseHeaderPath),
 	)
@@ -99,8 +128,12 @@ func (sg *ProviderGenerator) generate(group string, versionPkgList []string) err
 		g = "_" + group
 	}
 	vars := map[string]any{
-		"Aliases": aliases,
-		"Group":   g,
+		"PackageName":     filepath.Base(sg.LocalDirectoryPath),
+		"Aliases":         aliases,
+		"Group":           g,
+		"BuildConstraint": sg.buildConstraint(group),
+		"Register":        sg.FamilyBuildTag != "" && group != "" && group != config.PackageNameMonolith,
+		"Registered":      sg.FamilyBuildTag != "" && group == "",
 	}
 	filePath := ""
 	if len(group) == 0 {
@@ -110,3 +143,19 @@ func (sg *ProviderGenerator) generate(group string, versionPkgList []string) err
 	}
 	return errors.Wrap(setupFile.Write(filePath, vars, os.ModePerm), "cannot write setup file")
 }
+
+// buildConstraint returns the build constraint of the setup file of the
+// given API group, which is empty if there's no FamilyBuildTag. The setup
+// files of the API groups are included in the builds without the tag or
+// with the tag of their groups, while the config group is always included
+// and the monolith group is only included in the builds without the tag.
+func (sg *ProviderGenerator) buildConstraint(group string) string {
+	switch {
+	case sg.FamilyBuildTag == "" || group == "" || group == config.PackageNameConfig:
+		return ""
+	case group == config.PackageNameMonolith:
+		return "!" + sg.FamilyBuildTag
+	default:
+		return fmt.Sprintf("!%s || %s_%s", sg.FamilyBuildTag, sg.FamilyBuildTag, group)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBuildConstraint(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		tag    string
+		group  string
+		want   string
+	}{
+		"NoTag": {
+			reason: "The setup files should not be constrained without a family build tag.",
+			group:  "s3",
+		},
+		"Group": {
+			reason: "The setup file of an API group should be included in the builds without the tag or with the tag of the group.",
+			tag:    "family",
+			group:  "s3",
+			want:   "!family || family_s3",
+		},
+		"Config": {
+			reason: "The setup file of the config group should always be included.",
+			tag:    "family",
+			group:  "config",
+		},
+		"Monolith": {
+			reason: "The setup file of the monolith group should only be included in the builds without the tag.",
+			tag:    "family",
+			group:  "monolith",
+			want:   "!family",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sg := &ProviderGenerator{FamilyBuildTag: tc.tag}
+			if diff := cmp.Diff(tc.want, sg.buildConstraint(tc.group)); diff != "" {
+				t.Errorf("\n%s\nbuildConstraint(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
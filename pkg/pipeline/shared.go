@@ -0,0 +1,70 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	twtypes "github.com/muvaf/typewriter/pkg/types"
+	"github.com/muvaf/typewriter/pkg/wrapper"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+	tjtypes "github.com/upbound/upjet/pkg/types"
+)
+
+// NewSharedTypesGenerator returns a new SharedTypesGenerator generating the
+// shared types in the package at the given path relative to the root
+// directory, e.g. "apis/common/v1".
+func NewSharedTypesGenerator(rootDir, modulePath, pkgDir string, overrides fs.FS) *SharedTypesGenerator {
+	return &SharedTypesGenerator{
+		LocalDirectoryPath: filepath.Join(rootDir, pkgDir),
+		LicenseHeaderPath:  licenseHeaderPath(rootDir),
+		pkg:                types.NewPackage(filepath.Join(modulePath, pkgDir), filepath.Base(pkgDir)),
+		overrides:          overrides,
+	}
+}
+
+// SharedTypesGenerator generates the types of the blocks shared by the
+// resources.
+type SharedTypesGenerator struct {
+	LocalDirectoryPath string
+	LicenseHeaderPath  string
+
+	pkg       *types.Package
+	overrides fs.FS
+}
+
+// Generate builds and writes the types of the shared blocks with the given
+// schemas, and returns them to be referenced by the resources.
+func (sg *SharedTypesGenerator) Generate(blocks map[string]*schema.Resource) (map[string]*tjtypes.SharedBlock, error) {
+	tmpl, err := templates.Resolve(sg.overrides, "shared_types.go.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	file := wrapper.NewFile(sg.pkg.Path(), sg.pkg.Name(), tmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(sg.LicenseHeaderPath),
+	)
+	gen, err := tjtypes.NewBuilder(sg.pkg).BuildSharedBlocks(blocks)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot build the shared types")
+	}
+	// See the note in CRDGenerator.Generate about the scope of the printer.
+	pkg := types.NewPackage(sg.pkg.Path(), sg.pkg.Name())
+	typesStr, err := twtypes.NewPrinter(file.Imports, pkg.Scope(), twtypes.WithComments(gen.Comments)).Print(gen.Types)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot print the shared types")
+	}
+	vars := map[string]any{
+		"Package": sg.pkg.Name(),
+		"Types":   typesStr,
+	}
+	return gen.SharedBlocks, errors.Wrap(file.Write(filepath.Join(sg.LocalDirectoryPath, "zz_shared_types.go"), vars, os.ModePerm), "cannot write the shared types file")
+}
@@ -36,3 +36,111 @@ var RegisterTemplate string
 //
 //go:embed setup.go.tmpl
 var SetupTemplate string
+
+// InferredReferencesTemplate is populated with the inferred cross-resource
+// references.
+//
+//go:embed inferred_references.go.tmpl
+var InferredReferencesTemplate string
+
+// ConversionHubTemplate is populated with the hub markers of the CRDs served
+// in multiple versions.
+//
+//go:embed conversion_hub.go.tmpl
+var ConversionHubTemplate string
+
+// ConversionSpokeTemplate is populated with the conversion functions of the
+// CRDs served in multiple versions.
+//
+//go:embed conversion_spoke.go.tmpl
+var ConversionSpokeTemplate string
+
+// MovedControllerTemplate is populated with the setup functions of the
+// controllers moving the managed resources of the previous Kinds.
+//
+//go:embed moved_controller.go.tmpl
+var MovedControllerTemplate string
+
+// SharedTypesTemplate is populated with the types of the blocks shared by
+// the resources.
+//
+//go:embed shared_types.go.tmpl
+var SharedTypesTemplate string
+
+// DocsTemplate is populated with the Markdown API reference of a managed
+// resource.
+//
+//go:embed docs.md.tmpl
+var DocsTemplate string
+
+// DocsIndexTemplate is populated with the index of the Markdown API
+// references of the managed resources.
+//
+//go:embed docs_index.md.tmpl
+var DocsIndexTemplate string
+
+// ExternalNameTestTemplate is populated with the round-trip tests of the
+// external name configurations.
+//
+//go:embed external_name_test.go.tmpl
+var ExternalNameTestTemplate string
+
+// ClientTemplate is populated with the typed clients, listers and informers
+// of the managed resources in an API version.
+//
+//go:embed client.go.tmpl
+var ClientTemplate string
+
+// TerraformedTestTemplate is populated with the golden tests of the
+// Terraform conversions of the managed resources in an API version.
+//
+//go:embed terraformed_test.go.tmpl
+var TerraformedTestTemplate string
+
+// ControllerTestTemplate is populated with the integration test of the
+// controller of a managed resource.
+//
+//go:embed controller_test.go.tmpl
+var ControllerTestTemplate string
+
+// ResourceRegistryTemplate is populated with the identities of the managed
+// resources of the provider.
+//
+//go:embed resource_registry.go.tmpl
+var ResourceRegistryTemplate string
+
+// ConditionsTemplate is populated with the custom status conditions of the
+// managed resources in an API version.
+//
+//go:embed conditions.go.tmpl
+var ConditionsTemplate string
+
+// MainTemplate is populated with the main program of a sub-provider serving
+// the managed resources of an API group.
+//
+//go:embed main.go.tmpl
+var MainTemplate string
+
+// ProviderConfigTypesTemplate is populated with the ProviderConfig and
+// ProviderConfigUsage types.
+//
+//go:embed providerconfig_types.go.tmpl
+var ProviderConfigTypesTemplate string
+
+// ProviderConfigControllerTemplate is populated with the setup function of
+// the ProviderConfig controller.
+//
+//go:embed providerconfig_controller.go.tmpl
+var ProviderConfigControllerTemplate string
+
+// CredentialsTemplate is populated with the extraction of the credentials
+// of a ProviderConfig into the Terraform provider configuration.
+//
+//go:embed credentials.go.tmpl
+var CredentialsTemplate string
+
+// ApplyConfigurationTemplate is populated with the apply configurations of
+// the managed resources in an API version.
+//
+//go:embed applyconfiguration.go.tmpl
+var ApplyConfigurationTemplate string
@@ -0,0 +1,52 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package templates
+
+import (
+	"embed"
+	"io/fs"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// builtin is the file system of the built-in templates.
+//
+//go:embed *.tmpl
+var builtin embed.FS
+
+// Names returns the sorted names of the built-in templates.
+func Names() []string {
+	entries, err := builtin.ReadDir(".")
+	if err != nil {
+		// the embedded directory can always be read.
+		panic(err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve returns the template with the given name in the given file system
+// overlaying the built-in templates, if it's not nil and has one, or the
+// built-in template with the given name. It's meant to be called when the
+// template is rendered, so that the overrides are read only if they're
+// used.
+func Resolve(overrides fs.FS, name string) (string, error) {
+	if overrides != nil {
+		b, err := fs.ReadFile(overrides, name)
+		switch {
+		case err == nil:
+			return string(b), nil
+		case !errors.Is(err, fs.ErrNotExist):
+			return "", errors.Wrapf(err, "cannot read the overriding template %s", name)
+		}
+	}
+	b, err := builtin.ReadFile(name)
+	return string(b), errors.Wrapf(err, "cannot read the built-in template %s", name)
+}
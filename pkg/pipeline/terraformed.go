@@ -8,6 +8,7 @@ import (
 	"go/types"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/muvaf/typewriter/pkg/wrapper"
@@ -20,7 +21,8 @@ import (
 func NewTerraformedGenerator(pkg *types.Package, rootDir, group, version string) *TerraformedGenerator {
 	return &TerraformedGenerator{
 		LocalDirectoryPath: filepath.Join(rootDir, "apis", strings.ToLower(strings.Split(group, ".")[0]), version),
-		LicenseHeaderPath:  filepath.Join(rootDir, "hack", "boilerplate.go.txt"),
+		LicenseHeaderPath:  licenseHeaderPath(rootDir),
+		Template:           templates.TerraformedTemplate,
 		pkg:                pkg,
 	}
 }
@@ -30,13 +32,15 @@ func NewTerraformedGenerator(pkg *types.Package, rootDir, group, version string)
 type TerraformedGenerator struct {
 	LocalDirectoryPath string
 	LicenseHeaderPath  string
+	// Template is the template of the Terraformed methods files.
+	Template string
 
 	pkg *types.Package
 }
 
 // Generate writes generated Terraformed interface functions
 func (tg *TerraformedGenerator) Generate(cfgs []*terraformedInput, apiVersion string) error {
-	trFile := wrapper.NewFile(tg.pkg.Path(), tg.pkg.Name(), templates.TerraformedTemplate,
+	trFile := wrapper.NewFile(tg.pkg.Path(), tg.pkg.Name(), tg.Template,
 		wrapper.WithGenStatement(GenStatement),
 		wrapper.WithHeaderPath(tg.LicenseHeaderPath),
 	)
@@ -47,6 +51,11 @@ func (tg *TerraformedGenerator) Generate(cfgs []*terraformedInput, apiVersion st
 	resources := make([]map[string]any, len(cfgs))
 	index := 0
 	for _, cfg := range cfgs {
+		singletonLists := cfg.EmbeddedSingletonLists(apiVersion)
+		// the ignored fields are sorted as they're accumulated in the order
+		// of the generations of the resource.
+		ignoredFields := append([]string(nil), cfg.LateInitializer.GetIgnoredCanonicalFields()...)
+		sort.Strings(ignoredFields)
 		resources[index] = map[string]any{
 			"CRD": map[string]string{
 				"Kind":               cfg.Kind,
@@ -54,14 +63,18 @@ func (tg *TerraformedGenerator) Generate(cfgs []*terraformedInput, apiVersion st
 			},
 			"Terraform": map[string]any{
 				"ResourceType":  cfg.Name,
+				"Key":           cfg.Key(),
 				"SchemaVersion": cfg.TerraformResource.SchemaVersion,
 			},
 			"Sensitive": map[string]any{
 				"Fields": cfg.Sensitive.GetFieldPaths(),
 			},
 			"LateInitializer": map[string]any{
-				"IgnoredFields": cfg.LateInitializer.GetIgnoredCanonicalFields(),
+				"IgnoredFields": ignoredFields,
 			},
+			"TerraformConversions": len(cfg.TerraformConversions) > 0 || len(singletonLists) > 0,
+			"SingletonLists":       singletonLists,
+			"ProviderOverrides":    len(cfg.ProviderConfigOverrides) > 0,
 		}
 		index++
 	}
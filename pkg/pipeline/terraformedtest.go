@@ -0,0 +1,93 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"fmt"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/muvaf/typewriter/pkg/wrapper"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/pipeline/templates"
+)
+
+const resourcePackagePath = "github.com/upbound/upjet/pkg/resource"
+
+// NewTerraformedTestsGenerator returns a new TerraformedTestsGenerator.
+func NewTerraformedTestsGenerator(pkg *types.Package, rootDir, group, version string, overrides fs.FS) *TerraformedTestsGenerator {
+	groupPrefix := strings.ToLower(strings.Split(group, ".")[0])
+	return &TerraformedTestsGenerator{
+		LocalDirectoryPath: filepath.Join(rootDir, "apis", groupPrefix, version),
+		LicenseHeaderPath:  licenseHeaderPath(rootDir),
+		examplesDir:        filepath.Join(rootDir, "examples-generated"),
+		groupPrefix:        groupPrefix,
+		pkg:                pkg,
+		overrides:          overrides,
+	}
+}
+
+// TerraformedTestsGenerator generates the golden tests of the Terraform
+// conversions of the managed resources in an API version, which round-trip
+// their example manifests through the Terraformed methods.
+type TerraformedTestsGenerator struct {
+	LocalDirectoryPath string
+	LicenseHeaderPath  string
+
+	examplesDir string
+	groupPrefix string
+	pkg         *types.Package
+	overrides   fs.FS
+}
+
+// Generate writes the Terraformed tests file with the test cases of the
+// given resources whose examples are generated.
+func (tg *TerraformedTestsGenerator) Generate(cfgs []*config.Resource) error {
+	var resources []map[string]string
+	for _, cfg := range cfgs {
+		if cfg.MetaResource == nil || len(cfg.MetaResource.Examples) == 0 {
+			continue
+		}
+		resources = append(resources, map[string]string{
+			"Kind":        cfg.Kind,
+			"ExamplePath": tg.examplePath(cfg),
+		})
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+	tmpl, err := templates.Resolve(tg.overrides, "terraformed_test.go.tmpl")
+	if err != nil {
+		return err
+	}
+	testFile := wrapper.NewFile(tg.pkg.Path(), tg.pkg.Name(), tmpl,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(tg.LicenseHeaderPath),
+	)
+	vars := map[string]any{
+		"APIVersion":           tg.pkg.Name(),
+		"ResourcePackageAlias": testFile.Imports.UsePackage(resourcePackagePath),
+		"Resources":            resources,
+	}
+	return errors.Wrap(
+		testFile.Write(filepath.Join(tg.LocalDirectoryPath, "zz_generated_terraformed_test.go"), vars, os.ModePerm),
+		"cannot write the Terraformed tests file",
+	)
+}
+
+// examplePath returns the path of the example manifest of the given resource
+// relative to the API version package.
+func (tg *TerraformedTestsGenerator) examplePath(cfg *config.Resource) string {
+	p := filepath.Join(tg.examplesDir, tg.groupPrefix, fmt.Sprintf("%s.yaml", strings.ToLower(cfg.Kind)))
+	if rel, err := filepath.Rel(tg.LocalDirectoryPath, p); err == nil {
+		p = rel
+	}
+	return filepath.ToSlash(p)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/registry"
+)
+
+func TestTerraformedTestsGenerate(t *testing.T) {
+	withExample := func(kind string) *config.Resource {
+		return &config.Resource{
+			Kind: kind,
+			MetaResource: &registry.Resource{
+				Examples: []registry.ResourceExample{{Name: "example"}},
+			},
+		}
+	}
+	type want struct {
+		generated bool
+		cases     []string
+	}
+	cases := map[string]struct {
+		reason string
+		cfgs   []*config.Resource
+		want   want
+	}{
+		"NoExamples": {
+			reason: "The tests file should not be generated if none of the resources has examples.",
+			cfgs:   []*config.Resource{{Kind: "VPC"}},
+		},
+		"Examples": {
+			reason: "The resources with examples should be tested with their example manifests.",
+			cfgs:   []*config.Resource{withExample("Subnet"), {Kind: "Route"}, withExample("VPC")},
+			want: want{
+				generated: true,
+				cases: []string{
+					`"../../../examples-generated/ec2/subnet.yaml"`,
+					`"../../../examples-generated/ec2/vpc.yaml"`,
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(rootDir, "hack"), 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(rootDir, "hack", "boilerplate.go.txt"), []byte("/*\nCopyright 2023 Upbound Inc.\n*/"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			pkg := types.NewPackage("github.com/upbound/provider-aws/apis/ec2/v1beta1", "v1beta1")
+			tg := NewTerraformedTestsGenerator(pkg, rootDir, "ec2.aws.upbound.io", "v1beta1", nil)
+			if err := os.MkdirAll(tg.LocalDirectoryPath, 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := tg.Generate(tc.cfgs); err != nil {
+				t.Fatal(err)
+			}
+			f := filepath.Join(tg.LocalDirectoryPath, "zz_generated_terraformed_test.go")
+			_, err := os.Stat(f)
+			if diff := cmp.Diff(tc.want.generated, err == nil); diff != "" {
+				t.Fatalf("\n%s\nGenerate(...): -want generated, +got generated:\n%s", tc.reason, diff)
+			}
+			if !tc.want.generated {
+				return
+			}
+			file, err := parser.ParseFile(token.NewFileSet(), f, nil, 0)
+			if err != nil {
+				t.Fatalf("\n%s\nGenerate(...): cannot parse the generated file: %v", tc.reason, err)
+			}
+			var got []string
+			for _, l := range strings.Split(string(mustReadFile(t, f)), "\n") {
+				if l = strings.TrimSpace(l); strings.HasPrefix(l, "example:") {
+					got = append(got, strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(l, "example:")), ","))
+				}
+			}
+			if diff := cmp.Diff(tc.want.cases, got); diff != "" {
+				t.Errorf("\n%s\nGenerate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff("v1beta1", file.Name.Name); diff != "" {
+				t.Errorf("\n%s\nGenerate(...): -want package, +got package:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func mustReadFile(t *testing.T, path string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
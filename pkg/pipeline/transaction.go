@@ -0,0 +1,340 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+const (
+	// stagingDir is the directory, relative to the root directory, in which
+	// the outputs of the generation runs are staged.
+	stagingDir = ".work/upjet"
+
+	examplesRoot = "examples-generated"
+)
+
+// stagedTime is the modification time of the files copied to the staging
+// directory, which distinguishes them from the files written by the
+// generators.
+var stagedTime = time.Unix(0, 0)
+
+// transaction stages the outputs of a generation run in a copy of the
+// output paths of the root directory, which are committed to the root
+// directory only if the generation succeeds, so that a failed generation
+// doesn't leave a half-written tree mixing the previous and the new files.
+type transaction struct {
+	rootDir string
+	// dir is the staging directory, i.e. the root directory of the
+	// generators.
+	dir string
+	// paths are the output paths relative to the root directory, none of
+	// which is in another one.
+	paths []string
+	// staged is the set of the files copied to the staging directory
+	// relative to it.
+	staged map[string]struct{}
+}
+
+// newTransaction returns a new transaction of the given output paths,
+// relative to the given root directory, which are copied to the staging
+// directory.
+func newTransaction(rootDir string, paths []string) (*transaction, error) {
+	rootDir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get the absolute path of the root directory")
+	}
+	if err := os.MkdirAll(filepath.Join(rootDir, stagingDir), 0750); err != nil {
+		return nil, errors.Wrap(err, "cannot create the staging directory")
+	}
+	dir, err := os.MkdirTemp(filepath.Join(rootDir, stagingDir), "staging-")
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create the staging directory")
+	}
+	t := &transaction{
+		rootDir: rootDir,
+		dir:     dir,
+		paths:   outermostPaths(paths),
+		staged:  map[string]struct{}{},
+	}
+	for _, p := range t.paths {
+		if err := t.stage(p); err != nil {
+			t.discard()
+			return nil, errors.Wrapf(err, "cannot stage %s", p)
+		}
+	}
+	return t, nil
+}
+
+// outermostPaths returns the cleaned given paths, which are not in any of
+// the other given paths, sorted.
+func outermostPaths(paths []string) []string {
+	cleaned := make([]string, 0, len(paths))
+	for _, p := range paths {
+		cleaned = append(cleaned, filepath.Clean(p))
+	}
+	sort.Strings(cleaned)
+	var result []string
+	for _, p := range cleaned {
+		if len(result) > 0 {
+			last := result[len(result)-1]
+			if p == last || strings.HasPrefix(p, last+string(filepath.Separator)) {
+				continue
+			}
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// stage copies the regular files in the given path of the root directory to
+// the staging directory.
+func (t *transaction) stage(path string) error {
+	return filepath.WalkDir(filepath.Join(t.rootDir, path), func(p string, d fs.DirEntry, err error) error {
+		switch {
+		case os.IsNotExist(err):
+			return nil
+		case err != nil:
+			return err
+		case !d.Type().IsRegular():
+			return nil
+		}
+		rel, err := filepath.Rel(t.rootDir, p)
+		if err != nil {
+			return err
+		}
+		if err := copyFile(p, filepath.Join(t.dir, rel)); err != nil {
+			return err
+		}
+		t.staged[rel] = struct{}{}
+		return nil
+	})
+}
+
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+		return err
+	}
+	in, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return err
+	}
+	defer in.Close() // nolint:errcheck
+	out, err := os.OpenFile(filepath.Clean(dst), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, stagedTime, stagedTime)
+}
+
+// removeStale removes the stale generated files in the staging directory,
+// i.e. the files generated by upjet or in the generated examples directory
+// that are not written by this generation, e.g. the files of the resources
+// removed from the configuration, except for the given kept files, e.g. the
+// files of the resources whose generations are skipped.
+func (t *transaction) removeStale(kept map[string]struct{}) error {
+	for _, p := range t.paths {
+		err := filepath.WalkDir(filepath.Join(t.dir, p), func(path string, d fs.DirEntry, err error) error {
+			switch {
+			case os.IsNotExist(err):
+				return nil
+			case err != nil:
+				return err
+			case !d.Type().IsRegular():
+				return nil
+			}
+			if _, ok := kept[path]; ok {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			// the file is written by this generation.
+			if !info.ModTime().Equal(stagedTime) {
+				return nil
+			}
+			generated, err := t.isGenerated(path)
+			if err != nil || !generated {
+				return err
+			}
+			return os.Remove(path)
+		})
+		if err != nil {
+			return errors.Wrapf(err, "cannot remove the stale files in %s", p)
+		}
+	}
+	return nil
+}
+
+// isGenerated returns true if the given file in the staging directory is
+// generated by upjet.
+func (t *transaction) isGenerated(path string) (bool, error) {
+	rel, err := filepath.Rel(t.dir, path)
+	if err != nil {
+		return false, err
+	}
+	if strings.HasPrefix(rel, examplesRoot+string(filepath.Separator)) {
+		return true, nil
+	}
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return false, err
+	}
+	return bytes.Contains(b, []byte(strings.TrimPrefix(GenStatement, "// "))), nil
+}
+
+// commit moves the changed and the new files in the staging directory to
+// the root directory after syncing them to the disk, removes the files
+// removed from the staging directory from the root directory with the
+// directories they leave empty, and removes the staging directory. The
+// unchanged files aren't touched.
+func (t *transaction) commit() error {
+	dirs := map[string]struct{}{}
+	current := map[string]struct{}{}
+	for _, p := range t.paths {
+		err := filepath.WalkDir(filepath.Join(t.dir, p), func(path string, d fs.DirEntry, err error) error {
+			switch {
+			case os.IsNotExist(err):
+				return nil
+			case err != nil:
+				return err
+			case !d.Type().IsRegular():
+				return nil
+			}
+			rel, err := filepath.Rel(t.dir, path)
+			if err != nil {
+				return err
+			}
+			current[rel] = struct{}{}
+			dst := filepath.Join(t.rootDir, rel)
+			if changed, err := isChanged(path, dst); err != nil || !changed {
+				return err
+			}
+			if err := syncFile(path); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+				return err
+			}
+			dirs[filepath.Dir(dst)] = struct{}{}
+			return os.Rename(path, dst)
+		})
+		if err != nil {
+			return errors.Wrapf(err, "cannot commit %s", p)
+		}
+	}
+	for rel := range t.staged {
+		if _, ok := current[rel]; ok {
+			continue
+		}
+		dst := filepath.Join(t.rootDir, rel)
+		if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "cannot remove %s", rel)
+		}
+		t.removeEmptyDirs(filepath.Dir(dst))
+	}
+	for d := range dirs {
+		// the renames are persisted by syncing the directories, which is
+		// not supported on all the platforms.
+		_ = syncFile(d)
+	}
+	return errors.Wrap(os.RemoveAll(t.dir), "cannot remove the staging directory")
+}
+
+// removeEmptyDirs removes the given directory and its parents in the root
+// directory while they're empty.
+func (t *transaction) removeEmptyDirs(dir string) {
+	for dir != t.rootDir && strings.HasPrefix(dir, t.rootDir) {
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// discard removes the staging directory without committing it.
+func (t *transaction) discard() {
+	_ = os.RemoveAll(t.dir)
+}
+
+func isChanged(src, dst string) (bool, error) {
+	s, err := os.ReadFile(filepath.Clean(src))
+	if err != nil {
+		return false, err
+	}
+	d, err := os.ReadFile(filepath.Clean(dst))
+	switch {
+	case os.IsNotExist(err):
+		return true, nil
+	case err != nil:
+		return false, err
+	}
+	return !bytes.Equal(s, d), nil
+}
+
+func syncFile(path string) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// outputPaths returns the paths, relative to the root directory, of the
+// outputs of a generation run of the given provider with the given options,
+// which are staged by its transaction. The inputs of the generators in the
+// root directory, e.g. the license header and the generation cache, are
+// also staged.
+func outputPaths(pc *config.Provider, o *runOptions) []string {
+	paths := []string{
+		licenseHeaderDir,
+		generationCacheFile,
+		o.apisRoot,
+		o.controllersRoot,
+		providerConfigAPIRoot,
+		providerConfigControllerRoot,
+		credentialsRoot,
+		clientRoot,
+		applyConfigurationRoot,
+		examplesRoot,
+		docsRoot,
+		"config",
+		filepath.Join("cmd", "provider"),
+	}
+	if pc.SharedTypesPackage != "" {
+		paths = append(paths, pc.SharedTypesPackage)
+	}
+	if o.coverage != "" {
+		paths = append(paths, o.coverage)
+	}
+	return paths
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package pipeline
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const generatedContent = "// Code generated by upjet. DO NOT EDIT.\n\npackage v1beta1\n"
+
+func TestTransaction(t *testing.T) {
+	type generation func(t *testing.T, dir string)
+	write := func(rel, content string) generation {
+		return func(t *testing.T, dir string) {
+			p := filepath.Join(dir, rel)
+			if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	tree := map[string]string{
+		"hack/boilerplate.go.txt":                              "/*\nCopyright 2023 Upbound Inc.\n*/",
+		"apis/s3/v1beta1/zz_bucket_types.go":                   generatedContent,
+		"apis/s3/v1beta1/zz_object_types.go":                   generatedContent,
+		"apis/s3/v1beta1/custom.go":                            "package v1beta1\n",
+		"apis/sqs/v1beta1/zz_queue_types.go":                   generatedContent,
+		"internal/controller/sqs/queue/zz_controller.go":       generatedContent,
+		"examples-generated/sqs/queue.yaml":                    "kind: Queue\n",
+		"outside/zz_other.go":                                  generatedContent,
+		"internal/controller/s3/bucket/zz_controller.go":       generatedContent,
+		"internal/controller/s3/object/zz_controller.go":       generatedContent,
+		"internal/controller/s3/object/zz_controller_test.go":  generatedContent,
+		"internal/controller/zz_setup.go":                      generatedContent,
+		"internal/controller/providerconfig/config.go":         "package providerconfig\n",
+		"internal/controller/providerconfig/zz_unchanged.go":   generatedContent,
+		"internal/controller/providerconfig/zz_regenerated.go": generatedContent,
+	}
+	paths := []string{"hack", "apis", "apis/s3", "internal/controller", "examples-generated"}
+	cases := map[string]struct {
+		reason   string
+		generate []generation
+		// kept are the files of the skipped resources.
+		kept []string
+		fail bool
+		want map[string]string
+	}{
+		"Commit": {
+			reason: "The changed and the new files should be committed, and the stale generated files should be removed with their empty directories while the other files are kept.",
+			generate: []generation{
+				write("apis/s3/v1beta1/zz_bucket_types.go", generatedContent+"// changed\n"),
+				write("apis/s3/v1beta1/zz_acl_types.go", generatedContent),
+				write("internal/controller/providerconfig/zz_regenerated.go", generatedContent),
+				write("internal/controller/zz_setup.go", generatedContent),
+			},
+			kept: []string{"internal/controller/s3/bucket/zz_controller.go", "internal/controller/providerconfig/zz_unchanged.go"},
+			want: map[string]string{
+				"hack/boilerplate.go.txt":                              "/*\nCopyright 2023 Upbound Inc.\n*/",
+				"apis/s3/v1beta1/zz_bucket_types.go":                   generatedContent + "// changed\n",
+				"apis/s3/v1beta1/zz_acl_types.go":                      generatedContent,
+				"apis/s3/v1beta1/custom.go":                            "package v1beta1\n",
+				"outside/zz_other.go":                                  generatedContent,
+				"internal/controller/s3/bucket/zz_controller.go":       generatedContent,
+				"internal/controller/zz_setup.go":                      generatedContent,
+				"internal/controller/providerconfig/config.go":         "package providerconfig\n",
+				"internal/controller/providerconfig/zz_unchanged.go":   generatedContent,
+				"internal/controller/providerconfig/zz_regenerated.go": generatedContent,
+			},
+		},
+		"Discard": {
+			reason: "The root directory should be left intact if the generation fails.",
+			generate: []generation{
+				write("apis/s3/v1beta1/zz_bucket_types.go", "// half-written"),
+				write("apis/s3/v1beta1/zz_acl_types.go", generatedContent),
+			},
+			fail: true,
+			want: tree,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			for rel, content := range tree {
+				write(rel, content)(t, rootDir)
+			}
+			tx, err := newTransaction(rootDir, paths)
+			if err != nil {
+				t.Fatalf("newTransaction(...): %v", err)
+			}
+			for _, g := range tc.generate {
+				g(t, tx.dir)
+			}
+			if tc.fail {
+				tx.discard()
+			} else {
+				kept := map[string]struct{}{}
+				for _, f := range tc.kept {
+					kept[filepath.Join(tx.dir, f)] = struct{}{}
+				}
+				if err := tx.removeStale(kept); err != nil {
+					t.Fatalf("\n%s\nremoveStale(...): %v", tc.reason, err)
+				}
+				if err := tx.commit(); err != nil {
+					t.Fatalf("\n%s\ncommit(...): %v", tc.reason, err)
+				}
+			}
+			if diff := cmp.Diff(tc.want, readTree(t, rootDir)); diff != "" {
+				t.Errorf("\n%s\n-want tree, +got tree:\n%s", tc.reason, diff)
+			}
+			for _, d := range []string{"internal/controller/s3/object", "examples-generated"} {
+				if _, err := os.Stat(filepath.Join(rootDir, d)); !tc.fail && !os.IsNotExist(err) {
+					t.Errorf("\n%s\nthe empty directory %s is not removed", tc.reason, d)
+				}
+			}
+		})
+	}
+}
+
+// readTree returns the contents of the files in the given directory, except
+// for the staging directory, keyed by their relative paths.
+func readTree(t *testing.T, dir string) map[string]string {
+	tree := map[string]string{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if rel == ".work" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		b, err := os.ReadFile(filepath.Clean(p))
+		if err != nil {
+			return err
+		}
+		tree[filepath.ToSlash(rel)] = string(b)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
+func TestOutermostPaths(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		paths  []string
+		want   []string
+	}{
+		"Nested": {
+			reason: "The paths in the other paths should be removed.",
+			paths:  []string{"apis/v1beta1", "internal/clients", "apis", "./apis", "apisv2", "internal/controller"},
+			want:   []string{"apis", "apisv2", "internal/clients", "internal/controller"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, outermostPaths(tc.paths)); diff != "" {
+				t.Errorf("\n%s\noutermostPaths(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
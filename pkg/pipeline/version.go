@@ -6,6 +6,7 @@ package pipeline
 
 import (
 	"go/types"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,14 +18,22 @@ import (
 )
 
 // NewVersionGenerator returns a new VersionGenerator.
-func NewVersionGenerator(rootDir, modulePath, group, version string) *VersionGenerator {
-	pkgPath := filepath.Join(modulePath, "apis", strings.ToLower(strings.Split(group, ".")[0]), version)
+func NewVersionGenerator(rootDir, modulePath, group, version string, overrides fs.FS) *VersionGenerator {
+	return newVersionGenerator(rootDir, modulePath, apiRoot, group, version, overrides)
+}
+
+// newVersionGenerator returns a new VersionGenerator of the version package
+// under the given root of the API packages, which is relative to the root
+// directory and the module path.
+func newVersionGenerator(rootDir, modulePath, apisRoot, group, version string, overrides fs.FS) *VersionGenerator {
+	pkgPath := filepath.Join(modulePath, apisRoot, strings.ToLower(strings.Split(group, ".")[0]), version)
 	return &VersionGenerator{
 		Group:             group,
 		Version:           version,
-		DirectoryPath:     filepath.Join(rootDir, "apis", strings.ToLower(strings.Split(group, ".")[0]), version),
-		LicenseHeaderPath: filepath.Join(rootDir, "hack", "boilerplate.go.txt"),
+		DirectoryPath:     filepath.Join(rootDir, apisRoot, strings.ToLower(strings.Split(group, ".")[0]), version),
+		LicenseHeaderPath: licenseHeaderPath(rootDir),
 		pkg:               types.NewPackage(pkgPath, version),
+		overrides:         overrides,
 	}
 }
 
@@ -35,7 +44,8 @@ type VersionGenerator struct {
 	DirectoryPath     string
 	LicenseHeaderPath string
 
-	pkg *types.Package
+	pkg       *types.Package
+	overrides fs.FS
 }
 
 // Generate writes doc and group version info files to the disk.
@@ -46,7 +56,11 @@ func (vg *VersionGenerator) Generate() error {
 			"Group":   vg.Group,
 		},
 	}
-	gviFile := wrapper.NewFile(vg.pkg.Path(), vg.Version, templates.GroupVersionInfoTemplate,
+	tmpl, err := templates.Resolve(vg.overrides, "groupversion_info.go.tmpl")
+	if err != nil {
+		return err
+	}
+	gviFile := wrapper.NewFile(vg.pkg.Path(), vg.Version, tmpl,
 		wrapper.WithGenStatement(GenStatement),
 		wrapper.WithHeaderPath(vg.LicenseHeaderPath),
 	)
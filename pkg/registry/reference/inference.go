@@ -0,0 +1,155 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package reference
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/types"
+)
+
+var (
+	// reRefField matches the names of the fields which are candidates for
+	// cross-resource references, e.g. "vpc_id", "subnet_ids" or "role_arn",
+	// and captures the name of the referenced resource and the referenced
+	// attribute.
+	reRefField = regexp.MustCompile(`^(.+)_(id|arn)s?$`)
+	// reResourceName matches the Terraform resource names mentioned in the
+	// scraped argument documentation.
+	reResourceName = regexp.MustCompile(`[a-z0-9]+(?:_[a-z0-9]+)+`)
+)
+
+// Inferrer proposes cross-resource references by matching the names of the
+// fields like "vpc_id" or "role_arn" to the Terraform resources of the
+// provider, e.g. "aws_vpc" or "aws_iam_role", using the scraped argument
+// documentation to resolve the ambiguous matches.
+type Inferrer struct {
+	// TerraformResourcePrefix is the prefix of the Terraform resource names
+	// of the provider, e.g. "aws_".
+	TerraformResourcePrefix string
+}
+
+// NewInferrer initializes a new Inferrer
+func NewInferrer(tfResourcePrefix string) *Inferrer {
+	return &Inferrer{
+		TerraformResourcePrefix: tfResourcePrefix,
+	}
+}
+
+// InferReferences returns the inferred cross-resource references keyed by
+// the Terraform resource names. The fields which already have a configured
+// reference are skipped. The inferred references are proposals to be
+// reviewed and are not configured on the given resources.
+func (i *Inferrer) InferReferences(configResources map[string]*config.Resource) map[string]config.References {
+	inferred := make(map[string]config.References)
+	for n, r := range configResources {
+		if r.TerraformResource == nil {
+			continue
+		}
+		refs := config.References{}
+		i.inferReferences(configResources, r, r.TerraformResource.Schema, "", refs)
+		if len(refs) > 0 {
+			inferred[n] = refs
+		}
+	}
+	return inferred
+}
+
+func (i *Inferrer) inferReferences(configResources map[string]*config.Resource, r *config.Resource, s map[string]*schema.Schema, prefix string, refs config.References) {
+	for fn, sch := range s {
+		fieldPath := prefix + fn
+		if res, ok := sch.Elem.(*schema.Resource); ok {
+			i.inferReferences(configResources, r, res.Schema, fieldPath+".", refs)
+			continue
+		}
+		if _, ok := r.References[fieldPath]; ok || types.IsObservation(sch) || !isStringOrStringList(sch) {
+			continue
+		}
+		m := reRefField.FindStringSubmatch(fn)
+		if m == nil {
+			continue
+		}
+		target := i.matchResource(configResources, m[1], argumentDoc(r, fieldPath))
+		if target == "" {
+			continue
+		}
+		extractor := getExtractorFuncPath(configResources[target], m[2])
+		if extractor == "" {
+			continue
+		}
+		refs[fieldPath] = config.Reference{
+			TerraformName: target,
+			Extractor:     extractor,
+		}
+	}
+}
+
+// matchResource returns the name of the Terraform resource referenced by a
+// field, or an empty string if there is no unambiguous match. The resource
+// named after the field, e.g. "aws_vpc" for "vpc_id", is preferred.
+// Otherwise, the resources with names ending with the field's name, e.g.
+// "aws_ec2_transit_gateway" for "transit_gateway_id", are the candidates.
+// If there are multiple candidates or no candidates at all, the resources
+// mentioned in the field's documentation are used to resolve the match.
+func (i *Inferrer) matchResource(configResources map[string]*config.Resource, name, doc string) string {
+	if _, ok := configResources[i.TerraformResourcePrefix+name]; ok {
+		return i.TerraformResourcePrefix + name
+	}
+	var candidates []string
+	for n := range configResources {
+		if strings.HasSuffix(n, "_"+name) {
+			candidates = append(candidates, n)
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	mentioned := map[string]struct{}{}
+	for _, n := range reResourceName.FindAllString(doc, -1) {
+		if _, ok := configResources[n]; ok {
+			mentioned[n] = struct{}{}
+		}
+	}
+	if len(candidates) > 1 {
+		var filtered []string
+		for _, c := range candidates {
+			if _, ok := mentioned[c]; ok {
+				filtered = append(filtered, c)
+			}
+		}
+		candidates = filtered
+	} else {
+		for n := range mentioned {
+			candidates = append(candidates, n)
+		}
+	}
+	if len(candidates) != 1 {
+		return ""
+	}
+	return candidates[0]
+}
+
+func argumentDoc(r *config.Resource, fieldPath string) string {
+	if r.MetaResource == nil {
+		return ""
+	}
+	return r.MetaResource.ArgumentDocs[fieldPath]
+}
+
+func isStringOrStringList(sch *schema.Schema) bool {
+	switch sch.Type {
+	case schema.TypeString:
+		return true
+	case schema.TypeList, schema.TypeSet:
+		es, ok := sch.Elem.(*schema.Schema)
+		return ok && es.Type == schema.TypeString
+	case schema.TypeBool, schema.TypeFloat, schema.TypeInt, schema.TypeMap, schema.TypeInvalid:
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package reference
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/registry"
+)
+
+func newResource(name string, s map[string]*schema.Schema, argDocs map[string]string) *config.Resource {
+	return config.DefaultResource(name, &schema.Resource{Schema: s}, &registry.Resource{ArgumentDocs: argDocs})
+}
+
+func TestInferReferences(t *testing.T) {
+	str := &schema.Schema{Type: schema.TypeString, Optional: true}
+	computed := &schema.Schema{Type: schema.TypeString, Computed: true}
+	cases := map[string]struct {
+		reason    string
+		resources map[string]*config.Resource
+		want      map[string]config.References
+	}{
+		"ExactMatch": {
+			reason: "A field should reference the resource named after it.",
+			resources: map[string]*config.Resource{
+				"aws_vpc":        newResource("aws_vpc", map[string]*schema.Schema{"arn": computed}, nil),
+				"aws_vpc_peer":   newResource("aws_vpc_peer", map[string]*schema.Schema{"arn": computed}, nil),
+				"aws_iam_role":   newResource("aws_iam_role", map[string]*schema.Schema{"arn": computed}, nil),
+				"aws_subnet":     newResource("aws_subnet", map[string]*schema.Schema{"vpc_id": str, "role_arn": str, "owner_id": computed}, nil),
+				"aws_subnet_set": newResource("aws_subnet_set", map[string]*schema.Schema{"subnet_ids": {Type: schema.TypeList, Optional: true, Elem: str}}, nil),
+			},
+			want: map[string]config.References{
+				"aws_subnet": {
+					"vpc_id": {
+						TerraformName: "aws_vpc",
+						Extractor:     extractResourceIDFuncPath,
+					},
+					"role_arn": {
+						TerraformName: "aws_iam_role",
+						Extractor:     `github.com/upbound/upjet/pkg/resource.ExtractParamPath("arn",true)`,
+					},
+				},
+				"aws_subnet_set": {
+					"subnet_ids": {
+						TerraformName: "aws_subnet",
+						Extractor:     extractResourceIDFuncPath,
+					},
+				},
+			},
+		},
+		"AmbiguousMatchResolvedByDocs": {
+			reason: "An ambiguous match should be resolved using the resources mentioned in the documentation.",
+			resources: map[string]*config.Resource{
+				"aws_ec2_gateway": newResource("aws_ec2_gateway", nil, nil),
+				"aws_lb_gateway":  newResource("aws_lb_gateway", nil, nil),
+				"aws_route": newResource("aws_route", map[string]*schema.Schema{"gateway_id": str, "other_gateway_id": str}, map[string]string{
+					"gateway_id": "Identifier of an aws_lb_gateway.",
+				}),
+			},
+			want: map[string]config.References{
+				"aws_route": {
+					"gateway_id": {
+						TerraformName: "aws_lb_gateway",
+						Extractor:     extractResourceIDFuncPath,
+					},
+				},
+			},
+		},
+		"ConfiguredReference": {
+			reason: "A field with a configured reference should be skipped.",
+			resources: map[string]*config.Resource{
+				"aws_vpc": newResource("aws_vpc", nil, nil),
+				"aws_subnet": func() *config.Resource {
+					r := newResource("aws_subnet", map[string]*schema.Schema{"vpc_id": str}, nil)
+					r.References["vpc_id"] = config.Reference{TerraformName: "aws_vpc"}
+					return r
+				}(),
+			},
+			want: map[string]config.References{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewInferrer("aws_").InferReferences(tc.resources)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nInferReferences(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -25,6 +25,9 @@ const (
 type Injector struct {
 	ModulePath        string
 	ProviderShortName string
+	// ExternalProviders are consulted for the Terraform resources which are
+	// not configured for this provider.
+	ExternalProviders []config.ExternalProvider
 }
 
 // NewInjector initializes a new Injector
@@ -117,6 +120,11 @@ func (rr *Injector) storeResolvedDependencies(re *registry.ResourceExample, cont
 func (rr *Injector) getTypePath(tfName string, configResources map[string]*config.Resource) (string, error) {
 	r := configResources[tfName]
 	if r == nil {
+		for _, ep := range rr.ExternalProviders {
+			if p, ok := ep.TypePath(tfName); ok {
+				return p, nil
+			}
+		}
 		return "", errors.Errorf("cannot find configuration for Terraform resource: %s", tfName)
 	}
 	shortGroup := r.ShortGroup
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package reference
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+)
+
+func TestSetReferenceTypes(t *testing.T) {
+	type want struct {
+		refs config.References
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		refs   config.References
+		want   want
+	}{
+		"LocalResource": {
+			reason: "References to the configured resources should resolve to the types of this provider.",
+			refs: config.References{
+				"network_id": {TerraformName: "example_network"},
+			},
+			want: want{
+				refs: config.References{
+					"network_id": {TerraformName: "example_network", Type: "github.com/upbound/provider-example/apis/example/v1alpha1.Network"},
+				},
+			},
+		},
+		"ExternalResource": {
+			reason: "References to the resources of the external providers should resolve to their types.",
+			refs: config.References{
+				"vpc_id": {TerraformName: "aws_vpc"},
+			},
+			want: want{
+				refs: config.References{
+					"vpc_id": {TerraformName: "aws_vpc", Type: "github.com/upbound/provider-aws/apis/ec2/v1beta1.VPC"},
+				},
+			},
+		},
+		"UnknownResource": {
+			reason: "References to unknown resources should fail.",
+			refs: config.References{
+				"role_id": {TerraformName: "aws_iam_role"},
+			},
+			want: want{
+				refs: config.References{
+					"role_id": {TerraformName: "aws_iam_role"},
+				},
+				err: errors.Wrap(errors.New("cannot find configuration for Terraform resource: aws_iam_role"), "cannot set reference types"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rr := &Injector{
+				ModulePath:        "github.com/upbound/provider-example",
+				ProviderShortName: "example",
+				ExternalProviders: []config.ExternalProvider{
+					{
+						ModulePath: "github.com/upbound/provider-aws",
+						Types: map[string]string{
+							"aws_vpc": "apis/ec2/v1beta1.VPC",
+						},
+					},
+				},
+			}
+			r := config.DefaultResource("example_subnet", nil, nil)
+			r.References = tc.refs
+			resources := map[string]*config.Resource{
+				"example_subnet":  r,
+				"example_network": config.DefaultResource("example_network", nil, nil),
+			}
+			err := rr.SetReferenceTypes(resources)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nSetReferenceTypes(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.refs, r.References); diff != "" {
+				t.Errorf("\n%s\nSetReferenceTypes(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package resource
+
+import (
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/upbound/upjet/pkg/resource/json"
+)
+
+const (
+	errCollapsedValueNotSet = "collapsed value is not set"
+	errDecodeCollapsed      = "cannot decode the collapsed value"
+	errFmtGetCollapsedValue = "cannot get the collapsed value at path %q"
+	collapsedValueFieldPath = "value"
+)
+
+// GetCollapsedValue decodes the value at the given field path, e.g.
+// "[0].endpoint.address", of the given block collapsed into a
+// runtime.RawExtension field into the given object. The field path is
+// relative to the collapsed value and uses the Terraform field names. An
+// empty field path decodes the whole value.
+func GetCollapsedValue(raw *runtime.RawExtension, path string, v any) error {
+	if raw == nil || len(raw.Raw) == 0 {
+		return errors.New(errCollapsedValueNotSet)
+	}
+	var obj any
+	if err := json.JSParser.Unmarshal(raw.Raw, &obj); err != nil {
+		return errors.Wrap(err, errDecodeCollapsed)
+	}
+	p := collapsedValueFieldPath
+	switch {
+	case path == "":
+	case strings.HasPrefix(path, "["):
+		p += path
+	default:
+		p += "." + path
+	}
+	return errors.Wrapf(fieldpath.Pave(map[string]any{collapsedValueFieldPath: obj}).GetValueInto(p, v), errFmtGetCollapsedValue, path)
+}
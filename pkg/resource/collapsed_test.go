@@ -0,0 +1,62 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGetCollapsedValue(t *testing.T) {
+	raw := &runtime.RawExtension{Raw: []byte(`[{"endpoint":{"address":"10.0.0.1","port":443}}]`)}
+	type want struct {
+		v   any
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		raw    *runtime.RawExtension
+		path   string
+		want   want
+	}{
+		"NotSet": {
+			reason: "An error should be returned if the collapsed value is not set.",
+			want: want{
+				err: errors.New(errCollapsedValueNotSet),
+			},
+		},
+		"Nested": {
+			reason: "The nested value at the given path should be decoded.",
+			raw:    raw,
+			path:   "[0].endpoint.address",
+			want: want{
+				v: "10.0.0.1",
+			},
+		},
+		"Whole": {
+			reason: "The whole value should be decoded if the path is empty.",
+			raw:    raw,
+			want: want{
+				v: []any{map[string]any{"endpoint": map[string]any{"address": "10.0.0.1", "port": int64(443)}}},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got any
+			err := GetCollapsedValue(tc.raw, tc.path, &got)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nGetCollapsedValue(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.v, got); diff != "" {
+				t.Errorf("\n%s\nGetCollapsedValue(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
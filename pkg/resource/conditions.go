@@ -18,6 +18,7 @@ import (
 const (
 	TypeLastAsyncOperation = "LastAsyncOperation"
 	TypeAsyncOperation     = "AsyncOperation"
+	TypeDrifted            = "Drifted"
 
 	ReasonApplyFailure     xpv1.ConditionReason = "ApplyFailure"
 	ReasonDestroyFailure   xpv1.ConditionReason = "DestroyFailure"
@@ -25,6 +26,9 @@ const (
 	ReasonOngoing          xpv1.ConditionReason = "Ongoing"
 	ReasonFinished         xpv1.ConditionReason = "Finished"
 	ReasonResourceUpToDate xpv1.ConditionReason = "UpToDate"
+	ReasonDriftReported    xpv1.ConditionReason = "DriftReported"
+	ReasonDriftBlocked     xpv1.ConditionReason = "DriftRemediationBlocked"
+	ReasonInSync           xpv1.ConditionReason = "InSync"
 )
 
 // LastAsyncOperationCondition returns the condition depending on the content
@@ -87,6 +91,41 @@ func AsyncOperationOngoingCondition() xpv1.Condition {
 	}
 }
 
+// DriftReportedCondition returns the condition TypeDrifted if a drift of the
+// external resource is reported but not remediated.
+func DriftReportedCondition() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDrifted,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDriftReported,
+		Message:            "The external resource has drifted from the desired state",
+	}
+}
+
+// DriftBlockedCondition returns the condition TypeDrifted if the remediation
+// of a drift of the external resource is blocked until it is approved.
+func DriftBlockedCondition() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDrifted,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDriftBlocked,
+		Message:            "The external resource has drifted from the desired state. Annotate the resource with " + AnnotationKeyApproveDriftRemediation + "=true to reapply the desired state",
+	}
+}
+
+// InSyncCondition returns the condition TypeDrifted if the external resource
+// is in sync with the desired state.
+func InSyncCondition() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDrifted,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonInSync,
+	}
+}
+
 // UpToDateCondition returns the condition TypeAsyncOperation Ongoing
 // if the operation is still running
 func UpToDateCondition() xpv1.Condition {
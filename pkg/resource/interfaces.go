@@ -38,6 +38,12 @@ type LateInitializer interface {
 	LateInitialize(attrs []byte) (bool, error)
 }
 
+// ProviderOverrider is a Terraformed resource that overrides the arguments of
+// the provider configuration of its ProviderConfig.
+type ProviderOverrider interface {
+	GetProviderOverrides() map[string]string
+}
+
 // Terraformed is a Kubernetes object representing a concrete terraform managed
 // resource.
 type Terraformed interface {
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package json
+
+import (
+	"bytes"
+	"strings"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// TagOptionEmbedJSON is the tf tag option of the fields whose Go types hold
+// the JSON values, e.g., a policy document, whose Terraform representations
+// are the strings containing them. Such strings are decoded into the JSON
+// values they contain while decoding these fields, and the JSON values are
+// encoded into strings while encoding them. The empty strings are decoded as
+// unset values.
+const TagOptionEmbedJSON = "embedjson"
+
+type embedJSONExtension struct {
+	jsoniter.DummyExtension
+	tagKey string
+}
+
+func (e *embedJSONExtension) UpdateStructDescriptor(sd *jsoniter.StructDescriptor) {
+	for _, b := range sd.Fields {
+		for _, o := range strings.Split(b.Field.Tag().Get(e.tagKey), ",")[1:] {
+			if o == TagOptionEmbedJSON {
+				b.Decoder = &embedJSONDecoder{elem: b.Decoder}
+				b.Encoder = &embedJSONEncoder{elem: b.Encoder}
+				break
+			}
+		}
+	}
+}
+
+type embedJSONDecoder struct {
+	elem jsoniter.ValDecoder
+}
+
+func (d *embedJSONDecoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if iter.WhatIsNext() != jsoniter.StringValue {
+		d.elem.Decode(ptr, iter)
+		return
+	}
+	s := iter.ReadString()
+	if s == "" {
+		return
+	}
+	sub := iter.Pool().BorrowIterator([]byte(s))
+	defer iter.Pool().ReturnIterator(sub)
+	d.elem.Decode(ptr, sub)
+	if sub.Error != nil {
+		iter.ReportError("embedjson", sub.Error.Error())
+	}
+}
+
+type embedJSONEncoder struct {
+	elem jsoniter.ValEncoder
+}
+
+func (e *embedJSONEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return e.elem.IsEmpty(ptr)
+}
+
+func (e *embedJSONEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	sub := stream.Pool().BorrowStream(nil)
+	defer stream.Pool().ReturnStream(sub)
+	e.elem.Encode(ptr, sub)
+	if sub.Error != nil {
+		stream.Error = sub.Error
+		return
+	}
+	if raw := sub.Buffer(); bytes.Equal(raw, []byte("null")) {
+		stream.WriteNil()
+	} else {
+		stream.WriteString(string(raw))
+	}
+}
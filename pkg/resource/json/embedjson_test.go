@@ -0,0 +1,69 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package json
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type embedded struct {
+	Name   *string               `tf:"name,omitempty"`
+	Policy *runtime.RawExtension `tf:"policy,omitempty,embedjson"`
+}
+
+func TestTFParserEmbedJSON(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		data   string
+		obj    *embedded
+	}{
+		"EmbeddedJSON": {
+			reason: "The JSON value in a string should be decoded into the field with the embedjson option and encoded back into a string.",
+			data:   `{"name":"test","policy":"{\"Statement\":[{\"Effect\":\"Allow\"}],\"Version\":\"2012-10-17\"}"}`,
+			obj: &embedded{
+				Name:   ptr("test"),
+				Policy: &runtime.RawExtension{Raw: []byte(`{"Statement":[{"Effect":"Allow"}],"Version":"2012-10-17"}`)},
+			},
+		},
+		"Unset": {
+			reason: "An unset field with the embedjson option should be omitted.",
+			data:   `{"name":"test"}`,
+			obj: &embedded{
+				Name: ptr("test"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := &embedded{}
+			if err := TFParser.Unmarshal([]byte(tc.data), got); err != nil {
+				t.Fatalf("\n%s\nUnmarshal(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.obj, got); diff != "" {
+				t.Errorf("\n%s\nUnmarshal(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			data, err := TFParser.Marshal(got)
+			if err != nil {
+				t.Fatalf("\n%s\nMarshal(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.data, string(data)); diff != "" {
+				t.Errorf("\n%s\nMarshal(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTFParserEmbedJSONEmptyString(t *testing.T) {
+	got := &embedded{}
+	if err := TFParser.Unmarshal([]byte(`{"policy": ""}`), got); err != nil {
+		t.Fatalf("Unmarshal(...): unexpected error: %v", err)
+	}
+	if got.Policy != nil {
+		t.Errorf("Unmarshal(...): expected an empty string to be decoded as an unset value, got %s", string(got.Policy.Raw))
+	}
+}
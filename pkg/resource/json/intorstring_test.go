@@ -0,0 +1,53 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package json
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+type intOrString struct {
+	Port *intstr.IntOrString `tf:"port,omitempty"`
+}
+
+func TestTFParserIntOrString(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		data   string
+		want   *intOrString
+	}{
+		"Number": {
+			reason: "A Terraform number should be decoded into an integer and encoded back as a number.",
+			data:   `{"port":8080}`,
+			want:   &intOrString{Port: &intstr.IntOrString{Type: intstr.Int, IntVal: 8080}},
+		},
+		"String": {
+			reason: "A Terraform string should be decoded into a string and encoded back as a string.",
+			data:   `{"port":"${var.port}"}`,
+			want:   &intOrString{Port: &intstr.IntOrString{Type: intstr.String, StrVal: "${var.port}"}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := &intOrString{}
+			if err := TFParser.Unmarshal([]byte(tc.data), got); err != nil {
+				t.Fatalf("\n%s\nUnmarshal(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nUnmarshal(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			raw, err := TFParser.Marshal(got)
+			if err != nil {
+				t.Fatalf("\n%s\nMarshal(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.data, string(raw)); diff != "" {
+				t.Errorf("\n%s\nMarshal(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -6,8 +6,10 @@ package json
 
 import jsoniter "github.com/json-iterator/go"
 
-// TFParser is a json parser to marshal/unmarshal using "tf" tag.
-var TFParser = jsoniter.Config{TagKey: "tf"}.Froze()
+// TFParser is a json parser to marshal/unmarshal using "tf" tag. It
+// honors the TagOptionStringify and the TagOptionEmbedJSON options of the
+// "tf" tags.
+var TFParser = newTFParser()
 
 // JSParser is a json parser to marshal/unmarshal using "json" tag.
 var JSParser = jsoniter.Config{
@@ -15,3 +17,10 @@ var JSParser = jsoniter.Config{
 	// We need to sort the map keys to get consistent output in tests.
 	SortMapKeys: true,
 }.Froze()
+
+func newTFParser() jsoniter.API {
+	p := jsoniter.Config{TagKey: "tf"}.Froze()
+	p.RegisterExtension(&stringifyExtension{tagKey: "tf"})
+	p.RegisterExtension(&embedJSONExtension{tagKey: "tf"})
+	return p
+}
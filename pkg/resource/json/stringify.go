@@ -0,0 +1,84 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package json
+
+import (
+	stdjson "encoding/json"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// TagOptionStringify is the tf tag option of the fields whose Go types
+// expect strings where their Terraform representations may contain numbers
+// or booleans, e.g., a string field overriding a Terraform integer that
+// overflows int64. Such numbers and booleans are converted into strings
+// while decoding these fields. Their string values are passed to Terraform
+// as is while encoding, as Terraform converts them into the types declared
+// in the schema.
+const TagOptionStringify = "stringify"
+
+var numberParser = jsoniter.Config{UseNumber: true}.Froze()
+
+type stringifyExtension struct {
+	jsoniter.DummyExtension
+	tagKey string
+}
+
+func (e *stringifyExtension) UpdateStructDescriptor(sd *jsoniter.StructDescriptor) {
+	for _, b := range sd.Fields {
+		for _, o := range strings.Split(b.Field.Tag().Get(e.tagKey), ",")[1:] {
+			if o == TagOptionStringify {
+				b.Decoder = &stringifyDecoder{elem: b.Decoder}
+				break
+			}
+		}
+	}
+}
+
+type stringifyDecoder struct {
+	elem jsoniter.ValDecoder
+}
+
+func (d *stringifyDecoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	var v any
+	if err := numberParser.Unmarshal(iter.SkipAndReturnBytes(), &v); err != nil {
+		iter.ReportError("stringify", err.Error())
+		return
+	}
+	raw, err := numberParser.Marshal(stringify(v))
+	if err != nil {
+		iter.ReportError("stringify", err.Error())
+		return
+	}
+	sub := iter.Pool().BorrowIterator(raw)
+	defer iter.Pool().ReturnIterator(sub)
+	d.elem.Decode(ptr, sub)
+	if sub.Error != nil {
+		iter.ReportError("stringify", sub.Error.Error())
+	}
+}
+
+// stringify converts the numbers and booleans in the given value decoded
+// from JSON into strings.
+func stringify(v any) any {
+	switch t := v.(type) {
+	case stdjson.Number:
+		return string(t)
+	case bool:
+		return strconv.FormatBool(t)
+	case map[string]any:
+		for k, e := range t {
+			t[k] = stringify(e)
+		}
+	case []any:
+		for i, e := range t {
+			t[i] = stringify(e)
+		}
+	}
+	return v
+}
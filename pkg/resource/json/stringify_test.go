@@ -0,0 +1,77 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package json
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+type stringified struct {
+	Port   *string            `tf:"port,omitempty,stringify"`
+	Tags   map[string]*string `tf:"tags,omitempty,stringify"`
+	Number *int64             `tf:"number,omitempty"`
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestTFParserStringify(t *testing.T) {
+	type want struct {
+		obj *stringified
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		data   string
+		want   want
+	}{
+		"Stringify": {
+			reason: "Numbers and booleans should be decoded into the fields with the stringify option as strings.",
+			data:   `{"port": 18446744073709551615, "tags": {"a": "b", "c": 1.5, "d": true}}`,
+			want: want{
+				obj: &stringified{
+					Port: ptr("18446744073709551615"),
+					Tags: map[string]*string{
+						"a": ptr("b"),
+						"c": ptr("1.5"),
+						"d": ptr("true"),
+					},
+				},
+			},
+		},
+		"Strings": {
+			reason: "Strings should be decoded into the fields with the stringify option as is.",
+			data:   `{"port": "8080"}`,
+			want: want{
+				obj: &stringified{
+					Port: ptr("8080"),
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := &stringified{}
+			err := TFParser.Unmarshal([]byte(tc.data), got)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nUnmarshal(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.obj, got); diff != "" {
+				t.Errorf("\n%s\nUnmarshal(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTFParserStringifyNotSet(t *testing.T) {
+	got := &stringified{}
+	if err := TFParser.Unmarshal([]byte(`{"number": "1"}`), got); err == nil {
+		t.Errorf("Unmarshal(...): expected an error for a string decoded into a field without the stringify option")
+	}
+}
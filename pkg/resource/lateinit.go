@@ -27,6 +27,24 @@ const (
 	// AnnotationKeyTestResource is used for marking an MR as test for automated tests
 	AnnotationKeyTestResource = "upjet.upbound.io/test"
 
+	// AnnotationKeyDriftPolicy overrides the configured drift remediation
+	// policy of an MR.
+	AnnotationKeyDriftPolicy = "upjet.upbound.io/drift-policy"
+
+	// AnnotationKeyApproveDriftRemediation approves the remediation of a
+	// drift blocked by the Block drift policy when set to "true".
+	AnnotationKeyApproveDriftRemediation = "upjet.upbound.io/approve-drift-remediation"
+
+	// AnnotationKeyAllowReplacement allows the changes to the ForceNew
+	// parameters of an MR, which replace its external resource, when set to
+	// "true" and the replacement is guarded by an admission webhook.
+	AnnotationKeyAllowReplacement = "upjet.upbound.io/allow-replacement"
+
+	// AnnotationKeyInSyncGeneration is the generation of an MR whose
+	// external resource has last been observed to be in sync with the desired
+	// state. It's used to distinguish a drift from a desired state change.
+	AnnotationKeyInSyncGeneration = "upjet.upbound.io/in-sync-generation"
+
 	// CNameWildcard can be used as the canonical name of a value filter option
 	// that will apply to all fields of a struct
 	CNameWildcard = ""
@@ -54,7 +72,9 @@ func SetCriticalAnnotations(tr metav1.Object, cfg *config.Resource, tfstate map[
 	if err != nil {
 		return false, errors.Wrap(err, "cannot get external name")
 	}
-	if tr.GetAnnotations()[AnnotationKeyPrivateRawAttribute] == privateRaw &&
+	// the private attributes annotation is recorded even if empty as it
+	// marks the external resource as observed.
+	if pr, ok := tr.GetAnnotations()[AnnotationKeyPrivateRawAttribute]; ok && pr == privateRaw &&
 		tr.GetAnnotations()[xpmeta.AnnotationKeyExternalName] == name {
 		return false, nil
 	}
@@ -368,6 +388,12 @@ func (li *GenericLateInitializer) handleMap(cName string, desiredFieldValue, obs
 		// else if dealing with a slice of slices
 		case reflect.Slice:
 			_, err = li.handleSlice(cName, item.Elem(), observedFieldValue.MapIndex(k))
+		// else if dealing with a map of objects, whose values are not
+		// addressable
+		case reflect.Struct:
+			observedItem := reflect.New(observedFieldValue.Type().Elem())
+			observedItem.Elem().Set(observedFieldValue.MapIndex(k))
+			_, err = li.handleStruct(cName, item.Interface(), observedItem.Interface())
 		case reflect.String, reflect.Bool, reflect.Int, reflect.Uint,
 			reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 			reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
@@ -68,6 +68,10 @@ func TestLateInitialize(t *testing.T) {
 		F2 *nestedStruct1
 	}
 
+	type nestedStruct11 struct {
+		F1 map[string]nestedStruct3
+	}
+
 	tests := map[string]struct {
 		args         args
 		wantModified bool
@@ -455,6 +459,22 @@ func TestLateInitialize(t *testing.T) {
 				},
 			},
 		},
+		"TestUninitializedMapOfStructField": {
+			args: args{
+				desiredObject: &nestedStruct11{},
+				observedObject: &nestedStruct11{
+					F1: map[string]nestedStruct3{
+						testKeyObservedField: {F1: &testStringObservedField},
+					},
+				},
+			},
+			wantModified: true,
+			wantCRObject: &nestedStruct11{
+				F1: map[string]nestedStruct3{
+					testKeyObservedField: {F1: &testStringObservedField},
+				},
+			},
+		},
 		"TestInitializeWithZeroValues": {
 			args: args{
 				desiredObject: &nestedStruct4{},
@@ -0,0 +1,65 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package resource
+
+import (
+	"bytes"
+	"reflect"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// TerraformedRoundTrip decodes the first document of the given manifest,
+// e.g. a generated example manifest, into the given Terraformed resource and
+// returns its Terraform parameters and the Terraform observation of a
+// resource observed with them, under the "parameters" and the "observation"
+// keys. An error is returned if the parameters or the observation aren't
+// converted back to themselves by a new resource of the same type.
+func TerraformedRoundTrip(tr Terraformed, manifest []byte) (map[string]any, error) {
+	if err := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), len(manifest)).Decode(tr); err != nil {
+		return nil, errors.Wrap(err, "cannot decode the manifest")
+	}
+	params, err := tr.GetParameters()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get the Terraform parameters")
+	}
+	got, err := convert(tr, params, Terraformed.SetParameters, Terraformed.GetParameters)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot convert the Terraform parameters")
+	}
+	if diff := cmp.Diff(params, got); diff != "" {
+		return nil, errors.Errorf("Terraform parameters are not converted back to themselves: -want, +got:\n%s", diff)
+	}
+	obs, err := convert(tr, params, Terraformed.SetObservation, Terraformed.GetObservation)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot convert the Terraform observation")
+	}
+	got, err = convert(tr, obs, Terraformed.SetObservation, Terraformed.GetObservation)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot convert the Terraform observation")
+	}
+	if diff := cmp.Diff(obs, got); diff != "" {
+		return nil, errors.Errorf("Terraform observation is not converted back to itself: -want, +got:\n%s", diff)
+	}
+	return map[string]any{
+		"parameters":  params,
+		"observation": obs,
+	}, nil
+}
+
+// convert sets the given Terraform attributes to a new resource of the same
+// type as the given one and returns the attributes got from it.
+func convert(tr Terraformed, attrs map[string]any, set func(Terraformed, map[string]any) error, get func(Terraformed) (map[string]any, error)) (map[string]any, error) {
+	n, ok := reflect.New(reflect.TypeOf(tr).Elem()).Interface().(Terraformed)
+	if !ok {
+		return nil, errors.Errorf("cannot create a new resource of type %T", tr)
+	}
+	if err := set(n, attrs); err != nil {
+		return nil, err
+	}
+	return get(n)
+}
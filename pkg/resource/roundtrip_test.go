@@ -0,0 +1,78 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/upjet/pkg/resource/fake"
+)
+
+// lossyTerraformed drops the Terraform parameters set.
+type lossyTerraformed struct {
+	fake.Terraformed
+}
+
+func (l *lossyTerraformed) SetParameters(_ map[string]any) error {
+	l.Parameters = map[string]any{}
+	return nil
+}
+
+func TestTerraformedRoundTrip(t *testing.T) {
+	type want struct {
+		attrs map[string]any
+		err   bool
+	}
+	manifest := []byte(`Parameters:
+  name: example
+  tags:
+    team: upjet
+---
+Parameters:
+  name: dependency
+`)
+	cases := map[string]struct {
+		reason string
+		tr     Terraformed
+		want   want
+	}{
+		"RoundTrip": {
+			reason: "The parameters of the first document and the observation with them should be returned if they're converted back to themselves.",
+			tr:     &fake.Terraformed{},
+			want: want{
+				attrs: map[string]any{
+					"parameters": map[string]any{
+						"name": "example",
+						"tags": map[string]any{"team": "upjet"},
+					},
+					"observation": map[string]any{
+						"name": "example",
+						"tags": map[string]any{"team": "upjet"},
+					},
+				},
+			},
+		},
+		"Lossy": {
+			reason: "An error should be returned if the parameters aren't converted back to themselves.",
+			tr:     &lossyTerraformed{},
+			want: want{
+				err: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			attrs, err := TerraformedRoundTrip(tc.tr, manifest)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Fatalf("\n%s\nTerraformedRoundTrip(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.want.attrs, attrs); diff != "" {
+				t.Errorf("\n%s\nTerraformedRoundTrip(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
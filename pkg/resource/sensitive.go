@@ -82,6 +82,9 @@ func GetConnectionDetails(attr map[string]any, tr Terraformed, cfg *config.Resou
 		return nil, errors.Wrap(err, "cannot get connection details")
 	}
 
+	if cfg.Sensitive.AdditionalConnectionDetailsFn == nil {
+		return conn, nil
+	}
 	add, err := cfg.Sensitive.AdditionalConnectionDetailsFn(attr)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetAdditionalConnectionDetails)
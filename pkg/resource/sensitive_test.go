@@ -219,6 +219,26 @@ func TestGetConnectionDetails(t *testing.T) {
 				},
 			},
 		},
+		"NoAdditionalConnectionDetailsFn": {
+			args: args{
+				tr: &fake.Terraformed{
+					MetadataProvider: fake.MetadataProvider{
+						ConnectionDetailsMapping: map[string]string{
+							"top_level_secret": "some.field",
+						},
+					},
+				},
+				cfg: &config.Resource{},
+				data: map[string]any{
+					"top_level_secret": "sensitive-data-top-level-secret",
+				},
+			},
+			want: want{
+				out: map[string][]byte{
+					"attribute.top_level_secret": []byte("sensitive-data-top-level-secret"),
+				},
+			},
+		},
 		"AdditionalConnectionDetailsFailed": {
 			args: args{
 				tr: &fake.Terraformed{},
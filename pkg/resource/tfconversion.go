@@ -0,0 +1,79 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package resource
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/config/conversion"
+)
+
+const (
+	errFmtTerraformConversion  = "cannot apply the Terraform conversion at index %d"
+	errSingletonListConversion = "cannot convert the embedded singleton lists"
+)
+
+var (
+	tfConversionsMu sync.RWMutex
+	tfConversions   = map[string][]conversion.TerraformConversion{}
+)
+
+// RegisterTerraformConversions registers the Terraform conversions of the
+// given resource under its key, which are applied by ConvertToTerraform and
+// ConvertFromTerraform. It must be called before the managed resources of
+// the resource are reconciled.
+func RegisterTerraformConversions(r *config.Resource) {
+	tfConversionsMu.Lock()
+	defer tfConversionsMu.Unlock()
+	tfConversions[r.Key()] = r.TerraformConversions
+}
+
+// ConvertToTerraform converts the given Crossplane representation of the
+// parameters or observation of a managed resource whose configuration has
+// the given key, e.g. its Terraform resource type, into the Terraform representation. The embedded objects of
+// the singleton lists at the given Terraform paths are converted into lists
+// before the registered conversions are applied.
+func ConvertToTerraform(key string, params map[string]any, singletonLists ...string) (map[string]any, error) {
+	var err error
+	if len(singletonLists) > 0 {
+		if params, err = conversion.NewSingletonListConversion(singletonLists...).Convert(params, conversion.ToTerraform); err != nil {
+			return nil, errors.Wrap(err, errSingletonListConversion)
+		}
+	}
+	tfConversionsMu.RLock()
+	conversions := tfConversions[key]
+	tfConversionsMu.RUnlock()
+	for i, c := range conversions {
+		if params, err = c.Convert(params, conversion.ToTerraform); err != nil {
+			return nil, errors.Wrapf(err, errFmtTerraformConversion, i)
+		}
+	}
+	return params, nil
+}
+
+// ConvertFromTerraform converts the given Terraform representation of the
+// parameters or state of a managed resource whose configuration has the
+// given key, e.g. its Terraform resource type, into the Crossplane representation. The singleton lists at
+// the given Terraform paths are converted into embedded objects after the
+// registered conversions are applied.
+func ConvertFromTerraform(key string, params map[string]any, singletonLists ...string) (map[string]any, error) {
+	tfConversionsMu.RLock()
+	conversions := tfConversions[key]
+	tfConversionsMu.RUnlock()
+	var err error
+	for i := len(conversions) - 1; i >= 0; i-- {
+		if params, err = conversions[i].Convert(params, conversion.FromTerraform); err != nil {
+			return nil, errors.Wrapf(err, errFmtTerraformConversion, i)
+		}
+	}
+	if len(singletonLists) > 0 {
+		params, err = conversion.NewSingletonListConversion(singletonLists...).Convert(params, conversion.FromTerraform)
+		return params, errors.Wrap(err, errSingletonListConversion)
+	}
+	return params, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/config/conversion"
+)
+
+func TestTerraformConversions(t *testing.T) {
+	errBoom := errors.New("boom")
+	// appendStep records the conversion steps in the "steps" field.
+	appendStep := func(step string) conversion.TerraformConversion {
+		return conversion.TerraformConversionFn(func(params map[string]any, mode conversion.Mode) (map[string]any, error) {
+			dir := "to"
+			if mode == conversion.FromTerraform {
+				dir = "from"
+			}
+			steps, _ := params["steps"].(string)
+			params["steps"] = steps + dir + ":" + step + ";"
+			return params, nil
+		})
+	}
+	type want struct {
+		to   map[string]any
+		from map[string]any
+		err  error
+	}
+	cases := map[string]struct {
+		reason      string
+		conversions []conversion.TerraformConversion
+		want        want
+	}{
+		"NoConversions": {
+			reason: "The params should be left as is if there are no registered conversions.",
+			want: want{
+				to:   map[string]any{},
+				from: map[string]any{},
+			},
+		},
+		"Ordered": {
+			reason: "The conversions should be applied in order into Terraform and in reverse order from Terraform.",
+			conversions: []conversion.TerraformConversion{
+				appendStep("a"), appendStep("b"),
+			},
+			want: want{
+				to:   map[string]any{"steps": "to:a;to:b;"},
+				from: map[string]any{"steps": "from:b;from:a;"},
+			},
+		},
+		"Failed": {
+			reason: "The error returned by a conversion should be reported.",
+			conversions: []conversion.TerraformConversion{
+				conversion.TerraformConversionFn(func(_ map[string]any, _ conversion.Mode) (map[string]any, error) {
+					return nil, errBoom
+				}),
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtTerraformConversion, 0),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			RegisterTerraformConversions(&config.Resource{Name: "test_resource", TerraformConversions: tc.conversions})
+			to, err := ConvertToTerraform("test_resource", map[string]any{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nConvertToTerraform(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			from, err := ConvertFromTerraform("test_resource", map[string]any{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nConvertFromTerraform(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.to, to); diff != "" {
+				t.Errorf("\n%s\nConvertToTerraform(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.from, from); diff != "" {
+				t.Errorf("\n%s\nConvertFromTerraform(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTerraformConversionsWithSingletonLists(t *testing.T) {
+	// recordList records whether the "rule" field is a list while the
+	// registered conversion is applied.
+	recordList := conversion.TerraformConversionFn(func(params map[string]any, _ conversion.Mode) (map[string]any, error) {
+		_, params["list"] = params["rule"].([]any)
+		return params, nil
+	})
+	RegisterTerraformConversions(&config.Resource{Name: "test_resource", TerraformConversions: []conversion.TerraformConversion{recordList}})
+
+	to, err := ConvertToTerraform("test_resource", map[string]any{"rule": map[string]any{"a": "b"}}, "rule")
+	if err != nil {
+		t.Fatalf("ConvertToTerraform(...): unexpected error: %v", err)
+	}
+	want := map[string]any{"rule": []any{map[string]any{"a": "b"}}, "list": true}
+	if diff := cmp.Diff(want, to); diff != "" {
+		t.Errorf("\nThe embedded singleton lists should be converted before the registered conversions into Terraform.\nConvertToTerraform(...): -want, +got:\n%s", diff)
+	}
+
+	from, err := ConvertFromTerraform("test_resource", map[string]any{"rule": []any{map[string]any{"a": "b"}}}, "rule")
+	if err != nil {
+		t.Fatalf("ConvertFromTerraform(...): unexpected error: %v", err)
+	}
+	want = map[string]any{"rule": map[string]any{"a": "b"}, "list": true}
+	if diff := cmp.Diff(want, from); diff != "" {
+		t.Errorf("\nThe singleton lists should be embedded after the registered conversions from Terraform.\nConvertFromTerraform(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+// Package scaffold bootstraps the repositories of the Crossplane providers
+// generated with upjet.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errFmtFileExists      = "file %s already exists"
+	errFmtParseTemplate   = "cannot parse the template %s"
+	errFmtExecuteTemplate = "cannot execute the template %s"
+	errFmtFormat          = "cannot format the file %s"
+	errFmtWrite           = "cannot write the file %s"
+	errFmtInvalidSource   = "invalid Terraform provider source %q: the source must be in the <namespace>/<type> form"
+	errNoName             = "the name of the provider is required"
+	errNoVersion          = "the version of the Terraform provider is required"
+	errReadHeader         = "cannot render the license header"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// files are the files of the repository layout keyed by their paths
+// relative to the root directory, which are rendered from the templates
+// with the same paths under the templates directory, with the ".tmpl"
+// suffix, unless specified.
+var files = []struct {
+	path     string
+	template string
+}{
+	{path: "go.mod"},
+	{path: ".gitignore", template: "gitignore.tmpl"},
+	{path: "Makefile"},
+	{path: "README.md"},
+	{path: "hack/boilerplate.go.txt"},
+	{path: "apis/generate.go"},
+	{path: "apis/v1beta1/doc.go"},
+	{path: "apis/v1beta1/register.go"},
+	{path: "apis/v1beta1/types.go"},
+	{path: "cmd/generator/main.go"},
+	{path: "config/provider.go"},
+	{path: "config/external_name.go"},
+	{path: "config/provider-metadata.yaml"},
+	{path: "internal/clients/%s.go", template: "internal/clients/clients.go.tmpl"},
+	{path: "internal/controller/providerconfig/config.go"},
+}
+
+// Options are the options of the repository of a provider.
+type Options struct {
+	// Name is the name of the provider, e.g. "github", which is also the
+	// prefix of the Terraform resources.
+	Name string
+	// ModulePath is the path of the Go module of the provider. Defaults to
+	// "github.com/upbound/provider-<name>".
+	ModulePath string
+	// Source is the source of the Terraform provider in the Terraform
+	// registry, e.g. "integrations/github".
+	Source string
+	// Version is the version of the Terraform provider, e.g. "5.5.0".
+	Version string
+	// Repo is the Git repository of the Terraform provider, whose
+	// documentation is scraped for the provider metadata. Defaults to
+	// "https://github.com/<namespace>/terraform-provider-<type>".
+	Repo string
+	// DocsPath is the path of the documentation of the Terraform resources
+	// in the repository of the Terraform provider. Defaults to
+	// "website/docs/r".
+	DocsPath string
+	// Overwrite overwrites the existing files of the repository. If false,
+	// no files are written if any of them exists.
+	Overwrite bool
+}
+
+// Init writes the repository layout of a new provider with the given
+// options to the given root directory, i.e. the skeleton of the config
+// package, the generator's main program, the ProviderConfig API and its
+// controller, the Terraform setup and the Makefile targets to generate the
+// schema and scrape the metadata of the Terraform provider. It returns the
+// paths of the written files relative to the root directory.
+func Init(rootDir string, o Options) ([]string, error) {
+	vars, err := variables(o)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+		if strings.Contains(f.path, "%s") {
+			paths[i] = fmt.Sprintf(f.path, o.Name)
+		}
+		if o.Overwrite {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(rootDir, paths[i])); err == nil {
+			return nil, errors.Errorf(errFmtFileExists, paths[i])
+		}
+	}
+	for i, f := range files {
+		name := f.template
+		if name == "" {
+			name = f.path + ".tmpl"
+		}
+		b, err := render(name, vars)
+		if err != nil {
+			return nil, err
+		}
+		if filepath.Ext(paths[i]) == ".go" {
+			if b, err = format.Source(b); err != nil {
+				return nil, errors.Wrapf(err, errFmtFormat, paths[i])
+			}
+		}
+		p := filepath.Join(rootDir, paths[i])
+		if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+			return nil, errors.Wrapf(err, errFmtWrite, paths[i])
+		}
+		if err := os.WriteFile(p, b, 0600); err != nil {
+			return nil, errors.Wrapf(err, errFmtWrite, paths[i])
+		}
+	}
+	return paths, nil
+}
+
+// variables returns the template variables of the given options with their
+// defaults.
+func variables(o Options) (map[string]any, error) {
+	if o.Name == "" {
+		return nil, errors.New(errNoName)
+	}
+	if o.Version == "" {
+		return nil, errors.New(errNoVersion)
+	}
+	ns, typ, ok := strings.Cut(o.Source, "/")
+	if !ok || ns == "" || typ == "" || strings.Contains(typ, "/") {
+		return nil, errors.Errorf(errFmtInvalidSource, o.Source)
+	}
+	if o.ModulePath == "" {
+		o.ModulePath = "github.com/upbound/provider-" + o.Name
+	}
+	if o.Repo == "" {
+		o.Repo = fmt.Sprintf("https://github.com/%s/terraform-provider-%s", ns, typ)
+	}
+	if o.DocsPath == "" {
+		o.DocsPath = "website/docs/r"
+	}
+	vars := map[string]any{
+		"Name":         o.Name,
+		"ProviderName": "provider-" + o.Name,
+		"ModulePath":   o.ModulePath,
+		"Source":       o.Source,
+		"Version":      strings.TrimPrefix(o.Version, "v"),
+		"Repo":         o.Repo,
+		"DocsPath":     o.DocsPath,
+		"Group":        o.Name + ".upbound.io",
+		"Year":         time.Now().Year(),
+	}
+	header, err := render("hack/boilerplate.go.txt.tmpl", vars)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadHeader)
+	}
+	vars["Header"] = strings.TrimSpace(string(header))
+	return vars, nil
+}
+
+// render executes the template with the given name with the given
+// variables.
+func render(name string, vars map[string]any) ([]byte, error) {
+	t, err := template.ParseFS(templates, path.Join("templates", name))
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtParseTemplate, name)
+	}
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, vars); err != nil {
+		return nil, errors.Wrapf(err, errFmtExecuteTemplate, name)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestInit(t *testing.T) {
+	type args struct {
+		existing []string
+		opts     Options
+	}
+	type want struct {
+		// contents are the substrings of the written files keyed by their
+		// paths.
+		contents map[string][]string
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Defaults": {
+			reason: "The repository layout should be written with the defaults of the unspecified options.",
+			args: args{
+				opts: Options{Name: "github", Source: "integrations/github", Version: "v5.5.0"},
+			},
+			want: want{
+				contents: map[string][]string{
+					"go.mod": {"module github.com/upbound/provider-github"},
+					"Makefile": {
+						"export TERRAFORM_PROVIDER_SOURCE ?= integrations/github",
+						"export TERRAFORM_PROVIDER_REPO ?= https://github.com/integrations/terraform-provider-github",
+						"export TERRAFORM_PROVIDER_VERSION ?= 5.5.0",
+						"export TERRAFORM_DOCS_PATH ?= website/docs/r",
+					},
+					"config/provider.go":                           {`resourcePrefix = "github"`, `modulePath     = "github.com/upbound/provider-github"`},
+					"apis/v1beta1/register.go":                     {`Group   = "github.upbound.io"`},
+					"internal/clients/github.go":                   {`"github.com/upbound/provider-github/apis/v1beta1"`},
+					"internal/controller/providerconfig/config.go": {"func Setup(mgr ctrl.Manager, o controller.Options) error"},
+				},
+			},
+		},
+		"Options": {
+			reason: "The repository layout should be written with the given options.",
+			args: args{
+				opts: Options{Name: "github", ModulePath: "github.com/myorg/provider-github", Source: "integrations/github", Version: "5.5.0", Repo: "https://example.org/tf.git", DocsPath: "docs/resources"},
+			},
+			want: want{
+				contents: map[string][]string{
+					"go.mod":                {"module github.com/myorg/provider-github"},
+					"Makefile":              {"export TERRAFORM_PROVIDER_REPO ?= https://example.org/tf.git", "export TERRAFORM_DOCS_PATH ?= docs/resources"},
+					"cmd/generator/main.go": {`"github.com/myorg/provider-github/config"`},
+				},
+			},
+		},
+		"InvalidSource": {
+			reason: "An error should be returned if the source of the Terraform provider is not in the <namespace>/<type> form.",
+			args: args{
+				opts: Options{Name: "github", Source: "github", Version: "5.5.0"},
+			},
+			want: want{
+				err: errors.Errorf(errFmtInvalidSource, "github"),
+			},
+		},
+		"NoName": {
+			reason: "An error should be returned if the name of the provider is not specified.",
+			args: args{
+				opts: Options{Source: "integrations/github", Version: "5.5.0"},
+			},
+			want: want{
+				err: errors.New(errNoName),
+			},
+		},
+		"FileExists": {
+			reason: "An error should be returned without writing any files if a file of the layout exists.",
+			args: args{
+				existing: []string{"Makefile"},
+				opts:     Options{Name: "github", Source: "integrations/github", Version: "5.5.0"},
+			},
+			want: want{
+				contents: map[string][]string{
+					"Makefile": {"existing"},
+				},
+				err: errors.Errorf(errFmtFileExists, "Makefile"),
+			},
+		},
+		"Overwrite": {
+			reason: "The existing files of the layout should be overwritten if configured.",
+			args: args{
+				existing: []string{"Makefile"},
+				opts:     Options{Name: "github", Source: "integrations/github", Version: "5.5.0", Overwrite: true},
+			},
+			want: want{
+				contents: map[string][]string{
+					"Makefile": {"PROJECT_NAME ?= provider-github"},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, p := range tc.args.existing {
+				if err := os.WriteFile(filepath.Join(dir, p), []byte("existing"), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			_, err := Init(dir, tc.args.opts)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nInit(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			for p, substrs := range tc.want.contents {
+				b, err := os.ReadFile(filepath.Join(dir, p))
+				if err != nil {
+					t.Fatalf("\n%s\nInit(...): cannot read %s: %v", tc.reason, p, err)
+				}
+				for _, s := range substrs {
+					if !strings.Contains(string(b), s) {
+						t.Errorf("\n%s\nInit(...): %s does not contain %q:\n%s", tc.reason, p, s, string(b))
+					}
+				}
+			}
+			if tc.want.err != nil {
+				if _, err := os.Stat(filepath.Join(dir, "go.mod")); !os.IsNotExist(err) {
+					t.Errorf("\n%s\nInit(...): no files should be written on error", tc.reason)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+// Package fake contains a fake Terraform CLI, which can be used to run the
+// controllers of the managed resources without a Terraform provider.
+package fake
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/utils/exec"
+
+	"github.com/upbound/upjet/pkg/resource/json"
+)
+
+const (
+	mainFile  = "main.tf.json"
+	stateFile = "terraform.tfstate"
+)
+
+// Executor is a fake Terraform CLI executor, which keeps the external
+// resources of the workspaces it runs the Terraform commands in in memory.
+// A resource is created with the arguments in the main.tf.json file of its
+// workspace and an ID, which is its existing ID in the state or its name, by
+// the "apply" command, its state is refreshed by the "apply -refresh-only"
+// and the "import" commands, it's compared with the arguments by the "plan"
+// command and it's removed by the "destroy" command. The data sources are
+// read with their arguments and their names as their IDs. It's safe for
+// concurrent use.
+type Executor struct {
+	mu        sync.Mutex
+	resources map[string]map[string]any
+}
+
+// NewExecutor returns a new Executor.
+func NewExecutor() *Executor {
+	return &Executor{resources: map[string]map[string]any{}}
+}
+
+// Command returns a fake Terraform command with the given arguments.
+func (e *Executor) Command(cmd string, args ...string) exec.Cmd {
+	return e.CommandContext(context.Background(), cmd, args...)
+}
+
+// CommandContext returns a fake Terraform command with the given arguments.
+func (e *Executor) CommandContext(_ context.Context, _ string, args ...string) exec.Cmd {
+	return &command{executor: e, args: args}
+}
+
+// LookPath returns the given file as is.
+func (e *Executor) LookPath(file string) (string, error) {
+	return file, nil
+}
+
+// Resource returns the attributes of the external resource of the workspace
+// in the given directory, and whether it exists.
+func (e *Executor) Resource(dir string) (map[string]any, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	r, ok := e.resources[filepath.Clean(dir)]
+	return r, ok
+}
+
+// workspace is the main.tf.json file of a workspace.
+type workspace struct {
+	Resource map[string]map[string]map[string]any `json:"resource"`
+	Data     map[string]map[string]map[string]any `json:"data"`
+}
+
+// block returns the mode, the type, the name and the arguments of the
+// resource or the data source of the workspace.
+func (w workspace) block() (mode, typ, name string, args map[string]any, err error) {
+	mode = "managed"
+	blocks := w.Resource
+	if len(w.Data) > 0 {
+		mode, blocks = "data", w.Data
+	}
+	for t, b := range blocks {
+		for n, a := range b {
+			args = make(map[string]any, len(a))
+			for k, v := range a {
+				switch k {
+				case "lifecycle", "timeouts", "provider":
+				default:
+					args[k] = v
+				}
+			}
+			return mode, t, n, args, nil
+		}
+	}
+	return "", "", "", nil, errors.New("no resource in the workspace")
+}
+
+func (e *Executor) run(dir string, args []string) ([]byte, error) { // nolint:gocyclo
+	if len(args) == 0 {
+		return nil, errors.New("no Terraform command")
+	}
+	if args[0] == "init" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, mainFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read the main.tf.json file")
+	}
+	w := workspace{}
+	if err := json.JSParser.Unmarshal(raw, &w); err != nil {
+		return nil, errors.Wrap(err, "cannot unmarshal the main.tf.json file")
+	}
+	mode, typ, name, params, err := w.block()
+	if err != nil {
+		return nil, err
+	}
+	key := filepath.Clean(dir)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	switch {
+	case args[0] == "apply" && contains(args, "-refresh-only"):
+		if mode == "data" {
+			params["id"] = name
+			e.resources[key] = params
+		}
+	case args[0] == "apply":
+		attrs := make(map[string]any, len(params)+1)
+		for k, v := range params {
+			attrs[k] = v
+		}
+		attrs["id"] = name
+		if id, ok := e.stateID(dir); ok {
+			attrs["id"] = id
+		}
+		e.resources[key] = attrs
+	case args[0] == "import":
+		if _, ok := e.resources[key]; !ok {
+			return []byte("Error: Cannot import non-existent remote object"), errors.New("exit status 1")
+		}
+	case args[0] == "plan":
+		add, change := 0, 0
+		attrs, ok := e.resources[key]
+		if !ok {
+			add = 1
+		}
+		for k, v := range params {
+			if ok && !reflect.DeepEqual(attrs[k], v) {
+				change = 1
+			}
+		}
+		return []byte(fmt.Sprintf(`{"type":"change_summary","changes":{"add":%d,"change":%d,"remove":0,"operation":"plan"}}`, add, change)), nil
+	case args[0] == "destroy":
+		delete(e.resources, key)
+	default:
+		return nil, errors.Errorf("unsupported Terraform command %q", strings.Join(args, " "))
+	}
+	return nil, e.writeState(dir, mode, typ, name, e.resources[key])
+}
+
+// stateID returns the ID in the state of the workspace in the given
+// directory, if any.
+func (e *Executor) stateID(dir string) (string, bool) {
+	raw, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if err != nil {
+		return "", false
+	}
+	s := &json.StateV4{}
+	if err := json.JSParser.Unmarshal(raw, s); err != nil || s.GetAttributes() == nil {
+		return "", false
+	}
+	attrs := map[string]any{}
+	if err := json.JSParser.Unmarshal(s.GetAttributes(), &attrs); err != nil {
+		return "", false
+	}
+	id, ok := attrs["id"].(string)
+	return id, ok && id != ""
+}
+
+// writeState writes the state of the workspace in the given directory with
+// the given attributes of its resource, or without any resources if they're
+// nil.
+func (e *Executor) writeState(dir, mode, typ, name string, attrs map[string]any) error {
+	s := json.NewStateV4()
+	if attrs != nil {
+		raw, err := json.JSParser.Marshal(attrs)
+		if err != nil {
+			return errors.Wrap(err, "cannot marshal the attributes")
+		}
+		s.Resources = []json.ResourceStateV4{
+			{
+				Mode:      mode,
+				Type:      typ,
+				Name:      name,
+				Instances: []json.InstanceObjectStateV4{{AttributesRaw: raw}},
+			},
+		}
+	}
+	raw, err := json.JSParser.Marshal(s)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal the state")
+	}
+	return errors.Wrap(os.WriteFile(filepath.Join(dir, stateFile), raw, 0600), "cannot write the state file")
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// command is a fake Terraform command run by an Executor.
+type command struct {
+	executor *Executor
+	args     []string
+	dir      string
+	stdout   io.Writer
+	stderr   io.Writer
+}
+
+func (c *command) Run() error {
+	out, err := c.executor.run(c.dir, c.args)
+	if c.stdout != nil {
+		_, _ = c.stdout.Write(out)
+	}
+	return err
+}
+
+func (c *command) CombinedOutput() ([]byte, error) {
+	return c.executor.run(c.dir, c.args)
+}
+
+func (c *command) Output() ([]byte, error) {
+	return c.executor.run(c.dir, c.args)
+}
+
+func (c *command) SetDir(dir string) {
+	c.dir = dir
+}
+
+func (c *command) SetStdin(_ io.Reader) {}
+
+func (c *command) SetStdout(out io.Writer) {
+	c.stdout = out
+}
+
+func (c *command) SetStderr(out io.Writer) {
+	c.stderr = out
+}
+
+func (c *command) SetEnv(_ []string) {}
+
+func (c *command) StdoutPipe() (io.ReadCloser, error) {
+	out, err := c.executor.run(c.dir, c.args)
+	return io.NopCloser(bytes.NewReader(out)), err
+}
+
+func (c *command) StderrPipe() (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(nil)), nil
+}
+
+func (c *command) Start() error {
+	return nil
+}
+
+func (c *command) Wait() error {
+	return c.Run()
+}
+
+func (c *command) Stop() {}
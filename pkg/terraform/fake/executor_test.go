@@ -0,0 +1,104 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package fake
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/upjet/pkg/resource/json"
+)
+
+const (
+	mainTF = `{"resource":{"aws_vpc":{"example":{"cidr_block":"10.0.0.0/16","lifecycle":{"prevent_destroy":true}}}}}`
+)
+
+func TestExecutor(t *testing.T) {
+	type step struct {
+		args  []string
+		out   string
+		err   bool
+		state map[string]any
+	}
+	cases := map[string]struct {
+		reason string
+		steps  []step
+	}{
+		"Lifecycle": {
+			reason: "The resource should be created, observed and deleted with the arguments of the workspace.",
+			steps: []step{
+				{args: []string{"init"}},
+				{args: []string{"apply", "-refresh-only"}, state: nil},
+				{args: []string{"plan"}, out: `{"type":"change_summary","changes":{"add":1,"change":0,"remove":0,"operation":"plan"}}`},
+				{args: []string{"apply"}, state: map[string]any{"id": "example", "cidr_block": "10.0.0.0/16"}},
+				{args: []string{"apply", "-refresh-only"}, state: map[string]any{"id": "example", "cidr_block": "10.0.0.0/16"}},
+				{args: []string{"plan"}, out: `{"type":"change_summary","changes":{"add":0,"change":0,"remove":0,"operation":"plan"}}`},
+				{args: []string{"destroy"}, state: nil},
+			},
+		},
+		"Import": {
+			reason: "A non-existent resource should not be imported and an existing one should be.",
+			steps: []step{
+				{args: []string{"import"}, out: "Error: Cannot import non-existent remote object", err: true},
+				{args: []string{"apply"}, state: map[string]any{"id": "example", "cidr_block": "10.0.0.0/16"}},
+				{args: []string{"import"}, state: map[string]any{"id": "example", "cidr_block": "10.0.0.0/16"}},
+			},
+		},
+		"UnsupportedCommand": {
+			reason: "An error should be returned for an unsupported command.",
+			steps: []step{
+				{args: []string{"validate"}, err: true},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, mainFile), []byte(mainTF), 0600); err != nil {
+				t.Fatal(err)
+			}
+			e := NewExecutor()
+			for i, s := range tc.steps {
+				cmd := e.Command("terraform", s.args...)
+				cmd.SetDir(dir)
+				out, err := cmd.CombinedOutput()
+				if (err != nil) != s.err {
+					t.Fatalf("\n%s\nstep %d: %v: unexpected error: %v", tc.reason, i, s.args, err)
+				}
+				if diff := cmp.Diff(s.out, string(out)); diff != "" {
+					t.Errorf("\n%s\nstep %d: %v: -want output, +got output:\n%s", tc.reason, i, s.args, diff)
+				}
+				if s.err || s.args[0] == "init" || s.args[0] == "plan" {
+					continue
+				}
+				if diff := cmp.Diff(s.state, readAttributes(t, dir)); diff != "" {
+					t.Errorf("\n%s\nstep %d: %v: -want state, +got state:\n%s", tc.reason, i, s.args, diff)
+				}
+			}
+		})
+	}
+}
+
+func readAttributes(t *testing.T, dir string) map[string]any {
+	raw, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &json.StateV4{}
+	if err := json.JSParser.Unmarshal(raw, s); err != nil {
+		t.Fatal(err)
+	}
+	if s.GetAttributes() == nil {
+		return nil
+	}
+	attrs := map[string]any{}
+	if err := json.JSParser.Unmarshal(s.GetAttributes(), &attrs); err != nil {
+		t.Fatal(err)
+	}
+	return attrs
+}
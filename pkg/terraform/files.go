@@ -9,9 +9,12 @@ import (
 	"fmt"
 	iofs "io/fs"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 
@@ -34,6 +37,10 @@ const (
 	errReadMainTF        = "cannot read main.tf.json file"
 )
 
+// providerOverridesAlias is the alias of the provider block configured with
+// the overridden provider configuration arguments of a resource.
+const providerOverridesAlias = "override"
+
 // FileProducerOption allows you to configure FileProducer
 type FileProducerOption func(*FileProducer)
 
@@ -60,9 +67,23 @@ func NewFileProducer(ctx context.Context, client resource.SecretClient, dir stri
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot get parameters")
 	}
+	if err := checkCollapsedBlocks(cfg, params); err != nil {
+		return nil, errors.Wrap(err, "cannot check the collapsed blocks")
+	}
+	for k, v := range cfg.ParameterDefaults {
+		if _, ok := params[k]; !ok {
+			params[k] = v
+		}
+	}
+	for k, v := range cfg.HiddenParameters {
+		params[k] = v
+	}
 	if err = resource.GetSensitiveParameters(ctx, client, tr, params, tr.GetConnectionDetailsMapping()); err != nil {
 		return nil, errors.Wrap(err, "cannot get sensitive parameters")
 	}
+	if po, ok := tr.(resource.ProviderOverrider); ok {
+		fp.providerOverrides = providerOverrides(po.GetProviderOverrides(), cfg.ProviderConfigOverrides)
+	}
 	fp.Config.ExternalName.SetIdentifierArgumentFn(params, meta.GetExternalName(tr))
 	fp.parameters = params
 
@@ -78,6 +99,61 @@ func NewFileProducer(ctx context.Context, client resource.SecretClient, dir stri
 	return fp, nil
 }
 
+// checkCollapsedBlocks checks the kinds and the item limits of the values of
+// the blocks collapsed into runtime.RawExtension fields in the given
+// parameters, which are not validated by the CRD schemas.
+func checkCollapsedBlocks(cfg *config.Resource, params map[string]any) error {
+	blocks := cfg.CollapsedBlocks()
+	paths := make([]string, 0, len(blocks))
+	for p := range blocks {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	pv := fieldpath.Pave(params)
+	for _, p := range paths {
+		expanded, err := pv.ExpandWildcards(p)
+		if err != nil {
+			return errors.Wrapf(err, "cannot expand the collapsed block path %q", p)
+		}
+		for _, e := range expanded {
+			v, err := pv.GetValue(e)
+			if err != nil || v == nil {
+				continue
+			}
+			if err := checkCollapsedValue(blocks[p], v); err != nil {
+				return errors.Wrapf(err, "invalid value of the collapsed block %q", e)
+			}
+		}
+	}
+	return nil
+}
+
+func checkCollapsedValue(sch *schema.Schema, v any) error {
+	if sch.Type == schema.TypeMap {
+		if _, ok := v.(map[string]any); !ok {
+			return errors.New("value must be an object")
+		}
+		return nil
+	}
+	l, ok := v.([]any)
+	switch {
+	case !ok:
+		return errors.New("value must be a list")
+	case sch.MinItems > 0 && len(l) < sch.MinItems:
+		return errors.Errorf("value must be a list of at least %s", items(sch.MinItems))
+	case sch.MaxItems > 0 && len(l) > sch.MaxItems:
+		return errors.Errorf("value must be a list of at most %s", items(sch.MaxItems))
+	}
+	return nil
+}
+
+func items(n int) string {
+	if n == 1 {
+		return "1 item"
+	}
+	return fmt.Sprintf("%d items", n)
+}
+
 // FileProducer exist to serve as cache for the data that is costly to produce
 // every time like parameters and observation maps.
 type FileProducer struct {
@@ -88,39 +164,92 @@ type FileProducer struct {
 
 	parameters  map[string]any
 	observation map[string]any
-	fs          afero.Afero
+	// providerOverrides are the overridden provider configuration arguments
+	// of the resource.
+	providerOverrides map[string]any
+	fs                afero.Afero
+}
+
+// providerOverrides returns the given overridden provider configuration
+// arguments that are allowed to be overridden.
+func providerOverrides(overrides map[string]string, allowed []string) map[string]any {
+	result := make(map[string]any, len(allowed))
+	for _, a := range allowed {
+		if v, ok := overrides[a]; ok {
+			result[a] = v
+		}
+	}
+	return result
+}
+
+// providerConfiguration returns the configuration of the provider of the
+// resource, which is aliased if it has overridden provider configuration
+// arguments.
+func (fp *FileProducer) providerConfiguration() ProviderConfiguration {
+	if len(fp.providerOverrides) == 0 {
+		return fp.Setup.Configuration
+	}
+	pc := make(ProviderConfiguration, len(fp.Setup.Configuration)+len(fp.providerOverrides)+1)
+	for k, v := range fp.Setup.Configuration {
+		pc[k] = v
+	}
+	for k, v := range fp.providerOverrides {
+		pc[k] = v
+	}
+	pc["alias"] = providerOverridesAlias
+	return pc
 }
 
 // WriteMainTF writes the content main configuration file that has the desired
 // state configuration for Terraform.
 func (fp *FileProducer) WriteMainTF() (ProviderHandle, error) {
-	// If the resource is in a deletion process, we need to remove the deletion
-	// protection.
-	fp.parameters["lifecycle"] = map[string]bool{
-		"prevent_destroy": !meta.WasDeleted(fp.Resource),
-	}
+	// The data sources are only read, so they have neither a lifecycle nor
+	// operation timeouts.
+	mode := "resource"
+	if fp.Config.DataSource {
+		mode = "data"
+	} else {
+		// If the resource is in a deletion process, we need to remove the
+		// deletion protection.
+		lifecycle := map[string]any{
+			"prevent_destroy": !meta.WasDeleted(fp.Resource),
+		}
+		// The write-only fields are stripped from the state, so we need to
+		// ignore their changes to not update the resource in every plan.
+		if len(fp.Config.Sensitive.WriteOnlyFields) != 0 {
+			lifecycle["ignore_changes"] = fp.Config.Sensitive.WriteOnlyFields
+		}
+		fp.parameters["lifecycle"] = lifecycle
 
-	// Add operation timeouts if any timeout configured for the resource
-	if tp := timeouts(fp.Config.OperationTimeouts).asParameter(); len(tp) != 0 {
-		fp.parameters["timeouts"] = tp
+		// Add operation timeouts if any timeout configured for the resource
+		if tp := timeouts(fp.Config.OperationTimeouts).asParameter(); len(tp) != 0 {
+			fp.parameters["timeouts"] = tp
+		}
 	}
 
 	// Note(turkenh): To use third party providers, we need to configure
 	// provider name in required_providers.
 	providerSource := strings.Split(fp.Setup.Requirement.Source, "/")
+	providerName := providerSource[len(providerSource)-1]
+	pc := fp.providerConfiguration()
+	var provider any = fp.Setup.Configuration
+	if len(fp.providerOverrides) != 0 {
+		provider = []any{fp.Setup.Configuration, pc}
+		fp.parameters["provider"] = providerName + "." + providerOverridesAlias
+	}
 	m := map[string]any{
 		"terraform": map[string]any{
 			"required_providers": map[string]any{
-				providerSource[len(providerSource)-1]: map[string]string{
+				providerName: map[string]string{
 					"source":  fp.Setup.Requirement.Source,
 					"version": fp.Setup.Requirement.Version,
 				},
 			},
 		},
 		"provider": map[string]any{
-			providerSource[len(providerSource)-1]: fp.Setup.Configuration,
+			providerName: provider,
 		},
-		"resource": map[string]any{
+		mode: map[string]any{
 			fp.Resource.GetTerraformResourceType(): map[string]any{
 				fp.Resource.GetName(): fp.parameters,
 			},
@@ -130,7 +259,7 @@ func (fp *FileProducer) WriteMainTF() (ProviderHandle, error) {
 	if err != nil {
 		return InvalidProviderHandle, errors.Wrap(err, "cannot marshal main hcl object")
 	}
-	h, err := fp.Setup.Configuration.ToProviderHandle()
+	h, err := pc.ToProviderHandle()
 	if err != nil {
 		return InvalidProviderHandle, errors.Wrap(err, "cannot get scheduler handle")
 	}
@@ -150,8 +279,9 @@ func (fp *FileProducer) EnsureTFState(ctx context.Context, tfID string) error {
 	// of them from the TF state file signals that the deletion was successful.
 	// This is especially useful for resources whose deletion are scheduled for
 	// a long period of time, where if we fill the ID, the queries would actually
-	// succeed, i.e. GCP KMS KeyRing.
-	if !empty || meta.WasDeleted(fp.Resource) {
+	// succeed, i.e. GCP KMS KeyRing. The data sources are read from scratch
+	// in every refresh, so they don't need a state.
+	if !empty || meta.WasDeleted(fp.Resource) || fp.Config.DataSource {
 		return nil
 	}
 	base := make(map[string]any)
@@ -163,6 +293,9 @@ func (fp *FileProducer) EnsureTFState(ctx context.Context, tfID string) error {
 	for k, v := range fp.observation {
 		base[k] = v
 	}
+	for _, f := range fp.Config.Sensitive.WriteOnlyFields {
+		delete(base, f)
+	}
 	base["id"] = tfID
 	attr, err := json.JSParser.Marshal(base)
 	if err != nil {
@@ -175,6 +308,10 @@ func (fp *FileProducer) EnsureTFState(ctx context.Context, tfID string) error {
 	if privateRaw, err = insertTimeoutsMeta(privateRaw, timeouts(fp.Config.OperationTimeouts)); err != nil {
 		return errors.Wrap(err, errInsertTimeouts)
 	}
+	providerConfig := fmt.Sprintf(`provider["registry.terraform.io/%s"]`, fp.Setup.Requirement.Source)
+	if len(fp.providerOverrides) != 0 {
+		providerConfig += "." + providerOverridesAlias
+	}
 	s := json.NewStateV4()
 	s.TerraformVersion = fp.Setup.Version
 	s.Lineage = string(fp.Resource.GetUID())
@@ -185,7 +322,7 @@ func (fp *FileProducer) EnsureTFState(ctx context.Context, tfID string) error {
 			Name: fp.Resource.GetName(),
 			// TODO(muvaf): we should get the full URL from Dockerfile since
 			// providers don't have to be hosted in registry.terraform.io
-			ProviderConfig: fmt.Sprintf(`provider["registry.terraform.io/%s"]`, fp.Setup.Requirement.Source),
+			ProviderConfig: providerConfig,
 			Instances: []json.InstanceObjectStateV4{
 				{
 					SchemaVersion: uint64(fp.Resource.GetTerraformSchemaVersion()),
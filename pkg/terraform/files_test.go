@@ -15,6 +15,7 @@ import (
 	xpfake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -136,6 +137,35 @@ func TestEnsureTFState(t *testing.T) {
 				tfstate: empty,
 			},
 		},
+		"SuccessSkipDataSource": {
+			reason: "The state of a data source should not be written since it is read in every refresh.",
+			args: args{
+				tr: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								meta.AnnotationKeyExternalName: "some-id",
+							},
+						},
+					},
+					Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+						"param": "paramval",
+					}},
+					Observable: fake.Observable{Observation: map[string]any{
+						"obs": "obsval",
+					}},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, func(r *config.Resource) {
+					r.DataSource = true
+				}),
+				fs: func() afero.Afero {
+					return afero.Afero{Fs: afero.NewMemMapFs()}
+				},
+			},
+			want: want{
+				tfstate: "",
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -290,6 +320,15 @@ func TestIsStateEmpty(t *testing.T) {
 	}
 }
 
+type providerOverrider struct {
+	*fake.Terraformed
+	overrides map[string]string
+}
+
+func (tr *providerOverrider) GetProviderOverrides() map[string]string {
+	return tr.overrides
+}
+
 func TestWriteMainTF(t *testing.T) {
 	type args struct {
 		tr  resource.Terraformed
@@ -374,6 +413,175 @@ func TestWriteMainTF(t *testing.T) {
 				maintf: `{"provider":{"provider-test":null},"resource":{"":{"":{"lifecycle":{"prevent_destroy":true},"name":"some-id","param":"paramval"}}},"terraform":{"required_providers":{"provider-test":{"source":"hashicorp/provider-test","version":"1.2.3"}}}}`,
 			},
 		},
+		"DataSource": {
+			reason: "The data sources should be written as data blocks without a lifecycle or timeouts",
+			args: args{
+				tr: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								meta.AnnotationKeyExternalName: "some-id",
+							},
+						},
+					},
+					Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+						"param": "paramval",
+					}},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, func(r *config.Resource) {
+					r.DataSource = true
+					r.ExternalName = config.IdentifierFromProvider
+					r.OperationTimeouts = config.OperationTimeouts{
+						Read: 30 * time.Second,
+					}
+				}),
+				s: Setup{
+					Requirement: ProviderRequirement{
+						Source:  "hashicorp/provider-test",
+						Version: "1.2.3",
+					},
+					Configuration: nil,
+				},
+			},
+			want: want{
+				maintf: `{"data":{"":{"":{"param":"paramval"}}},"provider":{"provider-test":null},"terraform":{"required_providers":{"provider-test":{"source":"hashicorp/provider-test","version":"1.2.3"}}}}`,
+			},
+		},
+		"WriteOnlyFields": {
+			reason: "Changes to the write-only fields should be ignored since they are not kept in the state",
+			args: args{
+				tr: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								meta.AnnotationKeyExternalName: "some-id",
+							},
+						},
+					},
+					Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+						"param":    "paramval",
+						"password": "secret",
+					}},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, func(r *config.Resource) {
+					r.Sensitive.WriteOnlyFields = []string{"password"}
+				}),
+				s: Setup{
+					Requirement: ProviderRequirement{
+						Source:  "hashicorp/provider-test",
+						Version: "1.2.3",
+					},
+					Configuration: nil,
+				},
+			},
+			want: want{
+				maintf: `{"provider":{"provider-test":null},"resource":{"":{"":{"lifecycle":{"ignore_changes":["password"],"prevent_destroy":true},"name":"some-id","param":"paramval","password":"secret"}}},"terraform":{"required_providers":{"provider-test":{"source":"hashicorp/provider-test","version":"1.2.3"}}}}`,
+			},
+		},
+		"ParameterDefaults": {
+			reason: "The parameter defaults should be written for the parameters not set in the spec",
+			args: args{
+				tr: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								meta.AnnotationKeyExternalName: "some-id",
+							},
+						},
+					},
+					Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+						"param": "paramval",
+					}},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, func(r *config.Resource) {
+					r.ParameterDefaults = map[string]any{
+						"param": "defaultval",
+						"acl":   "private",
+					}
+				}),
+				s: Setup{
+					Requirement: ProviderRequirement{
+						Source:  "hashicorp/provider-test",
+						Version: "1.2.3",
+					},
+					Configuration: nil,
+				},
+			},
+			want: want{
+				maintf: `{"provider":{"provider-test":null},"resource":{"":{"":{"acl":"private","lifecycle":{"prevent_destroy":true},"name":"some-id","param":"paramval"}}},"terraform":{"required_providers":{"provider-test":{"source":"hashicorp/provider-test","version":"1.2.3"}}}}`,
+			},
+		},
+		"ProviderOverrides": {
+			reason: "The resource should use an aliased provider configured with the allowed overridden arguments",
+			args: args{
+				tr: &providerOverrider{
+					Terraformed: &fake.Terraformed{
+						Managed: xpfake.Managed{
+							ObjectMeta: metav1.ObjectMeta{
+								Annotations: map[string]string{
+									meta.AnnotationKeyExternalName: "some-id",
+								},
+							},
+						},
+						Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+							"param": "paramval",
+						}},
+					},
+					overrides: map[string]string{
+						"region":     "eu-west-1",
+						"access_key": "other",
+					},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, func(r *config.Resource) {
+					r.ProviderConfigOverrides = []string{"region"}
+				}),
+				s: Setup{
+					Requirement: ProviderRequirement{
+						Source:  "hashicorp/provider-test",
+						Version: "1.2.3",
+					},
+					Configuration: ProviderConfiguration{
+						"region": "us-east-1",
+					},
+				},
+			},
+			want: want{
+				maintf: `{"provider":{"provider-test":[{"region":"us-east-1"},{"alias":"override","region":"eu-west-1"}]},"resource":{"":{"":{"lifecycle":{"prevent_destroy":true},"name":"some-id","param":"paramval","provider":"provider-test.override"}}},"terraform":{"required_providers":{"provider-test":{"source":"hashicorp/provider-test","version":"1.2.3"}}}}`,
+			},
+		},
+		"HiddenParameters": {
+			reason: "The hidden parameters should always be written with their configured values",
+			args: args{
+				tr: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								meta.AnnotationKeyExternalName: "some-id",
+							},
+						},
+					},
+					Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+						"param": "paramval",
+					}},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, func(r *config.Resource) {
+					r.HiddenParameters = map[string]any{
+						"param":          "hiddenval",
+						"enable_feature": true,
+					}
+				}),
+				s: Setup{
+					Requirement: ProviderRequirement{
+						Source:  "hashicorp/provider-test",
+						Version: "1.2.3",
+					},
+					Configuration: nil,
+				},
+			},
+			want: want{
+				maintf: `{"provider":{"provider-test":null},"resource":{"":{"":{"enable_feature":true,"lifecycle":{"prevent_destroy":true},"name":"some-id","param":"hiddenval"}}},"terraform":{"required_providers":{"provider-test":{"source":"hashicorp/provider-test","version":"1.2.3"}}}}`,
+			},
+		},
 		"Custom Source": {
 			reason: "Custom source like my-company/namespace/provider-test resources should be able to write everything it has into maintf file",
 			args: args{
@@ -425,3 +633,76 @@ func TestWriteMainTF(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckCollapsedBlocks(t *testing.T) {
+	block := func(typ schema.ValueType, minItems, maxItems int, s map[string]*schema.Schema) *schema.Schema {
+		return &schema.Schema{
+			Type:     typ,
+			Optional: true,
+			MinItems: minItems,
+			MaxItems: maxItems,
+			Elem:     &schema.Resource{Schema: s},
+		}
+	}
+	leaf := map[string]*schema.Schema{
+		"prefix": {Type: schema.TypeString, Optional: true},
+	}
+	cfg := &config.Resource{
+		TerraformResource: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"rule": block(schema.TypeList, 0, 0, map[string]*schema.Schema{
+					"filter":      block(schema.TypeList, 0, 1, leaf),
+					"destination": block(schema.TypeSet, 2, 0, leaf),
+					"labels":      block(schema.TypeMap, 0, 0, leaf),
+				}),
+			},
+		},
+		MaxBlockNestingDepth: 1,
+	}
+	cases := map[string]struct {
+		reason string
+		params map[string]any
+		want   error
+	}{
+		"Valid": {
+			reason: "No error should be returned if the collapsed blocks have valid values.",
+			params: map[string]any{"rule": []any{map[string]any{
+				"filter":      []any{map[string]any{"prefix": "a"}},
+				"destination": []any{map[string]any{"prefix": "a"}, map[string]any{"prefix": "b"}},
+				"labels":      map[string]any{"a": map[string]any{"prefix": "a"}},
+			}}},
+		},
+		"NotSet": {
+			reason: "No error should be returned if the collapsed blocks are not set.",
+			params: map[string]any{"rule": []any{map[string]any{}}},
+		},
+		"NotAList": {
+			reason: "An error should be returned if the value of a collapsed list block is not a list.",
+			params: map[string]any{"rule": []any{map[string]any{"filter": map[string]any{"prefix": "a"}}}},
+			want:   errors.Wrap(errors.New("value must be a list"), `invalid value of the collapsed block "rule[0].filter"`),
+		},
+		"TooManyItems": {
+			reason: "An error should be returned if a collapsed list block has more items than its maximum.",
+			params: map[string]any{"rule": []any{map[string]any{"filter": []any{map[string]any{}, map[string]any{}}}}},
+			want:   errors.Wrap(errors.New("value must be a list of at most 1 item"), `invalid value of the collapsed block "rule[0].filter"`),
+		},
+		"TooFewItems": {
+			reason: "An error should be returned if a collapsed set block has fewer items than its minimum.",
+			params: map[string]any{"rule": []any{map[string]any{"destination": []any{map[string]any{}}}}},
+			want:   errors.Wrap(errors.New("value must be a list of at least 2 items"), `invalid value of the collapsed block "rule[0].destination"`),
+		},
+		"NotAnObject": {
+			reason: "An error should be returned if the value of a collapsed map block is not an object.",
+			params: map[string]any{"rule": []any{map[string]any{"labels": []any{}}}},
+			want:   errors.Wrap(errors.New("value must be an object"), `invalid value of the collapsed block "rule[0].labels"`),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := checkCollapsedBlocks(cfg, tc.params)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncheckCollapsedBlocks(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
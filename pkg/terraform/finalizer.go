@@ -9,6 +9,8 @@ import (
 
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/config"
 )
 
 const (
@@ -17,16 +19,19 @@ const (
 
 // StoreCleaner is the interface that the workspace finalizer needs to work with.
 type StoreCleaner interface {
-	Remove(obj xpresource.Object) error
+	Remove(obj xpresource.Object, cfg *config.Resource) error
 }
 
 // TODO(muvaf): A FinalizerChain in crossplane-runtime?
 
-// NewWorkspaceFinalizer returns a new WorkspaceFinalizer.
-func NewWorkspaceFinalizer(ws StoreCleaner, af xpresource.Finalizer) *WorkspaceFinalizer {
+// NewWorkspaceFinalizer returns a new WorkspaceFinalizer removing the
+// workspaces of the managed resources configured with the given resource
+// configuration.
+func NewWorkspaceFinalizer(ws StoreCleaner, af xpresource.Finalizer, cfg *config.Resource) *WorkspaceFinalizer {
 	return &WorkspaceFinalizer{
 		Finalizer: af,
 		Store:     ws,
+		Config:    cfg,
 	}
 }
 
@@ -34,7 +39,8 @@ func NewWorkspaceFinalizer(ws StoreCleaner, af xpresource.Finalizer) *WorkspaceF
 // then calls RemoveFinalizer of the underlying Finalizer.
 type WorkspaceFinalizer struct {
 	xpresource.Finalizer
-	Store StoreCleaner
+	Store  StoreCleaner
+	Config *config.Resource
 }
 
 // AddFinalizer to the supplied Managed resource.
@@ -45,7 +51,7 @@ func (wf *WorkspaceFinalizer) AddFinalizer(ctx context.Context, obj xpresource.O
 // RemoveFinalizer removes the workspace from workspace store before removing
 // the finalizer.
 func (wf *WorkspaceFinalizer) RemoveFinalizer(ctx context.Context, obj xpresource.Object) error {
-	if err := wf.Store.Remove(obj); err != nil {
+	if err := wf.Store.Remove(obj, wf.Config); err != nil {
 		return errors.Wrap(err, errRemoveWorkspace)
 	}
 	return wf.Finalizer.RemoveFinalizer(ctx, obj)
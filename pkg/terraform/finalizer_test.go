@@ -14,6 +14,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 
+	"github.com/upbound/upjet/pkg/config"
 	"github.com/upbound/upjet/pkg/resource"
 )
 
@@ -23,15 +24,15 @@ var (
 
 type StoreFns struct {
 	WorkspaceFn func(ctx context.Context, tr resource.Terraformed, ts Setup, l logging.Logger) (*Workspace, error)
-	RemoveFn    func(obj xpresource.Object) error
+	RemoveFn    func(obj xpresource.Object, cfg *config.Resource) error
 }
 
 func (sf *StoreFns) Workspace(ctx context.Context, tr resource.Terraformed, ts Setup, l logging.Logger) (*Workspace, error) {
 	return sf.WorkspaceFn(ctx, tr, ts, l)
 }
 
-func (sf *StoreFns) Remove(obj xpresource.Object) error {
-	return sf.RemoveFn(obj)
+func (sf *StoreFns) Remove(obj xpresource.Object, cfg *config.Resource) error {
+	return sf.RemoveFn(obj, cfg)
 }
 
 func TestAddFinalizer(t *testing.T) {
@@ -72,7 +73,7 @@ func TestAddFinalizer(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			f := NewWorkspaceFinalizer(tc.args.store, tc.args.finalizer)
+			f := NewWorkspaceFinalizer(tc.args.store, tc.args.finalizer, nil)
 			err := f.AddFinalizer(context.TODO(), tc.args.obj)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nAddFinalizer(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -98,7 +99,7 @@ func TestRemoveFinalizer(t *testing.T) {
 		"Success": {
 			args: args{
 				store: &StoreFns{
-					RemoveFn: func(_ xpresource.Object) error {
+					RemoveFn: func(_ xpresource.Object, _ *config.Resource) error {
 						return nil
 					},
 				},
@@ -112,7 +113,7 @@ func TestRemoveFinalizer(t *testing.T) {
 		"StoreRemovalFails": {
 			args: args{
 				store: &StoreFns{
-					RemoveFn: func(_ xpresource.Object) error {
+					RemoveFn: func(_ xpresource.Object, _ *config.Resource) error {
 						return errBoom
 					},
 				},
@@ -124,7 +125,7 @@ func TestRemoveFinalizer(t *testing.T) {
 		"FinalizerFails": {
 			args: args{
 				store: &StoreFns{
-					RemoveFn: func(_ xpresource.Object) error {
+					RemoveFn: func(_ xpresource.Object, _ *config.Resource) error {
 						return nil
 					},
 				},
@@ -141,7 +142,7 @@ func TestRemoveFinalizer(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			f := NewWorkspaceFinalizer(tc.args.store, tc.args.finalizer)
+			f := NewWorkspaceFinalizer(tc.args.store, tc.args.finalizer, nil)
 			err := f.RemoveFinalizer(context.TODO(), tc.args.obj)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nRemoveFinalizer(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -0,0 +1,1271 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package terraform
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	stdjson "encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+	"k8s.io/utils/exec"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/resource/json"
+)
+
+const (
+	modeManaged = "managed"
+	modeData    = "data"
+)
+
+// WithProviderExecution configures the workspaces to run the Terraform
+// operations as configured by the given provider configuration, i.e. in the
+// process of the controllers with an InProcessExecutor if its
+// TerraformExecutionMode is config.TerraformExecutionModeInProcess, or with
+// the Terraform CLI otherwise.
+func WithProviderExecution(pc *config.Provider) WorkspaceStoreOption {
+	return func(ws *WorkspaceStore) {
+		if pc.TerraformExecutionMode != config.TerraformExecutionModeInProcess {
+			return
+		}
+		v6 := make([]string, 0, len(pc.FrameworkResourceSchemas))
+		for name := range pc.FrameworkResourceSchemas {
+			v6 = append(v6, name)
+		}
+		ws.executor = NewInProcessExecutor(pc.TerraformPluginServerFn, WithProtocolV6Resources(v6...))
+		// there are no provider plugins to be installed.
+		ws.disableInit = true
+	}
+}
+
+// defaultPluginServerIdleTimeout is the default duration after which the
+// unused plugin servers are stopped. It's longer than the default poll
+// interval of the controllers, so that the plugin servers of the managed
+// resources that are only observed are kept.
+const defaultPluginServerIdleTimeout = time.Hour
+
+// InProcessExecutorOption configures an InProcessExecutor.
+type InProcessExecutorOption func(*InProcessExecutor)
+
+// WithPluginServerIdleTimeout configures the duration after which the plugin
+// servers that are not used by any Terraform operation are stopped, e.g.
+// the ones configured with the rotated credentials or with the deleted
+// ProviderConfigs. Defaults to an hour.
+func WithPluginServerIdleTimeout(d time.Duration) InProcessExecutorOption {
+	return func(e *InProcessExecutor) {
+		e.idleTimeout = d
+	}
+}
+
+// WithProtocolV6Resources configures the Terraform resources that are served
+// via the Terraform plugin protocol version 6, e.g. the ones of the
+// Config.Provider.FrameworkResourceSchemas, which are not supported by the
+// executor. Their operations fail instead of being run with the protocol
+// version 5 plugin server.
+func WithProtocolV6Resources(names ...string) InProcessExecutorOption {
+	return func(e *InProcessExecutor) {
+		for _, n := range names {
+			e.v6Resources[n] = struct{}{}
+		}
+	}
+}
+
+// NewInProcessExecutor returns a new InProcessExecutor running the Terraform
+// operations with the plugin servers returned by the given function.
+func NewInProcessExecutor(fn func() tfprotov5.ProviderServer, opts ...InProcessExecutorOption) *InProcessExecutor {
+	e := &InProcessExecutor{
+		newServer:   fn,
+		servers:     map[string]*pluginServer{},
+		v6Resources: map[string]struct{}{},
+		idleTimeout: defaultPluginServerIdleTimeout,
+		now:         time.Now,
+	}
+	for _, o := range opts {
+		o(e)
+	}
+	return e
+}
+
+// InProcessExecutor runs the Terraform commands of the workspaces by calling
+// the plugin server of the Terraform provider in the same process instead of
+// forking the Terraform CLI, which in turn forks the provider plugin. Like
+// the Terraform CLI, it reads the main.tf.json and the terraform.tfstate
+// files of the workspace the commands are run in and writes the resulting
+// state to the terraform.tfstate file, and it outputs the diagnostics and
+// the change summaries as JSON log lines, so that the workspaces work the
+// same with both. A plugin server is configured once per distinct provider
+// configuration and it's kept while it's used, so that the clients of the
+// provider are reused across the reconciliations. The plugin servers that
+// are not used for the idle timeout of the executor are stopped. Only the
+// Terraform plugin protocol version 5 is supported, so the operations on
+// the resources served via the protocol version 6 fail. It's safe for
+// concurrent use.
+type InProcessExecutor struct {
+	newServer   func() tfprotov5.ProviderServer
+	v6Resources map[string]struct{}
+	idleTimeout time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	servers map[string]*pluginServer
+}
+
+// pluginServer is a plugin server configured with a provider configuration.
+type pluginServer struct {
+	once   sync.Once
+	err    error
+	diags  []*tfprotov5.Diagnostic
+	server tfprotov5.ProviderServer
+	schema *tfprotov5.GetProviderSchemaResponse
+
+	// inUse is the number of the running operations using the server and
+	// lastUsed is the time the last one ended, which are guarded by the
+	// mutex of the executor.
+	inUse    int
+	lastUsed time.Time
+}
+
+// Command returns a Terraform command with the given arguments.
+func (e *InProcessExecutor) Command(cmd string, args ...string) exec.Cmd {
+	return e.CommandContext(context.Background(), cmd, args...)
+}
+
+// CommandContext returns a Terraform command with the given arguments,
+// whose provider calls are made with the given context.
+func (e *InProcessExecutor) CommandContext(ctx context.Context, _ string, args ...string) exec.Cmd {
+	return &inProcessCommand{ctx: ctx, executor: e, args: args}
+}
+
+// LookPath returns the given file as is as there are no executables run.
+func (e *InProcessExecutor) LookPath(file string) (string, error) {
+	return file, nil
+}
+
+// server returns the plugin server configured with the given provider
+// configuration, creating and configuring it if it doesn't exist. The
+// returned server must be released once the operation using it ends.
+func (e *InProcessExecutor) server(ctx context.Context, cfg map[string]jsoniter.RawMessage) (*pluginServer, error) {
+	if e.newServer == nil {
+		return nil, errors.New("no Terraform plugin server function is configured for the in-process execution")
+	}
+	raw, err := json.JSParser.Marshal(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot marshal the provider configuration")
+	}
+	key := fmt.Sprintf("%x", sha256.Sum256(raw))
+	e.mu.Lock()
+	evicted := e.evictIdle()
+	ps, ok := e.servers[key]
+	if !ok {
+		ps = &pluginServer{}
+		e.servers[key] = ps
+	}
+	ps.inUse++
+	e.mu.Unlock()
+	for _, s := range evicted {
+		// the evicted servers are stopped so that their provider clients
+		// are not leaked. They're not used anymore, so a failure to stop
+		// one doesn't fail the operation.
+		_, _ = s.StopProvider(ctx, &tfprotov5.StopProviderRequest{})
+	}
+	ps.once.Do(func() {
+		ps.err = ps.configure(ctx, e.newServer(), raw)
+	})
+	if ps.err != nil || hasErrors(ps.diags) {
+		// the servers that cannot be configured, e.g. with the invalid
+		// credentials, are not kept so that they're retried.
+		e.mu.Lock()
+		if e.servers[key] == ps {
+			delete(e.servers, key)
+		}
+		e.mu.Unlock()
+	}
+	if ps.err != nil {
+		e.release(ps)
+		return nil, ps.err
+	}
+	return ps, nil
+}
+
+// release marks the end of an operation using the given plugin server.
+func (e *InProcessExecutor) release(ps *pluginServer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ps.inUse--
+	ps.lastUsed = e.now()
+}
+
+// evictIdle removes the plugin servers that are not used for the idle
+// timeout and returns them to be stopped. It must be called with the mutex
+// held.
+func (e *InProcessExecutor) evictIdle() []tfprotov5.ProviderServer {
+	var evicted []tfprotov5.ProviderServer
+	now := e.now()
+	for k, ps := range e.servers {
+		if ps.inUse != 0 || ps.lastUsed.IsZero() || now.Sub(ps.lastUsed) < e.idleTimeout {
+			continue
+		}
+		delete(e.servers, k)
+		if ps.server != nil {
+			evicted = append(evicted, ps.server)
+		}
+	}
+	return evicted
+}
+
+// configure gets the schema of the given plugin server and configures it
+// with the given provider configuration.
+func (ps *pluginServer) configure(ctx context.Context, s tfprotov5.ProviderServer, cfg []byte) error {
+	ps.server = s
+	schema, err := s.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		return errors.Wrap(err, "cannot get the provider schema")
+	}
+	ps.schema = schema
+	if ps.diags = schema.Diagnostics; hasErrors(ps.diags) {
+		return nil
+	}
+	ty, err := schemaType(schema.Provider)
+	if err != nil {
+		return err
+	}
+	v, err := ctyjson.Unmarshal(cfg, ty)
+	if err != nil {
+		return errors.Wrap(err, "cannot decode the provider configuration")
+	}
+	dv, err := encode(ty, v)
+	if err != nil {
+		return err
+	}
+	prepared, err := s.PrepareProviderConfig(ctx, &tfprotov5.PrepareProviderConfigRequest{Config: dv})
+	if err != nil {
+		return errors.Wrap(err, "cannot prepare the provider configuration")
+	}
+	if ps.diags = append(ps.diags, prepared.Diagnostics...); hasErrors(ps.diags) {
+		return nil
+	}
+	if prepared.PreparedConfig != nil {
+		dv = prepared.PreparedConfig
+	}
+	configured, err := s.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{Config: dv})
+	if err != nil {
+		return errors.Wrap(err, "cannot configure the provider")
+	}
+	ps.diags = append(ps.diags, configured.Diagnostics...)
+	return nil
+}
+
+// inProcessWorkspace is the main.tf.json file of a workspace.
+type inProcessWorkspace struct {
+	Terraform struct {
+		RequiredProviders map[string]struct {
+			Source string `json:"source"`
+		} `json:"required_providers"`
+	} `json:"terraform"`
+	Provider map[string]jsoniter.RawMessage                       `json:"provider"`
+	Resource map[string]map[string]map[string]jsoniter.RawMessage `json:"resource"`
+	Data     map[string]map[string]map[string]jsoniter.RawMessage `json:"data"`
+}
+
+// providerConfig returns the configuration of the provider, or the one of
+// its aliased configuration with the given reference, e.g.
+// "aws.override", and the address of the provider configuration in the
+// state.
+func (w inProcessWorkspace) providerConfig(ref string) (map[string]jsoniter.RawMessage, string, error) {
+	for name, raw := range w.Provider {
+		addr := name
+		if rp, ok := w.Terraform.RequiredProviders[name]; ok && rp.Source != "" {
+			addr = fmt.Sprintf(`provider["registry.terraform.io/%s"]`, rp.Source)
+		}
+		var cfgs []map[string]jsoniter.RawMessage
+		if len(bytes.TrimSpace(raw)) > 0 && bytes.TrimSpace(raw)[0] == '[' {
+			if err := json.JSParser.Unmarshal(raw, &cfgs); err != nil {
+				return nil, "", errors.Wrap(err, "cannot unmarshal the provider configurations")
+			}
+		} else {
+			cfg := map[string]jsoniter.RawMessage{}
+			if err := json.JSParser.Unmarshal(raw, &cfg); err != nil {
+				return nil, "", errors.Wrap(err, "cannot unmarshal the provider configuration")
+			}
+			cfgs = append(cfgs, cfg)
+		}
+		alias := ""
+		if i := strings.Index(ref, "."); i != -1 {
+			alias = ref[i+1:]
+		}
+		for _, cfg := range cfgs {
+			a := ""
+			if raw, ok := cfg["alias"]; ok {
+				if err := json.JSParser.Unmarshal(raw, &a); err != nil {
+					return nil, "", errors.Wrap(err, "cannot unmarshal the provider alias")
+				}
+			}
+			if a != alias {
+				continue
+			}
+			delete(cfg, "alias")
+			if cfg == nil {
+				cfg = map[string]jsoniter.RawMessage{}
+			}
+			if alias != "" {
+				addr += "." + alias
+			}
+			return cfg, addr, nil
+		}
+		return nil, "", errors.Errorf("cannot find the provider configuration %q", ref)
+	}
+	return map[string]jsoniter.RawMessage{}, "", nil
+}
+
+// lifecycle is the lifecycle block of a resource.
+type lifecycle struct {
+	PreventDestroy bool     `json:"prevent_destroy"`
+	IgnoreChanges  []string `json:"ignore_changes"`
+}
+
+// inProcessOperation is a Terraform operation on the resource or the data
+// source of a workspace.
+type inProcessOperation struct {
+	ctx     context.Context
+	dir     string
+	ps      *pluginServer
+	mode    string
+	typ     string
+	name    string
+	addr    string
+	schema  *tfprotov5.Schema
+	ty      cty.Type
+	config  cty.Value
+	lc      lifecycle
+	diags   []*tfprotov5.Diagnostic
+	summary string
+}
+
+func (e *InProcessExecutor) run(ctx context.Context, dir string, args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, errors.New("no Terraform command")
+	}
+	if args[0] == "init" {
+		return nil, nil
+	}
+	op, err := e.operation(ctx, dir)
+	if op.ps != nil {
+		defer e.release(op.ps)
+	}
+	if err == nil && !hasErrors(op.diags) {
+		err = op.run(args)
+	}
+	return op.output(err)
+}
+
+// operation returns the operation on the resource or the data source of the
+// workspace in the given directory.
+func (e *InProcessExecutor) operation(ctx context.Context, dir string) (*inProcessOperation, error) { // nolint:gocyclo
+	op := &inProcessOperation{ctx: ctx, dir: dir, mode: modeManaged}
+	raw, err := os.ReadFile(filepath.Join(dir, "main.tf.json"))
+	if err != nil {
+		return op, errors.Wrap(err, "cannot read the main.tf.json file")
+	}
+	w := inProcessWorkspace{}
+	if err := json.JSParser.Unmarshal(raw, &w); err != nil {
+		return op, errors.Wrap(err, "cannot unmarshal the main.tf.json file")
+	}
+	blocks := w.Resource
+	if len(w.Data) > 0 {
+		op.mode, blocks = modeData, w.Data
+	}
+	var params map[string]jsoniter.RawMessage
+	for t, b := range blocks {
+		for n, p := range b {
+			op.typ, op.name, params = t, n, p
+		}
+	}
+	if params == nil {
+		return op, errors.New("no resource in the workspace")
+	}
+	if _, ok := e.v6Resources[op.typ]; ok {
+		return op, errors.Errorf("the %s %s is served via the Terraform plugin protocol version 6, which is not supported in process", op.mode, op.typ)
+	}
+	ref := ""
+	if raw, ok := params["provider"]; ok {
+		if err := json.JSParser.Unmarshal(raw, &ref); err != nil {
+			return op, errors.Wrap(err, "cannot unmarshal the provider reference")
+		}
+	}
+	if raw, ok := params["lifecycle"]; ok {
+		if err := json.JSParser.Unmarshal(raw, &op.lc); err != nil {
+			return op, errors.Wrap(err, "cannot unmarshal the lifecycle")
+		}
+	}
+	cfg, addr, err := w.providerConfig(ref)
+	if err != nil {
+		return op, err
+	}
+	op.addr = addr
+	if op.ps, err = e.server(ctx, cfg); err != nil {
+		return op, err
+	}
+	if op.diags = op.ps.diags; hasErrors(op.diags) {
+		return op, nil
+	}
+	schemas := op.ps.schema.ResourceSchemas
+	if op.mode == modeData {
+		schemas = op.ps.schema.DataSourceSchemas
+	}
+	if op.schema = schemas[op.typ]; op.schema == nil {
+		return op, errors.Errorf("the provider does not support the %s %s", op.mode, op.typ)
+	}
+	if op.ty, err = schemaType(op.schema); err != nil {
+		return op, err
+	}
+	// the meta-arguments and the arguments unknown to the schema, e.g. the
+	// "lifecycle" and the "provider", are not a part of the configuration.
+	attrs := make(map[string]jsoniter.RawMessage, len(params))
+	for k, v := range params {
+		if op.ty.HasAttribute(k) {
+			attrs[k] = v
+		}
+	}
+	if raw, err = json.JSParser.Marshal(attrs); err != nil {
+		return op, errors.Wrap(err, "cannot marshal the configuration")
+	}
+	op.config, err = ctyjson.Unmarshal(raw, op.ty)
+	return op, errors.Wrap(err, "cannot decode the configuration")
+}
+
+func (op *inProcessOperation) run(args []string) error {
+	switch {
+	case args[0] == "apply" && contains(args, "-refresh-only"):
+		return op.refresh()
+	case args[0] == "apply":
+		return op.apply()
+	case args[0] == "plan":
+		return op.plan()
+	case args[0] == "import" && len(args) > 1:
+		return op.importResource(args[len(args)-1])
+	case args[0] == "destroy":
+		return op.destroy()
+	}
+	return errors.Errorf("unsupported Terraform command %q", strings.Join(args, " "))
+}
+
+// refresh reads the current state of the resource or the data source.
+func (op *inProcessOperation) refresh() error {
+	if op.mode == modeData {
+		return op.readDataSource()
+	}
+	prior, private, err := op.readState()
+	if err != nil || prior.IsNull() {
+		return err
+	}
+	current, private, err := op.readResource(prior, private)
+	if err != nil || hasErrors(op.diags) {
+		return err
+	}
+	return op.writeState(current, private)
+}
+
+// apply refreshes the resource and then creates, updates or replaces it as
+// planned.
+func (op *inProcessOperation) apply() error { // nolint:gocyclo
+	if op.mode == modeData {
+		return op.readDataSource()
+	}
+	prior, private, err := op.readState()
+	if err != nil {
+		return err
+	}
+	if !prior.IsNull() {
+		if prior, private, err = op.readResource(prior, private); err != nil || hasErrors(op.diags) {
+			return err
+		}
+	}
+	planned, plannedPrivate, replace, err := op.planResourceChange(prior, private)
+	if err != nil || hasErrors(op.diags) {
+		return err
+	}
+	if !prior.IsNull() && !replace && equal(planned, prior) {
+		return op.writeState(prior, private)
+	}
+	if !prior.IsNull() && replace {
+		if err := op.preventDestroy(); err != nil || hasErrors(op.diags) {
+			return err
+		}
+		if prior, private, err = op.applyResourceChange(prior, cty.NullVal(op.ty), cty.NullVal(op.ty), private); err != nil || hasErrors(op.diags) {
+			return err
+		}
+		if planned, plannedPrivate, _, err = op.planResourceChange(prior, nil); err != nil || hasErrors(op.diags) {
+			return err
+		}
+	}
+	state, private, err := op.applyResourceChange(prior, planned, op.config, plannedPrivate)
+	if err != nil {
+		return err
+	}
+	return op.writeState(state, private)
+}
+
+// plan compares the configuration with the state of the resource without
+// refreshing it and outputs the change summary.
+func (op *inProcessOperation) plan() error {
+	add, change, remove := 0, 0, 0
+	prior, private, err := op.readState()
+	if err != nil {
+		return err
+	}
+	switch {
+	case prior.IsNull():
+		add = 1
+	default:
+		planned, _, replace, err := op.planResourceChange(prior, private)
+		if err != nil || hasErrors(op.diags) {
+			return err
+		}
+		switch {
+		// like the Terraform CLI, a replacement is summarized as an
+		// addition and a removal.
+		case replace:
+			add, remove = 1, 1
+		case !equal(planned, prior):
+			change = 1
+		}
+	}
+	op.summary = fmt.Sprintf(`{"@level":"info","@message":"Plan: %d to add, %d to change, %d to destroy.","type":"change_summary","changes":{"add":%d,"change":%d,"remove":%d,"operation":"plan"}}`, add, change, remove, add, change, remove)
+	return nil
+}
+
+// importResource imports the resource with the given ID and reads its
+// state.
+func (op *inProcessOperation) importResource(id string) error {
+	resp, err := op.ps.server.ImportResourceState(op.ctx, &tfprotov5.ImportResourceStateRequest{TypeName: op.typ, ID: id})
+	if err != nil {
+		return errors.Wrap(err, "cannot import the resource")
+	}
+	if op.diags = append(op.diags, resp.Diagnostics...); hasErrors(op.diags) {
+		return nil
+	}
+	for _, r := range resp.ImportedResources {
+		if r.TypeName != op.typ {
+			continue
+		}
+		imported, err := decode(op.ty, r.State)
+		if err != nil {
+			return err
+		}
+		current, private, err := op.readResource(imported, r.Private)
+		if err != nil || hasErrors(op.diags) {
+			return err
+		}
+		if current.IsNull() {
+			// the same diagnostic as the Terraform CLI, which the
+			// workspaces rely on to tell the resources that do not exist.
+			op.diags = append(op.diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Cannot import non-existent remote object",
+				Detail:   fmt.Sprintf("While attempting to import an existing object to %q, the provider detected that no object exists with the given id.", op.typ+"."+op.name),
+			})
+			return nil
+		}
+		return op.writeState(current, private)
+	}
+	return errors.Errorf("the provider did not import a %s", op.typ)
+}
+
+// destroy refreshes the resource and then deletes it, if it exists.
+func (op *inProcessOperation) destroy() error {
+	prior, private, err := op.readState()
+	if err != nil || prior.IsNull() {
+		return err
+	}
+	if prior, private, err = op.readResource(prior, private); err != nil || hasErrors(op.diags) {
+		return err
+	}
+	if !prior.IsNull() {
+		if err := op.preventDestroy(); err != nil || hasErrors(op.diags) {
+			return err
+		}
+		if prior, private, err = op.applyResourceChange(prior, cty.NullVal(op.ty), cty.NullVal(op.ty), private); err != nil {
+			return err
+		}
+	}
+	return op.writeState(prior, private)
+}
+
+func (op *inProcessOperation) preventDestroy() error {
+	if op.lc.PreventDestroy {
+		op.diags = append(op.diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Instance cannot be destroyed",
+			Detail:   fmt.Sprintf("Resource %s.%s has lifecycle.prevent_destroy set, but the plan calls for this resource to be destroyed.", op.typ, op.name),
+		})
+	}
+	return nil
+}
+
+func (op *inProcessOperation) readDataSource() error {
+	dv, err := encode(op.ty, op.config)
+	if err != nil {
+		return err
+	}
+	resp, err := op.ps.server.ReadDataSource(op.ctx, &tfprotov5.ReadDataSourceRequest{TypeName: op.typ, Config: dv})
+	if err != nil {
+		return errors.Wrap(err, "cannot read the data source")
+	}
+	if op.diags = append(op.diags, resp.Diagnostics...); hasErrors(op.diags) {
+		return nil
+	}
+	state, err := decode(op.ty, resp.State)
+	if err != nil {
+		return err
+	}
+	return op.writeState(state, nil)
+}
+
+func (op *inProcessOperation) readResource(current cty.Value, private []byte) (cty.Value, []byte, error) {
+	dv, err := encode(op.ty, current)
+	if err != nil {
+		return cty.NilVal, nil, err
+	}
+	resp, err := op.ps.server.ReadResource(op.ctx, &tfprotov5.ReadResourceRequest{TypeName: op.typ, CurrentState: dv, Private: private})
+	if err != nil {
+		return cty.NilVal, nil, errors.Wrap(err, "cannot read the resource")
+	}
+	op.diags = append(op.diags, resp.Diagnostics...)
+	if hasErrors(resp.Diagnostics) {
+		return current, private, nil
+	}
+	state, err := decode(op.ty, resp.NewState)
+	return state, resp.Private, err
+}
+
+// planResourceChange plans the change of the resource from the given prior
+// state to the configuration and reports whether it requires a replacement.
+func (op *inProcessOperation) planResourceChange(prior cty.Value, private []byte) (cty.Value, []byte, bool, error) {
+	config := op.config
+	if !prior.IsNull() {
+		var err error
+		if config, err = ignoreChanges(op.lc.IgnoreChanges, prior, config); err != nil {
+			return cty.NilVal, nil, false, err
+		}
+	}
+	proposed, err := encode(op.ty, proposedNew(op.schema.Block, prior, config))
+	if err != nil {
+		return cty.NilVal, nil, false, err
+	}
+	priorDV, err := encode(op.ty, prior)
+	if err != nil {
+		return cty.NilVal, nil, false, err
+	}
+	configDV, err := encode(op.ty, op.config)
+	if err != nil {
+		return cty.NilVal, nil, false, err
+	}
+	resp, err := op.ps.server.PlanResourceChange(op.ctx, &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         op.typ,
+		PriorState:       priorDV,
+		ProposedNewState: proposed,
+		Config:           configDV,
+		PriorPrivate:     private,
+	})
+	if err != nil {
+		return cty.NilVal, nil, false, errors.Wrap(err, "cannot plan the resource change")
+	}
+	if op.diags = append(op.diags, resp.Diagnostics...); hasErrors(op.diags) {
+		return cty.NilVal, nil, false, nil
+	}
+	planned, err := decode(op.ty, resp.PlannedState)
+	return planned, resp.PlannedPrivate, len(resp.RequiresReplace) != 0, err
+}
+
+// applyResourceChange applies the planned change of the resource, which is
+// its deletion if the planned state is null, and returns its new state,
+// which is the prior one if nothing is applied.
+func (op *inProcessOperation) applyResourceChange(prior, planned, config cty.Value, private []byte) (cty.Value, []byte, error) {
+	priorDV, err := encode(op.ty, prior)
+	if err != nil {
+		return cty.NilVal, nil, err
+	}
+	plannedDV, err := encode(op.ty, planned)
+	if err != nil {
+		return cty.NilVal, nil, err
+	}
+	configDV, err := encode(op.ty, config)
+	if err != nil {
+		return cty.NilVal, nil, err
+	}
+	resp, err := op.ps.server.ApplyResourceChange(op.ctx, &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:       op.typ,
+		PriorState:     priorDV,
+		PlannedState:   plannedDV,
+		Config:         configDV,
+		PlannedPrivate: private,
+	})
+	if err != nil {
+		return cty.NilVal, nil, errors.Wrap(err, "cannot apply the resource change")
+	}
+	op.diags = append(op.diags, resp.Diagnostics...)
+	// like the Terraform CLI, the new state is kept even if the change
+	// fails, e.g. for a resource that is created but cannot be tagged.
+	if resp.NewState == nil {
+		return prior, private, nil
+	}
+	state, err := decode(op.ty, resp.NewState)
+	if err != nil || !state.IsWhollyKnown() {
+		return prior, private, err
+	}
+	return state, resp.Private, nil
+}
+
+// readState returns the state of the resource in the terraform.tfstate file
+// of the workspace upgraded to the current schema version, or a null value
+// if there's none.
+func (op *inProcessOperation) readState() (cty.Value, []byte, error) {
+	s, err := op.stateFile()
+	if err != nil || s.GetAttributes() == nil {
+		return cty.NullVal(op.ty), nil, err
+	}
+	instance := s.Resources[0].Instances[0]
+	resp, err := op.ps.server.UpgradeResourceState(op.ctx, &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: op.typ,
+		Version:  int64(instance.SchemaVersion),
+		RawState: &tfprotov5.RawState{JSON: instance.AttributesRaw},
+	})
+	if err != nil {
+		return cty.NilVal, nil, errors.Wrap(err, "cannot upgrade the resource state")
+	}
+	if op.diags = append(op.diags, resp.Diagnostics...); hasErrors(op.diags) {
+		return cty.NilVal, nil, errors.New("cannot upgrade the resource state")
+	}
+	state, err := decode(op.ty, resp.UpgradedState)
+	return state, instance.PrivateRaw, err
+}
+
+// stateFile returns the terraform.tfstate file of the workspace, or a new
+// state if there's none.
+func (op *inProcessOperation) stateFile() (*json.StateV4, error) {
+	raw, err := os.ReadFile(filepath.Join(op.dir, "terraform.tfstate"))
+	if os.IsNotExist(err) {
+		return json.NewStateV4(), nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read the terraform.tfstate file")
+	}
+	s := &json.StateV4{}
+	return s, errors.Wrap(json.JSParser.Unmarshal(raw, s), "cannot unmarshal the terraform.tfstate file")
+}
+
+// writeState writes the given state of the resource to the
+// terraform.tfstate file of the workspace, or the state without any
+// resources if it's null.
+func (op *inProcessOperation) writeState(state cty.Value, private []byte) error {
+	s, err := op.stateFile()
+	if err != nil {
+		return err
+	}
+	s.Serial++
+	s.Resources = nil
+	if !state.IsNull() {
+		attrs, err := ctyjson.Marshal(state, op.ty)
+		if err != nil {
+			return errors.Wrap(err, "cannot encode the resource state")
+		}
+		s.Resources = []json.ResourceStateV4{
+			{
+				Mode:           op.mode,
+				Type:           op.typ,
+				Name:           op.name,
+				ProviderConfig: op.addr,
+				Instances: []json.InstanceObjectStateV4{
+					{
+						SchemaVersion: uint64(op.schema.Version),
+						AttributesRaw: attrs,
+						PrivateRaw:    private,
+					},
+				},
+			},
+		}
+	}
+	raw, err := json.JSParser.Marshal(s)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal the state")
+	}
+	return errors.Wrap(os.WriteFile(filepath.Join(op.dir, "terraform.tfstate"), raw, 0600), "cannot write the terraform.tfstate file")
+}
+
+// output returns the JSON log lines of the diagnostics and the change
+// summary of the operation, and an error if it failed.
+func (op *inProcessOperation) output(err error) ([]byte, error) {
+	type diagnostic struct {
+		Severity string `json:"severity"`
+		Summary  string `json:"summary"`
+		Detail   string `json:"detail"`
+	}
+	type logLine struct {
+		Level      string      `json:"@level"`
+		Message    string      `json:"@message"`
+		Type       string      `json:"type"`
+		Diagnostic *diagnostic `json:"diagnostic,omitempty"`
+	}
+	var lines []logLine
+	for _, d := range op.diags {
+		l := logLine{Level: "warn", Message: "Warning: " + d.Summary, Type: "diagnostic", Diagnostic: &diagnostic{Severity: "warning", Summary: d.Summary, Detail: d.Detail}}
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			l.Level, l.Message, l.Diagnostic.Severity = "error", "Error: "+d.Summary, "error"
+		}
+		lines = append(lines, l)
+	}
+	if err != nil {
+		lines = append(lines, logLine{Level: "error", Message: "Error: " + err.Error(), Type: "diagnostic", Diagnostic: &diagnostic{Severity: "error", Summary: err.Error()}})
+	}
+	out := &bytes.Buffer{}
+	for _, l := range lines {
+		b, mErr := stdjson.Marshal(l)
+		if mErr != nil {
+			return nil, errors.Wrap(mErr, "cannot marshal the log line")
+		}
+		out.Write(append(b, '\n'))
+	}
+	if op.summary != "" {
+		out.WriteString(op.summary + "\n")
+	}
+	if err == nil && hasErrors(op.diags) {
+		err = errors.New("the Terraform operation failed with error diagnostics")
+	}
+	return out.Bytes(), err
+}
+
+// proposedNew returns the proposed new state of an object of the given
+// block from its prior state and its configuration, which is the
+// configuration whose null computed attributes keep their prior values, as
+// proposed by the Terraform CLI to the provider. The nested blocks are
+// merged like Terraform's objchange.ProposedNew does, i.e. the list
+// elements are matched by their indices, the map elements by their keys and
+// the set elements by their non-computed attributes.
+func proposedNew(b *tfprotov5.SchemaBlock, prior, config cty.Value) cty.Value {
+	if b == nil || prior.IsNull() || config.IsNull() || !prior.IsKnown() || !config.IsKnown() {
+		return config
+	}
+	attrs := config.AsValueMap()
+	if attrs == nil {
+		return config
+	}
+	for _, a := range b.Attributes {
+		if a.Computed && attrs[a.Name].IsNull() {
+			attrs[a.Name] = prior.GetAttr(a.Name)
+		}
+	}
+	for _, nb := range b.BlockTypes {
+		attrs[nb.TypeName] = proposedNewNested(nb, prior.GetAttr(nb.TypeName), config.GetAttr(nb.TypeName))
+	}
+	return cty.ObjectVal(attrs)
+}
+
+// proposedNewNested returns the proposed new value of the given nested block
+// from its prior value and its configuration.
+func proposedNewNested(nb *tfprotov5.SchemaNestedBlock, prior, config cty.Value) cty.Value { // nolint:gocyclo
+	if nb.Nesting == tfprotov5.SchemaNestedBlockNestingModeSingle || nb.Nesting == tfprotov5.SchemaNestedBlockNestingModeGroup {
+		return proposedNew(nb.Block, prior, config)
+	}
+	if prior.IsNull() || config.IsNull() || !prior.IsKnown() || !config.IsKnown() || config.LengthInt() == 0 {
+		return config
+	}
+	switch nb.Nesting { // nolint:exhaustive
+	case tfprotov5.SchemaNestedBlockNestingModeList:
+		if !config.Type().IsListType() {
+			return config
+		}
+		elems := make([]cty.Value, 0, config.LengthInt())
+		for it := config.ElementIterator(); it.Next(); {
+			i, c := it.Element()
+			if !prior.HasIndex(i).True() {
+				elems = append(elems, c)
+				continue
+			}
+			elems = append(elems, proposedNew(nb.Block, prior.Index(i), c))
+		}
+		return cty.ListVal(elems)
+	case tfprotov5.SchemaNestedBlockNestingModeMap:
+		elems := make(map[string]cty.Value, config.LengthInt())
+		for it := config.ElementIterator(); it.Next(); {
+			k, c := it.Element()
+			switch {
+			case config.Type().IsObjectType() && prior.Type().IsObjectType() && prior.Type().HasAttribute(k.AsString()):
+				elems[k.AsString()] = proposedNew(nb.Block, prior.GetAttr(k.AsString()), c)
+			case config.Type().IsMapType() && prior.Type().IsMapType() && prior.HasIndex(k).True():
+				elems[k.AsString()] = proposedNew(nb.Block, prior.Index(k), c)
+			default:
+				elems[k.AsString()] = c
+			}
+		}
+		if config.Type().IsObjectType() {
+			return cty.ObjectVal(elems)
+		}
+		return cty.MapVal(elems)
+	case tfprotov5.SchemaNestedBlockNestingModeSet:
+		// the set elements are matched by their non-computed attributes, so
+		// a configuration change results in a new element without any
+		// prior computed values.
+		var priors, keys []cty.Value
+		for it := prior.ElementIterator(); it.Next(); {
+			_, p := it.Element()
+			priors = append(priors, p)
+			keys = append(keys, setElementKey(nb.Block, p, false))
+		}
+		used := make([]bool, len(priors))
+		elems := make([]cty.Value, 0, config.LengthInt())
+		for it := config.ElementIterator(); it.Next(); {
+			_, c := it.Element()
+			e, ck := c, setElementKey(nb.Block, c, true)
+			for i, k := range keys {
+				if !used[i] && k.RawEquals(ck) {
+					used[i], e = true, proposedNew(nb.Block, priors[i], c)
+					break
+				}
+			}
+			elems = append(elems, e)
+		}
+		return cty.SetVal(elems)
+	}
+	return config
+}
+
+// setElementKey returns the given set element of the given block whose
+// computed attributes are null, which matches the prior and the configured
+// elements. The optional computed attributes of the prior elements are null
+// too, so that the configured elements setting them don't match the prior
+// ones.
+func setElementKey(b *tfprotov5.SchemaBlock, v cty.Value, isConfig bool) cty.Value { // nolint:gocyclo
+	if b == nil || v.IsNull() || !v.IsKnown() {
+		return v
+	}
+	attrs := v.AsValueMap()
+	if attrs == nil {
+		return v
+	}
+	for _, a := range b.Attributes {
+		if a.Computed && (!a.Optional || !isConfig) {
+			attrs[a.Name] = cty.NullVal(v.GetAttr(a.Name).Type())
+		}
+	}
+	for _, nb := range b.BlockTypes {
+		n := v.GetAttr(nb.TypeName)
+		if nb.Nesting == tfprotov5.SchemaNestedBlockNestingModeSingle || nb.Nesting == tfprotov5.SchemaNestedBlockNestingModeGroup {
+			attrs[nb.TypeName] = setElementKey(nb.Block, n, isConfig)
+			continue
+		}
+		if n.IsNull() || !n.IsKnown() || n.LengthInt() == 0 {
+			continue
+		}
+		switch {
+		case n.Type().IsListType() || n.Type().IsSetType():
+			elems := make([]cty.Value, 0, n.LengthInt())
+			for it := n.ElementIterator(); it.Next(); {
+				_, e := it.Element()
+				elems = append(elems, setElementKey(nb.Block, e, isConfig))
+			}
+			if n.Type().IsSetType() {
+				attrs[nb.TypeName] = cty.SetVal(elems)
+			} else {
+				attrs[nb.TypeName] = cty.ListVal(elems)
+			}
+		case n.Type().IsMapType():
+			elems := make(map[string]cty.Value, n.LengthInt())
+			for it := n.ElementIterator(); it.Next(); {
+				k, e := it.Element()
+				elems[k.AsString()] = setElementKey(nb.Block, e, isConfig)
+			}
+			attrs[nb.TypeName] = cty.MapVal(elems)
+		}
+	}
+	return cty.ObjectVal(attrs)
+}
+
+// ignoreChanges returns the given configuration whose attributes at the
+// given ignore_changes paths, e.g. "tags", `tags["Name"]` or
+// "rule[0].filter", have their prior values.
+func ignoreChanges(paths []string, prior, config cty.Value) (cty.Value, error) {
+	for _, p := range paths {
+		steps, err := parseIgnorePath(p)
+		if err != nil {
+			return cty.NilVal, errors.Wrapf(err, "cannot parse the ignore_changes entry %q", p)
+		}
+		if config, err = ignoreChange(steps, prior, config); err != nil {
+			return cty.NilVal, errors.Wrapf(err, "cannot ignore the changes of %q", p)
+		}
+	}
+	return config, nil
+}
+
+// ignoreChange returns the given configuration whose value at the given path
+// is the prior one.
+func ignoreChange(path cty.Path, prior, config cty.Value) (cty.Value, error) { // nolint:gocyclo
+	if len(path) == 0 {
+		return prior, nil
+	}
+	if config.IsNull() || !config.IsKnown() || prior.IsNull() || !prior.IsKnown() {
+		return config, nil
+	}
+	switch s := path[0].(type) {
+	case cty.GetAttrStep:
+		if !config.Type().IsObjectType() || !config.Type().HasAttribute(s.Name) {
+			return cty.NilVal, errors.Errorf("no attribute %q", s.Name)
+		}
+		attrs := config.AsValueMap()
+		v, err := ignoreChange(path[1:], prior.GetAttr(s.Name), attrs[s.Name])
+		if err != nil {
+			return cty.NilVal, err
+		}
+		attrs[s.Name] = v
+		return cty.ObjectVal(attrs), nil
+	case cty.IndexStep:
+		ty := config.Type()
+		switch {
+		case ty.IsListType() && s.Key.Type() == cty.Number:
+			elems := config.AsValueSlice()
+			if !config.HasIndex(s.Key).True() || !prior.HasIndex(s.Key).True() {
+				return config, nil
+			}
+			i, _ := s.Key.AsBigFloat().Int64()
+			v, err := ignoreChange(path[1:], prior.Index(s.Key), elems[i])
+			if err != nil {
+				return cty.NilVal, err
+			}
+			elems[i] = v
+			return cty.ListVal(elems), nil
+		case ty.IsMapType() && s.Key.Type() == cty.String:
+			elems := config.AsValueMap()
+			if elems == nil {
+				elems = map[string]cty.Value{}
+			}
+			k := s.Key.AsString()
+			switch {
+			case !prior.HasIndex(s.Key).True():
+				// the ignored key isn't added.
+				delete(elems, k)
+			case !config.HasIndex(s.Key).True():
+				// the ignored key isn't removed.
+				elems[k] = prior.Index(s.Key)
+			default:
+				v, err := ignoreChange(path[1:], prior.Index(s.Key), elems[k])
+				if err != nil {
+					return cty.NilVal, err
+				}
+				elems[k] = v
+			}
+			if len(elems) == 0 {
+				return cty.MapValEmpty(ty.ElementType()), nil
+			}
+			return cty.MapVal(elems), nil
+		}
+		return cty.NilVal, errors.Errorf("cannot index a value of type %s with %s", ty.FriendlyName(), s.Key.GoString())
+	}
+	return config, nil
+}
+
+// parseIgnorePath parses the given ignore_changes entry, which is a
+// traversal of attribute names, list indices and map keys, e.g.
+// `rule[0].tags["Name"]`.
+func parseIgnorePath(s string) (cty.Path, error) { // nolint:gocyclo
+	var path cty.Path
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end == -1 {
+				return nil, errors.New("unterminated index")
+			}
+			key := s[i+1 : i+end]
+			if len(key) >= 2 && key[0] == '"' && key[len(key)-1] == '"' {
+				k := ""
+				if err := stdjson.Unmarshal([]byte(key), &k); err != nil {
+					return nil, errors.Wrap(err, "cannot unquote the key")
+				}
+				path = path.IndexString(k)
+			} else {
+				n, err := strconv.ParseInt(key, 10, 64)
+				if err != nil || n < 0 {
+					return nil, errors.Errorf("invalid index %q", key)
+				}
+				path = path.IndexInt(int(n))
+			}
+			i += end + 1
+		case s[i] == '.' && len(path) > 0 && i+1 < len(s) && s[i+1] != '.' && s[i+1] != '[':
+			i++
+		default:
+			end := strings.IndexAny(s[i:], ".[")
+			if end == -1 {
+				end = len(s) - i
+			}
+			if end == 0 {
+				return nil, errors.Errorf("empty attribute name at offset %d", i)
+			}
+			path = path.GetAttr(s[i : i+end])
+			i += end
+		}
+	}
+	if len(path) == 0 {
+		return nil, errors.New("empty path")
+	}
+	if _, ok := path[0].(cty.GetAttrStep); !ok {
+		return nil, errors.New("the path must start with an attribute name")
+	}
+	return path, nil
+}
+
+// equal reports whether the given values are known to be equal.
+func equal(a, b cty.Value) bool {
+	eq := a.Equals(b)
+	return eq.IsKnown() && eq.True()
+}
+
+// schemaType returns the type of the objects of the given schema.
+func schemaType(s *tfprotov5.Schema) (cty.Type, error) {
+	raw, err := stdjson.Marshal(s.ValueType())
+	if err != nil {
+		return cty.NilType, errors.Wrap(err, "cannot marshal the schema type")
+	}
+	ty, err := ctyjson.UnmarshalType(raw)
+	return ty, errors.Wrap(err, "cannot unmarshal the schema type")
+}
+
+func encode(ty cty.Type, v cty.Value) (*tfprotov5.DynamicValue, error) {
+	raw, err := ctymsgpack.Marshal(v, ty)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot encode the value")
+	}
+	return &tfprotov5.DynamicValue{MsgPack: raw}, nil
+}
+
+func decode(ty cty.Type, dv *tfprotov5.DynamicValue) (cty.Value, error) {
+	switch {
+	case dv == nil:
+		return cty.NullVal(ty), nil
+	case dv.MsgPack != nil:
+		v, err := ctymsgpack.Unmarshal(dv.MsgPack, ty)
+		return v, errors.Wrap(err, "cannot decode the value")
+	case dv.JSON != nil:
+		v, err := ctyjson.Unmarshal(dv.JSON, ty)
+		return v, errors.Wrap(err, "cannot decode the value")
+	}
+	return cty.NullVal(ty), nil
+}
+
+func hasErrors(diags []*tfprotov5.Diagnostic) bool {
+	for _, d := range diags {
+		if d != nil && d.Severity == tfprotov5.DiagnosticSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// inProcessCommand is a Terraform command run by an InProcessExecutor. The
+// command is run once, either by Start or by one of the methods running it
+// to completion, and its output and error are kept for the rest.
+type inProcessCommand struct {
+	ctx      context.Context
+	executor *InProcessExecutor
+	args     []string
+	dir      string
+	stdout   io.Writer
+
+	started bool
+	out     []byte
+	err     error
+}
+
+// start runs the command if it's not run yet and writes its output to the
+// configured stdout.
+func (c *inProcessCommand) start() {
+	if c.started {
+		return
+	}
+	c.started = true
+	c.out, c.err = c.executor.run(c.ctx, c.dir, c.args)
+	if c.stdout != nil {
+		_, _ = c.stdout.Write(c.out)
+	}
+}
+
+func (c *inProcessCommand) Run() error {
+	c.start()
+	return c.err
+}
+
+func (c *inProcessCommand) CombinedOutput() ([]byte, error) {
+	c.start()
+	return c.out, c.err
+}
+
+func (c *inProcessCommand) Output() ([]byte, error) {
+	c.start()
+	return c.out, c.err
+}
+
+func (c *inProcessCommand) SetDir(dir string) {
+	c.dir = dir
+}
+
+func (c *inProcessCommand) SetStdin(_ io.Reader) {}
+
+func (c *inProcessCommand) SetStdout(out io.Writer) {
+	c.stdout = out
+}
+
+func (c *inProcessCommand) SetStderr(_ io.Writer) {}
+
+func (c *inProcessCommand) SetEnv(_ []string) {}
+
+func (c *inProcessCommand) StdoutPipe() (io.ReadCloser, error) {
+	if c.stdout != nil {
+		return nil, errors.New("stdout is already set")
+	}
+	if c.started {
+		return nil, errors.New("StdoutPipe after the command is started")
+	}
+	buf := &bytes.Buffer{}
+	c.stdout = buf
+	return io.NopCloser(buf), nil
+}
+
+func (c *inProcessCommand) StderrPipe() (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(nil)), nil
+}
+
+func (c *inProcessCommand) Start() error {
+	c.start()
+	return nil
+}
+
+func (c *inProcessCommand) Wait() error {
+	c.start()
+	return c.err
+}
+
+func (c *inProcessCommand) Stop() {}
@@ -0,0 +1,618 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	xpfake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zclconf/go-cty/cty"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/upbound/upjet/pkg/resource/fake"
+	"github.com/upbound/upjet/pkg/resource/json"
+)
+
+// testPluginServerFn returns a function returning the plugin servers of a
+// provider whose "test_thing" resources are kept in the given map and whose
+// "test_region" data source reads the configured region.
+func testPluginServerFn(things map[string]map[string]any) func() tfprotov5.ProviderServer {
+	read := func(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+		t, ok := things[d.Id()]
+		if !ok {
+			d.SetId("")
+			return nil
+		}
+		_ = d.Set("name", d.Id())
+		_ = d.Set("size", t["size"])
+		_ = d.Set("arn", fmt.Sprintf("%s:%s", meta, d.Id()))
+		return nil
+	}
+	p := &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"region": {Type: schema.TypeString, Optional: true},
+		},
+		ConfigureContextFunc: func(_ context.Context, d *schema.ResourceData) (any, diag.Diagnostics) {
+			return d.Get("region").(string), nil
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"test_thing": {
+				Schema: map[string]*schema.Schema{
+					"name": {Type: schema.TypeString, Required: true, ForceNew: true},
+					"size": {Type: schema.TypeInt, Optional: true},
+					"arn":  {Type: schema.TypeString, Computed: true},
+				},
+				CreateContext: func(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+					d.SetId(d.Get("name").(string))
+					things[d.Id()] = map[string]any{"size": d.Get("size")}
+					return read(ctx, d, meta)
+				},
+				ReadContext: read,
+				UpdateContext: func(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+					things[d.Id()]["size"] = d.Get("size")
+					return read(ctx, d, meta)
+				},
+				DeleteContext: func(_ context.Context, d *schema.ResourceData, _ any) diag.Diagnostics {
+					delete(things, d.Id())
+					return nil
+				},
+				Importer: &schema.ResourceImporter{StateContext: schema.ImportStatePassthroughContext},
+			},
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"test_region": {
+				Schema: map[string]*schema.Schema{
+					"region": {Type: schema.TypeString, Computed: true},
+				},
+				ReadContext: func(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+					d.SetId(meta.(string))
+					_ = d.Set("region", meta)
+					return nil
+				},
+			},
+		},
+	}
+	return func() tfprotov5.ProviderServer {
+		return schema.NewGRPCProviderServer(p)
+	}
+}
+
+func TestInProcessExecutor(t *testing.T) {
+	mainTF := func(mode, typ, params string) string {
+		return fmt.Sprintf(`{"terraform":{"required_providers":{"test":{"source":"upbound/test","version":"1.0.0"}}},"provider":{"test":{"region":"us-east-1"}},%q:{%q:{"foo":%s}}}`, mode, typ, params)
+	}
+	thing := mainTF("resource", "test_thing", `{"name":"foo","size":2,"lifecycle":{"prevent_destroy":false}}`)
+	protected := mainTF("resource", "test_thing", `{"name":"foo","size":2,"lifecycle":{"prevent_destroy":true}}`)
+	current := map[string]any{"id": "foo", "name": "foo", "size": float64(2), "arn": "us-east-1:foo"}
+	type want struct {
+		result any
+		err    string
+		things map[string]map[string]any
+		state  map[string]any
+	}
+	cases := map[string]struct {
+		reason string
+		main   string
+		things map[string]map[string]any
+		state  map[string]any
+		opts   []InProcessExecutorOption
+		op     func(ctx context.Context, w *Workspace) (any, error)
+		want   want
+	}{
+		"ApplyCreate": {
+			reason: "The resource should be created and its state should be written if it doesn't exist.",
+			main:   thing,
+			things: map[string]map[string]any{},
+			op: func(ctx context.Context, w *Workspace) (any, error) {
+				r, err := w.Apply(ctx)
+				return r.State.GetAttributes() != nil, err
+			},
+			want: want{
+				result: true,
+				things: map[string]map[string]any{"foo": {"size": 2}},
+				state:  current,
+			},
+		},
+		"ApplyUpdate": {
+			reason: "The resource should be updated in place if its updatable arguments change.",
+			main:   thing,
+			things: map[string]map[string]any{"foo": {"size": 1}},
+			state:  map[string]any{"id": "foo", "name": "foo", "size": 1},
+			op: func(ctx context.Context, w *Workspace) (any, error) {
+				_, err := w.Apply(ctx)
+				return nil, err
+			},
+			want: want{
+				things: map[string]map[string]any{"foo": {"size": 2}},
+				state:  current,
+			},
+		},
+		"PlanAdd": {
+			reason: "The resource should be planned to be added if there's no state.",
+			main:   thing,
+			things: map[string]map[string]any{},
+			op: func(ctx context.Context, w *Workspace) (any, error) {
+				return w.Plan(ctx)
+			},
+			want: want{
+				result: PlanResult{Exists: false, UpToDate: true},
+				things: map[string]map[string]any{},
+			},
+		},
+		"PlanUpToDate": {
+			reason: "The resource should be up to date if its state matches the configuration.",
+			main:   thing,
+			things: map[string]map[string]any{"foo": {"size": 2}},
+			state:  current,
+			op: func(ctx context.Context, w *Workspace) (any, error) {
+				return w.Plan(ctx)
+			},
+			want: want{
+				result: PlanResult{Exists: true, UpToDate: true},
+				things: map[string]map[string]any{"foo": {"size": 2}},
+				state:  current,
+			},
+		},
+		"PlanChange": {
+			reason: "The resource should not be up to date if its state doesn't match the configuration.",
+			main:   thing,
+			things: map[string]map[string]any{"foo": {"size": 1}},
+			state:  map[string]any{"id": "foo", "name": "foo", "size": 1, "arn": "us-east-1:foo"},
+			op: func(ctx context.Context, w *Workspace) (any, error) {
+				return w.Plan(ctx)
+			},
+			want: want{
+				result: PlanResult{Exists: true, UpToDate: false},
+				things: map[string]map[string]any{"foo": {"size": 1}},
+				state:  map[string]any{"id": "foo", "name": "foo", "size": float64(1), "arn": "us-east-1:foo"},
+			},
+		},
+		"RefreshNotFound": {
+			reason: "The resource should not exist and the state should be emptied if it's deleted externally.",
+			main:   thing,
+			things: map[string]map[string]any{},
+			state:  current,
+			op: func(ctx context.Context, w *Workspace) (any, error) {
+				r, err := w.Refresh(ctx)
+				return r.Exists, err
+			},
+			want: want{
+				result: false,
+				things: map[string]map[string]any{},
+			},
+		},
+		"Import": {
+			reason: "The existing resource should be imported with its ID.",
+			main:   thing,
+			things: map[string]map[string]any{"foo": {"size": 2}},
+			op: func(ctx context.Context, w *Workspace) (any, error) {
+				r, err := w.Import(ctx, &fake.Terraformed{
+					Managed:          xpfake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "foo"}},
+					MetadataProvider: fake.MetadataProvider{Type: "test_thing"},
+				})
+				return r.Exists, err
+			},
+			want: want{
+				result: true,
+				things: map[string]map[string]any{"foo": {"size": 2}},
+				state:  current,
+			},
+		},
+		"ImportNotFound": {
+			reason: "The resource should not exist if there's no resource with its ID to import.",
+			main:   thing,
+			things: map[string]map[string]any{},
+			op: func(ctx context.Context, w *Workspace) (any, error) {
+				r, err := w.Import(ctx, &fake.Terraformed{
+					Managed:          xpfake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "foo"}},
+					MetadataProvider: fake.MetadataProvider{Type: "test_thing"},
+				})
+				return r.Exists, err
+			},
+			want: want{
+				result: false,
+				things: map[string]map[string]any{},
+			},
+		},
+		"Destroy": {
+			reason: "The resource should be deleted and the state should be emptied.",
+			main:   thing,
+			things: map[string]map[string]any{"foo": {"size": 2}},
+			state:  current,
+			op: func(ctx context.Context, w *Workspace) (any, error) {
+				return nil, w.Destroy(ctx)
+			},
+			want: want{
+				things: map[string]map[string]any{},
+			},
+		},
+		"DestroyPrevented": {
+			reason: "The resource should not be deleted if its lifecycle prevents it.",
+			main:   protected,
+			things: map[string]map[string]any{"foo": {"size": 2}},
+			state:  current,
+			op: func(ctx context.Context, w *Workspace) (any, error) {
+				return nil, w.Destroy(ctx)
+			},
+			want: want{
+				err:    "destroy failed: Instance cannot be destroyed: Resource test_thing.foo has lifecycle.prevent_destroy set, but the plan calls for this resource to be destroyed.",
+				things: map[string]map[string]any{"foo": {"size": 2}},
+				state:  current,
+			},
+		},
+		"ProtocolV6": {
+			reason: "The operations on the resources served via the protocol version 6 should fail.",
+			main:   thing,
+			things: map[string]map[string]any{},
+			opts:   []InProcessExecutorOption{WithProtocolV6Resources("test_thing")},
+			op: func(ctx context.Context, w *Workspace) (any, error) {
+				_, err := w.Plan(ctx)
+				return nil, err
+			},
+			want: want{
+				err:    "plan failed: the managed test_thing is served via the Terraform plugin protocol version 6, which is not supported in process: ",
+				things: map[string]map[string]any{},
+			},
+		},
+		"DataSource": {
+			reason: "The data source should be read with the provider configuration.",
+			main:   mainTF("data", "test_region", `{}`),
+			things: map[string]map[string]any{},
+			op: func(ctx context.Context, w *Workspace) (any, error) {
+				r, err := w.Refresh(ctx)
+				return r.Exists, err
+			},
+			want: want{
+				result: true,
+				things: map[string]map[string]any{},
+				state:  map[string]any{"id": "us-east-1", "region": "us-east-1"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "main.tf.json"), []byte(tc.main), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if tc.state != nil {
+				attrs, err := json.JSParser.Marshal(tc.state)
+				if err != nil {
+					t.Fatal(err)
+				}
+				s := json.NewStateV4()
+				s.Resources = []json.ResourceStateV4{{Mode: "managed", Type: "test_thing", Name: "foo", Instances: []json.InstanceObjectStateV4{{AttributesRaw: attrs}}}}
+				raw, err := json.JSParser.Marshal(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "terraform.tfstate"), raw, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			w := NewWorkspace(dir, WithExecutor(NewInProcessExecutor(testPluginServerFn(tc.things), tc.opts...)), WithFilterFn(func(s string) string { return s }))
+			w.terraformID = "foo"
+			result, err := tc.op(context.Background(), w)
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if diff := cmp.Diff(tc.want.err, gotErr); diff != "" {
+				t.Fatalf("\n%s\nerror: -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, result); diff != "" {
+				t.Errorf("\n%s\nresult: -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.things, tc.things); diff != "" {
+				t.Errorf("\n%s\nexternal resources: -want, +got:\n%s", tc.reason, diff)
+			}
+			var state map[string]any
+			if raw, err := os.ReadFile(filepath.Join(dir, "terraform.tfstate")); err == nil {
+				s := &json.StateV4{}
+				if err := json.JSParser.Unmarshal(raw, s); err != nil {
+					t.Fatal(err)
+				}
+				if attrs := s.GetAttributes(); attrs != nil {
+					if err := json.JSParser.Unmarshal(attrs, &state); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.want.state, state); diff != "" {
+				t.Errorf("\n%s\nstate: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// stopCountingServer is a plugin server counting the times it's stopped.
+type stopCountingServer struct {
+	tfprotov5.ProviderServer
+	stopped *int
+}
+
+func (s stopCountingServer) StopProvider(ctx context.Context, req *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error) {
+	*s.stopped++
+	return s.ProviderServer.StopProvider(ctx, req)
+}
+
+func TestInProcessExecutorEviction(t *testing.T) {
+	mainTF := func(region string) string {
+		return fmt.Sprintf(`{"terraform":{"required_providers":{"test":{"source":"upbound/test","version":"1.0.0"}}},"provider":{"test":{"region":%q}},"data":{"test_region":{"foo":{}}}}`, region)
+	}
+	type want struct {
+		servers int
+		stopped int
+	}
+	cases := map[string]struct {
+		reason  string
+		regions []string
+		elapsed time.Duration
+		want    want
+	}{
+		"SameConfiguration": {
+			reason:  "The plugin server should be reused for the same provider configuration.",
+			regions: []string{"us-east-1", "us-east-1"},
+			elapsed: 30 * time.Second,
+			want:    want{servers: 1},
+		},
+		"SameConfigurationIdle": {
+			reason:  "The plugin server should be stopped and configured again if it's not used for the idle timeout.",
+			regions: []string{"us-east-1", "us-east-1"},
+			elapsed: 2 * time.Minute,
+			want:    want{servers: 1, stopped: 1},
+		},
+		"RecentlyUsed": {
+			reason:  "The plugin server of another provider configuration should be kept if it's used within the idle timeout.",
+			regions: []string{"us-east-1", "us-west-2"},
+			elapsed: 30 * time.Second,
+			want:    want{servers: 2},
+		},
+		"Idle": {
+			reason:  "The plugin server of another provider configuration should be evicted and stopped if it's not used for the idle timeout.",
+			regions: []string{"us-east-1", "us-west-2"},
+			elapsed: 2 * time.Minute,
+			want:    want{servers: 1, stopped: 1},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			stopped := 0
+			newServer := testPluginServerFn(map[string]map[string]any{})
+			e := NewInProcessExecutor(func() tfprotov5.ProviderServer {
+				return stopCountingServer{ProviderServer: newServer(), stopped: &stopped}
+			}, WithPluginServerIdleTimeout(time.Minute))
+			now := time.Now()
+			e.now = func() time.Time { return now }
+			for _, r := range tc.regions {
+				dir := t.TempDir()
+				if err := os.WriteFile(filepath.Join(dir, "main.tf.json"), []byte(mainTF(r)), 0600); err != nil {
+					t.Fatal(err)
+				}
+				w := NewWorkspace(dir, WithExecutor(e), WithFilterFn(func(s string) string { return s }))
+				w.terraformID = "foo"
+				if _, err := w.Refresh(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+				now = now.Add(tc.elapsed)
+			}
+			if diff := cmp.Diff(tc.want.servers, len(e.servers)); diff != "" {
+				t.Errorf("\n%s\nservers: -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.stopped, stopped); diff != "" {
+				t.Errorf("\n%s\nstopped: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// planCountingServer is a plugin server counting the planned changes.
+type planCountingServer struct {
+	tfprotov5.ProviderServer
+	planned *int
+}
+
+func (s planCountingServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	*s.planned++
+	return s.ProviderServer.PlanResourceChange(ctx, req)
+}
+
+func TestInProcessCommandStdoutPipe(t *testing.T) {
+	dir := t.TempDir()
+	main := `{"terraform":{"required_providers":{"test":{"source":"upbound/test","version":"1.0.0"}}},"provider":{"test":{"region":"us-east-1"}},"resource":{"test_thing":{"foo":{"name":"foo","size":2}}}}`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf.json"), []byte(main), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s := json.NewStateV4()
+	s.Resources = []json.ResourceStateV4{{Mode: "managed", Type: "test_thing", Name: "foo", Instances: []json.InstanceObjectStateV4{{AttributesRaw: []byte(`{"id":"foo","name":"foo","size":1}`)}}}}
+	raw, err := json.JSParser.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "terraform.tfstate"), raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+	planned := 0
+	newServer := testPluginServerFn(map[string]map[string]any{"foo": {"size": 1}})
+	e := NewInProcessExecutor(func() tfprotov5.ProviderServer {
+		return planCountingServer{ProviderServer: newServer(), planned: &planned}
+	})
+	cmd := e.CommandContext(context.Background(), "terraform", "plan", "-refresh=false", "-input=false", "-lock=false", "-json")
+	cmd.SetDir(dir)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"change":1`) {
+		t.Errorf("the plan output should be read from the stdout pipe, got: %s", out)
+	}
+	if diff := cmp.Diff(1, planned); diff != "" {
+		t.Errorf("the operation should be run once: -want, +got:\n%s", diff)
+	}
+}
+
+func TestProposedNew(t *testing.T) {
+	rule := &tfprotov5.SchemaBlock{
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "port", Optional: true},
+			{Name: "id", Computed: true},
+		},
+	}
+	ruleType := cty.Object(map[string]cty.Type{"port": cty.Number, "id": cty.String})
+	ruleVal := func(port int, id cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"port": cty.NumberIntVal(int64(port)), "id": id})
+	}
+	nullID := cty.NullVal(cty.String)
+	block := func(nesting tfprotov5.SchemaNestedBlockNestingMode) *tfprotov5.SchemaBlock {
+		return &tfprotov5.SchemaBlock{BlockTypes: []*tfprotov5.SchemaNestedBlock{{TypeName: "rule", Nesting: nesting, Block: rule}}}
+	}
+	obj := func(v cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"rule": v})
+	}
+	cases := map[string]struct {
+		reason string
+		block  *tfprotov5.SchemaBlock
+		prior  cty.Value
+		config cty.Value
+		want   cty.Value
+	}{
+		"ListElements": {
+			reason: "The list elements should be matched by their indices and the new ones should be kept as configured.",
+			block:  block(tfprotov5.SchemaNestedBlockNestingModeList),
+			prior:  obj(cty.ListVal([]cty.Value{ruleVal(80, cty.StringVal("a"))})),
+			config: obj(cty.ListVal([]cty.Value{ruleVal(443, nullID), ruleVal(8080, nullID)})),
+			want:   obj(cty.ListVal([]cty.Value{ruleVal(443, cty.StringVal("a")), ruleVal(8080, nullID)})),
+		},
+		"SetElements": {
+			reason: "The set elements should be matched by their non-computed attributes, so that the changed ones have no prior computed values.",
+			block:  block(tfprotov5.SchemaNestedBlockNestingModeSet),
+			prior:  obj(cty.SetVal([]cty.Value{ruleVal(80, cty.StringVal("a")), ruleVal(443, cty.StringVal("b"))})),
+			config: obj(cty.SetVal([]cty.Value{ruleVal(443, nullID), ruleVal(8080, nullID)})),
+			want:   obj(cty.SetVal([]cty.Value{ruleVal(443, cty.StringVal("b")), ruleVal(8080, nullID)})),
+		},
+		"MapElements": {
+			reason: "The map elements should be matched by their keys.",
+			block:  block(tfprotov5.SchemaNestedBlockNestingModeMap),
+			prior:  obj(cty.MapVal(map[string]cty.Value{"http": ruleVal(80, cty.StringVal("a"))})),
+			config: obj(cty.MapVal(map[string]cty.Value{"http": ruleVal(8080, nullID), "https": ruleVal(443, nullID)})),
+			want:   obj(cty.MapVal(map[string]cty.Value{"http": ruleVal(8080, cty.StringVal("a")), "https": ruleVal(443, nullID)})),
+		},
+		"NoPrior": {
+			reason: "The configuration should be proposed as is if there's no prior state.",
+			block:  block(tfprotov5.SchemaNestedBlockNestingModeSet),
+			prior:  cty.NullVal(cty.Object(map[string]cty.Type{"rule": cty.Set(ruleType)})),
+			config: obj(cty.SetVal([]cty.Value{ruleVal(443, nullID)})),
+			want:   obj(cty.SetVal([]cty.Value{ruleVal(443, nullID)})),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := proposedNew(tc.block, tc.prior, tc.config)
+			if !got.RawEquals(tc.want) {
+				t.Errorf("\n%s\nproposedNew(...): want %s, got %s", tc.reason, tc.want.GoString(), got.GoString())
+			}
+		})
+	}
+}
+
+func TestIgnoreChanges(t *testing.T) {
+	val := func(size int, tags map[string]string, ports ...int) cty.Value {
+		t := make(map[string]cty.Value, len(tags))
+		for k, v := range tags {
+			t[k] = cty.StringVal(v)
+		}
+		tv := cty.MapValEmpty(cty.String)
+		if len(t) > 0 {
+			tv = cty.MapVal(t)
+		}
+		rules := make([]cty.Value, 0, len(ports))
+		for _, p := range ports {
+			rules = append(rules, cty.ObjectVal(map[string]cty.Value{"port": cty.NumberIntVal(int64(p))}))
+		}
+		return cty.ObjectVal(map[string]cty.Value{
+			"size": cty.NumberIntVal(int64(size)),
+			"tags": tv,
+			"rule": cty.ListVal(rules),
+		})
+	}
+	prior := val(1, map[string]string{"team": "a", "env": "dev"}, 80)
+	type want struct {
+		config cty.Value
+		err    string
+	}
+	cases := map[string]struct {
+		reason string
+		paths  []string
+		config cty.Value
+		want   want
+	}{
+		"TopLevel": {
+			reason: "The top-level attributes should have their prior values.",
+			paths:  []string{"size", "tags"},
+			config: val(2, map[string]string{"team": "b"}, 443),
+			want:   want{config: val(1, map[string]string{"team": "a", "env": "dev"}, 443)},
+		},
+		"MapKeys": {
+			reason: "The ignored map keys should have their prior values and should neither be added nor removed.",
+			paths:  []string{`tags["team"]`, `tags["env"]`, `tags["owner"]`},
+			config: val(2, map[string]string{"team": "b", "owner": "c"}, 443),
+			want:   want{config: val(2, map[string]string{"team": "a", "env": "dev"}, 443)},
+		},
+		"ListElementAttribute": {
+			reason: "The attributes of the list elements should have their prior values.",
+			paths:  []string{"rule[0].port", "rule[1].port"},
+			config: val(2, nil, 443, 8080),
+			want:   want{config: val(2, nil, 80, 8080)},
+		},
+		"UnknownAttribute": {
+			reason: "An error should be returned if the path doesn't exist in the schema.",
+			paths:  []string{"rule[0].name"},
+			config: val(2, nil, 443),
+			want:   want{err: `cannot ignore the changes of "rule[0].name": no attribute "name"`},
+		},
+		"InvalidPath": {
+			reason: "An error should be returned if the path can't be parsed.",
+			paths:  []string{"rule[x]"},
+			config: val(2, nil, 443),
+			want:   want{err: `cannot parse the ignore_changes entry "rule[x]": invalid index "x"`},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ignoreChanges(tc.paths, prior, tc.config)
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if diff := cmp.Diff(tc.want.err, gotErr); diff != "" {
+				t.Fatalf("\n%s\nignoreChanges(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err == "" && !got.RawEquals(tc.want.config) {
+				t.Errorf("\n%s\nignoreChanges(...): want %s, got %s", tc.reason, tc.want.config.GoString(), got.GoString())
+			}
+		})
+	}
+}
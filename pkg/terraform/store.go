@@ -25,13 +25,14 @@ import (
 	"sync"
 	"time"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/mitchellh/go-ps"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/exec"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -124,6 +125,15 @@ type Setup struct {
 	// made available only by this map.
 	ClientMetadata map[string]string
 
+	// Context contains the values resolved by the SetupFn from the
+	// ProviderConfig or the environment, such as the account ID, the project
+	// or the subscription, which are made available to the external-name
+	// functions, e.g. as ".context.project" in the templates of
+	// config.TemplatedStringAsIdentifier, so that they do not need to be
+	// repeated in the spec of the managed resources just to construct the
+	// Terraform IDs.
+	Context map[string]any
+
 	// Scheduler specifies the provider scheduler to be used for the Terraform
 	// workspace being setup. If not set, no scheduler is configured and
 	// the lifecycle of Terraform provider processes will be managed by
@@ -143,7 +153,58 @@ func (s Setup) Map() map[string]any {
 		},
 		"configuration":   s.Configuration,
 		"client_metadata": s.ClientMetadata,
+		"context":         s.Context,
+	}
+}
+
+// WorkspaceKeyFn returns the key identifying the workspace of the given
+// object in the WorkspaceStore. The key is also used as the name of the
+// workspace directory, so it must be safe to use as a file name.
+// The GroupKind of the object is passed separately, as the TypeMeta of the
+// typed objects read through the controller-runtime clients is empty.
+type WorkspaceKeyFn func(gk schema.GroupKind, obj xpresource.Object) string
+
+// UIDAsWorkspaceKey keys the workspaces by the UIDs of the managed resources,
+// i.e., a fresh workspace is used for each managed resource lifecycle. This is
+// the default.
+func UIDAsWorkspaceKey(_ schema.GroupKind, obj xpresource.Object) string {
+	return string(obj.GetUID())
+}
+
+// ExternalNameAsWorkspaceKey keys the workspaces by the kind, the external
+// name and the ProviderConfig of the managed resources, so that the
+// Terraform state of an external resource can be reused when its managed
+// resource is deleted with an orphan deletion policy and then recreated.
+// Objects without an external name fall back to their UIDs. Please note
+// that managed resources of the same kind that share the same external name,
+// ProviderConfig and namespace at the same time would share the same
+// workspace.
+func ExternalNameAsWorkspaceKey(gk schema.GroupKind, obj xpresource.Object) string {
+	en := meta.GetExternalName(obj)
+	if en == "" {
+		return UIDAsWorkspaceKey(gk, obj)
 	}
+	pc := ""
+	if mg, ok := obj.(xpresource.Managed); ok && mg.GetProviderConfigReference() != nil {
+		pc = mg.GetProviderConfigReference().Name
+	}
+	parts := []string{gk.String(), pc, en}
+	// namespaced managed resources do not share workspaces across namespaces
+	if ns := obj.GetNamespace(); ns != "" {
+		parts = append(parts, ns)
+	}
+	hash := sha256.Sum256([]byte(strings.Join(parts, "/")))
+	return fmt.Sprintf("%x", hash)
+}
+
+// groupKind returns the GroupKind of the managed resources configured with
+// the given resource configuration. The short group is used as the group,
+// which is unique among the resources of the provider sharing a store.
+func groupKind(cfg *config.Resource) schema.GroupKind {
+	if cfg == nil {
+		return schema.GroupKind{}
+	}
+	return schema.GroupKind{Group: cfg.ShortGroup, Kind: cfg.Kind}
 }
 
 // WorkspaceStoreOption lets you configure the workspace store.
@@ -174,14 +235,35 @@ func WithDisableInit(disable bool) WorkspaceStoreOption {
 	}
 }
 
+// WithWorkspaceKeyFn configures the strategy used to key the workspaces of
+// managed resources. Workspaces not keyed by the managed resource UIDs are
+// retained when the managed resource is deleted with an orphan deletion
+// policy, so that they can be reused by a later managed resource for the
+// same external resource. Defaults to UIDAsWorkspaceKey.
+func WithWorkspaceKeyFn(fn WorkspaceKeyFn) WorkspaceStoreOption {
+	return func(ws *WorkspaceStore) {
+		ws.keyFn = fn
+	}
+}
+
+// WithWorkspaceExecutor sets the executor of the Terraform CLI commands of
+// the workspaces, e.g. a fake Terraform CLI in the integration tests of the
+// controllers. Defaults to the executor of the OS commands.
+func WithWorkspaceExecutor(e exec.Interface) WorkspaceStoreOption {
+	return func(ws *WorkspaceStore) {
+		ws.executor = e
+	}
+}
+
 // NewWorkspaceStore returns a new WorkspaceStore.
 func NewWorkspaceStore(l logging.Logger, opts ...WorkspaceStoreOption) *WorkspaceStore {
 	ws := &WorkspaceStore{
-		store:    map[types.UID]*Workspace{},
+		store:    map[string]*Workspace{},
 		logger:   l,
 		mu:       sync.Mutex{},
 		fs:       afero.Afero{Fs: afero.NewOsFs()},
 		executor: exec.New(),
+		keyFn:    UIDAsWorkspaceKey,
 	}
 	for _, f := range opts {
 		f(ws)
@@ -199,29 +281,31 @@ type WorkspaceStore struct {
 	// Since there can be multiple calls that add/remove values from the map at
 	// the same time, it has to be safe for concurrency since those operations
 	// cause rehashing in some cases.
-	store                 map[types.UID]*Workspace
+	store                 map[string]*Workspace
 	logger                logging.Logger
 	mu                    sync.Mutex
 	processReportInterval time.Duration
 	fs                    afero.Afero
 	executor              exec.Interface
 	disableInit           bool
+	keyFn                 WorkspaceKeyFn
 }
 
 // Workspace makes sure the Terraform workspace for the given resource is ready
 // to be used and returns the Workspace object configured to work in that
 // workspace folder in the filesystem.
 func (ws *WorkspaceStore) Workspace(ctx context.Context, c resource.SecretClient, tr resource.Terraformed, ts Setup, cfg *config.Resource) (*Workspace, error) { //nolint:gocyclo
-	dir := filepath.Join(ws.fs.GetTempDir(""), string(tr.GetUID()))
+	key := ws.keyFn(groupKind(cfg), tr)
+	dir := filepath.Join(ws.fs.GetTempDir(""), key)
 	if err := ws.fs.MkdirAll(dir, os.ModePerm); err != nil {
 		return nil, errors.Wrap(err, "cannot create directory for workspace")
 	}
 	ws.mu.Lock()
-	w, ok := ws.store[tr.GetUID()]
+	w, ok := ws.store[key]
 	if !ok {
 		l := ws.logger.WithValues("workspace", dir)
-		ws.store[tr.GetUID()] = NewWorkspace(dir, WithLogger(l), WithExecutor(ws.executor), WithFilterFn(ts.filterSensitiveInformation))
-		w = ws.store[tr.GetUID()]
+		ws.store[key] = NewWorkspace(dir, WithLogger(l), WithExecutor(ws.executor), WithFilterFn(ts.filterSensitiveInformation), WithOperationTimeouts(cfg.OperationTimeouts), WithWriteOnlyFields(cfg.Sensitive.WriteOnlyFields))
+		w = ws.store[key]
 	}
 	ws.mu.Unlock()
 	// If there is an ongoing operation, no changes should be made in the
@@ -278,18 +362,30 @@ func (ws *WorkspaceStore) Workspace(ctx context.Context, c resource.SecretClient
 }
 
 // Remove deletes the workspace directory from the filesystem and erases its
-// record from the store.
-func (ws *WorkspaceStore) Remove(obj xpresource.Object) error {
+// record from the store. Workspaces not keyed by UID are retained if the
+// object is a managed resource deleted with an orphan deletion policy.
+func (ws *WorkspaceStore) Remove(obj xpresource.Object, cfg *config.Resource) error {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
-	w, ok := ws.store[obj.GetUID()]
-	if !ok {
-		return nil
+	gk := groupKind(cfg)
+	// A workspace keyed by UID might have been created before an external
+	// name was assigned to the object, so we always remove it.
+	keys := []string{UIDAsWorkspaceKey(gk, obj)}
+	if k := ws.keyFn(gk, obj); k != keys[0] {
+		if mg, ok := obj.(xpresource.Managed); !ok || mg.GetDeletionPolicy() != xpv1.DeletionOrphan {
+			keys = append(keys, k)
+		}
 	}
-	if err := ws.fs.RemoveAll(w.dir); err != nil {
-		return errors.Wrap(err, "cannot remove workspace folder")
+	for _, k := range keys {
+		w, ok := ws.store[k]
+		if !ok {
+			continue
+		}
+		if err := ws.fs.RemoveAll(w.dir); err != nil {
+			return errors.Wrap(err, "cannot remove workspace folder")
+		}
+		delete(ws.store, k)
 	}
-	delete(ws.store, obj.GetUID())
 	return nil
 }
 
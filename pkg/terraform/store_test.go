@@ -0,0 +1,141 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package terraform
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	xpfake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/resource/fake"
+)
+
+func newTerraformed(uid, externalName, providerConfig string, policy xpv1.DeletionPolicy) *fake.Terraformed {
+	tr := &fake.Terraformed{
+		Managed: xpfake.Managed{
+			ProviderConfigReferencer: xpfake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: providerConfig}},
+			Orphanable:               xpfake.Orphanable{Policy: policy},
+		},
+	}
+	tr.SetUID(types.UID(uid))
+	if externalName != "" {
+		meta.SetExternalName(tr, externalName)
+	}
+	return tr
+}
+
+func TestExternalNameAsWorkspaceKey(t *testing.T) {
+	bucket := schema.GroupKind{Group: "s3", Kind: "Bucket"}
+	type args struct {
+		gk schema.GroupKind
+		tr *fake.Terraformed
+	}
+	type want struct {
+		same bool
+	}
+	cases := map[string]struct {
+		a, b args
+		want
+	}{
+		"SameExternalResource": {
+			a: args{gk: bucket, tr: newTerraformed("uid-1", "name", "default", xpv1.DeletionDelete)},
+			b: args{gk: bucket, tr: newTerraformed("uid-2", "name", "default", xpv1.DeletionDelete)},
+			want: want{
+				same: true,
+			},
+		},
+		"DifferentKind": {
+			a: args{gk: bucket, tr: newTerraformed("uid-1", "name", "default", xpv1.DeletionDelete)},
+			b: args{gk: schema.GroupKind{Group: "s3", Kind: "BucketPolicy"}, tr: newTerraformed("uid-2", "name", "default", xpv1.DeletionDelete)},
+		},
+		"DifferentProviderConfig": {
+			a: args{gk: bucket, tr: newTerraformed("uid-1", "name", "default", xpv1.DeletionDelete)},
+			b: args{gk: bucket, tr: newTerraformed("uid-2", "name", "other", xpv1.DeletionDelete)},
+		},
+		"DifferentNamespace": {
+			a: args{gk: bucket, tr: func() *fake.Terraformed {
+				tr := newTerraformed("uid-1", "name", "default", xpv1.DeletionDelete)
+				tr.SetNamespace("team-a")
+				return tr
+			}()},
+			b: args{gk: bucket, tr: func() *fake.Terraformed {
+				tr := newTerraformed("uid-2", "name", "default", xpv1.DeletionDelete)
+				tr.SetNamespace("team-b")
+				return tr
+			}()},
+		},
+		"NoExternalName": {
+			a: args{gk: bucket, tr: newTerraformed("uid-1", "", "default", xpv1.DeletionDelete)},
+			b: args{gk: bucket, tr: newTerraformed("uid-2", "", "default", xpv1.DeletionDelete)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ExternalNameAsWorkspaceKey(tc.a.gk, tc.a.tr) == ExternalNameAsWorkspaceKey(tc.b.gk, tc.b.tr)
+			if diff := cmp.Diff(tc.want.same, got); diff != "" {
+				t.Errorf("ExternalNameAsWorkspaceKey(...): -want same, +got same: %s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkspaceStoreRemove(t *testing.T) {
+	type args struct {
+		keyFn WorkspaceKeyFn
+		tr    *fake.Terraformed
+	}
+	type want struct {
+		retained bool
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"UIDKeyOrphaned": {
+			args: args{
+				keyFn: UIDAsWorkspaceKey,
+				tr:    newTerraformed("uid-1", "name", "default", xpv1.DeletionOrphan),
+			},
+		},
+		"ExternalNameKeyDeleted": {
+			args: args{
+				keyFn: ExternalNameAsWorkspaceKey,
+				tr:    newTerraformed("uid-1", "name", "default", xpv1.DeletionDelete),
+			},
+		},
+		"ExternalNameKeyOrphaned": {
+			args: args{
+				keyFn: ExternalNameAsWorkspaceKey,
+				tr:    newTerraformed("uid-1", "name", "default", xpv1.DeletionOrphan),
+			},
+			want: want{
+				retained: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ws := NewWorkspaceStore(logging.NewNopLogger(), WithFs(afero.NewMemMapFs()), WithWorkspaceKeyFn(tc.args.keyFn))
+			cfg := &config.Resource{ShortGroup: "s3", Kind: "Bucket"}
+			key := tc.args.keyFn(groupKind(cfg), tc.args.tr)
+			ws.store[key] = NewWorkspace(key)
+			if err := ws.Remove(tc.args.tr, cfg); err != nil {
+				t.Fatalf("Remove(...): unexpected error: %v", err)
+			}
+			_, got := ws.store[key]
+			if diff := cmp.Diff(tc.want.retained, got); diff != "" {
+				t.Errorf("Remove(...): -want retained, +got retained: %s", diff)
+			}
+		})
+	}
+}
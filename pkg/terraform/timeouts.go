@@ -17,6 +17,8 @@
 package terraform
 
 import (
+	"time"
+
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
 	"github.com/upbound/upjet/pkg/config"
@@ -66,6 +68,24 @@ func (ts timeouts) asMetadata() map[string]any {
 	return meta
 }
 
+// asyncTimeout returns the timeout of an asynchronous operation which first
+// refreshes the resource and then runs the longest of the operations with
+// the given timeouts. It is never shorter than the default asynchronous
+// timeout so that the configured Terraform timeouts take effect before the
+// operation is cancelled.
+func (ts timeouts) asyncTimeout(ops ...time.Duration) time.Duration {
+	var op time.Duration
+	for _, o := range ops {
+		if o > op {
+			op = o
+		}
+	}
+	if t := ts.Read + op; t > defaultAsyncTimeout {
+		return t
+	}
+	return defaultAsyncTimeout
+}
+
 func insertTimeoutsMeta(existingMeta []byte, to timeouts) ([]byte, error) {
 	customTimeouts := to.asMetadata()
 	if len(customTimeouts) == 0 {
@@ -227,3 +227,53 @@ func TestInsertTimeoutsMeta(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeoutsAsyncTimeout(t *testing.T) {
+	type args struct {
+		to  timeouts
+		ops []time.Duration
+	}
+	type want struct {
+		out time.Duration
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NoTimeouts": {
+			want: want{
+				out: defaultAsyncTimeout,
+			},
+		},
+		"ShorterThanDefault": {
+			args: args{
+				to: timeouts{
+					Read: time.Minute,
+				},
+				ops: []time.Duration{10 * time.Minute},
+			},
+			want: want{
+				out: defaultAsyncTimeout,
+			},
+		},
+		"LongerThanDefault": {
+			args: args{
+				to: timeouts{
+					Read: 10 * time.Minute,
+				},
+				ops: []time.Duration{time.Hour, 2 * time.Hour},
+			},
+			want: want{
+				out: 2*time.Hour + 10*time.Minute,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.args.to.asyncTimeout(tc.args.ops...)
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Errorf("\n%s\nasyncTimeout(...): -want out, +got out:\n%s", name, diff)
+			}
+		})
+	}
+}
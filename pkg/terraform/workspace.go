@@ -29,6 +29,7 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
+	"github.com/upbound/upjet/pkg/config"
 	"github.com/upbound/upjet/pkg/metrics"
 	"github.com/upbound/upjet/pkg/resource"
 	"github.com/upbound/upjet/pkg/resource/json"
@@ -109,6 +110,23 @@ func WithProviderInUse(providerInUse InUse) WorkspaceOption {
 	}
 }
 
+// WithOperationTimeouts configures the Terraform operation timeouts of the
+// resource so that the asynchronous operations are not cancelled before
+// they time out.
+func WithOperationTimeouts(to config.OperationTimeouts) WorkspaceOption {
+	return func(w *Workspace) {
+		w.timeouts = timeouts(to)
+	}
+}
+
+// WithWriteOnlyFields configures the top-level Terraform arguments of the
+// resource to be stripped from the state after every Terraform operation.
+func WithWriteOnlyFields(fields []string) WorkspaceOption {
+	return func(w *Workspace) {
+		w.writeOnlyFields = fields
+	}
+}
+
 // NewWorkspace returns a new Workspace object that operates in the given
 // directory.
 func NewWorkspace(dir string, opts ...WorkspaceOption) *Workspace {
@@ -149,7 +167,9 @@ type Workspace struct {
 	fs            afero.Afero
 	mu            *sync.Mutex
 
-	filterFn func(string) string
+	filterFn        func(string) string
+	timeouts        timeouts
+	writeOnlyFields []string
 
 	terraformID string
 }
@@ -177,7 +197,7 @@ func (w *Workspace) ApplyAsync(callback CallbackFn) error {
 	if !w.LastOperation.MarkStart("apply") {
 		return errors.Errorf("%s operation that started at %s is still running", w.LastOperation.Type, w.LastOperation.StartTime().String())
 	}
-	ctx, cancel := context.WithDeadline(context.TODO(), w.LastOperation.StartTime().Add(defaultAsyncTimeout))
+	ctx, cancel := context.WithDeadline(context.TODO(), w.LastOperation.StartTime().Add(w.timeouts.asyncTimeout(w.timeouts.Create, w.timeouts.Update)))
 	w.providerInUse.Increment()
 	go func() {
 		defer cancel()
@@ -211,13 +231,9 @@ func (w *Workspace) Apply(ctx context.Context) (ApplyResult, error) {
 	if err != nil {
 		return ApplyResult{}, tferrors.NewApplyFailed(out)
 	}
-	raw, err := w.fs.ReadFile(filepath.Join(w.dir, "terraform.tfstate"))
+	s, err := w.readState()
 	if err != nil {
-		return ApplyResult{}, errors.Wrap(err, "cannot read terraform state file")
-	}
-	s := &json.StateV4{}
-	if err := json.JSParser.Unmarshal(raw, s); err != nil {
-		return ApplyResult{}, errors.Wrap(err, "cannot unmarshal tfstate file")
+		return ApplyResult{}, err
 	}
 	return ApplyResult{State: s}, nil
 }
@@ -236,7 +252,7 @@ func (w *Workspace) DestroyAsync(callback CallbackFn) error {
 	case !w.LastOperation.MarkStart("destroy"):
 		return errors.Errorf("%s operation that started at %s is still running", w.LastOperation.Type, w.LastOperation.StartTime().String())
 	}
-	ctx, cancel := context.WithDeadline(context.TODO(), w.LastOperation.StartTime().Add(defaultAsyncTimeout))
+	ctx, cancel := context.WithDeadline(context.TODO(), w.LastOperation.StartTime().Add(w.timeouts.asyncTimeout(w.timeouts.Delete)))
 	w.providerInUse.Increment()
 	go func() {
 		defer cancel()
@@ -291,13 +307,9 @@ func (w *Workspace) Refresh(ctx context.Context) (RefreshResult, error) {
 	if err != nil {
 		return RefreshResult{}, tferrors.NewRefreshFailed(out)
 	}
-	raw, err := w.fs.ReadFile(filepath.Join(w.dir, "terraform.tfstate"))
+	s, err := w.readState()
 	if err != nil {
-		return RefreshResult{}, errors.Wrap(err, "cannot read terraform state file")
-	}
-	s := &json.StateV4{}
-	if err := json.JSParser.Unmarshal(raw, s); err != nil {
-		return RefreshResult{}, errors.Wrap(err, "cannot unmarshal tfstate file")
+		return RefreshResult{}, err
 	}
 	return RefreshResult{
 		Exists: s.GetAttributes() != nil,
@@ -392,13 +404,9 @@ func (w *Workspace) Import(ctx context.Context, tr resource.Terraformed) (Import
 		}
 		return ImportResult{}, errors.WithMessage(errors.New("import failed"), w.filterFn(string(out)))
 	}
-	raw, err := w.fs.ReadFile(filepath.Join(w.dir, "terraform.tfstate"))
+	s, err := w.readState()
 	if err != nil {
-		return ImportResult{}, errors.Wrap(err, "cannot read terraform state file")
-	}
-	s := &json.StateV4{}
-	if err := json.JSParser.Unmarshal(raw, s); err != nil {
-		return ImportResult{}, errors.Wrap(err, "cannot unmarshal tfstate file")
+		return ImportResult{}, err
 	}
 	return ImportResult{
 		Exists: s.GetAttributes() != nil,
@@ -406,6 +414,37 @@ func (w *Workspace) Import(ctx context.Context, tr resource.Terraformed) (Import
 	}, nil
 }
 
+// readState reads the Terraform state file of the workspace. If the resource
+// has write-only fields, they are stripped from the state and the state file
+// is rewritten, so that their values are not persisted.
+func (w *Workspace) readState() (*json.StateV4, error) {
+	raw, err := w.fs.ReadFile(filepath.Join(w.dir, "terraform.tfstate"))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read terraform state file")
+	}
+	s := &json.StateV4{}
+	if err := json.JSParser.Unmarshal(raw, s); err != nil {
+		return nil, errors.Wrap(err, "cannot unmarshal tfstate file")
+	}
+	if len(w.writeOnlyFields) == 0 || s.GetAttributes() == nil {
+		return s, nil
+	}
+	attr := map[string]any{}
+	if err := json.JSParser.Unmarshal(s.GetAttributes(), &attr); err != nil {
+		return nil, errors.Wrap(err, "cannot unmarshal state attributes")
+	}
+	for _, f := range w.writeOnlyFields {
+		delete(attr, f)
+	}
+	if s.Resources[0].Instances[0].AttributesRaw, err = json.JSParser.Marshal(attr); err != nil {
+		return nil, errors.Wrap(err, "cannot marshal state attributes")
+	}
+	if raw, err = json.JSParser.Marshal(s); err != nil {
+		return nil, errors.Wrap(err, "cannot marshal state object")
+	}
+	return s, errors.Wrap(w.fs.WriteFile(filepath.Join(w.dir, "terraform.tfstate"), raw, 0600), "cannot write terraform state file")
+}
+
 func (w *Workspace) runTF(ctx context.Context, execMode ExecMode, args ...string) ([]byte, error) {
 	if len(args) < 1 {
 		return nil, errors.New("args cannot be empty")
@@ -271,6 +271,42 @@ func TestWorkspaceRefresh(t *testing.T) {
 	}
 }
 
+func TestWorkspaceRefreshWriteOnlyFields(t *testing.T) {
+	raw := `{"version":4,"terraform_version":"1.0.10","serial":3,"lineage":"very-cool-lineage","outputs":{},"resources":[{"mode":"managed","type":"test","name":"test","provider":"provider","instances":[{"schema_version":0,"attributes":{"id":"some-id","name":"some-name","password":"secret"}}]}]}`
+	w := NewWorkspace(directory, WithExecutor(&testingexec.FakeExec{DisableScripts: true}), WithAferoFs(fs),
+		WithFilterFn(filterFn), WithWriteOnlyFields([]string{"password"}))
+	if err := w.fs.WriteFile(directory+"terraform.tfstate", []byte(raw), 0777); err != nil {
+		panic(err)
+	}
+	r, err := w.Refresh(context.TODO())
+	if err != nil {
+		t.Fatalf("Refresh(...): unexpected error: %s", err.Error())
+	}
+	want := map[string]any{"id": "some-id", "name": "some-name"}
+	got := map[string]any{}
+	if err := json.JSParser.Unmarshal(r.State.GetAttributes(), &got); err != nil {
+		t.Fatalf("cannot unmarshal state attributes: %s", err.Error())
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nRefresh(...): -want attributes, +got attributes:\n%s", diff)
+	}
+	stored, err := w.fs.ReadFile(directory + "terraform.tfstate")
+	if err != nil {
+		t.Fatalf("cannot read terraform state file: %s", err.Error())
+	}
+	s := &json.StateV4{}
+	if err := json.JSParser.Unmarshal(stored, s); err != nil {
+		t.Fatalf("cannot unmarshal tfstate file: %s", err.Error())
+	}
+	got = map[string]any{}
+	if err := json.JSParser.Unmarshal(s.GetAttributes(), &got); err != nil {
+		t.Fatalf("cannot unmarshal stored state attributes: %s", err.Error())
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nRefresh(...): -want stored attributes, +got stored attributes:\n%s", diff)
+	}
+}
+
 func TestWorkspacePlan(t *testing.T) {
 	type args struct {
 		w *Workspace
@@ -5,15 +5,19 @@ Copyright 2021 Upbound Inc.
 package types
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"go/token"
 	"go/types"
+	"reflect"
 	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	twtypes "github.com/muvaf/typewriter/pkg/types"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/version"
 
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 
@@ -24,6 +28,10 @@ const (
 	wildcard = "*"
 
 	emptyStruct = "struct{}"
+
+	// hashLength is the length of the hash suffix of the truncated type
+	// names.
+	hashLength = 8
 )
 
 // Generated is a struct that holds generated types
@@ -31,10 +39,61 @@ type Generated struct {
 	Types    []*types.Named
 	Comments twtypes.Comments
 
-	ForProviderType *types.Named
-	AtProviderType  *types.Named
+	ForProviderType  *types.Named
+	InitProviderType *types.Named
+	AtProviderType   *types.Named
 
 	ValidationRules string
+
+	// CollapsedPaths are the Terraform field paths of the blocks that have
+	// been collapsed into runtime.RawExtension fields because they are nested
+	// deeper than the configured maximum block nesting depth.
+	CollapsedPaths []string
+
+	// CollapsedObservationPaths are the Terraform field paths of the
+	// computed-only blocks that have been collapsed into
+	// runtime.RawExtension fields as configured by
+	// config.Resource.CollapseObservationBlocks.
+	CollapsedObservationPaths []string
+
+	// EmbeddedSingletonLists are the Terraform field paths of the singleton
+	// list blocks that have been generated as embedded objects.
+	EmbeddedSingletonLists []string
+
+	// ResolvedTypeNameCollisions maps the Terraform paths of the blocks whose
+	// type names collided with the existing types in the package to the
+	// names of their types without the "Parameters" suffix, which can be
+	// pinned with config.Resource.OverrideFieldNames.
+	ResolvedTypeNameCollisions map[string]string
+
+	// TruncatedTypeNames maps the Terraform paths of the blocks whose type
+	// names exceeded the maximum length to the names of their truncated
+	// types without the "Parameters" suffix.
+	TruncatedTypeNames map[string]string
+
+	// TypeNames maps the Terraform paths of the blocks to the names of their
+	// types without the "Parameters", "InitParameters" and "Observation"
+	// suffixes, which can be pinned with a TypeNameMapping.
+	TypeNames map[string]string
+
+	// SharedBlocks maps the names of the shared blocks built with
+	// BuildSharedBlocks to their types.
+	SharedBlocks map[string]*SharedBlock
+
+	// PrinterColumns are the printer columns of the CRD, which are either
+	// the configured ones or the ones derived from the state and the
+	// location attributes of the resource.
+	PrinterColumns []config.PrinterColumn
+
+	// UnmatchedIgnoredFields are the late-initialization ignored field
+	// patterns that don't match any field of the schema, e.g. the stale
+	// entries of the removed fields.
+	UnmatchedIgnoredFields []string
+
+	// CompositionFieldPaths maps the Terraform paths of the fields of the
+	// composition hints to the paths of their fields in the managed
+	// resource, e.g. "spec.forProvider.region".
+	CompositionFieldPaths map[string]string
 }
 
 // Builder is used to generate Go type equivalence of given Terraform schema.
@@ -44,42 +103,280 @@ type Builder struct {
 	genTypes        []*types.Named
 	comments        twtypes.Comments
 	validationRules string
+	collapsedPaths  []string
+	// collapsedObsPaths are the Terraform paths of the computed-only blocks
+	// collapsed into runtime.RawExtension fields.
+	collapsedObsPaths []string
+	// embeddedLists is the set of the Terraform paths of the singleton list
+	// blocks to be generated as embedded objects.
+	embeddedLists map[string]struct{}
+	// matchedIgnoredFields is the set of late-initialization ignored field
+	// patterns that matched at least one field of the schema.
+	matchedIgnoredFields map[string]struct{}
+	// strictIgnoredFields fails the build if any of the late-initialization
+	// ignored field patterns doesn't match a field of the schema.
+	strictIgnoredFields bool
+	// obsPruning configures the observation fields to be pruned.
+	obsPruning config.ObservationPruning
+	// obsFieldPaths is the set of the Terraform paths of the observation
+	// fields, including the pruned ones.
+	obsFieldPaths map[string]struct{}
+	// prunedCRDPaths are the CRD paths of the pruned observation fields.
+	prunedCRDPaths []string
+	// matchedValidators is the set of the field validators applied to a
+	// spec field.
+	matchedValidators map[string]struct{}
+	// topLevelParams maps the top-level Terraform arguments to the names of
+	// their spec fields.
+	topLevelParams map[string]string
+	// compositionFieldPaths maps the Terraform paths of the composition hint
+	// fields to their paths in the managed resource.
+	compositionFieldPaths map[string]string
+	// resolvedCollisions maps the Terraform paths of the blocks with
+	// auto-resolved type name collisions to their type names.
+	resolvedCollisions map[string]string
+	// matchedOverrideFieldNames is the set of the Terraform paths of the
+	// blocks whose type names have been overridden.
+	matchedOverrideFieldNames map[string]struct{}
+	// truncatedNames maps the Terraform paths of the blocks with truncated
+	// type names to their type names.
+	truncatedNames map[string]string
+	// maxTypeNameLength is the maximum length of the type names of the
+	// blocks. Zero disables their truncation.
+	maxTypeNameLength int
+	// topLevelObs maps the Terraform names of the top-level string
+	// observation fields to the names of their fields.
+	topLevelObs map[string]string
+	// sharedBlocks maps the names of the shared blocks to their types.
+	sharedBlocks map[string]*SharedBlock
+	// matchedSharedBlocks is the set of the Terraform paths of the blocks
+	// whose shared types have been used.
+	matchedSharedBlocks map[string]struct{}
+	// pinned are the type names pinned in the package.
+	pinned *PinnedTypeNames
+	// typeNames maps the Terraform paths of the blocks to the names of
+	// their types.
+	typeNames map[string]string
+}
+
+// BuilderOption configures a Builder.
+type BuilderOption func(*Builder)
+
+// WithPinnedTypeNames configures the type names pinned in the package of
+// the Builder, which are used for the blocks they're mapped to instead of
+// the generated ones.
+func WithPinnedTypeNames(p *PinnedTypeNames) BuilderOption {
+	return func(g *Builder) {
+		g.pinned = p
+	}
+}
+
+// WithMaxTypeNameLength configures the maximum length of the type names
+// generated for the blocks, including their suffixes. The longer names are
+// truncated and suffixed with a hash of the original names.
+func WithMaxTypeNameLength(l int) BuilderOption {
+	return func(g *Builder) {
+		g.maxTypeNameLength = l
+	}
+}
+
+// WithStrictIgnoredFields configures whether the late-initialization ignored
+// field patterns that don't match any field of the schema fail the build
+// instead of being reported with Generated.UnmatchedIgnoredFields.
+func WithStrictIgnoredFields(strict bool) BuilderOption {
+	return func(g *Builder) {
+		g.strictIgnoredFields = strict
+	}
+}
+
+// WithSharedBlocks configures the types of the shared blocks, which are used
+// for the blocks of the resources configured to use them instead of the
+// generated ones.
+func WithSharedBlocks(b map[string]*SharedBlock) BuilderOption {
+	return func(g *Builder) {
+		g.sharedBlocks = b
+	}
+}
+
+// SharedBlock holds the types of a block shared by the resources.
+type SharedBlock struct {
+	Parameters     *types.Named
+	InitParameters *types.Named
+	Observation    *types.Named
+
+	// fingerprint is the config.SchemaFingerprint of the block schema.
+	fingerprint string
+}
+
+// BuildSharedBlocks builds the types of the shared blocks with the given
+// schemas in the package of the Builder. The types of a shared block named
+// "Endpoint" are "EndpointParameters", "EndpointInitParameters" and
+// "EndpointObservation".
+func (g *Builder) BuildSharedBlocks(blocks map[string]*schema.Resource) (Generated, error) {
+	names := make([]string, 0, len(blocks))
+	for n := range blocks {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	shared := make(map[string]*SharedBlock, len(names))
+	for _, n := range names {
+		res := blocks[n]
+		if hasSensitiveField(res) {
+			return Generated{}, errors.Errorf("shared block %q cannot have sensitive fields", n)
+		}
+		cfg := &config.Resource{Name: n, TerraformResource: res}
+		// the shared blocks are built as nested blocks, whose required
+		// arguments are required.
+		p, i, o, err := g.buildResource(res, cfg, []string{n}, nil, false, n)
+		if err != nil {
+			return Generated{}, errors.Wrapf(err, "cannot build the types of shared block %q", n)
+		}
+		shared[n] = &SharedBlock{Parameters: p, InitParameters: i, Observation: o, fingerprint: config.SchemaFingerprint(res)}
+	}
+	return Generated{
+		Types:        g.genTypes,
+		Comments:     g.comments,
+		SharedBlocks: shared,
+	}, nil
+}
+
+// sharedBlockTypes returns the types of the shared block with the given name
+// for the given block field after checking that the schema of the block is
+// identical to the shared one.
+func (g *Builder) sharedBlockTypes(f *Field, n string, res *schema.Resource) (*types.Named, *types.Named, *types.Named, error) {
+	p := fieldPath(f.TerraformPaths[:len(f.TerraformPaths)-1])
+	sb, ok := g.sharedBlocks[n]
+	if !ok {
+		return nil, nil, nil, errors.Errorf("shared block %q of block %s is not found", n, p)
+	}
+	if sb.fingerprint != config.SchemaFingerprint(res) {
+		return nil, nil, nil, errors.Errorf("schema of block %s is not identical to the schema of shared block %q", p, n)
+	}
+	if g.matchedSharedBlocks == nil {
+		g.matchedSharedBlocks = map[string]struct{}{}
+	}
+	g.matchedSharedBlocks[p] = struct{}{}
+	return sb.Parameters, sb.InitParameters, sb.Observation, nil
+}
+
+func hasSensitiveField(res *schema.Resource) bool {
+	for _, sch := range res.Schema {
+		if sch.Sensitive {
+			return true
+		}
+		if er, ok := sch.Elem.(*schema.Resource); ok && hasSensitiveField(er) {
+			return true
+		}
+	}
+	return false
 }
 
 // NewBuilder returns a new Builder.
-func NewBuilder(pkg *types.Package) *Builder {
-	return &Builder{
+func NewBuilder(pkg *types.Package, opts ...BuilderOption) *Builder {
+	g := &Builder{
 		Package:  pkg,
 		comments: twtypes.Comments{},
 	}
+	for _, o := range opts {
+		o(g)
+	}
+	return g
 }
 
 // Build returns parameters and observation types built out of Terraform schema.
 func (g *Builder) Build(cfg *config.Resource) (Generated, error) {
-	fp, ap, err := g.buildResource(cfg.TerraformResource, cfg, nil, nil, false, cfg.Kind)
+	if err := cfg.ApplySchemaElementOptions(); err != nil {
+		return Generated{}, errors.Wrapf(err, "cannot build the Types")
+	}
+	embedded := cfg.EmbeddedSingletonLists(g.Package.Name())
+	g.embeddedLists = make(map[string]struct{}, len(embedded))
+	for _, p := range embedded {
+		g.embeddedLists[p] = struct{}{}
+	}
+	if err := validateWriteOnlyFields(cfg); err != nil {
+		return Generated{}, errors.Wrapf(err, "cannot build the Types")
+	}
+	if err := validateHiddenParameters(cfg); err != nil {
+		return Generated{}, errors.Wrapf(err, "cannot build the Types")
+	}
+	if err := validateFieldMaturities(cfg); err != nil {
+		return Generated{}, errors.Wrapf(err, "cannot build the Types")
+	}
+	g.obsPruning = cfg.ObservationPruning
+	g.obsFieldPaths = map[string]struct{}{}
+	fp, ip, ap, err := g.buildResource(cfg.TerraformResource, cfg, nil, nil, false, cfg.Kind)
+	var unmatched []string
+	if err == nil {
+		unmatched = g.unmatchedIgnoredFields(cfg)
+		if len(unmatched) > 0 && g.strictIgnoredFields {
+			return Generated{}, errors.Wrapf(errors.Errorf("late-initialization ignored field %q does not match any field in the schema", unmatched[0]), "cannot build the Types")
+		}
+		if err := g.validateObservationPruning(cfg); err != nil {
+			return Generated{}, errors.Wrapf(err, "cannot build the Types")
+		}
+		if err := g.addExclusivityRules(cfg); err != nil {
+			return Generated{}, errors.Wrapf(err, "cannot build the Types")
+		}
+		for p := range cfg.OverrideFieldNames {
+			if _, ok := g.matchedOverrideFieldNames[p]; !ok {
+				return Generated{}, errors.Wrapf(errors.Errorf("overridden field name %q does not match any block", p), "cannot build the Types")
+			}
+		}
+		for p := range cfg.SharedBlocks {
+			if _, ok := g.matchedSharedBlocks[p]; !ok {
+				return Generated{}, errors.Wrapf(errors.Errorf("shared block %q does not match any block", p), "cannot build the Types")
+			}
+		}
+		for _, p := range cfg.CompositionHints.Fields {
+			if _, ok := g.compositionFieldPaths[p]; !ok {
+				return Generated{}, errors.Wrapf(errors.Errorf("composition field %q does not match any field", p), "cannot build the Types")
+			}
+		}
+	}
 	return Generated{
-		Types:           g.genTypes,
-		Comments:        g.comments,
-		ForProviderType: fp,
-		AtProviderType:  ap,
-		ValidationRules: g.validationRules,
+		Types:            g.genTypes,
+		Comments:         g.comments,
+		ForProviderType:  fp,
+		InitProviderType: ip,
+		AtProviderType:   ap,
+		ValidationRules:  g.validationRules,
+		CollapsedPaths:   g.collapsedPaths,
+
+		CollapsedObservationPaths: g.collapsedObsPaths,
+
+		EmbeddedSingletonLists: embedded,
+		CompositionFieldPaths:  g.compositionFieldPaths,
+
+		ResolvedTypeNameCollisions: g.resolvedCollisions,
+		TruncatedTypeNames:         g.truncatedNames,
+		TypeNames:                  g.typeNames,
+		PrinterColumns:             g.printerColumns(cfg),
+		UnmatchedIgnoredFields:     unmatched,
 	}, errors.Wrapf(err, "cannot build the Types")
 }
 
-func (g *Builder) buildResource(res *schema.Resource, cfg *config.Resource, tfPath []string, xpPath []string, asBlocksMode bool, names ...string) (*types.Named, *types.Named, error) { //nolint:gocyclo
+func (g *Builder) buildResource(res *schema.Resource, cfg *config.Resource, tfPath []string, xpPath []string, asBlocksMode bool, names ...string) (*types.Named, *types.Named, *types.Named, error) { //nolint:gocyclo
 	// NOTE(muvaf): There can be fields in the same CRD with same name but in
 	// different types. Since we generate the type using the field name, there
 	// can be collisions. In order to be able to generate unique names consistently,
 	// we need to process all fields in the same order all the time.
 	keys := sortedKeys(res.Schema)
 
-	typeNames, err := NewTypeNames(names, g.Package)
+	typeNames, err := g.newTypeNames(cfg, tfPath, names)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	r := &resource{}
+	r := &resource{dataSource: cfg.DataSource}
 	for _, snakeFieldName := range keys {
+		if d, ok := cfg.DeprecatedFields[fieldPath(append(tfPath, snakeFieldName))]; ok && d.RemovedIn != "" &&
+			version.CompareKubeAwareVersionStrings(g.Package.Name(), d.RemovedIn) >= 0 {
+			// the deprecated field has been removed in this API version
+			continue
+		}
+		if !g.alphaFieldServed(cfg, fieldPath(append(tfPath, snakeFieldName))) {
+			continue
+		}
 		var reference *config.Reference
 		ref, ok := cfg.References[fieldPath(append(tfPath, snakeFieldName))]
 		// if a reference is configured and the field does not belong to status
@@ -87,37 +384,456 @@ func (g *Builder) buildResource(res *schema.Resource, cfg *config.Resource, tfPa
 			reference = &ref
 		}
 
+		sch := res.Schema[snakeFieldName]
+		if _, ok := sch.Elem.(*schema.Resource); ok && sch.Sensitive && !IsObservation(sch) {
+			sch = sensitiveBlock(sch)
+		}
 		var f *Field
 		switch {
-		case res.Schema[snakeFieldName].Sensitive:
+		case sch.Sensitive, len(tfPath) == 0 && cfg.Sensitive.IsWriteOnly(snakeFieldName):
 			var drop bool
-			f, drop, err = NewSensitiveField(g, cfg, r, res.Schema[snakeFieldName], snakeFieldName, tfPath, xpPath, names, asBlocksMode)
+			f, drop, err = NewSensitiveField(g, cfg, r, sch, snakeFieldName, tfPath, xpPath, names, asBlocksMode)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
 			}
 			if drop {
 				continue
 			}
 		case reference != nil:
-			f, err = NewReferenceField(g, cfg, r, res.Schema[snakeFieldName], reference, snakeFieldName, tfPath, xpPath, names, asBlocksMode)
+			f, err = NewReferenceField(g, cfg, r, sch, reference, snakeFieldName, tfPath, xpPath, names, asBlocksMode)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
 			}
 		default:
-			f, err = NewField(g, cfg, r, res.Schema[snakeFieldName], snakeFieldName, tfPath, xpPath, names, asBlocksMode)
+			f, err = NewField(g, cfg, r, sch, snakeFieldName, tfPath, xpPath, names, asBlocksMode)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
 			}
 		}
 		f.AddToResource(g, r, typeNames)
 	}
 
-	paramType, obsType := g.AddToBuilder(typeNames, r)
-	return paramType, obsType, nil
+	paramType, initType, obsType := g.AddToBuilder(typeNames, r)
+	g.addConstraintRules(cfg, res, tfPath, typeNames, r)
+	g.addDocsComments(cfg, tfPath, typeNames)
+	return paramType, initType, obsType, nil
+}
+
+// addDocsComments links the generated types of the block at the given
+// Terraform path to its section in the Terraform registry documentation of
+// the resource. The top-level types are linked to the documentation page
+// itself.
+func (g *Builder) addDocsComments(cfg *config.Resource, tfPath []string, typeNames *TypeNames) {
+	if cfg.DocsURL == "" {
+		return
+	}
+	u := cfg.DocsURL
+	for i := len(tfPath) - 1; i >= 0; i-- {
+		if tfPath[i] != wildcard {
+			u = fmt.Sprintf("%s#%s", u, tfPath[i])
+			break
+		}
+	}
+	c := fmt.Sprintf("// Terraform documentation: %s", u)
+	for _, tn := range []*types.TypeName{typeNames.ParameterTypeName, typeNames.InitTypeName, typeNames.ObservationTypeName} {
+		g.addTypeComment(tn, c)
+	}
+}
+
+// addTypeComment prepends the given comment to the comment of the given
+// type.
+func (g *Builder) addTypeComment(tn *types.TypeName, c string) {
+	if e := g.comments[twtypes.QualifiedTypePath(tn)]; e != "" {
+		c += "\n" + e
+	}
+	g.comments.AddTypeComment(tn, c)
+}
+
+// addConstraintRules adds the CEL rules of the ExactlyOneOf, AtLeastOneOf,
+// ConflictsWith and RequiredWith constraints among the arguments of the
+// block at the given Terraform path unless they're disabled for the
+// resource. The constraints spanning multiple blocks or including the
+// arguments not in the spec are skipped.
+func (g *Builder) addConstraintRules(cfg *config.Resource, res *schema.Resource, tfPath []string, typeNames *TypeNames, r *resource) {
+	if cfg.DisableConstraintRules {
+		return
+	}
+	g.addGroupRules(res, tfPath, typeNames, r)
+	g.addPairRules(res, tfPath, typeNames, r)
+}
+
+// addGroupRules adds the CEL rules requiring exactly one, or at least one,
+// of the arguments of the ExactlyOneOf and AtLeastOneOf constraints to be
+// set.
+func (g *Builder) addGroupRules(res *schema.Resource, tfPath []string, typeNames *TypeNames, r *resource) {
+	for _, c := range []struct {
+		keys   func(sch *schema.Schema) []string
+		op     string
+		phrase string
+	}{
+		{keys: func(sch *schema.Schema) []string { return sch.ExactlyOneOf }, op: "== 1", phrase: "exactly one"},
+		{keys: func(sch *schema.Schema) []string { return sch.AtLeastOneOf }, op: ">= 1", phrase: "at least one"},
+	} {
+		for _, group := range constraintGroups(res, tfPath, func(_ string, sch *schema.Schema) []string {
+			group := append([]string{}, c.keys(sch)...)
+			sort.Strings(group)
+			return group
+		}) {
+			terms := make([]string, 0, len(group))
+			names := make([]string, 0, len(group))
+			for _, m := range group {
+				p, n, ok := r.presence(m, len(tfPath) == 0)
+				if !ok {
+					terms = nil
+					break
+				}
+				terms = append(terms, fmt.Sprintf("(%s ? 1 : 0)", p))
+				names = append(names, n)
+			}
+			if len(terms) == 0 {
+				continue
+			}
+			g.addConstraintRule(tfPath, typeNames, r, strings.Join(terms, " + ")+" "+c.op, fmt.Sprintf("%s of %s must be set", c.phrase, strings.Join(names, ", ")), true)
+		}
+	}
+}
+
+// addPairRules adds the CEL rules rejecting the arguments of the
+// ConflictsWith constraints set together, and the ones requiring the
+// arguments of the RequiredWith constraints to be set if the arguments
+// declaring them are set. The arguments are validated pairwise with the
+// arguments declaring the constraints.
+func (g *Builder) addPairRules(res *schema.Resource, tfPath []string, typeNames *TypeNames, r *resource) {
+	for _, c := range []struct {
+		keys     func(sch *schema.Schema) []string
+		rule     string
+		message  string
+		required bool
+		ordered  bool
+	}{
+		{keys: func(sch *schema.Schema) []string { return sch.ConflictsWith }, rule: "!(%s) || !(%s)", message: "%s and %s are mutually exclusive"},
+		{keys: func(sch *schema.Schema) []string { return sch.RequiredWith }, rule: "!(%s) || (%s)", message: "%[2]s is required when %[1]s is set", required: true, ordered: true},
+	} {
+		prefix := siblingPrefix(tfPath)
+		seen := map[string]struct{}{}
+		for _, pair := range constraintGroups(res, tfPath, func(k string, sch *schema.Schema) []string {
+			// the constraints with the arguments of the other blocks are
+			// skipped.
+			var group []string
+			for _, o := range c.keys(sch) {
+				if segments := strings.Split(o, "."); strings.Join(segments[:len(segments)-1], ".") == strings.Join(prefix, ".") && segments[len(segments)-1] != k {
+					group = append(group, o)
+				}
+			}
+			if len(group) == 0 {
+				return nil
+			}
+			return append([]string{strings.Join(append(prefix, k), ".")}, group...)
+		}) {
+			for _, o := range pair[1:] {
+				key := []string{pair[0], o}
+				if !c.ordered {
+					sort.Strings(key)
+				}
+				if _, ok := seen[strings.Join(key, ",")]; ok {
+					continue
+				}
+				seen[strings.Join(key, ",")] = struct{}{}
+				p1, n1, ok1 := r.presence(pair[0], len(tfPath) == 0)
+				p2, n2, ok2 := r.presence(o, len(tfPath) == 0)
+				if !ok1 || !ok2 {
+					continue
+				}
+				g.addConstraintRule(tfPath, typeNames, r, fmt.Sprintf(c.rule, p1, p2), fmt.Sprintf(c.message, n1, n2), c.required)
+			}
+		}
+	}
+}
+
+// addConstraintRule adds the given CEL rule of a constraint among the
+// arguments of the block at the given Terraform path. The rules of the
+// top-level arguments are added to the resource, and the rules requiring
+// some arguments to be set are only enforced if the resource is created or
+// updated unless it's a data source. The rules of the nested arguments are
+// added to the parameters type of the block.
+func (g *Builder) addConstraintRule(tfPath []string, typeNames *TypeNames, r *resource, rule, msg string, required bool) {
+	switch {
+	case len(tfPath) > 0:
+		g.addTypeComment(typeNames.ParameterTypeName, fmt.Sprintf(`// +kubebuilder:validation:XValidation:rule="%s",message="%s"`, rule, msg))
+	case required && !r.dataSource:
+		g.validationRules += "\n"
+		g.validationRules += fmt.Sprintf(`// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || %s",message="%s"`, rule, msg)
+	default:
+		g.validationRules += "\n"
+		g.validationRules += fmt.Sprintf(`// +kubebuilder:validation:XValidation:rule="%s",message="%s"`, rule, msg)
+	}
+}
+
+// constraintGroups returns the Terraform names of the arguments of the
+// constraints returned by the given function for the arguments of the
+// block at the given Terraform path. The constraints are returned in the
+// order of the arguments declaring them with their first arguments
+// preserved and the rest sorted, and the duplicates and the constraints
+// spanning multiple blocks are skipped.
+func constraintGroups(res *schema.Resource, tfPath []string, fn func(k string, sch *schema.Schema) []string) [][]string {
+	prefix := strings.Join(siblingPrefix(tfPath), ".")
+	var groups [][]string
+	seen := map[string]struct{}{}
+	for _, k := range sortedKeys(res.Schema) {
+		var group []string
+		for _, m := range fn(k, res.Schema[k]) {
+			segments := strings.Split(m, ".")
+			if strings.Join(segments[:len(segments)-1], ".") != prefix {
+				group = nil
+				break
+			}
+			group = append(group, segments[len(segments)-1])
+		}
+		if len(group) < 2 {
+			continue
+		}
+		sort.Strings(group[1:])
+		key := append([]string{}, group...)
+		sort.Strings(key)
+		if _, ok := seen[strings.Join(key, ",")]; ok {
+			continue
+		}
+		seen[strings.Join(key, ",")] = struct{}{}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// siblingPrefix returns the segments of the Terraform schema keys, e.g.
+// "rule.0.allow", of the arguments of the block at the given Terraform path.
+func siblingPrefix(tfPath []string) []string {
+	prefix := make([]string, len(tfPath))
+	for i, p := range tfPath {
+		if p == wildcard {
+			p = "0"
+		}
+		prefix[i] = p
+	}
+	return prefix
+}
+
+func (g *Builder) addMatchedIgnoredFields(patterns ...string) {
+	if g.matchedIgnoredFields == nil {
+		g.matchedIgnoredFields = make(map[string]struct{}, len(patterns))
+	}
+	for _, p := range patterns {
+		g.matchedIgnoredFields[p] = struct{}{}
+	}
+}
+
+// unmatchedIgnoredFields returns the late-initialization ignored field
+// patterns that don't match any field in the schema.
+func (g *Builder) unmatchedIgnoredFields(cfg *config.Resource) []string {
+	var unmatched []string
+	for _, p := range cfg.LateInitializer.IgnoredFields {
+		if _, ok := g.matchedIgnoredFields[p]; !ok {
+			unmatched = append(unmatched, p)
+		}
+	}
+	return unmatched
+}
+
+// addExclusivityRules validates the field validators and adds the CEL rules
+// rejecting the mutually exclusive top-level arguments set together.
+func (g *Builder) addExclusivityRules(cfg *config.Resource) error {
+	paths := make([]string, 0, len(cfg.FieldValidators))
+	for p := range cfg.FieldValidators {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if _, ok := g.matchedValidators[p]; !ok {
+			return errors.Errorf("field validator %q does not match any spec field", p)
+		}
+		for _, e := range cfg.FieldValidators[p].ExclusiveWith {
+			n, ok := g.topLevelParams[p]
+			if !ok {
+				return errors.Errorf("field %q exclusive with %q is not a top-level spec field", p, e)
+			}
+			en, ok := g.topLevelParams[e]
+			if !ok {
+				return errors.Errorf("field %q exclusive with %q is not a top-level spec field", e, p)
+			}
+			g.validationRules += "\n"
+			g.validationRules += fmt.Sprintf(`// +kubebuilder:validation:XValidation:rule="!has(self.forProvider.%s) || !has(self.forProvider.%s)",message="%s and %s are mutually exclusive"`, n, en, n, en)
+		}
+	}
+	return nil
+}
+
+// addCompositionFieldPath records the path of the given field in the managed
+// resource if it's one of the composition hint fields.
+func (g *Builder) addCompositionFieldPath(cfg *config.Resource, f *Field, parameter bool) {
+	p := fieldPath(f.TerraformPaths)
+	found := false
+	for _, cp := range cfg.CompositionHints.Fields {
+		if cp == p {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return
+	}
+	if g.compositionFieldPaths == nil {
+		g.compositionFieldPaths = map[string]string{}
+	}
+	prefix := "status.atProvider."
+	if parameter {
+		prefix = "spec.forProvider."
+	}
+	g.compositionFieldPaths[p] = prefix + fieldPathWithWildcard(f.CRDPaths)
+}
+
+// printerColumns returns the configured printer columns of the resource or,
+// if none are configured, the ones displaying its top-level state and
+// location string attributes, e.g. "status" and "region", unless the
+// automatic printer columns are disabled. Only the first existing attribute
+// of each kind is displayed.
+func (g *Builder) printerColumns(cfg *config.Resource) []config.PrinterColumn {
+	if cfg.PrinterColumns != nil || cfg.DisableAutoPrinterColumns {
+		return cfg.PrinterColumns
+	}
+	var cols []config.PrinterColumn
+	for _, candidates := range [][]string{{"state", "status"}, {"region", "location", "zone"}} {
+		for _, k := range candidates {
+			if n, ok := g.topLevelObs[k]; ok {
+				cols = append(cols, config.PrinterColumn{Name: strings.ToUpper(k), Type: "string", JSONPath: ".status.atProvider." + n})
+				break
+			}
+		}
+	}
+	return cols
+}
+
+// observationPruned reports whether the given field is pruned from the
+// observation types and records its paths for the validation of the
+// pruning configuration.
+func (g *Builder) observationPruned(f *Field) bool {
+	tfPath := fieldPath(f.TerraformPaths)
+	if g.obsFieldPaths != nil {
+		g.obsFieldPaths[tfPath] = struct{}{}
+	}
+	if !g.obsPruning.Pruned(tfPath) {
+		if len(f.TerraformPaths) == 1 && f.Schema.Type == schema.TypeString {
+			if g.topLevelObs == nil {
+				g.topLevelObs = map[string]string{}
+			}
+			g.topLevelObs[tfPath] = f.TransformedName
+		}
+		return false
+	}
+	g.prunedCRDPaths = append(g.prunedCRDPaths, fieldPath(f.CRDPaths))
+	return true
+}
+
+// validateObservationPruning checks that every pruning entry matches an
+// observation field and that neither the "id" field nor the fields
+// displayed in the printer columns are pruned.
+func (g *Builder) validateObservationPruning(cfg *config.Resource) error {
+	for _, p := range append(append([]string{}, cfg.ObservationPruning.ExcludedFields...), cfg.ObservationPruning.IncludedFields...) {
+		if _, ok := g.obsFieldPaths[reIndex.ReplaceAllString(p, "")]; !ok {
+			return errors.Errorf("pruned observation field %q does not match any observation field in the schema", p)
+		}
+	}
+	if _, ok := g.obsFieldPaths["id"]; ok && cfg.ObservationPruning.Pruned("id") {
+		return errors.New(`observation field "id" cannot be pruned`)
+	}
+	for _, c := range cfg.PrinterColumns {
+		p, ok := strings.CutPrefix(c.JSONPath, ".status.atProvider.")
+		if !ok {
+			continue
+		}
+		p = reIndex.ReplaceAllString(p, "")
+		for _, pruned := range g.prunedCRDPaths {
+			if p == pruned || strings.HasPrefix(p, pruned+".") {
+				return errors.Errorf("observation field %q displayed in the printer column %q cannot be pruned", c.JSONPath, c.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateWriteOnlyFields checks that the configured write-only fields are
+// top-level arguments of the Terraform schema.
+func validateWriteOnlyFields(cfg *config.Resource) error {
+	for _, f := range cfg.Sensitive.WriteOnlyFields {
+		sch, ok := cfg.TerraformResource.Schema[f]
+		if !ok {
+			return errors.Errorf("write-only field %q is not a top-level field of the schema", f)
+		}
+		if IsObservation(sch) {
+			return errors.Errorf("write-only field %q is not an argument", f)
+		}
+	}
+	return nil
+}
+
+// alphaFieldServed reports whether the field with the given Terraform path
+// is generated in the API version of the package, which is false for the
+// alpha fields if the package is not one of the configured alpha field
+// versions.
+func (g *Builder) alphaFieldServed(cfg *config.Resource, p string) bool {
+	if cfg.FieldMaturities[p] != config.FieldMaturityAlpha || len(cfg.AlphaFieldVersions) == 0 {
+		return true
+	}
+	for _, v := range cfg.AlphaFieldVersions {
+		if v == g.Package.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFieldMaturities returns an error if a field maturity is not one of
+// the known levels.
+func validateFieldMaturities(cfg *config.Resource) error {
+	paths := make([]string, 0, len(cfg.FieldMaturities))
+	for p := range cfg.FieldMaturities {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		switch m := cfg.FieldMaturities[p]; m {
+		case config.FieldMaturityAlpha, config.FieldMaturityBeta, config.FieldMaturityStable:
+		default:
+			return errors.Errorf("maturity %q of field %q is not one of %q, %q or %q", m, p, config.FieldMaturityAlpha, config.FieldMaturityBeta, config.FieldMaturityStable)
+		}
+	}
+	return nil
+}
+
+// validateHiddenParameters returns an error if a hidden parameter is not a
+// top-level argument that can be set by the controller, i.e. it's sensitive,
+// write-only or a reference.
+func validateHiddenParameters(cfg *config.Resource) error {
+	params := make([]string, 0, len(cfg.HiddenParameters))
+	for f := range cfg.HiddenParameters {
+		params = append(params, f)
+	}
+	sort.Strings(params)
+	for _, f := range params {
+		sch, ok := cfg.TerraformResource.Schema[f]
+		if !ok {
+			return errors.Errorf("hidden parameter %q is not a top-level field of the schema", f)
+		}
+		if IsObservation(sch) {
+			return errors.Errorf("hidden parameter %q is not an argument", f)
+		}
+		if _, ok := cfg.References[f]; ok || sch.Sensitive || cfg.Sensitive.IsWriteOnly(f) {
+			return errors.Errorf("hidden parameter %q cannot be a sensitive, write-only or reference field", f)
+		}
+	}
+	return nil
 }
 
 // AddToBuilder adds fields to the Builder.
-func (g *Builder) AddToBuilder(typeNames *TypeNames, r *resource) (*types.Named, *types.Named) {
+func (g *Builder) AddToBuilder(typeNames *TypeNames, r *resource) (*types.Named, *types.Named, *types.Named) {
 	// NOTE(muvaf): Not every struct has both computed and configurable fields,
 	// so some types we generate here are empty and unnecessary. However,
 	// there are valid types with zero fields and we don't have the information
@@ -128,6 +844,9 @@ func (g *Builder) AddToBuilder(typeNames *TypeNames, r *resource) (*types.Named,
 	paramType := types.NewNamed(typeNames.ParameterTypeName, types.NewStruct(r.paramFields, r.paramTags), nil)
 	g.genTypes = append(g.genTypes, paramType)
 
+	initType := types.NewNamed(typeNames.InitTypeName, types.NewStruct(r.initFields, r.initTags), nil)
+	g.genTypes = append(g.genTypes, initType)
+
 	obsType := types.NewNamed(typeNames.ObservationTypeName, types.NewStruct(r.obsFields, r.obsTags), nil)
 	g.genTypes = append(g.genTypes, obsType)
 
@@ -136,7 +855,7 @@ func (g *Builder) AddToBuilder(typeNames *TypeNames, r *resource) (*types.Named,
 		g.validationRules += fmt.Sprintf(`// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.%s)",message="%s is a required parameter"`, p, p)
 	}
 
-	return paramType, obsType
+	return paramType, initType, obsType
 }
 
 func (g *Builder) buildSchema(f *Field, cfg *config.Resource, names []string, r *resource) (types.Type, error) { // nolint:gocyclo
@@ -150,13 +869,28 @@ func (g *Builder) buildSchema(f *Field, cfg *config.Resource, names []string, r
 	case schema.TypeString:
 		return types.NewPointer(types.Universe.Lookup("string").Type()), nil
 	case schema.TypeMap, schema.TypeList, schema.TypeSet:
+		sharedName, shared := cfg.SharedBlocks[fieldPath(f.TerraformPaths)]
 		names = append(names, f.Name.Camel)
-		if f.Schema.Type != schema.TypeMap {
+		embedded := g.isEmbeddedList(f.TerraformPaths)
+		_, isBlock := f.Schema.Elem.(*schema.Resource)
+		if f.Schema.Type != schema.TypeMap || isBlock {
 			// We don't want to have a many-to-many relationship in case of a Map, since we use SecretReference as
 			// the type of XP field. In this case, we want to have a one-to-many relationship which is handled at
-			// runtime in the controller.
+			// runtime in the controller. The maps of objects, on the other hand, are traversed like the lists.
 			f.TerraformPaths = append(f.TerraformPaths, wildcard)
-			f.CRDPaths = append(f.CRDPaths, wildcard)
+			// The embedded singleton lists are objects in the CRD.
+			if !embedded {
+				f.CRDPaths = append(f.CRDPaths, wildcard)
+			}
+		}
+		if isBlock && cfg.MaxBlockNestingDepth > 0 && len(names)-1 > cfg.MaxBlockNestingDepth {
+			return g.collapseBlock(f), nil
+		}
+		if cfg.CollapsedObservationBlock(f.Schema) {
+			g.collapsedObsPaths = append(g.collapsedObsPaths, fieldPath(f.TerraformPaths))
+			f.Comment.Schemaless = true
+			f.Comment.PreserveUnknownFields = true
+			return types.NewPointer(typeRawExtension), nil
 		}
 		var elemType types.Type
 		switch et := f.Schema.Elem.(type) {
@@ -174,7 +908,7 @@ func (g *Builder) buildSchema(f *Field, cfg *config.Resource, names []string, r
 				return nil, errors.Errorf("element type of %s is basic but not one of known basic types", fieldPath(names))
 			}
 		case *schema.Schema:
-			newf, err := NewField(g, cfg, r, et, f.Name.Snake, f.TerraformPaths, f.CRDPaths, names, false)
+			newf, err := NewField(g, cfg, r, et, f.TFName, f.TerraformPaths, f.CRDPaths, names, false)
 			if err != nil {
 				return nil, err
 			}
@@ -186,7 +920,13 @@ func (g *Builder) buildSchema(f *Field, cfg *config.Resource, names []string, r
 			if f.Schema.ConfigMode == schema.SchemaConfigModeAttr {
 				asBlocksMode = true
 			}
-			paramType, obsType, err := g.buildResource(et, cfg, f.TerraformPaths, f.CRDPaths, asBlocksMode, names...)
+			var paramType, initType, obsType *types.Named
+			var err error
+			if shared {
+				paramType, initType, obsType, err = g.sharedBlockTypes(f, sharedName, et)
+			} else {
+				paramType, initType, obsType, err = g.buildResource(et, cfg, f.TerraformPaths, f.CRDPaths, asBlocksMode, names...)
+			}
 			if err != nil {
 				return nil, errors.Wrapf(err, "cannot infer type from resource schema of element type of %s", fieldPath(names))
 			}
@@ -201,19 +941,26 @@ func (g *Builder) buildSchema(f *Field, cfg *config.Resource, names []string, r
 				// that can go under spec. This check prevents the elimination of fields in parameter type, by checking
 				// whether the schema in observation type has nested parameter (spec) fields.
 				if paramType.Underlying().String() != emptyStruct {
-					field := types.NewField(token.NoPos, g.Package, f.Name.Camel, types.NewSlice(paramType), false)
+					field := types.NewField(token.NoPos, g.Package, f.Name.Camel, collectionOf(f.Schema, paramType, embedded), false)
 					r.addParameterField(f, field)
 				}
+				if initType.Underlying().String() != emptyStruct {
+					field := types.NewField(token.NoPos, g.Package, f.Name.Camel, collectionOf(f.Schema, initType, embedded), false)
+					r.addInitField(f, field)
+				}
 			default:
 				if paramType == nil {
 					return nil, errors.Errorf("element type of %s is configurable but the underlying schema does not return a parameter type", fieldPath(names))
 				}
 				elemType = paramType
+				if initType.Underlying().String() != emptyStruct {
+					f.InitType = collectionOf(f.Schema, initType, embedded)
+				}
 				// There are some types that are parameter field but also has nested fields that can go under status.
 				// This check prevents the elimination of fields in observation type, by checking whether the schema in
 				// parameter type has nested observation (status) fields.
-				if obsType.Underlying().String() != emptyStruct {
-					field := types.NewField(token.NoPos, g.Package, f.Name.Camel, types.NewSlice(obsType), false)
+				if obsType.Underlying().String() != emptyStruct && !g.obsPruning.Pruned(fieldPath(f.TerraformPaths)) {
+					field := types.NewField(token.NoPos, g.Package, f.Name.Camel, collectionOf(f.Schema, obsType, embedded), false)
 					r.addObservationField(f, field)
 				}
 			}
@@ -227,10 +974,7 @@ func (g *Builder) buildSchema(f *Field, cfg *config.Resource, names []string, r
 
 		// NOTE(muvaf): Maps and slices are already pointers, so we don't need to
 		// wrap them even if they are optional.
-		if f.Schema.Type == schema.TypeMap {
-			return types.NewMap(types.Universe.Lookup("string").Type(), elemType), nil
-		}
-		return types.NewSlice(elemType), nil
+		return collectionOf(f.Schema, elemType, embedded), nil
 	case schema.TypeInvalid:
 		return nil, errors.Errorf("invalid schema type %s", f.Schema.Type.String())
 	default:
@@ -238,9 +982,215 @@ func (g *Builder) buildSchema(f *Field, cfg *config.Resource, names []string, r
 	}
 }
 
-// TypeNames represents the parameter and observation name of the resource.
+// isEmbeddedList reports whether the list field with the given Terraform
+// paths is an embedded singleton list.
+func (g *Builder) isEmbeddedList(tfPaths []string) bool {
+	_, ok := g.embeddedLists[fieldPathWithWildcard(tfPaths)]
+	return ok
+}
+
+// collectionOf returns the map or the list type of the given element type
+// for the given schema of type map, list or set.
+func collectionOf(sch *schema.Schema, elemType types.Type, embedded bool) types.Type {
+	if sch.Type == schema.TypeMap {
+		return types.NewMap(types.Universe.Lookup("string").Type(), elemType)
+	}
+	return listOrEmbedded(elemType, embedded)
+}
+
+// listOrEmbedded returns the list type of the given element type, or the
+// pointer type of the element type if the list is an embedded singleton
+// list.
+func listOrEmbedded(elemType types.Type, embedded bool) types.Type {
+	if embedded {
+		return types.NewPointer(elemType)
+	}
+	return types.NewSlice(elemType)
+}
+
+// sensitiveBlock returns a copy of the given sensitive block of the spec
+// which is not sensitive itself but whose string fields and blocks are, so
+// that the secret key selectors of its sensitive values are generated at
+// their positions in the block. The other fields of the block are kept as
+// plain spec fields.
+func sensitiveBlock(sch *schema.Schema) *schema.Schema {
+	er := sch.Elem.(*schema.Resource)
+	ec := &schema.Resource{
+		Schema: make(map[string]*schema.Schema, len(er.Schema)),
+	}
+	for k, s := range er.Schema {
+		sc := *s
+		sc.Sensitive = sensitiveValue(s)
+		ec.Schema[k] = &sc
+	}
+	c := *sch
+	c.Sensitive = false
+	c.Elem = ec
+	return &c
+}
+
+// sensitiveValue reports whether the values of the given field of a
+// sensitive block can be loaded from secrets, i.e. whether it's a block or a
+// string field, or a collection of strings.
+func sensitiveValue(sch *schema.Schema) bool {
+	switch sch.Type { //nolint:exhaustive
+	case schema.TypeString:
+		return true
+	case schema.TypeList, schema.TypeSet, schema.TypeMap:
+		switch et := sch.Elem.(type) {
+		case *schema.Resource, nil:
+			return true
+		case *schema.Schema:
+			return et.Type == schema.TypeString
+		case schema.ValueType:
+			return et == schema.TypeString
+		}
+	}
+	return false
+}
+
+// collapseBlock returns the runtime.RawExtension type for the given block
+// field and records its Terraform path as collapsed.
+func (g *Builder) collapseBlock(f *Field) types.Type {
+	g.collapsedPaths = append(g.collapsedPaths, fieldPath(f.TerraformPaths))
+	// The collapsed value may be a list or a map of objects, so we cannot use
+	// the object schema generated for runtime.RawExtension. As the schemaless
+	// field has no type for the CEL rules either, the kind and the item
+	// limits of the value are validated at runtime, see
+	// config.Resource.CollapsedBlocks.
+	f.Comment.Schemaless = true
+	f.Comment.PreserveUnknownFields = true
+	f.Comment.MinItems = nil
+	f.Comment.MaxItems = nil
+	f.Comment.ListType = ""
+	f.Comment.ListMapKeys = nil
+	return types.NewPointer(typeRawExtension)
+}
+
+// newTypeNames returns the names of the types of the block at the given
+// Terraform path, which are overridden or pinned if configured, and records
+// the auto-resolved collisions of the generated names.
+func (g *Builder) newTypeNames(cfg *config.Resource, tfPath []string, names []string) (*TypeNames, error) {
+	if len(tfPath) == 0 {
+		// the names of the top-level types cannot change, so they take
+		// precedence over the names pinned for the blocks.
+		if tn, ok := g.pinned.claim(names[0]); ok {
+			return tn, nil
+		}
+		return NewTypeNames(names, g.Package)
+	}
+	p := fieldPath(tfPath)
+	if n, ok := cfg.OverrideFieldNames[p]; ok {
+		if g.matchedOverrideFieldNames == nil {
+			g.matchedOverrideFieldNames = map[string]struct{}{}
+		}
+		g.matchedOverrideFieldNames[p] = struct{}{}
+		tn, ok := g.pinned.claim(n)
+		if !ok {
+			var err error
+			if tn, err = overriddenTypeNames(n, g.Package); err != nil {
+				return nil, err
+			}
+		}
+		g.recordTypeName(p, n)
+		return tn, nil
+	}
+	if n, ok := g.pinned.name(cfg.Name, p); ok {
+		if tn, ok := g.pinned.claim(n); ok {
+			g.recordTypeName(p, n)
+			return tn, nil
+		}
+	}
+	if tn, ok, err := g.truncatedTypeNames(p, names); ok || err != nil {
+		return tn, err
+	}
+	tn, err := NewTypeNames(names, g.Package)
+	if err != nil {
+		return nil, err
+	}
+	n := names[len(names)-1]
+	if tn.ParameterTypeName.Name() != n+"Parameters" || tn.InitTypeName.Name() != n+"InitParameters" || tn.ObservationTypeName.Name() != n+"Observation" {
+		if g.resolvedCollisions == nil {
+			g.resolvedCollisions = map[string]string{}
+		}
+		g.resolvedCollisions[p] = strings.TrimSuffix(tn.ParameterTypeName.Name(), "Parameters")
+	}
+	// the names with an index suffix, e.g. "FilterParameters_2", cannot be
+	// pinned.
+	if n := strings.TrimSuffix(tn.ParameterTypeName.Name(), "Parameters"); tn.InitTypeName.Name() == n+"InitParameters" && tn.ObservationTypeName.Name() == n+"Observation" {
+		g.recordTypeName(p, n)
+	}
+	return tn, nil
+}
+
+// truncatedTypeNames returns the truncated type names of the block at the
+// given Terraform path if the names generated for it exceed the maximum
+// length. The truncated names are suffixed with the first characters of the
+// SHA-256 hash of the generated names.
+func (g *Builder) truncatedTypeNames(p string, names []string) (*TypeNames, bool, error) {
+	const sfx = "InitParameters"
+	if g.maxTypeNameLength <= 0 {
+		return nil, false, nil
+	}
+	pn, err := generateTypeName("Parameters", g.Package, names...)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "cannot generate parameters type name of %s", fieldPath(names))
+	}
+	i := strings.LastIndex(pn, "Parameters")
+	n := pn[:i] + pn[i+len("Parameters"):]
+	if len(n)+len(sfx) <= g.maxTypeNameLength {
+		return nil, false, nil
+	}
+	l := g.maxTypeNameLength - len(sfx) - hashLength
+	if l <= 0 {
+		return nil, false, errors.Errorf("maximum type name length %d is too short to truncate type name %s", g.maxTypeNameLength, n)
+	}
+	h := sha256.Sum256([]byte(n))
+	n = n[:l] + hex.EncodeToString(h[:])[:hashLength]
+	tn, err := overriddenTypeNames(n, g.Package)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "cannot truncate type name of %s", p)
+	}
+	if g.truncatedNames == nil {
+		g.truncatedNames = map[string]string{}
+	}
+	g.truncatedNames[p] = n
+	g.recordTypeName(p, n)
+	return tn, true, nil
+}
+
+func (g *Builder) recordTypeName(p, n string) {
+	if g.typeNames == nil {
+		g.typeNames = map[string]string{}
+	}
+	g.typeNames[p] = n
+}
+
+// overriddenTypeNames returns the type names with the given overridden name
+// and inserts them into the package scope. It returns an error if the names
+// are already in use.
+func overriddenTypeNames(n string, pkg *types.Package) (*TypeNames, error) {
+	for _, sfx := range []string{"Parameters", "InitParameters", "Observation"} {
+		if pkg.Scope().Lookup(n+sfx) != nil {
+			return nil, errors.Errorf("overridden type name %s is already in use", n+sfx)
+		}
+	}
+	tn := &TypeNames{
+		ParameterTypeName:   types.NewTypeName(token.NoPos, pkg, n+"Parameters", nil),
+		InitTypeName:        types.NewTypeName(token.NoPos, pkg, n+"InitParameters", nil),
+		ObservationTypeName: types.NewTypeName(token.NoPos, pkg, n+"Observation", nil),
+	}
+	pkg.Scope().Insert(tn.ParameterTypeName)
+	pkg.Scope().Insert(tn.InitTypeName)
+	pkg.Scope().Insert(tn.ObservationTypeName)
+	return tn, nil
+}
+
+// TypeNames represents the parameter, init parameter and observation name of
+// the resource.
 type TypeNames struct {
 	ParameterTypeName   *types.TypeName
+	InitTypeName        *types.TypeName
 	ObservationTypeName *types.TypeName
 }
 
@@ -252,6 +1202,12 @@ func NewTypeNames(fieldPaths []string, pkg *types.Package) (*TypeNames, error) {
 	}
 	paramName := types.NewTypeName(token.NoPos, pkg, paramTypeName, nil)
 
+	initTypeName, err := generateTypeName("InitParameters", pkg, fieldPaths...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot generate init parameters type name of %s", fieldPath(fieldPaths))
+	}
+	initName := types.NewTypeName(token.NoPos, pkg, initTypeName, nil)
+
 	obsTypeName, err := generateTypeName("Observation", pkg, fieldPaths...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot generate observation type name of %s", fieldPath(fieldPaths))
@@ -261,15 +1217,26 @@ func NewTypeNames(fieldPaths []string, pkg *types.Package) (*TypeNames, error) {
 	// We insert them to the package scope so that the type name calculations in
 	// recursive calls are checked against their upper level type's name as well.
 	pkg.Scope().Insert(paramName)
+	pkg.Scope().Insert(initName)
 	pkg.Scope().Insert(obsName)
 
-	return &TypeNames{ParameterTypeName: paramName, ObservationTypeName: obsName}, nil
+	return &TypeNames{ParameterTypeName: paramName, InitTypeName: initName, ObservationTypeName: obsName}, nil
 }
 
 type resource struct {
-	paramFields, obsFields []*types.Var
-	paramTags, obsTags     []string
-	topLevelRequiredParams []string
+	paramFields, initFields, obsFields []*types.Var
+	paramTags, initTags, obsTags       []string
+	topLevelRequiredParams             []string
+	// paramNames maps the Terraform names of the arguments to the JSON
+	// names of their parameter fields, including their reference and
+	// selector fields.
+	paramNames map[string][]string
+	// initNames maps the Terraform names of the arguments to the JSON names
+	// of their init parameter fields.
+	initNames map[string]string
+	// dataSource is true if the resource is a Terraform data source, whose
+	// required arguments are required for observing it, too.
+	dataSource bool
 }
 
 func (r *resource) addParameterField(f *Field, field *types.Var) {
@@ -282,7 +1249,9 @@ func (r *resource) addParameterField(f *Field, field *types.Var) {
 	// - req => required
 	// - !f.Identifier => not identifiers - i.e. region, zone, etc.
 	// - len(f.CanonicalPaths) == 1 => top level, i.e. not a nested field
-	if req && !f.Identifier && len(f.CanonicalPaths) == 1 {
+	// - !r.dataSource => not the arguments of a data source, which is only
+	// observed
+	if req && !f.Identifier && !r.dataSource && len(f.CanonicalPaths) == 1 {
 		req = false
 		r.topLevelRequiredParams = append(r.topLevelRequiredParams, f.TransformedName)
 	}
@@ -297,6 +1266,49 @@ func (r *resource) addParameterField(f *Field, field *types.Var) {
 		r.paramTags = append(r.paramTags, fmt.Sprintf(`json:"%s" tf:"%s"`, strings.TrimSuffix(f.JSONTag, ",omitempty"), f.TFTag))
 	}
 	r.paramFields = append(r.paramFields, field)
+	if r.paramNames == nil {
+		r.paramNames = map[string][]string{}
+	}
+	r.paramNames[f.TFName] = append(r.paramNames[f.TFName], jsonName(f.JSONTag))
+}
+
+// presence returns the CEL expression checking whether the argument with the
+// given Terraform name is set in the parameters, including its reference
+// and selector fields, and the JSON name of its parameter field. The
+// expression of a top-level argument checks the init parameters, too. It
+// returns false if the argument is not in the parameters.
+func (r *resource) presence(tfName string, topLevel bool) (string, string, bool) {
+	params, ok := r.paramNames[tfName]
+	if !ok {
+		return "", "", false
+	}
+	alts := make([]string, 0, len(params)+1)
+	for _, n := range params {
+		if topLevel {
+			n = "forProvider." + n
+		}
+		alts = append(alts, fmt.Sprintf("has(self.%s)", n))
+	}
+	if n, ok := r.initNames[tfName]; ok && topLevel {
+		alts = append(alts, fmt.Sprintf("has(self.initProvider.%s)", n))
+	}
+	return strings.Join(alts, " || "), params[0], true
+}
+
+// jsonName returns the name in the given JSON tag value.
+func jsonName(tag string) string {
+	return strings.Split(tag, ",")[0]
+}
+
+// addInitField adds the given field to the init parameters, which are all
+// optional.
+func (r *resource) addInitField(f *Field, field *types.Var) {
+	r.initTags = append(r.initTags, fmt.Sprintf(`json:"%s" tf:"%s"`, f.JSONTag, f.TFTag))
+	r.initFields = append(r.initFields, field)
+	if r.initNames == nil {
+		r.initNames = map[string]string{}
+	}
+	r.initNames[f.TFName] = jsonName(f.JSONTag)
 }
 
 func (r *resource) addObservationField(f *Field, field *types.Var) {
@@ -316,6 +1328,12 @@ func (r *resource) addReferenceFields(g *Builder, paramName *types.TypeName, fie
 	refFields, refTags := g.generateReferenceFields(paramName, field)
 	r.paramTags = append(r.paramTags, refTags...)
 	r.paramFields = append(r.paramFields, refFields...)
+	if r.paramNames == nil {
+		r.paramNames = map[string][]string{}
+	}
+	for _, t := range refTags {
+		r.paramNames[field.TFName] = append(r.paramNames[field.TFName], jsonName(reflect.StructTag(t).Get("json")))
+	}
 }
 
 // generateTypeName generates a unique name for the type if its original name
@@ -20,14 +20,17 @@ import (
 	"fmt"
 	"go/token"
 	"go/types"
+	"strings"
 	"testing"
 
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
 
 	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/registry"
 )
 
 func TestBuilder_generateTypeName(t *testing.T) {
@@ -205,12 +208,16 @@ func TestBuilder_generateTypeName(t *testing.T) {
 
 func TestBuild(t *testing.T) {
 	type args struct {
-		cfg *config.Resource
+		cfg     *config.Resource
+		version string
+		strict  bool
 	}
 	type want struct {
-		forProvider string
-		atProvider  string
-		err         error
+		forProvider    string
+		atProvider     string
+		collapsedPaths []string
+		unmatched      []string
+		err            error
 	}
 	cases := map[string]struct {
 		args
@@ -286,6 +293,176 @@ func TestBuild(t *testing.T) {
 				atProvider:  `type example.Observation struct{List []*string "json:\"list,omitempty\" tf:\"list,omitempty\""; ResourceIn map[string]example.ResourceInParameters "json:\"resourceIn,omitempty\" tf:\"resource_in,omitempty\""; ResourceOut map[string]example.ResourceOutObservation "json:\"resourceOut,omitempty\" tf:\"resource_out,omitempty\""}`,
 			},
 		},
+		"Field_Renames": {
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"config": {
+								Type:     schema.TypeString,
+								Optional: false,
+								Computed: true,
+							},
+						},
+					},
+					FieldRenames: map[string]string{
+						"name":   "displayName",
+						"config": "configuration",
+					},
+				},
+			},
+			want: want{
+				forProvider: `type example.Parameters struct{DisplayName *string "json:\"displayName,omitempty\" tf:\"name,omitempty\""}`,
+				atProvider:  `type example.Observation struct{Configuration *string "json:\"configuration,omitempty\" tf:\"config,omitempty\""; DisplayName *string "json:\"displayName,omitempty\" tf:\"name,omitempty\""}`,
+			},
+		},
+		"Deprecated_Field_Removed": {
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"size": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+						},
+					},
+					DeprecatedFields: map[string]config.FieldDeprecation{
+						"size": {
+							RemovedIn: "v1beta2",
+						},
+					},
+				},
+				version: "v1beta2",
+			},
+			want: want{
+				forProvider: `type example.Parameters struct{Name *string "json:\"name,omitempty\" tf:\"name,omitempty\""}`,
+				atProvider:  `type example.Observation struct{Name *string "json:\"name,omitempty\" tf:\"name,omitempty\""}`,
+			},
+		},
+		"Deprecated_Field_Not_Removed_Yet": {
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"size": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+						},
+					},
+					DeprecatedFields: map[string]config.FieldDeprecation{
+						"size": {
+							RemovedIn: "v1beta2",
+						},
+					},
+				},
+				version: "v1beta1",
+			},
+			want: want{
+				forProvider: `type example.Parameters struct{Size *string "json:\"size,omitempty\" tf:\"size,omitempty\""}`,
+				atProvider:  `type example.Observation struct{Size *string "json:\"size,omitempty\" tf:\"size,omitempty\""}`,
+			},
+		},
+		"Type_Overrides": {
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"size": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+							"labels": {
+								Type:     schema.TypeMap,
+								Optional: true,
+								Elem: &schema.Schema{
+									Type: schema.TypeInt,
+								},
+							},
+						},
+					},
+					TypeOverrides: map[string]config.TypeOverride{
+						"size":   config.TypeOverrideString,
+						"labels": config.TypeOverrideStringMap,
+					},
+				},
+			},
+			want: want{
+				forProvider: `type example.Parameters struct{Labels map[string]*string "json:\"labels,omitempty\" tf:\"labels,omitempty,stringify\""; Size *string "json:\"size,omitempty\" tf:\"size,omitempty,stringify\""}`,
+				atProvider:  `type example.Observation struct{Labels map[string]*string "json:\"labels,omitempty\" tf:\"labels,omitempty,stringify\""; Size *string "json:\"size,omitempty\" tf:\"size,omitempty,stringify\""}`,
+			},
+		},
+		"JSON_Type_Override": {
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"policy": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+						},
+					},
+					TypeOverrides: map[string]config.TypeOverride{
+						"policy": config.TypeOverrideJSON,
+					},
+				},
+			},
+			want: want{
+				forProvider: `type example.Parameters struct{Policy *k8s.io/apimachinery/pkg/runtime.RawExtension "json:\"policy,omitempty\" tf:\"policy,omitempty,embedjson\""}`,
+				atProvider:  `type example.Observation struct{Policy *k8s.io/apimachinery/pkg/runtime.RawExtension "json:\"policy,omitempty\" tf:\"policy,omitempty,embedjson\""}`,
+			},
+		},
+		"IntOrString_Type_Override": {
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"port": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+						},
+					},
+					TypeOverrides: map[string]config.TypeOverride{
+						"port": config.TypeOverrideIntOrString,
+					},
+				},
+			},
+			want: want{
+				forProvider: `type example.Parameters struct{Port *k8s.io/apimachinery/pkg/util/intstr.IntOrString "json:\"port,omitempty\" tf:\"port,omitempty\""}`,
+				atProvider:  `type example.Observation struct{Port *k8s.io/apimachinery/pkg/util/intstr.IntOrString "json:\"port,omitempty\" tf:\"port,omitempty\""}`,
+			},
+		},
+		"Invalid_Type_Overrides": {
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"size": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+						},
+					},
+					TypeOverrides: map[string]config.TypeOverride{
+						"size": config.TypeOverrideStringMap,
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errors.Errorf("type override %q is not applicable to a field of type %s", config.TypeOverrideStringMap, schema.TypeInt), "cannot override type of field size"), "cannot build the Types"),
+			},
+		},
 		"Sensitive_Fields": {
 			args: args{
 				cfg: &config.Resource{
@@ -313,6 +490,37 @@ func TestBuild(t *testing.T) {
 				atProvider:  `type example.Observation struct{}`,
 			},
 		},
+		"Sensitive_Block": {
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"auth": {
+								Type:      schema.TypeList,
+								Optional:  true,
+								Sensitive: true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"password": {
+											Type:     schema.TypeString,
+											Required: true,
+										},
+										"port": {
+											Type:     schema.TypeInt,
+											Optional: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				forProvider: `type example.Parameters struct{Auth []example.AuthParameters "json:\"auth,omitempty\" tf:\"auth,omitempty\""}`,
+				atProvider:  `type example.Observation struct{Auth []example.AuthObservation "json:\"auth,omitempty\" tf:\"auth,omitempty\""}`,
+			},
+		},
 		"Invalid_Sensitive_Fields": {
 			args: args{
 				cfg: &config.Resource{
@@ -358,6 +566,144 @@ func TestBuild(t *testing.T) {
 				atProvider:  `type example.Observation struct{Name *string "json:\"name,omitempty\" tf:\"name,omitempty\""; ReferenceID *string "json:\"referenceId,omitempty\" tf:\"reference_id,omitempty\""}`,
 			},
 		},
+		"LateInitializer_Ignored_Fields": {
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"rule": {
+								Type:     schema.TypeList,
+								Optional: true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"filter": {
+											Type:     schema.TypeString,
+											Optional: true,
+										},
+									},
+								},
+							},
+						},
+					},
+					LateInitializer: config.LateInitializer{
+						IgnoredFields: []string{"rule[*].filter"},
+					},
+				},
+			},
+			want: want{
+				forProvider: `type example.Parameters struct{Rule []example.RuleParameters "json:\"rule,omitempty\" tf:\"rule,omitempty\""}`,
+				atProvider:  `type example.Observation struct{Rule []example.RuleObservation "json:\"rule,omitempty\" tf:\"rule,omitempty\""}`,
+			},
+		},
+		"Unmatched_LateInitializer_Ignored_Fields": {
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+						},
+					},
+					LateInitializer: config.LateInitializer{
+						IgnoredFields: []string{"name", "rule[*].filter"},
+					},
+				},
+			},
+			want: want{
+				forProvider: `type example.Parameters struct{Name *string "json:\"name,omitempty\" tf:\"name,omitempty\""}`,
+				atProvider:  `type example.Observation struct{Name *string "json:\"name,omitempty\" tf:\"name,omitempty\""}`,
+				unmatched:   []string{"rule[*].filter"},
+			},
+		},
+		"Strict_Unmatched_LateInitializer_Ignored_Fields": {
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+						},
+					},
+					LateInitializer: config.LateInitializer{
+						IgnoredFields: []string{"rule[*].filter"},
+					},
+				},
+				strict: true,
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf("late-initialization ignored field %q does not match any field in the schema", "rule[*].filter"), "cannot build the Types"),
+			},
+		},
+		"Max_Block_Nesting_Depth": {
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"rule": {
+								Type:     schema.TypeList,
+								Optional: true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"filter": {
+											Type:     schema.TypeList,
+											Optional: true,
+											Elem: &schema.Resource{
+												Schema: map[string]*schema.Schema{
+													"prefix": {
+														Type:     schema.TypeString,
+														Optional: true,
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					MaxBlockNestingDepth: 1,
+				},
+			},
+			want: want{
+				forProvider:    `type example.Parameters struct{Rule []example.RuleParameters "json:\"rule,omitempty\" tf:\"rule,omitempty\""}`,
+				atProvider:     `type example.Observation struct{Rule []example.RuleObservation "json:\"rule,omitempty\" tf:\"rule,omitempty\""}`,
+				collapsedPaths: []string{"rule.filter"},
+			},
+		},
+		"Map_Of_Objects": {
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"rules": {
+								Type:     schema.TypeMap,
+								Optional: true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"priority": {
+											Type:     schema.TypeInt,
+											Optional: true,
+										},
+										"arn": {
+											Type:     schema.TypeString,
+											Computed: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				forProvider: `type example.Parameters struct{Rules map[string]example.RulesParameters "json:\"rules,omitempty\" tf:\"rules,omitempty\""}`,
+				atProvider:  `type example.Observation struct{Rules map[string]example.RulesObservation "json:\"rules,omitempty\" tf:\"rules,omitempty\""}`,
+			},
+		},
 		"Invalid_Schema_Type": {
 			args: args{
 				cfg: &config.Resource{
@@ -378,7 +724,7 @@ func TestBuild(t *testing.T) {
 	}
 	for n, tc := range cases {
 		t.Run(n, func(t *testing.T) {
-			builder := NewBuilder(types.NewPackage("example", ""))
+			builder := NewBuilder(types.NewPackage("example", tc.version), WithStrictIgnoredFields(tc.strict))
 			g, err := builder.Build(tc.cfg)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -394,6 +740,2213 @@ func TestBuild(t *testing.T) {
 					t.Fatalf("Build(...): -want atProvider, +got atProvider: %s", diff)
 				}
 			}
+			if diff := cmp.Diff(tc.want.collapsedPaths, g.CollapsedPaths); diff != "" {
+				t.Fatalf("Build(...): -want collapsedPaths, +got collapsedPaths: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.unmatched, g.UnmatchedIgnoredFields); diff != "" {
+				t.Fatalf("Build(...): -want unmatched ignored fields, +got unmatched ignored fields: %s", diff)
+			}
+		})
+	}
+}
+
+func TestBuildForceNewImmutability(t *testing.T) {
+	const immutable = `+kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"`
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"size": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"policy": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"filter": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+		},
+	}
+	cases := map[string]struct {
+		reason     string
+		cfg        *config.Resource
+		want       map[string]bool
+		schemaless []string
+	}{
+		"TopLevelForceNew": {
+			reason: "Only the top-level ForceNew parameters should be immutable.",
+			cfg: &config.Resource{
+				TerraformResource: tfResource,
+			},
+			want: map[string]bool{
+				"example.Parameters:Size":       true,
+				"example.Observation:Size":      false,
+				"example.RuleParameters:Filter": false,
+			},
+		},
+		"Schemaless": {
+			reason: "The schemaless ForceNew parameters should not be immutable as they have no types for the transition rules.",
+			cfg: &config.Resource{
+				TerraformResource: tfResource,
+				TypeOverrides:     map[string]config.TypeOverride{"policy": config.TypeOverrideJSON},
+			},
+			want: map[string]bool{
+				"example.Parameters:Size":   true,
+				"example.Parameters:Policy": false,
+			},
+			schemaless: []string{"example.Parameters:Policy"},
+		},
+		"OptOut": {
+			reason: "No parameters should be immutable if the resource opts out.",
+			cfg: &config.Resource{
+				TerraformResource:           tfResource,
+				DisableForceNewImmutability: true,
+			},
+			want: map[string]bool{
+				"example.Parameters:Size": false,
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			g, err := NewBuilder(types.NewPackage("example", "")).Build(tc.cfg)
+			if err != nil {
+				t.Fatalf("Build(...): unexpected error: %v", err)
+			}
+			got := make(map[string]bool, len(tc.want))
+			for k := range tc.want {
+				got[k] = strings.Contains(g.Comments[k], immutable)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want immutable, +got immutable:\n%s", tc.reason, diff)
+			}
+			for _, k := range tc.schemaless {
+				if !strings.Contains(g.Comments[k], "+kubebuilder:validation:Schemaless") {
+					t.Errorf("\n%s\nBuild(...): %s should be schemaless:\n%s", tc.reason, k, g.Comments[k])
+				}
+			}
+		})
+	}
+}
+
+func TestBuildEmbeddedSingletonLists(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"filter": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"target": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"arn": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+	type want struct {
+		fieldTypes map[string]string
+		embedded   []string
+	}
+	cases := map[string]struct {
+		reason  string
+		cfg     *config.Resource
+		version string
+		want    want
+	}{
+		"Embedded": {
+			reason: "The singleton list blocks should be generated as embedded objects.",
+			cfg: &config.Resource{
+				TerraformResource:      tfResource,
+				SingletonListEmbedding: config.SingletonListEmbedding{Enabled: true},
+			},
+			version: "v1beta1",
+			want: want{
+				fieldTypes: map[string]string{
+					"Rule":   "*RuleParameters",
+					"Target": "[]TargetParameters",
+				},
+				embedded: []string{"rule"},
+			},
+		},
+		"NotEmbeddedBeforeSince": {
+			reason: "The singleton list blocks should be generated as lists in the API versions before the configured one.",
+			cfg: &config.Resource{
+				TerraformResource:      tfResource,
+				SingletonListEmbedding: config.SingletonListEmbedding{Enabled: true, Since: "v1beta2"},
+			},
+			version: "v1beta1",
+			want: want{
+				fieldTypes: map[string]string{
+					"Rule":   "[]RuleParameters",
+					"Target": "[]TargetParameters",
+				},
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			g, err := NewBuilder(types.NewPackage("example", tc.version)).Build(tc.cfg)
+			if err != nil {
+				t.Fatalf("Build(...): unexpected error: %v", err)
+			}
+			s := g.ForProviderType.Underlying().(*types.Struct)
+			got := make(map[string]string, s.NumFields())
+			for i := 0; i < s.NumFields(); i++ {
+				got[s.Field(i).Name()] = types.TypeString(s.Field(i).Type(), func(*types.Package) string { return "" })
+			}
+			if diff := cmp.Diff(tc.want.fieldTypes, got); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want field types, +got field types:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.embedded, g.EmbeddedSingletonLists); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want embedded, +got embedded:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildWriteOnlyFields(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"password": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"arn": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+	type want struct {
+		fieldTypes map[string]string
+		err        error
+	}
+	cases := map[string]struct {
+		reason    string
+		writeOnly []string
+		want      want
+	}{
+		"WriteOnly": {
+			reason:    "The write-only fields should be generated as secret key references.",
+			writeOnly: []string{"password"},
+			want: want{
+				fieldTypes: map[string]string{
+					"Name":              "*string",
+					"PasswordSecretRef": "*v1.SecretKeySelector",
+				},
+			},
+		},
+		"UnknownField": {
+			reason:    "An error should be returned if a write-only field does not exist in the schema.",
+			writeOnly: []string{"secret"},
+			want: want{
+				err: errors.Wrap(errors.New(`write-only field "secret" is not a top-level field of the schema`), "cannot build the Types"),
+			},
+		},
+		"ObservationField": {
+			reason:    "An error should be returned if a write-only field is not an argument.",
+			writeOnly: []string{"arn"},
+			want: want{
+				err: errors.Wrap(errors.New(`write-only field "arn" is not an argument`), "cannot build the Types"),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource: tfResource,
+				Sensitive:         config.Sensitive{WriteOnlyFields: tc.writeOnly},
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nBuild(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			s := g.ForProviderType.Underlying().(*types.Struct)
+			got := make(map[string]string, s.NumFields())
+			for i := 0; i < s.NumFields(); i++ {
+				got[s.Field(i).Name()] = types.TypeString(s.Field(i).Type(), func(p *types.Package) string { return p.Name() })
+			}
+			if diff := cmp.Diff(tc.want.fieldTypes, got); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want field types, +got field types:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff("spec.forProvider.passwordSecretRef", cfg.Sensitive.GetFieldPaths()["password"]); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want sensitive field path, +got sensitive field path:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildHiddenParameters(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"enable_feature": {
+				Type:     schema.TypeBool,
+				Required: true,
+			},
+			"arn": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+	type want struct {
+		paramFields []string
+		obsFields   []string
+		err         error
+	}
+	cases := map[string]struct {
+		reason string
+		hidden map[string]any
+		want   want
+	}{
+		"Hidden": {
+			reason: "The hidden parameters should be removed from the spec but kept in the status.",
+			hidden: map[string]any{"enable_feature": true},
+			want: want{
+				paramFields: []string{"Name"},
+				obsFields:   []string{"Arn", "EnableFeature", "Name"},
+			},
+		},
+		"UnknownField": {
+			reason: "An error should be returned if a hidden parameter does not exist in the schema.",
+			hidden: map[string]any{"feature": true},
+			want: want{
+				err: errors.Wrap(errors.New(`hidden parameter "feature" is not a top-level field of the schema`), "cannot build the Types"),
+			},
+		},
+		"ObservationField": {
+			reason: "An error should be returned if a hidden parameter is not an argument.",
+			hidden: map[string]any{"arn": "arn"},
+			want: want{
+				err: errors.Wrap(errors.New(`hidden parameter "arn" is not an argument`), "cannot build the Types"),
+			},
+		},
+	}
+	fieldNames := func(n *types.Named) []string {
+		s := n.Underlying().(*types.Struct)
+		names := make([]string, 0, s.NumFields())
+		for i := 0; i < s.NumFields(); i++ {
+			names = append(names, s.Field(i).Name())
+		}
+		return names
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource: tfResource,
+				HiddenParameters:  tc.hidden,
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nBuild(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.paramFields, fieldNames(g.ForProviderType)); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want parameter fields, +got parameter fields:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.obsFields, fieldNames(g.AtProviderType)); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want observation fields, +got observation fields:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildObservationPruning(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"arn": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"filter": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"rule_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+	type want struct {
+		fields []string
+		err    error
+	}
+	cases := map[string]struct {
+		reason  string
+		pruning config.ObservationPruning
+		columns []config.PrinterColumn
+		want    want
+	}{
+		"Excluded": {
+			reason:  "The excluded observation fields should not be generated.",
+			pruning: config.ObservationPruning{ExcludedFields: []string{"status", "rule"}},
+			want: want{
+				fields: []string{"Arn", "ID", "Name"},
+			},
+		},
+		"Included": {
+			reason:  "Only the included observation fields and the id field should be generated.",
+			pruning: config.ObservationPruning{IncludedFields: []string{"id", "status[*].state"}},
+			want: want{
+				fields: []string{"ID", "Status"},
+			},
+		},
+		"UnknownField": {
+			reason:  "An error should be returned if a pruned field does not exist.",
+			pruning: config.ObservationPruning{ExcludedFields: []string{"tags"}},
+			want: want{
+				err: errors.Wrap(errors.New(`pruned observation field "tags" does not match any observation field in the schema`), "cannot build the Types"),
+			},
+		},
+		"PrunedID": {
+			reason:  "An error should be returned if the id field is pruned.",
+			pruning: config.ObservationPruning{IncludedFields: []string{"arn"}},
+			want: want{
+				err: errors.Wrap(errors.New(`observation field "id" cannot be pruned`), "cannot build the Types"),
+			},
+		},
+		"PrunedPrinterColumn": {
+			reason:  "An error should be returned if a field displayed in a printer column is pruned.",
+			pruning: config.ObservationPruning{ExcludedFields: []string{"status"}},
+			columns: []config.PrinterColumn{{Name: "STATE", Type: "string", JSONPath: ".status.atProvider.status[0].state"}},
+			want: want{
+				err: errors.Wrap(errors.New(`observation field ".status.atProvider.status[0].state" displayed in the printer column "STATE" cannot be pruned`), "cannot build the Types"),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource:  tfResource,
+				ObservationPruning: tc.pruning,
+				PrinterColumns:     tc.columns,
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nBuild(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			s := g.AtProviderType.Underlying().(*types.Struct)
+			got := make([]string, 0, s.NumFields())
+			for i := 0; i < s.NumFields(); i++ {
+				got = append(got, s.Field(i).Name())
+			}
+			if diff := cmp.Diff(tc.want.fields, got); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want observation fields, +got observation fields:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildSchemaElementOptions(t *testing.T) {
+	newResource := func() *schema.Resource {
+		return &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"ports": {
+					Type:     schema.TypeList,
+					Optional: true,
+				},
+				"rule": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"filter": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	type want struct {
+		fieldTypes map[string]string
+		markers    map[string]string
+	}
+	cases := map[string]struct {
+		reason string
+		cfg    *config.Resource
+		want   want
+	}{
+		"NoOptions": {
+			reason: "The fields should be generated from the upstream schema if no options are configured.",
+			cfg: &config.Resource{
+				TerraformResource: newResource(),
+			},
+			want: want{
+				fieldTypes: map[string]string{
+					"Ports": "[]string",
+					"Rule":  "[]RuleParameters",
+				},
+				markers: map[string]string{
+					"example.Parameters:Rule": "",
+				},
+			},
+		},
+		"Options": {
+			reason: "The element type and the item limits should be overridden.",
+			cfg: &config.Resource{
+				TerraformResource: newResource(),
+				SchemaElementOptions: map[string]config.SchemaElementOption{
+					"ports": {ElemType: schema.TypeInt},
+					"rule":  {MinItems: 1, MaxItems: 2},
+				},
+			},
+			want: want{
+				fieldTypes: map[string]string{
+					"Ports": "[]*int64",
+					"Rule":  "[]RuleParameters",
+				},
+				markers: map[string]string{
+					"example.Parameters:Rule":  "// +kubebuilder:validation:MinItems=1\n// +kubebuilder:validation:MaxItems=2\n",
+					"example.Observation:Rule": "",
+				},
+			},
+		},
+		"EmbeddedSingletonList": {
+			reason: "A block whose MaxItems is overridden to 1 should be embedded without item limits.",
+			cfg: &config.Resource{
+				TerraformResource:      newResource(),
+				SingletonListEmbedding: config.SingletonListEmbedding{Enabled: true},
+				SchemaElementOptions: map[string]config.SchemaElementOption{
+					"rule": {MaxItems: 1},
+				},
+			},
+			want: want{
+				fieldTypes: map[string]string{
+					"Ports": "[]string",
+					"Rule":  "*RuleParameters",
+				},
+				markers: map[string]string{
+					"example.Parameters:Rule": "",
+				},
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(tc.cfg)
+			if err != nil {
+				t.Fatalf("Build(...): unexpected error: %v", err)
+			}
+			s := g.ForProviderType.Underlying().(*types.Struct)
+			got := make(map[string]string, s.NumFields())
+			for i := 0; i < s.NumFields(); i++ {
+				got[s.Field(i).Name()] = types.TypeString(s.Field(i).Type(), func(*types.Package) string { return "" })
+			}
+			if diff := cmp.Diff(tc.want.fieldTypes, got); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want field types, +got field types:\n%s", tc.reason, diff)
+			}
+			markers := make(map[string]string, len(tc.want.markers))
+			for k := range tc.want.markers {
+				markers[k] = ""
+				for _, l := range strings.SplitAfter(g.Comments[k], "\n") {
+					if strings.Contains(l, "Items=") {
+						markers[k] += l
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.want.markers, markers); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want item limit markers, +got item limit markers:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildFieldValidators(t *testing.T) {
+	newResource := func() *schema.Resource {
+		return &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"name_prefix": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"rule": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"priority": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+						},
+					},
+				},
+				"arn": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		}
+	}
+	minimum, maximum := 1, 100
+	type want struct {
+		markers map[string]string
+		rules   string
+		err     error
+	}
+	cases := map[string]struct {
+		reason     string
+		validators map[string]config.FieldValidator
+		want       want
+	}{
+		"Validators": {
+			reason: "The validation markers and the mutual exclusivity rules should be generated for the spec fields.",
+			validators: map[string]config.FieldValidator{
+				"name":          {Pattern: "^[a-z-]+$", ExclusiveWith: []string{"name_prefix"}},
+				"rule.priority": {Minimum: &minimum, Maximum: &maximum},
+				"name_prefix":   {Enum: []string{"app-", "web-"}},
+			},
+			want: want{
+				markers: map[string]string{
+					"example.Parameters:Name":          "// +kubebuilder:validation:Pattern=`^[a-z-]+$`\n",
+					"example.Observation:Name":         "",
+					"example.Parameters:NamePrefix":    "// +kubebuilder:validation:Enum=\"app-\";\"web-\"\n",
+					"example.Observation:NamePrefix":   "",
+					"example.RuleParameters:Priority":  "// +kubebuilder:validation:Minimum=1\n// +kubebuilder:validation:Maximum=100\n",
+					"example.RuleObservation:Priority": "",
+				},
+				rules: "\n" + `// +kubebuilder:validation:XValidation:rule="!has(self.forProvider.name) || !has(self.forProvider.namePrefix)",message="name and namePrefix are mutually exclusive"`,
+			},
+		},
+		"UnknownField": {
+			reason: "An error should be returned if a validator does not match a field.",
+			validators: map[string]config.FieldValidator{
+				"rule.weight": {Minimum: &minimum},
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`field validator "rule.weight" does not match any spec field`), "cannot build the Types"),
+			},
+		},
+		"ObservationField": {
+			reason: "An error should be returned if a validator is configured for an observation field.",
+			validators: map[string]config.FieldValidator{
+				"arn": {Pattern: "^arn:"},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errors.New(`field is not in the spec`), "cannot apply the validator of field arn"), "cannot build the Types"),
+			},
+		},
+		"InvalidRange": {
+			reason: "An error should be returned if a range is configured for a non-numeric field.",
+			validators: map[string]config.FieldValidator{
+				"name": {Minimum: &minimum},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errors.New(`range is not applicable to a field of type TypeString`), "cannot apply the validator of field name"), "cannot build the Types"),
+			},
+		},
+		"InvalidEnum": {
+			reason: "An error should be returned if an enum value of an integer field is not an integer.",
+			validators: map[string]config.FieldValidator{
+				"rule.priority": {Enum: []string{"1", "high"}},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errors.Wrap(errors.Wrap(errors.New(`enum value "high" is not an integer`), "cannot apply the validator of field rule.priority"), "cannot infer type from resource schema of element type of .Rule"), "cannot infer type from schema of field rule"), "cannot build the Types"),
+			},
+		},
+		"NestedExclusivity": {
+			reason: "An error should be returned if a nested field is configured as mutually exclusive.",
+			validators: map[string]config.FieldValidator{
+				"rule.priority": {ExclusiveWith: []string{"name"}},
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`field "rule.priority" exclusive with "name" is not a top-level spec field`), "cannot build the Types"),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource: newResource(),
+				FieldValidators:   tc.validators,
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nBuild(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			markers := make(map[string]string, len(tc.want.markers))
+			for k := range tc.want.markers {
+				markers[k] = ""
+				for _, l := range strings.SplitAfter(g.Comments[k], "\n") {
+					if strings.Contains(l, "Pattern=") || strings.Contains(l, "imum=") || strings.Contains(l, "Enum=") {
+						markers[k] += l
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.want.markers, markers); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want validation markers, +got validation markers:\n%s", tc.reason, diff)
+			}
+			rules := strings.ReplaceAll(g.ValidationRules, "\n"+`// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.name)",message="name is a required parameter"`, "")
+			if diff := cmp.Diff(tc.want.rules, rules); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want validation rules, +got validation rules:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildDataSource(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"arn": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+	type want struct {
+		required string
+		rules    string
+	}
+	cases := map[string]struct {
+		reason     string
+		dataSource bool
+		want       want
+	}{
+		"Resource": {
+			reason: "The required arguments of a resource should only be required if it's not only observed.",
+			want: want{
+				required: "// +kubebuilder:validation:Optional\n",
+				rules:    "\n" + `// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.name)",message="name is a required parameter"`,
+			},
+		},
+		"DataSource": {
+			reason:     "The required arguments of a data source should be required for observing it.",
+			dataSource: true,
+			want: want{
+				required: "// +kubebuilder:validation:Required\n",
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource: tfResource,
+				DataSource:        tc.dataSource,
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+			if err != nil {
+				t.Fatalf("\n%s\nBuild(...): unexpected error: %v", tc.reason, err)
+			}
+			required := ""
+			for _, l := range strings.SplitAfter(g.Comments["example.Parameters:Name"], "\n") {
+				if strings.Contains(l, "validation:Required") || strings.Contains(l, "validation:Optional") {
+					required += l
+				}
+			}
+			if diff := cmp.Diff(tc.want.required, required); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want required marker, +got required marker:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rules, g.ValidationRules); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want validation rules, +got validation rules:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildCompositionFieldPaths(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"filter_prefix": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+	type want struct {
+		paths map[string]string
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		fields []string
+		want   want
+	}{
+		"Fields": {
+			reason: "The paths of the composition fields in the managed resource should be returned.",
+			fields: []string{"region", "rule.filter_prefix", "endpoint"},
+			want: want{
+				paths: map[string]string{
+					"region":             "spec.forProvider.region",
+					"rule.filter_prefix": "spec.forProvider.rule[*].filterPrefix",
+					"endpoint":           "status.atProvider.endpoint",
+				},
+			},
+		},
+		"UnknownField": {
+			reason: "An error should be returned if a composition field does not exist in the schema.",
+			fields: []string{"zone"},
+			want: want{
+				err: errors.Wrap(errors.New(`composition field "zone" does not match any field`), "cannot build the Types"),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource: tfResource,
+				CompositionHints:  config.CompositionHints{Fields: tc.fields},
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nBuild(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.paths, g.CompositionFieldPaths); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want composition field paths, +got composition field paths:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildOverrideFieldNames(t *testing.T) {
+	newResource := func() *schema.Resource {
+		return &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"rule": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"priority": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	type want struct {
+		ruleType   string
+		collisions map[string]string
+		err        error
+	}
+	cases := map[string]struct {
+		reason    string
+		overrides map[string]string
+		want      want
+	}{
+		"Collision": {
+			reason: "A type name collision should be resolved and reported.",
+			want: want{
+				ruleType:   "[]OtherRuleParameters",
+				collisions: map[string]string{"rule": "OtherRule"},
+			},
+		},
+		"Override": {
+			reason:    "The overridden type name should be used.",
+			overrides: map[string]string{"rule": "PinnedRule"},
+			want: want{
+				ruleType: "[]PinnedRuleParameters",
+			},
+		},
+		"OverrideInUse": {
+			reason:    "An error should be returned if the overridden type name is already in use.",
+			overrides: map[string]string{"rule": "Rule"},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errors.Wrap(errors.New("overridden type name RuleParameters is already in use"), "cannot infer type from resource schema of element type of Other.Rule"), "cannot infer type from schema of field rule"), "cannot build the Types"),
+			},
+		},
+		"UnknownBlock": {
+			reason:    "An error should be returned if an overridden field name does not match a block.",
+			overrides: map[string]string{"filter": "Filter"},
+			want: want{
+				err: errors.Wrap(errors.New(`overridden field name "filter" does not match any block`), "cannot build the Types"),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			pkg := types.NewPackage("example", "v1alpha1")
+			if _, err := NewBuilder(pkg).Build(&config.Resource{TerraformResource: newResource(), Kind: "Example"}); err != nil {
+				t.Fatalf("Build(...): unexpected error: %v", err)
+			}
+			g, err := NewBuilder(pkg).Build(&config.Resource{TerraformResource: newResource(), Kind: "Other", OverrideFieldNames: tc.overrides})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nBuild(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			s := g.ForProviderType.Underlying().(*types.Struct)
+			if diff := cmp.Diff(tc.want.ruleType, types.TypeString(s.Field(0).Type(), func(*types.Package) string { return "" })); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want rule type, +got rule type:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.collisions, g.ResolvedTypeNameCollisions); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want collisions, +got collisions:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildPinnedTypeNames(t *testing.T) {
+	newResource := func() *schema.Resource {
+		return &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"rule": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"priority": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	type want struct {
+		exampleNames map[string]string
+		otherNames   map[string]string
+		collisions   map[string]string
+	}
+	cases := map[string]struct {
+		reason  string
+		mapping TypeNameMapping
+		want    want
+	}{
+		"NotPinned": {
+			reason: "The type name of the block processed first should not be prefixed.",
+			want: want{
+				exampleNames: map[string]string{"rule": "Rule"},
+				otherNames:   map[string]string{"rule": "OtherRule"},
+				collisions:   map[string]string{"rule": "OtherRule"},
+			},
+		},
+		"Pinned": {
+			reason: "The pinned type name should be used for the block it's mapped to even if it's processed later.",
+			mapping: TypeNameMapping{
+				"example_other": {"rule": "Rule"},
+			},
+			want: want{
+				exampleNames: map[string]string{"rule": "ExampleRule"},
+				otherNames:   map[string]string{"rule": "Rule"},
+			},
+		},
+		"PinnedTopLevel": {
+			reason: "The pinned type name should not be used if it's the name of a top-level type.",
+			mapping: TypeNameMapping{
+				"example_other": {"rule": "Example"},
+			},
+			want: want{
+				exampleNames: map[string]string{"rule": "Rule"},
+				otherNames:   map[string]string{"rule": "OtherRule"},
+				collisions:   map[string]string{"rule": "OtherRule"},
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			pkg := types.NewPackage("example", "v1alpha1")
+			pinned := NewPinnedTypeNames(pkg, tc.mapping)
+			g, err := NewBuilder(pkg, WithPinnedTypeNames(pinned)).Build(&config.Resource{TerraformResource: newResource(), Name: "example_example", Kind: "Example"})
+			if err != nil {
+				t.Fatalf("Build(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want.exampleNames, g.TypeNames); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want type names, +got type names:\n%s", tc.reason, diff)
+			}
+			g, err = NewBuilder(pkg, WithPinnedTypeNames(pinned)).Build(&config.Resource{TerraformResource: newResource(), Name: "example_other", Kind: "Other"})
+			if err != nil {
+				t.Fatalf("Build(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want.otherNames, g.TypeNames); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want type names, +got type names:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.collisions, g.ResolvedTypeNameCollisions); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want collisions, +got collisions:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildCollapsedBlocks(t *testing.T) {
+	block := func(typ schema.ValueType, maxItems int, s map[string]*schema.Schema) *schema.Schema {
+		return &schema.Schema{
+			Type:     typ,
+			Optional: true,
+			MaxItems: maxItems,
+			Elem:     &schema.Resource{Schema: s},
+		}
+	}
+	leaf := map[string]*schema.Schema{
+		"prefix": {Type: schema.TypeString, Optional: true},
+	}
+	cfg := &config.Resource{
+		TerraformResource: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"rule": block(schema.TypeList, 0, map[string]*schema.Schema{
+					"filter":      block(schema.TypeList, 1, leaf),
+					"destination": block(schema.TypeSet, 0, leaf),
+				}),
+			},
+		},
+		MaxBlockNestingDepth: 1,
+	}
+	g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+	if err != nil {
+		t.Fatalf("Build(...): unexpected error: %v", err)
+	}
+	// the schemaless fields have no type for the CEL rules, so they should
+	// have no validation rules.
+	schemaless := "// +kubebuilder:validation:Schemaless\n"
+	want := map[string]string{
+		"example.RuleParameters:Filter":       schemaless,
+		"example.RuleParameters:Destination":  schemaless,
+		"example.RuleObservation:Filter":      schemaless,
+		"example.RuleObservation:Destination": schemaless,
+	}
+	got := make(map[string]string, len(want))
+	for k := range want {
+		got[k] = ""
+		for _, l := range strings.SplitAfter(g.Comments[k], "\n") {
+			if strings.Contains(l, "+kubebuilder:validation:XValidation") || strings.Contains(l, "+kubebuilder:validation:Schemaless") {
+				got[k] += l
+			}
+		}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Build(...): -want validation markers, +got validation markers:\n%s", diff)
+	}
+}
+
+func TestBuildListMapKeys(t *testing.T) {
+	block := func(typ schema.ValueType, s map[string]*schema.Schema) *schema.Schema {
+		return &schema.Schema{
+			Type:     typ,
+			Optional: true,
+			Elem:     &schema.Resource{Schema: s},
+		}
+	}
+	newResource := func() *schema.Resource {
+		return &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"rule": block(schema.TypeSet, map[string]*schema.Schema{
+					"name":     {Type: schema.TypeString, Required: true},
+					"priority": {Type: schema.TypeInt, Optional: true},
+				}),
+				"filter": block(schema.TypeList, map[string]*schema.Schema{
+					"key":   {Type: schema.TypeString, Required: true},
+					"value": {Type: schema.TypeString, Optional: true},
+				}),
+				"tag": block(schema.TypeSet, map[string]*schema.Schema{
+					"name": {Type: schema.TypeString, Optional: true},
+				}),
+				"zones": {
+					Type:     schema.TypeSet,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		}
+	}
+	type want struct {
+		markers map[string]string
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		keys   map[string][]string
+		want   want
+	}{
+		"Inferred": {
+			reason: "The set blocks with a required identifying field should be keyed by it.",
+			want: want{
+				markers: map[string]string{
+					"example.ExampleParameters:Rule":    "// +listType=map\n// +listMapKey=name\n",
+					"example.ExampleObservation:Rule":   "",
+					"example.ExampleParameters:Filter":  "",
+					"example.ExampleParameters:Tag":     "",
+					"example.ExampleObservation:Filter": "",
+					"example.ExampleParameters:Zones":   "// +listType=set\n",
+					"example.ExampleObservation:Zones":  "",
+				},
+			},
+		},
+		"NotRequiredKey": {
+			reason: "An error should be returned if a configured list map key is not a required field.",
+			keys:   map[string][]string{"filter": {"key", "value"}},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errors.New(`list map key "value" is not a required primitive field of the block`), "cannot set the list type of field filter"), "cannot build the Types"),
+			},
+		},
+		"Configured": {
+			reason: "The configured list map keys should be generated for the list blocks.",
+			keys:   map[string][]string{"filter": {"key"}},
+			want: want{
+				markers: map[string]string{
+					"example.ExampleParameters:Filter":  "// +listType=map\n// +listMapKey=key\n",
+					"example.ExampleObservation:Filter": "",
+				},
+			},
+		},
+		"NotABlock": {
+			reason: "An error should be returned if the list map keys are configured for a field which is not a block.",
+			keys:   map[string][]string{"rule.name": {"name"}},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errors.Wrap(errors.Wrap(errors.New("list map keys are only applicable to the list and set blocks of the spec"), "cannot set the list type of field rule.name"), "cannot infer type from resource schema of element type of Example.Rule"), "cannot infer type from schema of field rule"), "cannot build the Types"),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource: newResource(),
+				Kind:              "Example",
+				ListMapKeys:       tc.keys,
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nBuild(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			markers := make(map[string]string, len(tc.want.markers))
+			for k := range tc.want.markers {
+				markers[k] = ""
+				for _, l := range strings.SplitAfter(g.Comments[k], "\n") {
+					if strings.Contains(l, "+listType=") || strings.Contains(l, "+listMapKey=") {
+						markers[k] += l
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.want.markers, markers); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want list markers, +got list markers:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildSensitiveBlocks(t *testing.T) {
+	cfg := &config.Resource{
+		TerraformResource: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"auth": {
+					Type:      schema.TypeList,
+					Optional:  true,
+					MaxItems:  1,
+					Sensitive: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"password": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"port": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+							"token": {
+								Type:     schema.TypeString,
+								Computed: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		SingletonListEmbedding: config.SingletonListEmbedding{Enabled: true},
+	}
+	g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+	if err != nil {
+		t.Fatalf("Build(...): unexpected error: %v", err)
+	}
+	got := map[string]string{}
+	for _, n := range g.Types {
+		if n.Obj().Name() == "AuthParameters" || n.Obj().Name() == "AuthObservation" {
+			got[n.Obj().Name()] = n.Obj().String()
+		}
+	}
+	want := map[string]string{
+		"AuthParameters":  `type example.AuthParameters struct{PasswordSecretRef github.com/crossplane/crossplane-runtime/apis/common/v1.SecretKeySelector "json:\"passwordSecretRef\" tf:\"-\""; Port *int64 "json:\"port,omitempty\" tf:\"port,omitempty\""}`,
+		"AuthObservation": `type example.AuthObservation struct{Port *int64 "json:\"port,omitempty\" tf:\"port,omitempty\""}`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Build(...): -want types, +got types:\n%s", diff)
+	}
+	wantPaths := map[string]string{
+		"auth[*].password": "spec.forProvider.auth[*].passwordSecretRef",
+		"auth[*].token":    "status.atProvider.auth[*].token",
+	}
+	if diff := cmp.Diff(wantPaths, cfg.Sensitive.GetFieldPaths()); diff != "" {
+		t.Errorf("Build(...): -want sensitive field paths, +got sensitive field paths:\n%s", diff)
+	}
+}
+
+func TestBuildInitProvider(t *testing.T) {
+	cfg := &config.Resource{
+		TerraformResource: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"region": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Computed: true,
+				},
+				"capacity": {
+					Type:     schema.TypeInt,
+					Optional: true,
+					Computed: true,
+				},
+				"description": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"password": {
+					Type:      schema.TypeString,
+					Optional:  true,
+					Computed:  true,
+					Sensitive: true,
+				},
+				"rule": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"prefix": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"storage_class": {
+								Type:     schema.TypeString,
+								Optional: true,
+								Computed: true,
+							},
+						},
+					},
+				},
+				"logging": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"target": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		ExternalName: config.ExternalName{
+			IdentifierFields: []string{"region"},
+		},
+	}
+	g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+	if err != nil {
+		t.Fatalf("Build(...): unexpected error: %v", err)
+	}
+	got := map[string]string{}
+	for _, n := range g.Types {
+		if strings.HasSuffix(n.Obj().Name(), "InitParameters") {
+			got[n.Obj().Name()] = n.Obj().String()
+		}
+	}
+	want := map[string]string{
+		"InitParameters":        `type example.InitParameters struct{Capacity *int64 "json:\"capacity,omitempty\" tf:\"capacity,omitempty\""; Rule []example.RuleInitParameters "json:\"rule,omitempty\" tf:\"rule,omitempty\""}`,
+		"LoggingInitParameters": `type example.LoggingInitParameters struct{}`,
+		"RuleInitParameters":    `type example.RuleInitParameters struct{StorageClass *string "json:\"storageClass,omitempty\" tf:\"storage_class,omitempty\""}`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Build(...): -want init parameter types, +got init parameter types:\n%s", diff)
+	}
+	if n := g.InitProviderType.Obj().Name(); n != "InitParameters" {
+		t.Errorf("Build(...): want init provider type %q, got %q", "InitParameters", n)
+	}
+	if c, ok := g.Comments["example.RuleInitParameters:StorageClass"]; !ok || strings.Contains(c, "kubebuilder:validation:Required") {
+		t.Errorf("Build(...): init parameter should not be required: %q", c)
+	}
+}
+
+func TestBuildSchemaDefaults(t *testing.T) {
+	newResource := func() *schema.Resource {
+		return &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"acl": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  "private",
+				},
+				"force_destroy": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+				"legacy": {
+					Type:     schema.TypeInt,
+					Optional: true,
+					Default:  1,
+				},
+				"password": {
+					Type:      schema.TypeString,
+					Optional:  true,
+					Sensitive: true,
+					Default:   "secret",
+				},
+			},
+		}
+	}
+	cases := map[string]struct {
+		reason  string
+		enabled bool
+		want    map[string]string
+	}{
+		"Enabled": {
+			reason:  "The static defaults of the non-sensitive and non-deprecated spec fields should be generated.",
+			enabled: true,
+			want: map[string]string{
+				"example.Parameters:ACL":           "// +kubebuilder:default=\"private\"\n",
+				"example.Observation:ACL":          "",
+				"example.Parameters:ForceDestroy":  "// +kubebuilder:default=false\n",
+				"example.Observation:ForceDestroy": "",
+				"example.Parameters:Legacy":        "",
+			},
+		},
+		"Disabled": {
+			reason: "No defaults should be generated if the schema defaults are not enabled.",
+			want: map[string]string{
+				"example.Parameters:ACL":          "",
+				"example.Parameters:ForceDestroy": "",
+				"example.Parameters:Legacy":       "",
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource: newResource(),
+				SchemaDefaults:    tc.enabled,
+				DeprecatedFields:  map[string]config.FieldDeprecation{"legacy": {}},
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+			if err != nil {
+				t.Fatalf("\n%s\nBuild(...): unexpected error: %v", tc.reason, err)
+			}
+			markers := make(map[string]string, len(tc.want))
+			for k := range tc.want {
+				markers[k] = ""
+				for _, l := range strings.SplitAfter(g.Comments[k], "\n") {
+					if strings.Contains(l, "+kubebuilder:default=") {
+						markers[k] += l
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.want, markers); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want default markers, +got default markers:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildNestedDocStrings(t *testing.T) {
+	block := func(s map[string]*schema.Schema) *schema.Schema {
+		return &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     &schema.Resource{Schema: s},
+		}
+	}
+	cfg := &config.Resource{
+		TerraformResource: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"rule": block(map[string]*schema.Schema{
+					"filter": block(map[string]*schema.Schema{
+						"prefix": {Type: schema.TypeString, Optional: true},
+					}),
+					"destination": block(map[string]*schema.Schema{
+						"prefix": {Type: schema.TypeString, Optional: true},
+					}),
+				}),
+			},
+		},
+		MetaResource: &registry.Resource{
+			ArgumentDocs: map[string]string{
+				"rule":               "(Optional) The rule configuration block.",
+				"filter":             "(Optional) The filter configuration block.",
+				"destination.prefix": "(Optional) The prefix of the destination.",
+				"filter.prefix":      "(Optional) The prefix of the filtered objects.",
+				"prefix":             "(Optional) The prefix of the rule.",
+			},
+		},
+	}
+	g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+	if err != nil {
+		t.Fatalf("Build(...): unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"example.Parameters:Rule":               "// The rule configuration block.",
+		"example.RuleParameters:Filter":         "// The filter configuration block.",
+		"example.FilterParameters:Prefix":       "// The prefix of the filtered objects.",
+		"example.DestinationParameters:Prefix":  "// The prefix of the destination.",
+		"example.DestinationObservation:Prefix": "// The prefix of the destination.",
+	}
+	got := make(map[string]string, len(want))
+	for k := range want {
+		got[k] = strings.SplitN(g.Comments[k], "\n", 2)[0]
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Build(...): -want doc strings, +got doc strings:\n%s", diff)
+	}
+}
+
+func TestBuildDocsComments(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"priority": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+	const u = "https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/wafv2_web_acl"
+	cases := map[string]struct {
+		reason  string
+		docsURL string
+		want    map[string]string
+	}{
+		"NoDocsURL": {
+			reason: "The types should not be linked to any documentation if the resource has no documentation URL.",
+			want: map[string]string{
+				"example.Parameters":     "",
+				"example.RuleParameters": "",
+			},
+		},
+		"DocsURL": {
+			reason:  "The top-level types should be linked to the documentation of the resource and the types of the blocks to their sections.",
+			docsURL: u,
+			want: map[string]string{
+				"example.Parameters":         "// Terraform documentation: " + u,
+				"example.InitParameters":     "// Terraform documentation: " + u,
+				"example.Observation":        "// Terraform documentation: " + u,
+				"example.RuleParameters":     "// Terraform documentation: " + u + "#rule",
+				"example.RuleInitParameters": "// Terraform documentation: " + u + "#rule",
+				"example.RuleObservation":    "// Terraform documentation: " + u + "#rule",
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource: tfResource,
+				DocsURL:           tc.docsURL,
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+			if err != nil {
+				t.Fatalf("\n%s\nBuild(...): unexpected error: %v", tc.reason, err)
+			}
+			got := make(map[string]string, len(tc.want))
+			for k := range tc.want {
+				got[k] = g.Comments[k]
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want type comments, +got type comments:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildTruncatedTypeNames(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"statement_configuration": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"priority": {
+										Type:     schema.TypeInt,
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	type want struct {
+		truncated map[string]string
+		err       error
+	}
+	cases := map[string]struct {
+		reason    string
+		maxLength int
+		want      want
+	}{
+		"NoTruncation": {
+			reason: "The type names should not be truncated if the maximum length is not configured.",
+		},
+		"Truncated": {
+			reason:    "The type names longer than the maximum length should be truncated and suffixed with a hash.",
+			maxLength: 30,
+			want: want{
+				truncated: map[string]string{
+					"rule.statement_configuration": "Statemen75d6fd89",
+				},
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource: tfResource,
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1"), WithMaxTypeNameLength(tc.maxLength)).Build(cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nBuild(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.truncated, g.TruncatedTypeNames); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want truncated type names, +got truncated type names:\n%s", tc.reason, diff)
+			}
+			for _, n := range g.TypeNames {
+				if tc.maxLength > 0 && len(n+"InitParameters") > tc.maxLength {
+					t.Errorf("\n%s\nBuild(...): type name %s exceeds the maximum length %d", tc.reason, n, tc.maxLength)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildCollapsedObservationBlocks(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"endpoint": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"credentials": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"token": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+					},
+				},
+			},
+		},
+	}
+	type want struct {
+		atProvider string
+		collapsed  []string
+	}
+	cases := map[string]struct {
+		reason   string
+		collapse bool
+		want     want
+	}{
+		"NotCollapsed": {
+			reason: "The computed-only blocks should be generated as typed fields by default.",
+			want: want{
+				atProvider: `type example.Observation struct{Credentials []example.CredentialsObservation "json:\"credentials,omitempty\" tf:\"credentials,omitempty\""; Endpoint []example.EndpointObservation "json:\"endpoint,omitempty\" tf:\"endpoint,omitempty\""; Name *string "json:\"name,omitempty\" tf:\"name,omitempty\""}`,
+			},
+		},
+		"Collapsed": {
+			reason:   "The computed-only blocks without sensitive fields should be collapsed into runtime.RawExtension fields.",
+			collapse: true,
+			want: want{
+				atProvider: `type example.Observation struct{Credentials []example.CredentialsObservation "json:\"credentials,omitempty\" tf:\"credentials,omitempty\""; Endpoint *k8s.io/apimachinery/pkg/runtime.RawExtension "json:\"endpoint,omitempty\" tf:\"endpoint,omitempty\""; Name *string "json:\"name,omitempty\" tf:\"name,omitempty\""}`,
+				collapsed:  []string{"endpoint"},
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource:         tfResource,
+				CollapseObservationBlocks: tc.collapse,
+				Sensitive: config.Sensitive{
+					AdditionalConnectionDetailsFn: config.NopAdditionalConnectionDetails,
+				},
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+			if err != nil {
+				t.Fatalf("\n%s\nBuild(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.atProvider, g.AtProviderType.Obj().String()); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want atProvider, +got atProvider:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.collapsed, g.CollapsedObservationPaths); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want collapsed paths, +got collapsed paths:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildExactlyOneOf(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"cidr_block": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"cidr_block", "ipv4_ipam_pool_id"},
+			},
+			"ipv4_ipam_pool_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"cidr_block", "ipv4_ipam_pool_id"},
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allow": {
+							Type:         schema.TypeBool,
+							Optional:     true,
+							ExactlyOneOf: []string{"rule.0.allow", "rule.0.block"},
+						},
+						"block": {
+							Type:         schema.TypeBool,
+							Optional:     true,
+							ExactlyOneOf: []string{"rule.0.allow", "rule.0.block"},
+						},
+						"region": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ExactlyOneOf: []string{"rule.0.region", "cidr_block"},
+						},
+					},
+				},
+			},
+		},
+	}
+	type want struct {
+		rules       string
+		typeComment string
+	}
+	cases := map[string]struct {
+		reason     string
+		dataSource bool
+		renames    map[string]string
+		want       want
+	}{
+		"Resource": {
+			reason: "The top-level constraints should only be enforced if the resource is created or updated, the nested ones should be enforced on their blocks, and the ones spanning blocks should be skipped.",
+			want: want{
+				rules:       "\n" + `// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || (has(self.forProvider.cidrBlock) ? 1 : 0) + (has(self.forProvider.ipv4IpamPoolId) || has(self.initProvider.ipv4IpamPoolId) ? 1 : 0) == 1",message="exactly one of cidrBlock, ipv4IpamPoolId must be set"`,
+				typeComment: `// +kubebuilder:validation:XValidation:rule="(has(self.allow) ? 1 : 0) + (has(self.block) ? 1 : 0) == 1",message="exactly one of allow, block must be set"`,
+			},
+		},
+		"Renamed": {
+			reason: "The constraints of the renamed fields should be enforced with their CRD names.",
+			renames: map[string]string{
+				"cidr_block": "ipv4Cidr",
+				"rule.allow": "permit",
+			},
+			want: want{
+				rules:       "\n" + `// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || (has(self.forProvider.ipv4Cidr) ? 1 : 0) + (has(self.forProvider.ipv4IpamPoolId) || has(self.initProvider.ipv4IpamPoolId) ? 1 : 0) == 1",message="exactly one of ipv4Cidr, ipv4IpamPoolId must be set"`,
+				typeComment: `// +kubebuilder:validation:XValidation:rule="(has(self.permit) ? 1 : 0) + (has(self.block) ? 1 : 0) == 1",message="exactly one of permit, block must be set"`,
+			},
+		},
+		"DataSource": {
+			reason:     "The top-level constraints of a data source should be enforced for observing it.",
+			dataSource: true,
+			want: want{
+				rules:       "\n" + `// +kubebuilder:validation:XValidation:rule="(has(self.forProvider.cidrBlock) ? 1 : 0) + (has(self.forProvider.ipv4IpamPoolId) || has(self.initProvider.ipv4IpamPoolId) ? 1 : 0) == 1",message="exactly one of cidrBlock, ipv4IpamPoolId must be set"`,
+				typeComment: `// +kubebuilder:validation:XValidation:rule="(has(self.allow) ? 1 : 0) + (has(self.block) ? 1 : 0) == 1",message="exactly one of allow, block must be set"`,
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource: tfResource,
+				DataSource:        tc.dataSource,
+				FieldRenames:      tc.renames,
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+			if err != nil {
+				t.Fatalf("\n%s\nBuild(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.rules, g.ValidationRules); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want validation rules, +got validation rules:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.typeComment, g.Comments["example.RuleParameters"]); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want type comment, +got type comment:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildConflictsWith(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"cidr_block": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ipv4_ipam_pool_id"},
+			},
+			"ipv4_ipam_pool_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"cidr_block"},
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allow": {
+							Type:          schema.TypeBool,
+							Optional:      true,
+							ConflictsWith: []string{"rule.0.block", "cidr_block"},
+						},
+						"block": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+	g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(&config.Resource{TerraformResource: tfResource})
+	if err != nil {
+		t.Fatalf("Build(...): unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"rules":   "\n" + `// +kubebuilder:validation:XValidation:rule="!(has(self.forProvider.cidrBlock)) || !(has(self.forProvider.ipv4IpamPoolId))",message="cidrBlock and ipv4IpamPoolId are mutually exclusive"`,
+		"type":    `// +kubebuilder:validation:XValidation:rule="!(has(self.allow)) || !(has(self.block))",message="allow and block are mutually exclusive"`,
+		"comment": "// Conflicts with rule.block, cidrBlock.\n// +kubebuilder:validation:Optional\n",
+	}
+	got := map[string]string{
+		"rules":   g.ValidationRules,
+		"type":    g.Comments["example.RuleParameters"],
+		"comment": g.Comments["example.RuleParameters:Allow"],
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Build(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestBuildRequiredWithAtLeastOneOf(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"username", "password"},
+			},
+			"password": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allow": {
+							Type:         schema.TypeBool,
+							Optional:     true,
+							AtLeastOneOf: []string{"rule.0.allow", "rule.0.block"},
+						},
+						"block": {
+							Type:         schema.TypeBool,
+							Optional:     true,
+							AtLeastOneOf: []string{"rule.0.allow", "rule.0.block"},
+						},
+					},
+				},
+			},
+		},
+	}
+	type want struct {
+		rules       string
+		typeComment string
+	}
+	cases := map[string]struct {
+		reason  string
+		disable bool
+		want    want
+	}{
+		"Enabled": {
+			reason: "The RequiredWith and AtLeastOneOf constraints should be translated into CEL rules.",
+			want: want{
+				rules:       "\n" + `// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || !(has(self.forProvider.username)) || (has(self.forProvider.password))",message="password is required when username is set"`,
+				typeComment: `// +kubebuilder:validation:XValidation:rule="(has(self.allow) ? 1 : 0) + (has(self.block) ? 1 : 0) >= 1",message="at least one of allow, block must be set"`,
+			},
+		},
+		"Disabled": {
+			reason:  "No CEL rules should be generated for the constraints if they're disabled for the resource.",
+			disable: true,
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource:      tfResource,
+				DisableConstraintRules: tc.disable,
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+			if err != nil {
+				t.Fatalf("\n%s\nBuild(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.rules, g.ValidationRules); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want validation rules, +got validation rules:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.typeComment, g.Comments["example.RuleParameters"]); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want type comment, +got type comment:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildPrinterColumns(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"location": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+	configured := []config.PrinterColumn{{Name: "NAME", Type: "string", JSONPath: ".spec.forProvider.name"}}
+	cases := map[string]struct {
+		reason  string
+		columns []config.PrinterColumn
+		disable bool
+		want    []config.PrinterColumn
+	}{
+		"Auto": {
+			reason: "The printer columns of the first state and location attributes should be derived if none are configured.",
+			want: []config.PrinterColumn{
+				{Name: "STATUS", Type: "string", JSONPath: ".status.atProvider.status"},
+				{Name: "REGION", Type: "string", JSONPath: ".status.atProvider.region"},
+			},
+		},
+		"Configured": {
+			reason:  "The configured printer columns should override the derived ones.",
+			columns: configured,
+			want:    configured,
+		},
+		"Disabled": {
+			reason:  "No printer columns should be derived if they're disabled.",
+			disable: true,
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource:         tfResource,
+				PrinterColumns:            tc.columns,
+				DisableAutoPrinterColumns: tc.disable,
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(cfg)
+			if err != nil {
+				t.Fatalf("\n%s\nBuild(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, g.PrinterColumns); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want printer columns, +got printer columns:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildFieldMaturities(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The name of the resource.",
+			},
+			"preview": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The preview feature.",
+			},
+		},
+	}
+	type want struct {
+		forProvider string
+		comment     string
+		err         error
+	}
+	cases := map[string]struct {
+		reason     string
+		version    string
+		maturities map[string]config.FieldMaturity
+		versions   []string
+		want       want
+	}{
+		"Prefixed": {
+			reason:     "The descriptions of the fields should be prefixed with their maturity levels.",
+			version:    "v1beta1",
+			maturities: map[string]config.FieldMaturity{"preview": config.FieldMaturityAlpha},
+			want: want{
+				forProvider: `type example.Parameters struct{Name *string "json:\"name,omitempty\" tf:\"name,omitempty\""; Preview *string "json:\"preview,omitempty\" tf:\"preview,omitempty\""}`,
+				comment:     "// (Alpha) The preview feature.\n// +kubebuilder:validation:Optional\n",
+			},
+		},
+		"AlphaFieldVersion": {
+			reason:     "The alpha fields should be generated in the alpha field versions.",
+			version:    "v1alpha2",
+			maturities: map[string]config.FieldMaturity{"preview": config.FieldMaturityAlpha},
+			versions:   []string{"v1alpha2"},
+			want: want{
+				forProvider: `type example.Parameters struct{Name *string "json:\"name,omitempty\" tf:\"name,omitempty\""; Preview *string "json:\"preview,omitempty\" tf:\"preview,omitempty\""}`,
+				comment:     "// (Alpha) The preview feature.\n// +kubebuilder:validation:Optional\n",
+			},
+		},
+		"NotAlphaFieldVersion": {
+			reason:     "The alpha fields should not be generated in the other versions if the alpha field versions are configured.",
+			version:    "v1beta1",
+			maturities: map[string]config.FieldMaturity{"preview": config.FieldMaturityAlpha},
+			versions:   []string{"v1alpha2"},
+			want: want{
+				forProvider: `type example.Parameters struct{Name *string "json:\"name,omitempty\" tf:\"name,omitempty\""}`,
+			},
+		},
+		"UnknownMaturity": {
+			reason:     "An error should be returned for an unknown maturity level.",
+			version:    "v1beta1",
+			maturities: map[string]config.FieldMaturity{"preview": "Experimental"},
+			want: want{
+				err: errors.Wrap(errors.Errorf("maturity %q of field %q is not one of %q, %q or %q", "Experimental", "preview", config.FieldMaturityAlpha, config.FieldMaturityBeta, config.FieldMaturityStable), "cannot build the Types"),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				TerraformResource:  tfResource,
+				FieldMaturities:    tc.maturities,
+				AlphaFieldVersions: tc.versions,
+			}
+			g, err := NewBuilder(types.NewPackage("example", tc.version)).Build(cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nBuild(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.forProvider, g.ForProviderType.Obj().String()); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want forProvider, +got forProvider:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.comment, g.Comments["example.Parameters:Preview"]); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want comment, +got comment:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildSharedBlocks(t *testing.T) {
+	endpoint := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"address": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	}
+	type want struct {
+		forProvider string
+		err         error
+	}
+	cases := map[string]struct {
+		reason string
+		block  *schema.Resource
+		shared map[string]string
+		want   want
+	}{
+		"Shared": {
+			reason: "The types of a shared block should be used for the identical blocks.",
+			block:  endpoint,
+			shared: map[string]string{"endpoint": "Endpoint"},
+			want: want{
+				forProvider: `type example.Parameters struct{Endpoint []example.com/apis/common.EndpointParameters "json:\"endpoint,omitempty\" tf:\"endpoint,omitempty\""}`,
+			},
+		},
+		"NotIdentical": {
+			reason: "An error should be returned if the schema of a block is not identical to the schema of the shared block.",
+			block: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"address": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+				},
+			},
+			shared: map[string]string{"endpoint": "Endpoint"},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errors.Wrap(errors.Errorf("schema of block %s is not identical to the schema of shared block %q", "endpoint", "Endpoint"), "cannot infer type from resource schema of element type of .Endpoint"), "cannot infer type from schema of field endpoint"), "cannot build the Types"),
+			},
+		},
+		"NotMatched": {
+			reason: "An error should be returned if a shared block does not match any block.",
+			block:  endpoint,
+			shared: map[string]string{"target": "Endpoint"},
+			want: want{
+				err: errors.Wrap(errors.Errorf("shared block %q does not match any block", "target"), "cannot build the Types"),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			sg, err := NewBuilder(types.NewPackage("example.com/apis/common", "common")).BuildSharedBlocks(map[string]*schema.Resource{"Endpoint": endpoint})
+			if err != nil {
+				t.Fatalf("BuildSharedBlocks(...): %v", err)
+			}
+			cfg := &config.Resource{
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"endpoint": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     tc.block,
+						},
+					},
+				},
+				SharedBlocks: tc.shared,
+			}
+			g, err := NewBuilder(types.NewPackage("example", "v1alpha1"), WithSharedBlocks(sg.SharedBlocks)).Build(cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nBuild(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.forProvider, g.ForProviderType.Obj().String()); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want forProvider, +got forProvider:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildExampleValues(t *testing.T) {
+	tfResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":     {Type: schema.TypeString, Optional: true, Description: "The name."},
+			"password": {Type: schema.TypeString, Optional: true, Sensitive: true, Description: "The password."},
+			"vpc_id":   {Type: schema.TypeString, Optional: true, Description: "The VPC ID."},
+			"zones":    {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}, Description: "The zones."},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"port": {Type: schema.TypeInt, Optional: true, Description: "The port."},
+					},
+				},
+			},
+		},
+	}
+	example := map[string]any{
+		"name":     "example",
+		"password": "secret",
+		"vpc_id":   "${aws_vpc.example.id}",
+		"zones":    []any{"a", "b"},
+		"rule":     []any{map[string]any{"port": 443}},
+	}
+	cases := map[string]struct {
+		reason string
+		field  string
+		want   string
+	}{
+		"String": {
+			reason: "The example value of a string argument should be embedded into its description.",
+			field:  "example.Parameters:Name",
+			want:   "// The name.\n// Example: \"example\"\n",
+		},
+		"Sensitive": {
+			reason: "The example value of a sensitive argument should be redacted.",
+			field:  "example.Parameters:PasswordSecretRef",
+			want:   "// The password.\n// Example: <redacted>\n",
+		},
+		"Reference": {
+			reason: "The interpolated example values should not be embedded.",
+			field:  "example.Parameters:VPCID",
+			want:   "// The VPC ID.\n",
+		},
+		"List": {
+			reason: "The example values of the lists of primitives should be embedded.",
+			field:  "example.Parameters:Zones",
+			want:   "// The zones.\n// Example: [\"a\",\"b\"]\n",
+		},
+		"Nested": {
+			reason: "The example values of the nested arguments should be looked up in the first element of the block.",
+			field:  "example.RuleParameters:Port",
+			want:   "// The port.\n// Example: 443\n",
+		},
+	}
+	g, err := NewBuilder(types.NewPackage("example", "v1alpha1")).Build(&config.Resource{
+		TerraformResource: tfResource,
+		MetaResource: &registry.Resource{
+			Examples: []registry.ResourceExample{{Paved: *fieldpath.Pave(example)}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Build(...): %v", err)
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			var got string
+			for _, l := range strings.SplitAfter(g.Comments[tc.field], "\n") {
+				if !strings.HasPrefix(l, "// +") {
+					got += l
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nBuild(...): -want comment, +got comment:\n%s", tc.reason, diff)
+			}
 		})
 	}
 }
@@ -57,12 +57,7 @@ func v2ResourceFromTFJSONSchema(s *tfjson.Schema) *schemav2.Resource {
 		toSchemaMap[k] = tfJSONAttributeToV2Schema(v)
 	}
 	for k, v := range s.Block.NestedBlocks {
-		// Note(turkenh): We see resource timeouts here as NestingModeSingle.
-		// However, in plugin SDK resource timeouts is not part of resource
-		// schema map but set as a separate field. So, we just need to ignore
-		// here.
-		// https://github.com/hashicorp/terraform-plugin-sdk/blob/6461ac6e9044a44157c4e2c8aec0f1ab7efc2055/helper/schema/core_schema.go#L315
-		if v.NestingMode == tfjson.SchemaNestingModeSingle {
+		if isTimeouts(k, v) {
 			continue
 		}
 		toSchemaMap[k] = tfJSONBlockTypeToV2Schema(v)
@@ -74,6 +69,19 @@ func v2ResourceFromTFJSONSchema(s *tfjson.Schema) *schemav2.Resource {
 	return v2Res
 }
 
+// isTimeouts reports whether the given nested block is the resource
+// timeouts block.
+func isTimeouts(key string, nb *tfjson.SchemaBlockType) bool {
+	// Note(turkenh): We see resource timeouts here as NestingModeSingle.
+	// However, in plugin SDK resource timeouts is not part of resource
+	// schema map but set as a separate field. So, we just need to ignore
+	// here.
+	// https://github.com/hashicorp/terraform-plugin-sdk/blob/6461ac6e9044a44157c4e2c8aec0f1ab7efc2055/helper/schema/core_schema.go#L315
+	// The other single nested blocks are declared by the resources of the
+	// Terraform plugin framework and converted to singleton lists.
+	return key == "timeouts" && nb.NestingMode == tfjson.SchemaNestingModeSingle
+}
+
 func tfJSONAttributeToV2Schema(attr *tfjson.SchemaAttribute) *schemav2.Schema {
 	v2sch := &schemav2.Schema{
 		Optional:    attr.Optional,
@@ -83,12 +91,47 @@ func tfJSONAttributeToV2Schema(attr *tfjson.SchemaAttribute) *schemav2.Schema {
 		Deprecated:  deprecatedMessage(attr.Deprecated),
 		Sensitive:   attr.Sensitive,
 	}
+	if attr.AttributeNestedType != nil {
+		tfJSONNestedAttributeTypeToV2Schema(attr.AttributeNestedType, v2sch)
+		return v2sch
+	}
 	if err := schemaV2TypeFromCtyType(attr.AttributeType, v2sch); err != nil {
 		panic(err)
 	}
 	return v2sch
 }
 
+// tfJSONNestedAttributeTypeToV2Schema sets the type of the given schema of
+// a nested attribute, which is only supported by the protocol version 6
+// providers, e.g. the ones built with the Terraform plugin framework. The
+// single nested attributes are converted to singleton lists.
+func tfJSONNestedAttributeTypeToV2Schema(nt *tfjson.SchemaNestedAttributeType, v2sch *schemav2.Schema) {
+	v2sch.MinItems = int(nt.MinItems)
+	v2sch.MaxItems = int(nt.MaxItems)
+	switch nt.NestingMode {
+	case tfjson.SchemaNestingModeSingle:
+		v2sch.Type = schemav2.TypeList
+		v2sch.MaxItems = 1
+	case tfjson.SchemaNestingModeList:
+		v2sch.Type = schemav2.TypeList
+	case tfjson.SchemaNestingModeSet:
+		v2sch.Type = schemav2.TypeSet
+	case tfjson.SchemaNestingModeMap:
+		v2sch.Type = schemav2.TypeMap
+	case tfjson.SchemaNestingModeGroup:
+		panic("unexpected nesting mode: " + nt.NestingMode)
+	default:
+		panic("unknown nesting mode: " + nt.NestingMode)
+	}
+	res := &schemav2.Resource{}
+	res.Schema = make(map[string]*schemav2.Schema, len(nt.Attributes))
+	for key, attr := range nt.Attributes {
+		res.Schema[key] = tfJSONAttributeToV2Schema(attr)
+	}
+	v2sch.ConfigMode = schemav2.SchemaConfigModeAttr
+	v2sch.Elem = res
+}
+
 func tfJSONBlockTypeToV2Schema(nb *tfjson.SchemaBlockType) *schemav2.Schema { //nolint:gocyclo
 	v2sch := &schemav2.Schema{
 		MinItems: int(nb.MinItems),
@@ -115,7 +158,12 @@ func tfJSONBlockTypeToV2Schema(nb *tfjson.SchemaBlockType) *schemav2.Schema { //
 	case tfjson.SchemaNestingModeMap:
 		v2sch.Type = schemav2.TypeMap
 	case tfjson.SchemaNestingModeSingle, tfjson.SchemaNestingModeGroup:
-		panic("unexpected nesting mode: " + nb.NestingMode)
+		// the item limits are not applicable to the single nested blocks
+		// of the Terraform plugin framework, which are converted to
+		// singleton lists.
+		v2sch.Type = schemav2.TypeList
+		v2sch.MaxItems = 1
+		v2sch.Computed = false
 	default:
 		panic("unknown nesting mode: " + nb.NestingMode)
 	}
@@ -133,12 +181,7 @@ func tfJSONBlockTypeToV2Schema(nb *tfjson.SchemaBlockType) *schemav2.Schema { //
 		res.Schema[key] = tfJSONAttributeToV2Schema(attr)
 	}
 	for key, block := range nb.Block.NestedBlocks {
-		// Note(turkenh): We see resource timeouts here as NestingModeSingle.
-		// However, in plugin SDK resource timeouts is not part of resource
-		// schema map but set as a separate field. So, we just need to ignore
-		// here.
-		// https://github.com/hashicorp/terraform-plugin-sdk/blob/6461ac6e9044a44157c4e2c8aec0f1ab7efc2055/helper/schema/core_schema.go#L315
-		if block.NestingMode == tfjson.SchemaNestingModeSingle {
+		if isTimeouts(key, block) {
 			continue
 		}
 		res.Schema[key] = tfJSONBlockTypeToV2Schema(block)
@@ -0,0 +1,191 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package tfprotov6
+
+import (
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	schemav2 "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"github.com/zclconf/go-cty/cty"
+
+	conversiontfjson "github.com/upbound/upjet/pkg/types/conversion/tfjson"
+)
+
+// GetV2ResourceMap converts input resource schemas with the Terraform
+// plugin protocol version 6 representation, e.g. the ones served by the
+// providers built with the Terraform plugin framework, to
+// terraform-plugin-sdk representation which is what Upjet expects today.
+//
+// The schemas are first converted to their "terraform-json" representation,
+// which is the output of `terraform providers schema -json` for the same
+// provider, so that the resources of the SDK and the framework providers
+// are converted alike.
+func GetV2ResourceMap(resourceSchemas map[string]*tfprotov6.Schema) (map[string]*schemav2.Resource, error) {
+	jsonSchemas := make(map[string]*tfjson.Schema, len(resourceSchemas))
+	for k, v := range resourceSchemas {
+		s, err := tfJSONSchema(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot convert the schema of resource %s", k)
+		}
+		jsonSchemas[k] = s
+	}
+	return conversiontfjson.GetV2ResourceMap(jsonSchemas), nil
+}
+
+func tfJSONSchema(s *tfprotov6.Schema) (*tfjson.Schema, error) {
+	js := &tfjson.Schema{Version: uint64(s.Version)}
+	if s.Block == nil {
+		return js, nil
+	}
+	b, err := tfJSONBlock(s.Block)
+	if err != nil {
+		return nil, err
+	}
+	js.Block = b
+	return js, nil
+}
+
+func tfJSONBlock(b *tfprotov6.SchemaBlock) (*tfjson.SchemaBlock, error) {
+	jb := &tfjson.SchemaBlock{
+		Attributes:   make(map[string]*tfjson.SchemaAttribute, len(b.Attributes)),
+		NestedBlocks: make(map[string]*tfjson.SchemaBlockType, len(b.BlockTypes)),
+		Description:  b.Description,
+		Deprecated:   b.Deprecated,
+	}
+	for _, a := range b.Attributes {
+		ja, err := tfJSONAttribute(a)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot convert attribute %s", a.Name)
+		}
+		jb.Attributes[a.Name] = ja
+	}
+	for _, nb := range b.BlockTypes {
+		jnb := &tfjson.SchemaBlockType{
+			MinItems: uint64(nb.MinItems),
+			MaxItems: uint64(nb.MaxItems),
+		}
+		switch nb.Nesting {
+		case tfprotov6.SchemaNestedBlockNestingModeSingle:
+			jnb.NestingMode = tfjson.SchemaNestingModeSingle
+		case tfprotov6.SchemaNestedBlockNestingModeGroup:
+			jnb.NestingMode = tfjson.SchemaNestingModeGroup
+		case tfprotov6.SchemaNestedBlockNestingModeList:
+			jnb.NestingMode = tfjson.SchemaNestingModeList
+		case tfprotov6.SchemaNestedBlockNestingModeSet:
+			jnb.NestingMode = tfjson.SchemaNestingModeSet
+		case tfprotov6.SchemaNestedBlockNestingModeMap:
+			jnb.NestingMode = tfjson.SchemaNestingModeMap
+		case tfprotov6.SchemaNestedBlockNestingModeInvalid:
+			return nil, errors.Errorf("invalid nesting mode of block %s", nb.TypeName)
+		}
+		if nb.Block != nil {
+			b, err := tfJSONBlock(nb.Block)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot convert block %s", nb.TypeName)
+			}
+			jnb.Block = b
+		}
+		jb.NestedBlocks[nb.TypeName] = jnb
+	}
+	return jb, nil
+}
+
+func tfJSONAttribute(a *tfprotov6.SchemaAttribute) (*tfjson.SchemaAttribute, error) {
+	ja := &tfjson.SchemaAttribute{
+		Description: a.Description,
+		Deprecated:  a.Deprecated,
+		Required:    a.Required,
+		Optional:    a.Optional,
+		Computed:    a.Computed,
+		Sensitive:   a.Sensitive,
+	}
+	if a.NestedType == nil {
+		t, err := ctyType(a.Type)
+		if err != nil {
+			return nil, err
+		}
+		ja.AttributeType = t
+		return ja, nil
+	}
+	nt := &tfjson.SchemaNestedAttributeType{
+		Attributes: make(map[string]*tfjson.SchemaAttribute, len(a.NestedType.Attributes)),
+	}
+	switch a.NestedType.Nesting {
+	case tfprotov6.SchemaObjectNestingModeSingle:
+		nt.NestingMode = tfjson.SchemaNestingModeSingle
+	case tfprotov6.SchemaObjectNestingModeList:
+		nt.NestingMode = tfjson.SchemaNestingModeList
+	case tfprotov6.SchemaObjectNestingModeSet:
+		nt.NestingMode = tfjson.SchemaNestingModeSet
+	case tfprotov6.SchemaObjectNestingModeMap:
+		nt.NestingMode = tfjson.SchemaNestingModeMap
+	case tfprotov6.SchemaObjectNestingModeInvalid:
+		return nil, errors.New("invalid nesting mode")
+	}
+	for _, na := range a.NestedType.Attributes {
+		jna, err := tfJSONAttribute(na)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot convert attribute %s", na.Name)
+		}
+		nt.Attributes[na.Name] = jna
+	}
+	ja.AttributeNestedType = nt
+	return ja, nil
+}
+
+// ctyType returns the cty.Type of the given tftypes.Type, both of which
+// represent the same Terraform type system.
+func ctyType(t tftypes.Type) (cty.Type, error) { //nolint:gocyclo
+	switch tt := t.(type) {
+	case tftypes.List:
+		et, err := ctyType(tt.ElementType)
+		return cty.List(et), err
+	case tftypes.Set:
+		et, err := ctyType(tt.ElementType)
+		return cty.Set(et), err
+	case tftypes.Map:
+		et, err := ctyType(tt.ElementType)
+		return cty.Map(et), err
+	case tftypes.Object:
+		attrs := make(map[string]cty.Type, len(tt.AttributeTypes))
+		for k, at := range tt.AttributeTypes {
+			ct, err := ctyType(at)
+			if err != nil {
+				return cty.NilType, err
+			}
+			attrs[k] = ct
+		}
+		optional := make([]string, 0, len(tt.OptionalAttributes))
+		for k := range tt.OptionalAttributes {
+			optional = append(optional, k)
+		}
+		return cty.ObjectWithOptionalAttrs(attrs, optional), nil
+	case tftypes.Tuple:
+		elems := make([]cty.Type, len(tt.ElementTypes))
+		for i, et := range tt.ElementTypes {
+			ct, err := ctyType(et)
+			if err != nil {
+				return cty.NilType, err
+			}
+			elems[i] = ct
+		}
+		return cty.Tuple(elems), nil
+	}
+	switch {
+	case t == nil:
+		return cty.NilType, errors.New("type is not set")
+	case t.Is(tftypes.String):
+		return cty.String, nil
+	case t.Is(tftypes.Number):
+		return cty.Number, nil
+	case t.Is(tftypes.Bool):
+		return cty.Bool, nil
+	case t.Is(tftypes.DynamicPseudoType):
+		return cty.DynamicPseudoType, nil
+	}
+	return cty.NilType, errors.Errorf("unexpected type %s", t)
+}
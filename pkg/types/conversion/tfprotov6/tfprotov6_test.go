@@ -0,0 +1,111 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package tfprotov6
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	schemav2 "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestGetV2ResourceMap(t *testing.T) {
+	type want struct {
+		schema map[string]*schemav2.Schema
+		err    bool
+	}
+	cases := map[string]struct {
+		reason string
+		schema *tfprotov6.Schema
+		want   want
+	}{
+		"Attributes": {
+			reason: "The primitive and collection attributes should be converted.",
+			schema: &tfprotov6.Schema{Block: &tfprotov6.SchemaBlock{
+				Attributes: []*tfprotov6.SchemaAttribute{
+					{Name: "name", Type: tftypes.String, Required: true},
+					{Name: "tags", Type: tftypes.Map{ElementType: tftypes.String}, Optional: true},
+					{Name: "id", Type: tftypes.String, Computed: true},
+				},
+			}},
+			want: want{
+				schema: map[string]*schemav2.Schema{
+					"name": {Type: schemav2.TypeString, Required: true},
+					"tags": {Type: schemav2.TypeMap, Optional: true, Elem: &schemav2.Schema{Type: schemav2.TypeString, Optional: true}},
+					"id":   {Type: schemav2.TypeString, Computed: true},
+				},
+			},
+		},
+		"NestedAttributes": {
+			reason: "The single nested attributes should be converted to singleton lists and the others to collections of objects.",
+			schema: &tfprotov6.Schema{Block: &tfprotov6.SchemaBlock{
+				Attributes: []*tfprotov6.SchemaAttribute{
+					{Name: "config", Optional: true, NestedType: &tfprotov6.SchemaObject{
+						Nesting:    tfprotov6.SchemaObjectNestingModeSingle,
+						Attributes: []*tfprotov6.SchemaAttribute{{Name: "size", Type: tftypes.Number, Optional: true}},
+					}},
+					{Name: "rules", Optional: true, NestedType: &tfprotov6.SchemaObject{
+						Nesting:    tfprotov6.SchemaObjectNestingModeSet,
+						Attributes: []*tfprotov6.SchemaAttribute{{Name: "port", Type: tftypes.Number, Required: true}},
+					}},
+				},
+			}},
+			want: want{
+				schema: map[string]*schemav2.Schema{
+					"config": {Type: schemav2.TypeList, Optional: true, MaxItems: 1, ConfigMode: schemav2.SchemaConfigModeAttr, Elem: &schemav2.Resource{
+						Schema: map[string]*schemav2.Schema{"size": {Type: schemav2.TypeFloat, Optional: true}},
+					}},
+					"rules": {Type: schemav2.TypeSet, Optional: true, ConfigMode: schemav2.SchemaConfigModeAttr, Elem: &schemav2.Resource{
+						Schema: map[string]*schemav2.Schema{"port": {Type: schemav2.TypeFloat, Required: true}},
+					}},
+				},
+			},
+		},
+		"Blocks": {
+			reason: "The single nested blocks should be converted to singleton lists except the timeouts block.",
+			schema: &tfprotov6.Schema{Block: &tfprotov6.SchemaBlock{
+				BlockTypes: []*tfprotov6.SchemaNestedBlock{
+					{TypeName: "logging", Nesting: tfprotov6.SchemaNestedBlockNestingModeSingle, Block: &tfprotov6.SchemaBlock{
+						Attributes: []*tfprotov6.SchemaAttribute{{Name: "enabled", Type: tftypes.Bool, Optional: true}},
+					}},
+					{TypeName: "timeouts", Nesting: tfprotov6.SchemaNestedBlockNestingModeSingle, Block: &tfprotov6.SchemaBlock{}},
+				},
+			}},
+			want: want{
+				schema: map[string]*schemav2.Schema{
+					"logging": {Type: schemav2.TypeList, Optional: true, MaxItems: 1, Elem: &schemav2.Resource{
+						Schema: map[string]*schemav2.Schema{"enabled": {Type: schemav2.TypeBool, Optional: true}},
+					}},
+				},
+			},
+		},
+		"InvalidType": {
+			reason: "An error should be returned if the type of an attribute is not set.",
+			schema: &tfprotov6.Schema{Block: &tfprotov6.SchemaBlock{
+				Attributes: []*tfprotov6.SchemaAttribute{{Name: "name", Optional: true}},
+			}},
+			want: want{
+				err: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m, err := GetV2ResourceMap(map[string]*tfprotov6.Schema{"example_resource": tc.schema})
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Fatalf("\n%s\nGetV2ResourceMap(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+			if tc.want.err {
+				return
+			}
+			if diff := cmp.Diff(tc.want.schema, m["example_resource"].Schema, cmpopts.IgnoreUnexported(schemav2.Resource{})); diff != "" {
+				t.Errorf("\n%s\nGetV2ResourceMap(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
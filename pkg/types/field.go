@@ -6,6 +6,7 @@ import (
 	"go/types"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -13,17 +14,24 @@ import (
 
 	"github.com/upbound/upjet/pkg"
 	"github.com/upbound/upjet/pkg/config"
+	"github.com/upbound/upjet/pkg/resource/json"
 	"github.com/upbound/upjet/pkg/types/comments"
 	"github.com/upbound/upjet/pkg/types/name"
 )
 
 var parentheses = regexp.MustCompile(`\(([^)]+)\)`)
 
+// reIndex matches the list indices in the field paths, e.g. "[*]" or "[0]".
+var reIndex = regexp.MustCompile(`\[(\*|\d+)\]`)
+
 // Field represents a field that is built from the Terraform schema.
 // It contains the go field related information such as tags, field type, comment.
 type Field struct {
-	Schema                                   *schema.Schema
-	Name                                     name.Name
+	Schema *schema.Schema
+	Name   name.Name
+	// TFName is the Terraform name of the field in snake case, which is kept
+	// when the field is renamed with the FieldRenames of its resource.
+	TFName                                   string
 	Comment                                  *comments.Comment
 	TFTag, JSONTag, FieldNameCamel           string
 	TerraformPaths, CRDPaths, CanonicalPaths []string
@@ -33,17 +41,24 @@ type Field struct {
 	TransformedName                          string
 	SelectorName                             string
 	Identifier                               bool
+	Hidden                                   bool
+	// InitType is the type of the block field in the init parameters, which
+	// is nil if none of the nested fields of the block is an init parameter.
+	InitType types.Type
 }
 
 // getDocString tries to extract the documentation string for the specified
 // field by:
 // - first, looking up the field's hierarchical name in
 // the dictionary of extracted doc strings
-// - second, looking up the terminal name in the same dictionary
+// - second, looking up the terminal name in the same dictionary, preferring
+// the hierarchical names sharing the most trailing segments with the field's,
+// so that the nested fields with common names, e.g. "rule.filter.prefix" and
+// "rule.destination.prefix", get their own doc strings
 // - and third, tries to match hierarchical name with
 // the longest suffix matching
 func getDocString(cfg *config.Resource, f *Field, tfPath []string) string { //nolint:gocyclo
-	hName := f.Name.Snake
+	hName := f.TFName
 	if len(tfPath) > 0 {
 		hName = fieldPath(append(tfPath, hName))
 	}
@@ -61,10 +76,17 @@ func getDocString(cfg *config.Resource, f *Field, tfPath []string) string { //no
 		}
 		sort.Strings(sortedKeys)
 		// look up the terminal name
+		hParts := strings.Split(hName, ".")
+		common, extra := 0, 0
 		for _, k := range sortedKeys {
 			parts := strings.Split(k, ".")
-			if parts[len(parts)-1] == f.Name.Snake {
-				lm = len(f.Name.Snake)
+			if parts[len(parts)-1] != f.TFName {
+				continue
+			}
+			c := commonSuffixLen(parts, hParts)
+			if c > common || (c == common && len(parts)-c < extra) {
+				common, extra = c, len(parts)-c
+				lm = len(f.TFName)
 				match = k
 			}
 		}
@@ -86,11 +108,107 @@ func getDocString(cfg *config.Resource, f *Field, tfPath []string) string { //no
 	return docString
 }
 
+// conflictingFields returns the CRD paths, e.g. "rule.allow", of the
+// arguments with the given Terraform schema keys, e.g. "rule.0.allow".
+func conflictingFields(keys []string) []string {
+	paths := make([]string, len(keys))
+	for i, k := range keys {
+		var segments []string
+		for _, s := range strings.Split(k, ".") {
+			if _, err := strconv.Atoi(s); err == nil {
+				continue
+			}
+			segments = append(segments, name.NewFromSnake(s).LowerCamelComputed)
+		}
+		paths[i] = strings.Join(segments, ".")
+	}
+	return paths
+}
+
+// maxExampleValueLength is the maximum length of the example values embedded
+// into the field descriptions, above which they're omitted.
+const maxExampleValueLength = 80
+
+// exampleValue returns the value of the argument at the given Terraform path
+// in the first scraped example of the resource, or false if the example does
+// not set the argument to a literal. The blocks are not embedded, and the
+// first elements of the lists of blocks are traversed for the nested ones.
+// The values of the sensitive arguments are redacted.
+func exampleValue(cfg *config.Resource, tfPath []string, sch *schema.Schema) (string, bool) { //nolint:gocyclo
+	if cfg.MetaResource == nil || len(cfg.MetaResource.Examples) == 0 || IsObservation(sch) {
+		return "", false
+	}
+	if _, ok := sch.Elem.(*schema.Resource); ok {
+		return "", false
+	}
+	var v any = cfg.MetaResource.Examples[0].Paved.UnstructuredContent()
+	for _, p := range tfPath {
+		if p == wildcard {
+			continue
+		}
+		if l, ok := v.([]any); ok {
+			if len(l) == 0 {
+				return "", false
+			}
+			v = l[0]
+		}
+		m, ok := v.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if v, ok = m[p]; !ok || v == nil {
+			return "", false
+		}
+	}
+	if sch.Sensitive {
+		return "<redacted>", true
+	}
+	b, err := json.TFParser.Marshal(v)
+	if err != nil || len(b) > maxExampleValueLength || strings.Contains(string(b), "${") || !isPrimitiveCollection(v) {
+		return "", false
+	}
+	return string(b), true
+}
+
+// isPrimitiveCollection returns true if the given list or map contains only
+// the primitive values.
+func isPrimitiveCollection(v any) bool {
+	var elems []any
+	switch c := v.(type) {
+	case []any:
+		elems = c
+	case map[string]any:
+		for _, e := range c {
+			elems = append(elems, e)
+		}
+	default:
+		return true
+	}
+	for _, e := range elems {
+		switch e.(type) {
+		case []any, map[string]any:
+			return false
+		}
+	}
+	return true
+}
+
+// commonSuffixLen returns the number of the trailing segments the given
+// hierarchical names have in common.
+func commonSuffixLen(a, b []string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return n
+}
+
 // NewField returns a constructed Field object.
 func NewField(g *Builder, cfg *config.Resource, r *resource, sch *schema.Schema, snakeFieldName string, tfPath, xpPath, names []string, asBlocksMode bool) (*Field, error) {
 	f := &Field{
 		Schema:         sch,
 		Name:           name.NewFromSnake(snakeFieldName),
+		TFName:         snakeFieldName,
 		FieldNameCamel: name.NewFromSnake(snakeFieldName).Camel,
 		AsBlocksMode:   asBlocksMode,
 	}
@@ -104,6 +222,11 @@ func NewField(g *Builder, cfg *config.Resource, r *resource, sch *schema.Schema,
 			break
 		}
 	}
+	// The hidden parameters are injected into the Terraform configuration by
+	// the controller, so they're only exposed in the status.
+	if _, ok := cfg.HiddenParameters[snakeFieldName]; ok && len(tfPath) == 0 {
+		f.Hidden = true
+	}
 
 	var commentText string
 	docString := getDocString(cfg, f, tfPath)
@@ -111,43 +234,294 @@ func NewField(g *Builder, cfg *config.Resource, r *resource, sch *schema.Schema,
 		commentText = docString + "\n"
 	}
 	commentText += f.Schema.Description
+	if d, ok := cfg.DeprecatedFields[fieldPath(append(tfPath, snakeFieldName))]; ok {
+		commentText += "\nDeprecated: " + d.Notice()
+	}
+	if len(sch.ConflictsWith) > 0 && !IsObservation(sch) {
+		commentText += "\nConflicts with " + strings.Join(conflictingFields(sch.ConflictsWith), ", ") + "."
+	}
+	if v, ok := exampleValue(cfg, append(tfPath, snakeFieldName), sch); ok {
+		commentText += "\nExample: " + v
+	}
+	if m, ok := cfg.FieldMaturities[fieldPath(append(tfPath, snakeFieldName))]; ok {
+		commentText = fmt.Sprintf("(%s) %s", m, commentText)
+	}
 	commentText = pkg.FilterDescription(commentText, pkg.TerraformKeyword)
 	comment, err := comments.New(commentText)
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot build comment for description: %s", commentText)
 	}
 	f.Comment = comment
-	f.TFTag = fmt.Sprintf("%s,omitempty", f.Name.Snake)
+	// Changing a ForceNew field replaces the external resource, so we make
+	// the top-level ones immutable unless the resource opts out or guards
+	// the replacements with a webhook. The nested ones cannot be validated
+	// with transition rules as they are in lists, and neither can the ones
+	// that turn out to be schemaless, see AddToResource.
+	if sch.ForceNew && len(tfPath) == 0 && !cfg.DisableForceNewImmutability && !cfg.AllowReplacementWithAnnotation {
+		f.Comment.Immutable = true
+	}
+	f.TFTag = fmt.Sprintf("%s,omitempty", f.TFName)
+	// Terraform paths, e.g. { "lifecycle_rule", "*", "transition", "*", "days" } for https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/s3_bucket#lifecycle_rule
+	f.TerraformPaths = append(tfPath, f.TFName) // nolint:gocritic
+	// The Terraform tag and paths above keep the Terraform name so that the
+	// conversion between the CRD and the Terraform representations continues
+	// to work for a renamed field.
+	if n, ok := cfg.FieldRenames[fieldPath(f.TerraformPaths)]; ok {
+		f.Name = name.NewFromCamel(n)
+		f.FieldNameCamel = f.Name.Camel
+	}
+	// The item limits are not applicable to the embedded singleton lists,
+	// which are generated as objects.
+	if o, ok := cfg.SchemaElementOptions[fieldPath(f.TerraformPaths)]; ok && !g.isEmbeddedList(f.TerraformPaths) {
+		if o.MinItems > 0 {
+			f.Comment.MinItems = &o.MinItems
+		}
+		if o.MaxItems > 0 {
+			f.Comment.MaxItems = &o.MaxItems
+		}
+	}
+	if v, ok := cfg.FieldValidators[fieldPath(f.TerraformPaths)]; ok {
+		if err := f.applyValidator(cfg, v); err != nil {
+			return nil, errors.Wrapf(err, "cannot apply the validator of field %s", fieldPath(f.TerraformPaths))
+		}
+		if g.matchedValidators == nil {
+			g.matchedValidators = map[string]struct{}{}
+		}
+		g.matchedValidators[fieldPath(f.TerraformPaths)] = struct{}{}
+	} else if _, ok := cfg.TypeOverrides[fieldPath(f.TerraformPaths)]; !ok && f.validatable(cfg) && !g.isEmbeddedList(f.TerraformPaths) {
+		inferValidation(f.Schema, commentText, &f.Comment.KubebuilderOptions)
+	}
+	if err := g.applyListType(cfg, f); err != nil {
+		return nil, errors.Wrapf(err, "cannot set the list type of field %s", fieldPath(f.TerraformPaths))
+	}
+	// The defaults of the deprecated fields are not generated as they would
+	// trigger the deprecation warnings for all the managed resources.
+	if _, deprecated := cfg.DeprecatedFields[fieldPath(f.TerraformPaths)]; cfg.SchemaDefaults && !deprecated && f.validatable(cfg) {
+		if _, ok := cfg.TypeOverrides[fieldPath(f.TerraformPaths)]; !ok {
+			f.Comment.Default = schemaDefault(f.Schema)
+		}
+	}
 	f.JSONTag = fmt.Sprintf("%s,omitempty", f.Name.LowerCamelComputed)
 	f.TransformedName = f.Name.LowerCamelComputed
 
-	// Terraform paths, e.g. { "lifecycle_rule", "*", "transition", "*", "days" } for https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/s3_bucket#lifecycle_rule
-	f.TerraformPaths = append(tfPath, f.Name.Snake) // nolint:gocritic
 	// Crossplane paths, e.g. {"lifecycleRule", "*", "transition", "*", "days"}
 	f.CRDPaths = append(xpPath, f.Name.LowerCamelComputed) // nolint:gocritic
 	// Canonical paths, e.g. {"LifecycleRule", "Transition", "Days"}
 	f.CanonicalPaths = append(names[1:], f.Name.Camel) // nolint:gocritic
+	g.addCompositionFieldPath(cfg, f, !IsObservation(f.Schema) && !f.Hidden)
 
-	for _, ignoreField := range cfg.LateInitializer.IgnoredFields {
+	if matched := cfg.LateInitializer.MatchIgnoredFields(fieldPath(f.TerraformPaths)); len(matched) > 0 {
 		// Convert configuration input from Terraform path to canonical path
-		// Todo(turkenh/muvaf): Replace with a simple string conversion
-		//  like GetIgnoredCanonicalFields where we just make each word
-		//  between points camel case using names.go utilities. If the path
-		//  doesn't match anything, it's no-op in late-init logic anyway.
-		if ignoreField == fieldPath(f.TerraformPaths) {
-			cfg.LateInitializer.AddIgnoredCanonicalFields(fieldPath(f.CanonicalPaths))
+		cfg.LateInitializer.AddIgnoredCanonicalFields(fieldPath(f.CanonicalPaths))
+		g.addMatchedIgnoredFields(matched...)
+	}
+
+	if o, ok := cfg.TypeOverrides[fieldPath(f.TerraformPaths)]; ok {
+		fieldType, err := overrideType(f.Schema, o)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot override type of field %s", f.TFName)
+		}
+		f.FieldType = fieldType
+		switch o { //nolint:exhaustive
+		case config.TypeOverrideJSON:
+			// the JSON value may be of any kind, e.g. an object or a list,
+			// so we cannot use the object schema generated for
+			// runtime.RawExtension.
+			f.Comment.Schemaless = true
+			f.Comment.PreserveUnknownFields = true
+			f.TFTag = fmt.Sprintf("%s,%s", f.TFTag, json.TagOptionEmbedJSON)
+		case config.TypeOverrideIntOrString:
+			// intstr.IntOrString is encoded into and decoded from both the
+			// Terraform numbers and strings by itself.
+		default:
+			f.TFTag = fmt.Sprintf("%s,%s", f.TFTag, json.TagOptionStringify)
 		}
+		return f, nil
 	}
 
 	fieldType, err := g.buildSchema(f, cfg, names, r)
 	if err != nil {
-		return nil, errors.Wrapf(err, "cannot infer type from schema of field %s", f.Name.Snake)
+		return nil, errors.Wrapf(err, "cannot infer type from schema of field %s", f.TFName)
 	}
 	f.FieldType = fieldType
 
 	return f, nil
 }
 
+// schemaDefault returns the literal of the static default value of the given
+// primitive schema, or an empty string if it has none.
+func schemaDefault(sch *schema.Schema) string {
+	switch v := sch.Default.(type) {
+	case string:
+		if sch.Type == schema.TypeString {
+			return strconv.Quote(v)
+		}
+	case bool:
+		if sch.Type == schema.TypeBool {
+			return strconv.FormatBool(v)
+		}
+	case int:
+		if sch.Type == schema.TypeInt || sch.Type == schema.TypeFloat {
+			return strconv.Itoa(v)
+		}
+	case float64:
+		if sch.Type == schema.TypeFloat {
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		}
+	}
+	return ""
+}
+
+// validatable returns whether the field is a non-sensitive spec field, whose
+// values can be validated.
+func (f *Field) validatable(cfg *config.Resource) bool {
+	return !IsObservation(f.Schema) && !f.Hidden && !f.sensitive(cfg)
+}
+
+func (f *Field) sensitive(cfg *config.Resource) bool {
+	return f.Schema.Sensitive || len(f.TerraformPaths) == 1 && cfg.Sensitive.IsWriteOnly(f.TerraformPaths[0])
+}
+
+// applyValidator sets the validation markers of the field from the given
+// validator after checking it's applicable to the field.
+func (f *Field) applyValidator(cfg *config.Resource, v config.FieldValidator) error {
+	switch {
+	case IsObservation(f.Schema), f.Hidden:
+		return errors.New("field is not in the spec")
+	case f.sensitive(cfg):
+		return errors.New("sensitive fields cannot be validated")
+	case v.Pattern != "" && f.Schema.Type != schema.TypeString:
+		return errors.Errorf("pattern is not applicable to a field of type %s", f.Schema.Type)
+	case (v.Minimum != nil || v.Maximum != nil) && f.Schema.Type != schema.TypeInt && f.Schema.Type != schema.TypeFloat:
+		return errors.Errorf("range is not applicable to a field of type %s", f.Schema.Type)
+	case len(v.Enum) > 0 && f.Schema.Type != schema.TypeString && f.Schema.Type != schema.TypeInt:
+		return errors.Errorf("enum is not applicable to a field of type %s", f.Schema.Type)
+	}
+	enum := make([]string, 0, len(v.Enum))
+	for _, e := range v.Enum {
+		if f.Schema.Type == schema.TypeString {
+			enum = append(enum, strconv.Quote(e))
+			continue
+		}
+		if _, err := strconv.Atoi(e); err != nil {
+			return errors.Errorf("enum value %q is not an integer", e)
+		}
+		enum = append(enum, e)
+	}
+	f.Comment.Pattern = v.Pattern
+	f.Comment.Minimum = v.Minimum
+	f.Comment.Maximum = v.Maximum
+	if len(enum) > 0 {
+		f.Comment.Enum = enum
+	}
+	return nil
+}
+
+// identifyingFields are the names of the required fields identifying the
+// items of the set blocks whose list map keys are not configured.
+var identifyingFields = []string{"name", "key"}
+
+// applyListType generates the given list or set block of the spec as a map
+// list, i.e. a list merged by the server-side apply with the keys of its
+// items, if its items are identified by the configured or the well-known
+// required fields. The sets of primitive values are generated as set lists,
+// whose items are unique and unordered.
+func (g *Builder) applyListType(cfg *config.Resource, f *Field) error {
+	p := fieldPath(f.TerraformPaths)
+	keys, configured := cfg.ListMapKeys[p]
+	if (f.Schema.Type != schema.TypeList && f.Schema.Type != schema.TypeSet) || !f.validatable(cfg) || g.isEmbeddedList(f.TerraformPaths) {
+		if configured {
+			return errors.New("list map keys are only applicable to the list and set blocks of the spec")
+		}
+		return nil
+	}
+	if _, ok := cfg.TypeOverrides[p]; ok {
+		return nil
+	}
+	res, ok := f.Schema.Elem.(*schema.Resource)
+	switch {
+	case !ok && configured:
+		return errors.New("list map keys are only applicable to the list and set blocks of the spec")
+	case !ok:
+		if f.Schema.Type == schema.TypeSet {
+			f.Comment.ListType = "set"
+		}
+		return nil
+	case !configured && f.Schema.Type == schema.TypeSet:
+		for _, k := range identifyingFields {
+			if listMapKey(cfg, res, p, k) == "" {
+				continue
+			}
+			keys = []string{k}
+			break
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	jsonKeys := make([]string, len(keys))
+	for i, k := range keys {
+		if jsonKeys[i] = listMapKey(cfg, res, p, k); jsonKeys[i] == "" {
+			return errors.Errorf("list map key %q is not a required primitive field of the block", k)
+		}
+	}
+	f.Comment.ListType = "map"
+	f.Comment.ListMapKeys = jsonKeys
+	return nil
+}
+
+// listMapKey returns the JSON name of the field with the given Terraform name
+// of the given block at the given Terraform path if it can be a list map
+// key, i.e. a required primitive field which is not a reference.
+func listMapKey(cfg *config.Resource, block *schema.Resource, blockPath, k string) string {
+	sch, ok := block.Schema[k]
+	if !ok || !sch.Required || sch.Sensitive {
+		return ""
+	}
+	switch sch.Type { //nolint:exhaustive
+	case schema.TypeString, schema.TypeInt, schema.TypeBool:
+	default:
+		return ""
+	}
+	p := blockPath + "." + k
+	if _, ok := cfg.References[p]; ok {
+		return ""
+	}
+	if n, ok := cfg.FieldRenames[p]; ok {
+		return name.NewFromCamel(n).LowerCamelComputed
+	}
+	return name.NewFromSnake(k).LowerCamelComputed
+}
+
+// overrideType returns the Go type of a field with the given schema whose
+// type is overridden.
+func overrideType(sch *schema.Schema, o config.TypeOverride) (types.Type, error) {
+	str := types.NewPointer(types.Universe.Lookup("string").Type())
+	switch o {
+	case config.TypeOverrideString:
+		switch sch.Type {
+		case schema.TypeBool, schema.TypeFloat, schema.TypeInt, schema.TypeString:
+			return str, nil
+		case schema.TypeList, schema.TypeMap, schema.TypeSet, schema.TypeInvalid:
+		}
+	case config.TypeOverrideStringMap:
+		if sch.Type == schema.TypeMap {
+			return types.NewMap(types.Universe.Lookup("string").Type(), str), nil
+		}
+	case config.TypeOverrideJSON:
+		if sch.Type == schema.TypeString {
+			return types.NewPointer(typeRawExtension), nil
+		}
+	case config.TypeOverrideIntOrString:
+		if sch.Type == schema.TypeInt || sch.Type == schema.TypeString {
+			return types.NewPointer(typeIntOrString), nil
+		}
+	default:
+		return nil, errors.Errorf("unknown type override %q", o)
+	}
+	return nil, errors.Errorf("type override %q is not applicable to a field of type %s", o, sch.Type)
+}
+
 // NewSensitiveField returns a constructed sensitive Field object.
 func NewSensitiveField(g *Builder, cfg *config.Resource, r *resource, sch *schema.Schema, snakeFieldName string, tfPath, xpPath, names []string, asBlocksMode bool) (*Field, bool, error) { //nolint:gocyclo
 	f, err := NewField(g, cfg, r, sch, snakeFieldName, tfPath, xpPath, names, asBlocksMode)
@@ -207,6 +581,12 @@ func NewReferenceField(g *Builder, cfg *config.Resource, r *resource, sch *schem
 
 // AddToResource adds built field to the resource.
 func (f *Field) AddToResource(g *Builder, r *resource, typeNames *TypeNames) {
+	// The schemaless fields, e.g. the JSON fields, have no types for the
+	// transition rules, which the API server rejects, so they cannot be
+	// immutable. Their types are only resolved once they're built.
+	if f.Comment.Schemaless {
+		f.Comment.Immutable = false
+	}
 	if f.Comment.UpjetOptions.FieldTFTag != nil {
 		f.TFTag = *f.Comment.UpjetOptions.FieldTFTag
 	}
@@ -223,21 +603,36 @@ func (f *Field) AddToResource(g *Builder, r *resource, typeNames *TypeNames) {
 	// We do this only if tf tag is not set to "-" because otherwise it won't
 	// be populated from the tfstate. We typically set tf tag to "-" for
 	// sensitive fields which were replaced with secretKeyRefs.
+	pruned := false
+	var initField *types.Var
 	if f.TFTag != "-" {
-		r.addObservationField(f, field)
+		if pruned = g.observationPruned(f); !pruned {
+			r.addObservationField(f, field)
+		}
 	}
-	if !IsObservation(f.Schema) {
+	if !IsObservation(f.Schema) && !f.Hidden {
 		if f.AsBlocksMode {
 			f.TFTag = strings.TrimSuffix(f.TFTag, ",omitempty")
 		}
 		r.addParameterField(f, field)
+		if initField = f.initField(g); initField != nil {
+			r.addInitField(f, initField)
+		}
+		if len(f.TerraformPaths) == 1 {
+			if g.topLevelParams == nil {
+				g.topLevelParams = map[string]string{}
+			}
+			g.topLevelParams[f.TerraformPaths[0]] = f.TransformedName
+		}
 	}
 
 	if f.Reference != nil {
 		r.addReferenceFields(g, typeNames.ParameterTypeName, f)
 	}
 
-	g.comments.AddFieldComment(typeNames.ParameterTypeName, f.FieldNameCamel, f.Comment.Build())
+	if !f.Hidden {
+		g.comments.AddFieldComment(typeNames.ParameterTypeName, f.FieldNameCamel, f.Comment.Build())
+	}
 	// Note(turkenh): We don't want reference resolver to be generated for
 	// fields under status.atProvider. So, we don't want reference comments to
 	// be added, hence we are unsetting reference on the field comment just
@@ -246,7 +641,52 @@ func (f *Field) AddToResource(g *Builder, r *resource, typeNames *TypeNames) {
 	// Note(turkenh): We don't need required/optional markers for observation
 	// fields.
 	f.Comment.Required = nil
-	g.comments.AddFieldComment(typeNames.ObservationTypeName, f.FieldNameCamel, f.Comment.Build())
+	f.Comment.Immutable = false
+	if initField != nil {
+		// The init parameters are optional and they're not defaulted. The list
+		// map keys are not generated as the keys of the blocks are not init
+		// parameters.
+		c := *f.Comment
+		c.Default = ""
+		c.ListType = ""
+		c.ListMapKeys = nil
+		g.comments.AddFieldComment(typeNames.InitTypeName, f.FieldNameCamel, c.Build())
+	}
+	f.Comment.MinItems = nil
+	f.Comment.MaxItems = nil
+	f.Comment.Pattern = ""
+	f.Comment.Minimum = nil
+	f.Comment.Maximum = nil
+	f.Comment.MinLength = nil
+	f.Comment.MaxLength = nil
+	f.Comment.Enum = nil
+	f.Comment.Default = ""
+	f.Comment.XValidations = nil
+	f.Comment.ListType = ""
+	f.Comment.ListMapKeys = nil
+	if !pruned {
+		g.comments.AddFieldComment(typeNames.ObservationTypeName, f.FieldNameCamel, f.Comment.Build())
+	}
+}
+
+// initField returns the field of the init parameters for the given parameter
+// field, or nil if it's not an init parameter. The optional fields with
+// server-side defaults, which are late-initialized, and the blocks with such
+// nested fields are init parameters, so that their initial values can be
+// set without being enforced afterwards. The identifiers, the sensitive
+// fields and the references cannot be set with the init parameters.
+func (f *Field) initField(g *Builder) *types.Var {
+	if f.Identifier || f.TFTag == "-" {
+		return nil
+	}
+	t := f.InitType
+	if t == nil {
+		if _, ok := f.Schema.Elem.(*schema.Resource); ok || !f.Schema.Optional || !f.Schema.Computed {
+			return nil
+		}
+		t = f.FieldType
+	}
+	return types.NewField(token.NoPos, g.Package, f.FieldNameCamel, t, false)
 }
 
 func getDescription(s string) string {
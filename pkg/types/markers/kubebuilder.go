@@ -1,13 +1,42 @@
 package markers
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // KubebuilderOptions represents the kubebuilder options that upjet would
 // need to control
 type KubebuilderOptions struct {
-	Required *bool
-	Minimum  *int
-	Maximum  *int
+	Required  *bool
+	Minimum   *int
+	Maximum   *int
+	MinItems  *int
+	MaxItems  *int
+	MinLength *int
+	MaxLength *int
+	Pattern   string
+	// Enum is the list of the literals of the allowed values, e.g. quoted
+	// strings or integers.
+	Enum []string
+	// Default is the literal of the default value, e.g. a quoted string.
+	Default string
+	// ListType is the server-side apply topology of a list, e.g. "map",
+	// whose items are keyed by the ListMapKeys.
+	ListType              string
+	ListMapKeys           []string
+	Schemaless            bool
+	PreserveUnknownFields bool
+	Immutable             bool
+	// XValidations are the validation rules of the field in addition to the
+	// immutability rule.
+	XValidations []XValidation
+}
+
+// XValidation is a CEL validation rule of a field.
+type XValidation struct {
+	Rule    string
+	Message string
 }
 
 func (o KubebuilderOptions) String() string {
@@ -26,6 +55,45 @@ func (o KubebuilderOptions) String() string {
 	if o.Maximum != nil {
 		m += fmt.Sprintf("+kubebuilder:validation:Maximum=%d\n", *o.Maximum)
 	}
+	if o.MinItems != nil {
+		m += fmt.Sprintf("+kubebuilder:validation:MinItems=%d\n", *o.MinItems)
+	}
+	if o.MaxItems != nil {
+		m += fmt.Sprintf("+kubebuilder:validation:MaxItems=%d\n", *o.MaxItems)
+	}
+	if o.MinLength != nil {
+		m += fmt.Sprintf("+kubebuilder:validation:MinLength=%d\n", *o.MinLength)
+	}
+	if o.MaxLength != nil {
+		m += fmt.Sprintf("+kubebuilder:validation:MaxLength=%d\n", *o.MaxLength)
+	}
+	if o.Pattern != "" {
+		m += fmt.Sprintf("+kubebuilder:validation:Pattern=`%s`\n", o.Pattern)
+	}
+	if len(o.Enum) > 0 {
+		m += fmt.Sprintf("+kubebuilder:validation:Enum=%s\n", strings.Join(o.Enum, ";"))
+	}
+	if o.Default != "" {
+		m += fmt.Sprintf("+kubebuilder:default=%s\n", o.Default)
+	}
+	if o.ListType != "" {
+		m += fmt.Sprintf("+listType=%s\n", o.ListType)
+	}
+	for _, k := range o.ListMapKeys {
+		m += fmt.Sprintf("+listMapKey=%s\n", k)
+	}
+	if o.Schemaless {
+		m += "+kubebuilder:validation:Schemaless\n"
+	}
+	if o.PreserveUnknownFields {
+		m += "+kubebuilder:pruning:PreserveUnknownFields\n"
+	}
+	if o.Immutable {
+		m += "+kubebuilder:validation:XValidation:rule=\"self == oldSelf\",message=\"Value is immutable\"\n"
+	}
+	for _, v := range o.XValidations {
+		m += fmt.Sprintf("+kubebuilder:validation:XValidation:rule=%q,message=%q\n", v.Rule, v.Message)
+	}
 
 	return m
 }
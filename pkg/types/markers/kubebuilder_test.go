@@ -13,9 +13,22 @@ func TestKubebuilderOptions_String(t *testing.T) {
 	max := 3
 
 	type args struct {
-		required *bool
-		minimum  *int
-		maximum  *int
+		required              *bool
+		minimum               *int
+		maximum               *int
+		minItems              *int
+		maxItems              *int
+		minLength             *int
+		maxLength             *int
+		enum                  []string
+		defaultValue          string
+		pattern               string
+		schemaless            bool
+		preserveUnknownFields bool
+		immutable             bool
+		listType              string
+		listMapKeys           []string
+		xValidations          []XValidation
 	}
 	type want struct {
 		out string
@@ -48,6 +61,95 @@ func TestKubebuilderOptions_String(t *testing.T) {
 				out: `+kubebuilder:validation:Optional
 +kubebuilder:validation:Minimum=1
 +kubebuilder:validation:Maximum=3
+`,
+			},
+		},
+		"MinMaxItems": {
+			args: args{
+				minItems: &min,
+				maxItems: &max,
+			},
+			want: want{
+				out: `+kubebuilder:validation:MinItems=1
++kubebuilder:validation:MaxItems=3
+`,
+			},
+		},
+		"Pattern": {
+			args: args{
+				pattern: "^[a-z0-9-]+$",
+			},
+			want: want{
+				out: "+kubebuilder:validation:Pattern=`^[a-z0-9-]+$`\n",
+			},
+		},
+		"StringValidations": {
+			args: args{
+				minLength: &min,
+				maxLength: &max,
+				enum:      []string{`"a"`, `"b"`},
+			},
+			want: want{
+				out: `+kubebuilder:validation:MinLength=1
++kubebuilder:validation:MaxLength=3
++kubebuilder:validation:Enum="a";"b"
+`,
+			},
+		},
+		"Default": {
+			args: args{
+				defaultValue: `"private"`,
+			},
+			want: want{
+				out: "+kubebuilder:default=\"private\"\n",
+			},
+		},
+		"SchemalessPreserveUnknownFields": {
+			args: args{
+				schemaless:            true,
+				preserveUnknownFields: true,
+			},
+			want: want{
+				out: `+kubebuilder:validation:Schemaless
++kubebuilder:pruning:PreserveUnknownFields
+`,
+			},
+		},
+		"Immutable": {
+			args: args{
+				required:  &optional,
+				immutable: true,
+			},
+			want: want{
+				out: `+kubebuilder:validation:Optional
++kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+`,
+			},
+		},
+		"ListMap": {
+			args: args{
+				listType:    "map",
+				listMapKeys: []string{"name", "port"},
+			},
+			want: want{
+				out: `+listType=map
++listMapKey=name
++listMapKey=port
+`,
+			},
+		},
+		"XValidations": {
+			args: args{
+				schemaless:            true,
+				preserveUnknownFields: true,
+				xValidations: []XValidation{
+					{Rule: "type(self) == list && size(self) <= 1", Message: "Value must be a list of at most 1 items"},
+				},
+			},
+			want: want{
+				out: `+kubebuilder:validation:Schemaless
++kubebuilder:pruning:PreserveUnknownFields
++kubebuilder:validation:XValidation:rule="type(self) == list && size(self) <= 1",message="Value must be a list of at most 1 items"
 `,
 			},
 		},
@@ -55,9 +157,22 @@ func TestKubebuilderOptions_String(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			o := KubebuilderOptions{
-				Required: tc.required,
-				Minimum:  tc.minimum,
-				Maximum:  tc.maximum,
+				Required:              tc.required,
+				Minimum:               tc.minimum,
+				Maximum:               tc.maximum,
+				MinItems:              tc.minItems,
+				MaxItems:              tc.maxItems,
+				MinLength:             tc.minLength,
+				MaxLength:             tc.maxLength,
+				Pattern:               tc.pattern,
+				Enum:                  tc.enum,
+				Default:               tc.defaultValue,
+				Schemaless:            tc.schemaless,
+				PreserveUnknownFields: tc.preserveUnknownFields,
+				Immutable:             tc.immutable,
+				ListType:              tc.listType,
+				ListMapKeys:           tc.listMapKeys,
+				XValidations:          tc.xValidations,
 			}
 			got := o.String()
 			if diff := cmp.Diff(tc.want.out, got); diff != "" {
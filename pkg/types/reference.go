@@ -18,6 +18,14 @@ const (
 	// PackagePathXPCommonAPIs is the go path for the Crossplane Runtime package
 	// with common APIs
 	PackagePathXPCommonAPIs = "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	// PackagePathK8sRuntime is the go path for the Kubernetes apimachinery
+	// runtime package
+	PackagePathK8sRuntime = "k8s.io/apimachinery/pkg/runtime"
+
+	// PackagePathK8sIntStr is the go path for the Kubernetes apimachinery
+	// intstr package
+	PackagePathK8sIntStr = "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // Types to use from by reference generator.
@@ -42,6 +50,16 @@ var (
 		types.NewStruct(nil, nil),
 		nil,
 	)
+	typeRawExtension types.Type = types.NewNamed(
+		types.NewTypeName(token.NoPos, types.NewPackage(PackagePathK8sRuntime, "runtime"), "RawExtension", nil),
+		types.NewStruct(nil, nil),
+		nil,
+	)
+	typeIntOrString types.Type = types.NewNamed(
+		types.NewTypeName(token.NoPos, types.NewPackage(PackagePathK8sIntStr, "intstr"), "IntOrString", nil),
+		types.NewStruct(nil, nil),
+		nil,
+	)
 	commentOptional = &comments.Comment{
 		Options: markers.Options{
 			KubebuilderOptions: markers.KubebuilderOptions{
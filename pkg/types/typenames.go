@@ -0,0 +1,80 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package types
+
+import (
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// TypeNameMapping maps the Terraform names of the resources, e.g.
+// "aws_s3_bucket", to the Terraform paths of their blocks, e.g.
+// "rule.filter", to the names of the Go types generated for the blocks
+// without the "Parameters", "InitParameters" and "Observation" suffixes,
+// e.g. "RuleFilter".
+type TypeNameMapping map[string]map[string]string
+
+// PinnedTypeNames are the type names of a package pinned with a
+// TypeNameMapping. The pinned names are reserved in the package scope so
+// that they're not taken by the types generated for the other blocks, and
+// each of them can be claimed once by the block it's mapped to.
+type PinnedTypeNames struct {
+	mapping  TypeNameMapping
+	reserved map[string]*TypeNames
+}
+
+// NewPinnedTypeNames returns the PinnedTypeNames of the given package with
+// the names in the given mapping reserved in its scope. The names already in
+// use are not reserved.
+func NewPinnedTypeNames(pkg *types.Package, m TypeNameMapping) *PinnedTypeNames {
+	p := &PinnedTypeNames{
+		mapping:  m,
+		reserved: map[string]*TypeNames{},
+	}
+	var names []string
+	for _, paths := range m {
+		for _, n := range paths {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if _, ok := p.reserved[n]; ok || pkg.Scope().Lookup(n+"Parameters") != nil || pkg.Scope().Lookup(n+"InitParameters") != nil || pkg.Scope().Lookup(n+"Observation") != nil {
+			continue
+		}
+		tn := &TypeNames{
+			ParameterTypeName:   types.NewTypeName(token.NoPos, pkg, n+"Parameters", nil),
+			InitTypeName:        types.NewTypeName(token.NoPos, pkg, n+"InitParameters", nil),
+			ObservationTypeName: types.NewTypeName(token.NoPos, pkg, n+"Observation", nil),
+		}
+		pkg.Scope().Insert(tn.ParameterTypeName)
+		pkg.Scope().Insert(tn.InitTypeName)
+		pkg.Scope().Insert(tn.ObservationTypeName)
+		p.reserved[n] = tn
+	}
+	return p
+}
+
+// name returns the type name mapped to the block at the given Terraform
+// path of the given resource.
+func (p *PinnedTypeNames) name(resource, path string) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	n, ok := p.mapping[resource][path]
+	return n, ok
+}
+
+// claim returns the reserved type names with the given name if they've not
+// been claimed yet.
+func (p *PinnedTypeNames) claim(n string) (*TypeNames, bool) {
+	if p == nil {
+		return nil, false
+	}
+	tn, ok := p.reserved[n]
+	delete(p.reserved, n)
+	return tn, ok
+}
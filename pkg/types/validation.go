@@ -0,0 +1,215 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package types
+
+import (
+	"math"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+
+	"github.com/upbound/upjet/pkg/types/markers"
+)
+
+const pkgValidation = "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation."
+
+var (
+	reLength     = regexp.MustCompile(`expected length of .* to be in the range \((-?\d+) - (-?\d+)\)`)
+	reNotEmpty   = regexp.MustCompile(`to not be an empty string, got`)
+	reRange      = regexp.MustCompile(`to be in the range \((-?\d+) - (-?\d+)\)`)
+	reAtLeast    = regexp.MustCompile(`to be at least \((-?\d+)\)`)
+	reAtMost     = regexp.MustCompile(`to be at most \((-?\d+)\)`)
+	reMatch      = regexp.MustCompile(`to match regular expression ("(?:[^"\\]|\\.)*")`)
+	reOneOf      = regexp.MustCompile(`to be one of \[(.*)\], got`)
+	rePatternCRD = regexp.MustCompile("`|\\(\\?")
+	reClosure    = regexp.MustCompile(`\.(\w+)\.func\d+$`)
+
+	// the constraints documented with the commonly used phrases, e.g.
+	// "between 1 and 63 characters" or "Valid values are between 1 and 10".
+	reDocLength    = regexp.MustCompile(`(?i)\bbetween (\d+) and (\d+) characters`)
+	reDocMaxLength = regexp.MustCompile(`(?i)\b(?:up to|at most|maximum(?: length)? of) (\d+) characters`)
+	reDocRange     = regexp.MustCompile(`(?i)\bvalid values are(?: integers)? (?:between|from) (-?\d+) (?:and|to) (-?\d+)\b`)
+)
+
+// inferValidation sets the validation markers equivalent to the
+// constraints of the given schema: the item limits of the lists and sets,
+// the well-known validators of the Terraform plugin SDK, e.g.
+// validation.StringLenBetween, used as the ValidateFunc or wrapped with
+// validation.ToDiagFunc, and the constraints documented with the commonly
+// used phrases in the given description, which are only considered if no
+// validator is recognized. The markers already set are not overridden.
+func inferValidation(sch *schema.Schema, description string, o *markers.KubebuilderOptions) {
+	if sch.Type == schema.TypeList || sch.Type == schema.TypeSet {
+		if sch.MinItems > 0 && o.MinItems == nil {
+			o.MinItems = intPtr(sch.MinItems)
+		}
+		if sch.MaxItems > 0 && o.MaxItems == nil {
+			o.MaxItems = intPtr(sch.MaxItems)
+		}
+		return
+	}
+	inferred := *o
+	if fn := validateFunc(sch); fn != nil {
+		inferValidatorConstraints(sch.Type, fn, &inferred)
+	}
+	if reflect.DeepEqual(inferred, *o) {
+		inferDocumentedConstraints(sch.Type, description, &inferred)
+	}
+	*o = inferred
+}
+
+// validateFunc returns the validator of the given schema if it's from the
+// validation package of the Terraform plugin SDK.
+func validateFunc(sch *schema.Schema) schema.SchemaValidateFunc {
+	if sch.ValidateFunc != nil {
+		if validatorName(sch.ValidateFunc) == "" {
+			return nil
+		}
+		return sch.ValidateFunc
+	}
+	if sch.ValidateDiagFunc == nil || validatorName(sch.ValidateDiagFunc) != "ToDiagFunc" {
+		return nil
+	}
+	return func(i any, k string) ([]string, []error) {
+		var errs []error
+		for _, d := range sch.ValidateDiagFunc(i, nil) {
+			if d.Severity == diag.Error {
+				errs = append(errs, errors.New(d.Summary))
+			}
+		}
+		return nil, errs
+	}
+}
+
+// validatorName returns the name of the function of the validation package
+// the given function is or is returned by, e.g. "IntBetween". As the
+// closures returned by the validators may be inlined into their callers,
+// e.g. "example.Resource.IntBetween.func1", only the names of the closures
+// are matched.
+func validatorName(fn any) string {
+	n := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	if m := reClosure.FindStringSubmatch(n); m != nil {
+		return m[1]
+	}
+	if name := strings.TrimPrefix(n, pkgValidation); name != n && !strings.Contains(name, ".") {
+		return name
+	}
+	return ""
+}
+
+// inferValidatorConstraints sets the validation markers equivalent to the
+// given validator of a field of the given type. As the arguments of the
+// validators are not accessible, they're extracted from the errors returned
+// for the values violating them. The validators whose arguments cannot be
+// extracted are ignored.
+func inferValidatorConstraints(t schema.ValueType, fn schema.SchemaValidateFunc, o *markers.KubebuilderOptions) {
+	switch t { //nolint:exhaustive
+	case schema.TypeString:
+		msg := violation(fn, "", strings.Repeat("a", math.MaxUint16), "\x00")
+		if m := reLength.FindStringSubmatch(msg); m != nil {
+			o.MinLength, o.MaxLength = atoi(m[1]), atoi(m[2])
+		} else if reNotEmpty.MatchString(msg) {
+			o.MinLength = intPtr(1)
+		} else if m := reMatch.FindStringSubmatch(msg); m != nil {
+			if p, err := strconv.Unquote(m[1]); err == nil && !rePatternCRD.MatchString(p) {
+				o.Pattern = p
+			}
+		} else if m := reOneOf.FindStringSubmatch(msg); m != nil {
+			o.Enum = enum(fn, m[1], func(v string) (any, bool) { return v, true }, strconv.Quote)
+		}
+	case schema.TypeInt:
+		msg := violation(fn, math.MinInt, math.MaxInt)
+		if m := reRange.FindStringSubmatch(msg); m != nil {
+			o.Minimum, o.Maximum = atoi(m[1]), atoi(m[2])
+		} else if m := reAtLeast.FindStringSubmatch(msg); m != nil {
+			o.Minimum = atoi(m[1])
+		} else if m := reAtMost.FindStringSubmatch(msg); m != nil {
+			o.Maximum = atoi(m[1])
+		} else if m := reOneOf.FindStringSubmatch(msg); m != nil {
+			o.Enum = enum(fn, m[1], func(v string) (any, bool) {
+				i, err := strconv.Atoi(v)
+				return i, err == nil
+			}, func(v string) string { return v })
+		}
+	}
+}
+
+// inferDocumentedConstraints sets the validation markers equivalent to the
+// constraints documented in the given description of a field of the given
+// type.
+func inferDocumentedConstraints(t schema.ValueType, description string, o *markers.KubebuilderOptions) {
+	switch t { //nolint:exhaustive
+	case schema.TypeString:
+		if m := reDocLength.FindStringSubmatch(description); m != nil {
+			o.MinLength, o.MaxLength = atoi(m[1]), atoi(m[2])
+		} else if m := reDocMaxLength.FindStringSubmatch(description); m != nil {
+			o.MaxLength = atoi(m[1])
+		}
+	case schema.TypeInt:
+		if m := reDocRange.FindStringSubmatch(description); m != nil {
+			o.Minimum, o.Maximum = atoi(m[1]), atoi(m[2])
+		}
+	}
+}
+
+// violation returns the message of the first error returned by the given
+// validator for the given values. The validators panicking for a value are
+// considered to accept all the values.
+func violation(fn schema.SchemaValidateFunc, values ...any) (msg string) {
+	defer func() {
+		if recover() != nil {
+			msg = ""
+		}
+	}()
+	for _, v := range values {
+		if _, errs := fn(v, ""); len(errs) > 0 {
+			return errs[0].Error()
+		}
+	}
+	return ""
+}
+
+// enum returns the literals of the given values allowed by the given
+// validator of the "one of" kind, which are extracted from its error. As the
+// values are printed separated by spaces, nil is returned if any of the
+// extracted values, which are parsed with the given function, is not
+// accepted by the validator, e.g. a value containing a space, or if the
+// validator ignores the case of the values.
+func enum(fn schema.SchemaValidateFunc, values string, parse func(string) (any, bool), literal func(string) string) []string {
+	if values == "" {
+		return nil
+	}
+	l := strings.Split(values, " ")
+	result := make([]string, 0, len(l))
+	for _, v := range l {
+		pv, ok := parse(v)
+		if !ok || violation(fn, pv) != "" {
+			return nil
+		}
+		if u := strings.ToUpper(v); u != v && violation(fn, u) == "" {
+			return nil
+		}
+		result = append(result, literal(v))
+	}
+	return result
+}
+
+func atoi(s string) *int {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+	return &i
+}
+
+func intPtr(i int) *int {
+	return &i
+}
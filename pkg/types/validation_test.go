@@ -0,0 +1,131 @@
+/*
+Copyright 2023 Upbound Inc.
+*/
+
+package types
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/upbound/upjet/pkg/types/markers"
+)
+
+func TestInferValidation(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		sch         *schema.Schema
+		description string
+		want        markers.KubebuilderOptions
+	}{
+		"ItemLimits": {
+			reason: "The item limits of a list should be propagated.",
+			sch:    &schema.Schema{Type: schema.TypeList, MinItems: 1, MaxItems: 5, Elem: &schema.Schema{Type: schema.TypeString}},
+			want:   markers.KubebuilderOptions{MinItems: intPtr(1), MaxItems: intPtr(5)},
+		},
+		"ToDiagFunc": {
+			reason: "The constraints of a validator wrapped with validation.ToDiagFunc should be inferred.",
+			sch:    &schema.Schema{Type: schema.TypeInt, ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(1, 10))},
+			want:   markers.KubebuilderOptions{Minimum: intPtr(1), Maximum: intPtr(10)},
+		},
+		"DocumentedLength": {
+			reason:      "The length limits documented in the description should be inferred.",
+			sch:         &schema.Schema{Type: schema.TypeString},
+			description: "Name of the bucket. Must be between 3 and 63 characters.",
+			want:        markers.KubebuilderOptions{MinLength: intPtr(3), MaxLength: intPtr(63)},
+		},
+		"DocumentedMaxLength": {
+			reason:      "The maximum length documented in the description should be inferred.",
+			sch:         &schema.Schema{Type: schema.TypeString},
+			description: "Description of the rule. Up to 256 characters.",
+			want:        markers.KubebuilderOptions{MaxLength: intPtr(256)},
+		},
+		"DocumentedRange": {
+			reason:      "The range documented in the description should be inferred.",
+			sch:         &schema.Schema{Type: schema.TypeInt},
+			description: "Number of days. Valid values are between 1 and 365.",
+			want:        markers.KubebuilderOptions{Minimum: intPtr(1), Maximum: intPtr(365)},
+		},
+		"ValidatorOverDocumentation": {
+			reason:      "The documented constraints should be ignored if a validator is recognized.",
+			sch:         &schema.Schema{Type: schema.TypeInt, ValidateFunc: validation.IntAtLeast(0)},
+			description: "Valid values are between 1 and 365.",
+			want:        markers.KubebuilderOptions{Minimum: intPtr(0)},
+		},
+		"StringIsNotEmpty": {
+			reason: "A minimum length of 1 should be inferred from validation.StringIsNotEmpty.",
+			sch:    &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringIsNotEmpty},
+			want:   markers.KubebuilderOptions{MinLength: intPtr(1)},
+		},
+		"StringLenBetween": {
+			reason: "The length limits should be inferred from validation.StringLenBetween.",
+			sch:    &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringLenBetween(0, 63)},
+			want:   markers.KubebuilderOptions{MinLength: intPtr(0), MaxLength: intPtr(63)},
+		},
+		"StringMatch": {
+			reason: "The pattern should be inferred from validation.StringMatch.",
+			sch:    &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[a-z]+$`), "")},
+			want:   markers.KubebuilderOptions{Pattern: `^[a-z]+$`},
+		},
+		"StringMatchWithMessage": {
+			reason: "No pattern should be inferred if validation.StringMatch hides the regular expression with a message.",
+			sch:    &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[a-z]+$`), "lowercase letters only")},
+		},
+		"StringInSlice": {
+			reason: "An enum should be inferred from validation.StringInSlice.",
+			sch:    &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice([]string{"private", "public-read"}, false)},
+			want:   markers.KubebuilderOptions{Enum: []string{`"private"`, `"public-read"`}},
+		},
+		"StringInSliceIgnoreCase": {
+			reason: "No enum should be inferred if validation.StringInSlice ignores the case.",
+			sch:    &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice([]string{"private", "public-read"}, true)},
+		},
+		"StringInSliceWithSpace": {
+			reason: "No enum should be inferred if a value of validation.StringInSlice contains a space.",
+			sch:    &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice([]string{"read only", "write"}, false)},
+		},
+		"IntBetween": {
+			reason: "The range should be inferred from validation.IntBetween.",
+			sch:    &schema.Schema{Type: schema.TypeInt, ValidateFunc: validation.IntBetween(-1, 65535)},
+			want:   markers.KubebuilderOptions{Minimum: intPtr(-1), Maximum: intPtr(65535)},
+		},
+		"IntAtLeast": {
+			reason: "The minimum should be inferred from validation.IntAtLeast.",
+			sch:    &schema.Schema{Type: schema.TypeInt, ValidateFunc: validation.IntAtLeast(1)},
+			want:   markers.KubebuilderOptions{Minimum: intPtr(1)},
+		},
+		"IntAtMost": {
+			reason: "The maximum should be inferred from validation.IntAtMost.",
+			sch:    &schema.Schema{Type: schema.TypeInt, ValidateFunc: validation.IntAtMost(10)},
+			want:   markers.KubebuilderOptions{Maximum: intPtr(10)},
+		},
+		"IntInSlice": {
+			reason: "An enum should be inferred from validation.IntInSlice.",
+			sch:    &schema.Schema{Type: schema.TypeInt, ValidateFunc: validation.IntInSlice([]int{128, 256})},
+			want:   markers.KubebuilderOptions{Enum: []string{"128", "256"}},
+		},
+		"TypeMismatch": {
+			reason: "No validation should be inferred from a validator not applicable to the type of the field.",
+			sch:    &schema.Schema{Type: schema.TypeFloat, ValidateFunc: validation.IntAtLeast(1)},
+		},
+		"UnknownValidator": {
+			reason: "No validation should be inferred from a validator that is not well-known.",
+			sch: &schema.Schema{Type: schema.TypeString, ValidateFunc: func(any, string) ([]string, []error) {
+				return nil, nil
+			}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := markers.KubebuilderOptions{}
+			inferValidation(tc.sch, tc.description, &got)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ninferValidation(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}